@@ -116,19 +116,24 @@ func logAccounts(logger *slog.Logger, book *address.Book, _ *dev.Node) error {
 
 	for _, entry := range entries {
 		address := entry.Address.String()
-		qres, err := client.NewLocal().ABCIQuery("auth/accounts/"+address, []byte{})
+		qres, err := client.NewLocal().ABCIQuery("auth/typedaccounts/"+address, []byte{})
 		if err != nil {
 			return fmt.Errorf("unable to query account %q: %w", address, err)
 		}
 
-		var qret struct{ BaseAccount std.BaseAccount }
-		if err = amino.UnmarshalJSON(qres.Response.Data, &qret); err != nil {
+		var acc std.Account
+		if err = amino.UnmarshalJSON(qres.Response.Data, &acc); err != nil {
 			return fmt.Errorf("unable to unmarshal query response: %w", err)
 		}
 
+		var coins std.Coins
+		if acc != nil {
+			coins = acc.GetCoins()
+		}
+
 		if len(entry.Names) == 0 {
 			// Insert row with name, address, and balance amount.
-			fmt.Fprintf(tabw, "%s\t%s\t%s\n", "_", address, qret.BaseAccount.GetCoins().String())
+			fmt.Fprintf(tabw, "%s\t%s\t%s\n", "_", address, coins.String())
 			continue
 		}
 
@@ -136,7 +141,7 @@ func logAccounts(logger *slog.Logger, book *address.Book, _ *dev.Node) error {
 			// Insert row with name, address, and balance amount.
 			fmt.Fprintf(tabw, "%s\t%s\t%s\n", name,
 				address,
-				qret.BaseAccount.GetCoins().String())
+				coins.String())
 		}
 	}
 
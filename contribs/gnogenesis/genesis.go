@@ -6,6 +6,7 @@ import (
 	"github.com/gnolang/contribs/gnogenesis/internal/txs"
 	"github.com/gnolang/contribs/gnogenesis/internal/validator"
 	"github.com/gnolang/contribs/gnogenesis/internal/verify"
+	"github.com/gnolang/contribs/gnogenesis/internal/vesting"
 	"github.com/gnolang/gno/tm2/pkg/commands"
 )
 
@@ -26,6 +27,7 @@ func newGenesisCmd(io commands.IO) *commands.Command {
 		verify.NewVerifyCmd(io),
 		balances.NewBalancesCmd(io),
 		txs.NewTxsCmd(io),
+		vesting.NewVestingCmd(io),
 	)
 
 	return cmd
@@ -137,7 +137,7 @@ func execBalancesAdd(ctx context.Context, cfg *balancesAddCfg, io commands.IO) e
 
 	// Initialize genesis app state if it is not initialized already
 	if genesis.AppState == nil {
-		genesis.AppState = gnoland.GnoGenesisState{}
+		genesis.AppState = gnoland.GnoGenesisState{MerkleizedVMStore: true}
 	}
 
 	// Construct the initial genesis balance sheet
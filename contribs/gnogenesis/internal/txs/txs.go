@@ -49,7 +49,7 @@ func (c *txsCfg) RegisterFlags(fs *flag.FlagSet) {
 func appendGenesisTxs(genesis *types.GenesisDoc, txs []gnoland.TxWithMetadata) error {
 	// Initialize the app state if it's not present
 	if genesis.AppState == nil {
-		genesis.AppState = gnoland.GnoGenesisState{}
+		genesis.AppState = gnoland.GnoGenesisState{MerkleizedVMStore: true}
 	}
 
 	// Make sure the app state is the Gno genesis state
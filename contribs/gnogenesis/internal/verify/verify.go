@@ -10,6 +10,8 @@ import (
 	"github.com/gnolang/gno/gno.land/pkg/gnoland"
 	"github.com/gnolang/gno/tm2/pkg/bft/types"
 	"github.com/gnolang/gno/tm2/pkg/commands"
+	"github.com/gnolang/gno/tm2/pkg/sdk/bank"
+	"github.com/gnolang/gno/tm2/pkg/std"
 )
 
 var errInvalidGenesisState = errors.New("invalid genesis state type")
@@ -67,10 +69,31 @@ func execVerify(cfg *verifyCfg, io commands.IO) error {
 		}
 
 		// Validate the initial balances
+		balances := make(map[types.Address]std.Coins, len(state.Balances))
 		for _, balance := range state.Balances {
 			if err := balance.Verify(); err != nil {
 				return fmt.Errorf("invalid balance: %w", err)
 			}
+
+			balances[balance.Address] = balance.Amount
+		}
+
+		// Validate the vesting schedules, and that
+		// the balances cover the vesting amounts
+		for _, vesting := range state.Vesting {
+			if err := vesting.Verify(); err != nil {
+				return fmt.Errorf("invalid vesting: %w", err)
+			}
+
+			acc := std.NewBaseAccount(vesting.Address, balances[vesting.Address], nil, 0, 0)
+			if err := vesting.Account(acc).Validate(); err != nil {
+				return fmt.Errorf("invalid vesting: %w", err)
+			}
+		}
+
+		// Validate the denom metadata
+		if err := bank.ValidateGenesis(state.Bank); err != nil {
+			return fmt.Errorf("invalid bank state: %w", err)
 		}
 	}
 
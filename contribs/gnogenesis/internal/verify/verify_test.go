@@ -8,7 +8,9 @@ import (
 	"github.com/gnolang/gno/gno.land/pkg/gnoland"
 	"github.com/gnolang/gno/tm2/pkg/bft/types"
 	"github.com/gnolang/gno/tm2/pkg/commands"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
 	"github.com/gnolang/gno/tm2/pkg/crypto/mock"
+	"github.com/gnolang/gno/tm2/pkg/std"
 	"github.com/gnolang/gno/tm2/pkg/testutils"
 	"github.com/stretchr/testify/require"
 )
@@ -92,6 +94,48 @@ func TestGenesis_Verify(t *testing.T) {
 		require.Error(t, cmdErr)
 	})
 
+	t.Run("vesting not covered by balance", func(t *testing.T) {
+		t.Parallel()
+
+		tempFile, cleanup := testutils.NewTestFile(t)
+		t.Cleanup(cleanup)
+
+		var (
+			g       = getValidTestGenesis()
+			address = crypto.AddressFromPreimage([]byte("vesting"))
+		)
+
+		g.AppState = gnoland.GnoGenesisState{
+			Balances: []gnoland.Balance{
+				{
+					Address: address,
+					Amount:  std.NewCoins(std.NewCoin("ugnot", 100)),
+				},
+			},
+			Vesting: []gnoland.Vesting{
+				{
+					Address: address,
+					Amount:  std.NewCoins(std.NewCoin("ugnot", 200)),
+					EndTime: 100,
+				},
+			},
+			Txs: []gnoland.TxWithMetadata{},
+		}
+
+		require.NoError(t, g.SaveAs(tempFile.Name()))
+
+		// Create the command
+		cmd := NewVerifyCmd(commands.NewTestIO())
+		args := []string{
+			"--genesis-path",
+			tempFile.Name(),
+		}
+
+		// Run the command
+		cmdErr := cmd.ParseAndRun(context.Background(), args)
+		require.ErrorContains(t, cmdErr, "invalid vesting")
+	})
+
 	t.Run("valid genesis", func(t *testing.T) {
 		t.Parallel()
 
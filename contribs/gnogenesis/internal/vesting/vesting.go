@@ -0,0 +1,50 @@
+package vesting
+
+import (
+	"flag"
+
+	"github.com/gnolang/contribs/gnogenesis/internal/common"
+	"github.com/gnolang/gno/tm2/pkg/commands"
+)
+
+type vestingCfg struct {
+	common.Cfg
+
+	address string
+}
+
+// NewVestingCmd creates the genesis vesting subcommand
+func NewVestingCmd(io commands.IO) *commands.Command {
+	cfg := &vestingCfg{
+		Cfg: common.Cfg{},
+	}
+
+	cmd := commands.NewCommand(
+		commands.Metadata{
+			Name:       "vesting",
+			ShortUsage: "<subcommand> [flags]",
+			ShortHelp:  "manages genesis.json vesting accounts",
+			LongHelp:   "Manipulates the genesis.json vesting schedules, which lock part of the initial account balances until they vest",
+		},
+		cfg,
+		commands.HelpExec,
+	)
+
+	cmd.AddSubCommands(
+		newVestingAddCmd(cfg, io),
+		newVestingRemoveCmd(cfg, io),
+	)
+
+	return cmd
+}
+
+func (c *vestingCfg) RegisterFlags(fs *flag.FlagSet) {
+	c.Cfg.RegisterFlags(fs)
+
+	fs.StringVar(
+		&c.address,
+		"address",
+		"",
+		"the gno bech32 address of the vesting account",
+	)
+}
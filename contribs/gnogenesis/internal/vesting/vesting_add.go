@@ -0,0 +1,213 @@
+package vesting
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gnolang/contribs/gnogenesis/internal/common"
+	"github.com/gnolang/gno/gno.land/pkg/gnoland"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/commands"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/gnolang/gno/tm2/pkg/sdk/auth"
+	"github.com/gnolang/gno/tm2/pkg/std"
+
+	_ "github.com/gnolang/gno/gno.land/pkg/sdk/vm"
+)
+
+var (
+	errNoSchedule          = errors.New("either an amount and an end time, or periods must be set")
+	errBalanceNotPresent   = errors.New("vesting account has no balance in genesis.json")
+	errInsufficientBalance = errors.New("vesting account balance does not cover the vesting amount")
+	errVestingPresent      = errors.New("vesting schedule with same address already present in genesis.json")
+	errInvalidPeriodFormat = errors.New("invalid period format, expected <length>=<amount>")
+)
+
+type vestingAddCfg struct {
+	rootCfg *vestingCfg
+
+	amount  string
+	start   int64
+	end     int64
+	periods commands.StringArr
+}
+
+// newVestingAddCmd creates the genesis vesting add subcommand
+func newVestingAddCmd(rootCfg *vestingCfg, io commands.IO) *commands.Command {
+	cfg := &vestingAddCfg{
+		rootCfg: rootCfg,
+	}
+
+	return commands.NewCommand(
+		commands.Metadata{
+			Name:       "add",
+			ShortUsage: "vesting add [flags]",
+			ShortHelp:  "adds a vesting schedule to the genesis.json",
+			LongHelp: "Adds a vesting schedule to an account of the genesis.json. " +
+				"The coins vest linearly from -start to -end, or at the end of each -period. " +
+				"The account must have a genesis balance covering the vested coins, " +
+				"which are locked until they vest.",
+		},
+		cfg,
+		func(_ context.Context, _ []string) error {
+			return execVestingAdd(cfg, io)
+		},
+	)
+}
+
+func (c *vestingAddCfg) RegisterFlags(fs *flag.FlagSet) {
+	fs.StringVar(
+		&c.amount,
+		"amount",
+		"",
+		"the coins vesting linearly from the start time to the end time",
+	)
+
+	fs.Int64Var(
+		&c.start,
+		"start",
+		0,
+		"the unix time at which vesting starts",
+	)
+
+	fs.Int64Var(
+		&c.end,
+		"end",
+		0,
+		"the unix time at which all the coins are vested",
+	)
+
+	fs.Var(
+		&c.periods,
+		"period",
+		"a vesting period in the format <length>=<amount>, where <length> is in seconds; "+
+			"the periods vest one after the other (can be repeated)",
+	)
+}
+
+func execVestingAdd(cfg *vestingAddCfg, io commands.IO) error {
+	// Load the genesis
+	genesis, loadErr := types.GenesisDocFromFile(cfg.rootCfg.GenesisPath)
+	if loadErr != nil {
+		return fmt.Errorf("unable to load genesis, %w", loadErr)
+	}
+
+	if genesis.AppState == nil {
+		return common.ErrAppStateNotSet
+	}
+
+	// Check the vesting address
+	address, err := crypto.AddressFromString(cfg.rootCfg.address)
+	if err != nil {
+		return fmt.Errorf("invalid vesting address, %w", err)
+	}
+
+	vesting := gnoland.Vesting{
+		Address:   address,
+		StartTime: cfg.start,
+		EndTime:   cfg.end,
+	}
+
+	if cfg.amount != "" {
+		vesting.Amount, err = std.ParseCoins(cfg.amount)
+		if err != nil {
+			return fmt.Errorf("invalid vesting amount, %w", err)
+		}
+	}
+
+	for _, entry := range cfg.periods {
+		period, err := parsePeriod(entry)
+		if err != nil {
+			return err
+		}
+
+		vesting.Periods = append(vesting.Periods, period)
+	}
+
+	if len(vesting.Amount) == 0 && len(vesting.Periods) == 0 {
+		return errNoSchedule
+	}
+
+	// Check the vesting schedule
+	if err := vesting.Verify(); err != nil {
+		return fmt.Errorf("invalid vesting schedule, %w", err)
+	}
+
+	state := genesis.AppState.(gnoland.GnoGenesisState)
+
+	// Check if the vesting schedule exists
+	for _, genesisVesting := range state.Vesting {
+		if genesisVesting.Address == address {
+			return errVestingPresent
+		}
+	}
+
+	// Check the genesis balance covers the vesting amount
+	if err := verifyBalance(state, vesting); err != nil {
+		return err
+	}
+
+	// Add the vesting schedule
+	state.Vesting = append(state.Vesting, vesting)
+	genesis.AppState = state
+
+	// Save the updated genesis
+	if err := genesis.SaveAs(cfg.rootCfg.GenesisPath); err != nil {
+		return fmt.Errorf("unable to save genesis.json, %w", err)
+	}
+
+	io.Printfln(
+		"Vesting schedule for address %s added to genesis file",
+		cfg.rootCfg.address,
+	)
+
+	return nil
+}
+
+// parsePeriod parses a vesting period in the format <length>=<amount>
+func parsePeriod(entry string) (auth.VestingPeriod, error) {
+	length, amount, found := strings.Cut(strings.TrimSpace(entry), "=")
+	if !found {
+		return auth.VestingPeriod{}, fmt.Errorf("%w: %q", errInvalidPeriodFormat, entry)
+	}
+
+	var (
+		period auth.VestingPeriod
+		err    error
+	)
+
+	period.Length, err = strconv.ParseInt(length, 10, 64)
+	if err != nil {
+		return auth.VestingPeriod{}, fmt.Errorf("invalid period length %q, %w", length, err)
+	}
+
+	period.Amount, err = std.ParseCoins(amount)
+	if err != nil {
+		return auth.VestingPeriod{}, fmt.Errorf("invalid period amount %q, %w", amount, err)
+	}
+
+	return period, nil
+}
+
+// verifyBalance checks the genesis balance of the vesting
+// account covers the vesting amount
+func verifyBalance(state gnoland.GnoGenesisState, vesting gnoland.Vesting) error {
+	for _, balance := range state.Balances {
+		if balance.Address != vesting.Address {
+			continue
+		}
+
+		original := vesting.Account(&std.BaseAccount{}).GetOriginalVesting()
+		if !balance.Amount.IsAllGTE(original) {
+			return fmt.Errorf("%w, %s < %s", errInsufficientBalance, balance.Amount, original)
+		}
+
+		return nil
+	}
+
+	return errBalanceNotPresent
+}
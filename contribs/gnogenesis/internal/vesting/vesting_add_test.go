@@ -0,0 +1,332 @@
+package vesting
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gnolang/contribs/gnogenesis/internal/common"
+	"github.com/gnolang/gno/gno.land/pkg/gnoland"
+	"github.com/gnolang/gno/gno.land/pkg/gnoland/ugnot"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/commands"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/gnolang/gno/tm2/pkg/sdk/auth"
+	"github.com/gnolang/gno/tm2/pkg/std"
+	"github.com/gnolang/gno/tm2/pkg/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// saveGenesisWithBalance saves a genesis.json with a balance for address
+func saveGenesisWithBalance(t *testing.T, address crypto.Address, amount int64) string {
+	t.Helper()
+
+	tempGenesis, cleanup := testutils.NewTestFile(t)
+	t.Cleanup(cleanup)
+
+	genesis := common.GetDefaultGenesis()
+	genesis.AppState = gnoland.GnoGenesisState{
+		Balances: []gnoland.Balance{
+			{
+				Address: address,
+				Amount:  std.NewCoins(std.NewCoin(ugnot.Denom, amount)),
+			},
+		},
+	}
+	require.NoError(t, genesis.SaveAs(tempGenesis.Name()))
+
+	return tempGenesis.Name()
+}
+
+func TestGenesis_Vesting_Add(t *testing.T) {
+	t.Parallel()
+
+	t.Run("invalid genesis file", func(t *testing.T) {
+		t.Parallel()
+
+		// Create the command
+		cmd := NewVestingCmd(commands.NewTestIO())
+		args := []string{
+			"add",
+			"--genesis-path",
+			"dummy-path",
+		}
+
+		// Run the command
+		cmdErr := cmd.ParseAndRun(context.Background(), args)
+		assert.ErrorContains(t, cmdErr, common.ErrUnableToLoadGenesis.Error())
+	})
+
+	t.Run("genesis app state not set", func(t *testing.T) {
+		t.Parallel()
+
+		tempGenesis, cleanup := testutils.NewTestFile(t)
+		t.Cleanup(cleanup)
+
+		genesis := common.GetDefaultGenesis()
+		require.NoError(t, genesis.SaveAs(tempGenesis.Name()))
+
+		// Create the command
+		cmd := NewVestingCmd(commands.NewTestIO())
+		args := []string{
+			"add",
+			"--genesis-path",
+			tempGenesis.Name(),
+		}
+
+		// Run the command
+		cmdErr := cmd.ParseAndRun(context.Background(), args)
+		assert.ErrorContains(t, cmdErr, common.ErrAppStateNotSet.Error())
+	})
+
+	t.Run("invalid vesting address", func(t *testing.T) {
+		t.Parallel()
+
+		dummyKey := common.GetDummyKey(t)
+		genesisPath := saveGenesisWithBalance(t, dummyKey.Address(), 100)
+
+		// Create the command
+		cmd := NewVestingCmd(commands.NewTestIO())
+		args := []string{
+			"add",
+			"--genesis-path",
+			genesisPath,
+			"--address",
+			"dummyaddress",
+		}
+
+		// Run the command
+		cmdErr := cmd.ParseAndRun(context.Background(), args)
+		assert.ErrorContains(t, cmdErr, "invalid vesting address")
+	})
+
+	t.Run("no vesting schedule", func(t *testing.T) {
+		t.Parallel()
+
+		dummyKey := common.GetDummyKey(t)
+		genesisPath := saveGenesisWithBalance(t, dummyKey.Address(), 100)
+
+		// Create the command
+		cmd := NewVestingCmd(commands.NewTestIO())
+		args := []string{
+			"add",
+			"--genesis-path",
+			genesisPath,
+			"--address",
+			dummyKey.Address().String(),
+		}
+
+		// Run the command
+		cmdErr := cmd.ParseAndRun(context.Background(), args)
+		assert.ErrorIs(t, cmdErr, errNoSchedule)
+	})
+
+	t.Run("invalid period", func(t *testing.T) {
+		t.Parallel()
+
+		dummyKey := common.GetDummyKey(t)
+		genesisPath := saveGenesisWithBalance(t, dummyKey.Address(), 100)
+
+		// Create the command
+		cmd := NewVestingCmd(commands.NewTestIO())
+		args := []string{
+			"add",
+			"--genesis-path",
+			genesisPath,
+			"--address",
+			dummyKey.Address().String(),
+			"--period",
+			"100ugnot",
+		}
+
+		// Run the command
+		cmdErr := cmd.ParseAndRun(context.Background(), args)
+		assert.ErrorIs(t, cmdErr, errInvalidPeriodFormat)
+	})
+
+	t.Run("invalid vesting schedule", func(t *testing.T) {
+		t.Parallel()
+
+		dummyKey := common.GetDummyKey(t)
+		genesisPath := saveGenesisWithBalance(t, dummyKey.Address(), 100)
+
+		// Create the command
+		cmd := NewVestingCmd(commands.NewTestIO())
+		args := []string{
+			"add",
+			"--genesis-path",
+			genesisPath,
+			"--address",
+			dummyKey.Address().String(),
+			"--amount",
+			"100ugnot",
+			"--start",
+			"200",
+			"--end",
+			"100",
+		}
+
+		// Run the command
+		cmdErr := cmd.ParseAndRun(context.Background(), args)
+		assert.ErrorContains(t, cmdErr, "invalid vesting schedule")
+	})
+
+	t.Run("balance not present", func(t *testing.T) {
+		t.Parallel()
+
+		dummyKeys := common.GetDummyKeys(t, 2)
+		genesisPath := saveGenesisWithBalance(t, dummyKeys[0].Address(), 100)
+
+		// Create the command
+		cmd := NewVestingCmd(commands.NewTestIO())
+		args := []string{
+			"add",
+			"--genesis-path",
+			genesisPath,
+			"--address",
+			dummyKeys[1].Address().String(),
+			"--amount",
+			"100ugnot",
+			"--end",
+			"100",
+		}
+
+		// Run the command
+		cmdErr := cmd.ParseAndRun(context.Background(), args)
+		assert.ErrorIs(t, cmdErr, errBalanceNotPresent)
+	})
+
+	t.Run("insufficient balance", func(t *testing.T) {
+		t.Parallel()
+
+		dummyKey := common.GetDummyKey(t)
+		genesisPath := saveGenesisWithBalance(t, dummyKey.Address(), 100)
+
+		// Create the command
+		cmd := NewVestingCmd(commands.NewTestIO())
+		args := []string{
+			"add",
+			"--genesis-path",
+			genesisPath,
+			"--address",
+			dummyKey.Address().String(),
+			"--period",
+			"100=60ugnot",
+			"--period",
+			"100=60ugnot",
+		}
+
+		// Run the command
+		cmdErr := cmd.ParseAndRun(context.Background(), args)
+		assert.ErrorIs(t, cmdErr, errInsufficientBalance)
+	})
+
+	t.Run("vesting schedule already present", func(t *testing.T) {
+		t.Parallel()
+
+		dummyKey := common.GetDummyKey(t)
+		genesisPath := saveGenesisWithBalance(t, dummyKey.Address(), 100)
+
+		args := []string{
+			"add",
+			"--genesis-path",
+			genesisPath,
+			"--address",
+			dummyKey.Address().String(),
+			"--amount",
+			"100ugnot",
+			"--end",
+			"100",
+		}
+
+		// Run the command twice
+		cmd := NewVestingCmd(commands.NewTestIO())
+		require.NoError(t, cmd.ParseAndRun(context.Background(), args))
+
+		cmd = NewVestingCmd(commands.NewTestIO())
+		cmdErr := cmd.ParseAndRun(context.Background(), args)
+		assert.ErrorIs(t, cmdErr, errVestingPresent)
+	})
+
+	t.Run("continuous vesting added", func(t *testing.T) {
+		t.Parallel()
+
+		dummyKey := common.GetDummyKey(t)
+		genesisPath := saveGenesisWithBalance(t, dummyKey.Address(), 100)
+
+		// Create the command
+		cmd := NewVestingCmd(commands.NewTestIO())
+		args := []string{
+			"add",
+			"--genesis-path",
+			genesisPath,
+			"--address",
+			dummyKey.Address().String(),
+			"--amount",
+			"80ugnot",
+			"--start",
+			"1000",
+			"--end",
+			"2000",
+		}
+
+		// Run the command
+		require.NoError(t, cmd.ParseAndRun(context.Background(), args))
+
+		// Make sure the vesting schedule was added
+		genesis, err := types.GenesisDocFromFile(genesisPath)
+		require.NoError(t, err)
+
+		state := genesis.AppState.(gnoland.GnoGenesisState)
+		require.Len(t, state.Vesting, 1)
+
+		assert.Equal(t, gnoland.Vesting{
+			Address:   dummyKey.Address(),
+			Amount:    std.NewCoins(std.NewCoin(ugnot.Denom, 80)),
+			StartTime: 1000,
+			EndTime:   2000,
+		}, state.Vesting[0])
+	})
+
+	t.Run("periodic vesting added", func(t *testing.T) {
+		t.Parallel()
+
+		dummyKey := common.GetDummyKey(t)
+		genesisPath := saveGenesisWithBalance(t, dummyKey.Address(), 100)
+
+		// Create the command
+		cmd := NewVestingCmd(commands.NewTestIO())
+		args := []string{
+			"add",
+			"--genesis-path",
+			genesisPath,
+			"--address",
+			dummyKey.Address().String(),
+			"--start",
+			"1000",
+			"--period",
+			"3600=40ugnot",
+			"--period",
+			"7200=60ugnot",
+		}
+
+		// Run the command
+		require.NoError(t, cmd.ParseAndRun(context.Background(), args))
+
+		// Make sure the vesting schedule was added
+		genesis, err := types.GenesisDocFromFile(genesisPath)
+		require.NoError(t, err)
+
+		state := genesis.AppState.(gnoland.GnoGenesisState)
+		require.Len(t, state.Vesting, 1)
+
+		assert.Equal(t, gnoland.Vesting{
+			Address:   dummyKey.Address(),
+			StartTime: 1000,
+			Periods: []auth.VestingPeriod{
+				{Length: 3600, Amount: std.NewCoins(std.NewCoin(ugnot.Denom, 40))},
+				{Length: 7200, Amount: std.NewCoins(std.NewCoin(ugnot.Denom, 60))},
+			},
+		}, state.Vesting[0])
+	})
+}
@@ -0,0 +1,80 @@
+package vesting
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/gnolang/contribs/gnogenesis/internal/common"
+	"github.com/gnolang/gno/gno.land/pkg/gnoland"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/commands"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+)
+
+var errVestingNotPresent = errors.New("vesting schedule not present in genesis.json")
+
+// newVestingRemoveCmd creates the genesis vesting remove subcommand
+func newVestingRemoveCmd(rootCfg *vestingCfg, io commands.IO) *commands.Command {
+	return commands.NewCommand(
+		commands.Metadata{
+			Name:       "remove",
+			ShortUsage: "vesting remove [flags]",
+			ShortHelp:  "removes a vesting schedule from the genesis.json",
+		},
+		commands.NewEmptyConfig(),
+		func(_ context.Context, _ []string) error {
+			return execVestingRemove(rootCfg, io)
+		},
+	)
+}
+
+func execVestingRemove(cfg *vestingCfg, io commands.IO) error {
+	// Load the genesis
+	genesis, loadErr := types.GenesisDocFromFile(cfg.GenesisPath)
+	if loadErr != nil {
+		return fmt.Errorf("unable to load genesis, %w", loadErr)
+	}
+
+	if genesis.AppState == nil {
+		return common.ErrAppStateNotSet
+	}
+
+	// Check the vesting address
+	address, err := crypto.AddressFromString(cfg.address)
+	if err != nil {
+		return fmt.Errorf("invalid vesting address, %w", err)
+	}
+
+	state := genesis.AppState.(gnoland.GnoGenesisState)
+
+	index := -1
+
+	for indx, vesting := range state.Vesting {
+		if vesting.Address == address {
+			index = indx
+
+			break
+		}
+	}
+
+	if index < 0 {
+		return errVestingNotPresent
+	}
+
+	// Drop the vesting schedule; the balance of the account is kept
+	state.Vesting = append(state.Vesting[:index], state.Vesting[index+1:]...)
+	genesis.AppState = state
+
+	// Save the updated genesis
+	if err := genesis.SaveAs(cfg.GenesisPath); err != nil {
+		return fmt.Errorf("unable to save genesis.json, %w", err)
+	}
+
+	io.Printfln(
+		"Vesting schedule for address %s removed from genesis file",
+		cfg.address,
+	)
+
+	return nil
+}
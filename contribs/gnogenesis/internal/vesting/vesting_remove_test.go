@@ -0,0 +1,108 @@
+package vesting
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gnolang/contribs/gnogenesis/internal/common"
+	"github.com/gnolang/gno/gno.land/pkg/gnoland"
+	"github.com/gnolang/gno/gno.land/pkg/gnoland/ugnot"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/commands"
+	"github.com/gnolang/gno/tm2/pkg/std"
+	"github.com/gnolang/gno/tm2/pkg/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenesis_Vesting_Remove(t *testing.T) {
+	t.Parallel()
+
+	t.Run("invalid genesis file", func(t *testing.T) {
+		t.Parallel()
+
+		// Create the command
+		cmd := NewVestingCmd(commands.NewTestIO())
+		args := []string{
+			"remove",
+			"--genesis-path",
+			"dummy-path",
+		}
+
+		// Run the command
+		cmdErr := cmd.ParseAndRun(context.Background(), args)
+		assert.ErrorContains(t, cmdErr, common.ErrUnableToLoadGenesis.Error())
+	})
+
+	t.Run("vesting schedule not found", func(t *testing.T) {
+		t.Parallel()
+
+		dummyKey := common.GetDummyKey(t)
+		genesisPath := saveGenesisWithBalance(t, dummyKey.Address(), 100)
+
+		// Create the command
+		cmd := NewVestingCmd(commands.NewTestIO())
+		args := []string{
+			"remove",
+			"--genesis-path",
+			genesisPath,
+			"--address",
+			dummyKey.Address().String(),
+		}
+
+		// Run the command
+		cmdErr := cmd.ParseAndRun(context.Background(), args)
+		assert.ErrorIs(t, cmdErr, errVestingNotPresent)
+	})
+
+	t.Run("vesting schedule removed", func(t *testing.T) {
+		t.Parallel()
+
+		tempGenesis, cleanup := testutils.NewTestFile(t)
+		t.Cleanup(cleanup)
+
+		var (
+			dummyKey = common.GetDummyKey(t)
+			amount   = std.NewCoins(std.NewCoin(ugnot.Denom, 100))
+		)
+
+		genesis := common.GetDefaultGenesis()
+		genesis.AppState = gnoland.GnoGenesisState{
+			Balances: []gnoland.Balance{
+				{
+					Address: dummyKey.Address(),
+					Amount:  amount,
+				},
+			},
+			Vesting: []gnoland.Vesting{
+				{
+					Address: dummyKey.Address(),
+					Amount:  amount,
+					EndTime: 100,
+				},
+			},
+		}
+		require.NoError(t, genesis.SaveAs(tempGenesis.Name()))
+
+		// Create the command
+		cmd := NewVestingCmd(commands.NewTestIO())
+		args := []string{
+			"remove",
+			"--genesis-path",
+			tempGenesis.Name(),
+			"--address",
+			dummyKey.Address().String(),
+		}
+
+		// Run the command
+		require.NoError(t, cmd.ParseAndRun(context.Background(), args))
+
+		// Make sure the vesting schedule was removed, and the balance kept
+		genesis, err := types.GenesisDocFromFile(tempGenesis.Name())
+		require.NoError(t, err)
+
+		state := genesis.AppState.(gnoland.GnoGenesisState)
+		assert.Empty(t, state.Vesting)
+		assert.Len(t, state.Balances, 1)
+	})
+}
@@ -616,6 +616,22 @@ func TestConfig_Get_P2P(t *testing.T) {
 			},
 			true,
 		},
+		{
+			"addr book file",
+			"p2p.addr_book_file",
+			func(loadedCfg *config.Config, value []byte) {
+				assert.Equal(t, loadedCfg.P2P.AddrBook, unmarshalJSONCommon[string](t, value))
+			},
+			false,
+		},
+		{
+			"addr book strict toggle",
+			"p2p.addr_book_strict",
+			func(loadedCfg *config.Config, value []byte) {
+				assert.Equal(t, loadedCfg.P2P.AddrBookStrict, unmarshalJSONCommon[bool](t, value))
+			},
+			false,
+		},
 		{
 			"upnp toggle",
 			"p2p.upnp",
@@ -914,6 +930,14 @@ func TestConfig_Get_Mempool(t *testing.T) {
 			},
 			true,
 		},
+		{
+			"mempool type",
+			"mempool.type",
+			func(loadedCfg *config.Config, value []byte) {
+				assert.Equal(t, loadedCfg.Mempool.Type, unmarshalJSONCommon[string](t, value))
+			},
+			false,
+		},
 		{
 			"recheck flag",
 			"mempool.recheck",
@@ -505,6 +505,29 @@ func TestConfig_Set_P2P(t *testing.T) {
 				assert.Equal(t, value, loadedCfg.P2P.PersistentPeers)
 			},
 		},
+		{
+			"addr book file updated",
+			[]string{
+				"p2p.addr_book_file",
+				"example path",
+			},
+			func(loadedCfg *config.Config, value string) {
+				assert.Equal(t, value, loadedCfg.P2P.AddrBook)
+			},
+		},
+		{
+			"addr book strict toggle updated",
+			[]string{
+				"p2p.addr_book_strict",
+				"false",
+			},
+			func(loadedCfg *config.Config, value string) {
+				boolVal, err := strconv.ParseBool(value)
+				require.NoError(t, err)
+
+				assert.Equal(t, boolVal, loadedCfg.P2P.AddrBookStrict)
+			},
+		},
 		{
 			"upnp toggle updated",
 			[]string{
@@ -818,6 +841,16 @@ func TestConfig_Set_Mempool(t *testing.T) {
 				assert.Equal(t, value, loadedCfg.Mempool.RootDir)
 			},
 		},
+		{
+			"mempool type updated",
+			[]string{
+				"mempool.type",
+				"priority",
+			},
+			func(loadedCfg *config.Config, value string) {
+				assert.Equal(t, value, loadedCfg.Mempool.Type)
+			},
+		},
 		{
 			"recheck flag updated",
 			[]string{
@@ -19,6 +19,7 @@ import (
 	"github.com/gnolang/gno/gnovm/pkg/gnoenv"
 	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
 	"github.com/gnolang/gno/tm2/pkg/bft/config"
+	memcfg "github.com/gnolang/gno/tm2/pkg/bft/mempool/config"
 	"github.com/gnolang/gno/tm2/pkg/bft/node"
 	"github.com/gnolang/gno/tm2/pkg/bft/privval"
 	bft "github.com/gnolang/gno/tm2/pkg/bft/types"
@@ -235,8 +236,27 @@ func execStart(ctx context.Context, c *startCfg, io commands.IO) error {
 	// Create a top-level shared event switch
 	evsw := events.NewEventSwitch()
 
+	// The store keeping the VM state is given by the genesis
+	genesis, err := bft.GenesisDocFromFile(genesisPath)
+	if err != nil {
+		return fmt.Errorf("unable to load the genesis.json, %w", err)
+	}
+
+	legacyVMStore, err := gnoland.UsesLegacyVMStore(genesis)
+	if err != nil {
+		return fmt.Errorf("unable to load the genesis.json, %w", err)
+	}
+
 	// Create application and node
-	cfg.LocalApp, err = gnoland.NewApp(nodeDir, c.skipFailingGenesisTxs, evsw, logger)
+	cfg.LocalApp, err = gnoland.NewApp(
+		nodeDir,
+		c.skipFailingGenesisTxs,
+		cfg.Mempool.Type == memcfg.PriorityMempoolType, // replace-by-fee
+		cfg.StateSync,
+		legacyVMStore,
+		evsw,
+		logger,
+	)
 	if err != nil {
 		return fmt.Errorf("unable to create the Gnoland app, %w", err)
 	}
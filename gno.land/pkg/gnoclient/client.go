@@ -8,6 +8,7 @@ import (
 type Client struct {
 	Signer    Signer           // Signer for transaction authentication
 	RPCClient rpcclient.Client // RPC client for blockchain communication
+	Verifier  *Verifier        // Optional, verifies the query responses of an untrusted RPC client
 }
 
 // validateSigner checks that the signer is correctly configured.
@@ -1,13 +1,17 @@
 package gnoclient
 
 import (
+	"encoding/json"
 	"fmt"
 
+	_ "github.com/gnolang/gno/gno.land/pkg/gnoland" // registers the gno.land account type
+	gno "github.com/gnolang/gno/gnovm/pkg/gnolang"
 	"github.com/gnolang/gno/tm2/pkg/amino"
 	rpcclient "github.com/gnolang/gno/tm2/pkg/bft/rpc/client"
 	ctypes "github.com/gnolang/gno/tm2/pkg/bft/rpc/core/types"
 	"github.com/gnolang/gno/tm2/pkg/crypto"
 	"github.com/gnolang/gno/tm2/pkg/errors"
+	"github.com/gnolang/gno/tm2/pkg/sdk/params"
 	"github.com/gnolang/gno/tm2/pkg/std"
 )
 
@@ -38,12 +42,17 @@ func (c *Client) Query(cfg QueryCfg) (*ctypes.ResultABCIQuery, error) {
 }
 
 // QueryAccount retrieves account information for a given address.
+// If the client has a Verifier, the account is verified with a merkle proof.
 func (c *Client) QueryAccount(addr crypto.Address) (*std.BaseAccount, *ctypes.ResultABCIQuery, error) {
 	if err := c.validateRPCClient(); err != nil {
 		return nil, nil, err
 	}
 
-	path := fmt.Sprintf("auth/accounts/%s", crypto.AddressToBech32(addr))
+	if c.Verifier != nil {
+		return c.queryAccountVerified(addr)
+	}
+
+	path := fmt.Sprintf("auth/typedaccounts/%s", crypto.AddressToBech32(addr))
 	data := []byte{}
 
 	qres, err := c.RPCClient.ABCIQuery(path, data)
@@ -54,13 +63,25 @@ func (c *Client) QueryAccount(addr crypto.Address) (*std.BaseAccount, *ctypes.Re
 		return nil, nil, std.ErrUnknownAddress("unknown address: " + crypto.AddressToBech32(addr))
 	}
 
-	var qret struct{ BaseAccount std.BaseAccount }
-	err = amino.UnmarshalJSON(qres.Response.Data, &qret)
+	var acc std.Account
+	err = amino.UnmarshalJSON(qres.Response.Data, &acc)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	return &qret.BaseAccount, qres, nil
+	return baseAccount(acc), qres, nil
+}
+
+// baseAccount returns the base account fields of any account type,
+// like a gno.land account or a vesting account
+func baseAccount(acc std.Account) *std.BaseAccount {
+	return std.NewBaseAccount(
+		acc.GetAddress(),
+		acc.GetCoins(),
+		acc.GetPubKey(),
+		acc.GetAccountNumber(),
+		acc.GetSequence(),
+	)
 }
 
 // QueryAppVersion retrieves information about the app version
@@ -84,6 +105,9 @@ func (c *Client) QueryAppVersion() (string, *ctypes.ResultABCIQuery, error) {
 // Render calls the Render function for pkgPath with optional args. The pkgPath should
 // include the prefix like "gno.land/". This is similar to using a browser URL
 // <testnet>/<pkgPath>:<args> where <pkgPath> doesn't have the prefix like "gno.land/".
+// If the client has a Verifier, the query is rejected with ErrUnverifiableQuery, unless
+// the Verifier has CrossCheckComputed set: the result is then cross-checked with its
+// witnesses, but not verified.
 func (c *Client) Render(pkgPath string, args string) (string, *ctypes.ResultABCIQuery, error) {
 	if err := c.validateRPCClient(); err != nil {
 		return "", nil, err
@@ -92,7 +116,7 @@ func (c *Client) Render(pkgPath string, args string) (string, *ctypes.ResultABCI
 	path := "vm/qrender"
 	data := []byte(fmt.Sprintf("%s:%s", pkgPath, args))
 
-	qres, err := c.RPCClient.ABCIQuery(path, data)
+	qres, err := c.query(path, data)
 	if err != nil {
 		return "", nil, errors.Wrap(err, "query render")
 	}
@@ -107,6 +131,9 @@ func (c *Client) Render(pkgPath string, args string) (string, *ctypes.ResultABCI
 // include the prefix like "gno.land/". The expression is usually a function call like
 // "GetBoardIDFromName(\"testboard\")". The return value is a typed expression like
 // "(1 gno.land/r/demo/boards.BoardID)\n(true bool)".
+// If the client has a Verifier, the query is rejected with ErrUnverifiableQuery, unless
+// the Verifier has CrossCheckComputed set: the result is then cross-checked with its
+// witnesses, but not verified.
 func (c *Client) QEval(pkgPath string, expression string) (string, *ctypes.ResultABCIQuery, error) {
 	if err := c.validateRPCClient(); err != nil {
 		return "", nil, err
@@ -115,7 +142,7 @@ func (c *Client) QEval(pkgPath string, expression string) (string, *ctypes.Resul
 	path := "vm/qeval"
 	data := []byte(fmt.Sprintf("%s.%s", pkgPath, expression))
 
-	qres, err := c.RPCClient.ABCIQuery(path, data)
+	qres, err := c.query(path, data)
 	if err != nil {
 		return "", nil, errors.Wrap(err, "query qeval")
 	}
@@ -126,6 +153,81 @@ func (c *Client) QEval(pkgPath string, expression string) (string, *ctypes.Resul
 	return string(qres.Response.Data), qres, nil
 }
 
+// QEvalJSON evaluates the given expression with the realm code at pkgPath, like QEval,
+// and returns its results decoded from their JSON encoding. Each result has its type in T,
+// like "gno.land/r/demo/boards.BoardID", and its value in V, which can be decoded with
+// JSONValue.Decode. Pointers to persisted objects are returned as {"$ref":"<object id>"}.
+// If the client has a Verifier, the query is rejected with ErrUnverifiableQuery, unless
+// the Verifier has CrossCheckComputed set: the result is then cross-checked with its
+// witnesses, but not verified.
+func (c *Client) QEvalJSON(pkgPath string, expression string) ([]gno.JSONValue, *ctypes.ResultABCIQuery, error) {
+	if err := c.validateRPCClient(); err != nil {
+		return nil, nil, err
+	}
+
+	path := "vm/qeval_json"
+	data := []byte(fmt.Sprintf("%s.%s", pkgPath, expression))
+
+	qres, err := c.query(path, data)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "query qeval_json")
+	}
+	if qres.Response.Error != nil {
+		return nil, nil, errors.Wrapf(qres.Response.Error, "QEvalJSON failed: log:%s", qres.Response.Log)
+	}
+
+	var results []gno.JSONValue
+	if err := json.Unmarshal(qres.Response.Data, &results); err != nil {
+		return nil, nil, errors.Wrap(err, "unmarshaling qeval_json results")
+	}
+
+	return results, qres, nil
+}
+
+// QueryParams retrieves the chain params whose key starts with prefix, with
+// their values decoded by type. The prefix is usually the path of a realm, like
+// "gno.land/r/sys/params", or the name of a module, like "auth".
+// If the client has a Verifier, the query is rejected with ErrUnverifiableQuery, unless
+// the Verifier has CrossCheckComputed set: the result is then cross-checked with its
+// witnesses, but not verified.
+func (c *Client) QueryParams(prefix string) ([]params.Param, *ctypes.ResultABCIQuery, error) {
+	if err := c.validateRPCClient(); err != nil {
+		return nil, nil, err
+	}
+
+	path := fmt.Sprintf("params/%s/%s", params.QueryList, prefix)
+
+	qres, err := c.query(path, nil)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "query params")
+	}
+	if qres.Response.Error != nil {
+		return nil, nil, errors.Wrapf(qres.Response.Error, "QueryParams failed: log:%s", qres.Response.Log)
+	}
+
+	var prms []params.Param
+	if err := json.Unmarshal(qres.Response.Data, &prms); err != nil {
+		return nil, nil, err
+	}
+
+	return prms, qres, nil
+}
+
+// query performs the given computed ABCI query. If the client has a Verifier,
+// the query is rejected, as it can't be verified, or cross-checked (but
+// unverified) with the witnesses if the Verifier accepts it
+func (c *Client) query(path string, data []byte) (*ctypes.ResultABCIQuery, error) {
+	if c.Verifier != nil {
+		if !c.Verifier.CrossCheckComputed {
+			return nil, ErrUnverifiableQuery
+		}
+
+		return c.queryCrossChecked(path, data)
+	}
+
+	return c.RPCClient.ABCIQuery(path, data)
+}
+
 // Block gets the latest block at height, if any
 // Height must be larger than 0
 func (c *Client) Block(height int64) (*ctypes.ResultBlock, error) {
@@ -10,6 +10,7 @@ import (
 	"github.com/gnolang/gno/gno.land/pkg/sdk/vm"
 	"github.com/gnolang/gno/gnovm"
 	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	"github.com/gnolang/gno/tm2/pkg/bft/light"
 	ctypes "github.com/gnolang/gno/tm2/pkg/bft/rpc/core/types"
 	"github.com/gnolang/gno/tm2/pkg/bft/types"
 	"github.com/gnolang/gno/tm2/pkg/crypto"
@@ -60,6 +61,63 @@ func TestRender(t *testing.T) {
 	assert.Equal(t, data.Response.Data, expectedRender)
 }
 
+func TestQueryParams(t *testing.T) {
+	t.Parallel()
+
+	client := Client{
+		RPCClient: &mockRPCClient{
+			abciQuery: func(path string, data []byte) (*ctypes.ResultABCIQuery, error) {
+				assert.Equal(t, "params/list/gno.land/r/sys/params", path)
+				res := &ctypes.ResultABCIQuery{
+					Response: abci.ResponseQuery{
+						ResponseBase: abci.ResponseBase{
+							Data: []byte(`[{"key":"gno.land/r/sys/params.foo.int64","type":"int64","value":42}]`),
+						},
+					},
+				}
+				return res, nil
+			},
+		},
+	}
+
+	prms, _, err := client.QueryParams("gno.land/r/sys/params")
+	require.NoError(t, err)
+	require.Len(t, prms, 1)
+	assert.Equal(t, "gno.land/r/sys/params.foo.int64", prms[0].Key)
+	assert.Equal(t, "int64", prms[0].Type)
+	assert.JSONEq(t, "42", string(prms[0].Value))
+}
+
+func TestQEvalJSON(t *testing.T) {
+	t.Parallel()
+
+	client := Client{
+		RPCClient: &mockRPCClient{
+			abciQuery: func(path string, data []byte) (*ctypes.ResultABCIQuery, error) {
+				assert.Equal(t, "vm/qeval_json", path)
+				assert.Equal(t, `gno.land/r/demo/boards.GetBoard(1)`, string(data))
+				res := &ctypes.ResultABCIQuery{
+					Response: abci.ResponseQuery{
+						ResponseBase: abci.ResponseBase{
+							Data: []byte(`[{"T":"*gno.land/r/demo/boards.Board","V":{"$ref":"0c4f:5"}},{"T":"uint64","V":18446744073709551615}]`),
+						},
+					},
+				}
+				return res, nil
+			},
+		},
+	}
+
+	results, _, err := client.QEvalJSON("gno.land/r/demo/boards", "GetBoard(1)")
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.Equal(t, "*gno.land/r/demo/boards.Board", results[0].T)
+	assert.JSONEq(t, `{"$ref":"0c4f:5"}`, string(results[0].V))
+	var n uint64
+	require.NoError(t, results[1].Decode(&n))
+	assert.Equal(t, uint64(18446744073709551615), n)
+}
+
 // Call tests
 func TestCallSingle(t *testing.T) {
 	t.Parallel()
@@ -245,8 +303,8 @@ func TestCallErrors(t *testing.T) {
 		{
 			name: "Invalid RPCClient",
 			client: Client{
-				&mockSigner{},
-				nil,
+				Signer:    &mockSigner{},
+				RPCClient: nil,
 			},
 			cfg: BaseTxCfg{
 				GasWanted:      100000,
@@ -437,8 +495,8 @@ func TestClient_Send_Errors(t *testing.T) {
 		{
 			name: "Invalid RPCClient",
 			client: Client{
-				&mockSigner{},
-				nil,
+				Signer:    &mockSigner{},
+				RPCClient: nil,
 			},
 			cfg: BaseTxCfg{
 				GasWanted:      100000,
@@ -813,8 +871,8 @@ func TestRunErrors(t *testing.T) {
 		{
 			name: "Invalid RPCClient",
 			client: Client{
-				&mockSigner{},
-				nil,
+				Signer:    &mockSigner{},
+				RPCClient: nil,
 			},
 			cfg: BaseTxCfg{
 				GasWanted:      100000,
@@ -1012,8 +1070,8 @@ func TestAddPackageErrors(t *testing.T) {
 		{
 			name: "Invalid RPCClient",
 			client: Client{
-				&mockSigner{},
-				nil,
+				Signer:    &mockSigner{},
+				RPCClient: nil,
 			},
 			cfg: BaseTxCfg{
 				GasWanted:      100000,
@@ -1250,8 +1308,8 @@ func TestBlockErrors(t *testing.T) {
 		{
 			name: "Invalid RPCClient",
 			client: Client{
-				&mockSigner{},
-				nil,
+				Signer:    &mockSigner{},
+				RPCClient: nil,
 			},
 			height:        1,
 			expectedError: ErrMissingRPCClient,
@@ -1259,8 +1317,8 @@ func TestBlockErrors(t *testing.T) {
 		{
 			name: "Invalid height",
 			client: Client{
-				&mockSigner{},
-				&mockRPCClient{},
+				Signer:    &mockSigner{},
+				RPCClient: &mockRPCClient{},
 			},
 			height:        0,
 			expectedError: ErrInvalidBlockHeight,
@@ -1291,8 +1349,8 @@ func TestBlockResultErrors(t *testing.T) {
 		{
 			name: "Invalid RPCClient",
 			client: Client{
-				&mockSigner{},
-				nil,
+				Signer:    &mockSigner{},
+				RPCClient: nil,
 			},
 			height:        1,
 			expectedError: ErrMissingRPCClient,
@@ -1300,8 +1358,8 @@ func TestBlockResultErrors(t *testing.T) {
 		{
 			name: "Invalid height",
 			client: Client{
-				&mockSigner{},
-				&mockRPCClient{},
+				Signer:    &mockSigner{},
+				RPCClient: &mockRPCClient{},
 			},
 			height:        0,
 			expectedError: ErrInvalidBlockHeight,
@@ -1331,8 +1389,8 @@ func TestLatestBlockHeightErrors(t *testing.T) {
 		{
 			name: "Invalid RPCClient",
 			client: Client{
-				&mockSigner{},
-				nil,
+				Signer:    &mockSigner{},
+				RPCClient: nil,
 			},
 			expectedError: ErrMissingRPCClient,
 		},
@@ -1350,6 +1408,89 @@ func TestLatestBlockHeightErrors(t *testing.T) {
 	}
 }
 
+func TestNewTxFeeGranter(t *testing.T) {
+	t.Parallel()
+
+	caller := crypto.AddressFromPreimage([]byte("caller"))
+	granter := crypto.AddressFromPreimage([]byte("granter"))
+	cfg := BaseTxCfg{
+		GasWanted:  100000,
+		GasFee:     ugnot.ValueString(10000),
+		FeeGranter: granter,
+	}
+
+	tx, err := NewCallTx(cfg, vm.MsgCall{
+		Caller:  caller,
+		PkgPath: "gno.land/r/demo/deep/very/deep",
+		Func:    "Render",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, granter, tx.Fee.Granter)
+
+	tx, err = NewSendTx(cfg, bank.MsgSend{
+		FromAddress: caller,
+		ToAddress:   granter,
+		Amount:      std.Coins{{Denom: ugnot.Denom, Amount: 1}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, granter, tx.Fee.Granter)
+}
+
+func TestNewTxTimeoutHeight(t *testing.T) {
+	t.Parallel()
+
+	caller := crypto.AddressFromPreimage([]byte("caller"))
+	cfg := BaseTxCfg{
+		GasWanted:     100000,
+		GasFee:        ugnot.ValueString(10000),
+		TimeoutHeight: 42,
+	}
+
+	tx, err := NewCallTx(cfg, vm.MsgCall{
+		Caller:  caller,
+		PkgPath: "gno.land/r/demo/deep/very/deep",
+		Func:    "Render",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(42), tx.TimeoutHeight)
+
+	// the timeout height is signed over.
+	signBytes, err := tx.GetSignBytes("dev", 0, 0)
+	require.NoError(t, err)
+	assert.Contains(t, string(signBytes), `"timeout_height":"42"`)
+}
+
+func TestNewVerifierNoWitnesses(t *testing.T) {
+	t.Parallel()
+
+	verifier, err := NewVerifier("dev", light.TrustOptions{}, &mockRPCClient{}, nil)
+	assert.ErrorIs(t, err, ErrNoWitnesses)
+	assert.Nil(t, verifier)
+}
+
+func TestVerifiedComputedQueries(t *testing.T) {
+	t.Parallel()
+
+	client := Client{
+		Signer:    &mockSigner{},
+		RPCClient: &mockRPCClient{},
+		Verifier:  &Verifier{},
+	}
+
+	// computed queries can't be verified.
+	_, _, err := client.Render("gno.land/r/demo/deep/very/deep", "")
+	assert.ErrorIs(t, err, ErrUnverifiableQuery)
+
+	_, _, err = client.QEval("gno.land/r/demo/deep/very/deep", `Render("")`)
+	assert.ErrorIs(t, err, ErrUnverifiableQuery)
+
+	_, _, err = client.QEvalJSON("gno.land/r/demo/deep/very/deep", `Render("")`)
+	assert.ErrorIs(t, err, ErrUnverifiableQuery)
+
+	_, _, err = client.QueryParams("vm")
+	assert.ErrorIs(t, err, ErrUnverifiableQuery)
+}
+
 // The same as client.Call, but test signing separately
 func callSigningSeparately(t *testing.T, client Client, cfg BaseTxCfg, msgs ...vm.MsgCall) (*ctypes.ResultBroadcastTxCommit, error) {
 	t.Helper()
@@ -4,6 +4,7 @@ import (
 	"github.com/gnolang/gno/gno.land/pkg/sdk/vm"
 	"github.com/gnolang/gno/tm2/pkg/amino"
 	ctypes "github.com/gnolang/gno/tm2/pkg/bft/rpc/core/types"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
 	"github.com/gnolang/gno/tm2/pkg/errors"
 	"github.com/gnolang/gno/tm2/pkg/sdk/bank"
 	"github.com/gnolang/gno/tm2/pkg/std"
@@ -18,11 +19,13 @@ var (
 
 // BaseTxCfg defines the base transaction configuration, shared by all message types
 type BaseTxCfg struct {
-	GasFee         string // Gas fee
-	GasWanted      int64  // Gas wanted
-	AccountNumber  uint64 // Account number
-	SequenceNumber uint64 // Sequence number
-	Memo           string // Memo
+	GasFee         string         // Gas fee
+	GasWanted      int64          // Gas wanted
+	AccountNumber  uint64         // Account number
+	SequenceNumber uint64         // Sequence number
+	Memo           string         // Memo
+	FeeGranter     crypto.Address // Fee granter paying the gas fee, within its fee allowance (optional)
+	TimeoutHeight  int64          // Last block height at which the transaction can be included (optional)
 }
 
 // Call executes one or more MsgCall calls on the blockchain
@@ -68,10 +71,11 @@ func NewCallTx(cfg BaseTxCfg, msgs ...vm.MsgCall) (*std.Tx, error) {
 
 	// Pack transaction
 	return &std.Tx{
-		Msgs:       vmMsgs,
-		Fee:        std.NewFee(cfg.GasWanted, gasFeeCoins),
-		Signatures: nil,
-		Memo:       cfg.Memo,
+		Msgs:          vmMsgs,
+		Fee:           std.NewFee(cfg.GasWanted, gasFeeCoins).WithGranter(cfg.FeeGranter),
+		Signatures:    nil,
+		Memo:          cfg.Memo,
+		TimeoutHeight: cfg.TimeoutHeight,
 	}, nil
 }
 
@@ -118,10 +122,11 @@ func NewRunTx(cfg BaseTxCfg, msgs ...vm.MsgRun) (*std.Tx, error) {
 
 	// Pack transaction
 	return &std.Tx{
-		Msgs:       vmMsgs,
-		Fee:        std.NewFee(cfg.GasWanted, gasFeeCoins),
-		Signatures: nil,
-		Memo:       cfg.Memo,
+		Msgs:          vmMsgs,
+		Fee:           std.NewFee(cfg.GasWanted, gasFeeCoins).WithGranter(cfg.FeeGranter),
+		Signatures:    nil,
+		Memo:          cfg.Memo,
+		TimeoutHeight: cfg.TimeoutHeight,
 	}, nil
 }
 
@@ -168,10 +173,11 @@ func NewSendTx(cfg BaseTxCfg, msgs ...bank.MsgSend) (*std.Tx, error) {
 
 	// Pack transaction
 	return &std.Tx{
-		Msgs:       vmMsgs,
-		Fee:        std.NewFee(cfg.GasWanted, gasFeeCoins),
-		Signatures: nil,
-		Memo:       cfg.Memo,
+		Msgs:          vmMsgs,
+		Fee:           std.NewFee(cfg.GasWanted, gasFeeCoins).WithGranter(cfg.FeeGranter),
+		Signatures:    nil,
+		Memo:          cfg.Memo,
+		TimeoutHeight: cfg.TimeoutHeight,
 	}, nil
 }
 
@@ -218,10 +224,11 @@ func NewAddPackageTx(cfg BaseTxCfg, msgs ...vm.MsgAddPackage) (*std.Tx, error) {
 
 	// Pack transaction
 	return &std.Tx{
-		Msgs:       vmMsgs,
-		Fee:        std.NewFee(cfg.GasWanted, gasFeeCoins),
-		Signatures: nil,
-		Memo:       cfg.Memo,
+		Msgs:          vmMsgs,
+		Fee:           std.NewFee(cfg.GasWanted, gasFeeCoins).WithGranter(cfg.FeeGranter),
+		Signatures:    nil,
+		Memo:          cfg.Memo,
+		TimeoutHeight: cfg.TimeoutHeight,
 	}, nil
 }
 
@@ -2,15 +2,20 @@ package gnoclient
 
 import (
 	"testing"
+	"time"
 
 	"github.com/gnolang/gno/gnovm/pkg/gnolang"
 
+	"github.com/gnolang/gno/gno.land/pkg/gnoland"
 	"github.com/gnolang/gno/gno.land/pkg/gnoland/ugnot"
 	"github.com/gnolang/gno/gno.land/pkg/integration"
 	"github.com/gnolang/gno/gno.land/pkg/sdk/vm"
 	"github.com/gnolang/gno/gnovm"
 	"github.com/gnolang/gno/gnovm/pkg/gnoenv"
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	"github.com/gnolang/gno/tm2/pkg/bft/light"
 	rpcclient "github.com/gnolang/gno/tm2/pkg/bft/rpc/client"
+	ctypes "github.com/gnolang/gno/tm2/pkg/bft/rpc/core/types"
 	"github.com/gnolang/gno/tm2/pkg/crypto"
 	"github.com/gnolang/gno/tm2/pkg/crypto/keys"
 	"github.com/gnolang/gno/tm2/pkg/log"
@@ -670,3 +675,113 @@ func newInMemorySigner(t *testing.T, chainid string) *SignerFromKeybase {
 		ChainID:  chainid, // Chain ID for transaction signing
 	}
 }
+
+func TestVerifiedQueries_Integration(t *testing.T) {
+	// Set up in-memory node, with slower blocks so the witness
+	// can be queried at the same height as the primary
+	config, _ := integration.TestingNodeConfig(t, gnoenv.RootDir())
+	config.TMConfig.Consensus.SkipTimeoutCommit = false
+	config.TMConfig.Consensus.TimeoutCommit = time.Second
+
+	// Make the default account a vesting account, locking a single coin
+	genesis := config.Genesis.AppState.(gnoland.GnoGenesisState)
+	genesis.Vesting = append(genesis.Vesting, gnoland.Vesting{
+		Address:   crypto.MustAddressFromString(integration.DefaultAccount_Address),
+		Amount:    std.NewCoins(std.NewCoin(ugnot.Denom, 1)),
+		StartTime: time.Now().Add(time.Hour).Unix(),
+		EndTime:   time.Now().Add(2 * time.Hour).Unix(),
+	})
+	config.Genesis.AppState = genesis
+
+	node, remoteAddr := integration.TestingInMemoryNode(t, log.NewNoopLogger(), config)
+	defer node.Stop()
+
+	// Init Signer & RPCClient
+	signer := newInMemorySigner(t, "tendermint_test")
+	rpcClient, err := rpcclient.NewHTTPClient(remoteAddr)
+	require.NoError(t, err)
+
+	// Wait for enough blocks to query proofs
+	require.Eventually(t, func() bool {
+		status, err := rpcClient.Status()
+		require.NoError(t, err)
+
+		return status.SyncInfo.LatestBlockHeight >= 3
+	}, 30*time.Second, 100*time.Millisecond)
+
+	// Trust the first header
+	trustHeight := int64(1)
+	commit, err := rpcClient.Commit(&trustHeight)
+	require.NoError(t, err)
+
+	trustOptions := light.TrustOptions{
+		Period: time.Hour,
+		Height: trustHeight,
+		Hash:   commit.Hash(),
+	}
+
+	verifier, err := NewVerifier(commit.ChainID, trustOptions, rpcClient, []rpcclient.Client{rpcClient})
+	require.NoError(t, err)
+
+	// Setup Client
+	client := Client{
+		Signer:    signer,
+		RPCClient: rpcClient,
+		Verifier:  verifier,
+	}
+
+	caller, err := client.Signer.Info()
+	require.NoError(t, err)
+
+	// Query a proven vesting account
+	account, _, err := client.QueryAccount(caller.GetAddress())
+	require.NoError(t, err)
+
+	assert.Equal(t, caller.GetAddress(), account.GetAddress())
+	assert.False(t, account.GetCoins().IsZero())
+
+	// The account is the same without verification
+	client.Verifier = nil
+
+	unverified, _, err := client.QueryAccount(caller.GetAddress())
+	require.NoError(t, err)
+	assert.Equal(t, account, unverified)
+
+	client.Verifier = verifier
+
+	// Query a proven missing account
+	unknown, _ := crypto.AddressFromBech32("g14a0y9a64dugh3l7hneshdxr4w0rfkkww9ls35p")
+
+	_, _, err = client.QueryAccount(unknown)
+	assert.Error(t, err)
+
+	// Computed queries can't be verified
+	_, _, err = client.Render("gno.land/r/demo/deep/very/deep", "")
+	assert.ErrorIs(t, err, ErrUnverifiableQuery)
+
+	// Cross-check computed queries
+	verifier.CrossCheckComputed = true
+
+	render, _, err := client.Render("gno.land/r/demo/deep/very/deep", "")
+	require.NoError(t, err)
+	assert.Equal(t, "it works!", render)
+
+	_, _, err = client.QEval("gno.land/r/demo/deep/very/deep", `Render("")`)
+	require.NoError(t, err)
+
+	// Conflicting witnesses are detected
+	verifier.Witnesses = []rpcclient.Client{&mockRPCClient{
+		abciQueryWithOptions: func(path string, data []byte, opts rpcclient.ABCIQueryOptions) (*ctypes.ResultABCIQuery, error) {
+			return &ctypes.ResultABCIQuery{
+				Response: abci.ResponseQuery{
+					ResponseBase: abci.ResponseBase{
+						Data: []byte("it doesn't work!"),
+					},
+				},
+			}, nil
+		},
+	}}
+
+	_, _, err = client.Render("gno.land/r/demo/deep/very/deep", "")
+	assert.ErrorIs(t, err, ErrWitnessConflict)
+}
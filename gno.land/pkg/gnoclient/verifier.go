@@ -0,0 +1,237 @@
+package gnoclient
+
+import (
+	"bytes"
+	goerrors "errors"
+	"fmt"
+	"time"
+
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	"github.com/gnolang/gno/tm2/pkg/bft/light"
+	rpcclient "github.com/gnolang/gno/tm2/pkg/bft/rpc/client"
+	ctypes "github.com/gnolang/gno/tm2/pkg/bft/rpc/core/types"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/gnolang/gno/tm2/pkg/db/memdb"
+	"github.com/gnolang/gno/tm2/pkg/errors"
+	"github.com/gnolang/gno/tm2/pkg/sdk/auth"
+	"github.com/gnolang/gno/tm2/pkg/std"
+)
+
+const (
+	// mainStoreName is the name of the gno.land store holding the accounts
+	mainStoreName = "main"
+
+	// defaultTimeout is the default maximum time to wait
+	// for the header verifying a query response
+	defaultTimeout = 30 * time.Second
+
+	// headerPollInterval is the interval at which new headers are polled
+	headerPollInterval = 200 * time.Millisecond
+
+	// maxQueryAttempts is the maximum number of attempts
+	// of a computed query, when the witnesses disagree
+	maxQueryAttempts = 3
+)
+
+var (
+	ErrNoWitnesses        = errors.New("at least one witness is required")
+	ErrWitnessConflict    = errors.New("witness returned a conflicting query result")
+	ErrInvalidQueryHeight = errors.New("invalid query height")
+	ErrHeaderTimeout      = errors.New("timed out waiting for the header")
+	ErrUnverifiableQuery  = errors.New("computed queries can't be verified, they can only be cross-checked")
+)
+
+// Verifier verifies the query responses of untrusted RPC providers.
+//
+// Account queries are proven against the app hash of a light-verified header.
+// As the app hash of a state is only committed in the next block, the
+// verification waits for the next block.
+// Render, QEval and params results are computed by the node, and can't be
+// proven, so they are rejected with ErrUnverifiableQuery, unless
+// CrossCheckComputed is set. They are then NOT verified: they are only
+// cross-checked, by being evaluated at a light-verified height, and having to
+// be identical on every witness.
+// Witnesses that pruned the queried height, or are a block ahead, may disagree
+// with the primary; the query can then be retried
+type Verifier struct {
+	LightClient        *light.Client      // light-verifies the block headers
+	Witnesses          []rpcclient.Client // cross-check the computed query results
+	Timeout            time.Duration      // maximum wait for the next block, defaults to 30s
+	CrossCheckComputed bool               // accept the computed query results, only cross-checked
+}
+
+// NewVerifier creates a new query verifier, with an in-memory light client
+// anchored at the given trusted header. The witnesses also cross-check
+// the light-verified headers, so at least one is required
+func NewVerifier(
+	chainID string,
+	trustOptions light.TrustOptions,
+	primary rpcclient.Client,
+	witnesses []rpcclient.Client,
+	opts ...light.Option,
+) (*Verifier, error) {
+	if len(witnesses) == 0 {
+		return nil, ErrNoWitnesses
+	}
+
+	providers := make([]light.Provider, 0, len(witnesses))
+	for _, witness := range witnesses {
+		providers = append(providers, light.NewRPCProvider(witness))
+	}
+
+	lc, err := light.NewClient(
+		chainID,
+		trustOptions,
+		light.NewRPCProvider(primary),
+		providers,
+		light.NewDBStore(memdb.NewMemDB()),
+		opts...,
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to create light client")
+	}
+
+	return &Verifier{
+		LightClient: lc,
+		Witnesses:   witnesses,
+	}, nil
+}
+
+// queryAccountVerified retrieves the account at the given address,
+// with a merkle proof verified against a light-verified header
+func (c *Client) queryAccountVerified(addr crypto.Address) (*std.BaseAccount, *ctypes.ResultABCIQuery, error) {
+	key := auth.AddressStoreKey(addr)
+
+	// Nodes usually only keep the latest state,
+	// so the account is proven at the latest height
+	qres, err := c.RPCClient.ABCIQueryWithOptions(
+		fmt.Sprintf(".store/%s/key", mainStoreName),
+		key,
+		rpcclient.ABCIQueryOptions{Prove: true},
+	)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "query account")
+	}
+
+	// The app hash of the state at height H is in the header at height H+1
+	lb, err := c.Verifier.waitLightBlock(qres.Response.Height + 1)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := light.VerifyStoreQuery(qres.Response, mainStoreName, key, lb.AppHash); err != nil {
+		return nil, nil, errors.Wrap(err, "unable to verify account")
+	}
+
+	if len(qres.Response.Value) == 0 {
+		return nil, nil, std.ErrUnknownAddress("unknown address: " + crypto.AddressToBech32(addr))
+	}
+
+	var acc std.Account
+	if err := amino.Unmarshal(qres.Response.Value, &acc); err != nil {
+		return nil, nil, err
+	}
+
+	return baseAccount(acc), qres, nil
+}
+
+// queryCrossChecked performs the given computed query at the latest height,
+// and cross-checks the result with the witnesses at the same height. The
+// result is NOT verified, as computed queries can't be proven.
+// Conflicting results are retried, as witnesses may have moved past the height
+func (c *Client) queryCrossChecked(path string, data []byte) (*ctypes.ResultABCIQuery, error) {
+	var (
+		qres   *ctypes.ResultABCIQuery
+		height int64
+		err    error
+	)
+
+	for attempt := 0; attempt < maxQueryAttempts; attempt++ {
+		qres, height, err = c.crossCheckQuery(path, data)
+		if !goerrors.Is(err, ErrWitnessConflict) {
+			break
+		}
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	// The query height must be a light-verified height
+	if _, err := c.Verifier.waitLightBlock(height); err != nil {
+		return nil, err
+	}
+
+	return qres, nil
+}
+
+// crossCheckQuery performs the given query at the latest height, and
+// cross-checks the result with the witnesses at the same height.
+// Returns the result, and the height it was queried at
+func (c *Client) crossCheckQuery(path string, data []byte) (*ctypes.ResultABCIQuery, int64, error) {
+	status, err := c.RPCClient.Status()
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "unable to fetch latest height")
+	}
+
+	height := status.SyncInfo.LatestBlockHeight
+	if height <= 0 {
+		return nil, 0, fmt.Errorf("%w, %d", ErrInvalidQueryHeight, height)
+	}
+
+	opts := rpcclient.ABCIQueryOptions{Height: height}
+
+	qres, err := c.RPCClient.ABCIQueryWithOptions(path, data, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	for i, witness := range c.Verifier.Witnesses {
+		wres, err := witness.ABCIQueryWithOptions(path, data, opts)
+		if err != nil {
+			return nil, 0, errors.Wrapf(err, "unable to query witness %d", i)
+		}
+
+		if (wres.Response.Error == nil) != (qres.Response.Error == nil) ||
+			!bytes.Equal(wres.Response.Data, qres.Response.Data) {
+			return nil, 0, fmt.Errorf("%w, witness %d at height %d", ErrWitnessConflict, i, height)
+		}
+	}
+
+	return qres, height, nil
+}
+
+// waitLightBlock waits for the light block at the given height
+// to be committed, and light-verifies it
+func (v *Verifier) waitLightBlock(height int64) (*light.LightBlock, error) {
+	timeout := v.Timeout
+	if timeout == 0 {
+		timeout = defaultTimeout
+	}
+
+	deadline := time.Now().Add(timeout)
+
+	for {
+		latest, err := v.LightClient.Update(time.Now())
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to verify latest header")
+		}
+
+		if latest.Height >= height {
+			break
+		}
+
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("%w, height %d", ErrHeaderTimeout, height)
+		}
+
+		time.Sleep(headerPollInterval)
+	}
+
+	lb, err := v.LightClient.VerifyLightBlockAtHeight(height, time.Now())
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to verify header at height %d", height)
+	}
+
+	return lb, nil
+}
@@ -2,19 +2,20 @@
 package gnoland
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
 	"path/filepath"
-	"strconv"
+	"slices"
 	"time"
 
 	"github.com/gnolang/gno/gno.land/pkg/sdk/vm"
 	"github.com/gnolang/gno/gnovm/pkg/gnoenv"
 	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
 	"github.com/gnolang/gno/tm2/pkg/bft/config"
+	sts "github.com/gnolang/gno/tm2/pkg/bft/statesync/config"
 	bft "github.com/gnolang/gno/tm2/pkg/bft/types"
-	"github.com/gnolang/gno/tm2/pkg/crypto"
 	dbm "github.com/gnolang/gno/tm2/pkg/db"
 	"github.com/gnolang/gno/tm2/pkg/events"
 	"github.com/gnolang/gno/tm2/pkg/log"
@@ -38,7 +39,18 @@ type AppOptions struct {
 	Logger            *slog.Logger       // required
 	EventSwitch       events.EventSwitch // required
 	VMOutput          io.Writer          // optional
+	ReplaceByFee      bool               // optional, only with the priority mempool
 	InitChainerConfig                    // options related to InitChainer
+
+	// Keep the VM state in the base store, as the chains whose genesis
+	// doesn't set [GnoGenesisState.MerkleizedVMStore]. It's a chain-level
+	// setting, given by [UsesLegacyVMStore].
+	LegacyVMStore bool // optional
+
+	// State sync snapshots; disabled if SnapshotDB is nil.
+	SnapshotDB         dbm.DB // optional
+	SnapshotInterval   uint64 // block interval between snapshots, 0 to only restore them
+	SnapshotKeepRecent uint32 // number of recent snapshots to keep, 0 to keep all
 }
 
 // TestAppOptions provides a "ready" default [AppOptions] for use with
@@ -75,37 +87,64 @@ func NewAppWithOptions(cfg *AppOptions) (abci.Application, error) {
 		return nil, err
 	}
 
+	// The VM state is kept in its own merkleized store, so that it's part of
+	// the app hash and of the state sync snapshots. The chains created before
+	// keep it in the non-merkleized base store, and can't be state synced.
+	legacyVMStore := cfg.LegacyVMStore
+	if err := checkVMStoreLayout(cfg.DB, legacyVMStore); err != nil {
+		return nil, err
+	}
+	if legacyVMStore && cfg.SnapshotDB != nil {
+		return nil, errStateSyncLegacyVMStore
+	}
+
 	// Capabilities keys.
 	mainKey := store.NewStoreKey("main")
 	baseKey := store.NewStoreKey("base")
+	vmKey := baseKey
+	if !legacyVMStore {
+		vmKey = store.NewStoreKey(vmStoreName)
+	}
 
 	// Create BaseApp.
 	// TODO: Add a consensus based min gas prices for the node, by default it does not check
-	baseApp := sdk.NewBaseApp("gnoland", cfg.Logger, cfg.DB, baseKey, mainKey)
+	var options []func(*sdk.BaseApp)
+	if cfg.SnapshotDB != nil {
+		options = append(options, sdk.SetSnapshotOptions(cfg.SnapshotDB, cfg.SnapshotInterval, cfg.SnapshotKeepRecent))
+	}
+
+	baseApp := sdk.NewBaseApp("gnoland", cfg.Logger, cfg.DB, baseKey, mainKey, options...)
 	baseApp.SetAppVersion("dev")
 
 	// Set mounts for BaseApp's MultiStore.
 	baseApp.MountStoreWithDB(mainKey, iavl.StoreConstructor, cfg.DB)
 	baseApp.MountStoreWithDB(baseKey, dbadapter.StoreConstructor, cfg.DB)
+	if !legacyVMStore {
+		// A nil db gives the store its own prefix in the multistore db,
+		// apart from the main store.
+		baseApp.MountStoreWithDB(vmKey, iavl.StoreConstructor, nil)
+	}
 
 	// Construct keepers.
 	paramsKpr := params.NewParamsKeeper(mainKey, "vm")
 	acctKpr := auth.NewAccountKeeper(mainKey, paramsKpr, ProtoGnoAccount)
 	gpKpr := auth.NewGasPriceKeeper(mainKey)
-	bankKpr := bank.NewBankKeeper(acctKpr)
+	bankKpr := bank.NewBankKeeper(mainKey, acctKpr)
 
-	vmk := vm.NewVMKeeper(baseKey, mainKey, acctKpr, bankKpr, paramsKpr)
+	vmk := vm.NewVMKeeper(vmKey, mainKey, acctKpr, bankKpr, paramsKpr)
 	vmk.Output = cfg.VMOutput
 
 	// Set InitChainer
 	icc := cfg.InitChainerConfig
 	icc.baseApp = baseApp
+	icc.legacyVMStore = legacyVMStore
 	icc.acctKpr, icc.bankKpr, icc.vmKpr, icc.paramsKpr, icc.gpKpr = acctKpr, bankKpr, vmk, paramsKpr, gpKpr
 	baseApp.SetInitChainer(icc.InitChainer)
 
 	// Set AnteHandler
 	authOptions := auth.AnteOptions{
 		VerifyGenesisSignatures: false, // for development
+		ReplaceByFee:            cfg.ReplaceByFee,
 	}
 	authAnteHandler := auth.NewAnteHandler(
 		acctKpr, bankKpr, auth.DefaultSigVerificationGasConsumer, authOptions)
@@ -139,12 +178,21 @@ func NewAppWithOptions(cfg *AppOptions) (abci.Application, error) {
 		}
 	})
 
+	// Reinitialize the VMKeeper once a snapshot is restored,
+	// as the gno store caches the state it was created with.
+	baseApp.SetRestoreHook(func(ms store.MultiStore) {
+		vmk.Reinitialize(cfg.Logger, ms)
+	})
+
 	// Set up the event collector
 	c := newCollector[validatorUpdate](
 		cfg.EventSwitch,      // global event switch filled by the node
 		validatorEventFilter, // filter fn that keeps the collector valid
 	)
 
+	// Set BeginBlocker
+	baseApp.SetBeginBlocker(BeginBlocker(vmk))
+
 	// Set EndBlocker
 	baseApp.SetEndBlocker(
 		EndBlocker(
@@ -157,7 +205,7 @@ func NewAppWithOptions(cfg *AppOptions) (abci.Application, error) {
 	)
 
 	// Set a handler Route.
-	baseApp.Router().AddRoute("auth", auth.NewHandler(acctKpr))
+	baseApp.Router().AddRoute("auth", auth.NewHandler(acctKpr, auth.DefaultSigVerificationGasConsumer))
 	baseApp.Router().AddRoute("bank", bank.NewHandler(bankKpr))
 	baseApp.Router().AddRoute("params", params.NewHandler(paramsKpr))
 	baseApp.Router().AddRoute("vm", vm.NewHandler(vmk))
@@ -168,29 +216,74 @@ func NewAppWithOptions(cfg *AppOptions) (abci.Application, error) {
 	}
 
 	// Initialize the VMKeeper.
-	ms := baseApp.GetCacheMultiStore()
-	vmk.Initialize(cfg.Logger, ms)
-	ms.MultiWrite() // XXX why was't this needed?
+	// The types saved while preprocessing the packages are only kept in the
+	// gno store caches: they are derived from the stored packages, and
+	// writing them would change the VM state of the restarted nodes only.
+	vmk.Initialize(cfg.Logger, baseApp.GetCacheMultiStore())
 
 	return baseApp, nil
 }
 
+// vmStoreName is the name of the store holding the VM state.
+const vmStoreName = "vm"
+
+var (
+	errStateSyncLegacyVMStore = errors.New("state sync is not supported by chains keeping the VM state in the base store")
+	errVMStoreLayoutMismatch  = errors.New("the store keeping the VM state doesn't match the one of the genesis")
+)
+
+// UsesLegacyVMStore returns whether the chain of the genesis keeps the VM
+// state in the base store, its genesis not setting
+// [GnoGenesisState.MerkleizedVMStore].
+func UsesLegacyVMStore(genesis *bft.GenesisDoc) (bool, error) {
+	switch state := genesis.AppState.(type) {
+	case GnoGenesisState:
+		return !state.MerkleizedVMStore, nil
+	case *GnoGenesisState:
+		return !state.MerkleizedVMStore, nil
+	default:
+		return false, fmt.Errorf("invalid AppState of type %T", genesis.AppState)
+	}
+}
+
+// checkVMStoreLayout checks that the chain saved in db, if any, was committed
+// with the VM store only if it keeps the VM state in it.
+func checkVMStoreLayout(db dbm.DB, legacyVMStore bool) error {
+	names, err := store.LatestStoreNames(db)
+	if err != nil {
+		return fmt.Errorf("unable to read the committed stores: %w", err)
+	}
+	if len(names) == 0 {
+		return nil
+	}
+
+	if slices.Contains(names, vmStoreName) == legacyVMStore {
+		return errVMStoreLayoutMismatch
+	}
+	return nil
+}
+
 // NewApp creates the gno.land application.
 func NewApp(
 	dataRootDir string,
 	skipFailingGenesisTxs bool,
+	replaceByFee bool,
+	stateSync *sts.StateSyncConfig,
+	legacyVMStore bool,
 	evsw events.EventSwitch,
 	logger *slog.Logger,
 ) (abci.Application, error) {
 	var err error
 
 	cfg := &AppOptions{
-		Logger:      logger,
-		EventSwitch: evsw,
+		Logger:       logger,
+		EventSwitch:  evsw,
+		ReplaceByFee: replaceByFee,
 		InitChainerConfig: InitChainerConfig{
 			GenesisTxResultHandler: PanicOnFailingTxResultHandler,
 			StdlibDir:              filepath.Join(gnoenv.RootDir(), "gnovm", "stdlibs"),
 		},
+		LegacyVMStore: legacyVMStore,
 	}
 	if skipFailingGenesisTxs {
 		cfg.GenesisTxResultHandler = NoopGenesisTxResultHandler
@@ -202,6 +295,17 @@ func NewApp(
 		return nil, fmt.Errorf("error initializing database %q using path %q: %w", dbm.GoLevelDBBackend, dataRootDir, err)
 	}
 
+	// Get state sync snapshot DB.
+	if stateSync != nil && (stateSync.Enable || stateSync.SnapshotInterval != 0) {
+		cfg.SnapshotDB, err = dbm.NewDB("snapshots", dbm.GoLevelDBBackend, filepath.Join(dataRootDir, config.DefaultDBDir))
+		if err != nil {
+			return nil, fmt.Errorf("error initializing snapshot database %q using path %q: %w", dbm.GoLevelDBBackend, dataRootDir, err)
+		}
+
+		cfg.SnapshotInterval = stateSync.SnapshotInterval
+		cfg.SnapshotKeepRecent = stateSync.SnapshotKeepRecent
+	}
+
 	return NewAppWithOptions(cfg)
 }
 
@@ -243,6 +347,9 @@ type InitChainerConfig struct {
 	bankKpr   bank.BankKeeperI
 	paramsKpr params.ParamsKeeperI
 	gpKpr     auth.GasPriceKeeperI
+
+	// Whether the app keeps the VM state in the base store.
+	legacyVMStore bool
 }
 
 // InitChainer is the function that can be used as a [sdk.InitChainer].
@@ -300,10 +407,14 @@ func (cfg InitChainerConfig) loadAppState(ctx sdk.Context, appState any) ([]abci
 	if !ok {
 		return nil, fmt.Errorf("invalid AppState of type %T", appState)
 	}
+	if state.MerkleizedVMStore == cfg.legacyVMStore {
+		return nil, errVMStoreLayoutMismatch
+	}
 	cfg.acctKpr.InitGenesis(ctx, state.Auth)
 	params := cfg.acctKpr.GetParams(ctx)
 	ctx = ctx.WithValue(auth.AuthParamsContextKey{}, params)
 	auth.InitChainer(ctx, cfg.gpKpr.(auth.GasPriceKeeper), params.InitialGasPrice)
+	cfg.bankKpr.InitGenesis(ctx, state.Bank)
 
 	// Apply genesis balances.
 	for _, bal := range state.Balances {
@@ -315,8 +426,24 @@ func (cfg InitChainerConfig) loadAppState(ctx sdk.Context, appState any) ([]abci
 		}
 	}
 
+	// Apply genesis vesting schedules, on top of the genesis balances.
+	for _, vesting := range state.Vesting {
+		acc := cfg.acctKpr.GetAccount(ctx, vesting.Address)
+		if acc == nil {
+			return nil, fmt.Errorf("vesting account %s has no genesis balance", vesting.Address)
+		}
+		vacc := vesting.Account(acc)
+		if err := vacc.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid vesting account %s: %w", vesting.Address, err)
+		}
+		cfg.acctKpr.SetAccount(ctx, vacc)
+	}
+
 	// Apply genesis params.
 	for _, param := range state.Params {
+		if err := param.Verify(); err != nil {
+			return nil, fmt.Errorf("invalid genesis param %s: %w", param, err)
+		}
 		param.register(ctx, cfg.paramsKpr)
 	}
 
@@ -367,6 +494,23 @@ func (cfg InitChainerConfig) loadAppState(ctx sdk.Context, appState any) ([]abci
 	return txResponses, nil
 }
 
+// BeginBlocker defines the logic executed before every block.
+// Currently, it runs the upgrades of the VM state at the height set by their
+// params, outside of the gas of the transactions
+func BeginBlocker(
+	vmk vm.VMKeeperI,
+) func(
+	ctx sdk.Context,
+	req abci.RequestBeginBlock,
+) abci.ResponseBeginBlock {
+	return func(ctx sdk.Context, _ abci.RequestBeginBlock) abci.ResponseBeginBlock {
+		// Index the package imports at their height
+		vmk.IndexPackageImports(ctx)
+
+		return abci.ResponseBeginBlock{}
+	}
+}
+
 // endBlockerApp is the app abstraction required by any EndBlocker
 type endBlockerApp interface {
 	// LastBlockHeight returns the latest app height
@@ -399,27 +543,21 @@ func EndBlocker(
 			auth.EndBlocker(ctx, gpKpr)
 		}
 		// Check if there was a valset change
-		if len(collector.getEvents()) == 0 {
+		valEvents := collector.getEvents()
+		if len(valEvents) == 0 {
 			// No valset updates
 			return abci.ResponseEndBlock{}
 		}
 
-		// Run the VM to get the updates from the chain
-		response, err := vmk.QueryEval(
-			ctx,
-			valRealm,
-			fmt.Sprintf("%s(%d)", valChangesFn, app.LastBlockHeight()),
-		)
-		if err != nil {
-			app.Logger().Error("unable to call VM during EndBlocker", "err", err)
-
+		// Make sure the changes were made by the validators realm
+		if !hasValidatorUpdate(valEvents, vmk.ValidatorsPkgPath(ctx)) {
 			return abci.ResponseEndBlock{}
 		}
 
-		// Extract the updates from the VM response
-		updates, err := extractUpdatesFromResponse(response)
+		// Run the VM to get the updates from the chain
+		updates, err := vmk.QueryValidatorChanges(ctx, app.LastBlockHeight())
 		if err != nil {
-			app.Logger().Error("unable to extract updates from response", "err", err)
+			app.Logger().Error("unable to get validator changes during EndBlocker", "err", err)
 
 			return abci.ResponseEndBlock{}
 		}
@@ -429,54 +567,3 @@ func EndBlocker(
 		}
 	}
 }
-
-// extractUpdatesFromResponse extracts the validator set updates
-// from the VM response.
-//
-// This method is not ideal, but currently there is no mechanism
-// in place to parse typed VM responses
-func extractUpdatesFromResponse(response string) ([]abci.ValidatorUpdate, error) {
-	// Find the submatches
-	matches := valRegexp.FindAllStringSubmatch(response, -1)
-	if len(matches) == 0 {
-		// No changes to extract
-		return nil, nil
-	}
-
-	updates := make([]abci.ValidatorUpdate, 0, len(matches))
-	for _, match := range matches {
-		var (
-			addressRaw = match[1]
-			pubKeyRaw  = match[2]
-			powerRaw   = match[3]
-		)
-
-		// Parse the address
-		address, err := crypto.AddressFromBech32(addressRaw)
-		if err != nil {
-			return nil, fmt.Errorf("unable to parse address, %w", err)
-		}
-
-		// Parse the public key
-		pubKey, err := crypto.PubKeyFromBech32(pubKeyRaw)
-		if err != nil {
-			return nil, fmt.Errorf("unable to parse public key, %w", err)
-		}
-
-		// Parse the voting power
-		power, err := strconv.ParseInt(powerRaw, 10, 64)
-		if err != nil {
-			return nil, fmt.Errorf("unable to parse voting power, %w", err)
-		}
-
-		update := abci.ValidatorUpdate{
-			Address: address,
-			PubKey:  pubKey,
-			Power:   power,
-		}
-
-		updates = append(updates, update)
-	}
-
-	return updates, nil
-}
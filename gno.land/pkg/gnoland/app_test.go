@@ -4,7 +4,6 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"strings"
 	"testing"
 	"time"
 
@@ -15,6 +14,7 @@ import (
 	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
 	bft "github.com/gnolang/gno/tm2/pkg/bft/types"
 	"github.com/gnolang/gno/tm2/pkg/crypto"
+	dbm "github.com/gnolang/gno/tm2/pkg/db"
 	"github.com/gnolang/gno/tm2/pkg/db/memdb"
 	"github.com/gnolang/gno/tm2/pkg/events"
 	"github.com/gnolang/gno/tm2/pkg/log"
@@ -134,7 +134,7 @@ func TestNewApp(t *testing.T) {
 	// NewApp should have good defaults and manage to run InitChain.
 	td := t.TempDir()
 
-	app, err := NewApp(td, true, events.NewEventSwitch(), log.NewNoopLogger())
+	app, err := NewApp(td, true, false, nil, false, events.NewEventSwitch(), log.NewNoopLogger())
 	require.NoError(t, err, "NewApp should be successful")
 
 	resp := app.InitChain(abci.RequestInitChain{
@@ -153,6 +153,230 @@ func TestNewApp(t *testing.T) {
 	assert.True(t, resp.IsOK(), "resp is not OK: %v", resp)
 }
 
+// Tests that the VM state is restored out of a state sync snapshot,
+// and verified by the app hash.
+func TestNewAppWithOptions_StateSync(t *testing.T) {
+	t.Parallel()
+
+	const (
+		chainID  = "dev"
+		realm    = "gno.land/r/test/greeting"
+		greeting = "hello from genesis"
+	)
+
+	// Create a source app, taking a snapshot every 2 blocks
+	opts := TestAppOptions(memdb.NewMemDB())
+	opts.SnapshotDB = memdb.NewMemDB()
+	opts.SnapshotInterval = 2
+	opts.SnapshotKeepRecent = 1
+
+	app, err := NewAppWithOptions(opts)
+	require.NoError(t, err)
+	source := app.(*sdk.BaseApp)
+
+	addr := crypto.AddressFromPreimage([]byte("test1"))
+	fee := std.Fee{GasWanted: 1e7, GasFee: std.Coin{Amount: 1e6, Denom: "ugnot"}}
+
+	appState := DefaultGenState()
+	appState.Balances = []Balance{
+		{
+			Address: addr,
+			Amount:  []std.Coin{{Amount: 1e15, Denom: "ugnot"}},
+		},
+	}
+	appState.Txs = []TxWithMetadata{
+		{
+			Tx: std.Tx{
+				Msgs: []std.Msg{vm.NewMsgAddPackage(addr, realm, []*gnovm.MemFile{
+					{
+						Name: "greeting.gno",
+						Body: `package greeting
+
+var greeting = "hello"
+
+func SetGreeting(s string) { greeting = s }
+
+func Render(_ string) string { return greeting }`,
+					},
+				})},
+				Fee:        fee,
+				Signatures: []std.Signature{{}}, // one empty signature
+			},
+		},
+		{
+			Tx: std.Tx{
+				Msgs:       []std.Msg{vm.NewMsgCall(addr, nil, realm, "SetGreeting", []string{greeting})},
+				Fee:        fee,
+				Signatures: []std.Signature{{}}, // one empty signature
+			},
+		},
+	}
+
+	resp := source.InitChain(abci.RequestInitChain{
+		Time:    time.Now(),
+		ChainID: chainID,
+		ConsensusParams: &abci.ConsensusParams{
+			Block: defaultBlockParams(),
+		},
+		Validators: []abci.ValidatorUpdate{},
+		AppState:   appState,
+	})
+	require.True(t, resp.IsOK(), "InitChain response: %v", resp)
+
+	commitBlock := func(app *sdk.BaseApp, height int64) {
+		header := &bft.Header{ChainID: chainID, Height: height}
+		app.BeginBlock(abci.RequestBeginBlock{Header: header})
+		app.EndBlock(abci.RequestEndBlock{})
+		app.Commit()
+	}
+	commitBlock(source, 1)
+	commitBlock(source, 2)
+
+	// Snapshots are created in the background
+	var snapshot *abci.Snapshot
+	require.Eventually(t, func() bool {
+		res := source.ListSnapshots(abci.RequestListSnapshots{})
+		if len(res.Snapshots) == 0 {
+			return false
+		}
+		snapshot = res.Snapshots[0]
+		return true
+	}, 10*time.Second, 10*time.Millisecond)
+	require.Equal(t, int64(2), snapshot.Height)
+
+	// Restore the snapshot into a new app
+	opts = TestAppOptions(memdb.NewMemDB())
+	opts.SnapshotDB = memdb.NewMemDB()
+
+	app, err = NewAppWithOptions(opts)
+	require.NoError(t, err)
+	target := app.(*sdk.BaseApp)
+
+	offerRes := target.OfferSnapshot(abci.RequestOfferSnapshot{
+		Snapshot: snapshot,
+		AppHash:  source.LastCommitID().Hash,
+		Header:   &bft.Header{ChainID: chainID, Height: snapshot.Height},
+	})
+	require.Equal(t, abci.OfferSnapshotAccept, offerRes.Result)
+
+	for index := uint32(0); index < snapshot.Chunks; index++ {
+		chunkRes := source.LoadSnapshotChunk(abci.RequestLoadSnapshotChunk{
+			Height: snapshot.Height,
+			Format: snapshot.Format,
+			Chunk:  index,
+		})
+		require.NotEmpty(t, chunkRes.Chunk)
+
+		applyRes := target.ApplySnapshotChunk(abci.RequestApplySnapshotChunk{
+			Index: index,
+			Chunk: chunkRes.Chunk,
+		})
+		require.Equal(t, abci.ApplySnapshotChunkAccept, applyRes.Result)
+	}
+	require.Equal(t, source.LastCommitID(), target.LastCommitID())
+
+	// The realm state was restored along with the app hash
+	qres := target.Query(abci.RequestQuery{
+		Path: "vm/qrender",
+		Data: []byte(realm + ":"),
+	})
+	require.True(t, qres.IsOK(), "Query response: %v", qres)
+	assert.Equal(t, greeting, string(qres.Data))
+
+	// Both apps keep producing the same app hash
+	commitBlock(source, 3)
+	commitBlock(target, 3)
+	assert.Equal(t, source.LastCommitID(), target.LastCommitID())
+}
+
+// Tests that the chains keeping the VM state in the base store still start,
+// but can't be state synced.
+func TestNewAppWithOptions_LegacyVMStore(t *testing.T) {
+	t.Parallel()
+
+	db := memdb.NewMemDB()
+
+	// Commit a chain without the VM store
+	cms := store.NewCommitMultiStore(db)
+	cms.MountStoreWithDB(store.NewStoreKey("main"), iavl.StoreConstructor, db)
+	cms.MountStoreWithDB(store.NewStoreKey("base"), dbadapter.StoreConstructor, db)
+	require.NoError(t, cms.LoadLatestVersion())
+	commitID := cms.Commit()
+
+	legacyAppOptions := func() *AppOptions {
+		opts := TestAppOptions(db)
+		opts.LegacyVMStore = true
+		return opts
+	}
+
+	opts := legacyAppOptions()
+	opts.SnapshotDB = memdb.NewMemDB()
+
+	_, err := NewAppWithOptions(opts)
+	require.ErrorIs(t, err, errStateSyncLegacyVMStore)
+
+	// The layout is given by the genesis, not by the db
+	_, err = NewAppWithOptions(TestAppOptions(db))
+	require.ErrorIs(t, err, errVMStoreLayoutMismatch)
+
+	app, err := NewAppWithOptions(legacyAppOptions())
+	require.NoError(t, err)
+	assert.Equal(t, commitID, app.(*sdk.BaseApp).LastCommitID())
+}
+
+// Tests that the store keeping the VM state is given by the genesis, so that
+// the new nodes of a chain keeping it in the base store replay it from
+// genesis with the same stores.
+func TestInitChainer_VMStoreLayout(t *testing.T) {
+	t.Parallel()
+
+	legacyGenesis := &bft.GenesisDoc{AppState: GnoGenesisState{}}
+	legacy, err := UsesLegacyVMStore(legacyGenesis)
+	require.NoError(t, err)
+	assert.True(t, legacy)
+
+	genesis := &bft.GenesisDoc{AppState: &GnoGenesisState{MerkleizedVMStore: true}}
+	legacy, err = UsesLegacyVMStore(genesis)
+	require.NoError(t, err)
+	assert.False(t, legacy)
+
+	initChain := func(db dbm.DB, legacyVMStore bool, appState GnoGenesisState) (*sdk.BaseApp, abci.ResponseInitChain) {
+		t.Helper()
+
+		opts := TestAppOptions(db)
+		opts.LegacyVMStore = legacyVMStore
+		app, err := NewAppWithOptions(opts)
+		require.NoError(t, err)
+
+		bapp := app.(*sdk.BaseApp)
+		return bapp, bapp.InitChain(abci.RequestInitChain{
+			ChainID: "dev",
+			ConsensusParams: &abci.ConsensusParams{
+				Block: defaultBlockParams(),
+			},
+			AppState: appState,
+		})
+	}
+
+	appState := DefaultGenState()
+	appState.MerkleizedVMStore = false
+
+	// The app must use the layout of the genesis
+	_, resp := initChain(memdb.NewMemDB(), false, appState)
+	assert.False(t, resp.IsOK())
+	assert.Contains(t, resp.Error.Error(), errVMStoreLayoutMismatch.Error())
+
+	db := memdb.NewMemDB()
+	app, resp := initChain(db, true, appState)
+	require.True(t, resp.IsOK(), "InitChain response: %v", resp)
+
+	// The chain is committed without the VM store
+	app.Commit()
+	names, err := store.LatestStoreNames(db)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"base", "main"}, names)
+}
+
 // Test whether InitChainer calls to load the stdlibs correctly.
 func TestInitChainer_LoadStdlib(t *testing.T) {
 	t.Parallel()
@@ -220,8 +444,11 @@ func testInitChainerLoadStdlib(t *testing.T, cached bool) { //nolint:thelper
 	}
 	// Construct keepers.
 	paramsKpr := params.NewParamsKeeper(iavlCapKey, "")
-	cfg.acctKpr = auth.NewAccountKeeper(iavlCapKey, paramsKpr, ProtoGnoAccount)
+	acctKpr := auth.NewAccountKeeper(iavlCapKey, paramsKpr, ProtoGnoAccount)
+	cfg.acctKpr = acctKpr
+	cfg.paramsKpr = paramsKpr
 	cfg.gpKpr = auth.NewGasPriceKeeper(iavlCapKey)
+	cfg.bankKpr = bank.NewBankKeeper(iavlCapKey, acctKpr)
 	cfg.InitChainer(testCtx, abci.RequestInitChain{
 		AppState: DefaultGenState(),
 	})
@@ -310,7 +537,8 @@ func TestInitChainer_MetadataTxs(t *testing.T) {
 					},
 				},
 				// Make sure the deployer account has a balance
-				Balances: balances,
+				Balances:          balances,
+				MerkleizedVMStore: true,
 			}
 		}
 
@@ -321,7 +549,8 @@ func TestInitChainer_MetadataTxs(t *testing.T) {
 						Tx: tx,
 					},
 				},
-				Balances: balances,
+				Balances:          balances,
+				MerkleizedVMStore: true,
 			}
 		}
 	)
@@ -443,33 +672,153 @@ func TestInitChainer_MetadataTxs(t *testing.T) {
 	}
 }
 
-func TestEndBlocker(t *testing.T) {
+func TestInitChainer_Vesting(t *testing.T) {
 	t.Parallel()
 
-	constructVMResponse := func(updates []abci.ValidatorUpdate) string {
-		var builder strings.Builder
+	var (
+		genesisTime = time.Unix(1_700_000_000, 0)
+		chainID     = "test"
+
+		vestingState = func(addr crypto.Address, balances []Balance) GnoGenesisState {
+			state := DefaultGenState()
+			state.Balances = balances
+			state.Vesting = []Vesting{
+				{
+					Address:   addr,
+					Amount:    std.NewCoins(std.NewCoin("ugnot", 10_000_000)),
+					StartTime: genesisTime.Unix(),
+					EndTime:   genesisTime.Unix() + 1000,
+				},
+			}
+			return state
+		}
+	)
 
-		builder.WriteString("(slice[")
+	testTable := []struct {
+		name     string
+		amount   int64
+		expected error
+	}{
+		{"spendable coins", 7_000_000, nil},
+		{"locked coins", 9_000_000, std.InsufficientCoinsError{}},
+	}
 
-		for i, update := range updates {
-			builder.WriteString(
-				fmt.Sprintf(
-					"(struct{(%q std.Address),(%q string),(%d uint64)} gno.land/p/sys/validators.Validator)",
-					update.Address,
-					update.PubKey,
-					update.Power,
-				),
-			)
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			key := getDummyKey(t)
+			addr := key.PubKey().Address()
+
+			app, err := NewAppWithOptions(TestAppOptions(memdb.NewMemDB()))
+			require.NoError(t, err)
+
+			resp := app.InitChain(abci.RequestInitChain{
+				ChainID: chainID,
+				Time:    genesisTime,
+				ConsensusParams: &abci.ConsensusParams{
+					Block: defaultBlockParams(),
+				},
+				Validators: []abci.ValidatorUpdate{},
+				AppState: vestingState(addr, []Balance{
+					{
+						Address: addr,
+						Amount:  std.NewCoins(std.NewCoin("ugnot", 20_000_000)),
+					},
+				}),
+			})
+			require.True(t, resp.IsOK(), "InitChain response: %v", resp)
+
+			// Half of the balance is locked; the fee is paid with the
+			// spendable coins
+			to := crypto.AddressFromPreimage([]byte("to"))
+			msg := bank.NewMsgSend(addr, to, std.NewCoins(std.NewCoin("ugnot", testCase.amount)))
+			tx := createAndSignTx(t, []std.Msg{msg}, chainID, key)
 
-			if i < len(updates)-1 {
-				builder.WriteString(",")
+			dtxResp := app.DeliverTx(abci.RequestDeliverTx{
+				Tx: amino.MustMarshal(tx),
+			})
+			if testCase.expected == nil {
+				require.True(t, dtxResp.IsOK(), "DeliverTx response: %v", dtxResp)
+			} else {
+				assert.IsType(t, testCase.expected, dtxResp.Error)
 			}
-		}
+		})
+	}
+
+	t.Run("no genesis balance", func(t *testing.T) {
+		t.Parallel()
 
-		builder.WriteString("] []gno.land/p/sys/validators.Validator)")
+		addr := crypto.AddressFromPreimage([]byte("vesting"))
+
+		app, err := NewAppWithOptions(TestAppOptions(memdb.NewMemDB()))
+		require.NoError(t, err)
+
+		resp := app.InitChain(abci.RequestInitChain{
+			ChainID: chainID,
+			Time:    genesisTime,
+			ConsensusParams: &abci.ConsensusParams{
+				Block: defaultBlockParams(),
+			},
+			Validators: []abci.ValidatorUpdate{},
+			AppState:   vestingState(addr, nil),
+		})
+		require.False(t, resp.IsOK())
+		assert.Contains(t, resp.Error.Error(), "has no genesis balance")
+	})
+}
+
+func TestInitChainer_InvalidGasSchedule(t *testing.T) {
+	t.Parallel()
 
-		return builder.String()
+	app, err := NewAppWithOptions(TestAppOptions(memdb.NewMemDB()))
+	require.NoError(t, err)
+
+	state := DefaultGenState()
+	state.Params = []Param{
+		{key: "gno.land/r/sys/params.vm.gas_schedule.1", kind: ParamKindString, value: `{"cpu":{"OpFoo":1}}`},
 	}
+	resp := app.InitChain(abci.RequestInitChain{
+		ChainID: "test",
+		Time:    time.Now(),
+		ConsensusParams: &abci.ConsensusParams{
+			Block: defaultBlockParams(),
+		},
+		Validators: []abci.ValidatorUpdate{},
+		AppState:   state,
+	})
+	require.False(t, resp.IsOK())
+	assert.Contains(t, resp.Error.Error(), "invalid gas schedule")
+}
+
+// testValRealm is the validators realm returned by the mockVMKeeper.
+const testValRealm = "gno.land/r/sys/validators/v2"
+
+func TestBeginBlocker(t *testing.T) {
+	t.Parallel()
+
+	var indexed []int64
+
+	mockVMKeeper := &mockVMKeeper{
+		indexPackageImportsFn: func(ctx sdk.Context) {
+			indexed = append(indexed, ctx.BlockHeight())
+		},
+	}
+
+	// Run the BeginBlocker
+	bb := BeginBlocker(mockVMKeeper)
+	for height := int64(1); height <= 3; height++ {
+		ctx := sdk.Context{}.WithBlockHeader(&bft.Header{Height: height})
+		res := bb(ctx, abci.RequestBeginBlock{})
+		assert.Equal(t, abci.ResponseBeginBlock{}, res)
+	}
+
+	// The keeper indexes the package imports at the height of their param
+	assert.Equal(t, []int64{1, 2, 3}, indexed)
+}
+
+func TestEndBlocker(t *testing.T) {
+	t.Parallel()
 
 	newCommonEvSwitch := func() *mockEventSwitch {
 		var cb events.EventCallback
@@ -504,12 +853,12 @@ func TestEndBlocker(t *testing.T) {
 		assert.Equal(t, abci.ResponseEndBlock{}, res)
 	})
 
-	t.Run("invalid VM call", func(t *testing.T) {
+	t.Run("events of another realm", func(t *testing.T) {
 		t.Parallel()
 
 		var (
 			noFilter = func(_ events.Event) []validatorUpdate {
-				return make([]validatorUpdate, 1) // 1 update
+				return []validatorUpdate{{pkgPath: "gno.land/r/demo/validators"}}
 			}
 
 			vmCalled bool
@@ -517,13 +866,10 @@ func TestEndBlocker(t *testing.T) {
 			mockEventSwitch = newCommonEvSwitch()
 
 			mockVMKeeper = &mockVMKeeper{
-				queryFn: func(_ sdk.Context, pkgPath, expr string) (string, error) {
+				queryValidatorChangesFn: func(_ sdk.Context, _ int64) ([]abci.ValidatorUpdate, error) {
 					vmCalled = true
 
-					require.Equal(t, valRealm, pkgPath)
-					require.NotEmpty(t, expr)
-
-					return "", errors.New("random call error")
+					return nil, nil
 				},
 			}
 		)
@@ -543,16 +889,16 @@ func TestEndBlocker(t *testing.T) {
 		// Verify the response was empty
 		assert.Equal(t, abci.ResponseEndBlock{}, res)
 
-		// Make sure the VM was called
-		assert.True(t, vmCalled)
+		// Make sure the VM was not called
+		assert.False(t, vmCalled)
 	})
 
-	t.Run("empty VM response", func(t *testing.T) {
+	t.Run("invalid VM call", func(t *testing.T) {
 		t.Parallel()
 
 		var (
 			noFilter = func(_ events.Event) []validatorUpdate {
-				return make([]validatorUpdate, 1) // 1 update
+				return []validatorUpdate{{pkgPath: testValRealm}} // 1 update
 			}
 
 			vmCalled bool
@@ -560,13 +906,10 @@ func TestEndBlocker(t *testing.T) {
 			mockEventSwitch = newCommonEvSwitch()
 
 			mockVMKeeper = &mockVMKeeper{
-				queryFn: func(_ sdk.Context, pkgPath, expr string) (string, error) {
+				queryValidatorChangesFn: func(_ sdk.Context, _ int64) ([]abci.ValidatorUpdate, error) {
 					vmCalled = true
 
-					require.Equal(t, valRealm, pkgPath)
-					require.NotEmpty(t, expr)
-
-					return constructVMResponse([]abci.ValidatorUpdate{}), nil
+					return nil, errors.New("random call error")
 				},
 			}
 		)
@@ -599,11 +942,16 @@ func TestEndBlocker(t *testing.T) {
 			mockEventSwitch = newCommonEvSwitch()
 
 			mockVMKeeper = &mockVMKeeper{
-				queryFn: func(_ sdk.Context, pkgPath, expr string) (string, error) {
-					require.Equal(t, valRealm, pkgPath)
-					require.NotEmpty(t, expr)
+				queryValidatorChangesFn: func(_ sdk.Context, from int64) ([]abci.ValidatorUpdate, error) {
+					require.Equal(t, int64(10), from)
+
+					return changes, nil
+				},
+			}
 
-					return constructVMResponse(changes), nil
+			mockApp = &mockEndBlockerApp{
+				lastBlockHeightFn: func() int64 {
+					return 10
 				},
 			}
 		)
@@ -616,7 +964,7 @@ func TestEndBlocker(t *testing.T) {
 		for index := range changes {
 			event := gnostdlibs.GnoEvent{
 				Type:    validatorAddedEvent,
-				PkgPath: valRealm,
+				PkgPath: testValRealm,
 			}
 
 			// Make half the changes validator removes
@@ -625,7 +973,7 @@ func TestEndBlocker(t *testing.T) {
 
 				event = gnostdlibs.GnoEvent{
 					Type:    validatorRemovedEvent,
-					PkgPath: valRealm,
+					PkgPath: testValRealm,
 				}
 			}
 
@@ -646,7 +994,7 @@ func TestEndBlocker(t *testing.T) {
 		mockEventSwitch.FireEvent(txEvent)
 
 		// Create the EndBlocker
-		eb := EndBlocker(c, nil, nil, mockVMKeeper, &mockEndBlockerApp{})
+		eb := EndBlocker(c, nil, nil, mockVMKeeper, mockApp)
 
 		// Run the EndBlocker
 		res := eb(sdk.Context{}, abci.RequestEndBlock{})
@@ -662,6 +1010,31 @@ func TestEndBlocker(t *testing.T) {
 	})
 }
 
+func TestValidatorEventFilter(t *testing.T) {
+	t.Parallel()
+
+	txEvent := bft.EventTx{
+		Result: bft.TxResult{
+			Response: abci.ResponseDeliverTx{
+				ResponseBase: abci.ResponseBase{
+					Events: []abci.Event{
+						gnostdlibs.GnoEvent{Type: validatorAddedEvent, PkgPath: testValRealm},
+						gnostdlibs.GnoEvent{Type: "Transfer", PkgPath: "gno.land/r/demo/foo20"},
+						gnostdlibs.GnoEvent{Type: validatorRemovedEvent, PkgPath: testValRealm},
+						gnostdlibs.GnoEvent{Type: validatorAddedEvent, PkgPath: "gno.land/r/demo/validators"},
+					},
+				},
+			},
+		},
+	}
+
+	assert.Equal(t, []validatorUpdate{
+		{pkgPath: testValRealm},
+		{pkgPath: "gno.land/r/demo/validators"},
+	}, validatorEventFilter(txEvent))
+	assert.Nil(t, validatorEventFilter(gnostdlibs.GnoEvent{}))
+}
+
 func TestGasPriceUpdate(t *testing.T) {
 	app := newGasPriceTestApp(t)
 
@@ -825,7 +1198,7 @@ func newGasPriceTestApp(t *testing.T) abci.Application {
 	paramsKpr := params.NewParamsKeeper(mainKey, "")
 	acctKpr := auth.NewAccountKeeper(mainKey, paramsKpr, ProtoGnoAccount)
 	gpKpr := auth.NewGasPriceKeeper(mainKey)
-	bankKpr := bank.NewBankKeeper(acctKpr)
+	bankKpr := bank.NewBankKeeper(mainKey, acctKpr)
 	vmk := vm.NewVMKeeper(baseKey, mainKey, acctKpr, bankKpr, paramsKpr)
 
 	// Set InitChainer
@@ -883,7 +1256,7 @@ func newGasPriceTestApp(t *testing.T) abci.Application {
 	)
 
 	// Set a handler Route.
-	baseApp.Router().AddRoute("auth", auth.NewHandler(acctKpr))
+	baseApp.Router().AddRoute("auth", auth.NewHandler(acctKpr, auth.DefaultSigVerificationGasConsumer))
 	baseApp.Router().AddRoute("bank", bank.NewHandler(bankKpr))
 	baseApp.Router().AddRoute(
 		testutils.RouteMsgCounter,
@@ -947,7 +1320,8 @@ func gnoGenesisState(t *testing.T) GnoGenesisState {
         "tx_sig_limit": "7",
         "tx_size_cost_per_byte": "10"
       }
-    }
+    },
+    "merkleized_vm_store": true
   }`)
 	err := amino.UnmarshalJSON(genBytes, &gen)
 	if err != nil {
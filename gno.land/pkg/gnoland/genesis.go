@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/gnolang/gno/gno.land/pkg/gnoland/ugnot"
 	vmm "github.com/gnolang/gno/gno.land/pkg/sdk/vm"
 	gno "github.com/gnolang/gno/gnovm/pkg/gnolang"
 	"github.com/gnolang/gno/gnovm/pkg/gnomod"
@@ -13,6 +14,7 @@ import (
 	"github.com/gnolang/gno/tm2/pkg/crypto"
 	osm "github.com/gnolang/gno/tm2/pkg/os"
 	"github.com/gnolang/gno/tm2/pkg/sdk/auth"
+	"github.com/gnolang/gno/tm2/pkg/sdk/bank"
 	"github.com/gnolang/gno/tm2/pkg/std"
 	"github.com/pelletier/go-toml"
 )
@@ -199,11 +201,33 @@ func DefaultGenState() GnoGenesisState {
 	}
 	authGen.Params.InitialGasPrice = gp
 
+	bankGen := bank.NewGenesisState([]bank.DenomMetadata{
+		{
+			Base:        ugnot.Denom,
+			Display:     "gnot",
+			Exponent:    6,
+			Description: "gno.land native coin",
+		},
+	})
+
 	gs := GnoGenesisState{
-		Balances: []Balance{},
-		Txs:      []TxWithMetadata{},
-		Auth:     authGen,
+		Balances:          []Balance{},
+		Txs:               []TxWithMetadata{},
+		Params:            []Param{indexPackageImportsParam()},
+		Auth:              authGen,
+		Bank:              bankGen,
+		MerkleizedVMStore: true,
 	}
 
 	return gs
 }
+
+// indexPackageImportsParam returns the param indexing the package imports
+// in the first block, so that the realms of a new chain can be upgraded.
+func indexPackageImportsParam() Param {
+	return Param{
+		key:   strings.TrimSuffix(vmm.IndexPackageImportsHeightParamPath, "."+ParamKindInt64),
+		kind:  ParamKindInt64,
+		value: int64(1),
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"log/slog"
 
 	"github.com/gnolang/gno/gno.land/pkg/sdk/vm"
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
 	"github.com/gnolang/gno/tm2/pkg/events"
 	"github.com/gnolang/gno/tm2/pkg/log"
 	"github.com/gnolang/gno/tm2/pkg/sdk"
@@ -47,13 +48,17 @@ func (m *mockEventSwitch) RemoveListener(listenerID string) {
 
 type mockVMKeeper struct {
 	addPackageFn                func(sdk.Context, vm.MsgAddPackage) error
+	upgradePackageFn            func(sdk.Context, vm.MsgUpgradePackage) error
 	callFn                      func(sdk.Context, vm.MsgCall) (string, error)
 	queryFn                     func(sdk.Context, string, string) (string, error)
+	queryValidatorChangesFn     func(sdk.Context, int64) ([]abci.ValidatorUpdate, error)
+	validatorsPkgPathFn         func(sdk.Context) string
 	runFn                       func(sdk.Context, vm.MsgRun) (string, error)
 	loadStdlibFn                func(sdk.Context, string)
 	loadStdlibCachedFn          func(sdk.Context, string)
 	makeGnoTransactionStoreFn   func(ctx sdk.Context) sdk.Context
 	commitGnoTransactionStoreFn func(ctx sdk.Context)
+	indexPackageImportsFn       func(ctx sdk.Context)
 }
 
 func (m *mockVMKeeper) AddPackage(ctx sdk.Context, msg vm.MsgAddPackage) error {
@@ -64,6 +69,14 @@ func (m *mockVMKeeper) AddPackage(ctx sdk.Context, msg vm.MsgAddPackage) error {
 	return nil
 }
 
+func (m *mockVMKeeper) UpgradePackage(ctx sdk.Context, msg vm.MsgUpgradePackage) error {
+	if m.upgradePackageFn != nil {
+		return m.upgradePackageFn(ctx, msg)
+	}
+
+	return nil
+}
+
 func (m *mockVMKeeper) Call(ctx sdk.Context, msg vm.MsgCall) (res string, err error) {
 	if m.callFn != nil {
 		return m.callFn(ctx, msg)
@@ -80,6 +93,22 @@ func (m *mockVMKeeper) QueryEval(ctx sdk.Context, pkgPath, expr string) (res str
 	return "", nil
 }
 
+func (m *mockVMKeeper) QueryValidatorChanges(ctx sdk.Context, from int64) ([]abci.ValidatorUpdate, error) {
+	if m.queryValidatorChangesFn != nil {
+		return m.queryValidatorChangesFn(ctx, from)
+	}
+
+	return nil, nil
+}
+
+func (m *mockVMKeeper) ValidatorsPkgPath(ctx sdk.Context) string {
+	if m.validatorsPkgPathFn != nil {
+		return m.validatorsPkgPathFn(ctx)
+	}
+
+	return testValRealm
+}
+
 func (m *mockVMKeeper) Run(ctx sdk.Context, msg vm.MsgRun) (res string, err error) {
 	if m.runFn != nil {
 		return m.runFn(ctx, msg)
@@ -113,6 +142,12 @@ func (m *mockVMKeeper) CommitGnoTransactionStore(ctx sdk.Context) {
 	}
 }
 
+func (m *mockVMKeeper) IndexPackageImports(ctx sdk.Context) {
+	if m.indexPackageImportsFn != nil {
+		m.indexPackageImportsFn(ctx)
+	}
+}
+
 type (
 	lastBlockHeightDelegate func() int64
 	loggerDelegate          func() *slog.Logger
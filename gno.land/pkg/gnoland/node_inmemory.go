@@ -9,6 +9,7 @@ import (
 
 	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
 	tmcfg "github.com/gnolang/gno/tm2/pkg/bft/config"
+	memcfg "github.com/gnolang/gno/tm2/pkg/bft/mempool/config"
 	"github.com/gnolang/gno/tm2/pkg/bft/node"
 	"github.com/gnolang/gno/tm2/pkg/bft/proxy"
 	bft "github.com/gnolang/gno/tm2/pkg/bft/types"
@@ -52,7 +53,9 @@ func NewDefaultGenesisConfig(chainid, chaindomain string) *bft.GenesisDoc {
 			Txs:      []TxWithMetadata{},
 			Params: []Param{
 				domainParam,
+				indexPackageImportsParam(),
 			},
+			MerkleizedVMStore: true,
 		},
 	}
 }
@@ -77,6 +80,10 @@ func (cfg *InMemoryNodeConfig) validate() error {
 		return fmt.Errorf("`PrivValidator` is required but not provided")
 	}
 
+	if cfg.Genesis == nil {
+		return fmt.Errorf("`Genesis` is required but not provided")
+	}
+
 	if cfg.TMConfig == nil {
 		return fmt.Errorf("`TMConfig` is required but not provided")
 	}
@@ -110,14 +117,30 @@ func NewInMemoryNode(logger *slog.Logger, cfg *InMemoryNodeConfig) (*node.Node,
 		cfg.DB = memdb.NewMemDB()
 	}
 
+	legacyVMStore, err := UsesLegacyVMStore(cfg.Genesis)
+	if err != nil {
+		return nil, fmt.Errorf("invalid genesis: %w", err)
+	}
+
 	// Initialize the application with the provided options
-	gnoApp, err := NewAppWithOptions(&AppOptions{
+	appOpts := &AppOptions{
 		Logger:            logger,
 		DB:                cfg.DB,
 		EventSwitch:       evsw,
 		InitChainerConfig: cfg.InitChainerConfig,
 		VMOutput:          cfg.VMOutput,
-	})
+		ReplaceByFee:      cfg.TMConfig.Mempool.Type == memcfg.PriorityMempoolType,
+		LegacyVMStore:     legacyVMStore,
+	}
+
+	// State sync snapshots are also kept in memory
+	if stateSync := cfg.TMConfig.StateSync; stateSync.Enable || stateSync.SnapshotInterval != 0 {
+		appOpts.SnapshotDB = memdb.NewMemDB()
+		appOpts.SnapshotInterval = stateSync.SnapshotInterval
+		appOpts.SnapshotKeepRecent = stateSync.SnapshotKeepRecent
+	}
+
+	gnoApp, err := NewAppWithOptions(appOpts)
 	if err != nil {
 		return nil, fmt.Errorf("error initializing new app: %w", err)
 	}
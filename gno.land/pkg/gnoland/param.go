@@ -7,6 +7,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/gnolang/gno/gno.land/pkg/sdk/vm"
 	"github.com/gnolang/gno/tm2/pkg/sdk"
 	"github.com/gnolang/gno/tm2/pkg/sdk/params"
 )
@@ -18,8 +19,11 @@ type Param struct {
 }
 
 func (p Param) Verify() error {
-	// XXX: validate
-	return nil
+	// XXX: validate the params of the other modules
+	// the params of the VM, like the gas schedules, must be valid, as they
+	// are loaded for every tx.
+	value, _ := p.value.(string)
+	return vm.ValidateStringParam(p.key+"."+p.kind, value)
 }
 
 const (
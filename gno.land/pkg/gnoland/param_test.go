@@ -23,6 +23,17 @@ func TestParam_Parse(t *testing.T) {
 		{"invalid kind", "invalid.kind=foo", Param{}, true},
 		{"invalid int64", "invalid.int64=foobar", Param{}, true},
 		{"invalid uint64", "invalid.uint64=-42", Param{}, true},
+		{
+			"valid gas schedule", `gno.land/r/sys/params.vm.gas_schedule.1.string={"height":10}`,
+			Param{key: "gno.land/r/sys/params.vm.gas_schedule.1", kind: "string", value: `{"height":10}`}, false,
+		},
+		{"invalid gas schedule", `gno.land/r/sys/params.vm.gas_schedule.1.string={"cpu":{"OpFoo":1}}`, Param{}, true},
+		{"invalid gas schedule kind", "gno.land/r/sys/params.vm.gas_schedule.1.int64=10", Param{}, true},
+		{
+			"valid storage price", "gno.land/r/sys/params.vm.storage_price.string=10ugnot",
+			Param{key: "gno.land/r/sys/params.vm.storage_price", kind: "string", value: "10ugnot"}, false,
+		},
+		{"invalid storage price", "gno.land/r/sys/params.vm.storage_price.string=10", Param{}, true},
 	}
 
 	for _, tc := range tests {
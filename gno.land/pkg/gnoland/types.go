@@ -9,6 +9,7 @@ import (
 
 	"github.com/gnolang/gno/tm2/pkg/amino"
 	"github.com/gnolang/gno/tm2/pkg/sdk/auth"
+	"github.com/gnolang/gno/tm2/pkg/sdk/bank"
 	"github.com/gnolang/gno/tm2/pkg/std"
 )
 
@@ -27,9 +28,16 @@ func ProtoGnoAccount() std.Account {
 
 type GnoGenesisState struct {
 	Balances []Balance         `json:"balances"`
+	Vesting  []Vesting         `json:"vesting,omitempty"`
 	Txs      []TxWithMetadata  `json:"txs"`
 	Params   []Param           `json:"params"`
 	Auth     auth.GenesisState `json:"auth"`
+	Bank     bank.GenesisState `json:"bank"`
+
+	// MerkleizedVMStore keeps the VM state in its own merkleized store, part
+	// of the app hash and of the state sync snapshots. The chains created
+	// before it keep the VM state in the base store.
+	MerkleizedVMStore bool `json:"merkleized_vm_store,omitempty"`
 }
 
 type TxWithMetadata struct {
@@ -1,31 +1,26 @@
 package gnoland
 
 import (
-	"regexp"
-
 	gnovm "github.com/gnolang/gno/gnovm/stdlibs/std"
 	"github.com/gnolang/gno/tm2/pkg/bft/types"
 	"github.com/gnolang/gno/tm2/pkg/events"
 )
 
 const (
-	valRealm     = "gno.land/r/sys/validators/v2" // XXX: make it configurable from GovDAO
-	valChangesFn = "GetChanges"
-
 	validatorAddedEvent   = "ValidatorAdded"
 	validatorRemovedEvent = "ValidatorRemoved"
 )
 
-// XXX: replace with amino-based clean approach
-var valRegexp = regexp.MustCompile(`{\("([^"]*)"\s[^)]+\),\("((?:[^"]|\\")*)"\s[^)]+\),\((\d+)\s[^)]+\)}`)
-
 // validatorUpdate is a type being used for "notifying"
 // that a validator change happened on-chain. The events from `r/sys/validators`
 // do not pass data related to validator add / remove instances (who, what, how)
-type validatorUpdate struct{}
+type validatorUpdate struct {
+	pkgPath string // realm which emitted the event
+}
 
 // validatorEventFilter filters the given event to determine if it
-// is tied to a validator update
+// is tied to a validator update. The realm of the event is checked
+// against the validators realm by the EndBlocker, as it is set by a param.
 func validatorEventFilter(event events.Event) []validatorUpdate {
 	// Make sure the event is a new TX event
 	txResult, ok := event.(types.EventTx)
@@ -34,6 +29,7 @@ func validatorEventFilter(event events.Event) []validatorUpdate {
 	}
 
 	// Make sure an add / remove event happened
+	var updates []validatorUpdate
 	for _, ev := range txResult.Result.Response.Events {
 		// Make sure the event is a GnoVM event
 		gnoEv, ok := ev.(gnovm.GnoEvent)
@@ -41,21 +37,30 @@ func validatorEventFilter(event events.Event) []validatorUpdate {
 			continue
 		}
 
-		// Make sure the event is from `r/sys/validators`
-		if gnoEv.PkgPath != valRealm {
-			continue
-		}
-
 		// Make sure the event is either an add / remove
 		switch gnoEv.Type {
 		case validatorAddedEvent, validatorRemovedEvent:
 			// We don't pass data around with the events, but a single
-			// notification is enough to "trigger" a VM scrape
-			return []validatorUpdate{{}}
+			// notification per realm is enough to "trigger" a VM scrape
+			if !hasValidatorUpdate(updates, gnoEv.PkgPath) {
+				updates = append(updates, validatorUpdate{pkgPath: gnoEv.PkgPath})
+			}
 		default:
 			continue
 		}
 	}
 
-	return nil
+	return updates
+}
+
+// hasValidatorUpdate returns true if one of the updates was emitted by the
+// realm at pkgPath.
+func hasValidatorUpdate(updates []validatorUpdate, pkgPath string) bool {
+	for _, update := range updates {
+		if update.pkgPath == pkgPath {
+			return true
+		}
+	}
+
+	return false
 }
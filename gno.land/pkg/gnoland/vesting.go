@@ -0,0 +1,57 @@
+package gnoland
+
+import (
+	"errors"
+
+	bft "github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/sdk/auth"
+	"github.com/gnolang/gno/tm2/pkg/std"
+)
+
+var (
+	ErrVestingEmptyAddress     = errors.New("vesting address is empty")
+	ErrVestingAmountAndPeriods = errors.New("vesting amount and end time cannot be set with periods")
+)
+
+// Vesting is the vesting schedule of a genesis account. The account must
+// have a genesis balance covering the vested coins, which are locked until
+// they vest.
+//
+// If Periods is empty, Amount vests linearly from StartTime to EndTime (see
+// [auth.ContinuousVestingAccount]). Otherwise, the amounts of the periods
+// vest one after the other, starting at StartTime (see
+// [auth.PeriodicVestingAccount]). Times are unix timestamps in seconds.
+type Vesting struct {
+	Address   bft.Address          `json:"address"`
+	Amount    std.Coins            `json:"amount,omitempty"`
+	StartTime int64                `json:"start_time"`
+	EndTime   int64                `json:"end_time,omitempty"`
+	Periods   []auth.VestingPeriod `json:"periods,omitempty"`
+}
+
+// Verify checks that the vesting schedule is valid.
+func (v Vesting) Verify() error {
+	if v.Address.IsZero() {
+		return ErrVestingEmptyAddress
+	}
+
+	if len(v.Periods) > 0 && (len(v.Amount) > 0 || v.EndTime != 0) {
+		return ErrVestingAmountAndPeriods
+	}
+
+	// Check the schedule alone, with a balance covering the vested coins
+	acc := v.Account(&std.BaseAccount{Address: v.Address})
+	acc.SetCoins(acc.GetOriginalVesting())
+
+	return acc.Validate()
+}
+
+// Account returns a vesting account with the fields of acc, following the
+// vesting schedule.
+func (v Vesting) Account(acc std.Account) auth.VestingAccount {
+	if len(v.Periods) > 0 {
+		return auth.NewPeriodicVestingAccount(acc, v.StartTime, v.Periods)
+	}
+
+	return auth.NewContinuousVestingAccount(acc, v.Amount, v.StartTime, v.EndTime)
+}
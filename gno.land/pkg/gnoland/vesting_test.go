@@ -0,0 +1,85 @@
+package gnoland
+
+import (
+	"testing"
+
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	bft "github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/gnolang/gno/tm2/pkg/sdk/auth"
+	"github.com/gnolang/gno/tm2/pkg/std"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVesting_Verify(t *testing.T) {
+	validAddress := crypto.MustAddressFromString("g1jg8mtutu9khhfwc4nxmuhcpftf0pajdhfvsqf5")
+	amount := std.NewCoins(std.NewCoin("ugnot", 100))
+	periods := []auth.VestingPeriod{{Length: 10, Amount: amount}}
+
+	tests := []struct {
+		name      string
+		vesting   Vesting
+		expectErr bool
+	}{
+		{"empty address", Vesting{Address: bft.Address{}, Amount: amount, EndTime: 10}, true},
+		{"empty amount", Vesting{Address: validAddress, EndTime: 10}, true},
+		{"end before start", Vesting{Address: validAddress, Amount: amount, StartTime: 10, EndTime: 5}, true},
+		{"amount and periods", Vesting{Address: validAddress, Amount: amount, Periods: periods}, true},
+		{"empty period", Vesting{Address: validAddress, Periods: []auth.VestingPeriod{{Length: 10}}}, true},
+		{"valid continuous", Vesting{Address: validAddress, Amount: amount, StartTime: 5, EndTime: 10}, false},
+		{"valid periodic", Vesting{Address: validAddress, StartTime: 5, Periods: periods}, false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.vesting.Verify()
+			if tc.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestVesting_Account(t *testing.T) {
+	address := crypto.MustAddressFromString("g1jg8mtutu9khhfwc4nxmuhcpftf0pajdhfvsqf5")
+	amount := std.NewCoins(std.NewCoin("ugnot", 100))
+	base := std.NewBaseAccount(address, amount.Add(amount), nil, 3, 4)
+
+	continuous := Vesting{Address: address, Amount: amount, StartTime: 5, EndTime: 10}.Account(base)
+	require.IsType(t, &auth.ContinuousVestingAccount{}, continuous)
+	assert.Equal(t, uint64(3), continuous.GetAccountNumber())
+	assert.Equal(t, "200ugnot", continuous.GetCoins().String())
+	assert.NoError(t, continuous.Validate())
+
+	periodic := Vesting{
+		Address:   address,
+		StartTime: 5,
+		Periods:   []auth.VestingPeriod{{Length: 10, Amount: amount}},
+	}.Account(base)
+	require.IsType(t, &auth.PeriodicVestingAccount{}, periodic)
+	assert.Equal(t, int64(15), periodic.GetEndTime())
+	assert.NoError(t, periodic.Validate())
+}
+
+func TestVesting_GenesisState(t *testing.T) {
+	state := DefaultGenState()
+	state.Vesting = []Vesting{
+		{
+			Address:   crypto.MustAddressFromString("g1jg8mtutu9khhfwc4nxmuhcpftf0pajdhfvsqf5"),
+			StartTime: 5,
+			Periods: []auth.VestingPeriod{
+				{Length: 10, Amount: std.NewCoins(std.NewCoin("ugnot", 100))},
+			},
+		},
+	}
+
+	bz, err := amino.MarshalJSON(state)
+	require.NoError(t, err)
+
+	var decoded GnoGenesisState
+	require.NoError(t, amino.UnmarshalJSON(bz, &decoded))
+	assert.Equal(t, state.Vesting, decoded.Vesting)
+}
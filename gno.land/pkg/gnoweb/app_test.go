@@ -33,6 +33,8 @@ func TestRoutes(t *testing.T) {
 		{"/r/gnoland/blog$help&func=Render", ok, "Render(path)"},
 		{"/r/gnoland/blog$help&func=Render&path=foo/bar", ok, `value="foo/bar"`},
 		// {"/r/gnoland/blog$help&func=NonExisting", ok, "NonExisting not found"}, // XXX(TODO)
+		{"/r/gnoland/blog$state", ok, "block"},
+		{"/r/gnoland/blog$state&oid=0000000000000000000000000000000000000000:1", notFound, ""},
 		{"/r/demo/users:administrator", ok, "address"},
 		{"/r/demo/users", ok, "moul"},
 		{"/r/demo/users/users.gno", ok, "// State"},
@@ -18,6 +18,8 @@ type HelpData struct {
 	ChainId   string
 	Remote    string
 	PkgPath   string
+	// Balance of the realm, in display units
+	Balance string
 }
 
 func registerHelpFuncs(funcs template.FuncMap) {
@@ -0,0 +1,30 @@
+package components
+
+import (
+	"io"
+)
+
+type StateField struct {
+	Name  string // name, index or key of the value
+	Type  string
+	Value string // JSON encoding of the value
+	Ref   string // id of the object the value refers to, if any
+}
+
+type StateData struct {
+	PkgPath  string
+	ObjectID string
+	Kind     string
+	Hash     string
+	OwnerID  string
+	RefCount int
+	Fields   []StateField
+	Total    int
+	// Links to the previous and next pages of fields, if any.
+	PrevPage string
+	NextPage string
+}
+
+func RenderStateComponent(w io.Writer, data StateData) error {
+	return tmpl.ExecuteTemplate(w, "renderState", data)
+}
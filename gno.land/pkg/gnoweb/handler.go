@@ -2,14 +2,17 @@ package gnoweb
 
 import (
 	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"html/template"
 	"io"
 	"log/slog"
 	"net/http"
+	"net/url"
 	"path/filepath"
 	"slices"
+	"strconv"
 	"strings"
 	"time"
 
@@ -136,6 +139,11 @@ func (h *WebHandler) renderPackage(w io.Writer, gnourl *GnoURL) (status int, err
 		return h.renderRealmHelp(w, gnourl)
 	}
 
+	// Display realm state page?
+	if kind == KindRealm && gnourl.WebQuery.Has("state") {
+		return h.renderRealmState(w, gnourl)
+	}
+
 	// Display package source page?
 	switch {
 	case gnourl.WebQuery.Has("source"):
@@ -215,6 +223,11 @@ func (h *WebHandler) renderRealmHelp(w io.Writer, gnourl *GnoURL) (status int, e
 		}
 	}
 
+	balance, err := h.webcli.Balance(gnourl.Path)
+	if err != nil {
+		h.logger.Warn("unable to fetch realm balance", "err", err)
+	}
+
 	// Catch last name of the path
 	// XXX: we should probably add a helper within the template
 	realmName := filepath.Base(gnourl.Path)
@@ -227,6 +240,7 @@ func (h *WebHandler) renderRealmHelp(w io.Writer, gnourl *GnoURL) (status int, e
 		PkgPath:   filepath.Join(DefaultChainDomain, gnourl.Path),
 		Remote:    h.static.RemoteHelp,
 		Functions: fsigs,
+		Balance:   balance,
 	})
 	if err != nil {
 		h.logger.Error("unable to render helper", "err", err)
@@ -236,6 +250,81 @@ func (h *WebHandler) renderRealmHelp(w io.Writer, gnourl *GnoURL) (status int, e
 	return http.StatusOK, nil
 }
 
+// statePageSize is the number of values of an object displayed per page.
+const statePageSize = 50
+
+func (h *WebHandler) renderRealmState(w io.Writer, gnourl *GnoURL) (status int, err error) {
+	oid := gnourl.WebQuery.Get("oid")
+	offset, _ := strconv.Atoi(gnourl.WebQuery.Get("offset"))
+	offset = max(offset, 0)
+
+	obj, err := h.webcli.Object(gnourl.Path, oid, offset, statePageSize)
+	if err != nil {
+		if errors.Is(err, vm.InvalidPkgPathError{}) || errors.Is(err, vm.InvalidObjectIDError{}) {
+			return http.StatusNotFound, components.RenderStatusComponent(w, "not found")
+		}
+
+		h.logger.Error("unable to fetch object", "path", gnourl.Path, "oid", oid, "err", err)
+		return http.StatusInternalServerError, components.RenderStatusComponent(w, "internal error")
+	}
+
+	fields := make([]components.StateField, len(obj.Fields))
+	for i, f := range obj.Fields {
+		field := components.StateField{
+			Name:  f.Name,
+			Type:  f.T,
+			Value: string(f.V),
+		}
+		if f.Key != nil {
+			field.Name = string(f.Key.V)
+		} else if field.Name == "" {
+			field.Name = strconv.Itoa(obj.Offset + i)
+		}
+		var ref struct {
+			Ref string `json:"$ref"`
+		}
+		if json.Unmarshal(f.V, &ref) == nil {
+			field.Ref = ref.Ref
+		}
+		fields[i] = field
+	}
+
+	// pageLink returns the link to the page of fields starting from offset.
+	pageLink := func(offset int) string {
+		query := url.Values{"state": {""}, "offset": {strconv.Itoa(offset)}}
+		if oid != "" {
+			query.Set("oid", oid)
+		}
+		return GnoURL{Path: gnourl.Path, WebQuery: query}.EncodeWebPath()
+	}
+	var prevPage, nextPage string
+	if obj.Offset > 0 {
+		prevPage = pageLink(max(obj.Offset-statePageSize, 0))
+	}
+	if end := obj.Offset + len(obj.Fields); end < obj.Total {
+		nextPage = pageLink(end)
+	}
+
+	err = components.RenderStateComponent(w, components.StateData{
+		PkgPath:  gnourl.Path,
+		ObjectID: obj.ObjectID,
+		Kind:     obj.Kind,
+		Hash:     obj.Hash,
+		OwnerID:  obj.OwnerID,
+		RefCount: obj.RefCount,
+		Fields:   fields,
+		Total:    obj.Total,
+		PrevPage: prevPage,
+		NextPage: nextPage,
+	})
+	if err != nil {
+		h.logger.Error("unable to render state", "err", err)
+		return http.StatusInternalServerError, components.RenderStatusComponent(w, "internal error")
+	}
+
+	return http.StatusOK, nil
+}
+
 func (h *WebHandler) renderRealmSource(w io.Writer, gnourl *GnoURL) (status int, err error) {
 	pkgPath := gnourl.Path
 
@@ -1,17 +1,22 @@
 package gnoweb
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"log/slog"
 	"path/filepath"
+	"regexp"
 	"strings"
 
 	md "github.com/gnolang/gno/gno.land/pkg/gnoweb/markdown"
 	"github.com/gnolang/gno/gno.land/pkg/sdk/vm" // for error types
+	gno "github.com/gnolang/gno/gnovm/pkg/gnolang"
 	"github.com/gnolang/gno/tm2/pkg/amino"
 	"github.com/gnolang/gno/tm2/pkg/bft/rpc/client"
+	"github.com/gnolang/gno/tm2/pkg/sdk/bank"
+	"github.com/gnolang/gno/tm2/pkg/std"
 	"github.com/yuin/goldmark"
 	"github.com/yuin/goldmark/parser"
 	"github.com/yuin/goldmark/text"
@@ -78,6 +83,64 @@ func (s *WebClient) Sources(path string) ([]string, error) {
 	return files, nil
 }
 
+// Balance returns the balance of the realm at pkgPath, with the amounts
+// formatted in display units when their denom has metadata.
+func (s *WebClient) Balance(pkgPath string) (string, error) {
+	const qpath = "bank/balances/"
+
+	addr := gno.DerivePkgAddr(fmt.Sprintf("gno.land/%s", strings.Trim(pkgPath, "/")))
+	res, err := s.query(qpath+addr.String(), nil)
+	if err != nil {
+		return "", err
+	}
+
+	var coins std.Coins
+	if err := amino.UnmarshalJSON(res, &coins); err != nil {
+		return "", fmt.Errorf("unable to unmarshal balance: %w", err)
+	}
+
+	// The metadata is only used for display; ignore errors.
+	var metadata []bank.DenomMetadata
+	if res, err := s.query("bank/denoms", nil); err == nil {
+		if err := amino.UnmarshalJSON(res, &metadata); err != nil {
+			s.logger.Warn("unable to unmarshal denom metadata", "err", err)
+		}
+	}
+
+	return bank.FormatCoins(coins, metadata), nil
+}
+
+// reObjectID matches an object ID, like "<pkg id>:<new time>".
+var reObjectID = regexp.MustCompile(`^[0-9a-f]{40}:[0-9]+$`)
+
+// Object returns the object oid of the realm at pkgPath, or its package block
+// if oid is empty, with at most limit of its values starting from offset.
+func (s *WebClient) Object(pkgPath, oid string, offset, limit int) (*gno.JSONObject, error) {
+	const qpath = "vm/qobject"
+
+	// the oid is sent as is in the query data.
+	if oid != "" && !reObjectID.MatchString(oid) {
+		return nil, fmt.Errorf("%w: %s", vm.InvalidObjectIDError{}, oid)
+	}
+
+	data := oid
+	if data == "" {
+		data = fmt.Sprintf("gno.land/%s", strings.Trim(pkgPath, "/"))
+	}
+	data += fmt.Sprintf("?offset=%d&limit=%d", offset, limit)
+	res, err := s.query(qpath, []byte(data))
+	if err != nil {
+		return nil, err
+	}
+
+	var obj gno.JSONObject
+	if err := json.Unmarshal(res, &obj); err != nil {
+		return nil, fmt.Errorf("unable to unmarshal object: %w", err)
+	}
+
+	return &obj, nil
+}
+
 type Metadata struct {
 	*md.Toc
 }
@@ -0,0 +1,25 @@
+package gnoweb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yuin/goldmark"
+
+	"github.com/gnolang/gno/gno.land/pkg/sdk/vm"
+	"github.com/gnolang/gno/tm2/pkg/log"
+)
+
+func TestWebClient_ObjectInvalidOID(t *testing.T) {
+	// The oid is checked before querying the node.
+	cl := NewWebClient(log.NewNoopLogger(), nil, goldmark.New())
+	for _, oid := range []string{
+		"foo",
+		"0000000000000000000000000000000000000000:1?offset=0&limit=1000000",
+		"0000000000000000000000000000000000000000:-1",
+		"gno.land/r/demo/foo",
+	} {
+		_, err := cl.Object("/r/demo/foo", oid, 0, statePageSize)
+		assert.ErrorIs(t, err, vm.InvalidObjectIDError{}, oid)
+	}
+}
@@ -30,7 +30,13 @@
 //   - Must be run before `gnoland start`.
 //   - Creates a new user in the default keybase directory from a given seed. ( Optionally, account and index can be provided )
 //
-// 5. `loadpkg`:
+// 5. `addvesting`:
+//   - Must be run before `gnoland start`.
+//   - Makes the genesis account of a user added with `adduser` or `adduserfrom` a
+//     continuous vesting account: `addvesting <user> <amount> <start_time> <end_time>`,
+//     with unix timestamps in seconds.
+//
+// 6. `loadpkg`:
 //   - Must be run before `gnoland start`.
 //   - Loads a specific package from the 'examples' directory or from the working ($WORK) directory.
 //   - Can be used to load a single package or all packages within a directory.
@@ -48,7 +54,7 @@
 //   - It's important to note that the load order is significant when using multiple `loadpkg`
 //     command; packages should be loaded in the order they are dependent upon.
 //
-// 6. `patchpkg`:
+// 7. `patchpkg`:
 //   - Patches any loaded files by package by replacing all occurrences of the first argument with the second.
 //   - This is mostly used to replace hardcoded addresses from loaded packages.
 //   - NOTE: this command may only be temporary, as it's not best approach to
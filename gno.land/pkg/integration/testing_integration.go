@@ -284,7 +284,9 @@ func setupGnolandTestScript(t *testing.T, txtarDir string) testscript.Params {
 				io.SetErr(commands.WriteNopCloser(ts.Stderr()))
 				cmd := keyscli.NewRootCmd(io, client.DefaultBaseOptions)
 
-				io.SetIn(strings.NewReader("\n")) // Inject empty password to stdin.
+				// Inject empty passwords to stdin, as the key rotation also asks
+				// the password of the new key.
+				io.SetIn(strings.NewReader("\n\n"))
 				defaultArgs := []string{
 					"-home", gnoHomeDir,
 					"-insecure-password-stdin=true", // There no use to not have this param by default.
@@ -391,6 +393,46 @@ func setupGnolandTestScript(t *testing.T, txtarDir string) testscript.Params {
 
 				fmt.Fprintf(ts.Stdout(), "Added %s(%s) to genesis", args[0], balance.Address)
 			},
+			// addvesting command must be executed before starting the node; it errors out otherwise.
+			"addvesting": func(ts *testscript.TestScript, neg bool, args []string) {
+				if nodeIsRunning(nodes, getNodeSID(ts)) {
+					tsValidateError(ts, "addvesting", neg, errors.New("addvesting must be used before starting node"))
+					return
+				}
+
+				if len(args) != 4 {
+					ts.Fatalf("syntax: addvesting <user> <amount> <start_time> <end_time>")
+				}
+
+				addr, err := crypto.AddressFromBech32(ts.Getenv("USER_ADDR_" + args[0]))
+				if err != nil {
+					ts.Fatalf("unknown user %s, it must be added first: %s", args[0], err)
+				}
+
+				amount, err := std.ParseCoins(args[1])
+				if err != nil {
+					ts.Fatalf("invalid vesting amount %s: %s", args[1], err)
+				}
+
+				startTime, err := strconv.ParseInt(args[2], 10, 64)
+				if err != nil {
+					ts.Fatalf("invalid vesting start time %s", args[2])
+				}
+
+				endTime, err := strconv.ParseInt(args[3], 10, 64)
+				if err != nil {
+					ts.Fatalf("invalid vesting end time %s", args[3])
+				}
+
+				// Add the vesting schedule to genesis, on top of the user balance
+				genesis := ts.Value(envKeyGenesis).(*gnoland.GnoGenesisState)
+				genesis.Vesting = append(genesis.Vesting, gnoland.Vesting{
+					Address:   addr,
+					Amount:    amount,
+					StartTime: startTime,
+					EndTime:   endTime,
+				})
+			},
 			// `patchpkg` Patch any loaded files by packages by replacing all occurrences of the
 			// first argument with the second.
 			// This is mostly use to replace hardcoded address inside txtar file.
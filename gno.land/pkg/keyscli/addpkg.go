@@ -92,7 +92,10 @@ func execMakeAddPkg(cfg *MakeAddPkgCfg, args []string, io commands.IO) error {
 	if err != nil {
 		return err
 	}
-	creator := info.GetAddress()
+	creator, err := cfg.RootCfg.AccountAddress(info)
+	if err != nil {
+		return err
+	}
 	// info.GetPubKey()
 
 	// parse deposit.
@@ -108,8 +111,7 @@ func execMakeAddPkg(cfg *MakeAddPkgCfg, args []string, io commands.IO) error {
 	}
 
 	// parse gas wanted & fee.
-	gaswanted := cfg.RootCfg.GasWanted
-	gasfee, err := std.ParseCoin(cfg.RootCfg.GasFee)
+	fee, err := cfg.RootCfg.ParseFee()
 	if err != nil {
 		panic(err)
 	}
@@ -120,10 +122,11 @@ func execMakeAddPkg(cfg *MakeAddPkgCfg, args []string, io commands.IO) error {
 		Deposit: deposit,
 	}
 	tx := std.Tx{
-		Msgs:       []std.Msg{msg},
-		Fee:        std.NewFee(gaswanted, gasfee),
-		Signatures: nil,
-		Memo:       cfg.RootCfg.Memo,
+		Msgs:          []std.Msg{msg},
+		Fee:           fee,
+		Signatures:    nil,
+		Memo:          cfg.RootCfg.Memo,
+		TimeoutHeight: cfg.RootCfg.TimeoutHeight,
 	}
 
 	if cfg.RootCfg.Broadcast {
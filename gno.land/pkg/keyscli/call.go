@@ -20,6 +20,8 @@ type MakeCallCfg struct {
 	PkgPath  string
 	FuncName string
 	Args     commands.StringArr
+
+	JSONResults bool
 }
 
 func NewMakeCallCmd(rootCfg *client.MakeTxCfg, io commands.IO) *commands.Command {
@@ -67,6 +69,13 @@ func (c *MakeCallCfg) RegisterFlags(fs *flag.FlagSet) {
 		"args",
 		"arguments to contract",
 	)
+
+	fs.BoolVar(
+		&c.JSONResults,
+		"json-results",
+		false,
+		"return the results of the call JSON-encoded",
+	)
 }
 
 func execMakeCall(cfg *MakeCallCfg, args []string, io commands.IO) error {
@@ -99,7 +108,10 @@ func execMakeCall(cfg *MakeCallCfg, args []string, io commands.IO) error {
 	if err != nil {
 		return err
 	}
-	caller := info.GetAddress()
+	caller, err := cfg.RootCfg.AccountAddress(info)
+	if err != nil {
+		return err
+	}
 	// info.GetPubKey()
 
 	// Parse send amount.
@@ -109,10 +121,9 @@ func execMakeCall(cfg *MakeCallCfg, args []string, io commands.IO) error {
 	}
 
 	// parse gas wanted & fee.
-	gaswanted := cfg.RootCfg.GasWanted
-	gasfee, err := std.ParseCoin(cfg.RootCfg.GasFee)
+	fee, err := cfg.RootCfg.ParseFee()
 	if err != nil {
-		return errors.Wrap(err, "parsing gas fee coin")
+		return err
 	}
 
 	// construct msg & tx and marshal.
@@ -122,12 +133,15 @@ func execMakeCall(cfg *MakeCallCfg, args []string, io commands.IO) error {
 		PkgPath: cfg.PkgPath,
 		Func:    fnc,
 		Args:    cfg.Args,
+
+		JSONResults: cfg.JSONResults,
 	}
 	tx := std.Tx{
-		Msgs:       []std.Msg{msg},
-		Fee:        std.NewFee(gaswanted, gasfee),
-		Signatures: nil,
-		Memo:       cfg.RootCfg.Memo,
+		Msgs:          []std.Msg{msg},
+		Fee:           fee,
+		Signatures:    nil,
+		Memo:          cfg.RootCfg.Memo,
+		TimeoutHeight: cfg.RootCfg.TimeoutHeight,
 	}
 
 	if cfg.RootCfg.Broadcast {
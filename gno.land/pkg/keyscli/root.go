@@ -2,6 +2,7 @@
 package keyscli
 
 import (
+	_ "github.com/gnolang/gno/gno.land/pkg/gnoland" // registers the gno.land account type
 	"github.com/gnolang/gno/tm2/pkg/commands"
 	"github.com/gnolang/gno/tm2/pkg/crypto/keys/client"
 
@@ -65,13 +65,15 @@ func execMakeRun(cfg *MakeRunCfg, args []string, cmdio commands.IO) error {
 	if err != nil {
 		return err
 	}
-	caller := info.GetAddress()
+	caller, err := cfg.RootCfg.AccountAddress(info)
+	if err != nil {
+		return err
+	}
 
 	// parse gas wanted & fee.
-	gaswanted := cfg.RootCfg.GasWanted
-	gasfee, err := std.ParseCoin(cfg.RootCfg.GasFee)
+	fee, err := cfg.RootCfg.ParseFee()
 	if err != nil {
-		return errors.Wrap(err, "parsing gas fee coin")
+		return err
 	}
 
 	memPkg := &gnovm.MemPackage{}
@@ -120,10 +122,11 @@ func execMakeRun(cfg *MakeRunCfg, args []string, cmdio commands.IO) error {
 		Package: memPkg,
 	}
 	tx := std.Tx{
-		Msgs:       []std.Msg{msg},
-		Fee:        std.NewFee(gaswanted, gasfee),
-		Signatures: nil,
-		Memo:       cfg.RootCfg.Memo,
+		Msgs:          []std.Msg{msg},
+		Fee:           fee,
+		Signatures:    nil,
+		Memo:          cfg.RootCfg.Memo,
+		TimeoutHeight: cfg.RootCfg.TimeoutHeight,
 	}
 
 	if cfg.RootCfg.Broadcast {
@@ -0,0 +1,126 @@
+package keyscli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/gnolang/gno/gno.land/pkg/sdk/vm"
+	gno "github.com/gnolang/gno/gnovm/pkg/gnolang"
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	"github.com/gnolang/gno/tm2/pkg/commands"
+	"github.com/gnolang/gno/tm2/pkg/crypto/keys"
+	"github.com/gnolang/gno/tm2/pkg/crypto/keys/client"
+	"github.com/gnolang/gno/tm2/pkg/errors"
+	"github.com/gnolang/gno/tm2/pkg/std"
+)
+
+type MakeUpgradePkgCfg struct {
+	RootCfg *client.MakeTxCfg
+
+	PkgPath string
+	PkgDir  string
+}
+
+func NewMakeUpgradePkgCmd(rootCfg *client.MakeTxCfg, io commands.IO) *commands.Command {
+	cfg := &MakeUpgradePkgCfg{
+		RootCfg: rootCfg,
+	}
+
+	return commands.NewCommand(
+		commands.Metadata{
+			Name:       "upgradepkg",
+			ShortUsage: "upgradepkg [flags] <key-name>",
+			ShortHelp:  "upgrades an existing realm",
+		},
+		cfg,
+		func(_ context.Context, args []string) error {
+			return execMakeUpgradePkg(cfg, args, io)
+		},
+	)
+}
+
+func (c *MakeUpgradePkgCfg) RegisterFlags(fs *flag.FlagSet) {
+	fs.StringVar(
+		&c.PkgPath,
+		"pkgpath",
+		"",
+		"package path (required)",
+	)
+
+	fs.StringVar(
+		&c.PkgDir,
+		"pkgdir",
+		"",
+		"path to package files (required)",
+	)
+}
+
+func execMakeUpgradePkg(cfg *MakeUpgradePkgCfg, args []string, io commands.IO) error {
+	if cfg.PkgPath == "" {
+		return errors.New("pkgpath not specified")
+	}
+	if cfg.PkgDir == "" {
+		return errors.New("pkgdir not specified")
+	}
+	if cfg.RootCfg.GasWanted == 0 {
+		return errors.New("gas-wanted not specified")
+	}
+	if cfg.RootCfg.GasFee == "" {
+		return errors.New("gas-fee not specified")
+	}
+
+	if len(args) != 1 {
+		return flag.ErrHelp
+	}
+
+	// read account pubkey.
+	nameOrBech32 := args[0]
+	kb, err := keys.NewKeyBaseFromDir(cfg.RootCfg.RootCfg.Home)
+	if err != nil {
+		return err
+	}
+	info, err := kb.GetByNameOrAddress(nameOrBech32)
+	if err != nil {
+		return err
+	}
+	creator, err := cfg.RootCfg.AccountAddress(info)
+	if err != nil {
+		return err
+	}
+	// info.GetPubKey()
+
+	// open files in directory as MemPackage.
+	memPkg := gno.MustReadMemPackage(cfg.PkgDir, cfg.PkgPath)
+	if memPkg.IsEmpty() {
+		panic(fmt.Sprintf("found an empty package %q", cfg.PkgPath))
+	}
+
+	// parse gas wanted & fee.
+	fee, err := cfg.RootCfg.ParseFee()
+	if err != nil {
+		panic(err)
+	}
+	// construct msg & tx and marshal.
+	msg := vm.MsgUpgradePackage{
+		Creator: creator,
+		Package: memPkg,
+	}
+	tx := std.Tx{
+		Msgs:          []std.Msg{msg},
+		Fee:           fee,
+		Signatures:    nil,
+		Memo:          cfg.RootCfg.Memo,
+		TimeoutHeight: cfg.RootCfg.TimeoutHeight,
+	}
+
+	if cfg.RootCfg.Broadcast {
+		err := client.ExecSignAndBroadcast(cfg.RootCfg, args, tx, io)
+		if err != nil {
+			return err
+		}
+	} else {
+		io.Println(string(amino.MustMarshalJSON(tx)))
+	}
+	return nil
+}
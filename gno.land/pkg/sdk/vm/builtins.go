@@ -1,9 +1,18 @@
 package vm
 
 import (
+	"encoding/json"
+	"strings"
+
+	gno "github.com/gnolang/gno/gnovm/pkg/gnolang"
+	"github.com/gnolang/gno/tm2/pkg/amino"
 	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/gnolang/gno/tm2/pkg/overflow"
 	"github.com/gnolang/gno/tm2/pkg/sdk"
+	"github.com/gnolang/gno/tm2/pkg/sdk/bank"
+	"github.com/gnolang/gno/tm2/pkg/sdk/params"
 	"github.com/gnolang/gno/tm2/pkg/std"
+	"github.com/gnolang/gno/tm2/pkg/store/types"
 )
 
 // ----------------------------------------
@@ -56,6 +65,19 @@ func (bnk *SDKBanker) RemoveCoin(b32addr crypto.Bech32Address, denom string, amo
 	}
 }
 
+func (bnk *SDKBanker) SetDenomMetadata(denom, display string, exponent uint32, description, issuer string) {
+	err := bnk.vmk.bank.SetDenomMetadata(bnk.ctx, bank.DenomMetadata{
+		Base:        denom,
+		Display:     display,
+		Exponent:    exponent,
+		Description: description,
+		Issuer:      issuer,
+	})
+	if err != nil {
+		panic(err)
+	}
+}
+
 // ----------------------------------------
 // SDKParams
 
@@ -71,10 +93,79 @@ func NewSDKParams(vmk *VMKeeper, ctx sdk.Context) *SDKParams {
 	}
 }
 
-func (prm *SDKParams) SetString(key, value string)      { prm.vmk.prmk.SetString(prm.ctx, key, value) }
+// SetString sets the string param key. The params of the VM, like the gas
+// schedules, must be valid.
+func (prm *SDKParams) SetString(key, value string) {
+	if err := ValidateStringParam(key, value); err != nil {
+		panic(err)
+	}
+	prm.vmk.prmk.SetString(prm.ctx, key, value)
+}
 func (prm *SDKParams) SetBool(key string, value bool)   { prm.vmk.prmk.SetBool(prm.ctx, key, value) }
 func (prm *SDKParams) SetInt64(key string, value int64) { prm.vmk.prmk.SetInt64(prm.ctx, key, value) }
 func (prm *SDKParams) SetUint64(key string, value uint64) {
 	prm.vmk.prmk.SetUint64(prm.ctx, key, value)
 }
 func (prm *SDKParams) SetBytes(key string, value []byte) { prm.vmk.prmk.SetBytes(prm.ctx, key, value) }
+
+func (prm *SDKParams) GetString(key string, ptr *string) bool { return prm.get(key, ptr) }
+func (prm *SDKParams) GetBool(key string, ptr *bool) bool     { return prm.get(key, ptr) }
+func (prm *SDKParams) GetInt64(key string, ptr *int64) bool   { return prm.get(key, ptr) }
+func (prm *SDKParams) GetUint64(key string, ptr *uint64) bool { return prm.get(key, ptr) }
+func (prm *SDKParams) GetBytes(key string, ptr *[]byte) bool  { return prm.get(key, ptr) }
+
+// get unmarshals the param key into ptr, and returns false if it is not set,
+// or if its value is not of the type of ptr. The read is charged like a read
+// of the params store, at the costs of the gas schedule of the transaction.
+func (prm *SDKParams) get(key string, ptr any) bool {
+	gasMeter := prm.ctx.GasMeter()
+	gasConfig := prm.vmk.getGasCosts(prm.ctx).store
+	gasMeter.ConsumeGas(gasConfig.GasGetParamFlat, gno.GasGetParamFlatDesc)
+
+	// the params of a module are stored together, and are all read to get
+	// one of their fields.
+	module, field, isModule := moduleParamKey(key)
+	if isModule {
+		key = string(params.ValueStoreKey(module))
+	}
+	bz := prm.vmk.prmk.GetRaw(prm.ctx, key)
+	gasMeter.ConsumeGas(overflow.Mul64p(gasConfig.GasGetParam, types.Gas(len(bz))), gno.GasGetParamDesc)
+	if isModule {
+		bz = moduleParamField(bz, field)
+	}
+
+	if bz == nil {
+		return false
+	}
+	if err := amino.UnmarshalJSON(bz, ptr); err != nil {
+		return false
+	}
+	return true
+}
+
+// moduleParamField returns the JSON value of the field of the params of a
+// module, or nil if it is not set.
+func moduleParamField(bz []byte, field string) []byte {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(bz, &fields); err != nil {
+		return nil
+	}
+	return fields[field]
+}
+
+// moduleParamKey returns the module name and the field of a param key like
+// "auth.max_memo_bytes.int64". The keys of realm params contain the realm
+// path, like "gno.land/r/foo.key.string".
+func moduleParamKey(key string) (module, field string, ok bool) {
+	if strings.Contains(key, "/") {
+		return "", "", false
+	}
+	module, field, ok = strings.Cut(key, ".")
+	if !ok {
+		return "", "", false
+	}
+	if i := strings.LastIndex(field, "."); i >= 0 {
+		field = field[:i]
+	}
+	return module, field, true
+}
@@ -49,7 +49,7 @@ func _setupTestEnv(cacheStdlibs bool) testEnv {
 	ctx := sdk.NewContext(sdk.RunTxModeDeliver, ms, &bft.Header{ChainID: "test-chain-id"}, log.NewNoopLogger())
 	prmk := paramsm.NewParamsKeeper(iavlCapKey, "params")
 	acck := authm.NewAccountKeeper(iavlCapKey, prmk, std.ProtoBaseAccount)
-	bank := bankm.NewBankKeeper(acck)
+	bank := bankm.NewBankKeeper(iavlCapKey, acck)
 
 	vmk := NewVMKeeper(baseCapKey, iavlCapKey, acck, bank, prmk)
 
@@ -63,6 +63,9 @@ func _setupTestEnv(cacheStdlibs bool) testEnv {
 		vmk.LoadStdlib(stdlibCtx, stdlibsDir)
 	}
 	vmk.CommitGnoTransactionStore(stdlibCtx)
+	// Index the package imports, like new chains in their first block.
+	prmk.SetInt64(stdlibCtx, IndexPackageImportsHeightParamPath, 1)
+	vmk.IndexPackageImports(stdlibCtx.WithBlockHeader(&bft.Header{ChainID: "test-chain-id", Height: 1}))
 	mcw.MultiWrite()
 	vmh := NewHandler(vmk)
 
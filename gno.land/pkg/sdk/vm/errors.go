@@ -20,6 +20,8 @@ type (
 	InvalidStmtError      struct{ abciError }
 	InvalidExprError      struct{ abciError }
 	UnauthorizedUserError struct{ abciError }
+	InvalidUpgradeError   struct{ abciError }
+	InvalidObjectIDError  struct{ abciError }
 	TypeCheckError        struct {
 		abciError
 		Errors []string `json:"errors"`
@@ -31,6 +33,8 @@ func (e PkgExistError) Error() string         { return "package already exists"
 func (e InvalidStmtError) Error() string      { return "invalid statement" }
 func (e InvalidExprError) Error() string      { return "invalid expression" }
 func (e UnauthorizedUserError) Error() string { return "unauthorized user" }
+func (e InvalidUpgradeError) Error() string   { return "invalid package upgrade" }
+func (e InvalidObjectIDError) Error() string  { return "invalid object id" }
 func (e TypeCheckError) Error() string {
 	var bld strings.Builder
 	bld.WriteString("invalid gno package; type check errors:\n")
@@ -46,6 +50,14 @@ func ErrUnauthorizedUser(msg string) error {
 	return errors.Wrap(UnauthorizedUserError{}, msg)
 }
 
+func ErrInvalidUpgrade(msg string) error {
+	return errors.Wrap(InvalidUpgradeError{}, msg)
+}
+
+func ErrInvalidObjectID(msg string) error {
+	return errors.Wrap(InvalidObjectIDError{}, msg)
+}
+
 func ErrInvalidPkgPath(msg string) error {
 	return errors.Wrap(InvalidPkgPathError{}, msg)
 }
@@ -0,0 +1,165 @@
+package vm
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	gno "github.com/gnolang/gno/gnovm/pkg/gnolang"
+	"github.com/gnolang/gno/tm2/pkg/sdk"
+	"github.com/gnolang/gno/tm2/pkg/store"
+)
+
+// GasScheduleParamPrefix is the prefix of the gas schedule params. The gas
+// schedule of version N is set as JSON in the param "<prefix>N.string", like
+// "gno.land/r/sys/params.vm.gas_schedule.1.string".
+const GasScheduleParamPrefix = "gno.land/r/sys/params.vm.gas_schedule."
+
+// GasSchedule is a version of the gas costs of the VM, set in the params,
+// which replaces the default costs from its activation height.
+type GasSchedule struct {
+	// Height is the block height from which the schedule is active.
+	Height int64 `json:"height"`
+	gno.GasSchedule
+}
+
+// gasCosts are the costs of a gas schedule.
+type gasCosts struct {
+	height int64
+	cpu    *gno.CPUCosts
+	store  gno.GasConfig
+}
+
+var defaultGasCosts = &gasCosts{
+	cpu:   gno.DefaultCPUCosts(),
+	store: gno.DefaultGasConfig(),
+}
+
+// maxGasCostsCacheSize is the maximum number of gas schedules cached by a
+// keeper; the cache is cleared when it is full.
+const maxGasCostsCacheSize = 64
+
+// gasCostsCache caches the costs of the gas schedules, by their JSON, so they
+// are not parsed for every transaction.
+type gasCostsCache struct {
+	mu    sync.Mutex
+	costs map[string]*gasCosts
+}
+
+// get returns the costs of the gas schedule in JSON, parsing it if it's not
+// cached yet.
+func (c *gasCostsCache) get(raw string) (*gasCosts, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if gc, ok := c.costs[raw]; ok {
+		return gc, nil
+	}
+	gc, err := parseGasSchedule(raw)
+	if err != nil {
+		return nil, err
+	}
+	if c.costs == nil || len(c.costs) >= maxGasCostsCacheSize {
+		c.costs = make(map[string]*gasCosts)
+	}
+	c.costs[raw] = gc
+	return gc, nil
+}
+
+// parseGasSchedule returns the costs of the gas schedule in JSON.
+func parseGasSchedule(raw string) (*gasCosts, error) {
+	var gs GasSchedule
+	dec := json.NewDecoder(strings.NewReader(raw))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&gs); err != nil {
+		return nil, err
+	}
+	if gs.Height < 0 {
+		return nil, fmt.Errorf("invalid activation height %d", gs.Height)
+	}
+	cpu, storeCosts, err := gs.Costs()
+	if err != nil {
+		return nil, err
+	}
+	return &gasCosts{height: gs.Height, cpu: cpu, store: storeCosts}, nil
+}
+
+// gasScheduleVersion returns the version of the gas schedule param key.
+func gasScheduleVersion(key string) (int64, bool) {
+	s, ok := strings.CutPrefix(key, GasScheduleParamPrefix)
+	if !ok {
+		return 0, false
+	}
+	s, ok = strings.CutSuffix(s, ".string")
+	if !ok {
+		return 0, false
+	}
+	version, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || version <= 0 {
+		return 0, false
+	}
+	return version, true
+}
+
+// ValidateGasScheduleParam returns an error if the gas schedule param key is
+// not set to a valid gas schedule.
+func ValidateGasScheduleParam(key, value string) error {
+	if _, ok := gasScheduleVersion(key); !ok {
+		return errors.New("gas schedule params must be like " + GasScheduleParamPrefix + "<version>.string")
+	}
+	if _, err := parseGasSchedule(value); err != nil {
+		return fmt.Errorf("invalid gas schedule: %w", err)
+	}
+	return nil
+}
+
+type gasCostsContextKeyType struct{}
+
+var gasCostsContextKey gasCostsContextKeyType
+
+// withGasCosts returns ctx with the costs of the active gas schedule, which
+// are then used for the whole transaction.
+func (vm *VMKeeper) withGasCosts(ctx sdk.Context) sdk.Context {
+	return ctx.WithValue(gasCostsContextKey, vm.loadGasCosts(ctx))
+}
+
+// getGasCosts returns the costs of the active gas schedule of the
+// transaction, or loads them if ctx has none, like in queries.
+func (vm *VMKeeper) getGasCosts(ctx sdk.Context) *gasCosts {
+	if gc, ok := ctx.Value(gasCostsContextKey).(*gasCosts); ok {
+		return gc
+	}
+	return vm.loadGasCosts(ctx)
+}
+
+// loadGasCosts returns the costs of the active gas schedule, the schedule of
+// the latest version activated at the block height, or the default costs if
+// no schedule is. Invalid schedules, which can only be stored by bypassing
+// the validation of the params, are ignored.
+func (vm *VMKeeper) loadGasCosts(ctx sdk.Context) *gasCosts {
+	// the schedule is loaded for every transaction, at no cost.
+	ctx = ctx.WithGasMeter(store.NewInfiniteGasMeter())
+
+	active, activeVersion := defaultGasCosts, int64(0)
+	for _, key := range vm.prmk.ListKeys(ctx, GasScheduleParamPrefix) {
+		version, ok := gasScheduleVersion(key)
+		if !ok || version < activeVersion {
+			continue
+		}
+		var raw string
+		vm.prmk.GetString(ctx, key, &raw)
+		gc, err := vm.gasCosts.get(raw)
+		if err != nil {
+			ctx.Logger().Error("ignoring invalid gas schedule", "key", key, "err", err)
+			continue
+		}
+		if gc.height > ctx.BlockHeight() {
+			continue
+		}
+		active, activeVersion = gc, version
+	}
+	return active
+}
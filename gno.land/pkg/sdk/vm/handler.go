@@ -2,6 +2,8 @@ package vm
 
 import (
 	"fmt"
+	"net/url"
+	"strconv"
 	"strings"
 
 	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
@@ -24,6 +26,8 @@ func (vh vmHandler) Process(ctx sdk.Context, msg std.Msg) sdk.Result {
 	switch msg := msg.(type) {
 	case MsgAddPackage:
 		return vh.handleMsgAddPackage(ctx, msg)
+	case MsgUpgradePackage:
+		return vh.handleMsgUpgradePackage(ctx, msg)
 	case MsgCall:
 		return vh.handleMsgCall(ctx, msg)
 	case MsgRun:
@@ -43,6 +47,15 @@ func (vh vmHandler) handleMsgAddPackage(ctx sdk.Context, msg MsgAddPackage) sdk.
 	return sdk.Result{}
 }
 
+// Handle MsgUpgradePackage.
+func (vh vmHandler) handleMsgUpgradePackage(ctx sdk.Context, msg MsgUpgradePackage) sdk.Result {
+	err := vh.vm.UpgradePackage(ctx, msg)
+	if err != nil {
+		return abciResult(err)
+	}
+	return sdk.Result{}
+}
+
 // Handle MsgCall.
 func (vh vmHandler) handleMsgCall(ctx sdk.Context, msg MsgCall) (res sdk.Result) {
 	resstr, err := vh.vm.Call(ctx, msg)
@@ -68,12 +81,15 @@ func (vh vmHandler) handleMsgRun(ctx sdk.Context, msg MsgRun) (res sdk.Result) {
 
 // query paths
 const (
-	QueryPackage = "package"
-	QueryStore   = "store"
-	QueryRender  = "qrender"
-	QueryFuncs   = "qfuncs"
-	QueryEval    = "qeval"
-	QueryFile    = "qfile"
+	QueryPackage  = "package"
+	QueryStore    = "store"
+	QueryRender   = "qrender"
+	QueryFuncs    = "qfuncs"
+	QueryEval     = "qeval"
+	QueryEvalJSON = "qeval_json"
+	QueryFile     = "qfile"
+	QueryStorage  = "qstorage"
+	QueryObject   = "qobject"
 )
 
 func (vh vmHandler) Query(ctx sdk.Context, req abci.RequestQuery) abci.ResponseQuery {
@@ -93,8 +109,14 @@ func (vh vmHandler) Query(ctx sdk.Context, req abci.RequestQuery) abci.ResponseQ
 		res = vh.queryFuncs(ctx, req)
 	case QueryEval:
 		res = vh.queryEval(ctx, req)
+	case QueryEvalJSON:
+		res = vh.queryEvalJSON(ctx, req)
 	case QueryFile:
 		res = vh.queryFile(ctx, req)
+	case QueryStorage:
+		res = vh.queryStorage(ctx, req)
+	case QueryObject:
+		res = vh.queryObject(ctx, req)
 	default:
 		return sdk.ABCIResponseQueryFromError(
 			std.ErrUnknownRequest(fmt.Sprintf(
@@ -160,6 +182,19 @@ func (vh vmHandler) queryEval(ctx sdk.Context, req abci.RequestQuery) (res abci.
 	return
 }
 
+// queryEvalJSON evaluates any expression in readonly mode and returns the
+// results as a JSON array of {"T":...,"V":...} objects.
+func (vh vmHandler) queryEvalJSON(ctx sdk.Context, req abci.RequestQuery) (res abci.ResponseQuery) {
+	pkgPath, expr := parseQueryEvalData(string(req.Data))
+	result, err := vh.vm.QueryEvalJSON(ctx, pkgPath, expr)
+	if err != nil {
+		res = sdk.ABCIResponseQueryFromError(err)
+		return
+	}
+	res.Data = []byte(result)
+	return
+}
+
 // parseQueryEval parses the input string of vm/qeval. It takes the first dot
 // after the first slash (if any) to separe the pkgPath and the expr.
 // For instance, in gno.land/r/realm.MyFunction(), gno.land/r/realm is the
@@ -197,6 +232,56 @@ func (vh vmHandler) queryFile(ctx sdk.Context, req abci.RequestQuery) (res abci.
 	return
 }
 
+// queryStorage returns the storage used by a realm, and its storage deposit.
+func (vh vmHandler) queryStorage(ctx sdk.Context, req abci.RequestQuery) (res abci.ResponseQuery) {
+	pkgPath := string(req.Data)
+	result, err := vh.vm.QueryStorage(ctx, pkgPath)
+	if err != nil {
+		res = sdk.ABCIResponseQueryFromError(err)
+		return
+	}
+	res.Data = []byte(result)
+	return
+}
+
+// queryObject returns the JSON description of an object of the store,
+// with the data being an object id or a package path, optionally followed
+// by pagination parameters, like "gno.land/r/demo/boards?offset=100&limit=50".
+func (vh vmHandler) queryObject(ctx sdk.Context, req abci.RequestQuery) (res abci.ResponseQuery) {
+	id, offset, limit, err := parseQueryObjectData(string(req.Data))
+	if err != nil {
+		res = sdk.ABCIResponseQueryFromError(err)
+		return
+	}
+	result, err := vh.vm.QueryObject(ctx, id, offset, limit)
+	if err != nil {
+		res = sdk.ABCIResponseQueryFromError(err)
+		return
+	}
+	res.Data = []byte(result)
+	return
+}
+
+// parseQueryObjectData parses the input string of vm/qobject.
+func parseQueryObjectData(data string) (id string, offset, limit int, err error) {
+	id, rawQuery, _ := strings.Cut(data, "?")
+	query, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return "", 0, 0, err
+	}
+	if s := query.Get("offset"); s != "" {
+		if offset, err = strconv.Atoi(s); err != nil {
+			return "", 0, 0, fmt.Errorf("invalid offset %q", s)
+		}
+	}
+	if s := query.Get("limit"); s != "" {
+		if limit, err = strconv.Atoi(s); err != nil {
+			return "", 0, 0, fmt.Errorf("invalid limit %q", s)
+		}
+	}
+	return id, offset, limit, nil
+}
+
 // ----------------------------------------
 // misc
 
@@ -1,14 +1,18 @@
 package vm
 
 import (
+	"encoding/json"
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/gnolang/gno/gnovm"
+	gno "github.com/gnolang/gno/gnovm/pkg/gnolang"
 	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
 	"github.com/gnolang/gno/tm2/pkg/crypto"
 	"github.com/gnolang/gno/tm2/pkg/std"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func Test_parseQueryEvalData(t *testing.T) {
@@ -177,6 +181,192 @@ func pvEcho(msg string) string { return "pvecho:"+msg }
 	}
 }
 
+func TestVmHandlerQuery_EvalJSON(t *testing.T) {
+	tt := []struct {
+		input              []byte
+		expectedResult     string
+		expectedErrorMatch string
+	}{
+		// valid queries
+		{input: []byte(`gno.land/r/hello.Echo("hello")`), expectedResult: `[{"T":"string","V":"echo:hello"}]`},
+		{input: []byte(`gno.land/r/hello.Owner`), expectedResult: `[{"T":"std.Address","V":"g1abc"}]`},
+		{input: []byte(`gno.land/r/hello.Pair()`), expectedResult: `[{"T":"int","V":42},{"T":"bool","V":true}]`},
+		{input: []byte(`gno.land/r/hello.sl`), expectedResult: `[{"T":"[]int","V":[1,2,3]}]`},
+		{input: []byte(`gno.land/r/hello.item`), expectedResult: `[{"T":"gno.land/r/hello.Item","V":{"Name":"foo","Tags":{"a":1}}}]`},
+		{input: []byte(`gno.land/r/hello.nilErr()`), expectedResult: `[{"T":"nil","V":null}]`},
+
+		// errors
+		{input: []byte(`gno.land/r/doesnotexist.Foo`), expectedErrorMatch: `^invalid package path$`},
+		{input: []byte(`gno.land/r/hello.Panic()`), expectedErrorMatch: `^foo$`},
+	}
+
+	for _, tc := range tt {
+		name := string(tc.input)
+		t.Run(name, func(t *testing.T) {
+			env := setupTestEnv()
+			ctx := env.vmk.MakeGnoTransactionStore(env.ctx)
+			vmHandler := env.vmh
+
+			// Give "addr1" some gnots.
+			addr := crypto.AddressFromPreimage([]byte("addr1"))
+			acc := env.acck.NewAccountWithAddress(ctx, addr)
+			env.acck.SetAccount(ctx, acc)
+			env.bank.SetCoins(ctx, addr, std.MustParseCoins("10000000ugnot"))
+
+			// Create test package.
+			files := []*gnovm.MemFile{
+				{Name: "hello.gno", Body: `
+package hello
+
+import "std"
+
+type Item struct {
+	Name string
+	Tags map[string]int
+}
+
+var Owner = std.Address("g1abc")
+var sl = []int{1,2,3}
+var item = Item{Name: "foo", Tags: map[string]int{"a": 1}}
+var counter int = 42
+func Echo(msg string) string { return "echo:"+msg }
+func Pair() (int, bool) { return counter, true }
+func nilErr() error { return nil }
+func Panic() { panic("foo") }
+`},
+			}
+			pkgPath := "gno.land/r/hello"
+			msg1 := NewMsgAddPackage(addr, pkgPath, files)
+			err := env.vmk.AddPackage(ctx, msg1)
+			assert.NoError(t, err)
+			env.vmk.CommitGnoTransactionStore(ctx)
+
+			req := abci.RequestQuery{
+				Path: "vm/qeval_json",
+				Data: tc.input,
+			}
+			res := vmHandler.Query(env.ctx, req)
+			if tc.expectedErrorMatch == "" {
+				assert.True(t, res.IsOK(), "should not have error")
+				assert.Equal(t, tc.expectedResult, string(res.Data))
+			} else {
+				assert.False(t, res.IsOK(), "should have an error")
+				errmsg := res.Error.Error()
+				assert.Regexp(t, tc.expectedErrorMatch, errmsg)
+			}
+		})
+	}
+}
+
+func TestVmHandlerQuery_Object(t *testing.T) {
+	env := setupTestEnv()
+	ctx := env.vmk.MakeGnoTransactionStore(env.ctx)
+	vmHandler := env.vmh
+
+	// Give "addr1" some gnots.
+	addr := crypto.AddressFromPreimage([]byte("addr1"))
+	acc := env.acck.NewAccountWithAddress(ctx, addr)
+	env.acck.SetAccount(ctx, acc)
+	env.bank.SetCoins(ctx, addr, std.MustParseCoins("10000000ugnot"))
+
+	// Create test package.
+	files := []*gnovm.MemFile{
+		{Name: "hello.gno", Body: `
+package hello
+
+type Item struct {
+	Name string
+	Tags map[string]int
+}
+
+var counter int = 42
+var item = &Item{Name: "foo", Tags: map[string]int{"a": 1, "b": 2, "c": 3}}
+var list = make([]int, 1200)
+`},
+	}
+	pkgPath := "gno.land/r/hello"
+	msg1 := NewMsgAddPackage(addr, pkgPath, files)
+	err := env.vmk.AddPackage(ctx, msg1)
+	require.NoError(t, err)
+	env.vmk.CommitGnoTransactionStore(ctx)
+
+	query := func(data string) (*gno.JSONObject, error) {
+		res := vmHandler.Query(env.ctx, abci.RequestQuery{
+			Path: "vm/qobject",
+			Data: []byte(data),
+		})
+		if res.Error != nil {
+			return nil, res.Error
+		}
+		var jo gno.JSONObject
+		require.NoError(t, json.Unmarshal(res.Data, &jo))
+		return &jo, nil
+	}
+	field := func(jo *gno.JSONObject, name string) gno.JSONField {
+		for _, f := range jo.Fields {
+			if f.Name == name {
+				return f
+			}
+		}
+		t.Fatalf("field %s not found", name)
+		return gno.JSONField{}
+	}
+	ref := func(v json.RawMessage) string {
+		var r struct {
+			Ref string `json:"$ref"`
+		}
+		require.NoError(t, json.Unmarshal(v, &r))
+		require.NotEmpty(t, r.Ref)
+		return r.Ref
+	}
+
+	// The package block.
+	blk, err := query(pkgPath)
+	require.NoError(t, err)
+	assert.Equal(t, "block", blk.Kind)
+	assert.NotEmpty(t, blk.Hash)
+	assert.Positive(t, blk.RefCount)
+	assert.Equal(t, "int", field(blk, "counter").T)
+	assert.JSONEq(t, "42", string(field(blk, "counter").V))
+	itemRef := ref(field(blk, "item").V)
+	assert.Contains(t, blk.Refs, itemRef)
+
+	// Following the pointer to the item.
+	item, err := query(itemRef)
+	require.NoError(t, err)
+	assert.Equal(t, itemRef, item.ObjectID)
+	assert.Equal(t, "struct", item.Kind)
+	require.Len(t, item.Fields, 2)
+	assert.JSONEq(t, `"foo"`, string(item.Fields[0].V))
+	tagsRef := ref(item.Fields[1].V)
+	assert.Equal(t, []string{tagsRef}, item.Refs)
+
+	// The map of tags, paginated.
+	tags, err := query(tagsRef + "?offset=1&limit=1")
+	require.NoError(t, err)
+	assert.Equal(t, "map", tags.Kind)
+	assert.Equal(t, item.ObjectID, tags.OwnerID)
+	assert.Equal(t, 3, tags.Total)
+	assert.Equal(t, 1, tags.Offset)
+	require.Len(t, tags.Fields, 1)
+	assert.JSONEq(t, `"b"`, string(tags.Fields[0].Key.V))
+	assert.JSONEq(t, `2`, string(tags.Fields[0].V))
+
+	// The limit is capped.
+	list, err := query(ref(field(blk, "list").V) + "?limit=5000")
+	require.NoError(t, err)
+	assert.Equal(t, 1200, list.Total)
+	assert.Len(t, list.Fields, maxQueryObjectLimit)
+
+	// Errors.
+	_, err = query("gno.land/r/doesnotexist")
+	assert.ErrorContains(t, err, "invalid package path")
+	_, err = query(strings.Repeat("0", 40) + ":1")
+	assert.ErrorContains(t, err, "invalid object id")
+	_, err = query(pkgPath + "?offset=foo")
+	assert.ErrorContains(t, err, "invalid offset")
+}
+
 func TestVmHandlerQuery_Funcs(t *testing.T) {
 	tt := []struct {
 		input              []byte
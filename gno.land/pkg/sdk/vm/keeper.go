@@ -5,6 +5,7 @@ package vm
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	goerrors "errors"
 	"fmt"
 	"io"
@@ -18,6 +19,7 @@ import (
 	"github.com/gnolang/gno/gnovm"
 	gno "github.com/gnolang/gno/gnovm/pkg/gnolang"
 	"github.com/gnolang/gno/gnovm/stdlibs"
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
 	"github.com/gnolang/gno/tm2/pkg/crypto"
 	"github.com/gnolang/gno/tm2/pkg/db/memdb"
 	"github.com/gnolang/gno/tm2/pkg/errors"
@@ -45,13 +47,17 @@ const (
 // smart contracts programming (scripting).
 type VMKeeperI interface {
 	AddPackage(ctx sdk.Context, msg MsgAddPackage) error
+	UpgradePackage(ctx sdk.Context, msg MsgUpgradePackage) error
 	Call(ctx sdk.Context, msg MsgCall) (res string, err error)
 	QueryEval(ctx sdk.Context, pkgPath string, expr string) (res string, err error)
+	QueryValidatorChanges(ctx sdk.Context, from int64) ([]abci.ValidatorUpdate, error)
+	ValidatorsPkgPath(ctx sdk.Context) string
 	Run(ctx sdk.Context, msg MsgRun) (res string, err error)
 	LoadStdlib(ctx sdk.Context, stdlibDir string)
 	LoadStdlibCached(ctx sdk.Context, stdlibDir string)
 	MakeGnoTransactionStore(ctx sdk.Context) sdk.Context
 	CommitGnoTransactionStore(ctx sdk.Context)
+	IndexPackageImports(ctx sdk.Context)
 }
 
 var _ VMKeeperI = &VMKeeper{}
@@ -69,6 +75,9 @@ type VMKeeper struct {
 
 	// cached, the DeliverTx persistent state.
 	gnoStore gno.Store
+
+	// cached, the costs of the gas schedules.
+	gasCosts *gasCostsCache
 }
 
 // NewVMKeeper returns a new VMKeeper.
@@ -80,11 +89,12 @@ func NewVMKeeper(
 	prmk params.ParamsKeeper,
 ) *VMKeeper {
 	vmk := &VMKeeper{
-		baseKey: baseKey,
-		iavlKey: iavlKey,
-		acck:    acck,
-		bank:    bank,
-		prmk:    prmk,
+		baseKey:  baseKey,
+		iavlKey:  iavlKey,
+		acck:     acck,
+		bank:     bank,
+		prmk:     prmk,
+		gasCosts: &gasCostsCache{},
 	}
 
 	return vmk
@@ -126,6 +136,17 @@ func (vm *VMKeeper) Initialize(
 	}
 }
 
+// Reinitialize discards the gno store, and initializes the VMKeeper again
+// using the given multistore. It's used once the state was restored
+// from a snapshot, as the gno store caches the state it was created with.
+func (vm *VMKeeper) Reinitialize(
+	logger *slog.Logger,
+	ms store.MultiStore,
+) {
+	vm.gnoStore = nil
+	vm.Initialize(logger, ms)
+}
+
 type stdlibCache struct {
 	dir  string
 	base store.Store
@@ -212,10 +233,17 @@ func (vm *VMKeeper) newGnoTransactionStore(ctx sdk.Context) gno.TransactionStore
 	iavl := ctx.Store(vm.iavlKey)
 	gasMeter := ctx.GasMeter()
 
-	return vm.gnoStore.BeginTransaction(base, iavl, gasMeter)
+	ts := vm.gnoStore.BeginTransaction(base, iavl, gasMeter)
+	ts.SetGasConfig(vm.getGasCosts(ctx).store)
+	// storage is only accounted for if it has a price, as saving the
+	// storage of the realms costs gas; the price is read at no cost.
+	price := vm.getStoragePriceParam(ctx.WithGasMeter(store.NewInfiniteGasMeter()))
+	ts.SetStorageAccounting(!price.IsZero())
+	return ts
 }
 
 func (vm *VMKeeper) MakeGnoTransactionStore(ctx sdk.Context) sdk.Context {
+	ctx = vm.withGasCosts(ctx)
 	return ctx.WithValue(gnoStoreContextKey, vm.newGnoTransactionStore(ctx))
 }
 
@@ -286,6 +314,7 @@ func (vm *VMKeeper) checkNamespacePermission(ctx sdk.Context, creator crypto.Add
 			Context:  msgCtx,
 			Alloc:    store.GetAllocator(),
 			GasMeter: ctx.GasMeter(),
+			CPUCosts: vm.getGasCosts(ctx).cpu,
 		})
 	defer m.Release()
 
@@ -391,10 +420,17 @@ func (vm *VMKeeper) AddPackage(ctx sdk.Context, msg MsgAddPackage) (err error) {
 			Alloc:    gnostore.GetAllocator(),
 			Context:  msgCtx,
 			GasMeter: ctx.GasMeter(),
+			CPUCosts: vm.getGasCosts(ctx).cpu,
 		})
 	defer m2.Release()
 	defer doRecover(m2, &err)
 	m2.RunMemPackage(memPkg, true)
+	if err := vm.setPackageImports(ctx, memPkg, nil); err != nil {
+		return err
+	}
+	if err := vm.processStorageDeposit(ctx, creator, gnostore); err != nil {
+		return err
+	}
 
 	// Log the telemetry
 	logTelemetry(
@@ -482,16 +518,19 @@ func (vm *VMKeeper) Call(ctx sdk.Context, msg MsgCall) (res string, err error) {
 			Context:  msgCtx,
 			Alloc:    gnostore.GetAllocator(),
 			GasMeter: ctx.GasMeter(),
+			CPUCosts: vm.getGasCosts(ctx).cpu,
 		})
 	defer m.Release()
 	m.SetActivePackage(mpv)
 	defer doRecover(m, &err)
 	rtvs := m.Eval(xn)
-	for i, rtv := range rtvs {
-		res = res + rtv.String()
-		if i < len(rtvs)-1 {
-			res += "\n"
-		}
+	if msg.JSONResults {
+		res = jsonResults(gnostore, rtvs)
+	} else {
+		res = stringResults(rtvs)
+	}
+	if err := vm.processStorageDeposit(ctx, caller, gnostore); err != nil {
+		return "", err
 	}
 
 	// Log the telemetry
@@ -510,6 +549,28 @@ func (vm *VMKeeper) Call(ctx sdk.Context, msg MsgCall) (res string, err error) {
 	// TODO pay for gas? TODO see context?
 }
 
+// stringResults returns the results of a call or an evaluation, one per line.
+func stringResults(rtvs []gno.TypedValue) string {
+	res := ""
+	for i, rtv := range rtvs {
+		res += rtv.String()
+		if i < len(rtvs)-1 {
+			res += "\n"
+		}
+	}
+	return res
+}
+
+// jsonResults returns the results of a call or an evaluation as a JSON array
+// of gno.JSONValue.
+func jsonResults(store gno.Store, rtvs []gno.TypedValue) string {
+	bz, err := json.Marshal(gno.JSONValues(store, rtvs))
+	if err != nil {
+		panic(err)
+	}
+	return string(bz)
+}
+
 func doRecover(m *gno.Machine, e *error) {
 	r := recover()
 	if r == nil {
@@ -606,6 +667,7 @@ func (vm *VMKeeper) Run(ctx sdk.Context, msg MsgRun) (res string, err error) {
 				Alloc:    gnostore.GetAllocator(),
 				Context:  msgCtx,
 				GasMeter: ctx.GasMeter(),
+				CPUCosts: vm.getGasCosts(ctx).cpu,
 			})
 		// XXX MsgRun does not have pkgPath. How do we find it on chain?
 		defer m.Release()
@@ -627,12 +689,16 @@ func (vm *VMKeeper) Run(ctx sdk.Context, msg MsgRun) (res string, err error) {
 			Alloc:    gnostore.GetAllocator(),
 			Context:  msgCtx,
 			GasMeter: ctx.GasMeter(),
+			CPUCosts: vm.getGasCosts(ctx).cpu,
 		})
 	defer m2.Release()
 	m2.SetActivePackage(pv)
 	defer doRecover(m2, &err)
 	m2.RunMain()
 	res = buf.String()
+	if err := vm.processStorageDeposit(ctx, caller, gnostore); err != nil {
+		return "", err
+	}
 
 	// Log the telemetry
 	logTelemetry(
@@ -711,6 +777,18 @@ func (vm *VMKeeper) QueryFuncs(ctx sdk.Context, pkgPath string) (fsigs FunctionS
 // TODO: modify query protocol to allow MsgEval.
 // TODO: then, rename to "Eval".
 func (vm *VMKeeper) QueryEval(ctx sdk.Context, pkgPath string, expr string) (res string, err error) {
+	return vm.queryEval(ctx, pkgPath, expr, func(_ gno.Store, rtvs []gno.TypedValue) string {
+		return stringResults(rtvs)
+	})
+}
+
+// QueryEvalJSON evaluates a gno expression (readonly, for ABCI queries),
+// like QueryEval, and returns its results as a JSON array of gno.JSONValue.
+func (vm *VMKeeper) QueryEvalJSON(ctx sdk.Context, pkgPath string, expr string) (res string, err error) {
+	return vm.queryEval(ctx, pkgPath, expr, jsonResults)
+}
+
+func (vm *VMKeeper) queryEval(ctx sdk.Context, pkgPath string, expr string, format func(gno.Store, []gno.TypedValue) string) (res string, err error) {
 	alloc := gno.NewAllocator(maxAllocQuery)
 	gnostore := vm.newGnoTransactionStore(ctx) // throwaway (never committed)
 	pkgAddr := gno.DerivePkgAddr(pkgPath)
@@ -749,18 +827,12 @@ func (vm *VMKeeper) QueryEval(ctx sdk.Context, pkgPath string, expr string) (res
 			Context:  msgCtx,
 			Alloc:    alloc,
 			GasMeter: ctx.GasMeter(),
+			CPUCosts: vm.getGasCosts(ctx).cpu,
 		})
 	defer m.Release()
 	defer doRecover(m, &err)
 	rtvs := m.Eval(xx)
-	res = ""
-	for i, rtv := range rtvs {
-		res += rtv.String()
-		if i < len(rtvs)-1 {
-			res += "\n"
-		}
-	}
-	return res, nil
+	return format(gnostore, rtvs), nil
 }
 
 // QueryEvalString evaluates a gno expression (readonly, for ABCI queries).
@@ -806,6 +878,7 @@ func (vm *VMKeeper) QueryEvalString(ctx sdk.Context, pkgPath string, expr string
 			Context:  msgCtx,
 			Alloc:    alloc,
 			GasMeter: ctx.GasMeter(),
+			CPUCosts: vm.getGasCosts(ctx).cpu,
 		})
 	defer m.Release()
 	defer doRecover(m, &err)
@@ -843,6 +916,52 @@ func (vm *VMKeeper) QueryFile(ctx sdk.Context, filepath string) (res string, err
 	}
 }
 
+// Pagination of the values of the objects returned by QueryObject.
+const (
+	defaultQueryObjectLimit = 100
+	maxQueryObjectLimit     = 1000
+)
+
+var reObjectID = regexp.MustCompile(`^[0-9a-f]{40}:[0-9]+$`)
+
+// QueryObject returns the JSON description of the object with the given id,
+// or of the package block of the package at the given path, with at most
+// limit of its values starting from offset; see gno.JSONObject.
+func (vm *VMKeeper) QueryObject(ctx sdk.Context, id string, offset, limit int) (res string, err error) {
+	store := vm.newGnoTransactionStore(ctx) // throwaway (never committed)
+	if offset < 0 {
+		return "", fmt.Errorf("invalid offset %d", offset)
+	}
+	if limit <= 0 {
+		limit = defaultQueryObjectLimit
+	}
+	limit = min(limit, maxQueryObjectLimit)
+	var oo gno.Object
+	if reObjectID.MatchString(id) {
+		var oid gno.ObjectID
+		if err := oid.UnmarshalAmino(id); err != nil {
+			return "", ErrInvalidObjectID(err.Error())
+		}
+		oo = store.GetObjectSafe(oid)
+		if oo == nil {
+			return "", ErrInvalidObjectID(fmt.Sprintf(
+				"object not found: %s", id))
+		}
+	} else {
+		pv := store.GetPackage(id, false)
+		if pv == nil {
+			return "", ErrInvalidPkgPath(fmt.Sprintf(
+				"package not found: %s", id))
+		}
+		oo = pv.GetBlock(store)
+	}
+	bz, err := json.Marshal(gno.NewJSONObject(store, oo, offset, limit))
+	if err != nil {
+		return "", err
+	}
+	return string(bz), nil
+}
+
 // logTelemetry logs the VM processing telemetry
 func logTelemetry(
 	gasUsed int64,
@@ -14,9 +14,14 @@ import (
 	"github.com/gnolang/gno/gno.land/pkg/gnoland/ugnot"
 	"github.com/gnolang/gno/gnovm"
 	"github.com/gnolang/gno/gnovm/pkg/gnolang"
+	gnostd "github.com/gnolang/gno/gnovm/stdlibs/std"
+	bft "github.com/gnolang/gno/tm2/pkg/bft/types"
 	"github.com/gnolang/gno/tm2/pkg/crypto"
 	"github.com/gnolang/gno/tm2/pkg/db/memdb"
 	"github.com/gnolang/gno/tm2/pkg/log"
+	"github.com/gnolang/gno/tm2/pkg/sdk"
+	"github.com/gnolang/gno/tm2/pkg/sdk/auth"
+	"github.com/gnolang/gno/tm2/pkg/sdk/params"
 	"github.com/gnolang/gno/tm2/pkg/std"
 	"github.com/gnolang/gno/tm2/pkg/store/dbadapter"
 	"github.com/gnolang/gno/tm2/pkg/store/types"
@@ -364,7 +369,8 @@ func Do() string {
 	std.SetParamInt64("bar.int64", int64(1337))
 	std.SetParamString("foo.string", "foo2") // override init
 
-	return "XXX" // return std.GetConfig("gno.land/r/test.foo"), if we want to expose std.GetConfig, maybe as a std.TestGetConfig
+	foo, _ := std.GetParamString("", "foo.string")
+	return foo
 }`},
 	}
 	pkgPath := "gno.land/r/test"
@@ -378,8 +384,7 @@ func Do() string {
 
 	res, err := env.vmk.Call(ctx, msg2)
 	assert.NoError(t, err)
-	_ = res
-	expected := fmt.Sprintf("(\"%s\" string)\n\n", "XXX") // XXX: return something more useful
+	expected := fmt.Sprintf("(\"%s\" string)\n\n", "foo2")
 	assert.Equal(t, expected, res)
 
 	var foo string
@@ -390,6 +395,579 @@ func Do() string {
 	assert.Equal(t, int64(1337), bar)
 }
 
+func TestVMKeeperGetParams(t *testing.T) {
+	env := setupTestEnv()
+	ctx := env.vmk.MakeGnoTransactionStore(env.ctx)
+
+	// Give "addr1" some gnots.
+	addr := crypto.AddressFromPreimage([]byte("addr1"))
+	acc := env.acck.NewAccountWithAddress(ctx, addr)
+	env.acck.SetAccount(ctx, acc)
+	env.bank.SetCoins(ctx, addr, std.MustParseCoins(coinsString))
+	env.acck.SetParams(ctx, auth.DefaultParams())
+	env.vmk.prmk.SetString(ctx, "gno.land/r/sys/params.sys.users_pkgpath.string", "gno.land/r/sys/users")
+	env.vmk.prmk.SetBool(ctx, "gno.land/r/other.enabled.bool", true)
+
+	// Create test package.
+	files := []*gnovm.MemFile{
+		{Name: "test.gno", Body: `
+package test
+
+import "std"
+
+func Get() (string, bool, int64, bool, bool, bool) {
+	users, _ := std.GetParamString("gno.land/r/sys/params", "sys.users_pkgpath.string")
+	enabled, _ := std.GetParamBool("gno.land/r/other", "enabled.bool")
+	memo, _ := std.GetParamInt64("auth", "max_memo_bytes.int64")
+	_, unset1 := std.GetParamString("gno.land/r/other", "unset.string")
+	_, unset2 := std.GetParamInt64("bank", "unset.int64")
+	_, malformed := std.GetParamBool("auth", "max_memo_bytes.bool")
+	return users, enabled, memo, unset1, unset2, malformed
+}`},
+	}
+	pkgPath := "gno.land/r/test"
+	err := env.vmk.AddPackage(ctx, NewMsgAddPackage(addr, pkgPath, files))
+	require.NoError(t, err)
+
+	gasBefore := ctx.GasMeter().GasConsumed()
+	res, err := env.vmk.Call(ctx, NewMsgCall(addr, nil, pkgPath, "Get", []string{}))
+	require.NoError(t, err)
+	expected := fmt.Sprintf("(\"gno.land/r/sys/users\" string)\n(true bool)\n(%d int64)\n(false bool)\n(false bool)\n(false bool)\n\n", auth.DefaultMaxMemoBytes)
+	assert.Equal(t, expected, res)
+	assert.Greater(t, ctx.GasMeter().GasConsumed(), gasBefore)
+
+	// The params of a module are charged as a whole.
+	prm := NewSDKParams(env.vmk, ctx)
+	gasBefore = ctx.GasMeter().GasConsumed()
+	var memo int64
+	require.True(t, prm.GetInt64("auth.max_memo_bytes.int64", &memo))
+	blob := env.vmk.prmk.GetRaw(ctx, string(params.ValueStoreKey("auth")))
+	gasConfig := gnolang.DefaultGasConfig()
+	assert.Equal(t, gasConfig.GasGetParamFlat+gasConfig.GasGetParam*types.Gas(len(blob)),
+		ctx.GasMeter().GasConsumed()-gasBefore)
+
+	// The reads are charged at the costs of the gas schedule.
+	env.vmk.prmk.SetString(ctx, GasScheduleParamPrefix+"1.string",
+		`{"height":0,"store":{"GasGetParamFlat":7,"GasGetParam":2}}`)
+	ctx = env.vmk.MakeGnoTransactionStore(ctx)
+	prm = NewSDKParams(env.vmk, ctx)
+	gasBefore = ctx.GasMeter().GasConsumed()
+	require.True(t, prm.GetInt64("auth.max_memo_bytes.int64", &memo))
+	assert.Equal(t, 7+2*types.Gas(len(blob)), ctx.GasMeter().GasConsumed()-gasBefore)
+}
+
+func TestVMKeeperGasSchedule(t *testing.T) {
+	env := setupTestEnv()
+	ctx := env.vmk.MakeGnoTransactionStore(env.ctx)
+	ctx = ctx.WithBlockHeader(&bft.Header{ChainID: "test-chain-id", Height: 10})
+
+	// Give "addr1" some gnots.
+	addr := crypto.AddressFromPreimage([]byte("addr1"))
+	acc := env.acck.NewAccountWithAddress(ctx, addr)
+	env.acck.SetAccount(ctx, acc)
+	env.bank.SetCoins(ctx, addr, std.MustParseCoins(coinsString))
+
+	// Create test package.
+	files := []*gnovm.MemFile{
+		{Name: "test.gno", Body: `
+package test
+
+func Sum(n int) int {
+	sum := 0
+	for i := 0; i < n; i++ {
+		sum += i
+	}
+	return sum
+}`},
+	}
+	pkgPath := "gno.land/r/test"
+	err := env.vmk.AddPackage(ctx, NewMsgAddPackage(addr, pkgPath, files))
+	require.NoError(t, err)
+	env.vmk.CommitGnoTransactionStore(ctx)
+
+	callGasIn := func(txCtx sdk.Context) int64 {
+		before := txCtx.GasMeter().GasConsumed()
+		_, err := env.vmk.Call(txCtx, NewMsgCall(addr, nil, pkgPath, "Sum", []string{"100"}))
+		require.NoError(t, err)
+		return txCtx.GasMeter().GasConsumed() - before
+	}
+	callGas := func() int64 {
+		return callGasIn(env.vmk.MakeGnoTransactionStore(ctx))
+	}
+	callGas() // load the package
+	defaultGas := callGas()
+	assert.Equal(t, defaultGas, callGas())
+
+	// An active schedule replaces the default costs.
+	env.vmk.prmk.SetString(ctx, GasScheduleParamPrefix+"1.string",
+		`{"height":5,"cpu":{"OpLss":1000},"store":{"GasGetObject":1}}`)
+	scheduleGas := callGas()
+	assert.Greater(t, scheduleGas, defaultGas)
+	assert.Equal(t, int64(1), env.vmk.getGasCosts(env.vmk.MakeGnoTransactionStore(ctx)).store.GasGetObject)
+	// The schedule is loaded once, at the start of a transaction.
+	assert.Equal(t, defaultGas, callGasIn(ctx))
+
+	// Schedules not yet active are ignored.
+	env.vmk.prmk.SetString(ctx, GasScheduleParamPrefix+"2.string", `{"height":11,"cpu":{"OpLss":1}}`)
+	assert.Equal(t, scheduleGas, callGas())
+
+	// The latest active version is used.
+	env.vmk.prmk.SetString(ctx, GasScheduleParamPrefix+"10.string", `{"height":10}`)
+	assert.Equal(t, defaultGas, callGas())
+
+	// Realms can only set valid schedules.
+	prm := NewSDKParams(env.vmk, ctx)
+	assert.Panics(t, func() {
+		prm.SetString(GasScheduleParamPrefix+"11.string", `{"cpu":{"OpFoo":1}}`)
+	})
+	assert.Panics(t, func() {
+		prm.SetString(GasScheduleParamPrefix+"latest.string", `{}`)
+	})
+	prm.SetString(GasScheduleParamPrefix+"11.string", `{"height":10,"cpu":{"OpLss":1000},"store":{"GasGetObject":1}}`)
+	assert.Equal(t, scheduleGas, callGas())
+
+	// Invalid schedules stored without validation are ignored.
+	env.vmk.prmk.SetString(ctx, GasScheduleParamPrefix+"12.string", `{"cpu":{"OpFoo":1}}`)
+	assert.Equal(t, scheduleGas, callGas())
+
+	// The cache of the schedules is bounded.
+	for i := range maxGasCostsCacheSize + 1 {
+		_, err := env.vmk.gasCosts.get(fmt.Sprintf(`{"height":%d}`, i))
+		require.NoError(t, err)
+	}
+	assert.LessOrEqual(t, len(env.vmk.gasCosts.costs), maxGasCostsCacheSize)
+}
+
+func TestVMKeeperUpgradePackage(t *testing.T) {
+	env := setupTestEnv()
+	ctx := env.vmk.MakeGnoTransactionStore(env.ctx)
+
+	// Give "addr1" some gnots.
+	addr := crypto.AddressFromPreimage([]byte("addr1"))
+	acc := env.acck.NewAccountWithAddress(ctx, addr)
+	env.acck.SetAccount(ctx, acc)
+	env.bank.SetCoins(ctx, addr, std.MustParseCoins(coinsString))
+	other := crypto.AddressFromPreimage([]byte("addr2"))
+
+	// Create test package, without upgrade authority.
+	pkgPath := "gno.land/r/test"
+	files := []*gnovm.MemFile{
+		{Name: "test.gno", Body: `
+package test
+
+var count int
+
+func Inc() { count++ }
+
+func Count() int { return count }`},
+	}
+	err := env.vmk.AddPackage(ctx, NewMsgAddPackage(addr, pkgPath, files))
+	require.NoError(t, err)
+	_, err = env.vmk.Call(ctx, NewMsgCall(addr, nil, pkgPath, "Inc", []string{}))
+	require.NoError(t, err)
+
+	files[0].Body += `
+
+func SetAuthority(addr string) { std.SetParamString("upgrade_authority.string", addr) }`
+	files[0].Body = strings.Replace(files[0].Body, "package test\n", "package test\n\nimport \"std\"\n", 1)
+	err = env.vmk.UpgradePackage(ctx, NewMsgUpgradePackage(addr, pkgPath, files))
+	assert.True(t, errors.Is(err, InvalidUpgradeError{}), "got: %v", err)
+
+	// Set the upgrade authority.
+	env.vmk.prmk.SetString(ctx, pkgPath+".upgrade_authority.string", addr.String())
+	err = env.vmk.UpgradePackage(ctx, NewMsgUpgradePackage(other, pkgPath, files))
+	assert.True(t, errors.Is(err, UnauthorizedUserError{}), "got: %v", err)
+
+	err = env.vmk.UpgradePackage(ctx, NewMsgUpgradePackage(addr, pkgPath, files))
+	require.NoError(t, err)
+	_, err = env.vmk.Call(ctx, NewMsgCall(addr, nil, pkgPath, "SetAuthority", []string{addr.String()}))
+	require.NoError(t, err)
+
+	// Upgrade with a migrate function.
+	files[0].Body += `
+
+var migrated bool
+
+func migrate() {
+	count *= 10
+	migrated = true
+}
+
+func Migrated() bool { return migrated }`
+	err = env.vmk.UpgradePackage(ctx, NewMsgUpgradePackage(addr, pkgPath, files))
+	require.NoError(t, err)
+	res, err := env.vmk.Call(ctx, NewMsgCall(addr, nil, pkgPath, "Count", []string{}))
+	require.NoError(t, err)
+	assert.Equal(t, "(10 int)\n\n", res)
+	res, err = env.vmk.Call(ctx, NewMsgCall(addr, nil, pkgPath, "Migrated", []string{}))
+	require.NoError(t, err)
+	assert.Equal(t, "(true bool)\n\n", res)
+
+	// migrate can only be called by the upgrade.
+	_, err = env.vmk.Call(ctx, NewMsgCall(addr, nil, pkgPath, "migrate", []string{}))
+	require.Error(t, err)
+	res, err = env.vmk.Call(ctx, NewMsgCall(addr, nil, pkgPath, "Count", []string{}))
+	require.NoError(t, err)
+	assert.Equal(t, "(10 int)\n\n", res)
+
+	events := ctx.EventLogger().Events()
+	require.NotEmpty(t, events)
+	ev, ok := events[len(events)-1].(gnostd.GnoEvent)
+	require.True(t, ok)
+	assert.Equal(t, "PackageUpgraded", ev.Type)
+	assert.Equal(t, pkgPath, ev.PkgPath)
+	require.Len(t, ev.Attributes, 2)
+	assert.NotEqual(t, ev.Attributes[0].Value, ev.Attributes[1].Value)
+
+	// Incompatible upgrades are rejected.
+	err = env.vmk.UpgradePackage(ctx, NewMsgUpgradePackage(addr, pkgPath, []*gnovm.MemFile{
+		{Name: "test.gno", Body: "package test\n\nvar count string\n"},
+	}))
+	require.Error(t, err)
+	assert.Contains(t, fmt.Sprintf("%+v", err), "var count changed type from int to string")
+
+	// Imported realms cannot be upgraded.
+	importedPath := "gno.land/r/imported"
+	err = env.vmk.AddPackage(ctx, NewMsgAddPackage(addr, importedPath, []*gnovm.MemFile{
+		{Name: "imported.gno", Body: "package imported\n\nfunc Hello() string { return \"hello\" }\n"},
+	}))
+	require.NoError(t, err)
+	err = env.vmk.AddPackage(ctx, NewMsgAddPackage(addr, "gno.land/r/importer", []*gnovm.MemFile{
+		{Name: "importer.gno", Body: `
+package importer
+
+import "gno.land/r/imported"
+
+func Hello() string { return imported.Hello() }`},
+	}))
+	require.NoError(t, err)
+	env.vmk.prmk.SetString(ctx, importedPath+".upgrade_authority.string", addr.String())
+	err = env.vmk.UpgradePackage(ctx, NewMsgUpgradePackage(addr, importedPath, []*gnovm.MemFile{
+		{Name: "imported.gno", Body: "package imported\n\nfunc Hello() string { return \"hi\" }\n"},
+	}))
+	assert.True(t, errors.Is(err, InvalidUpgradeError{}), "got: %v", err)
+	assert.Contains(t, fmt.Sprintf("%+v", err), "imported by gno.land/r/importer")
+
+	// Until the package imports are indexed, at the height of their param
+	// and outside of the gas of the transactions, the imports are not
+	// recorded and realms can't be upgraded.
+	upgrade := func() error {
+		return env.vmk.UpgradePackage(ctx, NewMsgUpgradePackage(addr, importedPath, []*gnovm.MemFile{
+			{Name: "imported.gno", Body: "package imported\n\nfunc Hello() string { return \"hi\" }\n"},
+		}))
+	}
+	stor := ctx.Store(env.vmk.iavlKey)
+	stor.Delete(importersKey(importedPath, "gno.land/r/importer"))
+	stor.Delete([]byte(importersIndexedKey))
+	err = env.vmk.AddPackage(ctx, NewMsgAddPackage(addr, "gno.land/r/importer2", []*gnovm.MemFile{
+		{Name: "importer2.gno", Body: "package importer2\n\nimport \"gno.land/r/imported\"\n\nfunc Hello() string { return imported.Hello() }\n"},
+	}))
+	require.NoError(t, err)
+	assert.False(t, stor.Has(importersKey(importedPath, "gno.land/r/importer2")))
+	err = upgrade()
+	assert.True(t, errors.Is(err, InvalidUpgradeError{}), "got: %v", err)
+	assert.Contains(t, fmt.Sprintf("%+v", err), "not indexed yet")
+
+	height := ctx.BlockHeight() + 1
+	env.vmk.prmk.SetInt64(ctx, IndexPackageImportsHeightParamPath, height)
+	env.vmk.IndexPackageImports(ctx)
+	assert.False(t, stor.Has([]byte(importersIndexedKey)))
+	ctx = ctx.WithBlockHeader(&bft.Header{ChainID: ctx.ChainID(), Height: height})
+	before := ctx.GasMeter().GasConsumed()
+	env.vmk.IndexPackageImports(ctx)
+	assert.Equal(t, before, ctx.GasMeter().GasConsumed())
+	assert.True(t, stor.Has([]byte(importersIndexedKey)))
+	assert.True(t, stor.Has(importersKey(importedPath, "gno.land/r/importer2")))
+	err = upgrade()
+	assert.True(t, errors.Is(err, InvalidUpgradeError{}), "got: %v", err)
+	assert.Contains(t, fmt.Sprintf("%+v", err), "imported by gno.land/r/importer")
+}
+
+func TestVMKeeperStorageDeposit(t *testing.T) {
+	env := setupTestEnv()
+	env.vmk.prmk.SetString(env.ctx, storagePriceParamPath, "10ugnot")
+	ctx := env.vmk.MakeGnoTransactionStore(env.ctx)
+
+	// Give "addr1" some gnots.
+	addr := crypto.AddressFromPreimage([]byte("addr1"))
+	acc := env.acck.NewAccountWithAddress(ctx, addr)
+	env.acck.SetAccount(ctx, acc)
+	env.bank.SetCoins(ctx, addr, std.MustParseCoins(coinsString))
+
+	// Create test package.
+	pkgPath := "gno.land/r/test"
+	files := []*gnovm.MemFile{
+		{Name: "test.gno", Body: `
+package test
+
+import "std"
+
+var items []string
+
+func Add(item string) { items = append(items, item) }
+
+func Clear() { items = nil }
+
+func Sponsor() { std.SetParamBool("sponsor_storage.bool", true) }`},
+	}
+	err := env.vmk.AddPackage(ctx, NewMsgAddPackage(addr, pkgPath, files))
+	require.NoError(t, err)
+
+	depAddr := StorageDepositAddr(pkgPath)
+	deposit := func() int64 { return env.bank.GetCoins(ctx, depAddr).AmountOf("ugnot") }
+	balance := func(addr crypto.Address) int64 { return env.bank.GetCoins(ctx, addr).AmountOf("ugnot") }
+	storage := func() (storage, deposit uint64) {
+		rlm := env.vmk.getGnoTransactionStore(ctx).GetPackageRealm(pkgPath)
+		return rlm.Storage, uint64(rlm.Deposit.AmountOf("ugnot"))
+	}
+
+	// The deposit of the package is paid by the creator.
+	stor, dep := storage()
+	assert.Greater(t, stor, uint64(0))
+	assert.Equal(t, stor*10, dep)
+	assert.Equal(t, int64(dep), deposit())
+	assert.Equal(t, 10_000_000-int64(dep), balance(addr))
+	res, err := env.vmk.QueryStorage(ctx, pkgPath)
+	require.NoError(t, err)
+	assert.Equal(t, fmt.Sprintf("storage: %d, deposit: %dugnot", stor, dep), res)
+
+	// Adding items locks more deposit, clearing them refunds it to the
+	// caller who locked it.
+	_, err = env.vmk.Call(ctx, NewMsgCall(addr, nil, pkgPath, "Add", []string{strings.Repeat("x", 1000)}))
+	require.NoError(t, err)
+	stor2, dep2 := storage()
+	assert.Greater(t, stor2, stor+1000)
+	assert.Equal(t, stor2*10, dep2)
+	assert.Equal(t, 10_000_000-int64(dep2), balance(addr))
+
+	// The deposit is refunded in the denom it was locked in.
+	env.vmk.prmk.SetString(ctx, storagePriceParamPath, "1foo")
+	_, err = env.vmk.Call(ctx, NewMsgCall(addr, nil, pkgPath, "Clear", []string{}))
+	require.NoError(t, err)
+	env.vmk.prmk.SetString(ctx, storagePriceParamPath, "10ugnot")
+	stor3, dep3 := storage()
+	assert.Less(t, stor3, stor2)
+	assert.Equal(t, stor3*10, dep3)
+	assert.Equal(t, int64(dep3), deposit())
+	assert.Equal(t, 10_000_000-int64(dep3), balance(addr))
+	assert.Equal(t, std.MustParseCoins(fmt.Sprintf("%dugnot", dep3)), env.vmk.getStorageDeposit(ctx, pkgPath, addr))
+	pkgAddr := gnolang.DerivePkgAddr(pkgPath)
+	assert.Equal(t, int64(0), balance(pkgAddr))
+
+	// Callers without enough coins cannot add storage.
+	poor := crypto.AddressFromPreimage([]byte("addr2"))
+	env.acck.SetAccount(ctx, env.acck.NewAccountWithAddress(ctx, poor))
+	_, err = env.vmk.Call(ctx, NewMsgCall(poor, nil, pkgPath, "Add", []string{"item"}))
+	assert.True(t, errors.Is(err, std.InsufficientCoinsError{}), "got: %v", err)
+
+	// Once sponsored, the realm pays the deposits.
+	_, err = env.vmk.Call(ctx, NewMsgCall(addr, nil, pkgPath, "Sponsor", []string{}))
+	require.NoError(t, err)
+	env.bank.SetCoins(ctx, pkgAddr, std.MustParseCoins(coinsString))
+	_, err = env.vmk.Call(ctx, NewMsgCall(poor, nil, pkgPath, "Add", []string{"item"}))
+	require.NoError(t, err)
+	_, dep4 := storage()
+	assert.Greater(t, dep4, dep3)
+	assert.Equal(t, 10_000_000-int64(dep4-dep3), balance(pkgAddr))
+	assert.Equal(t, int64(0), balance(poor))
+
+	// Realms persisted before the storage was accounted for can free
+	// their uncounted storage.
+	gnostore := env.vmk.getGnoTransactionStore(ctx)
+	rlm := gnostore.GetPackageRealm(pkgPath)
+	rlm.Storage, rlm.Deposit = 0, nil
+	gnostore.SetPackageRealm(rlm)
+	_, err = env.vmk.Call(ctx, NewMsgCall(addr, nil, pkgPath, "Clear", []string{}))
+	require.NoError(t, err)
+	stor5, dep5 := storage()
+	assert.Equal(t, uint64(0), stor5)
+	assert.Equal(t, uint64(0), dep5)
+
+	// Realms can only set a valid price.
+	prm := NewSDKParams(env.vmk, ctx)
+	assert.Panics(t, func() {
+		prm.SetString(storagePriceParamPath, "10")
+	})
+	prm.SetString(storagePriceParamPath, "")
+	assert.True(t, env.vmk.getStoragePriceParam(ctx).IsZero())
+}
+
+// The caller freeing storage is refunded at most the deposit it locked, the
+// rest goes to the realm.
+func TestVMKeeperStorageDepositRefund(t *testing.T) {
+	env := setupTestEnv()
+	env.vmk.prmk.SetString(env.ctx, storagePriceParamPath, "10ugnot")
+	ctx := env.vmk.MakeGnoTransactionStore(env.ctx)
+
+	alice := crypto.AddressFromPreimage([]byte("alice"))
+	bob := crypto.AddressFromPreimage([]byte("bob"))
+	for _, addr := range []crypto.Address{alice, bob} {
+		env.acck.SetAccount(ctx, env.acck.NewAccountWithAddress(ctx, addr))
+		env.bank.SetCoins(ctx, addr, std.MustParseCoins(coinsString))
+	}
+
+	pkgPath := "gno.land/r/test"
+	files := []*gnovm.MemFile{
+		{Name: "test.gno", Body: `
+package test
+
+var items []string
+
+func Add(item string) { items = append(items, item) }
+
+func Clear() { items = nil }`},
+	}
+	err := env.vmk.AddPackage(ctx, NewMsgAddPackage(bob, pkgPath, files))
+	require.NoError(t, err)
+
+	pkgAddr := gnolang.DerivePkgAddr(pkgPath)
+	balance := func(addr crypto.Address) int64 { return env.bank.GetCoins(ctx, addr).AmountOf("ugnot") }
+	locked := func(addr crypto.Address) int64 {
+		return env.vmk.getStorageDeposit(ctx, pkgPath, addr).AmountOf("ugnot")
+	}
+	bobLocked := locked(bob)
+	assert.Equal(t, 10_000_000-bobLocked, balance(bob))
+
+	// Alice pays for the items, Bob frees them.
+	_, err = env.vmk.Call(ctx, NewMsgCall(alice, nil, pkgPath, "Add", []string{strings.Repeat("x", 10_000)}))
+	require.NoError(t, err)
+	paid := 10_000_000 - balance(alice)
+	assert.Greater(t, paid, bobLocked)
+	assert.Equal(t, paid, locked(alice))
+
+	_, err = env.vmk.Call(ctx, NewMsgCall(bob, nil, pkgPath, "Clear", []string{}))
+	require.NoError(t, err)
+
+	// Bob gets back his deposit at most, the rest goes to the realm.
+	assert.Equal(t, int64(10_000_000), balance(bob))
+	assert.Equal(t, int64(0), locked(bob))
+	assert.Equal(t, 10_000_000-paid, balance(alice))
+	refund := balance(pkgAddr)
+	assert.Greater(t, refund, paid-bobLocked-100)
+	assert.LessOrEqual(t, refund, paid)
+
+	// Alice frees the items she paid for.
+	_, err = env.vmk.Call(ctx, NewMsgCall(alice, nil, pkgPath, "Add", []string{strings.Repeat("x", 10_000)}))
+	require.NoError(t, err)
+	paid2 := 10_000_000 - paid - balance(alice)
+	_, err = env.vmk.Call(ctx, NewMsgCall(alice, nil, pkgPath, "Clear", []string{}))
+	require.NoError(t, err)
+	assert.InDelta(t, 10_000_000-paid, balance(alice), 100)
+	assert.InDelta(t, paid, locked(alice), 100)
+	assert.Greater(t, paid2, int64(100_000))
+	assert.Equal(t, refund, balance(pkgAddr))
+}
+
+// The storage is only accounted for while it has a price, so that it costs
+// no gas otherwise.
+func TestVMKeeperStorageWithoutPrice(t *testing.T) {
+	env := setupTestEnv()
+	ctx := env.vmk.MakeGnoTransactionStore(env.ctx)
+
+	addr := crypto.AddressFromPreimage([]byte("addr1"))
+	env.acck.SetAccount(ctx, env.acck.NewAccountWithAddress(ctx, addr))
+	env.bank.SetCoins(ctx, addr, std.MustParseCoins(coinsString))
+
+	pkgPath := "gno.land/r/test"
+	files := []*gnovm.MemFile{
+		{Name: "test.gno", Body: `
+package test
+
+var items []string
+
+func Add(item string) { items = append(items, item) }
+
+func Clear() { items = nil }`},
+	}
+	err := env.vmk.AddPackage(ctx, NewMsgAddPackage(addr, pkgPath, files))
+	require.NoError(t, err)
+	_, err = env.vmk.Call(ctx, NewMsgCall(addr, nil, pkgPath, "Add", []string{strings.Repeat("x", 1000)}))
+	require.NoError(t, err)
+
+	storage := func() uint64 {
+		return env.vmk.getGnoTransactionStore(ctx).GetPackageRealm(pkgPath).Storage
+	}
+
+	// Nothing is counted nor deposited.
+	assert.Equal(t, uint64(0), storage())
+	assert.Equal(t, int64(10_000_000), env.bank.GetCoins(ctx, addr).AmountOf("ugnot"))
+	env.vmk.CommitGnoTransactionStore(ctx)
+
+	// Once a price is set, the storage added is counted, and freeing the
+	// storage not counted doesn't go below zero.
+	env.vmk.prmk.SetString(env.ctx, storagePriceParamPath, "10ugnot")
+	ctx = env.vmk.MakeGnoTransactionStore(env.ctx)
+	_, err = env.vmk.Call(ctx, NewMsgCall(addr, nil, pkgPath, "Add", []string{strings.Repeat("x", 1000)}))
+	require.NoError(t, err)
+	stor := storage()
+	assert.Greater(t, stor, uint64(1000))
+	assert.Equal(t, 10_000_000-int64(stor*10), env.bank.GetCoins(ctx, addr).AmountOf("ugnot"))
+
+	_, err = env.vmk.Call(ctx, NewMsgCall(addr, nil, pkgPath, "Clear", []string{}))
+	require.NoError(t, err)
+	assert.Equal(t, uint64(0), storage())
+}
+
+func TestVMKeeperCallJSONResults(t *testing.T) {
+	env := setupTestEnv()
+	ctx := env.vmk.MakeGnoTransactionStore(env.ctx)
+
+	// Give "addr1" some gnots.
+	addr := crypto.AddressFromPreimage([]byte("addr1"))
+	acc := env.acck.NewAccountWithAddress(ctx, addr)
+	env.acck.SetAccount(ctx, acc)
+	env.bank.SetCoins(ctx, addr, std.MustParseCoins(coinsString))
+
+	// Create test package.
+	files := []*gnovm.MemFile{
+		{Name: "init.gno", Body: `
+package test
+
+import "std"
+
+type Post struct {
+	Title  string
+	Author std.Address
+}
+
+var posts []*Post
+
+func NewPost(title string) (*Post, int) {
+	p := &Post{Title: title, Author: std.GetOrigCaller()}
+	posts = append(posts, p)
+	return p, len(posts)
+}
+
+func Echo(msg string) string {
+	return "echo:"+msg
+}`},
+	}
+	pkgPath := "gno.land/r/test"
+	msg1 := NewMsgAddPackage(addr, pkgPath, files)
+	err := env.vmk.AddPackage(ctx, msg1)
+	require.NoError(t, err)
+
+	msg2 := NewMsgCall(addr, nil, pkgPath, "Echo", []string{"hello"})
+	msg2.JSONResults = true
+	res, err := env.vmk.Call(ctx, msg2)
+	require.NoError(t, err)
+	assert.Equal(t, `[{"T":"string","V":"echo:hello"}]`+"\n\n", res)
+
+	// The new post is persisted by the end of the call, and is
+	// returned as a reference to its object.
+	msg3 := NewMsgCall(addr, nil, pkgPath, "NewPost", []string{"hello"})
+	msg3.JSONResults = true
+	res, err = env.vmk.Call(ctx, msg3)
+	require.NoError(t, err)
+	assert.Regexp(t, `^\[\{"T":"\*gno.land/r/test.Post","V":\{"\$ref":"[0-9a-f]+:\d+"\}\},\{"T":"int","V":1\}\]\n\n$`, res)
+
+	// Without the option, results are unchanged.
+	msg3.JSONResults = false
+	res, err = env.vmk.Call(ctx, msg3)
+	require.NoError(t, err)
+	assert.Contains(t, res, "(2 int)")
+}
+
 // Assign admin as OrigCaller on deploying the package.
 func TestVMKeeperOrigCallerInit(t *testing.T) {
 	env := setupTestEnv()
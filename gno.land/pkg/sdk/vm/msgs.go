@@ -79,6 +79,72 @@ func (msg MsgAddPackage) GetReceived() std.Coins {
 	return msg.Deposit
 }
 
+// Implements auth.PkgPathMsg.
+func (msg MsgAddPackage) GetPkgPath() string {
+	if msg.Package == nil {
+		return ""
+	}
+	return msg.Package.Path
+}
+
+//----------------------------------------
+// MsgUpgradePackage
+
+// MsgUpgradePackage - replace the code of an existing realm, keeping its state.
+type MsgUpgradePackage struct {
+	Creator crypto.Address    `json:"creator" yaml:"creator"`
+	Package *gnovm.MemPackage `json:"package" yaml:"package"`
+}
+
+var _ std.Msg = MsgUpgradePackage{}
+
+// NewMsgUpgradePackage - upload the new files of a realm.
+func NewMsgUpgradePackage(creator crypto.Address, pkgPath string, files []*gnovm.MemFile) MsgUpgradePackage {
+	msg := NewMsgAddPackage(creator, pkgPath, files)
+	return MsgUpgradePackage{
+		Creator: msg.Creator,
+		Package: msg.Package,
+	}
+}
+
+// Implements Msg.
+func (msg MsgUpgradePackage) Route() string { return RouterKey }
+
+// Implements Msg.
+func (msg MsgUpgradePackage) Type() string { return "upgrade_package" }
+
+// Implements Msg.
+func (msg MsgUpgradePackage) ValidateBasic() error {
+	if msg.Creator.IsZero() {
+		return std.ErrInvalidAddress("missing creator address")
+	}
+	if msg.Package == nil || msg.Package.Path == "" {
+		return ErrInvalidPkgPath("missing package path")
+	}
+	if !gno.IsRealmPath(msg.Package.Path) {
+		return ErrInvalidPkgPath("not a realm path: " + msg.Package.Path)
+	}
+	return nil
+}
+
+// Implements Msg.
+func (msg MsgUpgradePackage) GetSignBytes() []byte {
+	return std.MustSortJSON(amino.MustMarshalJSON(msg))
+}
+
+// Implements Msg.
+func (msg MsgUpgradePackage) GetSigners() []crypto.Address {
+	return []crypto.Address{msg.Creator}
+}
+
+// Implements auth.PkgPathMsg.
+func (msg MsgUpgradePackage) GetPkgPath() string {
+	if msg.Package == nil {
+		return ""
+	}
+	return msg.Package.Path
+}
+
 //----------------------------------------
 // MsgCall
 
@@ -89,6 +155,9 @@ type MsgCall struct {
 	PkgPath string         `json:"pkg_path" yaml:"pkg_path"`
 	Func    string         `json:"func" yaml:"func"`
 	Args    []string       `json:"args" yaml:"args"`
+	// JSONResults returns the results of the call JSON-encoded, as a
+	// JSON array of {"T":...,"V":...} objects; see gno.JSONValue.
+	JSONResults bool `json:"json_results,omitempty" yaml:"json_results,omitempty"`
 }
 
 var _ std.Msg = MsgCall{}
@@ -141,6 +210,12 @@ func (msg MsgCall) GetReceived() std.Coins {
 	return msg.Send
 }
 
+// Implements auth.PkgPathMsg.
+// NOTE: MsgRun does not, as it can call any realm.
+func (msg MsgCall) GetPkgPath() string {
+	return msg.PkgPath
+}
+
 //----------------------------------------
 // MsgRun
 
@@ -17,6 +17,7 @@ var Package = amino.RegisterPackage(amino.NewPackage(
 	MsgCall{}, "m_call",
 	MsgRun{}, "m_run",
 	MsgAddPackage{}, "m_addpkg", // TODO rename both to MsgAddPkg?
+	MsgUpgradePackage{}, "m_upgradepkg",
 
 	// errors
 	InvalidPkgPathError{}, "InvalidPkgPathError",
@@ -25,4 +26,6 @@ var Package = amino.RegisterPackage(amino.NewPackage(
 	InvalidExprError{}, "InvalidExprError",
 	TypeCheckError{}, "TypeCheckError",
 	UnauthorizedUserError{}, "UnauthorizedUserError",
+	InvalidUpgradeError{}, "InvalidUpgradeError",
+	InvalidObjectIDError{}, "InvalidObjectIDError",
 ))
@@ -1,12 +1,43 @@
 package vm
 
-import "github.com/gnolang/gno/tm2/pkg/sdk"
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gnolang/gno/tm2/pkg/sdk"
+	"github.com/gnolang/gno/tm2/pkg/std"
+)
 
 const (
-	sysUsersPkgParamPath = "gno.land/r/sys/params.sys.users_pkgpath.string"
-	chainDomainParamPath = "gno.land/r/sys/params.chain_domain.string"
+	sysUsersPkgParamPath      = "gno.land/r/sys/params.sys.users_pkgpath.string"
+	sysValidatorsPkgParamPath = "gno.land/r/sys/params.sys.validators_pkgpath.string"
+	chainDomainParamPath      = "gno.land/r/sys/params.chain_domain.string"
+	storagePriceParamPath     = "gno.land/r/sys/params.vm.storage_price.string"
+
+	// IndexPackageImportsHeightParamPath is the param holding the height of
+	// the block indexing the package imports, from which realms can be
+	// upgraded; 0 to never index them.
+	IndexPackageImportsHeightParamPath = "gno.land/r/sys/params.vm.index_package_imports_height.int64"
 )
 
+// ValidateStringParam returns an error if the string param key is one of the
+// params of the VM, and value is not valid for it. Invalid values would
+// otherwise make the transactions fail, or be ignored.
+func ValidateStringParam(key, value string) error {
+	switch {
+	case strings.HasPrefix(key, GasScheduleParamPrefix):
+		return ValidateGasScheduleParam(key, value)
+	case key == storagePriceParamPath:
+		if value == "" {
+			return nil
+		}
+		if _, err := std.ParseCoin(value); err != nil {
+			return fmt.Errorf("invalid storage price: %w", err)
+		}
+	}
+	return nil
+}
+
 func (vm *VMKeeper) getChainDomainParam(ctx sdk.Context) string {
 	chainDomain := "gno.land" // default
 	vm.prmk.GetString(ctx, chainDomainParamPath, &chainDomain)
@@ -18,3 +49,31 @@ func (vm *VMKeeper) getSysUsersPkgParam(ctx sdk.Context) string {
 	vm.prmk.GetString(ctx, sysUsersPkgParamPath, &sysUsersPkg)
 	return sysUsersPkg
 }
+
+func (vm *VMKeeper) getSysValidatorsPkgParam(ctx sdk.Context) string {
+	sysValidatorsPkg := defaultSysValidatorsPkg
+	vm.prmk.GetString(ctx, sysValidatorsPkgParamPath, &sysValidatorsPkg)
+	return sysValidatorsPkg
+}
+
+// getStoragePriceParam returns the storage deposit locked per byte of realm
+// storage, or a zero coin if no deposit is required.
+func (vm *VMKeeper) getStoragePriceParam(ctx sdk.Context) std.Coin {
+	var storagePrice string
+	vm.prmk.GetString(ctx, storagePriceParamPath, &storagePrice)
+	if storagePrice == "" {
+		return std.Coin{}
+	}
+	price, err := std.ParseCoin(storagePrice)
+	if err != nil {
+		// the price is validated in genesis and when it is set.
+		panic(fmt.Sprintf("invalid storage price %q: %v", storagePrice, err))
+	}
+	return price
+}
+
+func (vm *VMKeeper) getIndexPackageImportsHeightParam(ctx sdk.Context) int64 {
+	var height int64
+	vm.prmk.GetInt64(ctx, IndexPackageImportsHeightParamPath, &height)
+	return height
+}
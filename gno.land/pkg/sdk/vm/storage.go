@@ -0,0 +1,197 @@
+package vm
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+
+	gno "github.com/gnolang/gno/gnovm/pkg/gnolang"
+	gnostd "github.com/gnolang/gno/gnovm/stdlibs/std"
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/gnolang/gno/tm2/pkg/overflow"
+	"github.com/gnolang/gno/tm2/pkg/sdk"
+	"github.com/gnolang/gno/tm2/pkg/std"
+)
+
+const (
+	// sponsorStorageParamKey is the param key of a realm paying the storage
+	// deposits of its callers from its package address, if true; a realm
+	// sets it with std.SetParamBool("sponsor_storage.bool", true).
+	sponsorStorageParamKey = "sponsor_storage.bool"
+	// storageDepositEvent and storageUnlockEvent are the types of the
+	// events emitted when a deposit is locked or refunded.
+	storageDepositEvent = "StorageDeposit"
+	storageUnlockEvent  = "StorageUnlock"
+	// storageDepositKeyPrefix prefixes the keys of the deposits locked by
+	// each payer: storageDepositKeyPrefix + realm path + ":" + payer.
+	storageDepositKeyPrefix = "/vm/storagedeposits/"
+)
+
+// StorageDepositAddr returns the address holding the storage deposit of the
+// realm at pkgPath. No key is known for it; the coins can only be unlocked by
+// the realm freeing storage.
+func StorageDepositAddr(pkgPath string) crypto.Address {
+	return crypto.AddressFromPreimage([]byte("storage_deposit:" + pkgPath))
+}
+
+func (vm *VMKeeper) getSponsorStorageParam(ctx sdk.Context, pkgPath string) bool {
+	var sponsor bool
+	vm.prmk.GetBool(ctx, pkgPath+"."+sponsorStorageParamKey, &sponsor)
+	return sponsor
+}
+
+func storageDepositKey(pkgPath string, payer crypto.Address) []byte {
+	return []byte(storageDepositKeyPrefix + pkgPath + ":" + payer.String())
+}
+
+// getStorageDeposit returns the deposit locked by payer for the storage of
+// the realm at pkgPath, and not refunded yet.
+func (vm *VMKeeper) getStorageDeposit(ctx sdk.Context, pkgPath string, payer crypto.Address) std.Coins {
+	bz := ctx.Store(vm.iavlKey).Get(storageDepositKey(pkgPath, payer))
+	if bz == nil {
+		return nil
+	}
+	var coins std.Coins
+	amino.MustUnmarshal(bz, &coins)
+	return coins
+}
+
+func (vm *VMKeeper) setStorageDeposit(ctx sdk.Context, pkgPath string, payer crypto.Address, coins std.Coins) {
+	stor := ctx.Store(vm.iavlKey)
+	key := storageDepositKey(pkgPath, payer)
+	if coins.IsZero() {
+		stor.Delete(key)
+		return
+	}
+	stor.Set(key, amino.MustMarshal(coins))
+}
+
+// processStorageDeposit locks a deposit for the storage added by each realm
+// in the transaction, at the storage price per byte, and refunds the deposit
+// of the storage freed, in proportion of the deposit of the realm.
+//
+// The deposits are paid by the caller, unless the realm sponsors the storage
+// of its callers, and the deposit of each caller is recorded. The caller
+// freeing storage is refunded at most the deposit it locked in the realm;
+// the rest of the refund goes to the realm's package address, which decides
+// how to give it back.
+func (vm *VMKeeper) processStorageDeposit(ctx sdk.Context, caller crypto.Address, gnostore gno.Store) error {
+	diffs := gnostore.RealmStorageDiffs()
+	if len(diffs) == 0 {
+		return nil
+	}
+	paths := make([]string, 0, len(diffs))
+	for path := range diffs {
+		paths = append(paths, path)
+	}
+	// iterate deterministically.
+	sort.Strings(paths)
+
+	price := vm.getStoragePriceParam(ctx)
+	for _, path := range paths {
+		diff := diffs[path]
+		delete(diffs, path)
+		if diff == 0 || (diff > 0 && price.IsZero()) {
+			continue
+		}
+		rlm := gnostore.GetPackageRealm(path)
+		pkgAddr := gno.DerivePkgAddr(path)
+		depAddr := StorageDepositAddr(path)
+
+		if diff > 0 {
+			payer := caller
+			sponsored := vm.getSponsorStorageParam(ctx, path)
+			if sponsored {
+				payer = pkgAddr
+			}
+			amt, ok := overflow.Mul64(price.Amount, diff)
+			if !ok {
+				return std.ErrInsufficientCoins(fmt.Sprintf(
+					"storage deposit overflow for %d bytes in %s", diff, path))
+			}
+			coins := std.Coins{std.NewCoin(price.Denom, amt)}
+			if err := vm.bank.SendCoins(ctx, payer, depAddr, coins); err != nil {
+				return err
+			}
+			if !sponsored {
+				// the refunds of the realm go to its package
+				// address anyway.
+				dep := vm.getStorageDeposit(ctx, path, payer)
+				vm.setStorageDeposit(ctx, path, payer, dep.Add(coins))
+			}
+			rlm.Deposit = rlm.Deposit.Add(coins)
+			gnostore.SetPackageRealm(rlm)
+			emitStorageEvent(ctx, storageDepositEvent, path, "payer", payer, diff, coins)
+			continue
+		}
+
+		// rlm.Storage is the storage after the bytes were freed;
+		// the deposit is refunded in the denoms it was locked in.
+		freed := uint64(-diff)
+		total := rlm.Storage + freed
+		var coins std.Coins
+		for _, coin := range rlm.Deposit {
+			amt := big.NewInt(coin.Amount)
+			amt.Mul(amt, new(big.Int).SetUint64(freed))
+			amt.Quo(amt, new(big.Int).SetUint64(total))
+			if amt.Sign() > 0 {
+				coins = append(coins, std.NewCoin(coin.Denom, amt.Int64()))
+			}
+		}
+		if coins.IsZero() {
+			continue
+		}
+		// the caller is refunded at most its own deposit.
+		dep := vm.getStorageDeposit(ctx, path, caller)
+		var refund std.Coins
+		for _, coin := range coins {
+			if amt := min(coin.Amount, dep.AmountOf(coin.Denom)); amt > 0 {
+				refund = append(refund, std.NewCoin(coin.Denom, amt))
+			}
+		}
+		rest := coins.Sub(refund)
+		if !refund.IsZero() {
+			if err := vm.bank.SendCoins(ctx, depAddr, caller, refund); err != nil {
+				return err
+			}
+			vm.setStorageDeposit(ctx, path, caller, dep.Sub(refund))
+			emitStorageEvent(ctx, storageUnlockEvent, path, "recipient", caller, diff, refund)
+		}
+		if !rest.IsZero() {
+			if err := vm.bank.SendCoins(ctx, depAddr, pkgAddr, rest); err != nil {
+				return err
+			}
+			emitStorageEvent(ctx, storageUnlockEvent, path, "recipient", pkgAddr, diff, rest)
+		}
+		rlm.Deposit = rlm.Deposit.Sub(coins)
+		gnostore.SetPackageRealm(rlm)
+	}
+	return nil
+}
+
+func emitStorageEvent(ctx sdk.Context, evType, pkgPath, addrKey string, addr crypto.Address, diff int64, coins std.Coins) {
+	ctx.EventLogger().EmitEvent(gnostd.GnoEvent{
+		Type: evType,
+		Attributes: []gnostd.GnoEventAttribute{
+			{Key: addrKey, Value: addr.String()},
+			{Key: "storage", Value: fmt.Sprint(diff)},
+			{Key: "amount", Value: coins.String()},
+		},
+		PkgPath: pkgPath,
+	})
+}
+
+// QueryStorage returns the storage used by the realm at pkgPath, in bytes,
+// and its storage deposit.
+func (vm *VMKeeper) QueryStorage(ctx sdk.Context, pkgPath string) (res string, err error) {
+	store := vm.newGnoTransactionStore(ctx) // throwaway (never committed)
+	if !gno.IsRealmPath(pkgPath) {
+		return "", ErrInvalidPkgPath("not a realm: " + pkgPath)
+	}
+	rlm := store.GetPackageRealm(pkgPath)
+	if rlm == nil {
+		return "", ErrInvalidPkgPath("realm does not exist: " + pkgPath)
+	}
+	return fmt.Sprintf("storage: %d, deposit: %s", rlm.Storage, rlm.Deposit), nil
+}
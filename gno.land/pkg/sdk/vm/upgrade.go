@@ -0,0 +1,270 @@
+package vm
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gnolang/gno/gnovm"
+	gno "github.com/gnolang/gno/gnovm/pkg/gnolang"
+	"github.com/gnolang/gno/gnovm/pkg/packages"
+	"github.com/gnolang/gno/gnovm/stdlibs"
+	gnostd "github.com/gnolang/gno/gnovm/stdlibs/std"
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	"github.com/gnolang/gno/tm2/pkg/sdk"
+	"github.com/gnolang/gno/tm2/pkg/std"
+	"github.com/gnolang/gno/tm2/pkg/store"
+	"github.com/gnolang/gno/tm2/pkg/store/types"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const (
+	// upgradeAuthorityParamKey is the param key of a realm holding the
+	// address allowed to upgrade it; a realm sets it with
+	// std.SetParamString("upgrade_authority.string", addr).
+	upgradeAuthorityParamKey = "upgrade_authority.string"
+	// packageUpgradedEvent is the type of the event emitted on upgrades.
+	packageUpgradedEvent = "PackageUpgraded"
+	// importersKeyPrefix prefixes the keys of the packages importing a
+	// package: importersKeyPrefix + imported path + ":" + importer path.
+	importersKeyPrefix = "/vm/pkgimporters/"
+	// importersIndexedKey is set once the packages importing a package
+	// are indexed for all the packages, at the height of the
+	// index_package_imports_height param; the imports of the packages are
+	// recorded from then on.
+	importersIndexedKey = "/vm/pkgimporters_indexed"
+)
+
+func (vm *VMKeeper) getUpgradeAuthorityParam(ctx sdk.Context, pkgPath string) string {
+	var authority string
+	vm.prmk.GetString(ctx, pkgPath+"."+upgradeAuthorityParamKey, &authority)
+	return authority
+}
+
+func importersKey(pkgPath, importer string) []byte {
+	return []byte(importersKeyPrefix + pkgPath + ":" + importer)
+}
+
+// packageImports returns the paths of the packages imported by the non-test
+// files of memPkg.
+func packageImports(memPkg *gnovm.MemPackage) ([]string, error) {
+	var paths []string
+	for _, file := range memPkg.Files {
+		if !strings.HasSuffix(file.Name, ".gno") || strings.HasSuffix(file.Name, "_test.gno") ||
+			strings.HasSuffix(file.Name, "_filetest.gno") {
+			continue
+		}
+		imports, err := packages.FileImports(file.Name, file.Body, nil)
+		if err != nil {
+			return nil, err
+		}
+		for _, im := range imports {
+			paths = append(paths, im.PkgPath)
+		}
+	}
+	return paths, nil
+}
+
+// packageImportsIndexed returns whether the package imports are indexed.
+// It is checked at no cost, so that recording the imports only charges the
+// writes.
+func (vm *VMKeeper) packageImportsIndexed(ctx sdk.Context) bool {
+	ctx = ctx.WithGasMeter(store.NewInfiniteGasMeter())
+	return ctx.Store(vm.iavlKey).Has([]byte(importersIndexedKey))
+}
+
+// setPackageImports records the packages imported by memPkg, so that the
+// packages imported by other packages are not upgraded. oldPkg is the
+// previous version of memPkg, if any. Nothing is recorded until the package
+// imports are indexed, which records the imports of all the packages.
+func (vm *VMKeeper) setPackageImports(ctx sdk.Context, memPkg, oldPkg *gnovm.MemPackage) error {
+	if !vm.packageImportsIndexed(ctx) {
+		return nil
+	}
+	stor := ctx.Store(vm.iavlKey)
+	if oldPkg != nil {
+		paths, err := packageImports(oldPkg)
+		if err != nil {
+			return err
+		}
+		for _, path := range paths {
+			stor.Delete(importersKey(path, oldPkg.Path))
+		}
+	}
+	paths, err := packageImports(memPkg)
+	if err != nil {
+		return err
+	}
+	for _, path := range paths {
+		stor.Set(importersKey(path, memPkg.Path), []byte{})
+	}
+	return nil
+}
+
+// IndexPackageImports records the packages imported by all the stored
+// packages, at the beginning of the block at the height of the
+// index_package_imports_height param, if they are not indexed yet. It is run
+// outside of the gas of the transactions, as it reads all the packages. New
+// chains set the param in genesis, and the chains started before the index
+// set it with a governance proposal.
+func (vm *VMKeeper) IndexPackageImports(ctx sdk.Context) {
+	height := vm.getIndexPackageImportsHeightParam(ctx)
+	if height == 0 || ctx.BlockHeight() != height || vm.packageImportsIndexed(ctx) {
+		return
+	}
+	start := time.Now()
+	stor := ctx.Store(vm.iavlKey)
+	gnostore := gno.NewStore(nil, ctx.Store(vm.baseKey), stor)
+	if ch := gnostore.IterMemPackage(); ch != nil {
+		for memPkg := range ch {
+			paths, err := packageImports(memPkg)
+			if err != nil {
+				// the packages were parsed when they were added.
+				ctx.Logger().Error("skipping the imports of package",
+					"path", memPkg.Path, "err", err)
+				continue
+			}
+			for _, path := range paths {
+				stor.Set(importersKey(path, memPkg.Path), []byte{})
+			}
+		}
+	}
+	stor.Set([]byte(importersIndexedKey), []byte{})
+	ctx.Logger().Info("indexed the package imports", "elapsed", time.Since(start))
+}
+
+// getPackageImporter returns the path of a package importing pkgPath, or ""
+// if there is none.
+func (vm *VMKeeper) getPackageImporter(ctx sdk.Context, pkgPath string) (string, error) {
+	stor := ctx.Store(vm.iavlKey)
+	if !stor.Has([]byte(importersIndexedKey)) {
+		// only until the height of the index_package_imports_height
+		// param.
+		return "", ErrInvalidUpgrade("the package imports are not indexed yet")
+	}
+	prefix := importersKey(pkgPath, "")
+	iter := stor.Iterator(prefix, types.PrefixEndBytes(prefix))
+	defer iter.Close()
+	if !iter.Valid() {
+		return "", nil
+	}
+	return string(iter.Key()[len(prefix):]), nil
+}
+
+// memPackageHash returns the hex-encoded hash of the code of memPkg.
+func memPackageHash(memPkg *gnovm.MemPackage) string {
+	sum := sha256.Sum256(amino.MustMarshal(memPkg))
+	return hex.EncodeToString(sum[:])
+}
+
+// UpgradePackage replaces the code of an existing realm, keeping its state,
+// and calls its migrate function, if declared. The realm must have set the
+// creator as its upgrade authority, and must not be imported by other
+// packages.
+func (vm *VMKeeper) UpgradePackage(ctx sdk.Context, msg MsgUpgradePackage) (err error) {
+	creator := msg.Creator
+	pkgPath := msg.Package.Path
+	memPkg := msg.Package
+	gnostore := vm.getGnoTransactionStore(ctx)
+
+	// Validate arguments.
+	if creator.IsZero() {
+		return std.ErrInvalidAddress("missing creator address")
+	}
+	if err := msg.Package.Validate(); err != nil {
+		return ErrInvalidPkgPath(err.Error())
+	}
+	if !gno.IsRealmPath(pkgPath) {
+		return ErrInvalidPkgPath("not a realm path: " + pkgPath)
+	}
+	oldPkg := gnostore.GetMemPackage(pkgPath)
+	if oldPkg == nil {
+		return ErrInvalidPkgPath("package does not exist: " + pkgPath)
+	}
+	authority := vm.getUpgradeAuthorityParam(ctx, pkgPath)
+	if authority == "" {
+		return ErrInvalidUpgrade("package has no upgrade authority: " + pkgPath)
+	}
+	if authority != creator.String() {
+		return ErrUnauthorizedUser(
+			fmt.Sprintf("%s is not the upgrade authority of %s", creator, pkgPath))
+	}
+	importer, err := vm.getPackageImporter(ctx, pkgPath)
+	if err != nil {
+		return err
+	}
+	if importer != "" {
+		return ErrInvalidUpgrade(
+			fmt.Sprintf("package %s is imported by %s", pkgPath, importer))
+	}
+
+	// Validate Gno syntax and type check.
+	format := true
+	if err := gno.TypeCheckMemPackage(memPkg, gnostore, format); err != nil {
+		return ErrTypeCheck(err)
+	}
+
+	// Parse and run the files, replacing the code of the package.
+	chainDomain := vm.getChainDomainParam(ctx)
+	pkgAddr := gno.DerivePkgAddr(pkgPath)
+	msgCtx := stdlibs.ExecContext{
+		ChainID:       ctx.ChainID(),
+		ChainDomain:   chainDomain,
+		Height:        ctx.BlockHeight(),
+		Timestamp:     ctx.BlockTime().Unix(),
+		OrigCaller:    creator.Bech32(),
+		OrigSendSpent: new(std.Coins),
+		OrigPkgAddr:   pkgAddr.Bech32(),
+		Banker:        NewSDKBanker(vm, ctx),
+		Params:        NewSDKParams(vm, ctx),
+		EventLogger:   ctx.EventLogger(),
+	}
+	m2 := gno.NewMachineWithOptions(
+		gno.MachineOptions{
+			PkgPath:  "",
+			Output:   vm.Output,
+			Store:    gnostore,
+			Alloc:    gnostore.GetAllocator(),
+			Context:  msgCtx,
+			GasMeter: ctx.GasMeter(),
+			CPUCosts: vm.getGasCosts(ctx).cpu,
+		})
+	defer m2.Release()
+	err = func() (err error) {
+		defer doRecover(m2, &err)
+		m2.UpgradeMemPackage(memPkg)
+		return nil
+	}()
+	if err != nil {
+		return err
+	}
+	if err := vm.setPackageImports(ctx, memPkg, oldPkg); err != nil {
+		return err
+	}
+	if err := vm.processStorageDeposit(ctx, creator, gnostore); err != nil {
+		return err
+	}
+
+	// Log the telemetry
+	logTelemetry(
+		m2.GasMeter.GasConsumed(),
+		m2.Cycles,
+		attribute.KeyValue{
+			Key:   "operation",
+			Value: attribute.StringValue("m_upgradepkg"),
+		},
+	)
+
+	ctx.EventLogger().EmitEvent(gnostd.GnoEvent{
+		Type: packageUpgradedEvent,
+		Attributes: []gnostd.GnoEventAttribute{
+			{Key: "old_hash", Value: memPackageHash(oldPkg)},
+			{Key: "new_hash", Value: memPackageHash(memPkg)},
+		},
+		PkgPath: pkgPath,
+	})
+
+	return nil
+}
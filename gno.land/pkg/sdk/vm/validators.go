@@ -0,0 +1,150 @@
+package vm
+
+import (
+	"fmt"
+	"math"
+
+	gno "github.com/gnolang/gno/gnovm/pkg/gnolang"
+	"github.com/gnolang/gno/gnovm/stdlibs"
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/gnolang/gno/tm2/pkg/sdk"
+)
+
+const (
+	// defaultSysValidatorsPkg is the realm governing the validator set, if
+	// the sys.validators_pkgpath param is not set.
+	defaultSysValidatorsPkg = "gno.land/r/sys/validators/v2"
+
+	// valChangesFn is the function of the validators realm returning the
+	// changes of the validator set since a block height, as a slice of
+	// gno.land/p/sys/validators.Validator.
+	valChangesFn = "GetChanges"
+)
+
+// ValidatorsPkgPath returns the path of the realm governing the validator
+// set, set with the sys.validators_pkgpath param of r/sys/params.
+func (vm *VMKeeper) ValidatorsPkgPath(ctx sdk.Context) string {
+	return vm.getSysValidatorsPkgParam(ctx)
+}
+
+// QueryValidatorChanges returns the changes of the validator set made by the
+// validators realm since the given block height (readonly). A validator with
+// no voting power is removed from the set.
+func (vm *VMKeeper) QueryValidatorChanges(ctx sdk.Context, from int64) (updates []abci.ValidatorUpdate, err error) {
+	alloc := gno.NewAllocator(maxAllocQuery)
+	gnostore := vm.newGnoTransactionStore(ctx) // throwaway (never committed)
+	pkgPath := vm.getSysValidatorsPkgParam(ctx)
+	pkgAddr := gno.DerivePkgAddr(pkgPath)
+	// Get Package.
+	pv := gnostore.GetPackage(pkgPath, false)
+	if pv == nil {
+		err = ErrInvalidPkgPath(fmt.Sprintf(
+			"package not found: %s", pkgPath))
+		return nil, err
+	}
+	// Parse expression.
+	xx, err := gno.ParseExpr(fmt.Sprintf("%s(%d)", valChangesFn, from))
+	if err != nil {
+		return nil, err
+	}
+	// Construct new machine.
+	chainDomain := vm.getChainDomainParam(ctx)
+	msgCtx := stdlibs.ExecContext{
+		ChainID:     ctx.ChainID(),
+		ChainDomain: chainDomain,
+		Height:      ctx.BlockHeight(),
+		Timestamp:   ctx.BlockTime().Unix(),
+		OrigPkgAddr: pkgAddr.Bech32(),
+		Banker:      NewSDKBanker(vm, ctx), // safe as long as ctx is a fork to be discarded.
+		Params:      NewSDKParams(vm, ctx),
+		EventLogger: ctx.EventLogger(),
+	}
+	m := gno.NewMachineWithOptions(
+		gno.MachineOptions{
+			PkgPath:  pkgPath,
+			Output:   vm.Output,
+			Store:    gnostore,
+			Context:  msgCtx,
+			Alloc:    alloc,
+			GasMeter: ctx.GasMeter(),
+			CPUCosts: vm.getGasCosts(ctx).cpu,
+		})
+	defer m.Release()
+	defer doRecover(m, &err)
+	rtvs := m.Eval(xx)
+	if len(rtvs) != 1 {
+		return nil, fmt.Errorf("expected 1 result from %s.%s, got %d", pkgPath, valChangesFn, len(rtvs))
+	}
+	return validatorUpdatesFromTypedValue(gnostore, rtvs[0])
+}
+
+// validatorUpdatesFromTypedValue converts a slice of validators, which are
+// structs with the Address, PubKey and VotingPower fields of
+// gno.land/p/sys/validators.Validator, to validator updates.
+func validatorUpdatesFromTypedValue(store gno.Store, tv gno.TypedValue) ([]abci.ValidatorUpdate, error) {
+	if tv.T == nil || tv.T.Kind() != gno.SliceKind {
+		return nil, fmt.Errorf("expected a slice of validators, got %s", tv.String())
+	}
+	n := tv.GetLength()
+	if n == 0 {
+		return nil, nil
+	}
+
+	updates := make([]abci.ValidatorUpdate, 0, n)
+	for i := 0; i < n; i++ {
+		val := tv.GetPointerAtIndexInt(store, i).Deref()
+		st, ok := gno.BaseOf(val.T).(*gno.StructType)
+		if !ok {
+			return nil, fmt.Errorf("expected a validator struct, got %s", val.T.String())
+		}
+		sv := val.V.(*gno.StructValue)
+
+		var (
+			update     abci.ValidatorUpdate
+			fieldsSeen int
+		)
+		for fi, field := range st.Fields {
+			fv := sv.GetPointerToInt(store, fi).Deref()
+			switch field.Name {
+			case "Address":
+				if fv.T.Kind() != gno.StringKind {
+					return nil, fmt.Errorf("invalid validator address type %s", fv.T.String())
+				}
+				address, err := crypto.AddressFromBech32(fv.GetString())
+				if err != nil {
+					return nil, fmt.Errorf("unable to parse address, %w", err)
+				}
+				update.Address = address
+			case "PubKey":
+				if fv.T.Kind() != gno.StringKind {
+					return nil, fmt.Errorf("invalid validator public key type %s", fv.T.String())
+				}
+				pubKey, err := crypto.PubKeyFromBech32(fv.GetString())
+				if err != nil {
+					return nil, fmt.Errorf("unable to parse public key, %w", err)
+				}
+				update.PubKey = pubKey
+			case "VotingPower":
+				if fv.T.Kind() != gno.Uint64Kind {
+					return nil, fmt.Errorf("invalid validator voting power type %s", fv.T.String())
+				}
+				power := fv.GetUint64()
+				if power > math.MaxInt64 {
+					return nil, fmt.Errorf("voting power %d overflows int64", power)
+				}
+				update.Power = int64(power)
+			default:
+				continue
+			}
+			fieldsSeen++
+		}
+		if fieldsSeen != 3 {
+			return nil, fmt.Errorf("expected a validator struct, got %s", val.T.String())
+		}
+
+		updates = append(updates, update)
+	}
+
+	return updates, nil
+}
@@ -0,0 +1,115 @@
+package vm
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/gno/gnovm"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/gnolang/gno/tm2/pkg/crypto/ed25519"
+	"github.com/gnolang/gno/tm2/pkg/std"
+)
+
+func TestVMKeeperQueryValidatorChanges(t *testing.T) {
+	env := setupTestEnv()
+	ctx := env.vmk.MakeGnoTransactionStore(env.ctx)
+
+	// Give "addr1" some gnots.
+	addr := crypto.AddressFromPreimage([]byte("addr1"))
+	acc := env.acck.NewAccountWithAddress(ctx, addr)
+	env.acck.SetAccount(ctx, acc)
+	env.bank.SetCoins(ctx, addr, std.MustParseCoins(coinsString))
+
+	// the default validators realm does not exist.
+	assert.Equal(t, defaultSysValidatorsPkg, env.vmk.ValidatorsPkgPath(ctx))
+	_, err := env.vmk.QueryValidatorChanges(ctx, 0)
+	assert.Error(t, err)
+
+	// Create the validators realm.
+	pubKey1 := ed25519.GenPrivKey().PubKey()
+	pubKey2 := ed25519.GenPrivKey().PubKey()
+	files := []*gnovm.MemFile{
+		{Name: "validators.gno", Body: fmt.Sprintf(`
+package validators
+
+import "std"
+
+type Validator struct {
+	Address     std.Address
+	PubKey      string
+	VotingPower uint64
+}
+
+var changes = map[int64][]Validator{
+	1: {{Address: %q, PubKey: %q, VotingPower: 10}},
+	2: {{Address: %q, PubKey: %q, VotingPower: 0}},
+}
+
+func GetChanges(from int64) []Validator {
+	var res []Validator
+	for h := from; h <= 2; h++ {
+		res = append(res, changes[h]...)
+	}
+	return res
+}`,
+			pubKey1.Address(), crypto.PubKeyToBech32(pubKey1),
+			pubKey2.Address(), crypto.PubKeyToBech32(pubKey2),
+		)},
+	}
+	pkgPath := "gno.land/r/test/validators"
+	err = env.vmk.AddPackage(ctx, NewMsgAddPackage(addr, pkgPath, files))
+	require.NoError(t, err)
+	env.vmk.CommitGnoTransactionStore(ctx)
+
+	env.vmk.prmk.SetString(ctx, sysValidatorsPkgParamPath, pkgPath)
+	assert.Equal(t, pkgPath, env.vmk.ValidatorsPkgPath(ctx))
+
+	updates, err := env.vmk.QueryValidatorChanges(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, updates, 2)
+	assert.Equal(t, pubKey1.Address(), updates[0].Address)
+	assert.True(t, pubKey1.Equals(updates[0].PubKey))
+	assert.Equal(t, int64(10), updates[0].Power)
+	assert.Equal(t, pubKey2.Address(), updates[1].Address)
+	assert.Equal(t, int64(0), updates[1].Power)
+
+	updates, err = env.vmk.QueryValidatorChanges(ctx, 3)
+	require.NoError(t, err)
+	assert.Empty(t, updates)
+}
+
+func TestVMKeeperQueryValidatorChanges_InvalidType(t *testing.T) {
+	env := setupTestEnv()
+	ctx := env.vmk.MakeGnoTransactionStore(env.ctx)
+
+	// Give "addr1" some gnots.
+	addr := crypto.AddressFromPreimage([]byte("addr1"))
+	acc := env.acck.NewAccountWithAddress(ctx, addr)
+	env.acck.SetAccount(ctx, acc)
+	env.bank.SetCoins(ctx, addr, std.MustParseCoins(coinsString))
+
+	files := []*gnovm.MemFile{
+		{Name: "validators.gno", Body: `
+package validators
+
+type Validator struct {
+	Addr  string
+	Power int
+}
+
+func GetChanges(from int64) []Validator {
+	return []Validator{{Addr: "g1", Power: 1}}
+}`},
+	}
+	pkgPath := "gno.land/r/test/validators"
+	err := env.vmk.AddPackage(ctx, NewMsgAddPackage(addr, pkgPath, files))
+	require.NoError(t, err)
+	env.vmk.CommitGnoTransactionStore(ctx)
+	env.vmk.prmk.SetString(ctx, sysValidatorsPkgParamPath, pkgPath)
+
+	_, err = env.vmk.QueryValidatorChanges(ctx, 0)
+	assert.ErrorContains(t, err, "expected a validator struct")
+}
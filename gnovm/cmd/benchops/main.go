@@ -13,6 +13,9 @@ var (
 	outFlag   = flag.String("out", "results.csv", "the out put file")
 	benchFlag = flag.String("bench", "./pkg/benchops/gno", "the path to the benchmark contract")
 	binFlag   = flag.String("bin", "", "interpret the existing benchmarking file.")
+
+	scheduleFlag = flag.String("schedule", "", "write the gas schedule proposed by the results to this file.")
+	nsPerGasFlag = flag.Float64("ns-per-gas", 1, "the nanoseconds per unit of gas of the proposed gas schedule.")
 )
 
 // We dump the benchmark in bytes for speed and minimal overhead.
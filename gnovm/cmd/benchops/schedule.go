@@ -0,0 +1,73 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+
+	gno "github.com/gnolang/gno/gnovm/pkg/gnolang"
+)
+
+// storeGasCosts maps the store codes to the store gas costs they measure, by
+// GasConfig field name.
+var storeGasCosts = map[string]string{
+	"StoreGetObject":       "GasGetObject",
+	"StoreSetObject":       "GasSetObject",
+	"StoreGetType":         "GasGetType",
+	"StoreSetType":         "GasSetType",
+	"StoreGetPackageRealm": "GasGetPackageRealm",
+	"StoreSetPackageRealm": "GasSetPackageRealm",
+	"StoreAddMemPackage":   "GasAddMemPackage",
+	"StoreGetMemPackage":   "GasGetMemPackage",
+	"StoreDeleteObject":    "GasDeleteObject", // flat cost
+}
+
+// proposeSchedule returns the gas schedule proposed by the stats, with
+// nsPerGas nanoseconds per unit of gas. The CPU cycles of an op are its
+// average time, and the store gas costs are the average time per byte, or
+// the average time for GasDeleteObject.
+func proposeSchedule(css []codeStats, nsPerGas float64) gno.GasSchedule {
+	gas := func(ns float64) int64 {
+		return max(1, int64(math.Round(ns/nsPerGas)))
+	}
+	opNames := make(map[string]bool)
+	for _, name := range gno.OpNames() {
+		opNames[name] = true
+	}
+
+	gs := gno.GasSchedule{
+		CPU:   make(map[string]int64),
+		Store: make(map[string]int64),
+	}
+	for _, cs := range css {
+		if opNames[cs.codeName] {
+			gs.CPU[cs.codeName] = gas(float64(cs.avgTime))
+			continue
+		}
+		field, ok := storeGasCosts[cs.codeName]
+		if !ok {
+			continue
+		}
+		if field == "GasDeleteObject" || cs.avgSize == 0 {
+			gs.Store[field] = gas(float64(cs.avgTime))
+		} else {
+			gs.Store[field] = gas(float64(cs.avgTime) / float64(cs.avgSize))
+		}
+	}
+	return gs
+}
+
+// writeSchedule writes the gas schedule proposed by the stats as JSON, in
+// the format of the vm gas schedule params.
+func writeSchedule(filename string, css []codeStats) {
+	gs := proposeSchedule(css, *nsPerGasFlag)
+	bz, err := json.MarshalIndent(gs, "", "  ")
+	if err != nil {
+		panic("could not marshal gas schedule: " + err.Error())
+	}
+	if err := os.WriteFile(filename, append(bz, '\n'), 0o644); err != nil {
+		panic("could not create gas schedule file: " + err.Error())
+	}
+	fmt.Println("## Proposed gas schedule saved in:", filename)
+}
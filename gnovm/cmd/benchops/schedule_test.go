@@ -0,0 +1,24 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProposeSchedule(t *testing.T) {
+	css := []codeStats{
+		{codeName: "OpAdd", avgTime: 101},
+		{codeName: "OpHalt", avgTime: 0},
+		{codeName: "StoreGetObject", avgTime: 8000, avgSize: 400},
+		{codeName: "StoreDeleteObject", avgTime: 3000, avgSize: 100},
+		{codeName: "AminoMarshal", avgTime: 1000, avgSize: 100},
+	}
+	gs := proposeSchedule(css, 2)
+	assert.Equal(t, map[string]int64{"OpAdd": 51, "OpHalt": 1}, gs.CPU)
+	assert.Equal(t, map[string]int64{"GasGetObject": 10, "GasDeleteObject": 1500}, gs.Store)
+
+	_, _, err := gs.Costs()
+	require.NoError(t, err)
+}
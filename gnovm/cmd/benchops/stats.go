@@ -141,14 +141,20 @@ func calculateStats(crs []codeRecord) {
 		return keys[i] < keys[j]
 	})
 
+	css := make([]codeStats, 0, len(keys))
 	for _, k := range keys {
 		cs := calculate(k, m[k])
+		css = append(css, cs)
 		csv := cs.codeName + "," + fmt.Sprint(cs.avgTime) + "," + fmt.Sprint(cs.avgSize) + "," + fmt.Sprint(cs.timeStdDev) + "," + fmt.Sprint(cs.count)
 		fmt.Fprintln(out, csv)
 	}
 
 	fmt.Println("## Benchmark results saved in:", filename)
 	fmt.Println("## Benchmark result stats saved in:", out.Name())
+
+	if *scheduleFlag != "" {
+		writeSchedule(*scheduleFlag, css)
+	}
 }
 
 func addSuffix(filename string) string {
@@ -1,15 +1,20 @@
 package main
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"flag"
 	"fmt"
 	goio "io"
 	"log"
+	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
 	"time"
 
+	"github.com/gnolang/gno/gnovm"
 	"github.com/gnolang/gno/gnovm/pkg/gnoenv"
 	gno "github.com/gnolang/gno/gnovm/pkg/gnolang"
 	"github.com/gnolang/gno/gnovm/pkg/gnomod"
@@ -26,6 +31,14 @@ type testCfg struct {
 	updateGoldenTests   bool
 	printRuntimeMetrics bool
 	printEvents         bool
+	cover               bool
+	coverProfile        string
+	bench               string
+	benchTime           string
+	benchMem            bool
+	fuzz                string
+	fuzzTime            string
+	json                bool
 }
 
 func newTestCmd(io commands.IO) *commands.Command {
@@ -44,7 +57,7 @@ file pattern "*_test.gno" or "*_filetest.gno".
 The <package> can be directory or file path (relative or absolute).
 
 - "*_test.gno" files work like "*_test.go" files, but they contain only test
-functions. Benchmark and fuzz functions aren't supported yet. Similarly, only
+benchmark and fuzz functions. Similarly, only
 tests that belong to the same package are supported for now (no "xxx_test").
 
 The package path used to execute the "*_test.gno" file is fetched from the
@@ -85,6 +98,31 @@ of the GnoVM, acting as a "golden test":
 To speed up execution, imports of pure packages are processed separately from
 the execution of the tests. This makes testing faster, but means that the
 initialization of imported pure packages cannot be checked in filetests.
+
+The -bench flag runs the benchmarks matching the given regular expression,
+after the tests. Besides the time per iteration, benchmarks report the gas
+consumed per iteration and, with the -benchmem flag, the number of bytes and
+allocations of the VM per iteration.
+
+The -cover flag prints the percentage of statements of each tested package,
+and of each of its files, executed by its tests and filetests. The
+-coverprofile flag also writes the executed blocks of statements to a file,
+using the format of 'go test -coverprofile': it can then be viewed with
+'go tool cover -html=<file>'.
+
+Fuzz functions run the inputs of their seed corpus as subtests: the ones added
+with (*testing.F).Add, and the files of the testdata/fuzz/<FuzzName> directory,
+which use the same format as 'go test -fuzz'. The -fuzz flag then runs the fuzz
+function matching the given regular expression with generated inputs, until
+the -fuzztime is elapsed or an input makes it fail. The failing input is
+minimized and written to the testdata/fuzz directory, so that it's then run as
+a regression test. Only one package and one fuzz function can be fuzzed at a
+time.
+
+The -json flag prints the output of the tests as a stream of JSON events, like
+'go test -json' (see 'go doc test2json'). Besides the fields of test2json, the
+final events of top-level tests have "Gas" and "Events" fields, with the gas
+used and the events emitted by each test.
 `,
 		},
 		cfg,
@@ -143,6 +181,62 @@ func (c *testCfg) RegisterFlags(fs *flag.FlagSet) {
 		false,
 		"print emitted events",
 	)
+
+	fs.BoolVar(
+		&c.cover,
+		"cover",
+		false,
+		"enable coverage analysis, and print the statement coverage of each file",
+	)
+
+	fs.StringVar(
+		&c.coverProfile,
+		"coverprofile",
+		"",
+		"write a Go-compatible coverage profile to the given file (implies -cover)",
+	)
+
+	fs.StringVar(
+		&c.bench,
+		"bench",
+		"",
+		"run only the benchmarks matching the regular expression",
+	)
+
+	fs.StringVar(
+		&c.benchTime,
+		"benchtime",
+		"1s",
+		"run each benchmark for the given duration, or the given number of iterations with the Nx format",
+	)
+
+	fs.BoolVar(
+		&c.benchMem,
+		"benchmem",
+		false,
+		"print the memory allocations of benchmarks",
+	)
+
+	fs.StringVar(
+		&c.fuzz,
+		"fuzz",
+		"",
+		"fuzz the fuzz function matching the regular expression",
+	)
+
+	fs.StringVar(
+		&c.fuzzTime,
+		"fuzztime",
+		"",
+		"fuzz for the given duration, or the given number of executions with the Nx format (default: until a failing input is found)",
+	)
+
+	fs.BoolVar(
+		&c.json,
+		"json",
+		false,
+		"print the test events as JSON, like 'go test -json' (implies -v)",
+	)
 }
 
 func execTest(cfg *testCfg, args []string, io commands.IO) error {
@@ -155,6 +249,24 @@ func execTest(cfg *testCfg, args []string, io commands.IO) error {
 		cfg.rootDir = gnoenv.RootDir()
 	}
 
+	benchTime, err := test.ParseBenchTime(cfg.benchTime)
+	if err != nil {
+		return err
+	}
+
+	var fuzzTime test.BenchTime
+	if cfg.fuzzTime != "" {
+		fuzzTime, err = test.ParseBenchTime(cfg.fuzzTime)
+		if err != nil {
+			return err
+		}
+	}
+	if cfg.fuzz != "" {
+		if _, err := regexp.Compile(cfg.fuzz); err != nil {
+			return fmt.Errorf("invalid -fuzz regular expression: %w", err)
+		}
+	}
+
 	paths, err := targetsFromPatterns(args)
 	if err != nil {
 		return fmt.Errorf("list targets from patterns: %w", err)
@@ -175,24 +287,51 @@ func execTest(cfg *testCfg, args []string, io commands.IO) error {
 	if err != nil {
 		return fmt.Errorf("list sub packages: %w", err)
 	}
+	if cfg.fuzz != "" && len(subPkgs) > 1 {
+		return errors.New("cannot use -fuzz flag with multiple packages")
+	}
 
 	// Set up options to run tests.
-	stdout := goio.Discard
+	var stdout, stderr goio.Writer = goio.Discard, io.Err()
 	if cfg.verbose {
 		stdout = io.Out()
 	}
-	opts := test.NewTestOptions(cfg.rootDir, io.In(), stdout, io.Err())
+	var jw *test.JSONWriter
+	if cfg.json {
+		jw = test.NewJSONWriter(io.Out())
+		stdout, stderr = jw, jw
+		cfg.verbose = true
+	}
+	opts := test.NewTestOptions(cfg.rootDir, io.In(), stdout, stderr)
 	opts.RunFlag = cfg.run
 	opts.Sync = cfg.updateGoldenTests
 	opts.Verbose = cfg.verbose
 	opts.Metrics = cfg.printRuntimeMetrics
 	opts.Events = cfg.printEvents
+	opts.Bench = cfg.bench
+	opts.BenchTime = benchTime
+	opts.BenchMem = cfg.benchMem
+	opts.Fuzz = cfg.fuzz
+	opts.FuzzTime = fuzzTime
+	opts.JSON = jw
+
+	var profile bytes.Buffer
+	if cfg.coverProfile != "" {
+		cfg.cover = true
+		fmt.Fprintf(&profile, "mode: %s\n", test.CoverMode)
+	}
 
 	buildErrCount := 0
 	testErrCount := 0
 	for _, pkg := range subPkgs {
+		if jw != nil {
+			jw.StartPackage(pkg.Dir)
+		}
 		if len(pkg.TestGnoFiles) == 0 && len(pkg.FiletestGnoFiles) == 0 {
-			io.ErrPrintfln("?       %s \t[no test files]", pkg.Dir)
+			fmt.Fprintf(stderr, "?       %s \t[no test files]\n", pkg.Dir)
+			if jw != nil {
+				jw.EndPackage(test.ActionSkip, 0)
+			}
 			continue
 		}
 		// Determine gnoPkgPath by reading gno.mod
@@ -204,33 +343,56 @@ func execTest(cfg *testCfg, args []string, io commands.IO) error {
 			gnoPkgPath = pkgPathFromRootDir(pkg.Dir, cfg.rootDir)
 			if gnoPkgPath == "" {
 				// unable to read pkgPath from gno.mod, generate a random realm path
-				io.ErrPrintfln("--- WARNING: unable to read package path from gno.mod or gno root directory; try creating a gno.mod file")
+				fmt.Fprintln(stderr, "--- WARNING: unable to read package path from gno.mod or gno root directory; try creating a gno.mod file")
 				gnoPkgPath = "gno.land/r/" + strings.ToLower(random.RandStr(8)) // XXX: gno.land hardcoded for convenience.
 			}
 		}
 
 		memPkg := gno.MustReadMemPackage(pkg.Dir, gnoPkgPath)
 
+		if cfg.cover {
+			opts.Coverage = gno.NewCoverage()
+		}
+
 		startedAt := time.Now()
-		hasError := catchRuntimeError(gnoPkgPath, io.Err(), func() {
+		hasError := catchRuntimeError(gnoPkgPath, stderr, func() {
 			err = test.Test(memPkg, pkg.Dir, opts)
 		})
 
 		duration := time.Since(startedAt)
 		dstr := fmtDuration(duration)
 
+		if cfg.cover {
+			if cerr := printCoverage(memPkg, pkg.Dir, opts.Coverage, &profile, stderr); cerr != nil {
+				fmt.Fprintf(stderr, "%s: coverage: %v\n", pkg.Dir, cerr)
+			}
+		}
+
 		if hasError || err != nil {
 			if err != nil {
-				io.ErrPrintfln("%s: test pkg: %v", pkg.Dir, err)
+				fmt.Fprintf(stderr, "%s: test pkg: %v\n", pkg.Dir, err)
 			}
-			io.ErrPrintfln("FAIL")
-			io.ErrPrintfln("FAIL    %s \t%s", pkg.Dir, dstr)
-			io.ErrPrintfln("FAIL")
+			fmt.Fprintln(stderr, "FAIL")
+			fmt.Fprintf(stderr, "FAIL    %s \t%s\n", pkg.Dir, dstr)
+			fmt.Fprintln(stderr, "FAIL")
 			testErrCount++
+			if jw != nil {
+				jw.EndPackage(test.ActionFail, duration)
+			}
 		} else {
-			io.ErrPrintfln("ok      %s \t%s", pkg.Dir, dstr)
+			fmt.Fprintf(stderr, "ok      %s \t%s\n", pkg.Dir, dstr)
+			if jw != nil {
+				jw.EndPackage(test.ActionPass, duration)
+			}
 		}
 	}
+
+	if cfg.coverProfile != "" {
+		if err := os.WriteFile(cfg.coverProfile, profile.Bytes(), 0o644); err != nil {
+			return fmt.Errorf("unable to write coverage profile: %w", err)
+		}
+	}
+
 	if testErrCount > 0 || buildErrCount > 0 {
 		io.ErrPrintfln("FAIL")
 		return fmt.Errorf("FAIL: %d build errors, %d test errors", buildErrCount, testErrCount)
@@ -239,6 +401,34 @@ func execTest(cfg *testCfg, args []string, io commands.IO) error {
 	return nil
 }
 
+// printCoverage prints the statement coverage of memPkg and of each of its
+// files, and appends its blocks to the cover profile.
+func printCoverage(memPkg *gnovm.MemPackage, dir string, cov *gno.Coverage, profile, w goio.Writer) error {
+	pc, err := test.Coverage(memPkg, cov)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "coverage: %s\n", fmtCoverage(pc.Statements()))
+	for _, fc := range pc.Files {
+		fmt.Fprintf(w, "\t%s: %s\n", fc.Name, fmtCoverage(fc.Statements()))
+	}
+
+	// go tool cover resolves absolute file names without a go.mod
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return err
+	}
+	return pc.WriteProfile(profile, absDir)
+}
+
+func fmtCoverage(executed, total int) string {
+	if total == 0 {
+		return "[no statements]"
+	}
+	return fmt.Sprintf("%.1f%% of statements", 100*float64(executed)/float64(total))
+}
+
 // attempts to determine the full gno pkg path by analyzing the directory.
 func pkgPathFromRootDir(pkgPath, rootDir string) string {
 	abPkgPath, err := filepath.Abs(pkgPath)
@@ -9,6 +9,7 @@ import "reflect"
 type Allocator struct {
 	maxBytes int64
 	bytes    int64
+	allocs   int64 // number of allocations, for benchmarks
 }
 
 // for gonative, which doesn't consider the allocator.
@@ -26,6 +27,7 @@ const (
 	_allocSliceValue       = 40
 	_allocFuncValue        = 136
 	_allocMapValue         = 144
+	_allocChanValue        = 88
 	_allocBoundMethodValue = 176
 	_allocBlock            = 464
 	_allocNativeValue      = 48
@@ -53,6 +55,8 @@ const (
 	allocFunc        = _allocBase + _allocPointer + _allocFuncValue
 	allocMap         = _allocBase + _allocPointer + _allocMapValue
 	allocMapItem     = _allocTypedValue * 3 // XXX
+	allocChan        = _allocBase + _allocPointer + _allocChanValue
+	allocChanItem    = _allocTypedValue
 	allocBoundMethod = _allocBase + _allocPointer + _allocBoundMethodValue
 	allocBlock       = _allocBase + _allocPointer + _allocBlock
 	allocBlockItem   = _allocTypedValue
@@ -78,11 +82,20 @@ func (alloc *Allocator) Status() (maxBytes int64, bytes int64) {
 	return alloc.maxBytes, alloc.bytes
 }
 
+// Allocations returns the number of allocations since the last reset.
+func (alloc *Allocator) Allocations() int64 {
+	if alloc == nil {
+		return 0
+	}
+	return alloc.allocs
+}
+
 func (alloc *Allocator) Reset() *Allocator {
 	if alloc == nil {
 		return nil
 	}
 	alloc.bytes = 0
+	alloc.allocs = 0
 	return alloc
 }
 
@@ -93,6 +106,7 @@ func (alloc *Allocator) Fork() *Allocator {
 	return &Allocator{
 		maxBytes: alloc.maxBytes,
 		bytes:    alloc.bytes,
+		allocs:   alloc.allocs,
 	}
 }
 
@@ -103,6 +117,7 @@ func (alloc *Allocator) Allocate(size int64) {
 	}
 
 	alloc.bytes += size
+	alloc.allocs++
 	if alloc.bytes > alloc.maxBytes {
 		panic("allocation limit exceeded")
 	}
@@ -145,6 +160,10 @@ func (alloc *Allocator) AllocateMap(items int64) {
 	alloc.Allocate(allocMap + allocMapItem*items)
 }
 
+func (alloc *Allocator) AllocateChan(items int64) {
+	alloc.Allocate(allocChan + allocChanItem*items)
+}
+
 func (alloc *Allocator) AllocateMapItem() {
 	alloc.Allocate(allocMapItem)
 }
@@ -294,6 +313,14 @@ func (alloc *Allocator) NewMap(size int) *MapValue {
 	return mv
 }
 
+func (alloc *Allocator) NewChan(size int) *ChanValue {
+	alloc.AllocateChan(int64(size))
+	return &ChanValue{
+		Buffer: make([]TypedValue, 0, size),
+		Cap:    size,
+	}
+}
+
 func (alloc *Allocator) NewBlock(source BlockNode, parent *Block) *Block {
 	alloc.AllocateBlock(int64(source.GetNumNames()))
 	return NewBlock(source, parent)
@@ -0,0 +1,49 @@
+package gnolang
+
+// Coverage keeps track of the statements executed by a Machine, identified
+// by their source location, to produce code coverage reports.
+// A single Coverage can be shared by multiple machines, as long as they are
+// not running concurrently.
+type Coverage struct {
+	executed map[Location]struct{}
+}
+
+// NewCoverage returns a new, empty Coverage.
+func NewCoverage() *Coverage {
+	return &Coverage{
+		executed: make(map[Location]struct{}),
+	}
+}
+
+// Executed returns whether the statement starting at the given line and
+// column of the file has been executed.
+func (c *Coverage) Executed(pkgPath, file string, line, column int) bool {
+	_, ok := c.executed[Location{
+		PkgPath: pkgPath,
+		File:    file,
+		Line:    line,
+		Column:  column,
+	}]
+	return ok
+}
+
+// Count returns the number of distinct statements executed so far.
+func (c *Coverage) Count() int {
+	return len(c.executed)
+}
+
+// recordStmt marks the statement s, about to be executed in the last block
+// of m, as executed. Statements without a location, such as the ones
+// generated during preprocessing, are ignored.
+func (c *Coverage) recordStmt(m *Machine, s Stmt) {
+	line, column := s.GetLine(), s.GetColumn()
+	if line <= 0 || column <= 0 {
+		return
+	}
+	loc := m.LastBlock().Source.GetLocation()
+	if loc.File == "" {
+		return
+	}
+	loc.Line, loc.Column = line, column
+	c.executed[loc] = struct{}{}
+}
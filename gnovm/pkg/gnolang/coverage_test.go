@@ -0,0 +1,46 @@
+package gnolang
+
+import (
+	"testing"
+
+	"github.com/gnolang/gno/gnovm"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCoverage(t *testing.T) {
+	t.Parallel()
+
+	cov := NewCoverage()
+	m := NewMachineWithOptions(MachineOptions{
+		PkgPath:  "gno.land/p/demo/cov",
+		Coverage: cov,
+	})
+	defer m.Release()
+
+	m.RunMemPackage(&gnovm.MemPackage{
+		Name: "cov",
+		Path: "gno.land/p/demo/cov",
+		Files: []*gnovm.MemFile{
+			{Name: "cov.gno", Body: `package cov
+
+func Abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+`},
+		},
+	}, false)
+	m.RunStatement(S(Call(X("Abs"), 1)))
+
+	executed := func(line, column int) bool {
+		return cov.Executed("gno.land/p/demo/cov", "cov.gno", line, column)
+	}
+
+	assert.True(t, executed(4, 2))  // if x < 0
+	assert.False(t, executed(5, 3)) // return -x
+	assert.True(t, executed(7, 2))  // return x
+	assert.False(t, cov.Executed("gno.land/p/demo/cov", "other.gno", 4, 2))
+	assert.Equal(t, 2, cov.Count())
+}
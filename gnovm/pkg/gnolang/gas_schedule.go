@@ -0,0 +1,187 @@
+package gnolang
+
+import (
+	"fmt"
+	"sort"
+)
+
+// CPUCosts are the CPU cycles charged for the execution of each Op.
+type CPUCosts [256]int64
+
+// DefaultCPUCosts returns the default CPU cycles of the ops, given by the
+// OpCPU constants.
+func DefaultCPUCosts() *CPUCosts {
+	cc := defaultCPUCosts
+	return &cc
+}
+
+var defaultCPUCosts = CPUCosts{
+	OpHalt:                OpCPUHalt,
+	OpNoop:                OpCPUNoop,
+	OpExec:                OpCPUExec,
+	OpPrecall:             OpCPUPrecall,
+	OpCall:                OpCPUCall,
+	OpCallNativeBody:      OpCPUCallNativeBody,
+	OpReturn:              OpCPUReturn,
+	OpReturnFromBlock:     OpCPUReturnFromBlock,
+	OpReturnToBlock:       OpCPUReturnToBlock,
+	OpDefer:               OpCPUDefer,
+	OpCallDeferNativeBody: OpCPUCallDeferNativeBody,
+	OpGo:                  OpCPUGo,
+	OpSelect:              OpCPUSelect,
+	OpSwitchClause:        OpCPUSwitchClause,
+	OpSwitchClauseCase:    OpCPUSwitchClauseCase,
+	OpTypeSwitch:          OpCPUTypeSwitch,
+	OpIfCond:              OpCPUIfCond,
+	OpPopValue:            OpCPUPopValue,
+	OpPopResults:          OpCPUPopResults,
+	OpPopBlock:            OpCPUPopBlock,
+	OpPopFrameAndReset:    OpCPUPopFrameAndReset,
+	OpPanic1:              OpCPUPanic1,
+	OpPanic2:              OpCPUPanic2,
+	OpGoexit:              OpCPUGoexit,
+	OpUpos:                OpCPUUpos,
+	OpUneg:                OpCPUUneg,
+	OpUnot:                OpCPUUnot,
+	OpUxor:                OpCPUUxor,
+	OpUrecv:               OpCPUUrecv,
+	OpLor:                 OpCPULor,
+	OpLand:                OpCPULand,
+	OpEql:                 OpCPUEql,
+	OpNeq:                 OpCPUNeq,
+	OpLss:                 OpCPULss,
+	OpLeq:                 OpCPULeq,
+	OpGtr:                 OpCPUGtr,
+	OpGeq:                 OpCPUGeq,
+	OpAdd:                 OpCPUAdd,
+	OpSub:                 OpCPUSub,
+	OpBor:                 OpCPUBor,
+	OpXor:                 OpCPUXor,
+	OpMul:                 OpCPUMul,
+	OpQuo:                 OpCPUQuo,
+	OpRem:                 OpCPURem,
+	OpShl:                 OpCPUShl,
+	OpShr:                 OpCPUShr,
+	OpBand:                OpCPUBand,
+	OpBandn:               OpCPUBandn,
+	OpEval:                OpCPUEval,
+	OpBinary1:             OpCPUBinary1,
+	OpIndex1:              OpCPUIndex1,
+	OpIndex2:              OpCPUIndex2,
+	OpSelector:            OpCPUSelector,
+	OpSlice:               OpCPUSlice,
+	OpStar:                OpCPUStar,
+	OpRef:                 OpCPURef,
+	OpTypeAssert1:         OpCPUTypeAssert1,
+	OpTypeAssert2:         OpCPUTypeAssert2,
+	OpStaticTypeOf:        OpCPUStaticTypeOf,
+	OpCompositeLit:        OpCPUCompositeLit,
+	OpArrayLit:            OpCPUArrayLit,
+	OpSliceLit:            OpCPUSliceLit,
+	OpSliceLit2:           OpCPUSliceLit2,
+	OpMapLit:              OpCPUMapLit,
+	OpStructLit:           OpCPUStructLit,
+	OpFuncLit:             OpCPUFuncLit,
+	OpConvert:             OpCPUConvert,
+	OpArrayLitGoNative:    OpCPUArrayLitGoNative,
+	OpSliceLitGoNative:    OpCPUSliceLitGoNative,
+	OpStructLitGoNative:   OpCPUStructLitGoNative,
+	OpCallGoNative:        OpCPUCallGoNative,
+	OpFieldType:           OpCPUFieldType,
+	OpArrayType:           OpCPUArrayType,
+	OpSliceType:           OpCPUSliceType,
+	OpInterfaceType:       OpCPUInterfaceType,
+	OpChanType:            OpCPUChanType,
+	OpFuncType:            OpCPUFuncType,
+	OpMapType:             OpCPUMapType,
+	OpStructType:          OpCPUStructType,
+	OpMaybeNativeType:     OpCPUMaybeNativeType,
+	OpAssign:              OpCPUAssign,
+	OpAddAssign:           OpCPUAddAssign,
+	OpSubAssign:           OpCPUSubAssign,
+	OpMulAssign:           OpCPUMulAssign,
+	OpQuoAssign:           OpCPUQuoAssign,
+	OpRemAssign:           OpCPURemAssign,
+	OpBandAssign:          OpCPUBandAssign,
+	OpBandnAssign:         OpCPUBandnAssign,
+	OpBorAssign:           OpCPUBorAssign,
+	OpXorAssign:           OpCPUXorAssign,
+	OpShlAssign:           OpCPUShlAssign,
+	OpShrAssign:           OpCPUShrAssign,
+	OpDefine:              OpCPUDefine,
+	OpInc:                 OpCPUInc,
+	OpDec:                 OpCPUDec,
+	OpSend:                OpCPUSend,
+	OpValueDecl:           OpCPUValueDecl,
+	OpTypeDecl:            OpCPUTypeDecl,
+	OpBody:                OpCPUBody,
+	OpForLoop:             OpCPUForLoop,
+	OpRangeIter:           OpCPURangeIter,
+	OpRangeIterString:     OpCPURangeIterString,
+	OpRangeIterMap:        OpCPURangeIterMap,
+	OpRangeIterArrayPtr:   OpCPURangeIterArrayPtr,
+	OpReturnCallDefers:    OpCPUReturnCallDefers,
+	OpRangeIterChan:       OpCPURangeIterChan,
+}
+
+// opsByName maps the names of the ops charged for CPU cycles, like "OpAdd",
+// to the ops.
+var opsByName = func() map[string]Op {
+	ops := make(map[string]Op)
+	for i, cycles := range defaultCPUCosts {
+		if cycles != 0 {
+			ops[Op(i).String()] = Op(i)
+		}
+	}
+	return ops
+}()
+
+// OpNames returns the names of the ops charged for CPU cycles, like "OpAdd",
+// in alphabetical order.
+func OpNames() []string {
+	names := make([]string, 0, len(opsByName))
+	for name := range opsByName {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// GasSchedule is a set of gas costs of the VM, overriding the default CPU
+// cycles of the ops and the default gas config of the store.
+type GasSchedule struct {
+	// CPU are the CPU cycles of the ops, by op name, like "OpAdd".
+	CPU map[string]int64 `json:"cpu,omitempty"`
+	// Store are the gas costs of the store, by GasConfig field name, like
+	// "GasGetObject".
+	Store map[string]int64 `json:"store,omitempty"`
+}
+
+// Costs returns the CPU costs and the store gas config of the schedule, the
+// default ones for the costs it does not override.
+func (gs GasSchedule) Costs() (*CPUCosts, GasConfig, error) {
+	cpu := DefaultCPUCosts()
+	for name, cycles := range gs.CPU {
+		op, ok := opsByName[name]
+		if !ok {
+			return nil, GasConfig{}, fmt.Errorf("unknown op %q", name)
+		}
+		if cycles <= 0 {
+			return nil, GasConfig{}, fmt.Errorf("invalid cycles %d for op %s", cycles, name)
+		}
+		cpu[op] = cycles
+	}
+	gc := DefaultGasConfig()
+	fields := gc.fields()
+	for name, gas := range gs.Store {
+		field, ok := fields[name]
+		if !ok {
+			return nil, GasConfig{}, fmt.Errorf("unknown store gas cost %q", name)
+		}
+		if gas < 0 {
+			return nil, GasConfig{}, fmt.Errorf("invalid gas %d for %s", gas, name)
+		}
+		*field = gas
+	}
+	return cpu, gc, nil
+}
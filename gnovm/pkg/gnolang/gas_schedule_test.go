@@ -0,0 +1,39 @@
+package gnolang
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGasScheduleCosts(t *testing.T) {
+	cpu, gc, err := GasSchedule{}.Costs()
+	require.NoError(t, err)
+	assert.Equal(t, DefaultCPUCosts(), cpu)
+	assert.Equal(t, DefaultGasConfig(), gc)
+	assert.Len(t, OpNames(), len(opsByName))
+	assert.Contains(t, OpNames(), "OpAdd")
+
+	cpu, gc, err = GasSchedule{
+		CPU:   map[string]int64{"OpAdd": 100, "OpCall": 1},
+		Store: map[string]int64{"GasGetObject": 32, "GasDeleteObject": 0},
+	}.Costs()
+	require.NoError(t, err)
+	assert.Equal(t, int64(100), cpu[OpAdd])
+	assert.Equal(t, int64(1), cpu[OpCall])
+	assert.Equal(t, int64(OpCPUSub), cpu[OpSub])
+	assert.Equal(t, int64(32), gc.GasGetObject)
+	assert.Equal(t, int64(0), gc.GasDeleteObject)
+	assert.Equal(t, DefaultGasConfig().GasSetObject, gc.GasSetObject)
+
+	for _, gs := range []GasSchedule{
+		{CPU: map[string]int64{"OpFoo": 1}},
+		{CPU: map[string]int64{"OpAdd": 0}},
+		{Store: map[string]int64{"GasFoo": 1}},
+		{Store: map[string]int64{"GasGetObject": -1}},
+	} {
+		_, _, err := gs.Costs()
+		assert.Error(t, err, "%v", gs)
+	}
+}
@@ -332,6 +332,11 @@ func Go2Gno(fs *token.FileSet, gon ast.Node) (n Node) {
 		return &DeferStmt{
 			Call: *cx,
 		}
+	case *ast.GoStmt:
+		cx := toExpr(fs, gon.Call).(*CallExpr)
+		return &GoStmt{
+			Call: *cx,
+		}
 	case *ast.ExprStmt:
 		if cx, ok := gon.X.(*ast.CallExpr); ok {
 			if ix, ok := cx.Fun.(*ast.Ident); ok && ix.Name == "panic" {
@@ -399,6 +404,15 @@ func Go2Gno(fs *token.FileSet, gon ast.Node) (n Node) {
 		return &ReturnStmt{
 			Results: toExprs(fs, gon.Results),
 		}
+	case *ast.SelectStmt:
+		return &SelectStmt{
+			Cases: toSelectCases(fs, gon.Body.List),
+		}
+	case *ast.SendStmt:
+		return &SendStmt{
+			Chan:  toExpr(fs, gon.Chan),
+			Value: toExpr(fs, gon.Value),
+		}
 	case *ast.TypeSwitchStmt:
 		switch as := gon.Assign.(type) {
 		case *ast.AssignStmt:
@@ -497,6 +511,9 @@ type MemPackageGetter interface {
 // mempkg. To retrieve dependencies, it uses getter.
 //
 // The syntax checking is performed entirely using Go's go/types package.
+// Concurrency constructs (go statements, channels and select statements) are
+// reported as errors: they are only supported when running code locally, with
+// `gno run` and `gno test`.
 //
 // If format is true, the code will be automatically updated with the
 // formatted source code.
@@ -505,7 +522,7 @@ func TypeCheckMemPackage(mempkg *gnovm.MemPackage, getter MemPackageGetter, form
 }
 
 // TypeCheckMemPackageTest performs the same type checks as [TypeCheckMemPackage],
-// but allows re-declarations.
+// but allows re-declarations and concurrency constructs.
 //
 // Note: like TypeCheckMemPackage, this function ignores tests and filetests.
 func TypeCheckMemPackageTest(mempkg *gnovm.MemPackage, getter MemPackageGetter) error {
@@ -523,6 +540,7 @@ func typeCheckMemPackage(mempkg *gnovm.MemPackage, getter MemPackageGetter, test
 			},
 		},
 		allowRedefinitions: testing,
+		allowConcurrency:   testing,
 	}
 	imp.cfg.Importer = imp
 
@@ -547,6 +565,8 @@ type gnoImporter struct {
 
 	// allow symbol redefinitions? (test standard libraries)
 	allowRedefinitions bool
+	// allow concurrency constructs? (gno lint and gno test)
+	allowConcurrency bool
 }
 
 // Unused, but satisfies the Importer interface.
@@ -627,9 +647,48 @@ func (g *gnoImporter) parseCheckMemPackage(mpkg *gnovm.MemPackage, fmt bool) (*t
 		return nil, errs
 	}
 
+	if !g.allowConcurrency {
+		for _, f := range files {
+			checkConcurrency(fset, f, g.cfg.Error)
+		}
+	}
+
 	return g.cfg.Check(mpkg.Path, fset, files, nil)
 }
 
+// checkConcurrency calls report with a positioned error for each concurrency
+// construct used in f.
+func checkConcurrency(fset *token.FileSet, f *ast.File, report func(error)) {
+	if report == nil {
+		return
+	}
+	ast.Inspect(f, func(n ast.Node) bool {
+		var msg string
+		switch n := n.(type) {
+		case *ast.GoStmt:
+			msg = "go statement not supported"
+		case *ast.SelectStmt:
+			msg = "select statement not supported"
+		case *ast.SendStmt:
+			msg = "channel send not supported"
+		case *ast.UnaryExpr:
+			if n.Op == token.ARROW {
+				msg = "channel receive not supported"
+			}
+		case *ast.ChanType:
+			msg = "channel type not supported"
+		}
+		if msg != "" {
+			report(types.Error{
+				Fset: fset,
+				Pos:  n.Pos(),
+				Msg:  msg,
+			})
+		}
+		return true
+	})
+}
+
 func deleteOldIdents(idents map[string]func(), f *ast.File) {
 	for _, decl := range f.Decls {
 		fd, ok := decl.(*ast.FuncDecl)
@@ -970,6 +1029,26 @@ func toClauses(fs *token.FileSet, csz []ast.Stmt) []SwitchClauseStmt {
 	return res
 }
 
+func toSelectCases(fs *token.FileSet, csz []ast.Stmt) []SelectCaseStmt {
+	res := make([]SelectCaseStmt, len(csz))
+	hasDefault := false
+	for i, cs := range csz {
+		cc := cs.(*ast.CommClause)
+		if cc.Comm == nil {
+			if hasDefault {
+				panic("multiple defaults in select")
+			}
+			hasDefault = true
+		}
+		res[i] = SelectCaseStmt{
+			Comm: toStmt(fs, cc.Comm),
+			Body: toStmts(fs, cc.Body),
+		}
+		setLoc(fs, cc.Pos(), &res[i])
+	}
+	return res
+}
+
 func toSwitchClauseStmt(fs *token.FileSet, cc *ast.CaseClause) SwitchClauseStmt {
 	return SwitchClauseStmt{
 		Cases: toExprs(fs, cc.List),
@@ -120,6 +120,36 @@ func TestTypeCheckMemPackage(t *testing.T) {
 			nil,
 			errContains("cannot use 11"),
 		},
+		{
+			"Concurrency",
+			&gnovm.MemPackage{
+				Name: "hello",
+				Path: "gno.land/p/demo/hello",
+				Files: []*gnovm.MemFile{
+					{
+						Name: "hello.gno",
+						Body: `
+							package hello
+							func A() int {
+								ch := make(chan int, 1)
+								go func() { ch <- 1 }()
+								select {
+								case v := <-ch:
+									return v
+								}
+							}`,
+					},
+				},
+			},
+			nil,
+			errContains(
+				"hello.gno:4:20: channel type not supported",
+				"hello.gno:5:9: go statement not supported",
+				"hello.gno:5:21: channel send not supported",
+				"hello.gno:6:9: select statement not supported",
+				"hello.gno:7:19: channel receive not supported",
+			),
+		},
 		{
 			"ParseError",
 			&gnovm.MemPackage{
@@ -377,3 +407,30 @@ func Hello(name string) string {
 	assert.NotEqual(t, input, pkg.Files[0].Body)
 	assert.Equal(t, expected, pkg.Files[0].Body)
 }
+
+func TestTypeCheckMemPackageTest_concurrency(t *testing.T) {
+	t.Parallel()
+
+	pkg := &gnovm.MemPackage{
+		Name: "hello",
+		Path: "gno.land/p/demo/hello",
+		Files: []*gnovm.MemFile{
+			{
+				Name: "hello.gno",
+				Body: `
+					package hello
+					func A() int {
+						ch := make(chan int, 1)
+						go func() { ch <- 1 }()
+						return <-ch
+					}`,
+			},
+		},
+	}
+
+	// Concurrency is only supported off-chain.
+	err := TypeCheckMemPackageTest(pkg, mockPackageGetter{})
+	assert.NoError(t, err)
+	err = TypeCheckMemPackage(pkg, mockPackageGetter{}, false)
+	assert.ErrorContains(t, err, "go statement not supported")
+}
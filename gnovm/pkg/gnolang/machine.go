@@ -62,7 +62,10 @@ type Machine struct {
 	NumResults int   // number of results returned
 	Cycles     int64 // number of "cpu" cycles
 
+	sched scheduler // goroutines started by go statements
+
 	Debugger Debugger
+	Coverage *Coverage // records executed statements, if set
 
 	// Configuration
 	PreprocessorMode bool // this is used as a flag when const values are evaluated during preprocessing
@@ -72,6 +75,7 @@ type Machine struct {
 	Store            Store
 	Context          interface{}
 	GasMeter         store.GasMeter
+	cpuCosts         *CPUCosts
 	// PanicScope is incremented each time a panic occurs and is reset to
 	// zero when it is recovered.
 	PanicScope uint
@@ -113,6 +117,8 @@ type MachineOptions struct {
 	MaxAllocBytes    int64      // or 0 for no limit.
 	MaxCycles        int64      // or 0 for no limit.
 	GasMeter         store.GasMeter
+	CPUCosts         *CPUCosts // default DefaultCPUCosts().
+	Coverage         *Coverage // records executed statements, if set.
 }
 
 // the machine constructor gets spammed
@@ -174,9 +180,14 @@ func NewMachineWithOptions(opts MachineOptions) *Machine {
 	mm.Store = store
 	mm.Context = context
 	mm.GasMeter = vmGasMeter
+	mm.cpuCosts = opts.CPUCosts
+	if mm.cpuCosts == nil {
+		mm.cpuCosts = &defaultCPUCosts
+	}
 	mm.Debugger.enabled = opts.Debug
 	mm.Debugger.in = opts.Input
 	mm.Debugger.out = output
+	mm.Coverage = opts.Coverage
 
 	if pv != nil {
 		mm.SetActivePackage(pv)
@@ -198,6 +209,8 @@ var (
 // and m should not be used after this call. Only Machines initialized with this
 // package's constructors should be released.
 func (m *Machine) Release() {
+	// the stacks to reuse are the ones of the main goroutine.
+	m.restoreMain()
 	// here we zero in the values for the next user
 	m.NumOps = 0
 	m.NumValues = 0
@@ -458,6 +471,14 @@ func (m *Machine) RunFiles(fns ...*FileNode) {
 // This will also run each init function encountered.
 // Returns the updated typed values of package.
 func (m *Machine) runFileDecls(fns ...*FileNode) []TypedValue {
+	return m.runFileDeclsKeeping(nil, fns...)
+}
+
+// runFileDeclsKeeping is like runFileDecls, but the package-level variables
+// in kept are set to their kept value instead of running their initializers.
+// The other variables of their declarations must be kept too, see
+// keepValueDecl.
+func (m *Machine) runFileDeclsKeeping(kept map[Name]TypedValue, fns ...*FileNode) []TypedValue {
 	// Files' package names must match the machine's active one.
 	// if there is one.
 	for _, fn := range fns {
@@ -523,6 +544,18 @@ func (m *Machine) runFileDecls(fns ...*FileNode) []TypedValue {
 	// recursive function for var declarations.
 	var runDeclarationFor func(fn *FileNode, decl Decl)
 	runDeclarationFor = func(fn *FileNode, decl Decl) {
+		// set kept variables, without running initializers.
+		if vd, ok := decl.(*ValueDecl); ok && !vd.Const && keepValueDecl(kept, vd) {
+			for _, nx := range vd.NameExprs {
+				if nx.Name == blankIdentifier {
+					continue
+				}
+				idx, _ := pn.GetLocalIndex(nx.Name)
+				pb.Values[idx] = kept[nx.Name]
+				fdeclared[nx.Name] = struct{}{}
+			}
+			return
+		}
 		// get fileblock of fn.
 		// fb := pv.GetFileBlock(nil, fn.Name)
 		// get dependencies of decl.
@@ -844,6 +877,30 @@ func (m *Machine) RunDeclaration(d Decl) {
 // Declarations to be run within a body (not at the file or
 // package level, for which evaluations happen during
 // preprocessing).
+// keepValueDecl returns whether the variables of vd are in kept, so that its
+// initializers are not run. It panics if only some of them are.
+func keepValueDecl(kept map[Name]TypedValue, vd *ValueDecl) bool {
+	var keptNames, otherNames []string
+	for _, nx := range vd.NameExprs {
+		if nx.Name == blankIdentifier {
+			continue
+		}
+		if _, ok := kept[nx.Name]; ok {
+			keptNames = append(keptNames, string(nx.Name))
+		} else {
+			otherNames = append(otherNames, string(nx.Name))
+		}
+	}
+	if len(keptNames) == 0 {
+		return false
+	}
+	if len(otherNames) > 0 {
+		panic(fmt.Sprintf("var %s must be declared apart from the kept var %s",
+			strings.Join(otherNames, ", "), strings.Join(keptNames, ", ")))
+	}
+	return true
+}
+
 func (m *Machine) runDeclaration(d Decl) {
 	switch d := d.(type) {
 	case *FuncDecl:
@@ -897,6 +954,7 @@ const (
 	OpPopFrameAndReset    Op = 0x15 // pop frame and reset.
 	OpPanic1              Op = 0x16 // pop exception and pop call frames.
 	OpPanic2              Op = 0x17 // pop call frames.
+	OpGoexit              Op = 0x18 // end of goroutine
 
 	/* Unary & binary operators */
 	OpUpos  Op = 0x20 // + (unary)
@@ -979,6 +1037,7 @@ const (
 	OpDefine      Op = 0x8C // X... := Y...
 	OpInc         Op = 0x8D // X++
 	OpDec         Op = 0x8E // X--
+	OpSend        Op = 0x8F // X <- Y
 
 	/* Decl operators */
 	OpValueDecl Op = 0x90 // var/const ...
@@ -993,6 +1052,7 @@ const (
 	OpRangeIterMap      Op = 0xD5
 	OpRangeIterArrayPtr Op = 0xD6
 	OpReturnCallDefers  Op = 0xD7 // TODO rename?
+	OpRangeIterChan     Op = 0xD8
 	OpVoid              Op = 0xFF // For profiling simple operation
 )
 
@@ -1028,8 +1088,8 @@ const (
 	OpCPUReturnToBlock       = 23
 	OpCPUDefer               = 64
 	OpCPUCallDeferNativeBody = 33
-	OpCPUGo                  = 1 // Not yet implemented
-	OpCPUSelect              = 1 // Not yet implemented
+	OpCPUGo                  = 64 // XXX not benchmarked
+	OpCPUSelect              = 50 // XXX not benchmarked
 	OpCPUSwitchClause        = 38
 	OpCPUSwitchClauseCase    = 143
 	OpCPUTypeSwitch          = 171
@@ -1040,13 +1100,14 @@ const (
 	OpCPUPopFrameAndReset    = 15
 	OpCPUPanic1              = 121
 	OpCPUPanic2              = 21
+	OpCPUGoexit              = 20 // XXX not benchmarked
 
 	/* Unary & binary operators */
 	OpCPUUpos  = 7
 	OpCPUUneg  = 25
 	OpCPUUnot  = 6
 	OpCPUUxor  = 14
-	OpCPUUrecv = 1 // Not yet implemented
+	OpCPUUrecv = 30 // XXX not benchmarked
 	OpCPULor   = 26
 	OpCPULand  = 24
 	OpCPUEql   = 160
@@ -1124,6 +1185,7 @@ const (
 	OpCPUDefine      = 111
 	OpCPUInc         = 76
 	OpCPUDec         = 46
+	OpCPUSend        = 30 // XXX not benchmarked
 
 	/* Decl operators */
 	OpCPUValueDecl = 113
@@ -1138,6 +1200,7 @@ const (
 	OpCPURangeIterMap      = 48
 	OpCPURangeIterArrayPtr = 46
 	OpCPUReturnCallDefers  = 78
+	OpCPURangeIterChan     = 48 // XXX not benchmarked
 )
 
 //----------------------------------------
@@ -1182,324 +1245,333 @@ func (m *Machine) Run() {
 		switch op {
 		/* Control operators */
 		case OpHalt:
-			m.incrCPU(OpCPUHalt)
+			m.incrCPU(m.cpuCosts[OpHalt])
 			if bm.OpsEnabled {
 				bm.StopOpCode()
 			}
 			return
 		case OpNoop:
-			m.incrCPU(OpCPUNoop)
+			m.incrCPU(m.cpuCosts[OpNoop])
 			continue
 		case OpExec:
-			m.incrCPU(OpCPUExec)
+			m.incrCPU(m.cpuCosts[OpExec])
 			m.doOpExec(op)
 		case OpPrecall:
-			m.incrCPU(OpCPUPrecall)
+			m.incrCPU(m.cpuCosts[OpPrecall])
 			m.doOpPrecall()
 		case OpCall:
-			m.incrCPU(OpCPUCall)
+			m.incrCPU(m.cpuCosts[OpCall])
 			m.doOpCall()
 		case OpCallNativeBody:
-			m.incrCPU(OpCPUCallNativeBody)
+			m.incrCPU(m.cpuCosts[OpCallNativeBody])
 			m.doOpCallNativeBody()
 		case OpReturn:
-			m.incrCPU(OpCPUReturn)
+			m.incrCPU(m.cpuCosts[OpReturn])
 			m.doOpReturn()
 		case OpReturnFromBlock:
-			m.incrCPU(OpCPUReturnFromBlock)
+			m.incrCPU(m.cpuCosts[OpReturnFromBlock])
 			m.doOpReturnFromBlock()
 		case OpReturnToBlock:
-			m.incrCPU(OpCPUReturnToBlock)
+			m.incrCPU(m.cpuCosts[OpReturnToBlock])
 			m.doOpReturnToBlock()
 		case OpDefer:
-			m.incrCPU(OpCPUDefer)
+			m.incrCPU(m.cpuCosts[OpDefer])
 			m.doOpDefer()
 		case OpPanic1:
-			m.incrCPU(OpCPUPanic1)
+			m.incrCPU(m.cpuCosts[OpPanic1])
 			m.doOpPanic1()
 		case OpPanic2:
-			m.incrCPU(OpCPUPanic2)
+			m.incrCPU(m.cpuCosts[OpPanic2])
 			m.doOpPanic2()
 		case OpCallDeferNativeBody:
-			m.incrCPU(OpCPUCallDeferNativeBody)
+			m.incrCPU(m.cpuCosts[OpCallDeferNativeBody])
 			m.doOpCallDeferNativeBody()
 		case OpGo:
-			m.incrCPU(OpCPUGo)
-			panic("not yet implemented")
+			m.incrCPU(m.cpuCosts[OpGo])
+			m.doOpGo()
+		case OpGoexit:
+			m.incrCPU(m.cpuCosts[OpGoexit])
+			m.doOpGoexit()
 		case OpSelect:
-			m.incrCPU(OpCPUSelect)
-			panic("not yet implemented")
+			m.incrCPU(m.cpuCosts[OpSelect])
+			m.doOpSelect()
 		case OpSwitchClause:
-			m.incrCPU(OpCPUSwitchClause)
+			m.incrCPU(m.cpuCosts[OpSwitchClause])
 			m.doOpSwitchClause()
 		case OpSwitchClauseCase:
-			m.incrCPU(OpCPUSwitchClauseCase)
+			m.incrCPU(m.cpuCosts[OpSwitchClauseCase])
 			m.doOpSwitchClauseCase()
 		case OpTypeSwitch:
-			m.incrCPU(OpCPUTypeSwitch)
+			m.incrCPU(m.cpuCosts[OpTypeSwitch])
 			m.doOpTypeSwitch()
 		case OpIfCond:
-			m.incrCPU(OpCPUIfCond)
+			m.incrCPU(m.cpuCosts[OpIfCond])
 			m.doOpIfCond()
 		case OpPopValue:
-			m.incrCPU(OpCPUPopValue)
+			m.incrCPU(m.cpuCosts[OpPopValue])
 			m.PopValue()
 		case OpPopResults:
-			m.incrCPU(OpCPUPopResults)
+			m.incrCPU(m.cpuCosts[OpPopResults])
 			m.PopResults()
 		case OpPopBlock:
-			m.incrCPU(OpCPUPopBlock)
+			m.incrCPU(m.cpuCosts[OpPopBlock])
 			m.PopBlock()
 		case OpPopFrameAndReset:
-			m.incrCPU(OpCPUPopFrameAndReset)
+			m.incrCPU(m.cpuCosts[OpPopFrameAndReset])
 			m.PopFrameAndReset()
 		/* Unary operators */
 		case OpUpos:
-			m.incrCPU(OpCPUUpos)
+			m.incrCPU(m.cpuCosts[OpUpos])
 			m.doOpUpos()
 		case OpUneg:
-			m.incrCPU(OpCPUUneg)
+			m.incrCPU(m.cpuCosts[OpUneg])
 			m.doOpUneg()
 		case OpUnot:
-			m.incrCPU(OpCPUUnot)
+			m.incrCPU(m.cpuCosts[OpUnot])
 			m.doOpUnot()
 		case OpUxor:
-			m.incrCPU(OpCPUUxor)
+			m.incrCPU(m.cpuCosts[OpUxor])
 			m.doOpUxor()
 		case OpUrecv:
-			m.incrCPU(OpCPUUrecv)
+			m.incrCPU(m.cpuCosts[OpUrecv])
 			m.doOpUrecv()
 		/* Binary operators */
 		case OpLor:
-			m.incrCPU(OpCPULor)
+			m.incrCPU(m.cpuCosts[OpLor])
 			m.doOpLor()
 		case OpLand:
-			m.incrCPU(OpCPULand)
+			m.incrCPU(m.cpuCosts[OpLand])
 			m.doOpLand()
 		case OpEql:
-			m.incrCPU(OpCPUEql)
+			m.incrCPU(m.cpuCosts[OpEql])
 			m.doOpEql()
 		case OpNeq:
-			m.incrCPU(OpCPUNeq)
+			m.incrCPU(m.cpuCosts[OpNeq])
 			m.doOpNeq()
 		case OpLss:
-			m.incrCPU(OpCPULss)
+			m.incrCPU(m.cpuCosts[OpLss])
 			m.doOpLss()
 		case OpLeq:
-			m.incrCPU(OpCPULeq)
+			m.incrCPU(m.cpuCosts[OpLeq])
 			m.doOpLeq()
 		case OpGtr:
-			m.incrCPU(OpCPUGtr)
+			m.incrCPU(m.cpuCosts[OpGtr])
 			m.doOpGtr()
 		case OpGeq:
-			m.incrCPU(OpCPUGeq)
+			m.incrCPU(m.cpuCosts[OpGeq])
 			m.doOpGeq()
 		case OpAdd:
-			m.incrCPU(OpCPUAdd)
+			m.incrCPU(m.cpuCosts[OpAdd])
 			m.doOpAdd()
 		case OpSub:
-			m.incrCPU(OpCPUSub)
+			m.incrCPU(m.cpuCosts[OpSub])
 			m.doOpSub()
 		case OpBor:
-			m.incrCPU(OpCPUBor)
+			m.incrCPU(m.cpuCosts[OpBor])
 			m.doOpBor()
 		case OpXor:
-			m.incrCPU(OpCPUXor)
+			m.incrCPU(m.cpuCosts[OpXor])
 			m.doOpXor()
 		case OpMul:
-			m.incrCPU(OpCPUMul)
+			m.incrCPU(m.cpuCosts[OpMul])
 			m.doOpMul()
 		case OpQuo:
-			m.incrCPU(OpCPUQuo)
+			m.incrCPU(m.cpuCosts[OpQuo])
 			m.doOpQuo()
 		case OpRem:
-			m.incrCPU(OpCPURem)
+			m.incrCPU(m.cpuCosts[OpRem])
 			m.doOpRem()
 		case OpShl:
-			m.incrCPU(OpCPUShl)
+			m.incrCPU(m.cpuCosts[OpShl])
 			m.doOpShl()
 		case OpShr:
-			m.incrCPU(OpCPUShr)
+			m.incrCPU(m.cpuCosts[OpShr])
 			m.doOpShr()
 		case OpBand:
-			m.incrCPU(OpCPUBand)
+			m.incrCPU(m.cpuCosts[OpBand])
 			m.doOpBand()
 		case OpBandn:
-			m.incrCPU(OpCPUBandn)
+			m.incrCPU(m.cpuCosts[OpBandn])
 			m.doOpBandn()
 		/* Expression operators */
 		case OpEval:
-			m.incrCPU(OpCPUEval)
+			m.incrCPU(m.cpuCosts[OpEval])
 			m.doOpEval()
 		case OpBinary1:
-			m.incrCPU(OpCPUBinary1)
+			m.incrCPU(m.cpuCosts[OpBinary1])
 			m.doOpBinary1()
 		case OpIndex1:
-			m.incrCPU(OpCPUIndex1)
+			m.incrCPU(m.cpuCosts[OpIndex1])
 			m.doOpIndex1()
 		case OpIndex2:
-			m.incrCPU(OpCPUIndex2)
+			m.incrCPU(m.cpuCosts[OpIndex2])
 			m.doOpIndex2()
 		case OpSelector:
-			m.incrCPU(OpCPUSelector)
+			m.incrCPU(m.cpuCosts[OpSelector])
 			m.doOpSelector()
 		case OpSlice:
-			m.incrCPU(OpCPUSlice)
+			m.incrCPU(m.cpuCosts[OpSlice])
 			m.doOpSlice()
 		case OpStar:
-			m.incrCPU(OpCPUStar)
+			m.incrCPU(m.cpuCosts[OpStar])
 			m.doOpStar()
 		case OpRef:
-			m.incrCPU(OpCPURef)
+			m.incrCPU(m.cpuCosts[OpRef])
 			m.doOpRef()
 		case OpTypeAssert1:
-			m.incrCPU(OpCPUTypeAssert1)
+			m.incrCPU(m.cpuCosts[OpTypeAssert1])
 			m.doOpTypeAssert1()
 		case OpTypeAssert2:
-			m.incrCPU(OpCPUTypeAssert2)
+			m.incrCPU(m.cpuCosts[OpTypeAssert2])
 			m.doOpTypeAssert2()
 		case OpStaticTypeOf:
-			m.incrCPU(OpCPUStaticTypeOf)
+			m.incrCPU(m.cpuCosts[OpStaticTypeOf])
 			m.doOpStaticTypeOf()
 		case OpCompositeLit:
-			m.incrCPU(OpCPUCompositeLit)
+			m.incrCPU(m.cpuCosts[OpCompositeLit])
 			m.doOpCompositeLit()
 		case OpArrayLit:
-			m.incrCPU(OpCPUArrayLit)
+			m.incrCPU(m.cpuCosts[OpArrayLit])
 			m.doOpArrayLit()
 		case OpSliceLit:
-			m.incrCPU(OpCPUSliceLit)
+			m.incrCPU(m.cpuCosts[OpSliceLit])
 			m.doOpSliceLit()
 		case OpSliceLit2:
-			m.incrCPU(OpCPUSliceLit2)
+			m.incrCPU(m.cpuCosts[OpSliceLit2])
 			m.doOpSliceLit2()
 		case OpFuncLit:
-			m.incrCPU(OpCPUFuncLit)
+			m.incrCPU(m.cpuCosts[OpFuncLit])
 			m.doOpFuncLit()
 		case OpMapLit:
-			m.incrCPU(OpCPUMapLit)
+			m.incrCPU(m.cpuCosts[OpMapLit])
 			m.doOpMapLit()
 		case OpStructLit:
-			m.incrCPU(OpCPUStructLit)
+			m.incrCPU(m.cpuCosts[OpStructLit])
 			m.doOpStructLit()
 		case OpConvert:
-			m.incrCPU(OpCPUConvert)
+			m.incrCPU(m.cpuCosts[OpConvert])
 			m.doOpConvert()
 		/* GoNative Operators */
 		case OpArrayLitGoNative:
-			m.incrCPU(OpCPUArrayLitGoNative)
+			m.incrCPU(m.cpuCosts[OpArrayLitGoNative])
 			m.doOpArrayLitGoNative()
 		case OpSliceLitGoNative:
-			m.incrCPU(OpCPUSliceLitGoNative)
+			m.incrCPU(m.cpuCosts[OpSliceLitGoNative])
 			m.doOpSliceLitGoNative()
 		case OpStructLitGoNative:
-			m.incrCPU(OpCPUStructLitGoNative)
+			m.incrCPU(m.cpuCosts[OpStructLitGoNative])
 			m.doOpStructLitGoNative()
 		case OpCallGoNative:
-			m.incrCPU(OpCPUCallGoNative)
+			m.incrCPU(m.cpuCosts[OpCallGoNative])
 			m.doOpCallGoNative()
 		/* Type operators */
 		case OpFieldType:
-			m.incrCPU(OpCPUFieldType)
+			m.incrCPU(m.cpuCosts[OpFieldType])
 			m.doOpFieldType()
 		case OpArrayType:
-			m.incrCPU(OpCPUArrayType)
+			m.incrCPU(m.cpuCosts[OpArrayType])
 			m.doOpArrayType()
 		case OpSliceType:
-			m.incrCPU(OpCPUSliceType)
+			m.incrCPU(m.cpuCosts[OpSliceType])
 			m.doOpSliceType()
 		case OpChanType:
-			m.incrCPU(OpCPUChanType)
+			m.incrCPU(m.cpuCosts[OpChanType])
 			m.doOpChanType()
 		case OpFuncType:
-			m.incrCPU(OpCPUFuncType)
+			m.incrCPU(m.cpuCosts[OpFuncType])
 			m.doOpFuncType()
 		case OpMapType:
-			m.incrCPU(OpCPUMapType)
+			m.incrCPU(m.cpuCosts[OpMapType])
 			m.doOpMapType()
 		case OpStructType:
-			m.incrCPU(OpCPUStructType)
+			m.incrCPU(m.cpuCosts[OpStructType])
 			m.doOpStructType()
 		case OpInterfaceType:
-			m.incrCPU(OpCPUInterfaceType)
+			m.incrCPU(m.cpuCosts[OpInterfaceType])
 			m.doOpInterfaceType()
 		case OpMaybeNativeType:
-			m.incrCPU(OpCPUMaybeNativeType)
+			m.incrCPU(m.cpuCosts[OpMaybeNativeType])
 			m.doOpMaybeNativeType()
 		/* Statement operators */
 		case OpAssign:
-			m.incrCPU(OpCPUAssign)
+			m.incrCPU(m.cpuCosts[OpAssign])
 			m.doOpAssign()
 		case OpAddAssign:
-			m.incrCPU(OpCPUAddAssign)
+			m.incrCPU(m.cpuCosts[OpAddAssign])
 			m.doOpAddAssign()
 		case OpSubAssign:
-			m.incrCPU(OpCPUSubAssign)
+			m.incrCPU(m.cpuCosts[OpSubAssign])
 			m.doOpSubAssign()
 		case OpMulAssign:
-			m.incrCPU(OpCPUMulAssign)
+			m.incrCPU(m.cpuCosts[OpMulAssign])
 			m.doOpMulAssign()
 		case OpQuoAssign:
-			m.incrCPU(OpCPUQuoAssign)
+			m.incrCPU(m.cpuCosts[OpQuoAssign])
 			m.doOpQuoAssign()
 		case OpRemAssign:
-			m.incrCPU(OpCPURemAssign)
+			m.incrCPU(m.cpuCosts[OpRemAssign])
 			m.doOpRemAssign()
 		case OpBandAssign:
-			m.incrCPU(OpCPUBandAssign)
+			m.incrCPU(m.cpuCosts[OpBandAssign])
 			m.doOpBandAssign()
 		case OpBandnAssign:
-			m.incrCPU(OpCPUBandnAssign)
+			m.incrCPU(m.cpuCosts[OpBandnAssign])
 			m.doOpBandnAssign()
 		case OpBorAssign:
-			m.incrCPU(OpCPUBorAssign)
+			m.incrCPU(m.cpuCosts[OpBorAssign])
 			m.doOpBorAssign()
 		case OpXorAssign:
-			m.incrCPU(OpCPUXorAssign)
+			m.incrCPU(m.cpuCosts[OpXorAssign])
 			m.doOpXorAssign()
 		case OpShlAssign:
-			m.incrCPU(OpCPUShlAssign)
+			m.incrCPU(m.cpuCosts[OpShlAssign])
 			m.doOpShlAssign()
 		case OpShrAssign:
-			m.incrCPU(OpCPUShrAssign)
+			m.incrCPU(m.cpuCosts[OpShrAssign])
 			m.doOpShrAssign()
 		case OpDefine:
-			m.incrCPU(OpCPUDefine)
+			m.incrCPU(m.cpuCosts[OpDefine])
 			m.doOpDefine()
 		case OpInc:
-			m.incrCPU(OpCPUInc)
+			m.incrCPU(m.cpuCosts[OpInc])
 			m.doOpInc()
 		case OpDec:
-			m.incrCPU(OpCPUDec)
+			m.incrCPU(m.cpuCosts[OpDec])
 			m.doOpDec()
+		case OpSend:
+			m.incrCPU(m.cpuCosts[OpSend])
+			m.doOpSend()
 		/* Decl operators */
 		case OpValueDecl:
-			m.incrCPU(OpCPUValueDecl)
+			m.incrCPU(m.cpuCosts[OpValueDecl])
 			m.doOpValueDecl()
 		case OpTypeDecl:
-			m.incrCPU(OpCPUTypeDecl)
+			m.incrCPU(m.cpuCosts[OpTypeDecl])
 			m.doOpTypeDecl()
 		/* Loop (sticky) operators */
 		case OpBody:
-			m.incrCPU(OpCPUBody)
+			m.incrCPU(m.cpuCosts[OpBody])
 			m.doOpExec(op)
 		case OpForLoop:
-			m.incrCPU(OpCPUForLoop)
+			m.incrCPU(m.cpuCosts[OpForLoop])
 			m.doOpExec(op)
 		case OpRangeIter:
-			m.incrCPU(OpCPURangeIter)
+			m.incrCPU(m.cpuCosts[OpRangeIter])
 			m.doOpExec(op)
 		case OpRangeIterArrayPtr:
-			m.incrCPU(OpCPURangeIterArrayPtr)
+			m.incrCPU(m.cpuCosts[OpRangeIterArrayPtr])
+			m.doOpExec(op)
+		case OpRangeIterChan:
+			m.incrCPU(m.cpuCosts[OpRangeIterChan])
 			m.doOpExec(op)
 		case OpRangeIterString:
-			m.incrCPU(OpCPURangeIterString)
+			m.incrCPU(m.cpuCosts[OpRangeIterString])
 			m.doOpExec(op)
 		case OpRangeIterMap:
-			m.incrCPU(OpCPURangeIterMap)
+			m.incrCPU(m.cpuCosts[OpRangeIterMap])
 			m.doOpExec(op)
 		case OpReturnCallDefers:
-			m.incrCPU(OpCPUReturnCallDefers)
+			m.incrCPU(m.cpuCosts[OpReturnCallDefers])
 			m.doOpReturnCallDefers()
 		default:
 			panic(fmt.Sprintf("unexpected opcode %s", op.String()))
@@ -485,8 +485,9 @@ type TypeAssertExpr struct { // X.(Type)
 // (referencing) are represented with RefExpr nodes.
 type UnaryExpr struct { // (Op X)
 	Attributes
-	X  Expr // operand
-	Op Word // operator
+	X     Expr // operand
+	Op    Word // operator
+	HasOK bool // if true, is form: `value, ok := <-<X>`
 }
 
 // MyType{<key>:<value>} struct, array, slice, and map
@@ -867,6 +868,7 @@ type RangeStmt struct {
 	IsMap      bool // if X is map type
 	IsString   bool // if X is string type
 	IsArrayPtr bool // if X is array-pointer type
+	IsChan     bool // if X is channel type
 }
 
 type ReturnStmt struct {
@@ -287,7 +287,7 @@ func (x *SelectStmt) Copy() Node {
 
 func (x *SelectCaseStmt) Copy() Node {
 	return &SelectCaseStmt{
-		Comm: x.Comm.Copy().(Stmt),
+		Comm: copyStmt(x.Comm),
 		Body: copyStmts(x.Body),
 	}
 }
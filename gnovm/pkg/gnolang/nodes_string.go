@@ -227,9 +227,9 @@ func (x InterfaceTypeExpr) String() string {
 func (x ChanTypeExpr) String() string {
 	switch x.Dir {
 	case SEND:
-		return fmt.Sprintf("<-chan %s", x.Value)
-	case RECV:
 		return fmt.Sprintf("chan<- %s", x.Value)
+	case RECV:
+		return fmt.Sprintf("<-chan %s", x.Value)
 	case SEND | RECV:
 		return fmt.Sprintf("chan %s", x.Value)
 	default:
@@ -385,6 +385,9 @@ func (x SelectStmt) String() string {
 }
 
 func (x SelectCaseStmt) String() string {
+	if x.Comm == nil {
+		return fmt.Sprintf("default: %s", x.Body.String())
+	}
 	return fmt.Sprintf("case %v: %s", x.Comm.String(), x.Body.String())
 }
 
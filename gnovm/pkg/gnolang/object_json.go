@@ -0,0 +1,168 @@
+package gnolang
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+)
+
+// JSONObject is the JSON description of a persisted object, as returned by
+// the vm/qobject query. Fields hold a page of the values of the object,
+// from Offset, out of Total. The persisted objects they contain are written
+// as {"$ref":"<object id>"}, and listed in Refs.
+type JSONObject struct {
+	ObjectID string      `json:"objectid"`
+	Kind     string      `json:"kind"`
+	Hash     string      `json:"hash"`
+	OwnerID  string      `json:"owner_id,omitempty"`
+	RefCount int         `json:"ref_count"`
+	ModTime  uint64      `json:"mod_time"`
+	Escaped  bool        `json:"escaped,omitempty"`
+	Fields   []JSONField `json:"fields"`
+	Refs     []string    `json:"refs"`
+	Offset   int         `json:"offset"`
+	Total    int         `json:"total"`
+}
+
+// JSONField is a value of a JSONObject: a name of a block or a file of a
+// package, an element of an array, a field of a struct, or the key and the
+// value of a map entry.
+type JSONField struct {
+	Name string     `json:"name,omitempty"`
+	Key  *JSONValue `json:"key,omitempty"`
+	JSONValue
+}
+
+// NewJSONObject returns the JSON description of the object oo, with at most
+// limit of its values starting from offset.
+func NewJSONObject(store Store, oo Object, offset, limit int) *JSONObject {
+	oi := oo.GetObjectInfo()
+	jo := &JSONObject{
+		ObjectID: oi.ID.String(),
+		Kind:     objectKindString(oo),
+		Hash:     hashString(oi.Hash),
+		RefCount: oi.RefCount,
+		ModTime:  oi.ModTime,
+		Escaped:  oi.IsEscaped,
+		Fields:   []JSONField{},
+		Refs:     []string{},
+		Offset:   offset,
+	}
+	if !oi.OwnerID.IsZero() {
+		jo.OwnerID = oi.OwnerID.String()
+	}
+	enc := newJSONEncoder(store, true)
+	// value returns the JSON encoding of tv, with its type.
+	value := func(tv TypedValue) JSONValue {
+		return JSONValue{
+			T: jsonTypeString(tv.T),
+			V: enc.encode(tv),
+		}
+	}
+	// page returns the start and end of the page of n values.
+	page := func(n int) (int, int) {
+		jo.Total = n
+		start := min(offset, n)
+		return start, min(start+limit, n)
+	}
+
+	switch cv := oo.(type) {
+	case *PackageValue:
+		fields := make([]JSONField, 0, 1+len(cv.FNames))
+		fields = append(fields, JSONField{Name: "block", JSONValue: value(TypedValue{T: blockType{}, V: cv.Block})})
+		for i, fname := range cv.FNames {
+			fields = append(fields, JSONField{Name: string(fname), JSONValue: value(TypedValue{T: blockType{}, V: cv.FBlocks[i]})})
+		}
+		start, end := page(len(fields))
+		jo.Fields = append(jo.Fields, fields[start:end]...)
+	case *Block:
+		names := cv.GetSource(store).GetBlockNames()
+		start, end := page(len(cv.Values))
+		for i := start; i < end; i++ {
+			var name string
+			if i < len(names) {
+				name = string(names[i])
+			}
+			jo.Fields = append(jo.Fields, JSONField{Name: name, JSONValue: value(cv.Values[i])})
+		}
+	case *ArrayValue:
+		if cv.Data != nil {
+			start, end := page(len(cv.Data))
+			bz, _ := json.Marshal(base64.StdEncoding.EncodeToString(cv.Data[start:end]))
+			jo.Fields = append(jo.Fields, JSONField{JSONValue: JSONValue{T: "[]uint8", V: bz}})
+			break
+		}
+		start, end := page(len(cv.List))
+		for _, tv := range cv.List[start:end] {
+			jo.Fields = append(jo.Fields, JSONField{JSONValue: value(tv)})
+		}
+	case *StructValue:
+		start, end := page(len(cv.Fields))
+		for _, tv := range cv.Fields[start:end] {
+			jo.Fields = append(jo.Fields, JSONField{JSONValue: value(tv)})
+		}
+	case *MapValue:
+		start, end := page(cv.List.Size)
+		item := cv.List.Head
+		for i := 0; i < start; i++ {
+			item = item.Next
+		}
+		for i := start; i < end; i, item = i+1, item.Next {
+			key := value(item.Key)
+			jo.Fields = append(jo.Fields, JSONField{Key: &key, JSONValue: value(item.Value)})
+		}
+	case *HeapItemValue:
+		start, end := page(1)
+		if start < end {
+			jo.Fields = append(jo.Fields, JSONField{JSONValue: value(cv.Value)})
+		}
+	case *BoundMethodValue:
+		fields := []JSONField{
+			{Name: "func", JSONValue: value(TypedValue{T: cv.Func.Type, V: cv.Func})},
+			{Name: "receiver", JSONValue: value(cv.Receiver)},
+		}
+		start, end := page(len(fields))
+		jo.Fields = append(jo.Fields, fields[start:end]...)
+	default:
+		panic(fmt.Sprintf("unexpected object type %T", oo))
+	}
+
+	seen := make(map[ObjectID]struct{}, len(enc.refs))
+	for _, oid := range enc.refs {
+		if _, ok := seen[oid]; ok {
+			continue
+		}
+		seen[oid] = struct{}{}
+		jo.Refs = append(jo.Refs, oid.String())
+	}
+	return jo
+}
+
+func objectKindString(oo Object) string {
+	switch oo.(type) {
+	case *PackageValue:
+		return "package"
+	case *Block:
+		return "block"
+	case *ArrayValue:
+		return "array"
+	case *StructValue:
+		return "struct"
+	case *MapValue:
+		return "map"
+	case *HeapItemValue:
+		return "heapitem"
+	case *BoundMethodValue:
+		return "boundmethod"
+	default:
+		panic(fmt.Sprintf("unexpected object type %T", oo))
+	}
+}
+
+func hashString(vh ValueHash) string {
+	if vh.IsZero() {
+		return ""
+	}
+	s, _ := vh.MarshalAmino()
+	return s
+}
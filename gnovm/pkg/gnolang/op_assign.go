@@ -299,3 +299,19 @@ func (m *Machine) doOpShrAssign() {
 		m.Realm.DidUpdate(lv.Base.(Object), nil, nil)
 	}
 }
+
+func (m *Machine) doOpSend() {
+	// The statement and values are only popped once the value is sent, as
+	// the operation is executed again if it blocks.
+	xv := m.PeekValue(2) // channel
+	vv := m.PeekValue(1) // value to send
+	cv, _ := xv.V.(*ChanValue)
+	if w := m.chanSend(cv, vv); w != nil {
+		m.PushOp(OpSend)
+		m.park(w)
+		return
+	}
+	m.PopStmt()
+	m.PopValue()
+	m.PopValue()
+}
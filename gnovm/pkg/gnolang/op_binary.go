@@ -459,6 +459,9 @@ func isEql(store Store, lv, rv *TypedValue) bool {
 			}
 		}
 		return lv.V == rv.V
+	case ChanKind:
+		// channels are equal if created by the same make.
+		return lv.V == rv.V
 	case FuncKind:
 		if debug {
 			if lv.V != nil && rv.V != nil {
@@ -425,12 +425,86 @@ func (m *Machine) doOpExec(op Op) {
 				panic("should not happen")
 			}
 		}
+	case OpRangeIterChan:
+		bs := s.(*bodyStmt)
+		xv := m.PeekValue(1)
+		switch bs.NextBodyIndex {
+		case -2: // init.
+			bs.NumOps = m.NumOps
+			bs.NumValues = m.NumValues
+			bs.NumExprs = len(m.Exprs)
+			bs.NumStmts = len(m.Stmts)
+			bs.NextBodyIndex++
+			fallthrough
+		case -1: // receive and assign element.
+			cv, _ := xv.V.(*ChanValue)
+			ev, ok, w := m.chanRecv(cv)
+			if w != nil {
+				// blocked: the op is sticky, so this
+				// receives again once resumed.
+				m.park(w)
+				return
+			}
+			if !ok {
+				// done with range.
+				m.PopFrameAndReset()
+				return
+			}
+			if bs.Key != nil {
+				switch bs.Op {
+				case ASSIGN:
+					m.PopAsPointer(bs.Key).Assign2(m.Alloc, m.Store, m.Realm, ev, false)
+				case DEFINE:
+					knx := bs.Key.(*NameExpr)
+					ptr := m.LastBlock().GetPointerToMaybeHeapDefine(m.Store, knx)
+					ptr.TV.Assign(m.Alloc, ev, false)
+				default:
+					panic("should not happen")
+				}
+			}
+			bs.NextBodyIndex++
+			fallthrough
+		default:
+			// NOTE: duplicated for OpRangeIter,
+			// but the range only ends when the
+			// channel is closed.
+			if bs.NextBodyIndex < bs.BodyLen {
+				next := bs.Body[bs.NextBodyIndex]
+				bs.NextBodyIndex++
+				// continue onto exec stmt.
+				bs.Active = next
+				s = next // switch on bs.Active
+				goto EXEC_SWITCH
+			} else if bs.NextBodyIndex == bs.BodyLen {
+				// set up next assign if needed.
+				switch bs.Op {
+				case ASSIGN:
+					if bs.Key != nil {
+						m.PushForPointer(bs.Key)
+					}
+				case DEFINE:
+					// do nothing
+				case ILLEGAL:
+					// do nothing, no assignment
+				default:
+					panic("should not happen")
+				}
+				bs.NextBodyIndex = -1
+				bs.Active = nil
+				return // redo doOpExec:*bodyStmt
+			} else {
+				panic("should not happen")
+			}
+		}
 	}
 
 EXEC_SWITCH:
 	if debug {
 		debug.Printf("EXEC: %v\n", s)
 	}
+	if m.Coverage != nil {
+		m.Coverage.recordStmt(m, s)
+	}
 	switch cs := s.(type) {
 	case *AssignStmt:
 		switch cs.Op {
@@ -592,6 +666,8 @@ EXEC_SWITCH:
 		// TODO: replace with "cs.Op".
 		if cs.IsMap {
 			m.PushOp(OpRangeIterMap)
+		} else if cs.IsChan {
+			m.PushOp(OpRangeIterChan)
 		} else if cs.IsString {
 			m.PushOp(OpRangeIterString)
 		} else if cs.IsArrayPtr {
@@ -628,7 +704,7 @@ EXEC_SWITCH:
 			for {
 				fr := m.LastFrame()
 				switch fr.Source.(type) {
-				case *ForStmt, *RangeStmt, *SwitchStmt:
+				case *ForStmt, *RangeStmt, *SwitchStmt, *SelectStmt:
 					if cs.Label != "" && cs.Label != fr.Label {
 						m.PopFrame()
 					} else {
@@ -732,6 +808,52 @@ EXEC_SWITCH:
 		// evaluate func
 		m.PushExpr(cs.Call.Func)
 		m.PushOp(OpEval)
+	case *GoStmt:
+		m.PushOp(OpGo)
+		// evaluate args
+		args := cs.Call.Args
+		for i := len(args) - 1; 0 <= i; i-- {
+			m.PushExpr(args[i])
+			m.PushOp(OpEval)
+		}
+		// evaluate func
+		m.PushExpr(cs.Call.Func)
+		m.PushOp(OpEval)
+	case *SendStmt:
+		m.PushOp(OpSend)
+		// evaluate value
+		m.PushExpr(cs.Value)
+		m.PushOp(OpEval)
+		// evaluate chan
+		m.PushExpr(cs.Chan)
+		m.PushOp(OpEval)
+	case *SelectStmt:
+		m.PushFrameBasic(cs)
+		m.PushOp(OpPopFrameAndReset)
+		if len(cs.Cases) > 0 {
+			// like for switch clauses, a single block is created,
+			// and expanded once a case is selected.
+			b := m.Alloc.NewBlock(&cs.Cases[0], m.LastBlock())
+			m.PushBlock(b)
+			m.PushOp(OpPopBlock)
+		}
+		m.PushOp(OpSelect)
+		// evaluate the channels, and the values to send,
+		// of all the cases in source order.
+		for i := len(cs.Cases) - 1; 0 <= i; i-- {
+			switch comm := cs.Cases[i].Comm.(type) {
+			case nil:
+				// default case
+			case *SendStmt:
+				m.PushExpr(comm.Value)
+				m.PushOp(OpEval)
+				m.PushExpr(comm.Chan)
+				m.PushOp(OpEval)
+			default:
+				m.PushExpr(selectRecvExpr(comm).X)
+				m.PushOp(OpEval)
+			}
+		}
 	case *SwitchStmt:
 		m.PushFrameBasic(cs)
 		m.PushOp(OpPopFrameAndReset)
@@ -994,3 +1116,166 @@ func (m *Machine) doOpSwitchClauseCase() {
 		}
 	}
 }
+
+// selectRecvExpr returns the receive expression of the communication clause
+// of a select case, which is not a send statement.
+func selectRecvExpr(comm Stmt) *UnaryExpr {
+	switch comm := comm.(type) {
+	case *ExprStmt:
+		return comm.X.(*UnaryExpr)
+	case *AssignStmt:
+		return comm.Rhs[0].(*UnaryExpr)
+	default:
+		panic(fmt.Sprintf("unexpected select case %v", comm))
+	}
+}
+
+func (m *Machine) doOpSelect() {
+	ss := m.PeekStmt1().(*SelectStmt)
+	// The values of the cases are on the stack in source order: the
+	// channel for receive cases, the channel and the value for send cases.
+	numValues := 0
+	for _, cc := range ss.Cases {
+		switch cc.Comm.(type) {
+		case nil:
+		case *SendStmt:
+			numValues += 2
+		default:
+			numValues++
+		}
+	}
+	vs := m.Values[m.NumValues-numValues : m.NumValues]
+
+	selected := -1
+	var rv TypedValue // received value
+	var rok bool      // whether rv was received from an open channel
+	if w := m.resumeWait(); w != nil {
+		// resumed after one of the cases completed.
+		selected, rv, rok = w.index, w.value, w.ok
+		if _, ok := ss.Cases[selected].Comm.(*SendStmt); ok && !rok {
+			panic(&Exception{Value: typedString("send on closed channel")})
+		}
+	} else {
+		// select the first case which does not block.
+		defaultCase := -1
+		vi := 0
+	CASES:
+		for i, cc := range ss.Cases {
+			switch cc.Comm.(type) {
+			case nil:
+				defaultCase = i
+			case *SendStmt:
+				cv, _ := vs[vi].V.(*ChanValue)
+				vi += 2
+				if cv != nil && cv.trySend(vs[vi-1].Copy(m.Alloc)) {
+					selected = i
+					break CASES
+				}
+			default:
+				cv, _ := vs[vi].V.(*ChanValue)
+				vi++
+				if cv == nil {
+					continue
+				}
+				if v, ok, ready := cv.tryRecv(); ready {
+					selected, rv, rok = i, v, ok
+					break CASES
+				}
+			}
+		}
+		if selected < 0 {
+			selected = defaultCase
+		}
+		if selected < 0 {
+			// block on all the cases, until one completes.
+			w := &goroutineWait{}
+			vi = 0
+			for i, cc := range ss.Cases {
+				switch cc.Comm.(type) {
+				case *SendStmt:
+					if cv, _ := vs[vi].V.(*ChanValue); cv != nil {
+						cv.sendq = append(cv.sendq, &chanWaiter{
+							wait:  w,
+							index: i,
+							value: vs[vi+1].Copy(m.Alloc),
+						})
+					}
+					vi += 2
+				default:
+					if cv, _ := vs[vi].V.(*ChanValue); cv != nil {
+						cv.recvq = append(cv.recvq, &chanWaiter{wait: w, index: i})
+					}
+					vi++
+				}
+			}
+			// select again once resumed.
+			m.PushOp(OpSelect)
+			m.park(w)
+			return
+		}
+	}
+
+	if as, ok := ss.Cases[selected].Comm.(*AssignStmt); ok {
+		// find the channel of the receive case.
+		vi := 0
+		for i := 0; i < selected; i++ {
+			switch ss.Cases[i].Comm.(type) {
+			case nil:
+			case *SendStmt:
+				vi += 2
+			default:
+				vi++
+			}
+		}
+		if !rok { // closed channel
+			ct := baseOf(vs[vi].T).(*ChanType)
+			rv = defaultTypedValue(m.Alloc, ct.Elt)
+		}
+		// the values are no longer needed.
+		m.PopValues(numValues)
+		m.PopStmt() // pop select stmt
+		m.pushSelectCase(&ss.Cases[selected])
+		// assign the received value in the case block.
+		switch as.Op {
+		case ASSIGN:
+			m.PushOp(OpAssign)
+		case DEFINE:
+			m.PushOp(OpDefine)
+		default:
+			panic("should not happen")
+		}
+		m.PushStmt(as)
+		if len(as.Lhs) == 2 {
+			m.PushExpr(&ConstExpr{Source: as.Rhs[0], TypedValue: untypedBool(rok)})
+			m.PushOp(OpEval)
+		}
+		m.PushExpr(&ConstExpr{Source: as.Rhs[0], TypedValue: rv})
+		m.PushOp(OpEval)
+		if as.Op == ASSIGN {
+			for i := len(as.Lhs) - 1; 0 <= i; i-- {
+				m.PushForPointer(as.Lhs[i])
+			}
+		}
+		return
+	}
+	m.PopValues(numValues)
+	m.PopStmt() // pop select stmt
+	m.pushSelectCase(&ss.Cases[selected])
+}
+
+// pushSelectCase pushes the execution of the body of the selected case cc,
+// in the block of the select statement.
+func (m *Machine) pushSelectCase(cc *SelectCaseStmt) {
+	b := m.LastBlock()
+	b.Source = cc
+	if nn := cc.GetNumNames(); int(nn) > len(b.Values) {
+		b.ExpandToSize(m.Alloc, nn)
+	}
+	b.bodyStmt = bodyStmt{
+		Body:          cc.Body,
+		BodyLen:       len(cc.Body),
+		NextBodyIndex: -2,
+	}
+	m.PushOp(OpBody)
+	m.PushStmt(b.GetBodyStmt())
+}
@@ -32,6 +32,7 @@ func _() {
 	_ = x[OpPopFrameAndReset-21]
 	_ = x[OpPanic1-22]
 	_ = x[OpPanic2-23]
+	_ = x[OpGoexit-24]
 	_ = x[OpUpos-32]
 	_ = x[OpUneg-33]
 	_ = x[OpUnot-34]
@@ -104,6 +105,7 @@ func _() {
 	_ = x[OpDefine-140]
 	_ = x[OpInc-141]
 	_ = x[OpDec-142]
+	_ = x[OpSend-143]
 	_ = x[OpValueDecl-144]
 	_ = x[OpTypeDecl-145]
 	_ = x[OpSticky-208]
@@ -114,37 +116,36 @@ func _() {
 	_ = x[OpRangeIterMap-213]
 	_ = x[OpRangeIterArrayPtr-214]
 	_ = x[OpReturnCallDefers-215]
+	_ = x[OpRangeIterChan-216]
 	_ = x[OpVoid-255]
 }
 
 const (
-	_Op_name_0 = "OpInvalidOpHaltOpNoopOpExecOpPrecallOpCallOpCallNativeBodyOpReturnOpReturnFromBlockOpReturnToBlockOpDeferOpCallDeferNativeBodyOpGoOpSelectOpSwitchClauseOpSwitchClauseCaseOpTypeSwitchOpIfCondOpPopValueOpPopResultsOpPopBlockOpPopFrameAndResetOpPanic1OpPanic2"
+	_Op_name_0 = "OpInvalidOpHaltOpNoopOpExecOpPrecallOpCallOpCallNativeBodyOpReturnOpReturnFromBlockOpReturnToBlockOpDeferOpCallDeferNativeBodyOpGoOpSelectOpSwitchClauseOpSwitchClauseCaseOpTypeSwitchOpIfCondOpPopValueOpPopResultsOpPopBlockOpPopFrameAndResetOpPanic1OpPanic2OpGoexit"
 	_Op_name_1 = "OpUposOpUnegOpUnotOpUxor"
 	_Op_name_2 = "OpUrecvOpLorOpLandOpEqlOpNeqOpLssOpLeqOpGtrOpGeqOpAddOpSubOpBorOpXorOpMulOpQuoOpRemOpShlOpShrOpBandOpBandn"
 	_Op_name_3 = "OpEvalOpBinary1OpIndex1OpIndex2OpSelectorOpSliceOpStarOpRefOpTypeAssert1OpTypeAssert2OpStaticTypeOfOpCompositeLitOpArrayLitOpSliceLitOpSliceLit2OpMapLitOpStructLitOpFuncLitOpConvert"
 	_Op_name_4 = "OpArrayLitGoNativeOpSliceLitGoNativeOpStructLitGoNativeOpCallGoNative"
 	_Op_name_5 = "OpFieldTypeOpArrayTypeOpSliceTypeOpPointerTypeOpInterfaceTypeOpChanTypeOpFuncTypeOpMapTypeOpStructTypeOpMaybeNativeType"
-	_Op_name_6 = "OpAssignOpAddAssignOpSubAssignOpMulAssignOpQuoAssignOpRemAssignOpBandAssignOpBandnAssignOpBorAssignOpXorAssignOpShlAssignOpShrAssignOpDefineOpIncOpDec"
-	_Op_name_7 = "OpValueDeclOpTypeDecl"
-	_Op_name_8 = "OpStickyOpBodyOpForLoopOpRangeIterOpRangeIterStringOpRangeIterMapOpRangeIterArrayPtrOpReturnCallDefers"
-	_Op_name_9 = "OpVoid"
+	_Op_name_6 = "OpAssignOpAddAssignOpSubAssignOpMulAssignOpQuoAssignOpRemAssignOpBandAssignOpBandnAssignOpBorAssignOpXorAssignOpShlAssignOpShrAssignOpDefineOpIncOpDecOpSendOpValueDeclOpTypeDecl"
+	_Op_name_7 = "OpStickyOpBodyOpForLoopOpRangeIterOpRangeIterStringOpRangeIterMapOpRangeIterArrayPtrOpReturnCallDefersOpRangeIterChan"
+	_Op_name_8 = "OpVoid"
 )
 
 var (
-	_Op_index_0 = [...]uint16{0, 9, 15, 21, 27, 36, 42, 58, 66, 83, 98, 105, 126, 130, 138, 152, 170, 182, 190, 200, 212, 222, 240, 248, 256}
+	_Op_index_0 = [...]uint16{0, 9, 15, 21, 27, 36, 42, 58, 66, 83, 98, 105, 126, 130, 138, 152, 170, 182, 190, 200, 212, 222, 240, 248, 256, 264}
 	_Op_index_1 = [...]uint8{0, 6, 12, 18, 24}
 	_Op_index_2 = [...]uint8{0, 7, 12, 18, 23, 28, 33, 38, 43, 48, 53, 58, 63, 68, 73, 78, 83, 88, 93, 99, 106}
 	_Op_index_3 = [...]uint8{0, 6, 15, 23, 31, 41, 48, 54, 59, 72, 85, 99, 113, 123, 133, 144, 152, 163, 172, 181}
 	_Op_index_4 = [...]uint8{0, 18, 36, 55, 69}
 	_Op_index_5 = [...]uint8{0, 11, 22, 33, 46, 61, 71, 81, 90, 102, 119}
-	_Op_index_6 = [...]uint8{0, 8, 19, 30, 41, 52, 63, 75, 88, 99, 110, 121, 132, 140, 145, 150}
-	_Op_index_7 = [...]uint8{0, 11, 21}
-	_Op_index_8 = [...]uint8{0, 8, 14, 23, 34, 51, 65, 84, 102}
+	_Op_index_6 = [...]uint8{0, 8, 19, 30, 41, 52, 63, 75, 88, 99, 110, 121, 132, 140, 145, 150, 156, 167, 177}
+	_Op_index_7 = [...]uint8{0, 8, 14, 23, 34, 51, 65, 84, 102, 117}
 )
 
 func (i Op) String() string {
 	switch {
-	case i <= 23:
+	case i <= 24:
 		return _Op_name_0[_Op_index_0[i]:_Op_index_0[i+1]]
 	case 32 <= i && i <= 35:
 		i -= 32
@@ -161,17 +162,14 @@ func (i Op) String() string {
 	case 112 <= i && i <= 121:
 		i -= 112
 		return _Op_name_5[_Op_index_5[i]:_Op_index_5[i+1]]
-	case 128 <= i && i <= 142:
+	case 128 <= i && i <= 145:
 		i -= 128
 		return _Op_name_6[_Op_index_6[i]:_Op_index_6[i+1]]
-	case 144 <= i && i <= 145:
-		i -= 144
-		return _Op_name_7[_Op_index_7[i]:_Op_index_7[i+1]]
-	case 208 <= i && i <= 215:
+	case 208 <= i && i <= 216:
 		i -= 208
-		return _Op_name_8[_Op_index_8[i]:_Op_index_8[i+1]]
+		return _Op_name_7[_Op_index_7[i]:_Op_index_7[i+1]]
 	case i == 255:
-		return _Op_name_9
+		return _Op_name_8
 	default:
 		return "Op(" + strconv.FormatInt(int64(i), 10) + ")"
 	}
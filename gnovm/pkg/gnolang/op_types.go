@@ -508,8 +508,24 @@ func (m *Machine) doOpStaticTypeOf() {
 			m.PushOp(OpEval)
 		}
 	case *UnaryExpr:
-		m.PushExpr(x.X)
-		m.PushOp(OpStaticTypeOf)
+		if x.Op == ARROW {
+			start := m.NumValues
+			m.PushOp(OpHalt)
+			m.PushExpr(x.X)
+			m.PushOp(OpStaticTypeOf)
+			m.Run() // XXX replace
+			xt := m.ReapValues(start)[0].GetType()
+			if x.HasOK {
+				panic("receive assignment used with return 2 values; has no type")
+			} else if ct, ok := baseOf(xt).(*ChanType); ok {
+				m.PushValue(asValue(ct.Elt))
+			} else {
+				panic("unexpected receive expression")
+			}
+		} else {
+			m.PushExpr(x.X)
+			m.PushOp(OpStaticTypeOf)
+		}
 	case *CompositeLitExpr:
 		m.PushExpr(x.Type)
 		m.PushOp(OpEval)
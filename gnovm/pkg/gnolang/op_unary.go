@@ -121,5 +121,25 @@ func (m *Machine) doOpUxor() {
 }
 
 func (m *Machine) doOpUrecv() {
-	panic("not yet implemented")
+	ux := m.PopExpr().(*UnaryExpr)
+	if debug {
+		debug.Printf("doOpUrecv(%v)\n", ux)
+	}
+	xv := m.PeekValue(1)
+	cv, _ := xv.V.(*ChanValue)
+	v, ok, w := m.chanRecv(cv)
+	if w != nil {
+		// blocked: receive again once resumed.
+		m.PushExpr(ux)
+		m.PushOp(OpUrecv)
+		m.park(w)
+		return
+	}
+	if !ok { // closed channel
+		v = defaultTypedValue(m.Alloc, baseOf(xv.T).(*ChanType).Elt)
+	}
+	*xv = v
+	if ux.HasOK {
+		m.PushValue(untypedBool(ok))
+	}
 }
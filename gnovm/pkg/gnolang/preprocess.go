@@ -590,6 +590,12 @@ func preprocess1(store Store, ctx BlockNode, n Node) Node {
 				switch xt.Kind() {
 				case MapKind:
 					n.IsMap = true
+				case ChanKind:
+					assertChanOperand(n.X, xt, "range over", RECV)
+					if n.Value != nil {
+						panic(fmt.Sprintf("range over %s permits only one iteration variable", n.X))
+					}
+					n.IsChan = true
 				case StringKind:
 					n.IsString = true
 				case PointerKind:
@@ -612,6 +618,12 @@ func preprocess1(store Store, ctx BlockNode, n Node) Node {
 							vn := n.Value.(*NameExpr).Name
 							last.Define(vn, anyValue(vt))
 						}
+					} else if n.IsChan {
+						if n.Key != nil {
+							et := baseOf(xt).(*ChanType).Elt
+							kn := n.Key.(*NameExpr).Name
+							last.Define(kn, anyValue(et))
+						}
 					} else if xt.Kind() == StringKind {
 						if n.Key != nil {
 							it := IntType
@@ -1476,6 +1488,11 @@ func preprocess1(store Store, ctx BlockNode, n Node) Node {
 								n.Args[1] = args1
 							}
 						}
+					} else if fv.PkgPath == uversePkgPath && fv.Name == "close" {
+						if len(n.Args) == 1 {
+							xt := evalStaticTypeOf(store, last, n.Args[0])
+							assertChanOperand(n.Args[0], xt, "close", SEND)
+						}
 					}
 				}
 
@@ -1686,6 +1703,11 @@ func preprocess1(store Store, ctx BlockNode, n Node) Node {
 			// TRANS_LEAVE -----------------------
 			case *UnaryExpr:
 				xt := evalStaticTypeOf(store, last, n.X)
+				if n.Op == ARROW {
+					// receive operations are never constant.
+					assertChanOperand(n.X, xt, "receive from", RECV)
+					return n, TRANS_CONTINUE
+				}
 				n.AssertCompatible(xt)
 				if xnt, ok := xt.(*NativeType); ok {
 					// get concrete native base type.
@@ -2121,7 +2143,7 @@ func preprocess1(store Store, ctx BlockNode, n Node) Node {
 					} else {
 						// Make sure that the label exists, either for a switch or a
 						// BranchStmt.
-						if !isSwitchLabel(ns, n.Label) {
+						if !isSwitchLabel(ns, n.Label) && !isSelectLabel(ns, n.Label) {
 							findBranchLabel(last, n.Label)
 						}
 					}
@@ -2129,7 +2151,7 @@ func preprocess1(store Store, ctx BlockNode, n Node) Node {
 					if n.Label == "" {
 						findContinuableNode(last, store)
 					} else {
-						if isSwitchLabel(ns, n.Label) {
+						if isSwitchLabel(ns, n.Label) || isSelectLabel(ns, n.Label) {
 							panic(fmt.Sprintf("invalid continue label %q\n", n.Label))
 						}
 						findBranchLabel(last, n.Label)
@@ -2251,8 +2273,10 @@ func preprocess1(store Store, ctx BlockNode, n Node) Node {
 
 			// TRANS_LEAVE -----------------------
 			case *SendStmt:
-				// Value consts become default *ConstExprs.
-				checkOrConvertType(store, last, n, &n.Value, nil, false)
+				xt := evalStaticTypeOf(store, last, n.Chan)
+				ct := assertChanOperand(n.Chan, xt, "send to", SEND)
+				// Value consts become *ConstExprs of the element type.
+				checkOrConvertType(store, last, n, &n.Value, ct.Elt, false)
 
 			// TRANS_LEAVE -----------------------
 			case *SelectCaseStmt:
@@ -2260,6 +2284,24 @@ func preprocess1(store Store, ctx BlockNode, n Node) Node {
 				// if as, ok := n.Comm.(*AssignStmt); ok {
 				//     handled by case *AssignStmt.
 				// }
+				switch comm := n.Comm.(type) {
+				case nil, *SendStmt:
+					// default, or send case.
+				case *ExprStmt:
+					if !isRecvExpr(comm.X) {
+						panic("select case must be receive, send or assign recv")
+					}
+				case *AssignStmt:
+					if len(comm.Lhs) > 2 || len(comm.Rhs) != 1 || !isRecvExpr(comm.Rhs[0]) {
+						panic("select case must be receive, send or assign recv")
+					}
+				default:
+					panic("select case must be receive, send or assign recv")
+				}
+
+			// TRANS_LEAVE -----------------------
+			case *GoStmt:
+				assertGoCall(store, last, &n.Call)
 
 			// TRANS_LEAVE -----------------------
 			case *SwitchStmt:
@@ -2547,6 +2589,16 @@ func parseMultipleAssignFromOneExpr(
 		}
 		tuple = &tupleType{Elts: []Type{mt.Value, BoolType}}
 		expr.HasOK = true
+	case *UnaryExpr:
+		// Channel receive case:
+		// var a, b = <-n
+		// a, b := <-n
+		if expr.Op != ARROW {
+			panic(fmt.Sprintf("unexpected value expression %s", expr))
+		}
+		ct := assertChanOperand(expr.X, evalStaticTypeOf(store, bn, expr.X), "receive from", RECV)
+		tuple = &tupleType{Elts: []Type{ct.Elt, BoolType}}
+		expr.HasOK = true
 	default:
 		panic(fmt.Sprintf("unexpected value expression type %T", expr))
 	}
@@ -3351,7 +3403,7 @@ func findBreakableNode(last BlockNode, store Store) {
 			return
 		case *RangeStmt:
 			return
-		case *SwitchClauseStmt:
+		case *SwitchClauseStmt, *SelectCaseStmt:
 			return
 		}
 
@@ -3462,6 +3514,20 @@ func lastDecl(ns []Node) Decl {
 	return nil
 }
 
+// isSelectLabel returns whether label is the label of a select statement
+// enclosing the last node of ns.
+func isSelectLabel(ns []Node, label Name) bool {
+	if label == "" {
+		return false
+	}
+	for i := len(ns) - 1; 0 <= i; i-- {
+		if ss, ok := ns[i].(*SelectStmt); ok && ss.GetLabel() == label {
+			return true
+		}
+	}
+	return false
+}
+
 func lastSwitch(ns []Node) *SwitchStmt {
 	for i := len(ns) - 1; 0 <= i; i-- {
 		if d, ok := ns[i].(*SwitchStmt); ok {
@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	bm "github.com/gnolang/gno/gnovm/pkg/benchops"
+	"github.com/gnolang/gno/tm2/pkg/std"
 )
 
 /*
@@ -97,6 +98,17 @@ type Realm struct {
 	Path string
 	Time uint64
 
+	// Storage is the number of bytes of the persisted objects of the realm,
+	// and Deposit the coins locked for them, in the denoms of the storage
+	// prices at which they were locked, see RealmStorageDiffs.
+	Storage uint64
+	Deposit std.Coins
+
+	// EscapedFuncs is set once functions of the realm are held by the
+	// objects of other realms, which then need their code: the realm
+	// cannot be upgraded anymore.
+	EscapedFuncs bool
+
 	newCreated []Object
 	newEscaped []Object
 	newDeleted []Object
@@ -105,6 +117,8 @@ type Realm struct {
 	updated []Object // real objects that were modified.
 	deleted []Object // real objects that became deleted.
 	escaped []Object // real objects with refcount > 1.
+
+	sumDiff int64 // storage diff of the transaction, in bytes.
 }
 
 // Creates a blank new realm with counter 0.
@@ -356,6 +370,15 @@ func (rlm *Realm) FinalizeRealmTransaction(readonly bool, store Store) {
 	rlm.saveUnsavedObjects(store)
 	// delete all deleted objects.
 	rlm.removeDeletedObjects(store)
+	// save new realm time and storage size.
+	// throwaway realms of non-realm packages
+	// do not account for storage.
+	if !IsRealmPath(rlm.Path) {
+		rlm.sumDiff = 0
+	}
+	if len(rlm.newCreated) > 0 || rlm.sumDiff != 0 {
+		store.SetPackageRealm(rlm)
+	}
 	// reset realm state for new transaction.
 	rlm.clearMarks()
 }
@@ -389,10 +412,6 @@ func (rlm *Realm) processNewCreatedMarks(store Store) {
 			rlm.incRefCreatedDescendants(store, oo)
 		}
 	}
-	// Save new realm time.
-	if len(rlm.newCreated) > 0 {
-		store.SetPackageRealm(rlm)
-	}
 }
 
 // oo must be marked new-real, and ref-count already incremented.
@@ -514,6 +533,12 @@ func (rlm *Realm) decRefDeletedDescendants(store Store, oo Object) {
 	// recurse for children
 	more := getChildObjects2(store, oo)
 	for _, child := range more {
+		if _, ok := child.(*PackageValue); ok {
+			// extern package values are skipped, like in
+			// incRefCreatedDescendants: they are not owned
+			// by oo, e.g. the imports of a deleted file block.
+			continue
+		}
 		child.DecRefCount()
 		rc := child.GetRefCount()
 		if rc == 0 {
@@ -754,19 +779,55 @@ func (rlm *Realm) saveObject(store Store, oo Object) {
 	}
 	// set object to store.
 	// NOTE: also sets the hash to object.
-	store.SetObject(oo)
+	rlm.sumDiff += store.SetObject(oo)
+	// mark the other realms whose functions are held.
+	rlm.markEscapedFuncs(store, oo)
 	// set index.
 	if oo.GetIsEscaped() {
 		// XXX save oid->hash to iavl.
 	}
 }
 
+// markEscapedFuncs sets EscapedFuncs on the realms, other than rlm, of the
+// functions held by oo. Objects held by oo are saved separately.
+func (rlm *Realm) markEscapedFuncs(store Store, oo Object) {
+	var tvs []TypedValue
+	switch cv := oo.(type) {
+	case *ArrayValue:
+		tvs = cv.List
+	case *StructValue:
+		tvs = cv.Fields
+	case *Block:
+		tvs = cv.Values
+	case *HeapItemValue:
+		tvs = []TypedValue{cv.Value}
+	case *MapValue:
+		for cur := cv.List.Head; cur != nil; cur = cur.Next {
+			tvs = append(tvs, cur.Key, cur.Value)
+		}
+	case *BoundMethodValue:
+		tvs = []TypedValue{{V: cv.Func}}
+	}
+	for _, tv := range tvs {
+		fv, ok := tv.V.(*FuncValue)
+		if !ok || fv.PkgPath == rlm.Path || !IsRealmPath(fv.PkgPath) {
+			continue
+		}
+		pv := store.GetPackage(fv.PkgPath, false)
+		if pv == nil || pv.Realm == nil || pv.Realm.EscapedFuncs {
+			continue
+		}
+		pv.Realm.EscapedFuncs = true
+		store.SetPackageRealm(pv.Realm)
+	}
+}
+
 //----------------------------------------
 // removeDeletedObjects
 
 func (rlm *Realm) removeDeletedObjects(store Store) {
 	for _, do := range rlm.deleted {
-		store.DelObject(do)
+		rlm.sumDiff -= store.DelObject(do)
 	}
 }
 
@@ -800,6 +861,7 @@ func (rlm *Realm) clearMarks() {
 	rlm.updated = nil
 	rlm.deleted = nil
 	rlm.escaped = nil
+	rlm.sumDiff = 0
 }
 
 //----------------------------------------
@@ -891,6 +953,8 @@ func getChildObjects(val Value, more []Value) []Value {
 		return more
 	case *NativeValue:
 		panic("native values not supported")
+	case *ChanValue:
+		panic("channel values cannot be persisted")
 	default:
 		panic(fmt.Sprintf(
 			"unexpected type %v",
@@ -899,11 +963,16 @@ func getChildObjects(val Value, more []Value) []Value {
 }
 
 // like getChildObjects() but loads RefValues into objects.
+// RefValues of extern packages are skipped, as they have no object ID,
+// and the callers skip the loaded package values anyway.
 func getChildObjects2(store Store, val Value) []Object {
 	chos := getChildObjects(val, nil)
 	objs := make([]Object, 0, len(chos))
 	for _, child := range chos {
 		if ref, ok := child.(RefValue); ok {
+			if ref.PkgPath != "" {
+				continue
+			}
 			oo := store.GetObject(ref.ObjectID)
 			objs = append(objs, oo)
 		} else if oo, ok := child.(Object); ok {
@@ -1237,6 +1306,8 @@ func copyValueWithRefs(val Value) Value {
 		return hiv
 	case *NativeValue:
 		panic("native values not supported")
+	case *ChanValue:
+		panic("channel values cannot be persisted")
 	default:
 		panic(fmt.Sprintf(
 			"unexpected type %v",
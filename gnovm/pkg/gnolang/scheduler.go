@@ -0,0 +1,315 @@
+package gnolang
+
+// The scheduler of the Machine runs goroutines cooperatively, on a single
+// thread: the running goroutine only yields when it blocks on a channel
+// operation or a select statement, or when it exits. The goroutines are then
+// resumed in the order in which they were started or blocked, which makes the
+// execution deterministic.
+//
+// Goroutines and channels are only supported when running code locally, with
+// `gno run` and `gno test`: the type checker rejects them in packages which
+// are added on chain.
+
+// goroutineStackSize is the initial size of the op and value stacks of the
+// goroutines started by go statements.
+const goroutineStackSize = 64
+
+// deadlockError is the panic value when all the goroutines are blocked.
+const deadlockError = "fatal error: all goroutines are asleep - deadlock!"
+
+// goroutine is the state of a goroutine, saved when it is not running.
+type goroutine struct {
+	id   int
+	wait *goroutineWait // set while blocked
+
+	ops             []Op
+	numOps          int
+	values          []TypedValue
+	numValues       int
+	exprs           []Expr
+	stmts           []Stmt
+	blocks          []*Block
+	frames          []*Frame
+	pkg             *PackageValue
+	realm           *Realm
+	exceptions      []Exception
+	numResults      int
+	panicScope      uint
+	deferPanicScope uint
+}
+
+// runnable returns whether the goroutine can be resumed.
+func (g *goroutine) runnable() bool {
+	return g.wait == nil || g.wait.done
+}
+
+// save saves the state of the machine into g.
+func (g *goroutine) save(m *Machine) {
+	g.ops, g.numOps = m.Ops, m.NumOps
+	g.values, g.numValues = m.Values, m.NumValues
+	g.exprs = m.Exprs
+	g.stmts = m.Stmts
+	g.blocks = m.Blocks
+	g.frames = m.Frames
+	g.pkg = m.Package
+	g.realm = m.Realm
+	g.exceptions = m.Exceptions
+	g.numResults = m.NumResults
+	g.panicScope = m.PanicScope
+	g.deferPanicScope = m.DeferPanicScope
+}
+
+// restore restores the state of the machine from g.
+func (g *goroutine) restore(m *Machine) {
+	m.Ops, m.NumOps = g.ops, g.numOps
+	m.Values, m.NumValues = g.values, g.numValues
+	m.Exprs = g.exprs
+	m.Stmts = g.stmts
+	m.Blocks = g.blocks
+	m.Frames = g.frames
+	m.Package = g.pkg
+	m.Realm = g.realm
+	m.Exceptions = g.exceptions
+	m.NumResults = g.numResults
+	m.PanicScope = g.panicScope
+	m.DeferPanicScope = g.deferPanicScope
+}
+
+// goroutineWait is the reason a goroutine is blocked. It is done when one of
+// the channel operations it waits for completes.
+type goroutineWait struct {
+	done  bool
+	index int        // index of the completed select case
+	value TypedValue // received value
+	ok    bool       // false if the channel was closed
+}
+
+func (w *goroutineWait) complete(index int, value TypedValue, ok bool) {
+	w.done = true
+	w.index = index
+	w.value = value
+	w.ok = ok
+}
+
+// chanWaiter is a goroutine blocked sending to, or receiving from, a channel.
+type chanWaiter struct {
+	wait  *goroutineWait
+	index int        // index of the select case, if any
+	value TypedValue // value to send
+}
+
+// dequeueWaiter removes and returns the first waiter of q which is still
+// blocked; the waiters of a select statement which already completed are
+// discarded.
+func dequeueWaiter(q *[]*chanWaiter) *chanWaiter {
+	for len(*q) > 0 {
+		w := (*q)[0]
+		(*q)[0] = nil
+		*q = (*q)[1:]
+		if !w.wait.done {
+			return w
+		}
+	}
+	return nil
+}
+
+type scheduler struct {
+	main    *goroutine
+	current *goroutine
+	queue   []*goroutine // goroutines which are not running
+	lastID  int
+}
+
+// next removes and returns the first runnable goroutine of the queue, or nil
+// if there is none.
+func (s *scheduler) next() *goroutine {
+	for i, g := range s.queue {
+		if g.runnable() {
+			s.queue = append(s.queue[:i:i], s.queue[i+1:]...)
+			return g
+		}
+	}
+	return nil
+}
+
+// ----------------------------------------
+// ChanValue operations
+
+// trySend sends v to the channel if it does not block, and returns whether
+// it was sent.
+func (cv *ChanValue) trySend(v TypedValue) bool {
+	if cv.Closed {
+		panic(&Exception{Value: typedString("send on closed channel")})
+	}
+	if r := dequeueWaiter(&cv.recvq); r != nil {
+		r.wait.complete(r.index, v, true)
+		return true
+	}
+	if len(cv.Buffer) < cv.Cap {
+		cv.Buffer = append(cv.Buffer, v)
+		return true
+	}
+	return false
+}
+
+// tryRecv receives a value from the channel if it does not block; ready
+// is false otherwise. ok is false if the channel is closed and empty.
+func (cv *ChanValue) tryRecv() (v TypedValue, ok, ready bool) {
+	if len(cv.Buffer) > 0 {
+		v = cv.Buffer[0]
+		cv.Buffer[0] = TypedValue{}
+		cv.Buffer = cv.Buffer[1:]
+		// Make room for the first blocked sender.
+		if s := dequeueWaiter(&cv.sendq); s != nil {
+			cv.Buffer = append(cv.Buffer, s.value)
+			s.wait.complete(s.index, TypedValue{}, true)
+		}
+		return v, true, true
+	}
+	if s := dequeueWaiter(&cv.sendq); s != nil {
+		s.wait.complete(s.index, TypedValue{}, true)
+		return s.value, true, true
+	}
+	if cv.Closed {
+		return TypedValue{}, false, true
+	}
+	return TypedValue{}, false, false
+}
+
+// close closes the channel, waking up all the goroutines blocked on it.
+func (cv *ChanValue) close() {
+	if cv.Closed {
+		panic(&Exception{Value: typedString("close of closed channel")})
+	}
+	cv.Closed = true
+	for r := dequeueWaiter(&cv.recvq); r != nil; r = dequeueWaiter(&cv.recvq) {
+		r.wait.complete(r.index, TypedValue{}, false)
+	}
+	for s := dequeueWaiter(&cv.sendq); s != nil; s = dequeueWaiter(&cv.sendq) {
+		s.wait.complete(s.index, TypedValue{}, false)
+	}
+}
+
+// ----------------------------------------
+// Machine scheduling
+
+// goroutine returns the running goroutine.
+func (m *Machine) goroutine() *goroutine {
+	if m.sched.current == nil {
+		m.sched.lastID++
+		m.sched.main = &goroutine{id: m.sched.lastID}
+		m.sched.current = m.sched.main
+	}
+	return m.sched.current
+}
+
+// resumeWait returns the completed wait of the running goroutine, if it was
+// just resumed after blocking, and resets it.
+func (m *Machine) resumeWait() *goroutineWait {
+	g := m.goroutine()
+	w := g.wait
+	g.wait = nil
+	return w
+}
+
+// switchTo saves the state of the running goroutine, and resumes g.
+func (m *Machine) switchTo(g *goroutine) {
+	m.goroutine().save(m)
+	g.restore(m)
+	m.sched.current = g
+}
+
+// park blocks the running goroutine until w is done, and resumes the next
+// runnable goroutine. The operation which blocked must be pushed back before
+// calling park, to be executed again when the goroutine is resumed.
+func (m *Machine) park(w *goroutineWait) {
+	cur := m.goroutine()
+	cur.wait = w
+	next := m.sched.next()
+	if next == nil {
+		panic(deadlockError)
+	}
+	m.sched.queue = append(m.sched.queue, cur)
+	m.switchTo(next)
+}
+
+// restoreMain switches back to the main goroutine, if another one is running;
+// for example, after it panicked.
+func (m *Machine) restoreMain() {
+	if m.sched.current != nil && m.sched.current != m.sched.main {
+		m.switchTo(m.sched.main)
+	}
+}
+
+// chanRecv receives a value from cv, which may be nil. If the receive
+// blocks, it returns the wait to pass to park.
+func (m *Machine) chanRecv(cv *ChanValue) (v TypedValue, ok bool, w *goroutineWait) {
+	if w := m.resumeWait(); w != nil {
+		return w.value, w.ok, nil
+	}
+	w = &goroutineWait{}
+	if cv != nil { // receiving from a nil channel blocks forever.
+		if v, ok, ready := cv.tryRecv(); ready {
+			return v, ok, nil
+		}
+		cv.recvq = append(cv.recvq, &chanWaiter{wait: w})
+	}
+	return TypedValue{}, false, w
+}
+
+// chanSend sends a copy of v to cv, which may be nil. If the send blocks, it
+// returns the wait to pass to park.
+func (m *Machine) chanSend(cv *ChanValue, v *TypedValue) *goroutineWait {
+	if w := m.resumeWait(); w != nil {
+		if !w.ok {
+			panic(&Exception{Value: typedString("send on closed channel")})
+		}
+		return nil
+	}
+	w := &goroutineWait{}
+	if cv != nil { // sending to a nil channel blocks forever.
+		cv2 := v.Copy(m.Alloc)
+		if cv.trySend(cv2) {
+			return nil
+		}
+		cv.sendq = append(cv.sendq, &chanWaiter{wait: w, value: cv2})
+	}
+	return w
+}
+
+func (m *Machine) doOpGo() {
+	gs := m.PopStmt().(*GoStmt)
+	// Pop arguments and func, which are passed to the new goroutine.
+	numArgs := gs.Call.NumArgs
+	values := make([]TypedValue, max(goroutineStackSize, numArgs+1))
+	copy(values[1:], m.PopValues(numArgs))
+	values[0] = *m.PopValue()
+
+	m.goroutine() // ensure the main goroutine exists.
+	m.sched.lastID++
+	ops := make([]Op, goroutineStackSize)
+	ops[0] = OpGoexit
+	ops[1] = OpPrecall
+	g := &goroutine{
+		id:        m.sched.lastID,
+		ops:       ops,
+		numOps:    2,
+		values:    values,
+		numValues: numArgs + 1,
+		exprs:     []Expr{&gs.Call},
+		blocks:    []*Block{m.LastBlock()},
+		pkg:       m.Package,
+		realm:     m.Realm,
+	}
+	m.sched.queue = append(m.sched.queue, g)
+}
+
+func (m *Machine) doOpGoexit() {
+	// The state of the exiting goroutine is discarded.
+	next := m.sched.next()
+	if next == nil {
+		panic(deadlockError)
+	}
+	next.restore(m)
+	m.sched.current = next
+}
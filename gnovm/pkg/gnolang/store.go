@@ -43,8 +43,8 @@ type Store interface {
 	SetPackageRealm(*Realm)
 	GetObject(oid ObjectID) Object
 	GetObjectSafe(oid ObjectID) Object
-	SetObject(Object)
-	DelObject(Object)
+	SetObject(Object) int64 // returns the storage diff, in bytes, if accounted for.
+	DelObject(Object) int64 // returns the storage freed, in bytes, if accounted for.
 	GetType(tid TypeID) Type
 	GetTypeSafe(tid TypeID) Type
 	SetCacheType(Type)
@@ -54,6 +54,8 @@ type Store interface {
 	SetBlockNode(BlockNode)
 
 	// UNSTABLE
+	DelType(tid TypeID)        // to replace the types of an upgraded package.
+	DelBlockNode(loc Location) // to remove the nodes of an upgraded package.
 	Go2GnoType(rt reflect.Type) Type
 	GetAllocator() *Allocator
 	NumMemPackages() int64
@@ -70,6 +72,10 @@ type Store interface {
 	SetLogStoreOps(enabled bool)
 	SprintStoreOps() string
 	LogSwitchRealm(rlmpath string) // to mark change of realm boundaries
+	// RealmStorageDiffs returns the storage diffs of the realms, in
+	// bytes, keyed by realm path, since the beginning of the transaction.
+	// Entries may be deleted from the returned map once processed.
+	RealmStorageDiffs() map[string]int64
 	Print()
 }
 
@@ -82,6 +88,16 @@ type TransactionStore interface {
 	// Write commits the current buffered transaction data to the underlying store.
 	// It also clears the current buffer of the transaction.
 	Write()
+
+	// SetGasConfig sets the gas costs of the transaction, which are otherwise
+	// those of the parent store. It must be called before any store access.
+	SetGasConfig(GasConfig)
+
+	// SetStorageAccounting sets whether the storage of the realms is
+	// accounted for in the transaction, see RealmStorageDiffs; it saves the
+	// storage of the realms whose objects are saved or deleted. It must be
+	// called before any store access.
+	SetStorageAccounting(enabled bool)
 }
 
 // Gas consumption descriptors.
@@ -95,6 +111,8 @@ const (
 	GasAddMemPackageDesc   = "AddMemPackagePerByte"
 	GasGetMemPackageDesc   = "GetMemPackagePerByte"
 	GasDeleteObjectDesc    = "DeleteObjectFlat"
+	GasGetParamFlatDesc    = "GetParamFlat"
+	GasGetParamDesc        = "GetParamPerByte"
 )
 
 // GasConfig defines gas cost for each operation on KVStores
@@ -108,6 +126,8 @@ type GasConfig struct {
 	GasAddMemPackage   int64
 	GasGetMemPackage   int64
 	GasDeleteObject    int64
+	GasGetParamFlat    int64
+	GasGetParam        int64
 }
 
 // DefaultGasConfig returns a default gas config for KVStores.
@@ -122,6 +142,25 @@ func DefaultGasConfig() GasConfig {
 		GasAddMemPackage:   8,    // per byte cost
 		GasGetMemPackage:   8,    // per byte cost
 		GasDeleteObject:    3715, // flat cost
+		GasGetParamFlat:    1000, // flat cost, of the params read by realms
+		GasGetParam:        3,    // per byte cost, of the params read by realms
+	}
+}
+
+// fields returns the gas costs of gc, by field name.
+func (gc *GasConfig) fields() map[string]*int64 {
+	return map[string]*int64{
+		"GasGetObject":       &gc.GasGetObject,
+		"GasSetObject":       &gc.GasSetObject,
+		"GasGetType":         &gc.GasGetType,
+		"GasSetType":         &gc.GasSetType,
+		"GasGetPackageRealm": &gc.GasGetPackageRealm,
+		"GasSetPackageRealm": &gc.GasSetPackageRealm,
+		"GasAddMemPackage":   &gc.GasAddMemPackage,
+		"GasGetMemPackage":   &gc.GasGetMemPackage,
+		"GasDeleteObject":    &gc.GasDeleteObject,
+		"GasGetParamFlat":    &gc.GasGetParamFlat,
+		"GasGetParam":        &gc.GasGetParam,
 	}
 }
 
@@ -142,12 +181,15 @@ type defaultStore struct {
 	nativeResolver   NativeResolver        // for injecting natives
 
 	// transient
-	opslog  []StoreOp // for debugging and testing.
-	current []string  // for detecting import cycles.
+	opslog            []StoreOp        // for debugging and testing.
+	current           []string         // for detecting import cycles.
+	realmStorageDiffs map[string]int64 // for storage deposits.
 
 	// gas
 	gasMeter  store.GasMeter
 	gasConfig GasConfig
+
+	storageAccounting bool // for storage deposits.
 }
 
 func NewStore(alloc *Allocator, baseStore, iavlStore store.Store) *defaultStore {
@@ -166,6 +208,9 @@ func NewStore(alloc *Allocator, baseStore, iavlStore store.Store) *defaultStore
 		cacheNativeTypes: make(map[reflect.Type]Type),
 		nativeResolver:   nil,
 		gasConfig:        DefaultGasConfig(),
+
+		// transient
+		realmStorageDiffs: make(map[string]int64),
 	}
 	InitStoreCaches(ds)
 	return ds
@@ -200,8 +245,9 @@ func (ds *defaultStore) BeginTransaction(baseStore, iavlStore store.Store, gasMe
 		gasConfig: ds.gasConfig,
 
 		// transient
-		current: nil,
-		opslog:  nil,
+		current:           nil,
+		opslog:            nil,
+		realmStorageDiffs: make(map[string]int64),
 	}
 	ds2.SetCachePackage(Uverse())
 
@@ -210,6 +256,14 @@ func (ds *defaultStore) BeginTransaction(baseStore, iavlStore store.Store, gasMe
 
 type transactionStore struct{ *defaultStore }
 
+func (t transactionStore) SetGasConfig(gc GasConfig) {
+	t.gasConfig = gc
+}
+
+func (t transactionStore) SetStorageAccounting(enabled bool) {
+	t.storageAccounting = enabled
+}
+
 func (t transactionStore) Write() {
 	t.cacheTypes.(txlog.MapCommitter[TypeID, Type]).Commit()
 	t.cacheNodes.(txlog.MapCommitter[Location, BlockNode]).Commit()
@@ -382,6 +436,17 @@ func (ds *defaultStore) SetPackageRealm(rlm *Realm) {
 			bm.StopStore(size)
 		}()
 	}
+	if rlm.sumDiff != 0 {
+		// account for the objects saved or deleted
+		// since the realm was last set.
+		// the objects of realms persisted before the storage
+		// was accounted for are not counted, so the storage
+		// is clamped at zero when they are deleted.
+		storage := max(int64(rlm.Storage)+rlm.sumDiff, 0)
+		ds.realmStorageDiffs[rlm.Path] += storage - int64(rlm.Storage)
+		rlm.Storage = uint64(storage)
+		rlm.sumDiff = 0
+	}
 	oid := ObjectIDFromPkgPath(rlm.Path)
 	key := backendRealmKey(oid)
 	bz := amino.MustMarshal(rlm)
@@ -470,7 +535,7 @@ func (ds *defaultStore) loadObjectSafe(oid ObjectID) Object {
 
 // NOTE: unlike GetObject(), SetObject() is also used to persist updated
 // package values.
-func (ds *defaultStore) SetObject(oo Object) {
+func (ds *defaultStore) SetObject(oo Object) int64 {
 	if bm.OpsEnabled {
 		bm.PauseOpCode()
 		defer bm.ResumeOpCode()
@@ -496,11 +561,19 @@ func (ds *defaultStore) SetObject(oo Object) {
 	}
 	oo.SetHash(ValueHash{hash})
 	// save bytes to backend.
+	var diff int64
 	if ds.baseStore != nil {
 		key := backendObjectKey(oid)
 		hashbz := make([]byte, len(hash)+len(bz))
 		copy(hashbz, hash.Bytes())
 		copy(hashbz[HashSize:], bz)
+		if ds.storageAccounting {
+			diff = int64(len(hashbz))
+			if !oo.GetIsNewReal() {
+				// the previous version of the object is replaced.
+				diff -= int64(len(ds.baseStore.Get([]byte(key))))
+			}
+		}
 		ds.baseStore.Set([]byte(key), hashbz)
 		size = len(hashbz)
 	}
@@ -536,9 +609,10 @@ func (ds *defaultStore) SetObject(oo Object) {
 		value = hash.Bytes()
 		ds.iavlStore.Set(key, value)
 	}
+	return diff
 }
 
-func (ds *defaultStore) DelObject(oo Object) {
+func (ds *defaultStore) DelObject(oo Object) int64 {
 	if bm.OpsEnabled {
 		bm.PauseOpCode()
 		defer bm.ResumeOpCode()
@@ -555,8 +629,12 @@ func (ds *defaultStore) DelObject(oo Object) {
 	// delete from cache.
 	delete(ds.cacheObjects, oid)
 	// delete from backend.
+	var size int64
 	if ds.baseStore != nil {
 		key := backendObjectKey(oid)
+		if ds.storageAccounting {
+			size = int64(len(ds.baseStore.Get([]byte(key))))
+		}
 		ds.baseStore.Delete([]byte(key))
 	}
 	// make realm op log entry
@@ -564,6 +642,7 @@ func (ds *defaultStore) DelObject(oo Object) {
 		ds.opslog = append(ds.opslog,
 			StoreOp{Type: StoreOpDel, Object: oo})
 	}
+	return size
 }
 
 // NOTE: not used quite yet.
@@ -661,6 +740,16 @@ func (ds *defaultStore) SetType(tt Type) {
 	ds.cacheTypes.Set(tid, tt)
 }
 
+// DelType removes the type from the cache and the backend, so that it can be
+// replaced using SetType.
+func (ds *defaultStore) DelType(tid TypeID) {
+	if ds.baseStore != nil {
+		key := backendTypeKey(tid)
+		ds.baseStore.Delete([]byte(key))
+	}
+	ds.cacheTypes.Delete(tid)
+}
+
 func (ds *defaultStore) GetBlockNode(loc Location) BlockNode {
 	bn := ds.GetBlockNodeSafe(loc)
 	if bn == nil {
@@ -725,6 +814,11 @@ func (ds *defaultStore) SetBlockNode(bn BlockNode) {
 	// XXX
 }
 
+func (ds *defaultStore) DelBlockNode(loc Location) {
+	// NOTE: nodes are not persisted, see SetBlockNode.
+	ds.cacheNodes.Delete(loc)
+}
+
 func (ds *defaultStore) NumMemPackages() int64 {
 	ctrkey := []byte(backendPackageIndexCtrKey())
 	ctrbz := ds.baseStore.Get(ctrkey)
@@ -848,16 +942,27 @@ func (ds *defaultStore) IterMemPackage() <-chan *gnovm.MemPackage {
 		if err != nil {
 			panic(err)
 		}
+		// An upgraded package is indexed again, after the packages
+		// it may now import; only its last index is iterated.
+		paths := make([]string, ctr)
+		last := make(map[string]int, ctr)
+		for i := range paths {
+			idxkey := []byte(backendPackageIndexKey(uint64(i + 1)))
+			path := ds.baseStore.Get(idxkey)
+			if path == nil {
+				panic(fmt.Sprintf(
+					"missing package index %d", i+1))
+			}
+			paths[i] = string(path)
+			last[string(path)] = i
+		}
 		ch := make(chan *gnovm.MemPackage, 0)
 		go func() {
-			for i := uint64(1); i <= uint64(ctr); i++ {
-				idxkey := []byte(backendPackageIndexKey(i))
-				path := ds.baseStore.Get(idxkey)
-				if path == nil {
-					panic(fmt.Sprintf(
-						"missing package index %d", i))
+			for i, path := range paths {
+				if last[path] != i {
+					continue
 				}
-				memPkg := ds.GetMemPackage(string(path))
+				memPkg := ds.GetMemPackage(path)
 				ch <- memPkg
 			}
 			close(ch)
@@ -957,6 +1062,10 @@ func (ds *defaultStore) SprintStoreOps() string {
 	return strings.Join(ss, "\n")
 }
 
+func (ds *defaultStore) RealmStorageDiffs() map[string]int64 {
+	return ds.realmStorageDiffs
+}
+
 func (ds *defaultStore) LogSwitchRealm(rlmpath string) {
 	ds.opslog = append(ds.opslog,
 		StoreOp{Type: StoreOpSwitchRealm, RlmPath: rlmpath})
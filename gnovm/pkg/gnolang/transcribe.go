@@ -578,9 +578,11 @@ func transcribe(t Transform, ns []Node, ftype TransField, index int, n Node, nc
 		} else {
 			cnn = cnn2.(*SelectCaseStmt)
 		}
-		cnn.Comm = transcribe(t, nns, TRANS_SELECTCASE_COMM, 0, cnn.Comm, &c).(Stmt)
-		if isStopOrSkip(nc, c) {
-			return
+		if cnn.Comm != nil { // nil for the default case.
+			cnn.Comm = transcribe(t, nns, TRANS_SELECTCASE_COMM, 0, cnn.Comm, &c).(Stmt)
+			if isStopOrSkip(nc, c) {
+				return
+			}
 		}
 		// iterate over Body; its length can change if a statement is decomposed.
 		for idx := 0; idx < len(cnn.Body); idx++ {
@@ -163,13 +163,14 @@ func assertComparable2(dt Type) {
 	case *StructType:
 		for _, f := range cdt.Fields {
 			switch cft := baseOf(f.Type).(type) {
-			case PrimitiveType, *PointerType, *InterfaceType, *NativeType, *ArrayType, *StructType:
+			case PrimitiveType, *PointerType, *InterfaceType, *NativeType, *ArrayType, *StructType, *ChanType:
 				assertComparable2(cft)
 			default:
 				panic(fmt.Sprintf("%v is not comparable", dt))
 			}
 		}
 	case *PointerType: // &a == &b
+	case *ChanType: // a == b if created by the same make
 	case *InterfaceType:
 	case *SliceType, *FuncType, *MapType:
 	case *NativeType:
@@ -249,10 +250,10 @@ func checkValDefineMismatch(n Node) {
 	}
 
 	// Special case for single value.
-	// If the value is a call expression, type assertion, or index expression,
-	// it can be assigned to multiple variables.
+	// If the value is a call expression, type assertion, index expression,
+	// or channel receive, it can be assigned to multiple variables.
 	if numValues == 1 {
-		switch values[0].(type) {
+		switch vx := values[0].(type) {
 		case *CallExpr:
 			return
 		case *TypeAssertExpr:
@@ -265,6 +266,13 @@ func checkValDefineMismatch(n Node) {
 				panic(fmt.Sprintf("assignment mismatch: %d variable(s) but %d value(s)", numNames, numValues))
 			}
 			return
+		case *UnaryExpr:
+			if vx.Op == ARROW {
+				if numNames != 2 {
+					panic(fmt.Sprintf("assignment mismatch: %d variable(s) but %d value(s)", numNames, numValues))
+				}
+				return
+			}
 		}
 	}
 
@@ -566,7 +574,18 @@ func checkAssignableTo(n Node, xt, dt Type, autoNative bool) error {
 		return errors.New("should not happen")
 	case *DeclaredType:
 		panic("should not happen")
-	case *FuncType, *StructType, *PackageType, *ChanType, *TypeType:
+	case *ChanType:
+		if ct, ok := xt.(*ChanType); ok {
+			// a bidirectional channel is assignable to a
+			// directional channel of the same element type.
+			if ct.Dir == BOTH && checkSame(ct.Elt, cdt.Elt, "") == nil {
+				return nil // ok
+			}
+		}
+		if xt.TypeID() == cdt.TypeID() {
+			return nil // ok
+		}
+	case *FuncType, *StructType, *PackageType, *TypeType:
 		if xt.TypeID() == cdt.TypeID() {
 			return nil // ok
 		}
@@ -781,6 +800,29 @@ func (x *UnaryExpr) AssertCompatible(t Type) {
 	}
 }
 
+// assertChanOperand asserts that x, of type xt, is a channel which can be
+// used for the operation op ("send to", "receive from" or "close"), in the
+// direction dir, and returns its type.
+func assertChanOperand(x Expr, xt Type, op string, dir ChanDir) *ChanType {
+	if xt != nil && xt.Kind() == ChanKind {
+		if _, ok := baseOf(xt).(*NativeType); ok {
+			panic("native channels are not supported")
+		}
+	}
+	ct, ok := baseOf(xt).(*ChanType)
+	if !ok {
+		panic(fmt.Sprintf("invalid operation: cannot %s non-channel %s (variable of type %s)", op, x, xt))
+	}
+	if ct.Dir&dir == 0 {
+		only := "send-only"
+		if dir == SEND {
+			only = "receive-only"
+		}
+		panic(fmt.Sprintf("invalid operation: cannot %s %s channel %s (variable of type %s)", op, only, x, xt))
+	}
+	return ct
+}
+
 func (x *IncDecStmt) AssertCompatible(t Type) {
 	if nt, ok := t.(*NativeType); ok {
 		if _, ok := go2GnoBaseType(nt.Type).(PrimitiveType); ok {
@@ -822,6 +864,8 @@ func (x *RangeStmt) AssertCompatible(store Store, last BlockNode) {
 
 	xt := evalStaticTypeOf(store, last, x.X)
 	switch cxt := xt.(type) {
+	case *ChanType:
+		assertAssignableTo(x, cxt.Elt, kt, false)
 	case *MapType:
 		assertAssignableTo(x, cxt.Key, kt, false)
 		if vt != nil {
@@ -934,6 +978,26 @@ func (x *AssignStmt) AssertCompatible(store Store, last BlockNode) {
 					}
 				}
 				cx.HasOK = true
+			case *UnaryExpr: // must be a receive when len(Lhs) > len(Rhs)
+				if len(x.Lhs) != 2 || cx.Op != ARROW {
+					panic(fmt.Sprintf("RHS should not be %v when len(Lhs) > len(Rhs)", cx))
+				}
+				if x.Op == ASSIGN {
+					ct := assertChanOperand(cx.X, evalStaticTypeOf(store, last, cx.X), "receive from", RECV)
+					assertValidAssignLhs(store, last, x.Lhs[0])
+					if !isBlankIdentifier(x.Lhs[0]) {
+						lt := evalStaticTypeOf(store, last, x.Lhs[0])
+						assertAssignableTo(x, ct.Elt, lt, false)
+					}
+					assertValidAssignLhs(store, last, x.Lhs[1])
+					if !isBlankIdentifier(x.Lhs[1]) {
+						dt := evalStaticTypeOf(store, last, x.Lhs[1])
+						if dt != nil && dt.Kind() != BoolKind { // typed, not bool
+							panic(fmt.Sprintf("want bool type got %v", dt))
+						}
+					}
+				}
+				cx.HasOK = true
 			default:
 				panic(fmt.Sprintf("RHS should not be %v when len(Lhs) > len(Rhs)", cx))
 			}
@@ -1131,3 +1195,24 @@ func isBlankIdentifier(x Expr) bool {
 	}
 	return false
 }
+
+// isRecvExpr returns whether x is a receive expression, like `<-ch`.
+func isRecvExpr(x Expr) bool {
+	ux, ok := x.(*UnaryExpr)
+	return ok && ux.Op == ARROW
+}
+
+// assertGoCall asserts that the call of a go statement is a function call,
+// whose results can be discarded.
+func assertGoCall(store Store, last BlockNode, cx *CallExpr) {
+	ft := evalStaticTypeOf(store, last, cx.Func)
+	if _, ok := ft.(*TypeType); ok {
+		panic("expression in go must be function call")
+	}
+	if nx, ok := cx.Func.(*NameExpr); ok && nx.Path.Type == VPUverse {
+		switch nx.Name {
+		case "append", "cap", "complex", "imag", "len", "make", "new", "real":
+			panic(fmt.Sprintf("go discards result of %s", cx))
+		}
+	}
+}
@@ -0,0 +1,386 @@
+package gnolang
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gnolang/gno/gnovm"
+)
+
+// migrateFuncName is the function of a realm called after an upgrade.
+const migrateFuncName Name = "migrate"
+
+// UpgradeMemPackage replaces the code of the existing realm package at
+// memPkg.Path, keeping its state: the package block and the objects of the
+// realm are kept, and the package-level variables keep their values.
+//
+// The new code must be compatible with the persisted state:
+//   - every package-level variable must still be declared, with the same
+//     type, which must not contain function types, as the code of the old
+//     functions is removed; for the same reason, the values of interface
+//     types the variable holds must not be functions of the package, and
+//     no function of the realm must be held by other realms, see
+//     Realm.EscapedFuncs;
+//   - every declared type must still be declared, with the same underlying
+//     type, and its methods must be kept with the same signatures.
+//
+// The variables which were already declared keep their values, and their
+// initializers are not run, so that they have no side effects such as calls
+// to other realms; they must be declared apart from the new variables. The
+// initializers of the new variables are run. init functions
+// are not run; instead, the migrate function of the new code is called, if
+// declared, to update the state for the new code. It must take no arguments
+// and return no values, and is not exported so that it can only be called
+// by the upgrade.
+func (m *Machine) UpgradeMemPackage(memPkg *gnovm.MemPackage) (*PackageNode, *PackageValue) {
+	store := m.Store
+	pv := store.GetPackage(memPkg.Path, false)
+	if pv == nil {
+		panic(fmt.Sprintf("package %q does not exist", memPkg.Path))
+	}
+	if !pv.IsRealm() {
+		panic(fmt.Sprintf("package %q is not a realm", memPkg.Path))
+	}
+	if Name(memPkg.Name) != pv.PkgName {
+		panic(fmt.Sprintf("package name %q does not match %q",
+			memPkg.Name, pv.PkgName))
+	}
+	files := ParseMemPackage(memPkg)
+	if err := checkDuplicates(files); err != nil {
+		panic(fmt.Errorf("running package %q: %w", memPkg.Path, err))
+	}
+	m.SetActivePackage(pv)
+	rlm := pv.GetRealm()
+	if rlm.EscapedFuncs {
+		panic(fmt.Sprintf("incompatible upgrade of package %q: functions of the realm are held by other realms", pv.PkgPath))
+	}
+	pb := pv.GetBlock(store)
+	oldPn := pb.GetSource(store).(*PackageNode)
+	oldVars := packageVarNames(oldPn.FileSet)
+	newVars := packageVarNames(files)
+
+	// Get the objects referenced by the old package block and file
+	// blocks, except for the variables which are kept. The values of the
+	// kept variables are checked while the old types are still stored.
+	oldValues := pb.Values
+	kept := make(map[Name]TypedValue)
+	var xos []Object
+	var errs []string
+	for i, n := range oldPn.GetBlockNames() {
+		if _, ok := oldVars[n]; ok {
+			if _, ok := newVars[n]; ok {
+				if holdsPackageFuncs(store, pv.PkgPath, oldPn.GetStaticTypeOf(store, n), oldValues[i]) {
+					errs = append(errs, fmt.Sprintf("var %s holds functions, which cannot be kept", n))
+				}
+				kept[n] = oldValues[i]
+				continue
+			}
+		}
+		if xo := oldValues[i].GetFirstObject(store); xo != nil {
+			xos = append(xos, xo)
+		}
+	}
+	if len(errs) > 0 {
+		panic(fmt.Sprintf("incompatible upgrade of package %q: %s", pv.PkgPath, strings.Join(errs, "; ")))
+	}
+	oldFBlocks := make([]*Block, len(pv.FNames))
+	for i, fname := range pv.FNames {
+		oldFBlocks[i] = pv.GetFileBlock(store, fname)
+	}
+
+	// Remove the old nodes and types, as the preprocessor would reuse the
+	// types, and run the new files on the package block, keeping the
+	// values of the kept variables.
+	for _, fn := range oldPn.FileSet.Files {
+		delBlockNodes(store, fn)
+	}
+	for _, dt := range packageDeclaredTypes(oldPn) {
+		store.DelType(dt.TypeID())
+	}
+	pn := NewPackageNode(pv.PkgName, pv.PkgPath, &FileSet{})
+	store.SetBlockNode(pn)
+	pb.Source = pn
+	pb.Values = nil
+	pv.FNames = nil
+	pv.FBlocks = nil
+	pv.fBlocksMap = make(map[Name]*Block)
+	m.runFileDeclsKeeping(kept, files.Files...)
+
+	if err := checkUpgradeCompatibility(store, oldPn, pn); err != nil {
+		panic(fmt.Sprintf("incompatible upgrade of package %q: %v", pv.PkgPath, err))
+	}
+	if _, ok := pn.GetLocalIndex(migrateFuncName); ok {
+		ft, ok := pn.GetStaticTypeOf(store, migrateFuncName).(*FuncType)
+		if !ok || len(ft.Params) != 0 || len(ft.Results) != 0 {
+			panic(fmt.Sprintf("%s must be a function without parameters and results", migrateFuncName))
+		}
+	}
+	for _, dt := range packageDeclaredTypes(pn) {
+		store.SetType(dt)
+	}
+
+	// Update the references of the package block and package value; the
+	// new variables were set by runFileDeclsKeeping, other values were
+	// not, and the kept variables still reference their objects.
+	rlm.MarkDirty(pb)
+	for i, n := range pn.GetBlockNames() {
+		if _, ok := newVars[n]; !ok {
+			rlm.DidUpdate(pb, nil, pb.Values[i].GetFirstObject(store))
+		}
+	}
+	for _, xo := range xos {
+		rlm.DidUpdate(pb, xo, nil)
+	}
+	for _, fb := range oldFBlocks {
+		rlm.DidUpdate(pv, fb, nil)
+	}
+	for _, fname := range pv.FNames {
+		rlm.DidUpdate(pv, nil, pv.GetFileBlock(store, fname))
+	}
+	m.runMigrate(pn, pv)
+	rlm.FinalizeRealmTransaction(m.ReadOnly, store)
+	store.SetPackageRealm(rlm)
+
+	store.AddMemPackage(memPkg)
+
+	return pn, pv
+}
+
+// runMigrate calls the migrate function of pv, if declared, like an init
+// function.
+func (m *Machine) runMigrate(pn *PackageNode, pv *PackageValue) {
+	idx, ok := pn.GetLocalIndex(migrateFuncName)
+	if !ok {
+		return
+	}
+	fv := pv.GetBlock(m.Store).Values[idx].V.(*FuncValue)
+	fb := pv.GetFileBlock(m.Store, fv.FileName)
+	m.PushBlock(fb)
+	m.RunStatement(S(Call(Nx(migrateFuncName))))
+	m.PopBlock()
+}
+
+// packageVarNames returns the names of the package-level variables declared
+// in fset.
+func packageVarNames(fset *FileSet) map[Name]struct{} {
+	names := make(map[Name]struct{})
+	for _, fn := range fset.Files {
+		for _, decl := range fn.Decls {
+			if vd, ok := decl.(*ValueDecl); ok && !vd.Const {
+				for _, nx := range vd.NameExprs {
+					if nx.Name != blankIdentifier {
+						names[nx.Name] = struct{}{}
+					}
+				}
+			}
+		}
+	}
+	return names
+}
+
+// packageDeclaredTypes returns the types declared at the top level of pn, in
+// the order of the package block.
+func packageDeclaredTypes(pn *PackageNode) []*DeclaredType {
+	var dts []*DeclaredType
+	for _, tv := range pn.Values {
+		if tvv, ok := tv.V.(TypeValue); ok {
+			// NOTE: aliases to other types are skipped.
+			if dt, ok := tvv.Type.(*DeclaredType); ok && dt.PkgPath == pn.PkgPath {
+				dts = append(dts, dt)
+			}
+		}
+	}
+	return dts
+}
+
+// checkUpgradeCompatibility checks that the persisted state of a package
+// declared by oldPn can be used by the code of pn.
+func checkUpgradeCompatibility(store Store, oldPn, pn *PackageNode) error {
+	var errs []string
+	oldVars := packageVarNames(oldPn.FileSet)
+	newVars := packageVarNames(pn.FileSet)
+	for _, n := range oldPn.GetBlockNames() {
+		if _, ok := oldVars[n]; !ok {
+			continue
+		}
+		if _, ok := newVars[n]; !ok {
+			errs = append(errs, fmt.Sprintf("var %s was removed", n))
+			continue
+		}
+		oldT := oldPn.GetStaticTypeOf(store, n)
+		newT := pn.GetStaticTypeOf(store, n)
+		if oldT.TypeID() != newT.TypeID() {
+			errs = append(errs, fmt.Sprintf("var %s changed type from %s to %s",
+				n, oldT.String(), newT.String()))
+		} else if containsType(oldT, isFuncType, nil) {
+			errs = append(errs, fmt.Sprintf("var %s of type %s may hold functions, which cannot be kept",
+				n, oldT.String()))
+		}
+	}
+	newTypes := make(map[Name]*DeclaredType)
+	for _, dt := range packageDeclaredTypes(pn) {
+		newTypes[dt.Name] = dt
+	}
+	for _, oldDt := range packageDeclaredTypes(oldPn) {
+		n := oldDt.Name
+		dt, ok := newTypes[n]
+		if !ok {
+			errs = append(errs, fmt.Sprintf("type %s was removed", n))
+			continue
+		}
+		if oldDt.Base.TypeID() != dt.Base.TypeID() {
+			errs = append(errs, fmt.Sprintf("type %s changed underlying type from %s to %s",
+				n, oldDt.Base.String(), dt.Base.String()))
+		}
+	methods:
+		for _, oldMtv := range oldDt.Methods {
+			mn := oldMtv.V.(*FuncValue).Name
+			for _, mtv := range dt.Methods {
+				if mtv.V.(*FuncValue).Name != mn {
+					continue
+				}
+				if oldMtv.T.TypeID() != mtv.T.TypeID() {
+					errs = append(errs, fmt.Sprintf("method %s.%s changed type from %s to %s",
+						n, mn, oldMtv.T.String(), mtv.T.String()))
+				}
+				continue methods
+			}
+			errs = append(errs, fmt.Sprintf("method %s.%s was removed", n, mn))
+		}
+	}
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+func isFuncType(t Type) bool {
+	_, ok := t.(*FuncType)
+	return ok
+}
+
+func isInterfaceType(t Type) bool {
+	_, ok := t.(*InterfaceType)
+	return ok
+}
+
+// containsType returns true if t is, or contains, a type matching match; seen
+// holds the declared types already visited.
+func containsType(t Type, match func(Type) bool, seen map[*DeclaredType]struct{}) bool {
+	if match(t) {
+		return true
+	}
+	switch t := t.(type) {
+	case *PointerType:
+		return containsType(t.Elt, match, seen)
+	case *ArrayType:
+		return containsType(t.Elt, match, seen)
+	case *SliceType:
+		return containsType(t.Elt, match, seen)
+	case *ChanType:
+		return containsType(t.Elt, match, seen)
+	case *MapType:
+		return containsType(t.Key, match, seen) || containsType(t.Value, match, seen)
+	case *StructType:
+		for _, f := range t.Fields {
+			if containsType(f.Type, match, seen) {
+				return true
+			}
+		}
+		return false
+	case *DeclaredType:
+		if _, ok := seen[t]; ok {
+			return false
+		}
+		if seen == nil {
+			seen = make(map[*DeclaredType]struct{})
+		}
+		seen[t] = struct{}{}
+		return containsType(t.Base, match, seen)
+	default:
+		return false
+	}
+}
+
+// holdsPackageFuncs returns true if tv, a value of type t, holds a function
+// or a bound method declared by the package at pkgPath, directly or in the
+// values it references. Only the values of interface types can hold them,
+// the types containing function types being rejected.
+func holdsPackageFuncs(store Store, pkgPath string, t Type, tv TypedValue) bool {
+	if containsType(t, isFuncType, nil) || !containsType(t, isInterfaceType, nil) {
+		return false
+	}
+	seen := make(map[Object]struct{})
+	var holds func(tv TypedValue) bool
+	holds = func(tv TypedValue) bool {
+		if ref, ok := tv.V.(RefValue); ok && ref.PkgPath != "" {
+			// The packages are not walked.
+			return false
+		}
+		fillValueTV(store, &tv)
+		if oo, ok := tv.V.(Object); ok {
+			if _, ok := seen[oo]; ok {
+				return false
+			}
+			seen[oo] = struct{}{}
+		}
+		switch cv := tv.V.(type) {
+		case *FuncValue:
+			if cv.PkgPath == pkgPath {
+				return true
+			}
+			for _, ctv := range cv.Captures {
+				if holds(ctv) {
+					return true
+				}
+			}
+		case *BoundMethodValue:
+			return cv.Func.PkgPath == pkgPath || holds(cv.Receiver)
+		case PointerValue:
+			// Only the pointed value is walked, not its base, which may
+			// be the package block.
+			if cv.TV != nil {
+				return holds(*cv.TV)
+			}
+		case *ArrayValue:
+			for _, etv := range cv.List {
+				if holds(etv) {
+					return true
+				}
+			}
+		case *SliceValue:
+			return holds(TypedValue{V: cv.Base})
+		case *StructValue:
+			for _, ftv := range cv.Fields {
+				if holds(ftv) {
+					return true
+				}
+			}
+		case *MapValue:
+			for cur := cv.List.Head; cur != nil; cur = cur.Next {
+				if holds(cur.Key) || holds(cur.Value) {
+					return true
+				}
+			}
+		case *HeapItemValue:
+			return holds(cv.Value)
+		}
+		return false
+	}
+	return holds(tv)
+}
+
+// delBlockNodes removes the block nodes of fn from the store; see
+// SaveBlockNodes.
+func delBlockNodes(store Store, fn *FileNode) {
+	Transcribe(fn, func(ns []Node, ftype TransField, index int, n Node, stage TransStage) (Node, TransCtrl) {
+		if stage != TRANS_ENTER {
+			return n, TRANS_CONTINUE
+		}
+		if bn, ok := n.(BlockNode); ok {
+			store.DelBlockNode(bn.GetLocation())
+		}
+		return n, TRANS_CONTINUE
+	})
+}
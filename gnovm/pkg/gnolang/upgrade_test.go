@@ -0,0 +1,368 @@
+package gnolang
+
+import (
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/gnolang/gno/gnovm"
+	"github.com/gnolang/gno/tm2/pkg/db/memdb"
+	"github.com/gnolang/gno/tm2/pkg/store/dbadapter"
+	"github.com/gnolang/gno/tm2/pkg/store/iavl"
+	stypes "github.com/gnolang/gno/tm2/pkg/store/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const upgradeTestPath = "gno.land/r/test/upgrade"
+
+func upgradeTestPackage(body string) *gnovm.MemPackage {
+	return &gnovm.MemPackage{
+		Name: "upgrade",
+		Path: upgradeTestPath,
+		Files: []*gnovm.MemFile{
+			{Name: "upgrade.gno", Body: "package upgrade\n" + body},
+		},
+	}
+}
+
+// upgradeTestEval evaluates expr in a new machine, as a transaction would.
+func upgradeTestEval(store Store, expr string) string {
+	store.ClearObjectCache()
+	m := NewMachineWithOptions(MachineOptions{Store: store, Output: io.Discard})
+	defer m.Release()
+	mpn := NewPackageNode("main", "main", nil)
+	mpn.Define("pkg", TypedValue{T: &PackageType{}, V: store.GetPackage(upgradeTestPath, false)})
+	m.SetActivePackage(mpn.NewPackage())
+	res := m.Eval(MustParseExpr(expr))
+	if len(res) == 0 {
+		return ""
+	}
+	return res[0].String()
+}
+
+func upgradeTestUpgrade(store Store, body string) {
+	store.ClearObjectCache()
+	m := NewMachineWithOptions(MachineOptions{Store: store, Output: io.Discard})
+	defer m.Release()
+	m.UpgradeMemPackage(upgradeTestPackage(body))
+}
+
+func TestUpgradeMemPackage(t *testing.T) {
+	db := memdb.NewMemDB()
+	baseStore := dbadapter.StoreConstructor(db, stypes.StoreOptions{})
+	iavlStore := iavl.StoreConstructor(db, stypes.StoreOptions{})
+	store := NewStore(nil, baseStore, iavlStore)
+
+	m := NewMachineWithOptions(MachineOptions{Store: store, Output: io.Discard})
+	m.RunMemPackage(upgradeTestPackage(`
+type Item struct { Name string }
+
+func (i *Item) String() string { return "item " + i.Name }
+
+var (
+	count int
+	items []*Item
+)
+
+func Add(name string) {
+	count++
+	items = append(items, &Item{Name: name})
+}
+
+func Render() string {
+	s := ""
+	for _, item := range items {
+		s += item.String() + ","
+	}
+	return s
+}
+`), true)
+	m.Release()
+
+	upgradeTestEval(store, `pkg.Add("a")`)
+	upgradeTestEval(store, `pkg.Add("b")`)
+	assert.Equal(t, `("item a,item b," string)`, upgradeTestEval(store, `pkg.Render()`))
+
+	// Incompatible upgrades are rejected.
+	for _, tc := range []struct{ body, err string }{
+		{`type Item struct { Name string }; var items []*Item`, "var count was removed"},
+		{`type Item struct { Name string }; var count string; var items []*Item`, "var count changed type from int to string"},
+		{`type Item struct { Name []byte }; var count int; var items []*Item`, "type Item changed underlying type"},
+		{`type Item struct { Name string }; var count int; var items []*Item`, "method Item.String was removed"},
+		{`type Item struct { Name string }; func (i Item) String() string { return "" }; var count int; var items []*Item`, "method Item.String changed type"},
+		{`type Item struct { Name string }; func (i *Item) String() string { return "" }; var count int; var items []*Item; func migrate(n int) {}`, "migrate must be a function without parameters and results"},
+	} {
+		// The upgrade is done in a transaction store, which is
+		// discarded.
+		txStore := store.BeginTransaction(baseStore.CacheWrap(), iavlStore.CacheWrap(), nil)
+		r := func() (r any) {
+			defer func() { r = recover() }()
+			upgradeTestUpgrade(txStore, tc.body)
+			return
+		}()
+		require.NotNil(t, r, tc.body)
+		assert.Contains(t, fmt.Sprint(r), tc.err)
+	}
+	assert.Equal(t, `("item a,item b," string)`, upgradeTestEval(store, `pkg.Render()`))
+
+	upgradeTestUpgrade(store, `
+type Item struct { Name string }
+
+func (i *Item) String() string { return "ITEM " + i.Name }
+
+var (
+	count int
+	items []*Item
+	version = "v2"
+)
+
+func Add(name string) {
+	count += 10
+	items = append(items, &Item{Name: name})
+}
+
+func Count() int { return count }
+
+func migrate() {
+	version += "-migrated"
+}
+
+func Render() string {
+	s := version + ":"
+	for _, item := range items {
+		s += item.String() + ","
+	}
+	return s
+}
+`)
+	assert.Equal(t, `("v2-migrated:ITEM a,ITEM b," string)`, upgradeTestEval(store, `pkg.Render()`))
+	upgradeTestEval(store, `pkg.Add("c")`)
+	assert.Equal(t, `(12 int)`, upgradeTestEval(store, `pkg.Count()`))
+	assert.Equal(t, `("v2-migrated:ITEM a,ITEM b,ITEM c," string)`, upgradeTestEval(store, `pkg.Render()`))
+
+	// Restart, with the nodes of the new code.
+	store2 := NewStore(nil, baseStore, iavlStore)
+	m2 := NewMachineWithOptions(MachineOptions{Store: store2, Output: io.Discard})
+	m2.PreprocessAllFilesAndSaveBlockNodes()
+	m2.Release()
+	assert.Equal(t, `("v2-migrated:ITEM a,ITEM b,ITEM c," string)`, upgradeTestEval(store2, `pkg.Render()`))
+	assert.Equal(t, `(12 int)`, upgradeTestEval(store2, `pkg.Count()`))
+}
+
+func TestUpgradeMemPackage_funcVars(t *testing.T) {
+	db := memdb.NewMemDB()
+	baseStore := dbadapter.StoreConstructor(db, stypes.StoreOptions{})
+	iavlStore := iavl.StoreConstructor(db, stypes.StoreOptions{})
+	store := NewStore(nil, baseStore, iavlStore)
+
+	// The functions of the old code cannot be kept, even in the fields of
+	// the values of the variables.
+	for _, tc := range []struct{ body, err string }{
+		{`var handler = func() string { return "a" }`, "var handler of type func()( string) may hold functions"},
+		{`type Hooks struct { OnAdd func() }; var hooks map[string]*Hooks`, "var hooks of type map[string]*gno.land/r/test/upgrade.Hooks may hold functions"},
+		{`type Node struct { Next *Node; Fns []func() }; var nodes [2]Node`, "var nodes of type [2]gno.land/r/test/upgrade.Node may hold functions"},
+		// Values of interface types are checked when they are kept.
+		{`var handler interface{} = func() string { return "a" }`, "var handler holds functions"},
+		{`type Hooks struct { Fns []interface{} }; var hooks = map[string]*Hooks{"a": {Fns: []interface{}{1, func() {}}}}`, "var hooks holds functions"},
+		{`type T struct{}; func (T) F() {}; var t interface{} = &[]interface{}{T{}.F}`, "var t holds functions"},
+	} {
+		txStore := store.BeginTransaction(baseStore.CacheWrap(), iavlStore.CacheWrap(), nil)
+		m := NewMachineWithOptions(MachineOptions{Store: txStore, Output: io.Discard})
+		m.RunMemPackage(upgradeTestPackage(tc.body), true)
+		m.Release()
+
+		r := func() (r any) {
+			defer func() { r = recover() }()
+			upgradeTestUpgrade(txStore, tc.body)
+			return
+		}()
+		require.NotNil(t, r, tc.body)
+		assert.Contains(t, fmt.Sprint(r), tc.err)
+	}
+}
+
+func TestUpgradeMemPackage_interfaceVars(t *testing.T) {
+	db := memdb.NewMemDB()
+	baseStore := dbadapter.StoreConstructor(db, stypes.StoreOptions{})
+	iavlStore := iavl.StoreConstructor(db, stypes.StoreOptions{})
+	store := NewStore(nil, baseStore, iavlStore)
+
+	// Values of interface types are kept if they don't hold functions of
+	// the package.
+	body := `
+import "gno.land/p/test/lib"
+
+type Item struct{ V interface{} }
+
+var (
+	values = []interface{}{1, "a", &Item{V: 2}}
+	hello  interface{} = lib.Hello
+)
+
+func Render() string { return hello.(func(string) string)(values[1].(string)) }
+`
+	m := NewMachineWithOptions(MachineOptions{Store: store, Output: io.Discard})
+	m.RunMemPackage(&gnovm.MemPackage{
+		Name: "lib",
+		Path: "gno.land/p/test/lib",
+		Files: []*gnovm.MemFile{
+			{Name: "lib.gno", Body: "package lib\nfunc Hello(s string) string { return \"hello \" + s }\n"},
+		},
+	}, true)
+	m.RunMemPackage(upgradeTestPackage(body), true)
+	m.Release()
+
+	upgradeTestUpgrade(store, body)
+	assert.Equal(t, `("hello a" string)`, upgradeTestEval(store, `pkg.Render()`))
+}
+
+func TestUpgradeMemPackage_imports(t *testing.T) {
+	db := memdb.NewMemDB()
+	baseStore := dbadapter.StoreConstructor(db, stypes.StoreOptions{})
+	iavlStore := iavl.StoreConstructor(db, stypes.StoreOptions{})
+	store := NewStore(nil, baseStore, iavlStore)
+
+	m := NewMachineWithOptions(MachineOptions{Store: store, Output: io.Discard})
+	m.RunMemPackage(&gnovm.MemPackage{
+		Name: "lib",
+		Path: "gno.land/p/test/lib",
+		Files: []*gnovm.MemFile{
+			{Name: "lib.gno", Body: "package lib\nvar greeting = \"hello\"\nfunc Hello() string { return greeting }\n"},
+		},
+	}, true)
+	m.RunMemPackage(upgradeTestPackage(`
+import "gno.land/p/test/lib"
+
+func Render() string { return lib.Hello() }
+`), true)
+	m.Release()
+	assert.Equal(t, `("hello" string)`, upgradeTestEval(store, `pkg.Render()`))
+
+	// The old file block is deleted, without deleting
+	// the package it imports, which is not owned by it.
+	upgradeTestUpgrade(store, `
+import "gno.land/p/test/lib"
+
+func Render() string { return lib.Hello() + "!" }
+`)
+	assert.Equal(t, `("hello!" string)`, upgradeTestEval(store, `pkg.Render()`))
+
+	// Restart, loading the imported package from the store.
+	store2 := NewStore(nil, baseStore, iavlStore)
+	m2 := NewMachineWithOptions(MachineOptions{Store: store2, Output: io.Discard})
+	m2.PreprocessAllFilesAndSaveBlockNodes()
+	m2.Release()
+	assert.Equal(t, `("hello!" string)`, upgradeTestEval(store2, `pkg.Render()`))
+}
+
+func TestUpgradeMemPackage_escapedFuncs(t *testing.T) {
+	db := memdb.NewMemDB()
+	baseStore := dbadapter.StoreConstructor(db, stypes.StoreOptions{})
+	iavlStore := iavl.StoreConstructor(db, stypes.StoreOptions{})
+	store := NewStore(nil, baseStore, iavlStore)
+
+	body := `
+import "gno.land/r/test/registry"
+
+type T struct{ Name string }
+
+func (t *T) Hello() string { return "hello " + t.Name }
+
+func Hello() string { return "hello" }
+
+func Subscribe(kind string) {
+	switch kind {
+	case "func":
+		registry.Register(Hello)
+	case "closure":
+		name := "closure"
+		registry.Register(func() string { return "hello " + name })
+	case "method":
+		registry.Register((&T{Name: "method"}).Hello)
+	}
+}
+`
+	m := NewMachineWithOptions(MachineOptions{Store: store, Output: io.Discard})
+	m.RunMemPackage(&gnovm.MemPackage{
+		Name: "registry",
+		Path: "gno.land/r/test/registry",
+		Files: []*gnovm.MemFile{
+			{Name: "registry.gno", Body: `package registry
+var callbacks []func() string
+func Register(f func() string) { callbacks = append(callbacks, f) }
+`},
+		},
+	}, true)
+	m.RunMemPackage(upgradeTestPackage(body), true)
+	m.Release()
+
+	// The functions of the realm held by other realms would lose their
+	// code.
+	for _, kind := range []string{"func", "closure", "method"} {
+		txStore := store.BeginTransaction(baseStore.CacheWrap(), iavlStore.CacheWrap(), nil)
+		upgradeTestUpgrade(txStore, body)
+		upgradeTestEval(txStore, fmt.Sprintf(`pkg.Subscribe(%q)`, kind))
+
+		r := func() (r any) {
+			defer func() { r = recover() }()
+			upgradeTestUpgrade(txStore, body)
+			return
+		}()
+		require.NotNil(t, r, kind)
+		assert.Contains(t, fmt.Sprint(r), "functions of the realm are held by other realms")
+	}
+}
+
+func TestUpgradeMemPackage_keptVars(t *testing.T) {
+	db := memdb.NewMemDB()
+	baseStore := dbadapter.StoreConstructor(db, stypes.StoreOptions{})
+	iavlStore := iavl.StoreConstructor(db, stypes.StoreOptions{})
+	store := NewStore(nil, baseStore, iavlStore)
+
+	m := NewMachineWithOptions(MachineOptions{Store: store, Output: io.Discard})
+	m.RunMemPackage(&gnovm.MemPackage{
+		Name: "counter",
+		Path: "gno.land/r/test/counter",
+		Files: []*gnovm.MemFile{
+			{Name: "counter.gno", Body: `package counter
+var count int
+func Inc() int { count++; return count }
+func Count() int { return count }
+`},
+		},
+	}, true)
+	m.RunMemPackage(upgradeTestPackage(`
+import "gno.land/r/test/counter"
+
+var n = counter.Inc()
+
+func Render() int { return counter.Count()*10 + n }
+`), true)
+	m.Release()
+	assert.Equal(t, `(11 int)`, upgradeTestEval(store, `pkg.Render()`))
+
+	// A kept variable and a new one cannot share a declaration.
+	txStore := store.BeginTransaction(baseStore.CacheWrap(), iavlStore.CacheWrap(), nil)
+	r := func() (r any) {
+		defer func() { r = recover() }()
+		upgradeTestUpgrade(txStore, `import "gno.land/r/test/counter"; func pair() (int, int) { return counter.Inc(), 1 }; var n, x = pair()`)
+		return
+	}()
+	require.NotNil(t, r)
+	assert.Contains(t, fmt.Sprint(r), "var x must be declared apart from the kept var n")
+
+	// The initializer of the kept variable is not run again, and the new
+	// variables are initialized with its value.
+	upgradeTestUpgrade(store, `
+import "gno.land/r/test/counter"
+
+var (
+	n = counter.Inc()
+	m = n * 100
+)
+
+func Render() int { return counter.Count()*10 + n + m }
+`)
+	assert.Equal(t, `(111 int)`, upgradeTestEval(store, `pkg.Render()`))
+}
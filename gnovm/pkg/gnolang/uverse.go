@@ -642,7 +642,23 @@ func makeUverseNode() {
 			return
 		},
 	)
-	def("close", undefined)
+	defNative("close",
+		Flds( // params
+			"c", AnyT(),
+		),
+		nil, // results
+		func(m *Machine) {
+			arg0 := m.LastBlock().GetParams1()
+			cv, ok := arg0.TV.V.(*ChanValue)
+			if !ok {
+				if arg0.TV.T != nil && arg0.TV.T.Kind() == ChanKind {
+					panic(&Exception{Value: typedString("close of nil channel")})
+				}
+				panic(fmt.Sprintf("cannot close non-channel %s", arg0.TV.T))
+			}
+			cv.close()
+		},
+	)
 	def("complex", undefined)
 	defNative("copy",
 		Flds( // params
@@ -906,10 +922,24 @@ func makeUverseNode() {
 					panic("make() of map type takes 1 or 2 arguments")
 				}
 			case *ChanType:
+				// NOTE: the type is not used.
 				if vargsl == 0 {
-					panic("not yet implemented")
+					m.PushValue(TypedValue{
+						T: tt,
+						V: m.Alloc.NewChan(0),
+					})
+					return
 				} else if vargsl == 1 {
-					panic("not yet implemented")
+					sv := vargs.TV.GetPointerAtIndexInt(m.Store, 0).Deref()
+					si := sv.ConvertGetInt()
+					if si < 0 {
+						panic(&Exception{Value: typedString(`makechan: size out of range`)})
+					}
+					m.PushValue(TypedValue{
+						T: tt,
+						V: m.Alloc.NewChan(int(si)),
+					})
+					return
 				} else {
 					panic("make() of chan type takes 1 or 2 arguments")
 				}
@@ -35,6 +35,7 @@ func (*SliceValue) assertValue()       {}
 func (*StructValue) assertValue()      {}
 func (*FuncValue) assertValue()        {}
 func (*MapValue) assertValue()         {}
+func (*ChanValue) assertValue()        {}
 func (*BoundMethodValue) assertValue() {}
 func (TypeValue) assertValue()         {}
 func (*PackageValue) assertValue()     {}
@@ -59,6 +60,7 @@ var (
 	_ Value = &StructValue{}
 	_ Value = &FuncValue{}
 	_ Value = &MapValue{}
+	_ Value = &ChanValue{}
 	_ Value = &BoundMethodValue{}
 	_ Value = TypeValue{}
 	_ Value = &PackageValue{}
@@ -815,6 +817,30 @@ func (mv *MapValue) DeleteForKey(store Store, key *TypedValue) {
 	}
 }
 
+// ----------------------------------------
+// ChanValue
+
+// ChanValue is the value of a channel. Channels only exist while running code
+// locally: they are not objects, and cannot be persisted in a realm.
+// The goroutines blocked on the channel are woken up in FIFO order; see the
+// scheduler of the [Machine].
+type ChanValue struct {
+	Buffer []TypedValue // buffered values, oldest first
+	Cap    int
+	Closed bool
+
+	recvq []*chanWaiter // goroutines blocked receiving
+	sendq []*chanWaiter // goroutines blocked sending
+}
+
+func (cv *ChanValue) GetLength() int {
+	return len(cv.Buffer)
+}
+
+func (cv *ChanValue) GetCapacity() int {
+	return cv.Cap
+}
+
 // ----------------------------------------
 // TypeValue
 
@@ -2093,7 +2119,7 @@ func (tv *TypedValue) GetLength() int {
 			return 0
 		case *ArrayType:
 			return bt.Len
-		case *SliceType:
+		case *SliceType, *ChanType:
 			return 0
 		case *PointerType:
 			if at, ok := bt.Elt.(*ArrayType); ok {
@@ -2115,6 +2141,8 @@ func (tv *TypedValue) GetLength() int {
 		return cv.GetLength()
 	case *MapValue:
 		return cv.GetLength()
+	case *ChanValue:
+		return cv.GetLength()
 	case *NativeValue:
 		return cv.Value.Len()
 	case PointerValue:
@@ -2135,7 +2163,7 @@ func (tv *TypedValue) GetCapacity() int {
 		// strings have no capacity.
 		case *ArrayType:
 			return bt.Len
-		case *SliceType:
+		case *SliceType, *ChanType:
 			return 0
 		case *PointerType:
 			if at, ok := bt.Elt.(*ArrayType); ok {
@@ -2151,6 +2179,8 @@ func (tv *TypedValue) GetCapacity() int {
 		return cv.GetCapacity()
 	case *SliceValue:
 		return cv.GetCapacity()
+	case *ChanValue:
+		return cv.GetCapacity()
 	case *NativeValue:
 		return cv.Value.Cap()
 	case PointerValue:
@@ -0,0 +1,395 @@
+package gnolang
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math"
+	"strconv"
+)
+
+const (
+	// maxJSONDepth is the maximum nesting of the values encoded.
+	maxJSONDepth = 64
+	// maxJSONSize is the maximum size of the encoding of a value.
+	maxJSONSize = 1 << 20
+
+	GasEncodeJSONPerByte = 1
+	GasEncodeJSONDesc    = "EncodeJSONPerByte"
+)
+
+var errJSONTooLarge = errors.New("JSON encoding is too large")
+
+// JSONValue is the JSON encoding of a TypedValue, with T the type of the
+// value and V the value.
+//
+// Values are encoded as follows:
+//   - booleans, integers and floats as JSON booleans and numbers; NaN and
+//     infinite floats as the strings "NaN", "+Inf" and "-Inf";
+//   - strings, and declared types of strings like std.Address, as strings;
+//     untyped big numbers as strings;
+//   - byte arrays and slices as base64 strings;
+//   - arrays and slices as arrays, and structs as objects keyed by field name;
+//   - maps with string keys as objects, other maps as arrays of
+//     {"key":...,"value":...} objects, in insertion order;
+//   - pointers to persisted objects as {"$ref":"<object id>"}, other pointers
+//     as the value they point to;
+//   - values stored with an interface type, like in a field of type any,
+//     as {"T":...,"V":...} objects;
+//   - types as their name;
+//   - nil values as null, and other values (functions, packages...) as their
+//     String() representation.
+//
+// Values containing themselves are encoded as null where they recur, like
+// values nested deeper than maxJSONDepth. The encoding is charged to the gas
+// meter of the store, and panics if it is larger than maxJSONSize.
+type JSONValue struct {
+	T string          `json:"T"`
+	V json.RawMessage `json:"V"`
+}
+
+// Decode unmarshals the value into ptr.
+func (jv JSONValue) Decode(ptr any) error {
+	return json.Unmarshal(jv.V, ptr)
+}
+
+// JSONValue returns the JSON encoding of tv. Objects referenced by tv are
+// loaded from store.
+func (tv *TypedValue) JSONValue(store Store) JSONValue {
+	enc := newJSONEncoder(store, false)
+	return JSONValue{
+		T: jsonTypeString(tv.T),
+		V: enc.encode(*tv),
+	}
+}
+
+// JSONValues returns the JSON encoding of tvs, like the results of a call.
+func JSONValues(store Store, tvs []TypedValue) []JSONValue {
+	jvs := make([]JSONValue, len(tvs))
+	for i := range tvs {
+		jvs[i] = tvs[i].JSONValue(store)
+	}
+	return jvs
+}
+
+func jsonTypeString(t Type) string {
+	if t == nil {
+		return "nil"
+	}
+	return t.String()
+}
+
+type jsonEncoder struct {
+	store Store
+	buf   bytes.Buffer
+	seen  *seenValues
+	depth int
+	// shallow writes the persisted objects contained by a value as
+	// references, instead of only those pointed to.
+	shallow bool
+	refs    []ObjectID
+	// consumeGas charges the gas of the encoding, if the store has a
+	// gas meter; charged is the size of buf already charged.
+	consumeGas func(gas int64, descriptor string)
+	charged    int
+}
+
+func newJSONEncoder(store Store, shallow bool) *jsonEncoder {
+	enc := &jsonEncoder{store: store, seen: newSeenValues(), shallow: shallow}
+	if ds, ok := store.(interface{ consumeGas(int64, string) }); ok {
+		enc.consumeGas = ds.consumeGas
+	}
+	return enc
+}
+
+// encode returns the JSON encoding of tv.
+func (enc *jsonEncoder) encode(tv TypedValue) json.RawMessage {
+	enc.buf.Reset()
+	enc.charged = 0
+	enc.writeValue(tv)
+	enc.charge()
+	return json.RawMessage(bytes.Clone(enc.buf.Bytes()))
+}
+
+// charge charges the gas of the bytes written since the last charge, and
+// panics if the encoding is too large.
+func (enc *jsonEncoder) charge() {
+	n := enc.buf.Len()
+	if n > maxJSONSize {
+		panic(errJSONTooLarge)
+	}
+	if enc.consumeGas != nil && n > enc.charged {
+		enc.consumeGas(int64(n-enc.charged)*GasEncodeJSONPerByte, GasEncodeJSONDesc)
+	}
+	enc.charged = n
+}
+
+// enter marks the composite value v as being written, and returns false if
+// it already is, for values containing themselves, or if the values written
+// are nested too deep. Each successful enter must be followed by a leave.
+func (enc *jsonEncoder) enter(v Value) bool {
+	if enc.depth >= maxJSONDepth || enc.seen.Contains(v) {
+		return false
+	}
+	enc.depth++
+	enc.seen.Put(v)
+	return true
+}
+
+func (enc *jsonEncoder) leave() {
+	enc.depth--
+	enc.seen.Pop()
+}
+
+// writeRef writes a reference to the object oid, as {"$ref":"<oid>"}.
+func (enc *jsonEncoder) writeRef(oid ObjectID) {
+	enc.buf.WriteString(`{"$ref":`)
+	enc.writeJSON(oid.String())
+	enc.buf.WriteString("}")
+	enc.refs = append(enc.refs, oid)
+}
+
+// valueObjectID returns the id of the object v, or of the object v refers
+// to, or a zero id if v is not a persisted object.
+func valueObjectID(v Value) ObjectID {
+	switch cv := v.(type) {
+	case RefValue:
+		return cv.ObjectID
+	case Object:
+		return cv.GetObjectID()
+	default:
+		return ObjectID{}
+	}
+}
+
+func (enc *jsonEncoder) writeJSON(v any) {
+	bz, err := json.Marshal(v)
+	if err != nil {
+		panic(err)
+	}
+	enc.buf.Write(bz)
+}
+
+// writeElem writes a value stored with the static type st; values stored
+// with an interface type are written with their type.
+func (enc *jsonEncoder) writeElem(tv TypedValue, st Type) {
+	if st == nil || st.Kind() != InterfaceKind {
+		enc.writeValue(tv)
+		return
+	}
+	if tv.T == nil {
+		enc.buf.WriteString("null")
+		return
+	}
+	enc.buf.WriteString(`{"T":`)
+	enc.writeJSON(jsonTypeString(tv.T))
+	enc.buf.WriteString(`,"V":`)
+	enc.writeValue(tv)
+	enc.buf.WriteString("}")
+}
+
+func (enc *jsonEncoder) writeFloat(f float64) {
+	switch {
+	case math.IsNaN(f):
+		enc.buf.WriteString(`"NaN"`)
+	case math.IsInf(f, 1):
+		enc.buf.WriteString(`"+Inf"`)
+	case math.IsInf(f, -1):
+		enc.buf.WriteString(`"-Inf"`)
+	default:
+		enc.writeJSON(f)
+	}
+}
+
+func (enc *jsonEncoder) writeValue(tv TypedValue) {
+	enc.charge()
+	if tv.T == nil {
+		enc.buf.WriteString("null")
+		return
+	}
+	if enc.shallow {
+		if oid := valueObjectID(tv.V); !oid.IsZero() {
+			enc.writeRef(oid)
+			return
+		}
+	}
+	fillValueTV(enc.store, &tv)
+	switch tv.T.Kind() {
+	case BoolKind:
+		enc.buf.WriteString(strconv.FormatBool(tv.GetBool()))
+	case StringKind:
+		enc.writeJSON(tv.GetString())
+	case IntKind:
+		enc.buf.WriteString(strconv.FormatInt(int64(tv.GetInt()), 10))
+	case Int8Kind:
+		enc.buf.WriteString(strconv.FormatInt(int64(tv.GetInt8()), 10))
+	case Int16Kind:
+		enc.buf.WriteString(strconv.FormatInt(int64(tv.GetInt16()), 10))
+	case Int32Kind:
+		enc.buf.WriteString(strconv.FormatInt(int64(tv.GetInt32()), 10))
+	case Int64Kind:
+		enc.buf.WriteString(strconv.FormatInt(tv.GetInt64(), 10))
+	case UintKind:
+		enc.buf.WriteString(strconv.FormatUint(uint64(tv.GetUint()), 10))
+	case Uint8Kind:
+		enc.buf.WriteString(strconv.FormatUint(uint64(tv.GetUint8()), 10))
+	case Uint16Kind:
+		enc.buf.WriteString(strconv.FormatUint(uint64(tv.GetUint16()), 10))
+	case Uint32Kind:
+		enc.buf.WriteString(strconv.FormatUint(uint64(tv.GetUint32()), 10))
+	case Uint64Kind:
+		enc.buf.WriteString(strconv.FormatUint(tv.GetUint64(), 10))
+	case Float32Kind:
+		enc.writeFloat(float64(tv.GetFloat32()))
+	case Float64Kind:
+		enc.writeFloat(tv.GetFloat64())
+	case BigintKind:
+		enc.writeJSON(tv.GetBigInt().String())
+	case BigdecKind:
+		enc.writeJSON(tv.GetBigDec().String())
+	case ArrayKind:
+		at := baseOf(tv.T).(*ArrayType)
+		enc.writeArray(tv.V.(*ArrayValue), 0, at.Len, at.Elt)
+	case SliceKind:
+		sv, ok := tv.V.(*SliceValue)
+		if !ok || sv == nil || sv.Base == nil {
+			enc.buf.WriteString("null")
+			return
+		}
+		if oid := valueObjectID(sv.Base); enc.shallow && !oid.IsZero() {
+			enc.buf.WriteString(`{"$ref":`)
+			enc.writeJSON(oid.String())
+			enc.buf.WriteString(`,"offset":`)
+			enc.buf.WriteString(strconv.Itoa(sv.Offset))
+			enc.buf.WriteString(`,"length":`)
+			enc.buf.WriteString(strconv.Itoa(sv.Length))
+			enc.buf.WriteString("}")
+			enc.refs = append(enc.refs, oid)
+			return
+		}
+		st := baseOf(tv.T).(*SliceType)
+		enc.writeArray(sv.GetBase(enc.store), sv.Offset, sv.Length, st.Elt)
+	case StructKind:
+		enc.writeStruct(tv.V.(*StructValue), baseOf(tv.T).(*StructType))
+	case MapKind:
+		mv, ok := tv.V.(*MapValue)
+		if !ok || mv == nil {
+			enc.buf.WriteString("null")
+			return
+		}
+		enc.writeMap(mv, baseOf(tv.T).(*MapType))
+	case TypeKind:
+		enc.writeJSON(tv.GetType().String())
+	case PointerKind:
+		pv, ok := tv.V.(PointerValue)
+		if !ok {
+			enc.buf.WriteString("null")
+			return
+		}
+		enc.writePointer(pv, baseOf(tv.T).(*PointerType))
+	default:
+		if tv.V == nil {
+			enc.buf.WriteString("null")
+			return
+		}
+		enc.writeJSON(tv.String())
+	}
+}
+
+func (enc *jsonEncoder) writeArray(av *ArrayValue, offset, length int, et Type) {
+	if av.Data != nil {
+		enc.writeJSON(base64.StdEncoding.EncodeToString(av.Data[offset : offset+length]))
+		return
+	}
+	if !enc.enter(av) {
+		enc.buf.WriteString("null")
+		return
+	}
+	defer enc.leave()
+	enc.buf.WriteString("[")
+	for i, etv := range av.List[offset : offset+length] {
+		if i > 0 {
+			enc.buf.WriteString(",")
+		}
+		enc.writeElem(etv, et)
+	}
+	enc.buf.WriteString("]")
+}
+
+func (enc *jsonEncoder) writeStruct(sv *StructValue, st *StructType) {
+	if !enc.enter(sv) {
+		enc.buf.WriteString("null")
+		return
+	}
+	defer enc.leave()
+	enc.buf.WriteString("{")
+	for i, ftv := range sv.Fields {
+		if i > 0 {
+			enc.buf.WriteString(",")
+		}
+		ft := st.Fields[i]
+		enc.writeJSON(string(ft.Name))
+		enc.buf.WriteString(":")
+		enc.writeElem(ftv, ft.Type)
+	}
+	enc.buf.WriteString("}")
+}
+
+func (enc *jsonEncoder) writeMap(mv *MapValue, mt *MapType) {
+	if !enc.enter(mv) {
+		enc.buf.WriteString("null")
+		return
+	}
+	defer enc.leave()
+	if mt.Key.Kind() == StringKind {
+		enc.buf.WriteString("{")
+		for item, i := mv.List.Head, 0; item != nil; item, i = item.Next, i+1 {
+			if i > 0 {
+				enc.buf.WriteString(",")
+			}
+			enc.writeJSON(item.Key.GetString())
+			enc.buf.WriteString(":")
+			enc.writeElem(item.Value, mt.Value)
+		}
+		enc.buf.WriteString("}")
+		return
+	}
+	enc.buf.WriteString("[")
+	for item, i := mv.List.Head, 0; item != nil; item, i = item.Next, i+1 {
+		if i > 0 {
+			enc.buf.WriteString(",")
+		}
+		enc.buf.WriteString(`{"key":`)
+		enc.writeElem(item.Key, mt.Key)
+		enc.buf.WriteString(`,"value":`)
+		enc.writeElem(item.Value, mt.Value)
+		enc.buf.WriteString("}")
+	}
+	enc.buf.WriteString("]")
+}
+
+func (enc *jsonEncoder) writePointer(pv PointerValue, pt *PointerType) {
+	if pv.TV == nil {
+		enc.buf.WriteString("null")
+		return
+	}
+	// refer to the object pointed to, or to the
+	// base object of the pointer, if persisted.
+	oid := valueObjectID(pv.TV.V)
+	if oid.IsZero() {
+		oid = valueObjectID(pv.Base)
+	}
+	if !oid.IsZero() {
+		enc.writeRef(oid)
+		return
+	}
+	// not persisted, write the value pointed to,
+	// unless the pointer is recursive.
+	if !enc.enter(pv.Base) {
+		enc.buf.WriteString("null")
+		return
+	}
+	defer enc.leave()
+	enc.writeElem(pv.Deref(), pt.Elt)
+}
@@ -0,0 +1,145 @@
+package gnolang
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/gnolang/gno/gnovm"
+	"github.com/gnolang/gno/tm2/pkg/db/memdb"
+	"github.com/gnolang/gno/tm2/pkg/store/dbadapter"
+	"github.com/gnolang/gno/tm2/pkg/store/iavl"
+	stypes "github.com/gnolang/gno/tm2/pkg/store/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONValue(t *testing.T) {
+	db := memdb.NewMemDB()
+	baseStore := dbadapter.StoreConstructor(db, stypes.StoreOptions{})
+	iavlStore := iavl.StoreConstructor(db, stypes.StoreOptions{})
+	store := NewStore(nil, baseStore, iavlStore)
+
+	const pkgPath = "gno.land/r/test/json"
+	m := NewMachineWithOptions(MachineOptions{Store: store, Output: io.Discard})
+	m.RunMemPackage(&gnovm.MemPackage{
+		Name: "json",
+		Path: pkgPath,
+		Files: []*gnovm.MemFile{
+			{Name: "json.gno", Body: `package json
+
+type Address string
+
+type Item struct {
+	Name  string
+	Owner Address
+	Count uint64
+	Tags  []string
+	Extra interface{}
+	Next  *Item
+}
+
+var persisted = &Item{Name: "persisted"}
+
+func Persisted() *Item { return persisted }
+
+func Values() (bool, int, int8, uint64, float64, string, Address, []byte) {
+	return true, -42, 8, 18446744073709551615, 1.5, "foo", Address("g1abc"), []byte("hi")
+}
+
+func Cyclic() (interface{}, map[string]interface{}) {
+	s := []interface{}{nil}
+	s[0] = s
+	m := map[string]interface{}{}
+	m["m"] = m
+	return s, m
+}
+
+func Deep() interface{} {
+	var v interface{}
+	for i := 0; i < 100; i++ {
+		v = []interface{}{v}
+	}
+	return v
+}
+
+func Compound() (Item, [2]int, map[string]int, map[int]string, *Item, []interface{}, error) {
+	item := Item{Name: "a", Owner: "g1abc", Count: 2, Tags: []string{"x", "y"}, Extra: 3}
+	item.Next = &Item{Name: "b"}
+	return item, [2]int{1, 2}, map[string]int{"z": 1, "a": 2}, map[int]string{1: "one"}, nil, []interface{}{"s", 1, nil}, nil
+}
+`},
+		},
+	}, true)
+	m.Release()
+
+	eval := func(expr string) []JSONValue {
+		store.ClearObjectCache()
+		m := NewMachineWithOptions(MachineOptions{Store: store, Output: io.Discard})
+		defer m.Release()
+		mpn := NewPackageNode("main", "main", nil)
+		mpn.Define("pkg", TypedValue{T: &PackageType{}, V: store.GetPackage(pkgPath, false)})
+		m.SetActivePackage(mpn.NewPackage())
+		return JSONValues(store, m.Eval(MustParseExpr(expr)))
+	}
+	str := func(jvs []JSONValue) string {
+		ss := make([]string, len(jvs))
+		for i, jv := range jvs {
+			ss[i] = jv.T + " " + string(jv.V)
+		}
+		return strings.Join(ss, "\n")
+	}
+
+	assert.Equal(t, strings.Join([]string{
+		`bool true`,
+		`int -42`,
+		`int8 8`,
+		`uint64 18446744073709551615`,
+		`float64 1.5`,
+		`string "foo"`,
+		`gno.land/r/test/json.Address "g1abc"`,
+		`[]uint8 "aGk="`,
+	}, "\n"), str(eval("pkg.Values()")))
+
+	assert.Equal(t, strings.Join([]string{
+		`gno.land/r/test/json.Item {"Name":"a","Owner":"g1abc","Count":2,"Tags":["x","y"],"Extra":{"T":"int","V":3},"Next":{"Name":"b","Owner":"","Count":0,"Tags":null,"Extra":null,"Next":null}}`,
+		`[2]int [1,2]`,
+		`map[string]int {"z":1,"a":2}`,
+		`map[int]string [{"key":1,"value":"one"}]`,
+		`*gno.land/r/test/json.Item null`,
+		`[]interface{} [{"T":"string","V":"s"},{"T":"int","V":1},null]`,
+		`nil null`,
+	}, "\n"), str(eval("pkg.Compound()")))
+
+	// Values containing themselves are written as null where they recur.
+	assert.Equal(t, strings.Join([]string{
+		`[]interface{} [{"T":"[]interface{}","V":null}]`,
+		`map[string]interface{} {"m":{"T":"map[string]interface{}","V":null}}`,
+	}, "\n"), str(eval("pkg.Cyclic()")))
+	deep := str(eval("pkg.Deep()"))
+	assert.Equal(t, maxJSONDepth-1, strings.Count(deep, `"V":[`))
+	assert.True(t, strings.HasSuffix(deep, `"V":null}]`+strings.Repeat("}]", maxJSONDepth-1)), deep)
+
+	// The encoding is charged to the gas meter of the store.
+	gasMeter := stypes.NewInfiniteGasMeter()
+	jv := eval("pkg.Values()")[5].V
+	tv := TypedValue{T: StringType}
+	tv.SetString("foo")
+	assert.Equal(t, `"foo"`, string(jv))
+	JSONValues(store.BeginTransaction(nil, nil, gasMeter), []TypedValue{tv})
+	assert.Equal(t, int64(len(jv))*GasEncodeJSONPerByte, gasMeter.GasConsumed())
+
+	jvs := eval("pkg.Persisted()")
+	require.Len(t, jvs, 1)
+	var ref struct {
+		Ref string `json:"$ref"`
+	}
+	require.NoError(t, jvs[0].Decode(&ref))
+	var oid ObjectID
+	require.NoError(t, oid.UnmarshalAmino(ref.Ref))
+	assert.Equal(t, PkgIDFromPkgPath(pkgPath), oid.PkgID)
+
+	var count uint64
+	require.NoError(t, eval("pkg.Values()")[3].Decode(&count))
+	assert.Equal(t, uint64(18446744073709551615), count)
+}
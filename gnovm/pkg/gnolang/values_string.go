@@ -222,6 +222,26 @@ func (mv *MapValue) ProtectedString(seen *seenValues) string {
 	return "map{" + strings.Join(ss, ",") + "}"
 }
 
+func (cv *ChanValue) String() string {
+	return cv.ProtectedString(newSeenValues())
+}
+
+// ProtectedString returns the buffered values of the channel.
+func (cv *ChanValue) ProtectedString(seen *seenValues) string {
+	if seen.Contains(cv) {
+		return fmt.Sprintf("%p", cv)
+	}
+
+	seen.Put(cv)
+	defer seen.Pop()
+
+	ss := make([]string, len(cv.Buffer))
+	for i, v := range cv.Buffer {
+		ss[i] = v.ProtectedString(seen)
+	}
+	return "chan{" + strings.Join(ss, ",") + "}"
+}
+
 func (v TypeValue) String() string {
 	ptr := ""
 	if reflect.TypeOf(v.Type).Kind() == reflect.Ptr {
@@ -377,8 +397,6 @@ func (tv *TypedValue) ProtectedSprint(seen *seenValues, considerDeclaredType boo
 		panic("should not happen")
 	case *PackageType:
 		return tv.V.(*PackageValue).String()
-	case *ChanType:
-		panic("not yet implemented")
 	case *TypeType:
 		return tv.V.(TypeValue).String()
 	default:
@@ -0,0 +1,173 @@
+package test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	gno "github.com/gnolang/gno/gnovm/pkg/gnolang"
+)
+
+// DefaultBenchTime is the default duration of each benchmark.
+const DefaultBenchTime = time.Second
+
+var errInvalidBenchTime = errors.New("invalid benchmark time")
+
+// BenchTime is the duration, or the fixed number of iterations, of each
+// benchmark.
+type BenchTime struct {
+	D time.Duration
+	N int // number of iterations, if non-zero
+}
+
+// ParseBenchTime parses a benchmark time, in the format of the -benchtime flag
+// of `go test`: a duration like "1s", or a number of iterations like "100x".
+func ParseBenchTime(s string) (BenchTime, error) {
+	if s == "" {
+		return BenchTime{D: DefaultBenchTime}, nil
+	}
+
+	if strings.HasSuffix(s, "x") {
+		n, err := strconv.Atoi(s[:len(s)-1])
+		if err != nil || n <= 0 {
+			return BenchTime{}, fmt.Errorf("%w, %q", errInvalidBenchTime, s)
+		}
+		return BenchTime{N: n}, nil
+	}
+
+	d, err := time.ParseDuration(s)
+	if err != nil || d <= 0 {
+		return BenchTime{}, fmt.Errorf("%w, %q", errInvalidBenchTime, s)
+	}
+	return BenchTime{D: d}, nil
+}
+
+// benchReport is a mirror of Gno's stdlibs/testing.BenchmarkReport.
+type benchReport struct {
+	Failed  bool
+	Skipped bool
+	Results []benchResult
+}
+
+// benchResult is a mirror of Gno's stdlibs/testing.BenchmarkResult.
+type benchResult struct {
+	Name      string
+	N         int
+	T         int64
+	Gas       int64
+	MemBytes  int64
+	MemAllocs int64
+	Bytes     int64
+	Extra     []struct {
+		Value float64
+		Unit  string
+	}
+}
+
+// String formats the result like the benchmark results of `go test`,
+// with the gas consumed per iteration.
+func (r benchResult) String(benchMem bool) string {
+	if r.N <= 0 {
+		return fmt.Sprintf("%s\t%8d", r.Name, r.N)
+	}
+
+	var buf strings.Builder
+	n := float64(r.N)
+	fmt.Fprintf(&buf, "%s\t%8d", r.Name, r.N)
+
+	buf.WriteByte('\t')
+	prettyPrint(&buf, float64(r.T)/n, "ns/op")
+	buf.WriteByte('\t')
+	prettyPrint(&buf, float64(r.Gas)/n, "gas/op")
+
+	if r.Bytes > 0 && r.T > 0 {
+		mbs := float64(r.Bytes) * n / 1e6 / (float64(r.T) / 1e9)
+		fmt.Fprintf(&buf, "\t%7.2f MB/s", mbs)
+	}
+	for _, m := range r.Extra {
+		buf.WriteByte('\t')
+		prettyPrint(&buf, m.Value, m.Unit)
+	}
+	if benchMem {
+		fmt.Fprintf(&buf, "\t%8d B/op\t%8d allocs/op",
+			r.MemBytes/int64(r.N), r.MemAllocs/int64(r.N))
+	}
+	return buf.String()
+}
+
+// prettyPrint writes x and its unit, with a precision depending on its
+// magnitude, like `go test`.
+func prettyPrint(buf *strings.Builder, x float64, unit string) {
+	var format string
+	switch y := math.Abs(x); {
+	case y == 0 || y >= 999.95:
+		format = "%10.0f %s"
+	case y >= 99.995:
+		format = "%12.1f %s"
+	case y >= 9.9995:
+		format = "%13.2f %s"
+	case y >= 0.99995:
+		format = "%14.3f %s"
+	case y >= 0.099995:
+		format = "%15.4f %s"
+	case y >= 0.0099995:
+		format = "%16.5f %s"
+	case y >= 0.00099995:
+		format = "%17.6f %s"
+	default:
+		format = "%18.7f %s"
+	}
+	fmt.Fprintf(buf, format, x, unit)
+}
+
+// runBenchmark runs the benchmark bf on m, and prints its results.
+func (opts *TestOptions) runBenchmark(m *gno.Machine, bf testFunc) error {
+	benchTime := opts.BenchTime
+	if benchTime.D == 0 && benchTime.N == 0 {
+		benchTime.D = DefaultBenchTime
+	}
+
+	testingpv := m.Store.GetPackage("testing", false)
+	testingtv := gno.TypedValue{T: &gno.PackageType{}, V: testingpv}
+	testingcx := &gno.ConstExpr{TypedValue: testingtv}
+
+	eval := m.Eval(gno.Call(
+		gno.Sel(testingcx, "RunBenchmark"),                 // Call testing.RunBenchmark
+		gno.Str(opts.Bench),                                // bench flag
+		gno.Num(strconv.FormatInt(int64(benchTime.D), 10)), // bench time
+		gno.Num(strconv.Itoa(benchTime.N)),                 // bench iterations
+		gno.Nx(strconv.FormatBool(opts.Verbose)),           // is verbose?
+		&gno.CompositeLitExpr{ // Fifth param, the testing.InternalBenchmark
+			Type: gno.Sel(testingcx, "InternalBenchmark"),
+			Elts: gno.KeyValueExprs{
+				{Key: gno.X("Name"), Value: gno.Str(bf.Name)},
+				{Key: gno.X("F"), Value: gno.Nx(bf.Name)},
+			},
+		},
+	))
+
+	ret := eval[0].GetString()
+	if ret == "" {
+		fmt.Fprintf(opts.Error, "--- FAIL: %s [internal gno testing error]", bf.Name)
+		return fmt.Errorf("failed to execute benchmark: %q", bf.Name)
+	}
+
+	var rep benchReport
+	if err := json.Unmarshal([]byte(ret), &rep); err != nil {
+		fmt.Fprintf(opts.Error, "--- FAIL: %s [internal gno testing error]", bf.Name)
+		return err
+	}
+
+	for _, res := range rep.Results {
+		fmt.Fprintln(opts.Error, res.String(opts.BenchMem))
+	}
+
+	if rep.Failed {
+		return fmt.Errorf("failed: %q", bf.Name)
+	}
+	return nil
+}
@@ -0,0 +1,284 @@
+package test
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/gnolang/gno/gnovm"
+	gno "github.com/gnolang/gno/gnovm/pkg/gnolang"
+)
+
+// CoverMode is the mode of the cover profiles written by
+// [PackageCoverage.WriteProfile]: blocks are only marked as executed or not.
+const CoverMode = "set"
+
+// CoverBlock is a basic block of statements of a source file, which are all
+// executed if the first one is. Blocks are split like the ones of
+// `go test -cover`, so that the cover profiles can be read by `go tool cover`.
+type CoverBlock struct {
+	StartLine, StartCol int
+	EndLine, EndCol     int
+	NumStmt             int
+	Executed            bool
+}
+
+// FileCoverage is the statement coverage of a source file of a package.
+type FileCoverage struct {
+	Name   string
+	Blocks []CoverBlock
+}
+
+// Statements returns the number of executed statements of the file,
+// and its total number of statements.
+func (fc *FileCoverage) Statements() (executed, total int) {
+	for _, b := range fc.Blocks {
+		if b.Executed {
+			executed += b.NumStmt
+		}
+		total += b.NumStmt
+	}
+	return
+}
+
+// PackageCoverage is the statement coverage of the source files of a package,
+// excluding its test files.
+type PackageCoverage struct {
+	Path  string
+	Files []*FileCoverage
+}
+
+// Statements returns the number of executed statements of the package,
+// and its total number of statements.
+func (pc *PackageCoverage) Statements() (executed, total int) {
+	for _, fc := range pc.Files {
+		e, t := fc.Statements()
+		executed += e
+		total += t
+	}
+	return
+}
+
+// WriteProfile writes the blocks of the package in the Go cover profile
+// format, without the leading "mode:" line. The file names are joined to dir,
+// which should be the absolute path of the package directory for
+// `go tool cover` to find the source files.
+func (pc *PackageCoverage) WriteProfile(w io.Writer, dir string) error {
+	for _, fc := range pc.Files {
+		name := filepath.Join(dir, fc.Name)
+		for _, b := range fc.Blocks {
+			count := 0
+			if b.Executed {
+				count = 1
+			}
+			_, err := fmt.Fprintf(w, "%s:%d.%d,%d.%d %d %d\n",
+				name, b.StartLine, b.StartCol, b.EndLine, b.EndCol, b.NumStmt, count)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Coverage computes the statement coverage of memPkg, from the statements
+// recorded in cov while running its tests.
+func Coverage(memPkg *gnovm.MemPackage, cov *gno.Coverage) (*PackageCoverage, error) {
+	pc := &PackageCoverage{Path: memPkg.Path}
+	for _, mfile := range memPkg.Files {
+		if !strings.HasSuffix(mfile.Name, ".gno") ||
+			strings.HasSuffix(mfile.Name, "_test.gno") ||
+			strings.HasSuffix(mfile.Name, "_filetest.gno") {
+			continue
+		}
+
+		fset := token.NewFileSet()
+		f, err := parser.ParseFile(fset, mfile.Name, mfile.Body, 0)
+		if err != nil {
+			return nil, fmt.Errorf("parse %s: %w", mfile.Name, err)
+		}
+
+		cv := &coverVisitor{
+			fset: fset,
+			executed: func(pos token.Pos) bool {
+				p := fset.Position(pos)
+				return cov.Executed(memPkg.Path, mfile.Name, p.Line, p.Column)
+			},
+		}
+		ast.Walk(cv, f)
+
+		sort.SliceStable(cv.blocks, func(i, j int) bool {
+			bi, bj := cv.blocks[i], cv.blocks[j]
+			return bi.StartLine < bj.StartLine ||
+				bi.StartLine == bj.StartLine && bi.StartCol < bj.StartCol
+		})
+		pc.Files = append(pc.Files, &FileCoverage{
+			Name:   mfile.Name,
+			Blocks: cv.blocks,
+		})
+	}
+	return pc, nil
+}
+
+// coverVisitor splits the statements of a file into basic blocks, following
+// the same rules as cmd/cover.
+type coverVisitor struct {
+	fset     *token.FileSet
+	executed func(token.Pos) bool
+	blocks   []CoverBlock
+}
+
+func (v *coverVisitor) Visit(node ast.Node) ast.Visitor {
+	switch n := node.(type) {
+	case *ast.BlockStmt:
+		// The body of a switch is a list of case clauses, handled below.
+		if len(n.List) > 0 {
+			if _, ok := n.List[0].(*ast.CaseClause); ok {
+				return v
+			}
+		}
+		v.addBlocks(n.Lbrace, n.Rbrace+1, n.List, true)
+	case *ast.CaseClause:
+		v.addBlocks(n.Colon+1, n.End(), n.Body, false)
+	case *ast.IfStmt:
+		// An else block starts at the end of the if body, so that the else
+		// keyword is covered. An else if is wrapped in such a block.
+		switch els := n.Else.(type) {
+		case *ast.IfStmt:
+			n.Else = &ast.BlockStmt{
+				Lbrace: n.Body.End(),
+				List:   []ast.Stmt{els},
+				Rbrace: els.End(),
+			}
+		case *ast.BlockStmt:
+			els.Lbrace = n.Body.End()
+		}
+	}
+	return v
+}
+
+// addBlocks adds the basic blocks of the statement list, starting at pos.
+// If extendToClosingBrace is set, the last block ends at blockEnd.
+func (v *coverVisitor) addBlocks(pos, blockEnd token.Pos, list []ast.Stmt, extendToClosingBrace bool) {
+	// Empty blocks contain no statements, so their execution isn't recorded.
+	for len(list) > 0 {
+		end := blockEnd
+		last := 0
+		for ; last < len(list); last++ {
+			stmt := list[last]
+			end = statementBoundary(stmt)
+			if endsBasicBlock(stmt) {
+				last++
+				extendToClosingBrace = false
+				break
+			}
+		}
+		if extendToClosingBrace {
+			end = blockEnd
+		}
+		if pos != end {
+			start, stop := v.fset.Position(pos), v.fset.Position(end)
+			v.blocks = append(v.blocks, CoverBlock{
+				StartLine: start.Line,
+				StartCol:  start.Column,
+				EndLine:   stop.Line,
+				EndCol:    stop.Column,
+				NumStmt:   last,
+				Executed:  v.executed(list[0].Pos()),
+			})
+		}
+		list = list[last:]
+		if len(list) > 0 {
+			pos = list[0].Pos()
+		}
+	}
+}
+
+// statementBoundary returns the position where the basic block containing s
+// ends: the start of its body for control statements, or of the first
+// function literal it contains.
+func statementBoundary(s ast.Stmt) token.Pos {
+	switch s := s.(type) {
+	case *ast.BlockStmt:
+		return s.Lbrace
+	case *ast.IfStmt:
+		if pos, ok := funcLitPos(s.Init, s.Cond); ok {
+			return pos
+		}
+		return s.Body.Lbrace
+	case *ast.ForStmt:
+		if pos, ok := funcLitPos(s.Init, s.Cond, s.Post); ok {
+			return pos
+		}
+		return s.Body.Lbrace
+	case *ast.LabeledStmt:
+		return statementBoundary(s.Stmt)
+	case *ast.RangeStmt:
+		if pos, ok := funcLitPos(s.X); ok {
+			return pos
+		}
+		return s.Body.Lbrace
+	case *ast.SwitchStmt:
+		if pos, ok := funcLitPos(s.Init, s.Tag); ok {
+			return pos
+		}
+		return s.Body.Lbrace
+	case *ast.TypeSwitchStmt:
+		if pos, ok := funcLitPos(s.Init, s.Assign); ok {
+			return pos
+		}
+		return s.Body.Lbrace
+	}
+	if pos, ok := funcLitPos(s); ok {
+		return pos
+	}
+	return s.End()
+}
+
+// endsBasicBlock returns whether s ends the basic block it belongs to.
+func endsBasicBlock(s ast.Stmt) bool {
+	switch s := s.(type) {
+	case *ast.BlockStmt, *ast.BranchStmt, *ast.ForStmt, *ast.IfStmt,
+		*ast.RangeStmt, *ast.SwitchStmt, *ast.TypeSwitchStmt:
+		return true
+	case *ast.LabeledStmt:
+		return endsBasicBlock(s.Stmt)
+	case *ast.ExprStmt:
+		if call, ok := s.X.(*ast.CallExpr); ok {
+			if ident, ok := call.Fun.(*ast.Ident); ok && ident.Name == "panic" && len(call.Args) == 1 {
+				return true
+			}
+		}
+	}
+	_, ok := funcLitPos(s)
+	return ok
+}
+
+// funcLitPos returns the position of the body of the first function literal
+// found in the given nodes, if any.
+func funcLitPos(nodes ...ast.Node) (pos token.Pos, found bool) {
+	for _, n := range nodes {
+		if n == nil {
+			continue
+		}
+		ast.Inspect(n, func(n ast.Node) bool {
+			if found {
+				return false
+			}
+			if lit, ok := n.(*ast.FuncLit); ok {
+				pos, found = lit.Body.Lbrace, true
+				return false
+			}
+			return true
+		})
+		if found {
+			return
+		}
+	}
+	return
+}
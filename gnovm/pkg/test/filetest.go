@@ -63,6 +63,7 @@ func (opts *TestOptions) runFiletest(filename string, source []byte) (string, er
 		Store:         opts.TestStore.BeginTransaction(cw, cw, nil),
 		Context:       ctx,
 		MaxAllocBytes: maxAlloc,
+		Coverage:      opts.Coverage,
 	})
 	defer m.Release()
 	result := opts.runTest(m, pkgPath, filename, source)
@@ -0,0 +1,867 @@
+package test
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"math"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	gno "github.com/gnolang/gno/gnovm/pkg/gnolang"
+)
+
+const (
+	// fuzzCorpusHeader is the first line of the files of a fuzz corpus; the
+	// files are compatible with the ones of `go test -fuzz`.
+	fuzzCorpusHeader = "go test fuzz v1"
+	// fuzzProgressInterval is the interval between the progress reports
+	// printed while fuzzing.
+	fuzzProgressInterval = 3 * time.Second
+	// fuzzMinimizeLimit is the maximum number of inputs tried to minimize a
+	// failing input.
+	fuzzMinimizeLimit = 1000
+	// fuzzMaxCycles is the maximum number of cycles to run the fuzz target
+	// with an input, so that inputs which make it loop forever fail.
+	fuzzMaxCycles = 100_000_000
+)
+
+var (
+	errFuzzMultipleTargets = errors.New("will not fuzz, -fuzz matches more than one fuzz test")
+	errFuzzCorpusEntry     = errors.New("malformed fuzz corpus entry")
+)
+
+var bytesType = reflect.TypeOf([]byte(nil))
+
+// fuzzTypes are the Go types of the primitive types which can be fuzzed.
+var fuzzTypes = map[gno.PrimitiveType]reflect.Type{
+	gno.StringType:  reflect.TypeOf(""),
+	gno.BoolType:    reflect.TypeOf(false),
+	gno.IntType:     reflect.TypeOf(int(0)),
+	gno.Int8Type:    reflect.TypeOf(int8(0)),
+	gno.Int16Type:   reflect.TypeOf(int16(0)),
+	gno.Int32Type:   reflect.TypeOf(int32(0)),
+	gno.Int64Type:   reflect.TypeOf(int64(0)),
+	gno.UintType:    reflect.TypeOf(uint(0)),
+	gno.Uint8Type:   reflect.TypeOf(uint8(0)),
+	gno.Uint16Type:  reflect.TypeOf(uint16(0)),
+	gno.Uint32Type:  reflect.TypeOf(uint32(0)),
+	gno.Uint64Type:  reflect.TypeOf(uint64(0)),
+	gno.Float32Type: reflect.TypeOf(float32(0)),
+	gno.Float64Type: reflect.TypeOf(float64(0)),
+}
+
+// fuzzGoType returns the Go type of the argument type t of a fuzz target,
+// or nil if t can't be fuzzed.
+func fuzzGoType(t gno.Type) reflect.Type {
+	switch t := t.(type) {
+	case gno.PrimitiveType:
+		return fuzzTypes[t]
+	case *gno.SliceType:
+		if t.Elt == gno.Uint8Type && !t.Vrd {
+			return bytesType
+		}
+	}
+	return nil
+}
+
+// fuzzInput is an input of a fuzz target: the values of its arguments,
+// besides the *testing.T.
+type fuzzInput struct {
+	name string
+	args []interface{}
+}
+
+// fuzzer runs the fuzz target of a fuzz test, on the machine of the test.
+type fuzzer struct {
+	opts      *TestOptions
+	m         *gno.Machine
+	pv        *gno.PackageValue // the package of the fuzz test
+	testingcx gno.Expr
+	f         gno.Expr // the *testing.F of the fuzz test
+	name      string
+	fn        gno.TypedValue
+	types     []reflect.Type
+}
+
+// runFuzz runs the fuzz test ft: the fuzz target is run with the inputs of the
+// seed corpus, and of the corpus found in the testdata/fuzz directory of
+// fsDir. If fuzz is set, the fuzz target is then run with generated inputs,
+// until opts.FuzzTime is elapsed or a failing input is found.
+func (opts *TestOptions) runFuzz(m *gno.Machine, ft testFunc, fsDir string, fuzz bool) error {
+	testingpv := m.Store.GetPackage("testing", false)
+	testingtv := gno.TypedValue{T: &gno.PackageType{}, V: testingpv}
+	testingcx := &gno.ConstExpr{TypedValue: testingtv}
+
+	eval := m.Eval(gno.Call(
+		gno.Sel(testingcx, "SetupFuzz"),          // Call testing.SetupFuzz
+		gno.Str(opts.RunFlag),                    // run flag
+		gno.Nx(strconv.FormatBool(opts.Verbose)), // is verbose?
+		&gno.CompositeLitExpr{ // Third param, the testing.InternalFuzzTarget
+			Type: gno.Sel(testingcx, "InternalFuzzTarget"),
+			Elts: gno.KeyValueExprs{
+				{Key: gno.X("Name"), Value: gno.Str(ft.Name)},
+				{Key: gno.X("Fn"), Value: gno.Nx(ft.Name)},
+			},
+		},
+	))
+
+	fz := &fuzzer{
+		opts:      opts,
+		m:         m,
+		pv:        m.Package,
+		testingcx: testingcx,
+		f:         &gno.ConstExpr{TypedValue: eval[0]},
+		name:      ft.Name,
+		fn:        eval[1],
+	}
+
+	var msg string
+	if fz.fn.T != nil {
+		msg = fz.run(eval[2], fsDir, fuzz)
+	}
+
+	eval = m.Eval(gno.Call(
+		gno.Sel(testingcx, "ReportFuzz"),
+		fz.f,
+		gno.Str(msg),
+	))
+
+	ret := eval[0].GetString()
+	if ret == "" {
+		fmt.Fprintf(opts.Error, "--- FAIL: %s [internal gno testing error]", ft.Name)
+		return fmt.Errorf("failed to execute fuzz test: %q", ft.Name)
+	}
+
+	var rep report
+	if err := json.Unmarshal([]byte(ret), &rep); err != nil {
+		fmt.Fprintf(opts.Error, "--- FAIL: %s [internal gno testing error]", ft.Name)
+		return err
+	}
+
+	if rep.Failed {
+		return fmt.Errorf("failed: %q", ft.Name)
+	}
+	return nil
+}
+
+// run runs the fuzz target with the seed corpus and the corpus of fsDir, then
+// fuzzes it if fuzz is set. It returns the message to report as a failure of
+// the fuzz test, if any.
+func (fz *fuzzer) run(seeds gno.TypedValue, fsDir string, fuzz bool) string {
+	if err := fz.checkTarget(); err != nil {
+		return err.Error()
+	}
+
+	corpus, err := fz.seedCorpus(seeds)
+	if err != nil {
+		return err.Error()
+	}
+
+	dir := filepath.Join(fsDir, "testdata", "fuzz", fz.name)
+	files, err := readFuzzCorpus(dir, fz.types)
+	if err != nil {
+		return err.Error()
+	}
+	corpus = append(corpus, files...)
+
+	for _, in := range corpus {
+		if failed, msg := fz.runInput(in); failed {
+			// Don't fuzz a target which already fails.
+			return msg
+		}
+	}
+
+	if !fuzz {
+		return ""
+	}
+	return fz.fuzz(corpus, fsDir)
+}
+
+// checkTarget checks the signature of the fuzz target, and sets fz.types.
+func (fz *fuzzer) checkTarget() error {
+	ft, ok := gno.BaseOf(fz.fn.T).(*gno.FuncType)
+	switch {
+	case !ok:
+		return errors.New("testing: F.Fuzz function must be a function")
+	case len(ft.Results) != 0:
+		return errors.New("testing: F.Fuzz function must not return a value")
+	case len(ft.Params) < 2 || ft.HasVarg():
+		return errors.New("testing: F.Fuzz function must receive at least two arguments, and not be variadic")
+	case ft.Params[0].Type.String() != "*testing.T":
+		return errors.New("testing: F.Fuzz function's first argument must be of type *testing.T")
+	}
+
+	for _, p := range ft.Params[1:] {
+		rt := fuzzGoType(p.Type)
+		if rt == nil {
+			return fmt.Errorf("testing: F.Fuzz function's arguments can't be fuzzed, unsupported type %s", p.Type.String())
+		}
+		fz.types = append(fz.types, rt)
+	}
+	return nil
+}
+
+// seedCorpus returns the inputs added with testing.F.Add, checking that they
+// match the arguments of the fuzz target.
+func (fz *fuzzer) seedCorpus(seeds gno.TypedValue) ([]fuzzInput, error) {
+	n := seeds.GetLength()
+	corpus := make([]fuzzInput, 0, n)
+	for i := 0; i < n; i++ {
+		entry := seeds.GetPointerAtIndexInt(fz.m.Store, i).Deref()
+		in := fuzzInput{
+			name: fmt.Sprintf("seed#%d", i),
+			args: make([]interface{}, entry.GetLength()),
+		}
+		for j := range in.args {
+			arg := entry.GetPointerAtIndexInt(fz.m.Store, j).Deref()
+			in.args[j] = gno.Gno2GoValue(&arg, reflect.Value{}).Interface()
+		}
+		if err := checkFuzzArgs(in.args, fz.types); err != nil {
+			return nil, fmt.Errorf("testing: mismatched types in corpus entry %s: %w", in.name, err)
+		}
+		corpus = append(corpus, in)
+	}
+	return corpus, nil
+}
+
+// checkFuzzArgs checks that the arguments are of the given types.
+func checkFuzzArgs(args []interface{}, types []reflect.Type) error {
+	if len(args) != len(types) {
+		return fmt.Errorf("got %d values, want %d", len(args), len(types))
+	}
+	for i, arg := range args {
+		if rt := reflect.TypeOf(arg); rt != types[i] {
+			return fmt.Errorf("value #%d is of type %v, want %v", i, rt, types[i])
+		}
+	}
+	return nil
+}
+
+// call returns a function literal, calling the fuzz target with args.
+func (fz *fuzzer) call(args []interface{}) *gno.FuncLitExpr {
+	xs := make([]interface{}, 0, len(args)+1)
+	xs = append(xs, gno.Nx("t"))
+	for _, arg := range args {
+		tv := gno.Go2GnoValue(fz.m.Alloc, fz.m.Store, reflect.ValueOf(arg))
+		xs = append(xs, &gno.ConstExpr{TypedValue: tv})
+	}
+	return gno.Fn(
+		gno.Flds("t", gno.Ptr(gno.Sel(fz.testingcx, "T"))),
+		nil,
+		gno.Ss(gno.S(gno.Call(&gno.ConstExpr{TypedValue: fz.fn}, xs...))),
+	)
+}
+
+// runInput runs the fuzz target with the input, as a subtest of the fuzz
+// test. It returns whether the fuzz test failed, and the message to report if
+// the input exceeded fuzzMaxCycles.
+func (fz *fuzzer) runInput(in fuzzInput) (bool, string) {
+	eval, ok := fz.eval(gno.Call(
+		gno.Sel(fz.testingcx, "RunFuzzInput"),
+		fz.f,
+		gno.Str(in.name),
+		fz.call(in.args),
+	))
+	if !ok {
+		return true, fmt.Sprintf("input %s exceeded the maximum of %d cycles", in.name, fuzzMaxCycles)
+	}
+	return eval[0].GetBool(), ""
+}
+
+// tryInput runs the fuzz target with args, without printing anything. It
+// returns whether the fuzz target failed, or exceeded fuzzMaxCycles.
+func (fz *fuzzer) tryInput(args []interface{}) bool {
+	eval, ok := fz.eval(gno.Call(
+		gno.Sel(fz.testingcx, "TryFuzzInput"),
+		fz.call(args),
+	))
+	return !ok || eval[0].GetBool()
+}
+
+// eval evaluates x, which runs the fuzz target with an input, with at most
+// fuzzMaxCycles cycles. It returns false if x exceeded them; the machine is
+// then replaced, as its state is left inconsistent.
+func (fz *fuzzer) eval(x gno.Expr) (res []gno.TypedValue, ok bool) {
+	m := fz.m
+	maxCycles := m.MaxCycles
+	m.MaxCycles = m.Cycles + fuzzMaxCycles
+	defer func() {
+		m.MaxCycles = maxCycles
+		r := recover()
+		if r == nil {
+			return
+		}
+		if r != "CPU cycle overrun" {
+			panic(r)
+		}
+		fz.m = gno.NewMachineWithOptions(gno.MachineOptions{
+			Store:     m.Store,
+			Output:    m.Output,
+			Context:   m.Context,
+			Alloc:     m.Alloc.Reset(),
+			MaxCycles: maxCycles,
+			GasMeter:  m.GasMeter,
+			Coverage:  m.Coverage,
+		})
+		fz.m.SetActivePackage(fz.pv)
+		ok = false
+	}()
+	return m.Eval(x), true
+}
+
+// fuzz runs the fuzz target with inputs generated by mutating the ones of
+// the corpus. Inputs executing new statements are added to the corpus.
+// When an input makes the fuzz target fail, it is minimized and written to
+// the corpus directory of fsDir, and the message to report is returned.
+func (fz *fuzzer) fuzz(corpus []fuzzInput, fsDir string) string {
+	cov := fz.m.Coverage
+	if cov == nil {
+		cov = gno.NewCoverage()
+		fz.m.Coverage = cov
+		defer func() { fz.m.Coverage = nil }()
+	}
+
+	if len(corpus) == 0 {
+		// Start from the zero values, like `go test -fuzz`.
+		args := make([]interface{}, len(fz.types))
+		for i, rt := range fz.types {
+			args[i] = reflect.Zero(rt).Interface()
+		}
+		corpus = append(corpus, fuzzInput{args: args})
+	}
+
+	// Gather the baseline coverage.
+	for _, in := range corpus {
+		fz.tryInput(in.args)
+	}
+
+	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+	start := time.Now()
+	lastProgress := start
+	seeds := len(corpus)
+	execs := 0
+	progress := func() {
+		elapsed := time.Since(start)
+		fmt.Fprintf(fz.opts.Error, "fuzz: elapsed: %s, execs: %d (%.0f/sec), new interesting: %d (total: %d)\n",
+			elapsed.Round(time.Second), execs, float64(execs)/elapsed.Seconds(), len(corpus)-seeds, len(corpus))
+	}
+
+	fuzzTime := fz.opts.FuzzTime
+	for fuzzTime.N == 0 || execs < fuzzTime.N {
+		if fuzzTime.D > 0 && time.Since(start) >= fuzzTime.D {
+			break
+		}
+		if time.Since(lastProgress) >= fuzzProgressInterval {
+			progress()
+			lastProgress = time.Now()
+		}
+
+		args := mutateFuzzArgs(r, corpus[r.Intn(len(corpus))].args)
+		count := cov.Count()
+		execs++
+		if fz.tryInput(args) {
+			progress()
+			return fz.crash(args, fsDir)
+		}
+		if cov.Count() > count {
+			corpus = append(corpus, fuzzInput{args: args})
+		}
+	}
+	progress()
+	return ""
+}
+
+// crash minimizes the failing input args, and writes it to the corpus
+// directory of fsDir. The minimized input is then run as a subtest of the
+// fuzz test, to report its failure.
+func (fz *fuzzer) crash(args []interface{}, fsDir string) string {
+	args = minimizeFuzzArgs(args, fz.tryInput)
+
+	data := marshalFuzzCorpus(args)
+	name := fmt.Sprintf("%x", sha256.Sum256(data))[:16]
+	path := filepath.Join("testdata", "fuzz", fz.name, name)
+	if err := os.MkdirAll(filepath.Join(fsDir, filepath.Dir(path)), 0o755); err != nil {
+		return fmt.Sprintf("could not write failing input: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(fsDir, path), data, 0o644); err != nil {
+		return fmt.Sprintf("could not write failing input: %v", err)
+	}
+
+	_, msg := fz.runInput(fuzzInput{name: name, args: args})
+	if msg != "" {
+		msg += "\n"
+	}
+	return msg + fmt.Sprintf("Failing input written to %s\nTo re-run:\ngno test -run=%s/%s",
+		filepath.ToSlash(path), fz.name, name)
+}
+
+// fuzzTargetsToFuzz returns the fuzz tests matched by the -fuzz regexp.
+// Like `go test`, at most one fuzz test can be fuzzed at a time.
+func fuzzTargetsToFuzz(fuzzFlag string, fuzzTests []testFunc) (string, error) {
+	if fuzzFlag == "" {
+		return "", nil
+	}
+	re, err := regexp.Compile(fuzzFlag)
+	if err != nil {
+		return "", err
+	}
+	var matched []string
+	for _, ft := range fuzzTests {
+		if re.MatchString(ft.Name) {
+			matched = append(matched, ft.Name)
+		}
+	}
+	switch len(matched) {
+	case 0:
+		return "", nil
+	case 1:
+		return matched[0], nil
+	}
+	return "", fmt.Errorf("%w: %v", errFuzzMultipleTargets, matched)
+}
+
+// ----------------------------------------
+// Mutation and minimization
+
+// mutateFuzzArgs returns a copy of args, with one of them mutated.
+func mutateFuzzArgs(r *rand.Rand, args []interface{}) []interface{} {
+	mutated := make([]interface{}, len(args))
+	copy(mutated, args)
+	i := r.Intn(len(args))
+	mutated[i] = mutateFuzzValue(r, args[i])
+	return mutated
+}
+
+func mutateFuzzValue(r *rand.Rand, v interface{}) interface{} {
+	switch v := v.(type) {
+	case string:
+		return string(mutateBytes(r, []byte(v)))
+	case []byte:
+		return mutateBytes(r, append([]byte(nil), v...))
+	case bool:
+		return !v
+	case float32:
+		return float32(mutateFloat(r, float64(v)))
+	case float64:
+		return mutateFloat(r, v)
+	}
+
+	rv := reflect.ValueOf(v)
+	nv := reflect.New(rv.Type()).Elem()
+	bits := rv.Type().Bits()
+	delta := uint64(r.Intn(35) + 1)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		x := uint64(rv.Int())
+		nv.SetInt(int64(mutateInt(r, x, delta, bits)))
+	default:
+		nv.SetUint(mutateInt(r, rv.Uint(), delta, bits))
+	}
+	return nv.Interface()
+}
+
+// mutateInt mutates the integer x of the given size in bits; the result
+// wraps around, and is then truncated by the caller.
+func mutateInt(r *rand.Rand, x, delta uint64, bits int) uint64 {
+	switch r.Intn(4) {
+	case 0:
+		return x + delta
+	case 1:
+		return x - delta
+	case 2:
+		return x ^ 1<<r.Intn(bits)
+	default:
+		return r.Uint64()
+	}
+}
+
+func mutateFloat(r *rand.Rand, x float64) float64 {
+	switch r.Intn(5) {
+	case 0:
+		return x + float64(r.Intn(35)+1)
+	case 1:
+		return x - float64(r.Intn(35)+1)
+	case 2:
+		return x * (r.Float64()*4 - 2)
+	case 3:
+		return -x
+	default:
+		return math.Float64frombits(math.Float64bits(x) ^ 1<<r.Intn(64))
+	}
+}
+
+func mutateBytes(r *rand.Rand, b []byte) []byte {
+	if len(b) == 0 {
+		return []byte{byte(r.Intn(256))}
+	}
+	i := r.Intn(len(b))
+	switch r.Intn(6) {
+	case 0: // insert a random byte, possibly at the end
+		i = r.Intn(len(b) + 1)
+		b = append(b[:i], append([]byte{byte(r.Intn(256))}, b[i:]...)...)
+	case 1: // remove a byte
+		b = append(b[:i], b[i+1:]...)
+	case 2: // flip a bit
+		b[i] ^= 1 << r.Intn(8)
+	case 3: // replace a byte
+		b[i] = byte(r.Intn(256))
+	case 4: // duplicate a chunk
+		j := i + r.Intn(len(b)-i) + 1
+		b = append(b[:j], append(append([]byte(nil), b[i:j]...), b[j:]...)...)
+	default: // swap two bytes
+		j := r.Intn(len(b))
+		b[i], b[j] = b[j], b[i]
+	}
+	return b
+}
+
+// minimizeFuzzArgs tries to make the arguments of the failing input args
+// smaller, while still making the fuzz target fail: strings and byte slices
+// are shortened, and numbers are moved towards zero.
+func minimizeFuzzArgs(args []interface{}, fails func([]interface{}) bool) []interface{} {
+	args = append([]interface{}(nil), args...)
+	tries := 0
+	try := func(i int, v interface{}) bool {
+		if tries >= fuzzMinimizeLimit {
+			return false
+		}
+		tries++
+		cand := append([]interface{}(nil), args...)
+		cand[i] = v
+		if fails(cand) {
+			args = cand
+			return true
+		}
+		return false
+	}
+
+	for i, arg := range args {
+		switch v := arg.(type) {
+		case string:
+			minimizeBytes([]byte(v), func(b []byte) bool { return try(i, string(b)) })
+		case []byte:
+			minimizeBytes(v, func(b []byte) bool { return try(i, b) })
+		case bool:
+			if v {
+				try(i, false)
+			}
+		case float32:
+			minimizeFloat(float64(v), func(x float64) bool { return try(i, float32(x)) })
+		case float64:
+			minimizeFloat(v, func(x float64) bool { return try(i, x) })
+		default:
+			rv := reflect.ValueOf(arg)
+			set := func(x int64) bool {
+				nv := reflect.New(rv.Type()).Elem()
+				if rv.CanInt() {
+					nv.SetInt(x)
+				} else {
+					nv.SetUint(uint64(x))
+				}
+				return try(i, nv.Interface())
+			}
+			if rv.CanInt() {
+				minimizeInt(rv.Int(), set)
+			} else if rv.Uint() <= math.MaxInt64 {
+				minimizeInt(int64(rv.Uint()), set)
+			}
+		}
+	}
+	return args
+}
+
+// minimizeBytes removes chunks of b, of decreasing sizes, as long as try
+// succeeds.
+func minimizeBytes(b []byte, try func([]byte) bool) {
+	if len(b) > 0 && try(nil) {
+		return
+	}
+	for size := len(b) / 2; size > 0; size /= 2 {
+		for i := 0; i+size <= len(b); {
+			cand := append(append([]byte(nil), b[:i]...), b[i+size:]...)
+			if try(cand) {
+				b = cand
+			} else {
+				i += size
+			}
+		}
+	}
+}
+
+// minimizeInt moves x towards zero as long as try succeeds, halving the
+// steps when it fails.
+func minimizeInt(x int64, try func(int64) bool) {
+	if x == 0 || try(0) {
+		return
+	}
+	step := x / 2
+	for step != 0 {
+		if try(x - step) {
+			x -= step
+		} else {
+			step /= 2
+		}
+	}
+}
+
+func minimizeFloat(x float64, try func(float64) bool) {
+	if x == 0 || try(0) {
+		return
+	}
+	if t := math.Trunc(x); t != x {
+		try(t)
+	}
+}
+
+// ----------------------------------------
+// Corpus files
+
+// marshalFuzzCorpus encodes args in the format of the corpus files of
+// `go test -fuzz`.
+func marshalFuzzCorpus(args []interface{}) []byte {
+	var sb strings.Builder
+	sb.WriteString(fuzzCorpusHeader + "\n")
+	for _, arg := range args {
+		switch v := arg.(type) {
+		case string:
+			fmt.Fprintf(&sb, "string(%q)\n", v)
+		case []byte:
+			fmt.Fprintf(&sb, "[]byte(%q)\n", v)
+		case int32:
+			if utf8.ValidRune(v) && strconv.IsPrint(v) {
+				fmt.Fprintf(&sb, "rune(%q)\n", v)
+			} else {
+				fmt.Fprintf(&sb, "int32(%d)\n", v)
+			}
+		case uint8:
+			fmt.Fprintf(&sb, "byte(%q)\n", v)
+		case float32:
+			if math.IsNaN(float64(v)) {
+				fmt.Fprintf(&sb, "math.Float32frombits(0x%x)\n", math.Float32bits(v))
+			} else {
+				fmt.Fprintf(&sb, "float32(%v)\n", v)
+			}
+		case float64:
+			if math.IsNaN(v) {
+				fmt.Fprintf(&sb, "math.Float64frombits(0x%x)\n", math.Float64bits(v))
+			} else {
+				fmt.Fprintf(&sb, "float64(%v)\n", v)
+			}
+		default: // bool and other integers
+			fmt.Fprintf(&sb, "%T(%v)\n", v, v)
+		}
+	}
+	return []byte(sb.String())
+}
+
+// readFuzzCorpus reads the corpus files of dir, checking that their values
+// are of the given types. A missing directory is an empty corpus.
+func readFuzzCorpus(dir string, types []reflect.Type) ([]fuzzInput, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var corpus []fuzzInput
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		args, err := unmarshalFuzzCorpus(data)
+		if err != nil {
+			return nil, fmt.Errorf("%w, %q: %v", errFuzzCorpusEntry, entry.Name(), err)
+		}
+		if err := checkFuzzArgs(args, types); err != nil {
+			return nil, fmt.Errorf("%w, %q: %v", errFuzzCorpusEntry, entry.Name(), err)
+		}
+		corpus = append(corpus, fuzzInput{name: entry.Name(), args: args})
+	}
+	return corpus, nil
+}
+
+// unmarshalFuzzCorpus decodes the values of a corpus file.
+func unmarshalFuzzCorpus(data []byte) ([]interface{}, error) {
+	lines := strings.Split(string(data), "\n")
+	if len(lines) == 0 || strings.TrimSpace(lines[0]) != fuzzCorpusHeader {
+		return nil, errors.New("missing version header")
+	}
+
+	var args []interface{}
+	for _, line := range lines[1:] {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		v, err := parseFuzzValue(line)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", line, err)
+		}
+		args = append(args, v)
+	}
+	return args, nil
+}
+
+func parseFuzzValue(line string) (interface{}, error) {
+	x, err := parser.ParseExpr(line)
+	if err != nil {
+		return nil, err
+	}
+	call, ok := x.(*ast.CallExpr)
+	if !ok || len(call.Args) != 1 {
+		return nil, errors.New("expected a call expression with one argument")
+	}
+	arg := call.Args[0]
+
+	var typ string
+	switch fun := call.Fun.(type) {
+	case *ast.Ident:
+		typ = fun.Name
+	case *ast.ArrayType:
+		if elt, ok := fun.Elt.(*ast.Ident); ok && fun.Len == nil && (elt.Name == "byte" || elt.Name == "uint8") {
+			typ = "[]byte"
+		}
+	case *ast.SelectorExpr:
+		if pkg, ok := fun.X.(*ast.Ident); ok && pkg.Name == "math" {
+			typ = "math." + fun.Sel.Name
+		}
+	}
+
+	switch typ {
+	case "string", "[]byte":
+		lit, ok := arg.(*ast.BasicLit)
+		if !ok || lit.Kind != token.STRING {
+			return nil, errors.New("expected a string literal")
+		}
+		s, err := strconv.Unquote(lit.Value)
+		if err != nil {
+			return nil, err
+		}
+		if typ == "string" {
+			return s, nil
+		}
+		return []byte(s), nil
+	case "bool":
+		if id, ok := arg.(*ast.Ident); ok && (id.Name == "true" || id.Name == "false") {
+			return id.Name == "true", nil
+		}
+		return nil, errors.New("expected a boolean")
+	case "float32", "float64":
+		f, err := parseFuzzFloat(arg, typ)
+		if err != nil {
+			return nil, err
+		}
+		if typ == "float32" {
+			return float32(f), nil
+		}
+		return f, nil
+	case "math.Float32frombits", "math.Float64frombits":
+		bits, err := parseFuzzInt(arg, 64, false)
+		if err != nil {
+			return nil, err
+		}
+		if typ == "math.Float32frombits" {
+			return math.Float32frombits(uint32(bits)), nil
+		}
+		return math.Float64frombits(bits), nil
+	}
+
+	for _, rt := range fuzzTypes {
+		if rt.Name() != typ && !(typ == "rune" && rt.Kind() == reflect.Int32) && !(typ == "byte" && rt.Kind() == reflect.Uint8) {
+			continue
+		}
+		signed := rt.Kind() >= reflect.Int && rt.Kind() <= reflect.Int64
+		x, err := parseFuzzInt(arg, rt.Bits(), signed)
+		if err != nil {
+			return nil, err
+		}
+		rv := reflect.New(rt).Elem()
+		if signed {
+			rv.SetInt(int64(x))
+		} else {
+			rv.SetUint(x)
+		}
+		return rv.Interface(), nil
+	}
+	return nil, fmt.Errorf("unsupported type %q", typ)
+}
+
+// parseFuzzInt parses an integer or character literal, possibly negative.
+func parseFuzzInt(x ast.Expr, bits int, signed bool) (uint64, error) {
+	neg := false
+	if u, ok := x.(*ast.UnaryExpr); ok && u.Op == token.SUB && signed {
+		neg, x = true, u.X
+	}
+	lit, ok := x.(*ast.BasicLit)
+	if !ok {
+		return 0, errors.New("expected an integer literal")
+	}
+
+	s := lit.Value
+	switch lit.Kind {
+	case token.CHAR:
+		r, _, _, err := strconv.UnquoteChar(s[1:len(s)-1], '\'')
+		if err != nil {
+			return 0, err
+		}
+		s = strconv.Itoa(int(r))
+	case token.INT:
+	default:
+		return 0, errors.New("expected an integer literal")
+	}
+	if neg {
+		s = "-" + s
+	}
+
+	if signed {
+		i, err := strconv.ParseInt(s, 0, bits)
+		return uint64(i), err
+	}
+	return strconv.ParseUint(s, 0, bits)
+}
+
+// parseFuzzFloat parses a float literal, possibly negative, or one of the
+// special values printed by fmt: +Inf, -Inf and NaN.
+func parseFuzzFloat(x ast.Expr, typ string) (float64, error) {
+	sign := ""
+	if u, ok := x.(*ast.UnaryExpr); ok && (u.Op == token.SUB || u.Op == token.ADD) {
+		sign, x = u.Op.String(), u.X
+	}
+	var s string
+	switch x := x.(type) {
+	case *ast.BasicLit:
+		if x.Kind != token.INT && x.Kind != token.FLOAT {
+			return 0, errors.New("expected a float literal")
+		}
+		s = x.Value
+	case *ast.Ident:
+		if x.Name != "Inf" && x.Name != "NaN" {
+			return 0, errors.New("expected a float literal")
+		}
+		s = x.Name
+	default:
+		return 0, errors.New("expected a float literal")
+	}
+	bits := 64
+	if typ == "float32" {
+		bits = 32
+	}
+	return strconv.ParseFloat(sign+s, bits)
+}
@@ -0,0 +1,206 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	gno "github.com/gnolang/gno/gnovm/pkg/gnolang"
+	teststd "github.com/gnolang/gno/gnovm/tests/stdlibs/std"
+)
+
+// TestEvent is an event of the JSON output of tests. It has the same fields as
+// the events of `go test -json` (see `go doc test2json`), along with the gas
+// used and the events emitted by top-level tests.
+type TestEvent struct {
+	Time    time.Time
+	Action  string
+	Package string   `json:",omitempty"`
+	Test    string   `json:",omitempty"`
+	Elapsed *float64 `json:",omitempty"` // seconds
+	Output  string   `json:",omitempty"`
+	// Gas used by the test, set on the final event of top-level tests.
+	Gas int64 `json:",omitempty"`
+	// Events emitted by the test, set on the final event of top-level tests.
+	Events json.RawMessage `json:",omitempty"`
+}
+
+// Actions of the [TestEvent]s.
+const (
+	ActionStart  = "start"
+	ActionRun    = "run"
+	ActionOutput = "output"
+	ActionPass   = "pass"
+	ActionFail   = "fail"
+	ActionSkip   = "skip"
+)
+
+// JSONWriter converts the verbose output of tests, written to it, into a
+// stream of JSON [TestEvent]s, like `go test -json`.
+// The lines starting and ending tests are turned into run, pass, fail and skip
+// events, and all the lines are written as output events of the running test,
+// or of the package if none is running.
+type JSONWriter struct {
+	enc     *json.Encoder
+	pkg     string
+	line    []byte     // incomplete line
+	running []string   // names of the running tests
+	pending *TestEvent // final event of a top-level test, see endTest
+}
+
+// NewJSONWriter returns a JSONWriter, writing the events to w.
+func NewJSONWriter(w io.Writer) *JSONWriter {
+	return &JSONWriter{enc: json.NewEncoder(w)}
+}
+
+// StartPackage writes the start event of the tests of pkg; the following
+// events belong to pkg.
+func (jw *JSONWriter) StartPackage(pkg string) {
+	jw.flush()
+	jw.pkg = pkg
+	jw.running = nil
+	jw.emit(TestEvent{Action: ActionStart})
+}
+
+// EndPackage writes the final event of the tests of the package, which is one
+// of ActionPass, ActionFail or ActionSkip.
+func (jw *JSONWriter) EndPackage(action string, elapsed time.Duration) {
+	jw.flush()
+	secs := elapsed.Seconds()
+	jw.emit(TestEvent{Action: action, Elapsed: &secs})
+	jw.pkg = ""
+	jw.running = nil
+}
+
+// Write converts the complete lines of p to events.
+func (jw *JSONWriter) Write(p []byte) (int, error) {
+	jw.line = append(jw.line, p...)
+	for {
+		i := bytes.IndexByte(jw.line, '\n')
+		if i < 0 {
+			break
+		}
+		jw.handleLine(string(jw.line[:i+1]))
+		jw.line = jw.line[i+1:]
+	}
+	return len(p), nil
+}
+
+// flush writes the pending event, and the incomplete line as an output event.
+func (jw *JSONWriter) flush() {
+	jw.flushPending()
+	if len(jw.line) > 0 {
+		jw.handleLine(string(jw.line))
+		jw.line = nil
+	}
+}
+
+func (jw *JSONWriter) flushPending() {
+	if jw.pending != nil {
+		jw.emit(*jw.pending)
+		jw.pending = nil
+	}
+}
+
+func (jw *JSONWriter) handleLine(line string) {
+	jw.flushPending()
+
+	trimmed := strings.TrimSpace(line)
+	if name, ok := strings.CutPrefix(trimmed, "=== RUN   "); ok {
+		jw.running = append(jw.running, name)
+		jw.emit(TestEvent{Action: ActionRun, Test: name})
+		jw.emit(TestEvent{Action: ActionOutput, Test: name, Output: line})
+		return
+	}
+
+	for _, res := range testResults {
+		rest, ok := strings.CutPrefix(trimmed, res.prefix)
+		if !ok {
+			continue
+		}
+		name, elapsed := parseTestResult(rest)
+		jw.emit(TestEvent{Action: ActionOutput, Test: name, Output: line})
+		jw.stop(name)
+
+		ev := TestEvent{Action: res.action, Test: name, Elapsed: elapsed}
+		if !strings.Contains(name, "/") {
+			// Wait for the metrics of the test.
+			ev.Package, ev.Time = jw.pkg, time.Now()
+			jw.pending = &ev
+			return
+		}
+		jw.emit(ev)
+		return
+	}
+
+	var name string
+	if len(jw.running) > 0 {
+		name = jw.running[len(jw.running)-1]
+	}
+	jw.emit(TestEvent{Action: ActionOutput, Test: name, Output: line})
+}
+
+// testResults are the prefixes of the lines ending tests, and their actions.
+var testResults = []struct{ prefix, action string }{
+	{"--- PASS: ", ActionPass},
+	{"--- FAIL: ", ActionFail},
+	{"--- SKIP: ", ActionSkip},
+}
+
+// stop removes the test name from the running tests.
+func (jw *JSONWriter) stop(name string) {
+	for i := len(jw.running) - 1; i >= 0; i-- {
+		if jw.running[i] == name {
+			jw.running = append(jw.running[:i], jw.running[i+1:]...)
+			return
+		}
+	}
+}
+
+// parseTestResult parses the name and the duration of a test, from the end
+// of a line like "--- PASS: TestName (0.01s)".
+func parseTestResult(s string) (name string, elapsed *float64) {
+	i := strings.LastIndex(s, " (")
+	if i < 0 || !strings.HasSuffix(s, "s)") {
+		return s, nil
+	}
+	secs, err := strconv.ParseFloat(s[i+2:len(s)-2], 64)
+	if err != nil {
+		return s, nil
+	}
+	return s[:i], &secs
+}
+
+// endTest sets the gas used and the events emitted on m by the top-level test
+// name on its final event, and writes it.
+func (jw *JSONWriter) endTest(name string, m *gno.Machine) {
+	ev := jw.pending
+	if ev == nil || ev.Test != name {
+		return
+	}
+	if m.GasMeter != nil {
+		ev.Gas = m.GasMeter.GasConsumed()
+	}
+	if ctx, ok := m.Context.(*teststd.TestExecContext); ok {
+		if events := ctx.EventLogger.Events(); events != nil {
+			if res, err := json.Marshal(events); err == nil {
+				ev.Events = res
+			}
+		}
+	}
+	jw.flushPending()
+}
+
+func (jw *JSONWriter) emit(ev TestEvent) {
+	if ev.Time.IsZero() {
+		ev.Time = time.Now()
+	}
+	if ev.Package == "" {
+		ev.Package = jw.pkg
+	}
+	// Errors are ignored, like the ones of the writers of test output.
+	_ = jw.enc.Encode(ev)
+}
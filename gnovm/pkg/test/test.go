@@ -81,17 +81,34 @@ func Machine(testStore gno.Store, output io.Writer, pkgPath string) *gno.Machine
 // ----------------------------------------
 // testParams
 
-type testParams struct{}
+// testParams is an in-memory params store.
+type testParams struct {
+	params map[string]any
+}
 
 func newTestParams() *testParams {
-	return &testParams{}
+	return &testParams{params: make(map[string]any)}
 }
 
-func (tp *testParams) SetBool(key string, val bool)     { /* noop */ }
-func (tp *testParams) SetBytes(key string, val []byte)  { /* noop */ }
-func (tp *testParams) SetInt64(key string, val int64)   { /* noop */ }
-func (tp *testParams) SetUint64(key string, val uint64) { /* noop */ }
-func (tp *testParams) SetString(key string, val string) { /* noop */ }
+func (tp *testParams) SetBool(key string, val bool)     { tp.params[key] = val }
+func (tp *testParams) SetBytes(key string, val []byte)  { tp.params[key] = val }
+func (tp *testParams) SetInt64(key string, val int64)   { tp.params[key] = val }
+func (tp *testParams) SetUint64(key string, val uint64) { tp.params[key] = val }
+func (tp *testParams) SetString(key string, val string) { tp.params[key] = val }
+
+func (tp *testParams) GetBool(key string, ptr *bool) bool     { return getTestParam(tp, key, ptr) }
+func (tp *testParams) GetBytes(key string, ptr *[]byte) bool  { return getTestParam(tp, key, ptr) }
+func (tp *testParams) GetInt64(key string, ptr *int64) bool   { return getTestParam(tp, key, ptr) }
+func (tp *testParams) GetUint64(key string, ptr *uint64) bool { return getTestParam(tp, key, ptr) }
+func (tp *testParams) GetString(key string, ptr *string) bool { return getTestParam(tp, key, ptr) }
+
+func getTestParam[T any](tp *testParams, key string, ptr *T) bool {
+	val, ok := tp.params[key].(T)
+	if ok {
+		*ptr = val
+	}
+	return ok
+}
 
 // ----------------------------------------
 // main test function
@@ -121,6 +138,26 @@ type TestOptions struct {
 	Metrics bool
 	// Uses Error to print the events emitted.
 	Events bool
+	// Records the statements executed by the tests, if set.
+	Coverage *gno.Coverage
+	// Regular expression of the benchmarks to run. Benchmarks are not run if
+	// empty.
+	Bench string
+	// Duration or number of iterations of each benchmark; defaults to
+	// DefaultBenchTime.
+	BenchTime BenchTime
+	// Uses Error to print the memory allocations of benchmarks.
+	BenchMem bool
+	// Regular expression of the fuzz test to fuzz. Fuzz tests only run their
+	// seed corpus if empty.
+	Fuzz string
+	// Duration or number of executions of fuzzing; fuzzing runs until a
+	// failing input is found if zero.
+	FuzzTime BenchTime
+	// Converts the output of the tests to JSON events, if set. It should also
+	// be used as Output and Error, with Verbose enabled; the gas used and the
+	// events emitted are then added to the final events of top-level tests.
+	JSON *JSONWriter
 
 	filetestBuffer bytes.Buffer
 	outWriter      proxyWriter
@@ -173,7 +210,7 @@ func (p *proxyWriter) tee(w io.Writer) (revert func()) {
 // Test runs tests on the specified memPkg.
 // fsDir is the directory on filesystem of package; it's used in case opts.Sync
 // is enabled, and points to the directory where the files are contained if they
-// are to be updated.
+// are to be updated. It also contains the testdata/fuzz corpus of fuzz tests.
 // opts is a required set of options, which is often shared among different
 // tests; you can use [NewTestOptions] for a common base configuration.
 func Test(memPkg *gnovm.MemPackage, fsDir string, opts *TestOptions) error {
@@ -201,7 +238,7 @@ func Test(memPkg *gnovm.MemPackage, fsDir string, opts *TestOptions) error {
 
 		// Run test files in pkg.
 		if len(tset.Files) > 0 {
-			err := opts.runTestFiles(memPkg, tset, cw, gs)
+			err := opts.runTestFiles(memPkg, tset, fsDir, cw, gs)
 			if err != nil {
 				errs = multierr.Append(errs, err)
 			}
@@ -215,7 +252,7 @@ func Test(memPkg *gnovm.MemPackage, fsDir string, opts *TestOptions) error {
 				Files: itfiles,
 			}
 
-			err := opts.runTestFiles(itPkg, itset, cw, gs)
+			err := opts.runTestFiles(itPkg, itset, fsDir, cw, gs)
 			if err != nil {
 				errs = multierr.Append(errs, err)
 			}
@@ -267,6 +304,7 @@ func Test(memPkg *gnovm.MemPackage, fsDir string, opts *TestOptions) error {
 func (opts *TestOptions) runTestFiles(
 	memPkg *gnovm.MemPackage,
 	files *gno.FileSet,
+	fsDir string,
 	cw storetypes.Store, gs gno.TransactionStore,
 ) (errs error) {
 	var m *gno.Machine
@@ -283,7 +321,7 @@ func (opts *TestOptions) runTestFiles(
 		}
 	}()
 
-	tests := loadTestFuncs(memPkg.Name, files)
+	tests := loadTestFuncs(memPkg.Name, files, "Test")
 
 	var alloc *gno.Allocator
 	if opts.Metrics {
@@ -296,6 +334,7 @@ func (opts *TestOptions) runTestFiles(
 	// loaded.
 	m = Machine(gs, opts.WriterForStore(), memPkg.Path)
 	m.Alloc = alloc
+	m.Coverage = opts.Coverage
 	if opts.TestStore.GetMemPackage(memPkg.Path) == nil {
 		m.RunMemPackage(memPkg, true)
 	} else {
@@ -316,6 +355,10 @@ func (opts *TestOptions) runTestFiles(
 		// - Wrap here.
 		m = Machine(gs, opts.Output, memPkg.Path)
 		m.Alloc = alloc.Reset()
+		m.Coverage = opts.Coverage
+		if opts.JSON != nil {
+			m.GasMeter = storetypes.NewInfiniteGasMeter()
+		}
 		m.SetActivePackage(pv)
 
 		testingpv := m.Store.GetPackage("testing", false)
@@ -335,6 +378,10 @@ func (opts *TestOptions) runTestFiles(
 			},
 		))
 
+		if opts.JSON != nil {
+			opts.JSON.endTest(tf.Name, m)
+		}
+
 		if opts.Events {
 			events := m.Context.(*teststd.TestExecContext).EventLogger.Events()
 			if events != nil {
@@ -386,6 +433,46 @@ func (opts *TestOptions) runTestFiles(
 		}
 	}
 
+	fuzzTests := loadTestFuncs(memPkg.Name, files, "Fuzz")
+	fuzzName, err := fuzzTargetsToFuzz(opts.Fuzz, fuzzTests)
+	if err != nil {
+		errs = multierr.Append(errs, err)
+	}
+	for _, ft := range fuzzTests {
+		if !shouldRun(splitRegexp(opts.RunFlag), ft.Name) {
+			continue
+		}
+
+		m = Machine(gs, opts.Output, memPkg.Path)
+		m.Alloc = alloc.Reset()
+		m.Coverage = opts.Coverage
+		if opts.JSON != nil {
+			m.GasMeter = storetypes.NewInfiniteGasMeter()
+		}
+		m.SetActivePackage(pv)
+
+		if err := opts.runFuzz(m, ft, fsDir, ft.Name == fuzzName); err != nil {
+			errs = multierr.Append(errs, err)
+		}
+		if opts.JSON != nil {
+			opts.JSON.endTest(ft.Name, m)
+		}
+	}
+
+	if opts.Bench != "" {
+		for _, bf := range loadTestFuncs(memPkg.Name, files, "Benchmark") {
+			m = Machine(gs, opts.Output, memPkg.Path)
+			m.Alloc = gno.NewAllocator(math.MaxInt64)
+			m.GasMeter = storetypes.NewInfiniteGasMeter()
+			m.Coverage = opts.Coverage
+			m.SetActivePackage(pv)
+
+			if err := opts.runBenchmark(m, bf); err != nil {
+				errs = multierr.Append(errs, err)
+			}
+		}
+	}
+
 	return errs
 }
 
@@ -400,12 +487,12 @@ type testFunc struct {
 	Name    string
 }
 
-func loadTestFuncs(pkgName string, tfiles *gno.FileSet) (rt []testFunc) {
+func loadTestFuncs(pkgName string, tfiles *gno.FileSet, prefix string) (rt []testFunc) {
 	for _, tf := range tfiles.Files {
 		for _, d := range tf.Decls {
 			if fd, ok := d.(*gno.FuncDecl); ok {
 				fname := string(fd.Name)
-				if strings.HasPrefix(fname, "Test") {
+				if strings.HasPrefix(fname, prefix) {
 					tf := testFunc{
 						Package: pkgName,
 						Name:    fname,
@@ -19,6 +19,7 @@ type BankerInterface interface {
 	TotalCoin(denom string) int64
 	IssueCoin(addr crypto.Bech32Address, denom string, amount int64)
 	RemoveCoin(addr crypto.Bech32Address, denom string, amount int64)
+	SetDenomMetadata(denom, display string, exponent uint32, description, issuer string)
 }
 
 const (
@@ -76,3 +77,8 @@ func X_bankerIssueCoin(m *gno.Machine, bt uint8, addr string, denom string, amou
 func X_bankerRemoveCoin(m *gno.Machine, bt uint8, addr string, denom string, amount int64) {
 	GetContext(m).Banker.RemoveCoin(crypto.Bech32Address(addr), denom, amount)
 }
+
+func X_bankerSetDenomMetadata(m *gno.Machine, bt uint8, denom, display string, exponent uint32, description string) {
+	_, issuer := currentRealm(m)
+	GetContext(m).Banker.SetDenomMetadata(denom, display, exponent, description, issuer)
+}
@@ -19,6 +19,17 @@ type ParamsInterface interface {
 	SetInt64(key string, val int64)
 	SetUint64(key string, val uint64)
 	SetBytes(key string, val []byte)
+
+	// The getters return false if the param is not set, or is not of the
+	// type of ptr. The key is either
+	// decorated with a realm path, like "gno.land/r/foo.key.string", or
+	// with a module name, like "auth.key.int64", to get a field of the
+	// params of the module.
+	GetString(key string, ptr *string) bool
+	GetBool(key string, ptr *bool) bool
+	GetInt64(key string, ptr *int64) bool
+	GetUint64(key string, ptr *uint64) bool
+	GetBytes(key string, ptr *[]byte) bool
 }
 
 func X_setParamString(m *gno.Machine, key, val string) {
@@ -46,8 +57,69 @@ func X_setParamBytes(m *gno.Machine, key string, val []byte) {
 	GetContext(m).Params.SetBytes(pk, val)
 }
 
+func GetParamString(m *gno.Machine, prefix, key string) (string, bool) {
+	pk := prefixedPkey(m, prefix, key, "string")
+	var val string
+	found := GetContext(m).Params.GetString(pk, &val)
+	return val, found
+}
+
+func GetParamBool(m *gno.Machine, prefix, key string) (bool, bool) {
+	pk := prefixedPkey(m, prefix, key, "bool")
+	var val bool
+	found := GetContext(m).Params.GetBool(pk, &val)
+	return val, found
+}
+
+func GetParamInt64(m *gno.Machine, prefix, key string) (int64, bool) {
+	pk := prefixedPkey(m, prefix, key, "int64")
+	var val int64
+	found := GetContext(m).Params.GetInt64(pk, &val)
+	return val, found
+}
+
+func GetParamUint64(m *gno.Machine, prefix, key string) (uint64, bool) {
+	pk := prefixedPkey(m, prefix, key, "uint64")
+	var val uint64
+	found := GetContext(m).Params.GetUint64(pk, &val)
+	return val, found
+}
+
+func GetParamBytes(m *gno.Machine, prefix, key string) ([]byte, bool) {
+	pk := prefixedPkey(m, prefix, key, "bytes")
+	var val []byte
+	found := GetContext(m).Params.GetBytes(pk, &val)
+	return val, found
+}
+
 func pkey(m *gno.Machine, key string, kind string) string {
-	// validate key.
+	validateKey(m, key, kind, false)
+
+	// decorate key with realm and type.
+	_, rlmPath := currentRealm(m)
+	return fmt.Sprintf("%s.%s", rlmPath, key)
+}
+
+// prefixedPkey returns the key decorated with prefix, which is either the
+// path of a realm or the name of a module, or with the current realm if
+// prefix is empty.
+func prefixedPkey(m *gno.Machine, prefix, key string, kind string) string {
+	// the keys of other realms may be namespaced with dots, like
+	// "sys.users_pkgpath.string".
+	validateKey(m, key, kind, true)
+	if prefix == "" {
+		_, rlmPath := currentRealm(m)
+		return fmt.Sprintf("%s.%s", rlmPath, key)
+	}
+
+	// validate prefix.
+	if !strings.Contains(prefix, "/") && !isIdentifier(prefix) {
+		m.Panic(typedString("invalid param prefix: " + prefix))
+	}
+	return fmt.Sprintf("%s.%s", prefix, key)
+}
+
+func validateKey(m *gno.Machine, key string, kind string, dotted bool) {
 	untypedKey := strings.TrimSuffix(key, "."+kind)
 	if key == untypedKey {
 		m.Panic(typedString("invalid param key: " + key))
@@ -61,12 +133,18 @@ func pkey(m *gno.Machine, key string, kind string) string {
 		m.Panic(typedString("invalid param key: " + key))
 	}
 	for _, char := range untypedKey[1:] {
-		if !unicode.IsLetter(char) && !unicode.IsDigit(char) && char != '_' {
+		if !unicode.IsLetter(char) && !unicode.IsDigit(char) && char != '_' && (!dotted || char != '.') {
 			m.Panic(typedString("invalid param key: " + key))
 		}
 	}
+}
 
-	// decorate key with realm and type.
-	_, rlmPath := currentRealm(m)
-	return fmt.Sprintf("%s.%s", rlmPath, key)
+// isIdentifier returns true if s is a valid module name.
+func isIdentifier(s string) bool {
+	for i, char := range s {
+		if !unicode.IsLetter(char) && char != '_' && (i == 0 || !unicode.IsDigit(char)) {
+			return false
+		}
+	}
+	return s != ""
 }
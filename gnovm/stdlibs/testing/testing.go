@@ -4,3 +4,13 @@ func X_unixNano() int64 {
 	// only implemented in testing stdlibs
 	return 0
 }
+
+func X_gasConsumed() int64 {
+	// only implemented in testing stdlibs
+	return 0
+}
+
+func X_allocStats() (bytes, allocs int64) {
+	// only implemented in testing stdlibs
+	return 0, 0
+}
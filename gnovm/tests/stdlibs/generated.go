@@ -333,6 +333,52 @@ var nativeFuncs = [...]NativeFunc{
 			))
 		},
 	},
+	{
+		"testing",
+		"gasConsumed",
+		[]gno.FieldTypeExpr{},
+		[]gno.FieldTypeExpr{
+			{Name: gno.N("r0"), Type: gno.X("int64")},
+		},
+		true,
+		func(m *gno.Machine) {
+			r0 := testlibs_testing.X_gasConsumed(
+				m,
+			)
+
+			m.PushValue(gno.Go2GnoValue(
+				m.Alloc,
+				m.Store,
+				reflect.ValueOf(&r0).Elem(),
+			))
+		},
+	},
+	{
+		"testing",
+		"allocStats",
+		[]gno.FieldTypeExpr{},
+		[]gno.FieldTypeExpr{
+			{Name: gno.N("r0"), Type: gno.X("int64")},
+			{Name: gno.N("r1"), Type: gno.X("int64")},
+		},
+		true,
+		func(m *gno.Machine) {
+			r0, r1 := testlibs_testing.X_allocStats(
+				m,
+			)
+
+			m.PushValue(gno.Go2GnoValue(
+				m.Alloc,
+				m.Store,
+				reflect.ValueOf(&r0).Elem(),
+			))
+			m.PushValue(gno.Go2GnoValue(
+				m.Alloc,
+				m.Store,
+				reflect.ValueOf(&r1).Elem(),
+			))
+		},
+	},
 	{
 		"unicode",
 		"IsPrint",
@@ -233,6 +233,11 @@ func (tb *TestBanker) RemoveCoin(addr crypto.Bech32Address, denom string, amt in
 	tb.CoinTable[addr] = rest
 }
 
+// SetDenomMetadata implements the Banker interface.
+// Denom metadata is only used by clients, so it is not stored.
+func (tb *TestBanker) SetDenomMetadata(denom, display string, exponent uint32, description, issuer string) {
+}
+
 func X_testIssueCoins(m *gno.Machine, addr string, denom []string, amt []int64) {
 	ctx := m.Context.(*TestExecContext)
 	banker := ctx.Banker
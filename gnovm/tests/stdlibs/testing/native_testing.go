@@ -1,7 +1,26 @@
 package testing
 
-import "time"
+import (
+	"time"
+
+	gno "github.com/gnolang/gno/gnovm/pkg/gnolang"
+)
 
 func X_unixNano() int64 {
 	return time.Now().UnixNano()
 }
+
+func X_gasConsumed(m *gno.Machine) int64 {
+	if m.GasMeter == nil {
+		return 0
+	}
+	return m.GasMeter.GasConsumed()
+}
+
+func X_allocStats(m *gno.Machine) (bytes, allocs int64) {
+	if m.Alloc == nil {
+		return 0, 0
+	}
+	_, bytes = m.Alloc.Status()
+	return bytes, m.Alloc.Allocations()
+}
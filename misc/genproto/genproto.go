@@ -18,14 +18,18 @@ import (
 	"github.com/gnolang/gno/tm2/pkg/bft/consensus"
 	ctypes "github.com/gnolang/gno/tm2/pkg/bft/consensus/types"
 	"github.com/gnolang/gno/tm2/pkg/bft/mempool"
+	"github.com/gnolang/gno/tm2/pkg/bft/statesync"
 	btypes "github.com/gnolang/gno/tm2/pkg/bft/types"
 	"github.com/gnolang/gno/tm2/pkg/bitarray"
 	"github.com/gnolang/gno/tm2/pkg/crypto/ed25519"
 	"github.com/gnolang/gno/tm2/pkg/crypto/hd"
 	"github.com/gnolang/gno/tm2/pkg/crypto/merkle"
 	"github.com/gnolang/gno/tm2/pkg/crypto/multisig"
+	"github.com/gnolang/gno/tm2/pkg/p2p/pex"
 	"github.com/gnolang/gno/tm2/pkg/sdk"
+	"github.com/gnolang/gno/tm2/pkg/sdk/auth"
 	"github.com/gnolang/gno/tm2/pkg/sdk/bank"
+	"github.com/gnolang/gno/tm2/pkg/sdk/validators"
 	"github.com/gnolang/gno/tm2/pkg/std"
 )
 
@@ -52,12 +56,16 @@ func execGen(_ context.Context, _ []string) error {
 		mempool.Package,
 		ed25519.Package,
 		blockchain.Package,
+		statesync.Package,
+		pex.Package,
 		hd.Package,
 		multisig.Package,
 		std.Package,
 		gnovm.Package,
 		sdk.Package,
+		auth.Package,
 		bank.Package,
+		validators.Package,
 		vm.Package,
 		gno.Package,
 		tests.Package,
@@ -69,6 +69,16 @@ type CheckTxType int
 const (
 	CheckTxTypeNew     CheckTxType = 0
 	CheckTxTypeRecheck             = iota
+	// CheckTxTypeReplace checks a new tx, which may replace the tx of the
+	// mempool with the same sender and sequence. The app must reject it if
+	// its priority is not higher than that of the replaced tx, and else
+	// undo the state changes of the replaced tx.
+	CheckTxTypeReplace
+	// CheckTxTypeReset discards the state changes of the txs checked since
+	// the last commit, before rechecking the tx, if any, like
+	// CheckTxTypeRecheck. It is used by the mempool when the state of the
+	// app includes txs which are not in the mempool.
+	CheckTxTypeReset
 )
 
 type RequestCheckTx struct {
@@ -91,6 +101,36 @@ type RequestCommit struct {
 	RequestBase
 }
 
+type RequestListSnapshots struct {
+	RequestBase
+}
+
+// Offers a snapshot to the application, for it to be restored.
+// AppHash is the trusted app hash at the snapshot height, and Header
+// the trusted header of the block at the snapshot height.
+type RequestOfferSnapshot struct {
+	RequestBase
+	Snapshot *Snapshot
+	AppHash  []byte
+	Header   Header
+}
+
+type RequestLoadSnapshotChunk struct {
+	RequestBase
+	Height int64
+	Format uint32
+	Chunk  uint32
+}
+
+// Applies a chunk of the offered snapshot.
+// Sender is the ID of the peer that provided the chunk.
+type RequestApplySnapshotChunk struct {
+	RequestBase
+	Index  uint32
+	Chunk  []byte
+	Sender string
+}
+
 // ----------------------------------------
 // Response types
 
@@ -178,6 +218,11 @@ type ResponseCheckTx struct {
 	ResponseBase
 	GasWanted int64 // nondeterministic
 	GasUsed   int64
+
+	// Optional, used by the mempool to order the txs.
+	Priority int64  // the higher, the sooner the tx is included
+	Sender   string // txs with the same sender are ordered by sequence
+	Sequence uint64
 }
 
 type ResponseDeliverTx struct {
@@ -197,6 +242,50 @@ type ResponseCommit struct {
 	ResponseBase
 }
 
+type ResponseListSnapshots struct {
+	ResponseBase
+	Snapshots []*Snapshot
+}
+
+type ResponseOfferSnapshot struct {
+	ResponseBase
+	Result OfferSnapshotResult
+}
+
+type ResponseLoadSnapshotChunk struct {
+	ResponseBase
+	Chunk []byte
+}
+
+type ResponseApplySnapshotChunk struct {
+	ResponseBase
+	Result        ApplySnapshotChunkResult
+	RefetchChunks []uint32 // chunks to refetch and reapply
+	RejectSenders []string // peers to stop fetching chunks from
+}
+
+type OfferSnapshotResult int
+
+const (
+	OfferSnapshotUnknown      OfferSnapshotResult = iota
+	OfferSnapshotAccept                           // snapshot accepted, chunks can be applied
+	OfferSnapshotAbort                            // abort state sync altogether
+	OfferSnapshotReject                           // reject this snapshot, try others
+	OfferSnapshotRejectFormat                     // reject all snapshots of this format
+	OfferSnapshotRejectSender                     // reject all snapshots from the sender(s)
+)
+
+type ApplySnapshotChunkResult int
+
+const (
+	ApplySnapshotChunkUnknown        ApplySnapshotChunkResult = iota
+	ApplySnapshotChunkAccept                                  // chunk applied
+	ApplySnapshotChunkAbort                                   // abort state sync altogether
+	ApplySnapshotChunkRetry                                   // refetch and reapply the chunk
+	ApplySnapshotChunkRetrySnapshot                           // restart the snapshot restoration
+	ApplySnapshotChunkRejectSnapshot                          // reject the snapshot, try others
+)
+
 // ----------------------------------------
 // Interface types
 
@@ -255,6 +344,16 @@ type BlockParams struct {
 	TimeIotaMS    int64 // must be > 0
 }
 
+// Snapshot is a point-in-time copy of the application state,
+// that is split into chunks and used to bootstrap new nodes.
+type Snapshot struct {
+	Height   int64  // height at which the snapshot was taken
+	Format   uint32 // application-specific snapshot format
+	Chunks   uint32 // number of chunks
+	Hash     []byte // arbitrary snapshot hash, equal only for identical snapshots
+	Metadata []byte // arbitrary application metadata
+}
+
 type ValidatorParams struct {
 	PubKeyTypeURLs []string
 }
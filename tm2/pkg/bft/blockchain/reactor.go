@@ -119,6 +119,33 @@ func (bcR *BlockchainReactor) OnStart() error {
 	return nil
 }
 
+// SwitchToFastSync is called by the state sync reactor when it has restored
+// the given state, to fast sync the blocks that follow it.
+func (bcR *BlockchainReactor) SwitchToFastSync(state sm.State) error {
+	if bcR.fastSync {
+		return errors.New("already fast syncing")
+	}
+
+	if state.LastBlockHeight != bcR.store.Height() {
+		return fmt.Errorf("state (%v) and store (%v) height mismatch", state.LastBlockHeight,
+			bcR.store.Height())
+	}
+
+	bcR.fastSync = true
+	bcR.initialState = state
+	bcR.pool.mtx.Lock()
+	bcR.pool.height = state.LastBlockHeight + 1
+	bcR.pool.mtx.Unlock()
+
+	if err := bcR.pool.Start(); err != nil {
+		return err
+	}
+
+	go bcR.poolRoutine()
+
+	return nil
+}
+
 // OnStop implements cmn.Service.
 func (bcR *BlockchainReactor) OnStop() {
 	bcR.pool.Stop()
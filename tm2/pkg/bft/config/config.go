@@ -13,6 +13,7 @@ import (
 	mem "github.com/gnolang/gno/tm2/pkg/bft/mempool/config"
 	rpc "github.com/gnolang/gno/tm2/pkg/bft/rpc/config"
 	eventstore "github.com/gnolang/gno/tm2/pkg/bft/state/eventstore/types"
+	sts "github.com/gnolang/gno/tm2/pkg/bft/statesync/config"
 	"github.com/gnolang/gno/tm2/pkg/db"
 	"github.com/gnolang/gno/tm2/pkg/errors"
 	osm "github.com/gnolang/gno/tm2/pkg/os"
@@ -52,6 +53,7 @@ type Config struct {
 	P2P          *p2p.P2PConfig       `json:"p2p" toml:"p2p" comment:"##### peer to peer configuration options #####"`
 	Mempool      *mem.MempoolConfig   `json:"mempool" toml:"mempool" comment:"##### mempool configuration options #####"`
 	Consensus    *cns.ConsensusConfig `json:"consensus" toml:"consensus" comment:"##### consensus configuration options #####"`
+	StateSync    *sts.StateSyncConfig `json:"state_sync" toml:"state_sync" comment:"##### state sync configuration options #####"`
 	TxEventStore *eventstore.Config   `json:"tx_event_store" toml:"tx_event_store" comment:"##### event store #####"`
 	Telemetry    *telemetry.Config    `json:"telemetry" toml:"telemetry" comment:"##### node telemetry #####"`
 }
@@ -64,6 +66,7 @@ func DefaultConfig() *Config {
 		P2P:          p2p.DefaultP2PConfig(),
 		Mempool:      mem.DefaultMempoolConfig(),
 		Consensus:    cns.DefaultConsensusConfig(),
+		StateSync:    sts.DefaultStateSyncConfig(),
 		TxEventStore: eventstore.DefaultEventStoreConfig(),
 		Telemetry:    telemetry.DefaultTelemetryConfig(),
 	}
@@ -171,6 +174,7 @@ func TestConfig() *Config {
 		P2P:          p2p.TestP2PConfig(),
 		Mempool:      mem.TestMempoolConfig(),
 		Consensus:    cns.TestConsensusConfig(),
+		StateSync:    sts.TestStateSyncConfig(),
 		TxEventStore: eventstore.DefaultEventStoreConfig(),
 		Telemetry:    telemetry.DefaultTelemetryConfig(),
 	}
@@ -228,6 +232,9 @@ func (cfg *Config) ValidateBasic() error {
 	if err := cfg.Consensus.ValidateBasic(); err != nil {
 		return errors.Wrap(err, "Error in [consensus] section")
 	}
+	if err := cfg.StateSync.ValidateBasic(); err != nil {
+		return errors.Wrap(err, "Error in [state_sync] section")
+	}
 	return nil
 }
 
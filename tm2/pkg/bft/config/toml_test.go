@@ -159,6 +159,7 @@ func TestTOML_LoadConfig(t *testing.T) {
 		assert.EqualValues(t, defaultConfig.P2P, cfg.P2P)
 		assert.EqualValues(t, defaultConfig.Mempool, cfg.Mempool)
 		assert.EqualValues(t, defaultConfig.Consensus, cfg.Consensus)
+		assert.EqualValues(t, defaultConfig.StateSync, cfg.StateSync)
 		assert.Equal(t, defaultConfig.TxEventStore.EventStoreType, cfg.TxEventStore.EventStoreType)
 		assert.Empty(t, defaultConfig.TxEventStore.Params, cfg.TxEventStore.Params)
 	})
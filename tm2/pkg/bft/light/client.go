@@ -0,0 +1,397 @@
+// Package light implements a light client, that verifies block headers
+// without executing the blocks, starting from a trusted header.
+// Headers are verified sequentially, or by skipping over the intermediate
+// headers (bisection) when enough of the trusted validators signed them
+package light
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// defaultPruningSize is the default maximum number of stored light blocks
+const defaultPruningSize = 1000
+
+var (
+	errTrustHashMatch  = errors.New("header hash does not match the trusted hash")
+	errWitnessConflict = errors.New("witness reported a conflicting header")
+	errHashLinkMatch   = errors.New("header hash does not match the next header's last block ID")
+	errInvalidPruning  = errors.New("pruning size must be positive")
+)
+
+// Option is a light client option
+type Option func(*Client)
+
+// SequentialVerification makes the client verify every header,
+// between the trusted header and the target header
+func SequentialVerification() Option {
+	return func(c *Client) {
+		c.sequential = true
+	}
+}
+
+// SkippingVerification makes the client skip over the intermediate headers,
+// as long as the given trust level of the trusted validators signed the
+// target header. This is the default, with DefaultTrustLevel
+func SkippingVerification(trustLevel TrustLevel) Option {
+	return func(c *Client) {
+		c.sequential = false
+		c.trustLevel = trustLevel
+	}
+}
+
+// PruningSize sets the maximum number of light blocks kept in the store
+func PruningSize(size int) Option {
+	return func(c *Client) {
+		c.pruningSize = size
+	}
+}
+
+// Client is a light client, that verifies the light blocks fetched from
+// the primary provider, and cross-checks them against the witnesses
+type Client struct {
+	chainID        string
+	trustingPeriod time.Duration
+	trustLevel     TrustLevel
+	sequential     bool
+	pruningSize    int
+
+	primary   Provider
+	witnesses []Provider
+	store     Store
+
+	mtx sync.Mutex // guards verification and the store
+}
+
+// NewClient creates a new light client, anchored at the trusted header
+// of the trust options. If the store already contains the trusted header,
+// it is not fetched again
+func NewClient(
+	chainID string,
+	trustOptions TrustOptions,
+	primary Provider,
+	witnesses []Provider,
+	store Store,
+	opts ...Option,
+) (*Client, error) {
+	if err := trustOptions.ValidateBasic(); err != nil {
+		return nil, fmt.Errorf("invalid trust options, %w", err)
+	}
+
+	c := &Client{
+		chainID:        chainID,
+		trustingPeriod: trustOptions.Period,
+		trustLevel:     DefaultTrustLevel,
+		pruningSize:    defaultPruningSize,
+		primary:        primary,
+		witnesses:      witnesses,
+		store:          store,
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	if err := c.trustLevel.ValidateBasic(); err != nil {
+		return nil, err
+	}
+
+	if c.pruningSize <= 0 {
+		return nil, errInvalidPruning
+	}
+
+	if err := c.initializeTrust(trustOptions); err != nil {
+		return nil, fmt.Errorf("unable to initialize trust, %w", err)
+	}
+
+	return c, nil
+}
+
+// initializeTrust fetches and saves the trusted header,
+// unless it is already in the store
+func (c *Client) initializeTrust(trustOptions TrustOptions) error {
+	lb, err := c.store.LightBlock(trustOptions.Height)
+
+	switch {
+	case err == nil:
+		if !bytes.Equal(lb.Hash(), trustOptions.Hash) {
+			return fmt.Errorf("%w, %X != %X", errTrustHashMatch, lb.Hash(), trustOptions.Hash)
+		}
+
+		return nil
+	case !errors.Is(err, ErrLightBlockNotFound):
+		return err
+	}
+
+	lb, err = c.fetchLightBlock(trustOptions.Height)
+	if err != nil {
+		return err
+	}
+
+	if !bytes.Equal(lb.Hash(), trustOptions.Hash) {
+		return fmt.Errorf("%w, %X != %X", errTrustHashMatch, lb.Hash(), trustOptions.Hash)
+	}
+
+	// The trusted header must still be signed by its validators
+	if err := verifyCommit(c.chainID, lb); err != nil {
+		return err
+	}
+
+	if err := c.crossCheck(lb); err != nil {
+		return err
+	}
+
+	return c.saveLightBlocks(lb)
+}
+
+// ChainID returns the chain ID of the light client
+func (c *Client) ChainID() string {
+	return c.chainID
+}
+
+// TrustedLightBlock returns the trusted light block at the given height,
+// or the latest trusted light block if the height is 0.
+// It does not fetch nor verify any new light block
+func (c *Client) TrustedLightBlock(height int64) (*LightBlock, error) {
+	if height == 0 {
+		height = c.store.LastLightBlockHeight()
+	}
+
+	return c.store.LightBlock(height)
+}
+
+// LastTrustedHeight returns the height of the latest trusted
+// light block, or -1 if there are none
+func (c *Client) LastTrustedHeight() int64 {
+	return c.store.LastLightBlockHeight()
+}
+
+// Update verifies the latest light block of the primary provider,
+// and returns the latest trusted light block
+func (c *Client) Update(now time.Time) (*LightBlock, error) {
+	latest, err := c.fetchLightBlock(0)
+	if err != nil {
+		return nil, err
+	}
+
+	if latest.Height <= c.store.LastLightBlockHeight() {
+		return c.TrustedLightBlock(0)
+	}
+
+	return c.verifyLightBlock(latest, now)
+}
+
+// VerifyLightBlockAtHeight fetches and verifies the light block
+// at the given height, unless it is already trusted
+func (c *Client) VerifyLightBlockAtHeight(height int64, now time.Time) (*LightBlock, error) {
+	if height <= 0 {
+		return nil, fmt.Errorf("invalid height %d", height)
+	}
+
+	if lb, err := c.store.LightBlock(height); err == nil {
+		return lb, nil
+	}
+
+	untrusted, err := c.fetchLightBlock(height)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.verifyLightBlock(untrusted, now)
+}
+
+// verifyLightBlock verifies the given untrusted light block, from the closest
+// trusted light block below it, or backwards from the lowest trusted
+// light block above it
+func (c *Client) verifyLightBlock(untrusted *LightBlock, now time.Time) (*LightBlock, error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	// The light block could have been verified concurrently
+	if lb, err := c.store.LightBlock(untrusted.Height); err == nil {
+		return lb, nil
+	}
+
+	var (
+		verified []*LightBlock
+		err      error
+	)
+
+	trusted, err := c.store.LightBlockBefore(untrusted.Height)
+
+	switch {
+	case err == nil:
+		if c.sequential {
+			verified, err = c.verifySequential(trusted, untrusted, now)
+		} else {
+			verified, err = c.verifySkipping(trusted, untrusted, now)
+		}
+	case errors.Is(err, ErrLightBlockNotFound):
+		verified, err = c.verifyBackwards(untrusted, now)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.crossCheck(untrusted); err != nil {
+		return nil, err
+	}
+
+	if err := c.saveLightBlocks(verified...); err != nil {
+		return nil, err
+	}
+
+	return untrusted, nil
+}
+
+// verifySequential verifies every light block from the trusted light block
+// up to the untrusted one, and returns the verified light blocks
+func (c *Client) verifySequential(trusted, untrusted *LightBlock, now time.Time) ([]*LightBlock, error) {
+	verified := make([]*LightBlock, 0, untrusted.Height-trusted.Height)
+
+	for height := trusted.Height + 1; height <= untrusted.Height; height++ {
+		lb := untrusted
+
+		if height != untrusted.Height {
+			var err error
+
+			if lb, err = c.fetchLightBlock(height); err != nil {
+				return nil, err
+			}
+		}
+
+		if err := VerifyAdjacent(c.chainID, trusted, lb, c.trustingPeriod, now); err != nil {
+			return nil, err
+		}
+
+		verified = append(verified, lb)
+		trusted = lb
+	}
+
+	return verified, nil
+}
+
+// verifySkipping verifies the untrusted light block by skipping from the
+// trusted light block. When the trusted validators did not sign enough of a
+// header, a header halfway is verified first (bisection).
+// It returns the verified light blocks
+func (c *Client) verifySkipping(trusted, untrusted *LightBlock, now time.Time) ([]*LightBlock, error) {
+	var (
+		verified []*LightBlock
+		pending  = []*LightBlock{untrusted}
+	)
+
+	for len(pending) > 0 {
+		lb := pending[len(pending)-1]
+
+		err := Verify(c.chainID, trusted, lb, c.trustingPeriod, now, c.trustLevel)
+
+		switch {
+		case err == nil:
+			verified = append(verified, lb)
+			pending = pending[:len(pending)-1]
+			trusted = lb
+		case errors.Is(err, ErrNotEnoughTrust):
+			pivot, err := c.fetchLightBlock(trusted.Height + (lb.Height-trusted.Height)/2)
+			if err != nil {
+				return nil, err
+			}
+
+			pending = append(pending, pivot)
+		default:
+			return nil, err
+		}
+	}
+
+	return verified, nil
+}
+
+// verifyBackwards verifies the untrusted light block, below every trusted
+// light block, by following the hash links down from the lowest trusted
+// light block above it. It returns the verified light block
+func (c *Client) verifyBackwards(untrusted *LightBlock, now time.Time) ([]*LightBlock, error) {
+	trusted, err := c.store.LightBlockAfter(untrusted.Height)
+	if err != nil {
+		return nil, err
+	}
+
+	if HeaderExpired(trusted.SignedHeader, c.trustingPeriod, now) {
+		return nil, fmt.Errorf("%w, height %d, time %s", ErrHeaderExpired, trusted.Height, trusted.Time)
+	}
+
+	for height := trusted.Height - 1; height >= untrusted.Height; height-- {
+		lb := untrusted
+
+		if height != untrusted.Height {
+			if lb, err = c.fetchLightBlock(height); err != nil {
+				return nil, err
+			}
+		}
+
+		if err := lb.ValidateBasic(c.chainID); err != nil {
+			return nil, fmt.Errorf("invalid header at height %d, %w", height, err)
+		}
+
+		if !bytes.Equal(lb.Hash(), trusted.LastBlockID.Hash) {
+			return nil, fmt.Errorf("%w, height %d", errHashLinkMatch, height)
+		}
+
+		trusted = lb
+	}
+
+	return []*LightBlock{untrusted}, nil
+}
+
+// fetchLightBlock fetches the light block at the given height from the
+// primary provider, or the latest light block if the height is 0
+func (c *Client) fetchLightBlock(height int64) (*LightBlock, error) {
+	lb, err := c.primary.LightBlock(height)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := lb.ValidateBasic(c.chainID); err != nil {
+		return nil, fmt.Errorf("invalid header at height %d, %w", height, err)
+	}
+
+	return lb, nil
+}
+
+// crossCheck verifies the witnesses agree with
+// the primary provider on the given light block
+func (c *Client) crossCheck(lb *LightBlock) error {
+	for i, witness := range c.witnesses {
+		wlb, err := witness.LightBlock(lb.Height)
+		if err != nil {
+			return fmt.Errorf("unable to fetch header from witness %d at height %d, %w", i, lb.Height, err)
+		}
+
+		if wlb.SignedHeader == nil || !bytes.Equal(wlb.Hash(), lb.Hash()) {
+			return fmt.Errorf("%w, witness %d at height %d", errWitnessConflict, i, lb.Height)
+		}
+	}
+
+	return nil
+}
+
+// saveLightBlocks saves the given verified light blocks,
+// and prunes the oldest light blocks beyond the pruning size
+func (c *Client) saveLightBlocks(lbs ...*LightBlock) error {
+	for _, lb := range lbs {
+		if err := c.store.SaveLightBlock(lb); err != nil {
+			return fmt.Errorf("unable to save light block at height %d, %w", lb.Height, err)
+		}
+	}
+
+	for c.store.Size() > c.pruningSize {
+		if err := c.store.DeleteLightBlock(c.store.FirstLightBlockHeight()); err != nil {
+			return fmt.Errorf("unable to prune light blocks, %w", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,253 @@
+package light
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/gno/tm2/pkg/db/memdb"
+)
+
+// newTestClient creates a new light client, anchored at the given height
+// of the chain, with the chain as its primary and witness
+func newTestClient(t *testing.T, chain *mockChain, height int64, opts ...Option) *Client {
+	t.Helper()
+
+	c, err := NewClient(
+		testChainID,
+		chain.trustOptions(height),
+		newRPCProvider(chain),
+		[]Provider{chain},
+		NewDBStore(memdb.NewMemDB()),
+		opts...,
+	)
+	require.NoError(t, err)
+
+	return c
+}
+
+func TestClient_New(t *testing.T) {
+	t.Parallel()
+
+	chain := newMockChain(t, 4, 0)
+
+	t.Run("valid trust options", func(t *testing.T) {
+		t.Parallel()
+
+		c := newTestClient(t, chain, 2)
+
+		assert.Equal(t, testChainID, c.ChainID())
+		assert.Equal(t, int64(2), c.LastTrustedHeight())
+
+		lb, err := c.TrustedLightBlock(0)
+		require.NoError(t, err)
+		assert.Equal(t, chain.blocks[2].Hash(), lb.Hash())
+	})
+
+	t.Run("trust hash mismatch", func(t *testing.T) {
+		t.Parallel()
+
+		opts := chain.trustOptions(2)
+		opts.Hash = chain.blocks[3].Hash()
+
+		_, err := NewClient(testChainID, opts, chain, nil, NewDBStore(memdb.NewMemDB()))
+		assert.ErrorIs(t, err, errTrustHashMatch)
+	})
+
+	t.Run("invalid trust options", func(t *testing.T) {
+		t.Parallel()
+
+		opts := chain.trustOptions(2)
+		opts.Period = 0
+
+		_, err := NewClient(testChainID, opts, chain, nil, NewDBStore(memdb.NewMemDB()))
+		assert.ErrorIs(t, err, errInvalidPeriod)
+	})
+
+	t.Run("witness conflict", func(t *testing.T) {
+		t.Parallel()
+
+		fork := newMockChain(t, 4, 0)
+
+		_, err := NewClient(testChainID, chain.trustOptions(2), chain, []Provider{fork}, NewDBStore(memdb.NewMemDB()))
+		assert.ErrorIs(t, err, errWitnessConflict)
+	})
+
+	t.Run("existing store", func(t *testing.T) {
+		t.Parallel()
+
+		store := NewDBStore(memdb.NewMemDB())
+		require.NoError(t, store.SaveLightBlock(chain.blocks[2]))
+
+		// The trusted header is not fetched again
+		c, err := NewClient(testChainID, chain.trustOptions(2), &mockChain{}, nil, store)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), c.LastTrustedHeight())
+
+		// The stored header must match the trust options
+		_, err = NewClient(testChainID, chain.trustOptions(3), &mockChain{}, nil, store)
+		assert.Error(t, err)
+	})
+}
+
+func TestClient_VerifyLightBlockAtHeight(t *testing.T) {
+	t.Parallel()
+
+	var (
+		chain = newMockChain(t, 20, 3)
+		now   = time.Now()
+	)
+
+	t.Run("sequential verification", func(t *testing.T) {
+		t.Parallel()
+
+		c := newTestClient(t, chain, 1, SequentialVerification())
+
+		lb, err := c.VerifyLightBlockAtHeight(10, now)
+		require.NoError(t, err)
+		assert.Equal(t, chain.blocks[10].Hash(), lb.Hash())
+
+		// Every intermediate header is trusted
+		for h := int64(1); h <= 10; h++ {
+			_, err := c.TrustedLightBlock(h)
+			assert.NoError(t, err)
+		}
+	})
+
+	t.Run("skipping verification", func(t *testing.T) {
+		t.Parallel()
+
+		c := newTestClient(t, chain, 1)
+
+		lb, err := c.VerifyLightBlockAtHeight(20, now)
+		require.NoError(t, err)
+		assert.Equal(t, chain.blocks[20].Hash(), lb.Hash())
+
+		// The validator set changes every 3 blocks,
+		// so some intermediate headers are skipped
+		store := c.store
+		assert.Less(t, store.Size(), 20)
+		assert.Greater(t, store.Size(), 2)
+	})
+
+	t.Run("skipping verification with the same validators", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			chain = newMockChain(t, 20, 0)
+			c     = newTestClient(t, chain, 1)
+		)
+
+		_, err := c.VerifyLightBlockAtHeight(20, now)
+		require.NoError(t, err)
+
+		// Only the target header is verified
+		assert.Equal(t, 2, c.store.Size())
+	})
+
+	t.Run("backwards verification", func(t *testing.T) {
+		t.Parallel()
+
+		c := newTestClient(t, chain, 10)
+
+		lb, err := c.VerifyLightBlockAtHeight(4, now)
+		require.NoError(t, err)
+		assert.Equal(t, chain.blocks[4].Hash(), lb.Hash())
+		assert.Equal(t, int64(4), c.store.FirstLightBlockHeight())
+	})
+
+	t.Run("backwards hash link mismatch", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			fork = newMockChain(t, 20, 3)
+			c    = newTestClient(t, chain, 10)
+		)
+
+		c.primary = fork
+		c.witnesses = nil
+
+		_, err := c.VerifyLightBlockAtHeight(9, now)
+		assert.ErrorIs(t, err, errHashLinkMatch)
+	})
+
+	t.Run("forked primary", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			fork = newMockChain(t, 20, 3)
+			c    = newTestClient(t, chain, 1)
+		)
+
+		// The fork is not signed by the trusted validators
+		c.primary = fork
+		c.witnesses = nil
+
+		_, err := c.VerifyLightBlockAtHeight(2, now)
+		assert.Error(t, err)
+		assert.Equal(t, 1, c.store.Size())
+	})
+
+	t.Run("witness conflict", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			fork = newMockChain(t, 20, 3)
+			c    = newTestClient(t, chain, 1)
+		)
+
+		c.witnesses = []Provider{fork}
+
+		_, err := c.VerifyLightBlockAtHeight(2, now)
+		assert.ErrorIs(t, err, errWitnessConflict)
+		assert.Equal(t, 1, c.store.Size())
+	})
+
+	t.Run("expired trust", func(t *testing.T) {
+		t.Parallel()
+
+		c := newTestClient(t, chain, 1)
+
+		_, err := c.VerifyLightBlockAtHeight(10, now.Add(testTrustingPeriod))
+		assert.ErrorIs(t, err, ErrHeaderExpired)
+	})
+}
+
+func TestClient_Update(t *testing.T) {
+	t.Parallel()
+
+	var (
+		chain = newMockChain(t, 10, 4)
+		c     = newTestClient(t, chain, 2)
+		now   = time.Now()
+	)
+
+	lb, err := c.Update(now)
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(10), lb.Height)
+	assert.Equal(t, int64(10), c.LastTrustedHeight())
+
+	// Already up to date
+	lb, err = c.Update(now)
+	require.NoError(t, err)
+	assert.Equal(t, int64(10), lb.Height)
+}
+
+func TestClient_Pruning(t *testing.T) {
+	t.Parallel()
+
+	var (
+		chain = newMockChain(t, 10, 0)
+		c     = newTestClient(t, chain, 1, SequentialVerification(), PruningSize(3))
+	)
+
+	_, err := c.VerifyLightBlockAtHeight(10, time.Now())
+	require.NoError(t, err)
+
+	assert.Equal(t, 3, c.store.Size())
+	assert.Equal(t, int64(8), c.store.FirstLightBlockHeight())
+	assert.Equal(t, int64(10), c.LastTrustedHeight())
+}
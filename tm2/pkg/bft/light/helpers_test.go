@@ -0,0 +1,155 @@
+package light
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	ctypes "github.com/gnolang/gno/tm2/pkg/bft/rpc/core/types"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+)
+
+const (
+	testChainID        = "light-test"
+	testTrustingPeriod = 24 * time.Hour
+)
+
+// testBlockTime is the time of the first mock chain block
+var testBlockTime = time.Now().Add(-time.Hour).UTC().Truncate(time.Second)
+
+// mockChain is a mock chain, serving light blocks directly,
+// and as a mock rpc client
+type mockChain struct {
+	blocks map[int64]*LightBlock
+	height int64
+}
+
+// newMockChain creates a new mock chain with the given number of blocks.
+// The validator set is entirely replaced every changeEvery blocks,
+// or never if changeEvery is 0
+func newMockChain(t *testing.T, height, changeEvery int64) *mockChain {
+	t.Helper()
+
+	var (
+		c = &mockChain{
+			blocks: make(map[int64]*LightBlock),
+			height: height,
+		}
+
+		valSets  = make(map[int64]*types.ValidatorSet)
+		privVals = make(map[int64][]types.PrivValidator)
+	)
+
+	// setIndex returns the index of the validator set at the given height
+	setIndex := func(h int64) int64 {
+		if changeEvery == 0 {
+			return 0
+		}
+
+		return (h - 1) / changeEvery
+	}
+
+	validators := func(h int64) (*types.ValidatorSet, []types.PrivValidator) {
+		idx := setIndex(h)
+
+		if _, ok := valSets[idx]; !ok {
+			vals, pvs := types.RandValidatorSet(4, 10)
+			valSets[idx], privVals[idx] = vals, pvs
+		}
+
+		return valSets[idx].Copy(), privVals[idx]
+	}
+
+	var lastBlockID types.BlockID
+
+	for h := int64(1); h <= height; h++ {
+		var (
+			vals, pvs = validators(h)
+			next, _   = validators(h + 1)
+		)
+
+		header := &types.Header{
+			ChainID:            testChainID,
+			Height:             h,
+			Time:               testBlockTime.Add(time.Duration(h) * time.Second),
+			LastBlockID:        lastBlockID,
+			ValidatorsHash:     vals.Hash(),
+			NextValidatorsHash: next.Hash(),
+			AppHash:            []byte(fmt.Sprintf("app-hash-%d", h)),
+		}
+
+		blockID := types.BlockID{
+			Hash: header.Hash(),
+			PartsHeader: types.PartSetHeader{
+				Total: 1,
+				Hash:  []byte("parts"),
+			},
+		}
+
+		voteSet := types.NewVoteSet(testChainID, h, 0, types.PrecommitType, vals)
+
+		commit, err := types.MakeCommit(blockID, h, 0, voteSet, pvs)
+		require.NoError(t, err)
+
+		c.blocks[h] = &LightBlock{
+			SignedHeader: &types.SignedHeader{Header: header, Commit: commit},
+			ValidatorSet: vals,
+		}
+
+		lastBlockID = blockID
+	}
+
+	return c
+}
+
+// LightBlock implements Provider
+func (c *mockChain) LightBlock(height int64) (*LightBlock, error) {
+	if height == 0 {
+		height = c.height
+	}
+
+	lb, ok := c.blocks[height]
+	if !ok {
+		return nil, fmt.Errorf("no light block at height %d", height)
+	}
+
+	return lb, nil
+}
+
+func (c *mockChain) Commit(height *int64) (*ctypes.ResultCommit, error) {
+	h := c.height
+	if height != nil {
+		h = *height
+	}
+
+	lb, ok := c.blocks[h]
+	if !ok {
+		return nil, fmt.Errorf("no header at height %d", h)
+	}
+
+	return &ctypes.ResultCommit{SignedHeader: *lb.SignedHeader}, nil
+}
+
+func (c *mockChain) Validators(height *int64) (*ctypes.ResultValidators, error) {
+	lb, ok := c.blocks[*height]
+	if !ok {
+		return nil, fmt.Errorf("no validators at height %d", *height)
+	}
+
+	return &ctypes.ResultValidators{
+		BlockHeight: *height,
+		Validators:  lb.ValidatorSet.Copy().Validators,
+	}, nil
+}
+
+// trustOptions returns the trust options anchored
+// at the given height of the chain
+func (c *mockChain) trustOptions(height int64) TrustOptions {
+	return TrustOptions{
+		Period: testTrustingPeriod,
+		Height: height,
+		Hash:   c.blocks[height].Hash(),
+	}
+}
@@ -0,0 +1,56 @@
+package light
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	"github.com/gnolang/gno/tm2/pkg/crypto/merkle"
+	"github.com/gnolang/gno/tm2/pkg/store/rootmulti"
+)
+
+var (
+	errMissingProof = errors.New("query response is missing a proof")
+	errKeyMismatch  = errors.New("query response key does not match the queried key")
+)
+
+// VerifyStoreQuery verifies the response of a proven store query
+// (".store/<storeName>/key"), against the app hash of the state it was
+// queried at. The app hash of the state after the block at height H
+// is in the header at height H+1.
+// An empty response value is verified as the absence of the key
+func VerifyStoreQuery(res abci.ResponseQuery, storeName string, key, appHash []byte) error {
+	if res.Error != nil {
+		return fmt.Errorf("query failed, %w", res.Error)
+	}
+
+	if res.Proof == nil {
+		return errMissingProof
+	}
+
+	if !bytes.Equal(res.Key, key) {
+		return fmt.Errorf("%w, %X != %X", errKeyMismatch, res.Key, key)
+	}
+
+	keyPath := merkle.KeyPath{}.
+		AppendKey([]byte(storeName), merkle.KeyEncodingURL).
+		AppendKey(key, merkle.KeyEncodingHex).
+		String()
+
+	prt := rootmulti.DefaultProofRuntime()
+
+	if len(res.Value) == 0 {
+		if err := prt.VerifyAbsence(res.Proof, appHash, keyPath); err != nil {
+			return fmt.Errorf("unable to verify absence proof, %w", err)
+		}
+
+		return nil
+	}
+
+	if err := prt.VerifyValue(res.Proof, appHash, keyPath, res.Value); err != nil {
+		return fmt.Errorf("unable to verify value proof, %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,89 @@
+package light
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	"github.com/gnolang/gno/tm2/pkg/db/memdb"
+	"github.com/gnolang/gno/tm2/pkg/store"
+	"github.com/gnolang/gno/tm2/pkg/store/dbadapter"
+	"github.com/gnolang/gno/tm2/pkg/store/iavl"
+	"github.com/gnolang/gno/tm2/pkg/store/rootmulti"
+)
+
+func TestVerifyStoreQuery(t *testing.T) {
+	t.Parallel()
+
+	var (
+		key   = []byte{0x00, 'k', 'e', 'y'}
+		value = []byte("value")
+
+		mainKey = store.NewStoreKey("main")
+		baseKey = store.NewStoreKey("base")
+
+		ms = rootmulti.NewMultiStore(memdb.NewMemDB())
+	)
+
+	ms.MountStoreWithDB(mainKey, iavl.StoreConstructor, nil)
+	ms.MountStoreWithDB(baseKey, dbadapter.StoreConstructor, nil)
+	require.NoError(t, ms.LoadLatestVersion())
+
+	ms.GetStore(mainKey).Set(key, value)
+	ms.GetStore(mainKey).Set([]byte("other"), value)
+
+	appHash := ms.Commit().Hash
+
+	query := func(key []byte) abci.ResponseQuery {
+		return ms.Query(abci.RequestQuery{
+			Path:  "/main/key",
+			Data:  key,
+			Prove: true,
+		})
+	}
+
+	t.Run("value", func(t *testing.T) {
+		t.Parallel()
+
+		res := query(key)
+		require.Equal(t, value, res.Value)
+
+		assert.NoError(t, VerifyStoreQuery(res, "main", key, appHash))
+
+		// The proof is bound to the store, the key, the value and the app hash
+		assert.Error(t, VerifyStoreQuery(res, "base", key, appHash))
+		assert.ErrorIs(t, VerifyStoreQuery(res, "main", []byte("other"), appHash), errKeyMismatch)
+		assert.Error(t, VerifyStoreQuery(res, "main", key, []byte("app-hash")))
+
+		res.Value = []byte("tampered")
+		assert.Error(t, VerifyStoreQuery(res, "main", key, appHash))
+	})
+
+	t.Run("absence", func(t *testing.T) {
+		t.Parallel()
+
+		missing := []byte("missing")
+
+		res := query(missing)
+		require.Empty(t, res.Value)
+
+		assert.NoError(t, VerifyStoreQuery(res, "main", missing, appHash))
+
+		// A present key can't be proven absent
+		res = query(key)
+		res.Value = nil
+
+		assert.Error(t, VerifyStoreQuery(res, "main", key, appHash))
+	})
+
+	t.Run("missing proof", func(t *testing.T) {
+		t.Parallel()
+
+		res := query(key)
+		res.Proof = nil
+
+		assert.ErrorIs(t, VerifyStoreQuery(res, "main", key, appHash), errMissingProof)
+	})
+}
@@ -0,0 +1,87 @@
+package light
+
+import (
+	"fmt"
+
+	"github.com/gnolang/gno/tm2/pkg/bft/rpc/client"
+	ctypes "github.com/gnolang/gno/tm2/pkg/bft/rpc/core/types"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+)
+
+// Provider provides untrusted light blocks
+type Provider interface {
+	// LightBlock returns the light block at the given height,
+	// or the latest light block if the height is 0
+	LightBlock(height int64) (*LightBlock, error)
+}
+
+// rpcClient is the subset of the RPC client used to fetch light blocks
+type rpcClient interface {
+	Commit(height *int64) (*ctypes.ResultCommit, error)
+	Validators(height *int64) (*ctypes.ResultValidators, error)
+}
+
+// rpcProvider is a Provider that fetches
+// the light blocks from an RPC server
+type rpcProvider struct {
+	client rpcClient
+}
+
+// NewRPCProvider creates a new light block provider,
+// that fetches the light blocks using the given RPC client
+func NewRPCProvider(c client.SignClient) Provider {
+	return newRPCProvider(c)
+}
+
+// NewHTTPProvider creates a new light block provider,
+// that fetches the light blocks from the given RPC server
+func NewHTTPProvider(remote string) (Provider, error) {
+	c, err := client.NewHTTPClient(remote)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create rpc client for %q, %w", remote, err)
+	}
+
+	return newRPCProvider(c), nil
+}
+
+func newRPCProvider(c rpcClient) *rpcProvider {
+	return &rpcProvider{
+		client: c,
+	}
+}
+
+// LightBlock implements Provider
+func (p *rpcProvider) LightBlock(height int64) (*LightBlock, error) {
+	var h *int64
+	if height > 0 {
+		h = &height
+	}
+
+	res, err := p.client.Commit(h)
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch header at height %d, %w", height, err)
+	}
+
+	if res.Header == nil {
+		return nil, fmt.Errorf("missing header at height %d", height)
+	}
+
+	if height > 0 && res.Height != height {
+		return nil, fmt.Errorf("header height mismatch, %d != %d", res.Height, height)
+	}
+
+	valsHeight := res.Height
+
+	vals, err := p.client.Validators(&valsHeight)
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch validators at height %d, %w", valsHeight, err)
+	}
+
+	header := res.SignedHeader
+
+	return &LightBlock{
+		SignedHeader: &header,
+		// The validators are used as is, to preserve their order
+		ValidatorSet: &types.ValidatorSet{Validators: vals.Validators},
+	}, nil
+}
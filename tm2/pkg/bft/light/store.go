@@ -0,0 +1,247 @@
+package light
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	dbm "github.com/gnolang/gno/tm2/pkg/db"
+)
+
+// ErrLightBlockNotFound is returned when the store doesn't contain
+// a light block at the requested height
+var ErrLightBlockNotFound = errors.New("light block not found")
+
+var (
+	signedHeaderPrefix = []byte("sh/")
+	validatorSetPrefix = []byte("vs/")
+)
+
+// Store is the store of trusted light blocks
+type Store interface {
+	// SaveLightBlock saves the given trusted light block
+	SaveLightBlock(lb *LightBlock) error
+
+	// DeleteLightBlock deletes the light block at the given height
+	DeleteLightBlock(height int64) error
+
+	// LightBlock returns the light block at the given height,
+	// or ErrLightBlockNotFound
+	LightBlock(height int64) (*LightBlock, error)
+
+	// LightBlockBefore returns the highest light block strictly below
+	// the given height, or ErrLightBlockNotFound
+	LightBlockBefore(height int64) (*LightBlock, error)
+
+	// LightBlockAfter returns the lowest light block strictly above
+	// the given height, or ErrLightBlockNotFound
+	LightBlockAfter(height int64) (*LightBlock, error)
+
+	// LastLightBlockHeight returns the highest stored height, or -1 if empty
+	LastLightBlockHeight() int64
+
+	// FirstLightBlockHeight returns the lowest stored height, or -1 if empty
+	FirstLightBlockHeight() int64
+
+	// Size returns the number of stored light blocks
+	Size() int
+}
+
+// dbStore is a Store backed by a key-value database
+type dbStore struct {
+	mtx sync.Mutex
+
+	db   dbm.DB
+	size int
+}
+
+// NewDBStore creates a new light block store, backed by the given database
+func NewDBStore(db dbm.DB) Store {
+	s := &dbStore{
+		db: db,
+	}
+
+	// Count the light blocks already in the database
+	it := db.Iterator(signedHeaderPrefix, prefixEnd(signedHeaderPrefix))
+	defer it.Close()
+
+	for ; it.Valid(); it.Next() {
+		s.size++
+	}
+
+	return s
+}
+
+// SaveLightBlock implements Store
+func (s *dbStore) SaveLightBlock(lb *LightBlock) error {
+	if err := lb.ValidateBasic(lb.ChainID); err != nil {
+		return fmt.Errorf("invalid light block, %w", err)
+	}
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	shKey := heightKey(signedHeaderPrefix, lb.Height)
+
+	if s.db.Get(shKey) == nil {
+		s.size++
+	}
+
+	s.db.Set(heightKey(validatorSetPrefix, lb.Height), amino.MustMarshal(lb.ValidatorSet))
+	s.db.SetSync(shKey, amino.MustMarshal(lb.SignedHeader))
+
+	return nil
+}
+
+// DeleteLightBlock implements Store
+func (s *dbStore) DeleteLightBlock(height int64) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	shKey := heightKey(signedHeaderPrefix, height)
+
+	if s.db.Get(shKey) == nil {
+		return fmt.Errorf("%w, height %d", ErrLightBlockNotFound, height)
+	}
+
+	s.db.Delete(heightKey(validatorSetPrefix, height))
+	s.db.DeleteSync(shKey)
+
+	s.size--
+
+	return nil
+}
+
+// LightBlock implements Store
+func (s *dbStore) LightBlock(height int64) (*LightBlock, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	return s.lightBlock(height)
+}
+
+// LightBlockBefore implements Store
+func (s *dbStore) LightBlockBefore(height int64) (*LightBlock, error) {
+	if height <= 0 {
+		return nil, fmt.Errorf("%w, before height %d", ErrLightBlockNotFound, height)
+	}
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	it := s.db.ReverseIterator(signedHeaderPrefix, heightKey(signedHeaderPrefix, height))
+	defer it.Close()
+
+	if !it.Valid() {
+		return nil, fmt.Errorf("%w, before height %d", ErrLightBlockNotFound, height)
+	}
+
+	return s.lightBlock(keyHeight(it.Key()))
+}
+
+// LightBlockAfter implements Store
+func (s *dbStore) LightBlockAfter(height int64) (*LightBlock, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	it := s.db.Iterator(heightKey(signedHeaderPrefix, height+1), prefixEnd(signedHeaderPrefix))
+	defer it.Close()
+
+	if !it.Valid() {
+		return nil, fmt.Errorf("%w, after height %d", ErrLightBlockNotFound, height)
+	}
+
+	return s.lightBlock(keyHeight(it.Key()))
+}
+
+// LastLightBlockHeight implements Store
+func (s *dbStore) LastLightBlockHeight() int64 {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	it := s.db.ReverseIterator(signedHeaderPrefix, prefixEnd(signedHeaderPrefix))
+	defer it.Close()
+
+	if !it.Valid() {
+		return -1
+	}
+
+	return keyHeight(it.Key())
+}
+
+// FirstLightBlockHeight implements Store
+func (s *dbStore) FirstLightBlockHeight() int64 {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	it := s.db.Iterator(signedHeaderPrefix, prefixEnd(signedHeaderPrefix))
+	defer it.Close()
+
+	if !it.Valid() {
+		return -1
+	}
+
+	return keyHeight(it.Key())
+}
+
+// Size implements Store
+func (s *dbStore) Size() int {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	return s.size
+}
+
+// lightBlock loads the light block at the given height.
+// CONTRACT: the caller holds the lock
+func (s *dbStore) lightBlock(height int64) (*LightBlock, error) {
+	shBz := s.db.Get(heightKey(signedHeaderPrefix, height))
+	if shBz == nil {
+		return nil, fmt.Errorf("%w, height %d", ErrLightBlockNotFound, height)
+	}
+
+	var (
+		sh   = new(types.SignedHeader)
+		vals = new(types.ValidatorSet)
+	)
+
+	if err := amino.Unmarshal(shBz, sh); err != nil {
+		return nil, fmt.Errorf("unable to decode signed header at height %d, %w", height, err)
+	}
+
+	if err := amino.Unmarshal(s.db.Get(heightKey(validatorSetPrefix, height)), vals); err != nil {
+		return nil, fmt.Errorf("unable to decode validator set at height %d, %w", height, err)
+	}
+
+	return &LightBlock{
+		SignedHeader: sh,
+		ValidatorSet: vals,
+	}, nil
+}
+
+// heightKey returns the database key for the given height.
+// Heights are big-endian encoded, so the keys are sorted by height
+func heightKey(prefix []byte, height int64) []byte {
+	key := make([]byte, len(prefix)+8)
+
+	copy(key, prefix)
+	binary.BigEndian.PutUint64(key[len(prefix):], uint64(height))
+
+	return key
+}
+
+// keyHeight returns the height encoded in the given database key
+func keyHeight(key []byte) int64 {
+	return int64(binary.BigEndian.Uint64(key[len(key)-8:]))
+}
+
+// prefixEnd returns the end of the iteration range over the given prefix
+func prefixEnd(prefix []byte) []byte {
+	end := append([]byte(nil), prefix...)
+	end[len(end)-1]++
+
+	return end
+}
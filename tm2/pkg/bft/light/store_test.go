@@ -0,0 +1,75 @@
+package light
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/gno/tm2/pkg/db/memdb"
+)
+
+func TestDBStore(t *testing.T) {
+	t.Parallel()
+
+	var (
+		chain = newMockChain(t, 300, 0)
+		db    = memdb.NewMemDB()
+		store = NewDBStore(db)
+	)
+
+	assert.Equal(t, 0, store.Size())
+	assert.Equal(t, int64(-1), store.FirstLightBlockHeight())
+	assert.Equal(t, int64(-1), store.LastLightBlockHeight())
+
+	_, err := store.LightBlock(1)
+	assert.ErrorIs(t, err, ErrLightBlockNotFound)
+
+	// Heights are sorted numerically
+	for _, h := range []int64{2, 256, 10} {
+		require.NoError(t, store.SaveLightBlock(chain.blocks[h]))
+	}
+
+	// Saving twice is a noop
+	require.NoError(t, store.SaveLightBlock(chain.blocks[10]))
+
+	assert.Equal(t, 3, store.Size())
+	assert.Equal(t, int64(2), store.FirstLightBlockHeight())
+	assert.Equal(t, int64(256), store.LastLightBlockHeight())
+
+	lb, err := store.LightBlock(10)
+	require.NoError(t, err)
+	assert.Equal(t, chain.blocks[10].Hash(), lb.Hash())
+	assert.Equal(t, chain.blocks[10].ValidatorSet.Hash(), lb.ValidatorSet.Hash())
+
+	lb, err = store.LightBlockBefore(256)
+	require.NoError(t, err)
+	assert.Equal(t, int64(10), lb.Height)
+
+	lb, err = store.LightBlockAfter(10)
+	require.NoError(t, err)
+	assert.Equal(t, int64(256), lb.Height)
+
+	_, err = store.LightBlockBefore(2)
+	assert.ErrorIs(t, err, ErrLightBlockNotFound)
+
+	_, err = store.LightBlockAfter(256)
+	assert.ErrorIs(t, err, ErrLightBlockNotFound)
+
+	// Deleting light blocks
+	require.NoError(t, store.DeleteLightBlock(2))
+	assert.ErrorIs(t, store.DeleteLightBlock(2), ErrLightBlockNotFound)
+
+	assert.Equal(t, 2, store.Size())
+	assert.Equal(t, int64(10), store.FirstLightBlockHeight())
+
+	// The size is restored when reopening the store
+	assert.Equal(t, 2, NewDBStore(db).Size())
+
+	// Invalid light blocks are not saved
+	invalid := *chain.blocks[3]
+	invalid.ValidatorSet = chain.blocks[3].ValidatorSet.CopyIncrementProposerPriority(1)
+	invalid.ValidatorSet.Validators = invalid.ValidatorSet.Validators[1:]
+
+	assert.ErrorIs(t, store.SaveLightBlock(&invalid), errValidatorsMatch)
+}
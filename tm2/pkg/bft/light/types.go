@@ -0,0 +1,106 @@
+package light
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/crypto/tmhash"
+)
+
+var (
+	errMissingValidators = errors.New("missing validator set")
+	errValidatorsMatch   = errors.New("validator set does not match the header")
+	errInvalidTrustLevel = errors.New("trust level must be within [1/3, 1]")
+	errInvalidPeriod     = errors.New("trusting period must be positive")
+	errInvalidHeight     = errors.New("trusted height must be positive")
+	errInvalidHash       = errors.New("trusted hash must be a valid header hash")
+)
+
+// LightBlock is a signed header, along with the
+// validator set that signed it
+type LightBlock struct {
+	*types.SignedHeader
+	ValidatorSet *types.ValidatorSet
+}
+
+// ValidateBasic verifies the light block is well-formed, and its
+// validator set matches the header. It does not verify the signatures
+func (lb *LightBlock) ValidateBasic(chainID string) error {
+	if lb.SignedHeader == nil {
+		return errors.New("missing signed header")
+	}
+
+	if err := lb.SignedHeader.ValidateBasic(chainID); err != nil {
+		return err
+	}
+
+	if lb.ValidatorSet == nil || lb.ValidatorSet.Size() == 0 {
+		return errMissingValidators
+	}
+
+	if !bytes.Equal(lb.ValidatorSet.Hash(), lb.ValidatorsHash) {
+		return fmt.Errorf("%w, height %d", errValidatorsMatch, lb.Height)
+	}
+
+	return nil
+}
+
+// TrustLevel is the fraction of the trusted validator set voting power
+// that must have signed a header, for it to be trusted when skipping
+type TrustLevel struct {
+	Numerator   int64
+	Denominator int64
+}
+
+// DefaultTrustLevel is the default trust level for skipping verification.
+// At least one honest validator signed the header, assuming less than
+// 1/3 of the trusted validators are byzantine
+var DefaultTrustLevel = TrustLevel{Numerator: 1, Denominator: 3}
+
+// ValidateBasic verifies the trust level is within [1/3, 1]
+func (t TrustLevel) ValidateBasic() error {
+	if t.Denominator <= 0 ||
+		t.Numerator*3 < t.Denominator ||
+		t.Numerator > t.Denominator {
+		return fmt.Errorf("%w, %s", errInvalidTrustLevel, t)
+	}
+
+	return nil
+}
+
+func (t TrustLevel) String() string {
+	return fmt.Sprintf("%d/%d", t.Numerator, t.Denominator)
+}
+
+// TrustOptions are the options used to anchor the trust of the light client,
+// usually obtained from a trusted source (block explorer, validator...)
+type TrustOptions struct {
+	// Period is the trusting period, after which a trusted header can no
+	// longer be used to verify new headers. It should be shorter than the
+	// period during which validators can be punished for misbehaving
+	Period time.Duration
+
+	// Height and Hash identify the trusted header
+	Height int64
+	Hash   []byte
+}
+
+// ValidateBasic verifies the trust options are valid
+func (o TrustOptions) ValidateBasic() error {
+	if o.Period <= 0 {
+		return errInvalidPeriod
+	}
+
+	if o.Height <= 0 {
+		return errInvalidHeight
+	}
+
+	if len(o.Hash) != tmhash.Size {
+		return errInvalidHash
+	}
+
+	return nil
+}
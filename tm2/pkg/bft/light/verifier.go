@@ -0,0 +1,196 @@
+package light
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+)
+
+// maxClockDrift is the maximum time a header can be ahead of the local clock
+const maxClockDrift = 10 * time.Second
+
+var (
+	// ErrHeaderExpired is returned when the trusted header is
+	// outside the trusting period, and can no longer be used for verification
+	ErrHeaderExpired = errors.New("trusted header has expired")
+
+	// ErrNotEnoughTrust is returned when the trusted validators did not sign
+	// enough of an untrusted header, for it to be verified by skipping
+	ErrNotEnoughTrust = errors.New("not enough trusted voting power signed the header")
+
+	errNonIncreasingHeight = errors.New("header height is not above the trusted height")
+	errNonIncreasingTime   = errors.New("header time is not after the trusted header time")
+	errHeaderFromFuture    = errors.New("header time is too far in the future")
+	errNonAdjacentHeight   = errors.New("header height is not adjacent to the trusted height")
+	errNextValidatorsMatch = errors.New("header validators do not match the trusted next validators")
+)
+
+// HeaderExpired returns true if the given header is outside the trusting period
+func HeaderExpired(header *types.SignedHeader, trustingPeriod time.Duration, now time.Time) bool {
+	return !header.Time.Add(trustingPeriod).After(now)
+}
+
+// Verify verifies the untrusted light block using the trusted one,
+// sequentially if the untrusted block is adjacent, or by skipping otherwise
+func Verify(
+	chainID string,
+	trusted *LightBlock,
+	untrusted *LightBlock,
+	trustingPeriod time.Duration,
+	now time.Time,
+	trustLevel TrustLevel,
+) error {
+	if untrusted.Height == trusted.Height+1 {
+		return VerifyAdjacent(chainID, trusted, untrusted, trustingPeriod, now)
+	}
+
+	return VerifyNonAdjacent(chainID, trusted, untrusted, trustingPeriod, now, trustLevel)
+}
+
+// VerifyAdjacent verifies the untrusted light block, directly following
+// the trusted one. The untrusted validators must be the trusted next
+// validators, and more than 2/3 of them must have signed the header
+func VerifyAdjacent(
+	chainID string,
+	trusted *LightBlock,
+	untrusted *LightBlock,
+	trustingPeriod time.Duration,
+	now time.Time,
+) error {
+	if untrusted.Height != trusted.Height+1 {
+		return fmt.Errorf("%w, %d != %d+1", errNonAdjacentHeight, untrusted.Height, trusted.Height)
+	}
+
+	if err := verifyNewHeader(chainID, trusted, untrusted, trustingPeriod, now); err != nil {
+		return err
+	}
+
+	if !bytes.Equal(trusted.NextValidatorsHash, untrusted.ValidatorsHash) {
+		return fmt.Errorf("%w, height %d", errNextValidatorsMatch, untrusted.Height)
+	}
+
+	return verifyCommit(chainID, untrusted)
+}
+
+// VerifyNonAdjacent verifies the untrusted light block, at any height above
+// the trusted one. At least the trust level of the trusted validators
+// voting power must have signed the header, or ErrNotEnoughTrust is returned,
+// and more than 2/3 of the untrusted validators must have signed it
+func VerifyNonAdjacent(
+	chainID string,
+	trusted *LightBlock,
+	untrusted *LightBlock,
+	trustingPeriod time.Duration,
+	now time.Time,
+	trustLevel TrustLevel,
+) error {
+	if err := trustLevel.ValidateBasic(); err != nil {
+		return err
+	}
+
+	if err := verifyNewHeader(chainID, trusted, untrusted, trustingPeriod, now); err != nil {
+		return err
+	}
+
+	if err := verifyCommitTrusting(chainID, trusted.ValidatorSet, untrusted.Commit, trustLevel); err != nil {
+		return err
+	}
+
+	return verifyCommit(chainID, untrusted)
+}
+
+// verifyNewHeader verifies the untrusted light block is well-formed,
+// and it can follow the trusted light block
+func verifyNewHeader(
+	chainID string,
+	trusted *LightBlock,
+	untrusted *LightBlock,
+	trustingPeriod time.Duration,
+	now time.Time,
+) error {
+	if HeaderExpired(trusted.SignedHeader, trustingPeriod, now) {
+		return fmt.Errorf("%w, height %d, time %s", ErrHeaderExpired, trusted.Height, trusted.Time)
+	}
+
+	if err := untrusted.ValidateBasic(chainID); err != nil {
+		return fmt.Errorf("invalid header at height %d, %w", untrusted.Height, err)
+	}
+
+	if untrusted.Height <= trusted.Height {
+		return fmt.Errorf("%w, %d <= %d", errNonIncreasingHeight, untrusted.Height, trusted.Height)
+	}
+
+	if !untrusted.Time.After(trusted.Time) {
+		return fmt.Errorf("%w, height %d", errNonIncreasingTime, untrusted.Height)
+	}
+
+	if untrusted.Time.After(now.Add(maxClockDrift)) {
+		return fmt.Errorf("%w, height %d, time %s", errHeaderFromFuture, untrusted.Height, untrusted.Time)
+	}
+
+	return nil
+}
+
+// verifyCommit verifies more than 2/3 of the light block
+// validators signed its header
+func verifyCommit(chainID string, lb *LightBlock) error {
+	err := lb.ValidatorSet.VerifyCommit(chainID, lb.Commit.BlockID, lb.Height, lb.Commit)
+	if err != nil {
+		return fmt.Errorf("unable to verify commit at height %d, %w", lb.Height, err)
+	}
+
+	return nil
+}
+
+// verifyCommitTrusting verifies at least the trust level of the trusted
+// validators voting power signed the given commit.
+// Validators missing from the trusted set are ignored
+func verifyCommitTrusting(
+	chainID string,
+	trustedVals *types.ValidatorSet,
+	commit *types.Commit,
+	trustLevel TrustLevel,
+) error {
+	var (
+		talliedVotingPower int64
+		seen               = make(map[int]struct{}, len(commit.Precommits))
+
+		needed = trustedVals.TotalVotingPower() * trustLevel.Numerator / trustLevel.Denominator
+	)
+
+	for idx, precommit := range commit.Precommits {
+		if precommit == nil {
+			continue
+		}
+
+		valIdx, val := trustedVals.GetByAddress(precommit.ValidatorAddress)
+		if val == nil {
+			continue
+		}
+
+		// Validators can't sign twice
+		if _, ok := seen[valIdx]; ok {
+			continue
+		}
+
+		seen[valIdx] = struct{}{}
+
+		if !val.PubKey.VerifyBytes(commit.VoteSignBytes(chainID, idx), precommit.Signature) {
+			return fmt.Errorf("invalid commit signature, %v", precommit)
+		}
+
+		// Precommits for other blocks are included, but don't count
+		if commit.BlockID.Equals(precommit.BlockID) {
+			talliedVotingPower += val.VotingPower
+		}
+
+		if talliedVotingPower > needed {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w, got %d, needed more than %d", ErrNotEnoughTrust, talliedVotingPower, needed)
+}
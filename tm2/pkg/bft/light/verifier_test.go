@@ -0,0 +1,135 @@
+package light
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyAdjacent(t *testing.T) {
+	t.Parallel()
+
+	var (
+		chain = newMockChain(t, 4, 2)
+		now   = time.Now()
+	)
+
+	t.Run("valid header", func(t *testing.T) {
+		t.Parallel()
+
+		// Same validators
+		require.NoError(t, VerifyAdjacent(testChainID, chain.blocks[1], chain.blocks[2], testTrustingPeriod, now))
+
+		// Validator set change
+		require.NoError(t, VerifyAdjacent(testChainID, chain.blocks[2], chain.blocks[3], testTrustingPeriod, now))
+	})
+
+	t.Run("non adjacent header", func(t *testing.T) {
+		t.Parallel()
+
+		err := VerifyAdjacent(testChainID, chain.blocks[1], chain.blocks[3], testTrustingPeriod, now)
+		assert.ErrorIs(t, err, errNonAdjacentHeight)
+	})
+
+	t.Run("expired header", func(t *testing.T) {
+		t.Parallel()
+
+		err := VerifyAdjacent(testChainID, chain.blocks[1], chain.blocks[2], time.Second, now)
+		assert.ErrorIs(t, err, ErrHeaderExpired)
+	})
+
+	t.Run("header from the future", func(t *testing.T) {
+		t.Parallel()
+
+		err := VerifyAdjacent(testChainID, chain.blocks[1], chain.blocks[2], testTrustingPeriod, testBlockTime.Add(-time.Minute))
+		assert.ErrorIs(t, err, errHeaderFromFuture)
+	})
+
+	t.Run("next validators mismatch", func(t *testing.T) {
+		t.Parallel()
+
+		fork := newMockChain(t, 2, 0)
+
+		err := VerifyAdjacent(testChainID, chain.blocks[1], fork.blocks[2], testTrustingPeriod, now)
+		assert.ErrorIs(t, err, errNextValidatorsMatch)
+	})
+
+	t.Run("wrong chain", func(t *testing.T) {
+		t.Parallel()
+
+		err := VerifyAdjacent("other-chain", chain.blocks[1], chain.blocks[2], testTrustingPeriod, now)
+		assert.Error(t, err)
+	})
+}
+
+func TestVerifyNonAdjacent(t *testing.T) {
+	t.Parallel()
+
+	var (
+		chain = newMockChain(t, 6, 3)
+		now   = time.Now()
+	)
+
+	t.Run("valid header", func(t *testing.T) {
+		t.Parallel()
+
+		err := VerifyNonAdjacent(testChainID, chain.blocks[1], chain.blocks[3], testTrustingPeriod, now, DefaultTrustLevel)
+		assert.NoError(t, err)
+	})
+
+	t.Run("not enough trust", func(t *testing.T) {
+		t.Parallel()
+
+		// The validators at height 1 did not sign height 5
+		err := VerifyNonAdjacent(testChainID, chain.blocks[1], chain.blocks[5], testTrustingPeriod, now, DefaultTrustLevel)
+		assert.ErrorIs(t, err, ErrNotEnoughTrust)
+	})
+
+	t.Run("non increasing height", func(t *testing.T) {
+		t.Parallel()
+
+		err := VerifyNonAdjacent(testChainID, chain.blocks[3], chain.blocks[2], testTrustingPeriod, now, DefaultTrustLevel)
+		assert.ErrorIs(t, err, errNonIncreasingHeight)
+	})
+
+	t.Run("invalid trust level", func(t *testing.T) {
+		t.Parallel()
+
+		err := VerifyNonAdjacent(testChainID, chain.blocks[1], chain.blocks[3], testTrustingPeriod, now, TrustLevel{1, 4})
+		assert.ErrorIs(t, err, errInvalidTrustLevel)
+	})
+}
+
+func TestTrustLevel_ValidateBasic(t *testing.T) {
+	t.Parallel()
+
+	testTable := []struct {
+		name       string
+		trustLevel TrustLevel
+		valid      bool
+	}{
+		{"default", DefaultTrustLevel, true},
+		{"two thirds", TrustLevel{2, 3}, true},
+		{"one", TrustLevel{1, 1}, true},
+		{"below one third", TrustLevel{1, 4}, false},
+		{"above one", TrustLevel{4, 3}, false},
+		{"zero denominator", TrustLevel{1, 0}, false},
+		{"negative", TrustLevel{-1, -3}, false},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := testCase.trustLevel.ValidateBasic()
+
+			if testCase.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, errInvalidTrustLevel)
+			}
+		})
+	}
+}
@@ -0,0 +1,355 @@
+package mempool
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	auto "github.com/gnolang/gno/tm2/pkg/autofile"
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	"github.com/gnolang/gno/tm2/pkg/bft/appconn"
+	cfg "github.com/gnolang/gno/tm2/pkg/bft/mempool/config"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/clist"
+	"github.com/gnolang/gno/tm2/pkg/errors"
+	"github.com/gnolang/gno/tm2/pkg/log"
+	osm "github.com/gnolang/gno/tm2/pkg/os"
+	"github.com/gnolang/gno/tm2/pkg/telemetry"
+	"github.com/gnolang/gno/tm2/pkg/telemetry/metrics"
+)
+
+// baseMempool holds the state and implements the methods shared by the
+// mempools: the list of txs, the cache of seen txs, the WAL, and the
+// notification of available txs. The ordering of the txs, and how they are
+// (re)checked against the app, is left to the mempools embedding it.
+type baseMempool struct {
+	config *cfg.MempoolConfig
+
+	mtx          sync.Mutex
+	proxyAppConn appconn.Mempool
+	txs          *clist.CList // concurrent linked-list of good txs, in order of arrival
+	preCheck     PreCheckFunc
+	height       int64 // the last block Update()'d to
+	maxTxBytes   int64
+
+	// notify listeners (ie. consensus) when txs are available
+	notifiedTxsAvailable bool
+	txsAvailable         chan struct{} // fires once for each height, when the mempool is not empty
+
+	// Map for quick access to txs to record sender in CheckTx.
+	// txsMap: txKey -> CElement
+	txsMap sync.Map
+
+	// Atomic integers
+	txsBytes   int64 // total size of mempool, in bytes
+	rechecking int32 // for re-checking filtered txs on Update()
+
+	// Keep a cache of already-seen txs.
+	// This reduces the pressure on the proxyApp.
+	cache txCache
+
+	// A log of mempool txs
+	wal *auto.AutoFile
+
+	logger *slog.Logger
+}
+
+// init initializes the base of a new mempool; the response callback of
+// proxyAppConn is left to the caller.
+func (mem *baseMempool) init(
+	config *cfg.MempoolConfig,
+	proxyAppConn appconn.Mempool,
+	height int64,
+	maxTxBytes int64,
+) {
+	if maxTxBytes <= 0 {
+		panic("maxTxBytes must be positive")
+	}
+	mem.config = config
+	mem.proxyAppConn = proxyAppConn
+	mem.txs = clist.New()
+	mem.height = height
+	mem.maxTxBytes = maxTxBytes
+	mem.logger = log.NewNoopLogger()
+	if config.CacheSize > 0 {
+		mem.cache = newMapTxCache(config.CacheSize)
+	} else {
+		mem.cache = nopTxCache{}
+	}
+}
+
+// NOTE: not thread safe - should only be called once, on startup
+func (mem *baseMempool) EnableTxsAvailable() {
+	mem.txsAvailable = make(chan struct{}, 1)
+}
+
+// SetLogger sets the Logger.
+func (mem *baseMempool) SetLogger(l *slog.Logger) {
+	mem.logger = l
+}
+
+// *panics* if can't create directory or open file.
+// *not thread safe*
+func (mem *baseMempool) InitWAL() {
+	walDir := mem.config.WalDir()
+	err := osm.EnsureDir(walDir, 0o700)
+	if err != nil {
+		panic(errors.Wrap(err, "Error ensuring WAL dir"))
+	}
+	af, err := auto.OpenAutoFile(walDir + "/wal")
+	if err != nil {
+		panic(errors.Wrap(err, "Error opening WAL file"))
+	}
+	mem.wal = af
+}
+
+func (mem *baseMempool) CloseWAL() {
+	mem.mtx.Lock()
+	defer mem.mtx.Unlock()
+
+	if err := mem.wal.Close(); err != nil {
+		mem.logger.Error("Error closing WAL", "err", err)
+	}
+	mem.wal = nil
+}
+
+func (mem *baseMempool) Lock() {
+	mem.mtx.Lock()
+}
+
+func (mem *baseMempool) Unlock() {
+	mem.mtx.Unlock()
+}
+
+func (mem *baseMempool) Size() int {
+	return mem.txs.Len()
+}
+
+func (mem *baseMempool) MaxTxBytes() int64 {
+	mem.mtx.Lock()
+	defer mem.mtx.Unlock()
+	return mem.maxTxBytes
+}
+
+func (mem *baseMempool) TxsBytes() int64 {
+	return atomic.LoadInt64(&mem.txsBytes)
+}
+
+func (mem *baseMempool) FlushAppConn() error {
+	return mem.proxyAppConn.FlushSync()
+}
+
+// flush removes all txs from the list and the cache.
+// NOTE: lock must be held.
+func (mem *baseMempool) flush() {
+	mem.cache.Reset()
+
+	for e := mem.txs.Front(); e != nil; e = e.Next() {
+		mem.txs.Remove(e)
+		e.DetachPrev()
+	}
+
+	mem.txsMap = sync.Map{}
+	_ = atomic.SwapInt64(&mem.txsBytes, 0)
+}
+
+// TxsFront returns the first transaction in the order of arrival for peer
+// goroutines to call .NextWait() on.
+// FIXME: leaking implementation details!
+func (mem *baseMempool) TxsFront() *clist.CElement {
+	return mem.txs.Front()
+}
+
+// TxsWaitChan returns a channel to wait on transactions. It will be closed
+// once the mempool is not empty (ie. the internal `mem.txs` has at least one
+// element)
+func (mem *baseMempool) TxsWaitChan() <-chan struct{} {
+	return mem.txs.WaitChan()
+}
+
+// checkTxLocal runs the checks of a new tx which don't need the app, records
+// it in the cache and the WAL, and checks the connection to the app, before
+// the tx is sent to the app with CheckTx.
+// NOTE: lock must be held.
+func (mem *baseMempool) checkTxLocal(tx types.Tx, txInfo TxInfo) error {
+	txSize := len(tx)
+
+	// Check max tx bytes
+	if int64(txSize) > mem.maxTxBytes {
+		return TxTooLargeError{mem.maxTxBytes, int64(txSize)}
+	}
+
+	// Check custom preCheck function
+	if mem.preCheck != nil {
+		if err := mem.preCheck(tx); err != nil {
+			return err
+		}
+	}
+
+	// CACHE
+	if !mem.cache.Push(tx) {
+		// Record a new sender for a tx we've already seen.
+		// Note it's possible a tx is still in the cache but no longer in the mempool
+		// (eg. after committing a block, txs are removed from mempool but not cache),
+		// so we only record the sender for txs still in the mempool.
+		if e, ok := mem.txsMap.Load(txKey(tx)); ok {
+			memTx := e.(*clist.CElement).Value.(*mempoolTx)
+			memTx.senders.LoadOrStore(txInfo.SenderID, true)
+			// TODO: consider punishing peer for dups,
+			// its non-trivial since invalid txs can become valid,
+			// but they can spam the same tx with little cost to them atm.
+		}
+
+		return ErrTxInCache
+	}
+	// END CACHE
+
+	// WAL
+	if mem.wal != nil {
+		// TODO: Notify administrators when WAL fails
+		_, err := mem.wal.Write([]byte(tx))
+		if err != nil {
+			mem.logger.Error("Error writing to WAL", "err", err)
+		}
+		_, err = mem.wal.Write([]byte("\n"))
+		if err != nil {
+			mem.logger.Error("Error writing to WAL", "err", err)
+		}
+	}
+	// END WAL
+
+	// NOTE: proxyAppConn may error if tx buffer is full
+	return mem.proxyAppConn.Error()
+}
+
+// pushTx adds memTx at the back of the list of txs.
+func (mem *baseMempool) pushTx(memTx *mempoolTx) *clist.CElement {
+	e := mem.txs.PushBack(memTx)
+	mem.txsMap.Store(txKey(memTx.tx), e)
+	atomic.AddInt64(&mem.txsBytes, int64(len(memTx.tx)))
+
+	// Update the telemetry
+	mem.logTelemetry()
+
+	return e
+}
+
+// removeTx removes the tx at elem from the list of txs.
+func (mem *baseMempool) removeTx(tx types.Tx, elem *clist.CElement, removeFromCache bool) {
+	mem.txs.Remove(elem)
+	elem.DetachPrev()
+	mem.txsMap.Delete(txKey(tx))
+	atomic.AddInt64(&mem.txsBytes, int64(-len(tx)))
+
+	if removeFromCache {
+		mem.cache.Remove(tx)
+	}
+
+	// Update the telemetry
+	mem.logTelemetry()
+}
+
+// logTelemetry logs the mempool telemetry
+func (mem *baseMempool) logTelemetry() {
+	if !telemetry.MetricsEnabled() {
+		return
+	}
+
+	// Log the total number of mempool transactions
+	metrics.NumMempoolTxs.Record(context.Background(), int64(mem.txs.Len()))
+
+	// Log the total number of the mempool cache transactions
+	metrics.NumCachedTxs.Record(context.Background(), int64(mem.cache.Len()))
+}
+
+func (mem *baseMempool) TxsAvailable() <-chan struct{} {
+	return mem.txsAvailable
+}
+
+func (mem *baseMempool) notifyTxsAvailable() {
+	if mem.Size() == 0 {
+		panic("notified txs available but mempool is empty!")
+	}
+	if mem.txsAvailable != nil && !mem.notifiedTxsAvailable {
+		// channel cap is 1, so this will send once
+		mem.notifiedTxsAvailable = true
+		select {
+		case mem.txsAvailable <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// waitRecheck waits for the txs being rechecked, before reaping txs.
+func (mem *baseMempool) waitRecheck() {
+	for atomic.LoadInt32(&mem.rechecking) > 0 {
+		// TODO: Something better?
+		time.Sleep(time.Millisecond * 10)
+	}
+}
+
+// update sets the state of the mempool for the new height, and calls remove
+// for each of the committed txs which is still in the mempool.
+// NOTE: lock must be held.
+func (mem *baseMempool) update(
+	height int64,
+	txs types.Txs,
+	deliverTxResponses []abci.ResponseDeliverTx,
+	preCheck PreCheckFunc,
+	maxTxBytes int64,
+	remove func(tx types.Tx, elem *clist.CElement),
+) {
+	// Set height
+	mem.height = height
+	mem.notifiedTxsAvailable = false
+
+	if preCheck != nil {
+		mem.preCheck = preCheck
+	}
+	if maxTxBytes != 0 {
+		mem.maxTxBytes = maxTxBytes
+	}
+
+	for i, tx := range txs {
+		if deliverTxResponses[i].Error == nil {
+			// Add valid committed tx to the cache (if missing).
+			_ = mem.cache.Push(tx)
+		} else {
+			// Allow invalid transactions to be resubmitted.
+			mem.cache.Remove(tx)
+		}
+
+		// Remove committed tx from the mempool.
+		//
+		// Note an evil proposer can drop valid txs!
+		// Mempool before:
+		//   100 -> 101 -> 102
+		// Block, proposed by an evil proposer:
+		//   101 -> 102
+		// Mempool after:
+		//   100
+		// https://github.com/tendermint/classic/issues/3322.
+		if e, ok := mem.txsMap.Load(txKey(tx)); ok {
+			remove(tx, e.(*clist.CElement))
+		}
+	}
+}
+
+// checkRecheckTx returns false if memTx can no longer be in the mempool,
+// because of the max tx bytes or the preCheck function; it is then removed by
+// the caller before rechecking the other txs.
+func (mem *baseMempool) checkRecheckTx(memTx *mempoolTx) bool {
+	// check tx size
+	if int64(len(memTx.tx)) > mem.maxTxBytes {
+		return false
+	}
+	// run precheck
+	if mem.preCheck != nil {
+		if err := mem.preCheck(memTx.tx); err != nil {
+			return false
+		}
+	}
+	return true
+}
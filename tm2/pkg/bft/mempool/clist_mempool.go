@@ -3,25 +3,16 @@ package mempool
 import (
 	"bytes"
 	"container/list"
-	"context"
 	"crypto/sha256"
 	"fmt"
-	"log/slog"
 	"sync"
 	"sync/atomic"
-	"time"
 
-	auto "github.com/gnolang/gno/tm2/pkg/autofile"
 	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
 	"github.com/gnolang/gno/tm2/pkg/bft/appconn"
 	cfg "github.com/gnolang/gno/tm2/pkg/bft/mempool/config"
 	"github.com/gnolang/gno/tm2/pkg/bft/types"
 	"github.com/gnolang/gno/tm2/pkg/clist"
-	"github.com/gnolang/gno/tm2/pkg/errors"
-	"github.com/gnolang/gno/tm2/pkg/log"
-	osm "github.com/gnolang/gno/tm2/pkg/os"
-	"github.com/gnolang/gno/tm2/pkg/telemetry"
-	"github.com/gnolang/gno/tm2/pkg/telemetry/metrics"
 )
 
 // --------------------------------------------------------------------------------
@@ -32,41 +23,13 @@ import (
 // mempool uses a concurrent list structure for storing transactions that can
 // be efficiently accessed by multiple concurrent readers.
 type CListMempool struct {
-	config *cfg.MempoolConfig
-
-	mtx          sync.Mutex
-	proxyAppConn appconn.Mempool
-	txs          *clist.CList // concurrent linked-list of good txs
-	preCheck     PreCheckFunc
-	height       int64 // the last block Update()'d to
-	maxTxBytes   int64
+	baseMempool
 
 	// Track whether we're rechecking txs.
 	// These are not protected by a mutex and are expected to be mutated
 	// in serial (ie. by abci responses which are called in serial).
 	recheckCursor *clist.CElement // next expected response
 	recheckEnd    *clist.CElement // re-checking stops here
-
-	// notify listeners (ie. consensus) when txs are available
-	notifiedTxsAvailable bool
-	txsAvailable         chan struct{} // fires once for each height, when the mempool is not empty
-
-	// Map for quick access to txs to record sender in CheckTx.
-	// txsMap: txKey -> CElement
-	txsMap sync.Map
-
-	// Atomic integers
-	txsBytes   int64 // total size of mempool, in bytes
-	rechecking int32 // for re-checking filtered txs on Update()
-
-	// Keep a cache of already-seen txs.
-	// This reduces the pressure on the proxyApp.
-	cache txCache
-
-	// A log of mempool txs
-	wal *auto.AutoFile
-
-	logger *slog.Logger
 }
 
 var _ Mempool = &CListMempool{}
@@ -82,25 +45,8 @@ func NewCListMempool(
 	maxTxBytes int64,
 	options ...CListMempoolOption,
 ) *CListMempool {
-	if maxTxBytes <= 0 {
-		panic("maxTxBytes must be positive")
-	}
-	mempool := &CListMempool{
-		config:        config,
-		proxyAppConn:  proxyAppConn,
-		txs:           clist.New(),
-		height:        height,
-		maxTxBytes:    maxTxBytes,
-		rechecking:    0,
-		recheckCursor: nil,
-		recheckEnd:    nil,
-		logger:        log.NewNoopLogger(),
-	}
-	if config.CacheSize > 0 {
-		mempool.cache = newMapTxCache(config.CacheSize)
-	} else {
-		mempool.cache = nopTxCache{}
-	}
+	mempool := &CListMempool{}
+	mempool.init(config, proxyAppConn, height, maxTxBytes)
 	proxyAppConn.SetResponseCallback(mempool.globalCb)
 	for _, option := range options {
 		option(mempool)
@@ -108,100 +54,17 @@ func NewCListMempool(
 	return mempool
 }
 
-// NOTE: not thread safe - should only be called once, on startup
-func (mem *CListMempool) EnableTxsAvailable() {
-	mem.txsAvailable = make(chan struct{}, 1)
-}
-
-// SetLogger sets the Logger.
-func (mem *CListMempool) SetLogger(l *slog.Logger) {
-	mem.logger = l
-}
-
 // WithPreCheck sets a filter for the mempool to reject a tx if f(tx) returns
 // false. This is ran before CheckTx.
 func WithPreCheck(f PreCheckFunc) CListMempoolOption {
 	return func(mem *CListMempool) { mem.preCheck = f }
 }
 
-// *panics* if can't create directory or open file.
-// *not thread safe*
-func (mem *CListMempool) InitWAL() {
-	walDir := mem.config.WalDir()
-	err := osm.EnsureDir(walDir, 0o700)
-	if err != nil {
-		panic(errors.Wrap(err, "Error ensuring WAL dir"))
-	}
-	af, err := auto.OpenAutoFile(walDir + "/wal")
-	if err != nil {
-		panic(errors.Wrap(err, "Error opening WAL file"))
-	}
-	mem.wal = af
-}
-
-func (mem *CListMempool) CloseWAL() {
-	mem.mtx.Lock()
-	defer mem.mtx.Unlock()
-
-	if err := mem.wal.Close(); err != nil {
-		mem.logger.Error("Error closing WAL", "err", err)
-	}
-	mem.wal = nil
-}
-
-func (mem *CListMempool) Lock() {
-	mem.mtx.Lock()
-}
-
-func (mem *CListMempool) Unlock() {
-	mem.mtx.Unlock()
-}
-
-func (mem *CListMempool) Size() int {
-	return mem.txs.Len()
-}
-
-func (mem *CListMempool) MaxTxBytes() int64 {
-	mem.mtx.Lock()
-	defer mem.mtx.Unlock()
-	return mem.maxTxBytes
-}
-
-func (mem *CListMempool) TxsBytes() int64 {
-	return atomic.LoadInt64(&mem.txsBytes)
-}
-
-func (mem *CListMempool) FlushAppConn() error {
-	return mem.proxyAppConn.FlushSync()
-}
-
 func (mem *CListMempool) Flush() {
 	mem.mtx.Lock()
 	defer mem.mtx.Unlock()
 
-	mem.cache.Reset()
-
-	for e := mem.txs.Front(); e != nil; e = e.Next() {
-		mem.txs.Remove(e)
-		e.DetachPrev()
-	}
-
-	mem.txsMap = sync.Map{}
-	_ = atomic.SwapInt64(&mem.txsBytes, 0)
-}
-
-// TxsFront returns the first transaction in the ordered list for peer
-// goroutines to call .NextWait() on.
-// FIXME: leaking implementation details!
-func (mem *CListMempool) TxsFront() *clist.CElement {
-	return mem.txs.Front()
-}
-
-// TxsWaitChan returns a channel to wait on transactions. It will be closed
-// once the mempool is not empty (ie. the internal `mem.txs` has at least one
-// element)
-func (mem *CListMempool) TxsWaitChan() <-chan struct{} {
-	return mem.txs.WaitChan()
+	mem.flush()
 }
 
 // It blocks if we're waiting on Update() or Reap().
@@ -234,52 +97,7 @@ func (mem *CListMempool) CheckTxWithInfo(tx types.Tx, cb func(abci.Response), tx
 		}
 	}
 
-	// Check max tx bytes
-	if int64(txSize) > mem.maxTxBytes {
-		return TxTooLargeError{mem.maxTxBytes, int64(txSize)}
-	}
-
-	// Check custom preCheck function
-	if mem.preCheck != nil {
-		if err := mem.preCheck(tx); err != nil {
-			return err
-		}
-	}
-
-	// CACHE
-	if !mem.cache.Push(tx) {
-		// Record a new sender for a tx we've already seen.
-		// Note it's possible a tx is still in the cache but no longer in the mempool
-		// (eg. after committing a block, txs are removed from mempool but not cache),
-		// so we only record the sender for txs still in the mempool.
-		if e, ok := mem.txsMap.Load(txKey(tx)); ok {
-			memTx := e.(*clist.CElement).Value.(*mempoolTx)
-			memTx.senders.LoadOrStore(txInfo.SenderID, true)
-			// TODO: consider punishing peer for dups,
-			// its non-trivial since invalid txs can become valid,
-			// but they can spam the same tx with little cost to them atm.
-		}
-
-		return ErrTxInCache
-	}
-	// END CACHE
-
-	// WAL
-	if mem.wal != nil {
-		// TODO: Notify administrators when WAL fails
-		_, err := mem.wal.Write([]byte(tx))
-		if err != nil {
-			mem.logger.Error("Error writing to WAL", "err", err)
-		}
-		_, err = mem.wal.Write([]byte("\n"))
-		if err != nil {
-			mem.logger.Error("Error writing to WAL", "err", err)
-		}
-	}
-	// END WAL
-
-	// NOTE: proxyAppConn may error if tx buffer is full
-	if err = mem.proxyAppConn.Error(); err != nil {
+	if err = mem.checkTxLocal(tx, txInfo); err != nil {
 		return err
 	}
 
@@ -330,47 +148,6 @@ func (mem *CListMempool) reqResCb(tx []byte, peerID uint16, externalCb func(abci
 	}
 }
 
-// Called from:
-//   - resCbFirstTime (lock not held) if tx is valid
-func (mem *CListMempool) addTx(memTx *mempoolTx) {
-	e := mem.txs.PushBack(memTx)
-	mem.txsMap.Store(txKey(memTx.tx), e)
-	atomic.AddInt64(&mem.txsBytes, int64(len(memTx.tx)))
-
-	// Update the telemetry
-	mem.logTelemetry()
-}
-
-// logTelemetry logs the mempool telemetry
-func (mem *CListMempool) logTelemetry() {
-	if !telemetry.MetricsEnabled() {
-		return
-	}
-
-	// Log the total number of mempool transactions
-	metrics.NumMempoolTxs.Record(context.Background(), int64(mem.txs.Len()))
-
-	// Log the total number of the mempool cache transactions
-	metrics.NumCachedTxs.Record(context.Background(), int64(mem.cache.Len()))
-}
-
-// Called from:
-//   - Update (lock held) if tx was committed
-//   - resCbRecheck (lock not held) if tx was invalidated
-func (mem *CListMempool) removeTx(tx types.Tx, elem *clist.CElement, removeFromCache bool) {
-	mem.txs.Remove(elem)
-	elem.DetachPrev()
-	mem.txsMap.Delete(txKey(tx))
-	atomic.AddInt64(&mem.txsBytes, int64(-len(tx)))
-
-	if removeFromCache {
-		mem.cache.Remove(tx)
-	}
-
-	// Update the telemetry
-	mem.logTelemetry()
-}
-
 // callback, which is called after the app checked the tx for the first time.
 //
 // The case where the app checks the tx for the second and subsequent times is
@@ -385,7 +162,7 @@ func (mem *CListMempool) resCbFirstTime(tx []byte, peerID uint16, res abci.Respo
 				tx:        tx,
 			}
 			memTx.senders.Store(peerID, true)
-			mem.addTx(memTx)
+			mem.pushTx(memTx)
 			mem.logger.Info("Added good transaction",
 				"tx", txID(tx),
 				"res", res,
@@ -447,24 +224,6 @@ func (mem *CListMempool) resCbRecheck(req abci.Request, res abci.Response) {
 	}
 }
 
-func (mem *CListMempool) TxsAvailable() <-chan struct{} {
-	return mem.txsAvailable
-}
-
-func (mem *CListMempool) notifyTxsAvailable() {
-	if mem.Size() == 0 {
-		panic("notified txs available but mempool is empty!")
-	}
-	if mem.txsAvailable != nil && !mem.notifiedTxsAvailable {
-		// channel cap is 1, so this will send once
-		mem.notifiedTxsAvailable = true
-		select {
-		case mem.txsAvailable <- struct{}{}:
-		default:
-		}
-	}
-}
-
 func (mem *CListMempool) ReapMaxBytesMaxGas(maxDataBytes, maxGas int64) types.Txs {
 	mem.mtx.Lock()
 	defer mem.mtx.Unlock()
@@ -473,10 +232,7 @@ func (mem *CListMempool) ReapMaxBytesMaxGas(maxDataBytes, maxGas int64) types.Tx
 		panic("ReapMaxBytesMaxGas requires maxDataBytes > 0")
 	}
 
-	for atomic.LoadInt32(&mem.rechecking) > 0 {
-		// TODO: Something better?
-		time.Sleep(time.Millisecond * 10)
-	}
+	mem.waitRecheck()
 
 	var totalBytes int64
 	var totalGas int64
@@ -513,10 +269,7 @@ func (mem *CListMempool) ReapMaxTxs(maxVal int) types.Txs {
 		maxVal = mem.txs.Len()
 	}
 
-	for atomic.LoadInt32(&mem.rechecking) > 0 {
-		// TODO: Something better?
-		time.Sleep(time.Millisecond * 10)
-	}
+	mem.waitRecheck()
 
 	txs := make([]types.Tx, 0, min(mem.txs.Len(), maxVal))
 	for e := mem.txs.Front(); e != nil && len(txs) <= maxVal; e = e.Next() {
@@ -533,40 +286,9 @@ func (mem *CListMempool) Update(
 	preCheck PreCheckFunc,
 	maxTxBytes int64,
 ) error {
-	// Set height
-	mem.height = height
-	mem.notifiedTxsAvailable = false
-
-	if preCheck != nil {
-		mem.preCheck = preCheck
-	}
-	if maxTxBytes != 0 {
-		mem.maxTxBytes = maxTxBytes
-	}
-
-	for i, tx := range txs {
-		if deliverTxResponses[i].Error == nil {
-			// Add valid committed tx to the cache (if missing).
-			_ = mem.cache.Push(tx)
-		} else {
-			// Allow invalid transactions to be resubmitted.
-			mem.cache.Remove(tx)
-		}
-
-		// Remove committed tx from the mempool.
-		//
-		// Note an evil proposer can drop valid txs!
-		// Mempool before:
-		//   100 -> 101 -> 102
-		// Block, proposed by an evil proposer:
-		//   101 -> 102
-		// Mempool after:
-		//   100
-		// https://github.com/tendermint/classic/issues/3322.
-		if e, ok := mem.txsMap.Load(txKey(tx)); ok {
-			mem.removeTx(tx, e.(*clist.CElement), false)
-		}
-	}
+	mem.update(height, txs, deliverTxResponses, preCheck, maxTxBytes, func(tx types.Tx, e *clist.CElement) {
+		mem.removeTx(tx, e, false)
+	})
 
 	// Either recheck non-committed txs to see if they became invalid
 	// or just notify there're some txs left.
@@ -598,18 +320,10 @@ func (mem *CListMempool) recheckTxs() {
 	// NOTE: globalCb may be called concurrently.
 	for e := mem.txs.Front(); e != nil; e = e.Next() {
 		memTx := e.Value.(*mempoolTx)
-		// check tx size
-		if int64(len(memTx.tx)) > mem.maxTxBytes {
+		if !mem.checkRecheckTx(memTx) {
 			mem.removeTx(memTx.tx, e, false)
 			continue
 		}
-		// run precheck
-		if mem.preCheck != nil {
-			if err := mem.preCheck(memTx.tx); err != nil {
-				mem.removeTx(memTx.tx, e, false)
-				continue
-			}
-		}
 		// run proxy app checktx
 		mem.proxyAppConn.CheckTxAsync(abci.RequestCheckTx{
 			Tx:   memTx.tx,
@@ -628,6 +342,13 @@ type mempoolTx struct {
 	gasWanted int64    // amount of gas this tx states it will require
 	tx        types.Tx //
 
+	// ordering of the tx, used by the PriorityMempool.
+	priority int64  // priority returned by the app
+	sender   string // sender returned by the app
+	sequence uint64 // sequence returned by the app
+	id       uint64 // order of arrival
+	evictIdx int    // index in the evictable txs, -1 if not evictable
+
 	// ids of peers who've sent us this tx (as a map for quick lookups).
 	// senders: PeerID -> bool
 	senders sync.Map
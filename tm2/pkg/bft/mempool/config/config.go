@@ -1,13 +1,28 @@
 package config
 
-import "github.com/gnolang/gno/tm2/pkg/errors"
+import (
+	"fmt"
+
+	"github.com/gnolang/gno/tm2/pkg/errors"
+)
 
 // -----------------------------------------------------------------------------
 // MempoolConfig
 
+// Mempool types.
+const (
+	// FIFOMempoolType is the CListMempool, which orders the txs in the
+	// order they are received.
+	FIFOMempoolType = "fifo"
+	// PriorityMempoolType is the PriorityMempool, which orders the txs by gas
+	// price, and the txs of the same account by sequence.
+	PriorityMempoolType = "priority"
+)
+
 // MempoolConfig defines the configuration options for the Tendermint mempool
 type MempoolConfig struct {
 	RootDir            string `json:"home" toml:"home"`
+	Type               string `json:"type" toml:"type" comment:"Mempool implementation, either \"fifo\" (ordered by arrival)\n or \"priority\" (ordered by gas price, with replace-by-fee)"`
 	Recheck            bool   `json:"recheck" toml:"recheck"`
 	Broadcast          bool   `json:"broadcast" toml:"broadcast"`
 	WalPath            string `json:"wal_dir" toml:"wal_dir"`
@@ -19,6 +34,7 @@ type MempoolConfig struct {
 // DefaultMempoolConfig returns a default configuration for the Tendermint mempool
 func DefaultMempoolConfig() *MempoolConfig {
 	return &MempoolConfig{
+		Type:      FIFOMempoolType,
 		Recheck:   true,
 		Broadcast: true,
 		WalPath:   "",
@@ -50,6 +66,11 @@ func (cfg *MempoolConfig) WalEnabled() bool {
 // ValidateBasic performs basic validation (checking param bounds, etc.) and
 // returns an error if any check fails.
 func (cfg *MempoolConfig) ValidateBasic() error {
+	switch cfg.Type {
+	case "", FIFOMempoolType, PriorityMempoolType: // defaults to fifo
+	default:
+		return fmt.Errorf("unknown mempool type %q", cfg.Type)
+	}
 	if cfg.Size < 0 {
 		return errors.New("size can't be negative")
 	}
@@ -0,0 +1,731 @@
+package mempool
+
+import (
+	"bytes"
+	"container/heap"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	"github.com/gnolang/gno/tm2/pkg/bft/appconn"
+	cfg "github.com/gnolang/gno/tm2/pkg/bft/mempool/config"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/clist"
+	"github.com/gnolang/gno/tm2/pkg/errors"
+)
+
+// ErrTxUnderpriced is returned to the client if the tx did not have a priority
+// high enough to replace another tx, or to enter the full mempool.
+var ErrTxUnderpriced = errors.New("Tx priority too low")
+
+// ErrTxSequenceGap is returned to the client if the sequence of the tx does
+// not follow the sequence of the last pending tx of its sender.
+var ErrTxSequenceGap = errors.New("Tx sequence does not follow the pending txs of its sender")
+
+// --------------------------------------------------------------------------------
+
+// PriorityMempool is an in-memory pool for transactions before they are
+// proposed in a consensus round, which orders them by the priority returned by
+// the application on CheckTx (usually the gas price), instead of their order
+// of arrival like the CListMempool.
+//
+// The txs of the same sender are always ordered by sequence. A tx with the
+// same sender and sequence as a pending tx replaces it, if its priority is
+// higher (replace-by-fee); the application is asked to accept such txs with
+// the CheckTxTypeReplace requests, and to reject them if their priority is
+// not higher, and else to undo the changes of the replaced tx. When the
+// mempool is full, the txs with the lowest priority are evicted for txs with a
+// higher priority.
+//
+// The txs for which the application does not return a sender are ordered by
+// priority only.
+//
+// The application keeps the changes of the txs it accepts on CheckTx, like
+// the sequences of their senders. When a tx accepted by the application is not
+// added to the mempool, or when txs are evicted, the CheckTx state is reset
+// with a CheckTxTypeReset request and the pending txs are rechecked, before
+// the next tx is checked.
+type PriorityMempool struct {
+	baseMempool
+
+	// Index of the txs by sender, with its own mutex as it is accessed from
+	// the abci callbacks, which may run while mtx is held.
+	// senders: sender -> CElements, sorted by sequence
+	idxMtx  sync.RWMutex
+	senders map[string][]*clist.CElement
+	nextID  uint64 // to order the txs of equal priority by arrival
+
+	// Heap of the txs which can be evicted when the mempool is full, by
+	// increasing priority: the last pending tx of each sender, and the txs
+	// without sender. Protected by idxMtx.
+	evictable evictableTxs
+
+	// Track whether we're rechecking txs.
+	// These are not protected by a mutex and are expected to be mutated
+	// in serial (ie. by abci responses which are called in serial).
+	recheckTxs    []*clist.CElement // txs being rechecked, in the order of their requests
+	recheckCursor int               // index of the next expected response
+
+	// Set (atomically) when the CheckTx state of the app has changes of txs
+	// which are not in the mempool: txs accepted by the app but rejected by
+	// the mempool, and evicted txs. The state is then reset before the next
+	// tx is checked.
+	staleCheckState int32
+}
+
+var _ Mempool = &PriorityMempool{}
+
+// PriorityMempoolOption sets an optional parameter on the mempool.
+type PriorityMempoolOption func(*PriorityMempool)
+
+// NewPriorityMempool returns a new mempool with the given configuration and
+// connection to an application.
+func NewPriorityMempool(
+	config *cfg.MempoolConfig,
+	proxyAppConn appconn.Mempool,
+	height int64,
+	maxTxBytes int64,
+	options ...PriorityMempoolOption,
+) *PriorityMempool {
+	mempool := &PriorityMempool{
+		senders: make(map[string][]*clist.CElement),
+	}
+	mempool.init(config, proxyAppConn, height, maxTxBytes)
+	proxyAppConn.SetResponseCallback(mempool.globalCb)
+	for _, option := range options {
+		option(mempool)
+	}
+	return mempool
+}
+
+// WithPriorityPreCheck sets a filter for the mempool to reject a tx if f(tx)
+// returns false. This is ran before CheckTx.
+func WithPriorityPreCheck(f PreCheckFunc) PriorityMempoolOption {
+	return func(mem *PriorityMempool) { mem.preCheck = f }
+}
+
+func (mem *PriorityMempool) Flush() {
+	mem.mtx.Lock()
+	defer mem.mtx.Unlock()
+
+	mem.flush()
+
+	mem.idxMtx.Lock()
+	mem.senders = make(map[string][]*clist.CElement)
+	mem.evictable = nil
+	mem.idxMtx.Unlock()
+
+	// the changes of the removed txs are still in the CheckTx state.
+	atomic.StoreInt32(&mem.staleCheckState, 1)
+}
+
+// It blocks if we're waiting on Update() or Reap().
+// cb: A callback from the CheckTx command.
+//
+//	It gets called from another goroutine.
+//
+// CONTRACT: Either cb will get called, or err returned.
+func (mem *PriorityMempool) CheckTx(tx types.Tx, cb func(abci.Response)) (err error) {
+	return mem.CheckTxWithInfo(tx, cb, TxInfo{SenderID: UnknownPeerID})
+}
+
+func (mem *PriorityMempool) CheckTxWithInfo(tx types.Tx, cb func(abci.Response), txInfo TxInfo) (err error) {
+	mem.mtx.Lock()
+	// use defer to unlock mutex because application (*local client*) might panic
+	defer mem.mtx.Unlock()
+
+	// Check max pending txs bytes.
+	// NOTE: when the mempool is full, txs with a low priority are only
+	// evicted once the priority of tx is known, after CheckTx.
+	if mem.config.Size == 0 || int64(len(tx)) > mem.config.MaxPendingTxsBytes {
+		return MempoolIsFullError{
+			mem.Size(), mem.config.Size,
+			mem.TxsBytes(), mem.config.MaxPendingTxsBytes,
+		}
+	}
+
+	if err = mem.checkTxLocal(tx, txInfo); err != nil {
+		return err
+	}
+
+	if atomic.LoadInt32(&mem.staleCheckState) == 1 {
+		// Wait for the responses of the txs being checked, as their changes
+		// would be discarded, before resetting the CheckTx state.
+		if err = mem.proxyAppConn.FlushSync(); err != nil {
+			return err
+		}
+		atomic.StoreInt32(&mem.staleCheckState, 0)
+		mem.logger.Info("Reset check state", "numtxs", mem.Size())
+		mem.resetCheckState()
+	}
+
+	// The tx may replace a pending tx of its sender.
+	reqRes := mem.proxyAppConn.CheckTxAsync(abci.RequestCheckTx{
+		Tx:   tx,
+		Type: abci.CheckTxTypeReplace,
+	})
+	reqRes.SetCallback(mem.reqResCb(tx, txInfo.SenderID, cb))
+
+	return nil
+}
+
+// Global callback that will be called after every ABCI response.
+// If we're not in the midst of a recheck, this function will just return,
+// so the request specific callback can do the work.
+// When rechecking, we don't need the peerID, so the recheck callback happens here.
+func (mem *PriorityMempool) globalCb(req abci.Request, res abci.Response) {
+	if mem.recheckTxs == nil {
+		return
+	} else {
+		mem.resCbRecheck(req, res)
+	}
+}
+
+// Request specific callback that should be set on individual reqRes objects
+// to incorporate local information when processing the response.
+//
+// External callers of CheckTx, like the RPC, can also pass an externalCb
+// through here that is called when all other response processing is complete;
+// the response is given an error if the tx was not added to the mempool.
+func (mem *PriorityMempool) reqResCb(tx []byte, peerID uint16, externalCb func(abci.Response)) func(res abci.Response) {
+	return func(res abci.Response) {
+		if mem.recheckTxs != nil {
+			// this should never happen
+			panic("recheck txs is not nil in reqResCb")
+		}
+
+		err := mem.resCbFirstTime(tx, peerID, res)
+
+		// Passed in by the caller of CheckTx, eg. the RPC.
+		if externalCb != nil {
+			if res, ok := res.(abci.ResponseCheckTx); ok && err != nil {
+				res.Error = abci.StringError(err.Error())
+				res.Log = err.Error()
+				externalCb(res)
+				return
+			}
+			externalCb(res)
+		}
+	}
+}
+
+// callback, which is called after the app checked the tx for the first time.
+// It returns an error if the tx was accepted by the app, but not added to the
+// mempool.
+//
+// The case where the app checks the tx for the second and subsequent times is
+// handled by the resCbRecheck callback.
+func (mem *PriorityMempool) resCbFirstTime(tx []byte, peerID uint16, res abci.Response) error {
+	switch res := res.(type) {
+	case abci.ResponseCheckTx:
+		if res.Error != nil {
+			// ignore bad transaction
+			mem.logger.Info("Rejected bad transaction", "tx", txID(tx), "res", res, "err", res.Error)
+			// remove from cache (it might be good later)
+			mem.cache.Remove(tx)
+			return nil
+		}
+
+		memTx := &mempoolTx{
+			height:    mem.height,
+			gasWanted: res.GasWanted,
+			tx:        tx,
+			priority:  res.Priority,
+			sender:    res.Sender,
+			sequence:  res.Sequence,
+		}
+		memTx.senders.Store(peerID, true)
+		if err := mem.addTx(memTx); err != nil {
+			mem.logger.Info("Rejected transaction", "tx", txID(tx), "res", res, "err", err)
+			if _, ok := err.(MempoolIsFullError); ok {
+				// remove from cache (it might be good later)
+				mem.cache.Remove(tx)
+			}
+			// the tx was accepted by the app, so its changes are in the
+			// CheckTx state.
+			atomic.StoreInt32(&mem.staleCheckState, 1)
+			return err
+		}
+		mem.logger.Info("Added good transaction",
+			"tx", txID(tx),
+			"res", res,
+			"height", memTx.height,
+			"total", mem.Size(),
+		)
+		mem.notifyTxsAvailable()
+	default:
+		// ignore other messages
+	}
+	return nil
+}
+
+// addTx adds memTx to the mempool, replacing the pending tx of its sender with
+// the same sequence, and evicting the txs with the lowest priority if the
+// mempool is full.
+//
+// Called from:
+//   - resCbFirstTime (lock not held) if tx is valid
+func (mem *PriorityMempool) addTx(memTx *mempoolTx) error {
+	mem.idxMtx.Lock()
+	defer mem.idxMtx.Unlock()
+
+	// Find the position of the tx among the txs of its sender.
+	var (
+		senderTxs []*clist.CElement
+		idx       int
+		replaced  *clist.CElement
+	)
+	if memTx.sender != "" {
+		senderTxs = mem.senders[memTx.sender]
+		idx = sort.Search(len(senderTxs), func(i int) bool {
+			return senderTxs[i].Value.(*mempoolTx).sequence >= memTx.sequence
+		})
+		switch {
+		case idx < len(senderTxs) && senderTxs[idx].Value.(*mempoolTx).sequence == memTx.sequence:
+			replaced = senderTxs[idx]
+			if memTx.priority <= replaced.Value.(*mempoolTx).priority {
+				return ErrTxUnderpriced
+			}
+		case len(senderTxs) > 0 && idx != len(senderTxs):
+			// the pending txs of the sender are already included.
+			return ErrTxSequenceGap
+		case len(senderTxs) > 0 && memTx.sequence != senderTxs[idx-1].Value.(*mempoolTx).sequence+1:
+			// the previous tx may have been evicted.
+			return ErrTxSequenceGap
+		}
+	}
+
+	// Make room for the tx.
+	size, txsBytes := mem.Size(), mem.TxsBytes()
+	if replaced != nil {
+		size--
+		txsBytes -= int64(len(replaced.Value.(*mempoolTx).tx))
+	}
+	evicted, ok := mem.lowestPriorityTxs(memTx, size, txsBytes)
+	if !ok {
+		return MempoolIsFullError{
+			mem.Size(), mem.config.Size,
+			mem.TxsBytes(), mem.config.MaxPendingTxsBytes,
+		}
+	}
+	for _, e := range evicted {
+		evictedTx := e.Value.(*mempoolTx)
+		mem.logger.Info("Evicted transaction", "tx", txID(evictedTx.tx), "priority", evictedTx.priority)
+		// NOTE: we remove tx from the cache because it might be added later
+		mem.removeTx(evictedTx.tx, e, true)
+	}
+	if replaced != nil {
+		// the app undid the changes of the replaced tx.
+		replacedTx := replaced.Value.(*mempoolTx)
+		mem.logger.Info("Replaced transaction", "tx", txID(replacedTx.tx), "by", txID(memTx.tx))
+		mem.removeTx(replacedTx.tx, replaced, false)
+	}
+	if len(evicted) > 0 {
+		// the changes of the evicted txs are still in the CheckTx state.
+		atomic.StoreInt32(&mem.staleCheckState, 1)
+	}
+
+	memTx.id = mem.nextID
+	memTx.evictIdx = -1
+	mem.nextID++
+	e := mem.pushTx(memTx)
+	if memTx.sender == "" {
+		heap.Push(&mem.evictable, e)
+		return nil
+	}
+
+	// NOTE: the evicted and replaced txs were removed from senders.
+	senderTxs = mem.senders[memTx.sender]
+	idx = sort.Search(len(senderTxs), func(i int) bool {
+		return senderTxs[i].Value.(*mempoolTx).sequence >= memTx.sequence
+	})
+	senderTxs = append(senderTxs, nil)
+	copy(senderTxs[idx+1:], senderTxs[idx:])
+	senderTxs[idx] = e
+	mem.senders[memTx.sender] = senderTxs
+	if idx == len(senderTxs)-1 {
+		// the tx is the new last pending tx of its sender.
+		if idx > 0 {
+			mem.evictable.remove(senderTxs[idx-1])
+		}
+		heap.Push(&mem.evictable, e)
+	}
+
+	return nil
+}
+
+// lowestPriorityTxs returns the txs with the lowest priority, lower than the
+// priority of memTx, to evict for memTx to fit in a mempool holding size txs
+// of txsBytes, or false if it can't fit. Only the last pending tx of a sender
+// can be evicted, and not if it precedes memTx; once evicted, the previous tx
+// of the sender can be evicted too.
+//
+// The evictable txs are taken out of their heap by increasing priority, and
+// the heap is restored before returning, as the txs are not removed yet.
+// NOTE: idxMtx must be held.
+func (mem *PriorityMempool) lowestPriorityTxs(memTx *mempoolTx, size int, txsBytes int64) ([]*clist.CElement, bool) {
+	var (
+		evicted []*clist.CElement
+		popped  []*clist.CElement                // evictable txs, pushed back
+		pushed  = make(map[*clist.CElement]bool) // previous txs of the evicted txs
+		skipped = make(map[string]int)           // number of evicted txs by sender
+	)
+	defer func() {
+		for e := range pushed {
+			mem.evictable.remove(e)
+		}
+		for _, e := range popped {
+			heap.Push(&mem.evictable, e)
+		}
+	}()
+
+	for size >= mem.config.Size || int64(len(memTx.tx))+txsBytes > mem.config.MaxPendingTxsBytes {
+		if len(mem.evictable) == 0 {
+			return nil, false
+		}
+		e := mem.evictable[0]
+		candidate := e.Value.(*mempoolTx)
+		if candidate.priority >= memTx.priority {
+			return nil, false
+		}
+		heap.Pop(&mem.evictable)
+		if !pushed[e] {
+			popped = append(popped, e)
+		}
+		if candidate.sender != "" && candidate.sender == memTx.sender {
+			// the txs of the sender precede memTx, or are replaced.
+			continue
+		}
+
+		evicted = append(evicted, e)
+		size--
+		txsBytes -= int64(len(candidate.tx))
+		if candidate.sender != "" {
+			skipped[candidate.sender]++
+			senderTxs := mem.senders[candidate.sender]
+			if prev := len(senderTxs) - 1 - skipped[candidate.sender]; prev >= 0 {
+				heap.Push(&mem.evictable, senderTxs[prev])
+				pushed[senderTxs[prev]] = true
+			}
+		}
+	}
+	return evicted, true
+}
+
+// Called from:
+//   - addTx (idxMtx held) if tx was replaced or evicted
+//   - Update (lock held) if tx was committed
+//   - resCbRecheck (lock not held) if tx was invalidated
+//
+// NOTE: idxMtx must be held.
+func (mem *PriorityMempool) removeTx(tx types.Tx, elem *clist.CElement, removeFromCache bool) {
+	mem.baseMempool.removeTx(tx, elem, removeFromCache)
+	mem.evictable.remove(elem)
+
+	if sender := elem.Value.(*mempoolTx).sender; sender != "" {
+		senderTxs := mem.senders[sender]
+		for i, e := range senderTxs {
+			if e == elem {
+				senderTxs = append(senderTxs[:i], senderTxs[i+1:]...)
+				break
+			}
+		}
+		if len(senderTxs) == 0 {
+			delete(mem.senders, sender)
+		} else {
+			mem.senders[sender] = senderTxs
+			// the previous tx may be the new last pending tx.
+			if last := senderTxs[len(senderTxs)-1]; last.Value.(*mempoolTx).evictIdx < 0 {
+				heap.Push(&mem.evictable, last)
+			}
+		}
+	}
+}
+
+// callback, which is called after the app rechecked the tx.
+//
+// The case where the app checks the tx for the first time is handled by the
+// resCbFirstTime callback.
+func (mem *PriorityMempool) resCbRecheck(req abci.Request, res abci.Response) {
+	switch res := res.(type) {
+	case abci.ResponseCheckTx:
+		tx := req.(abci.RequestCheckTx).Tx
+		elem := mem.recheckTxs[mem.recheckCursor]
+		memTx := elem.Value.(*mempoolTx)
+		if !bytes.Equal(tx, memTx.tx) {
+			panic(fmt.Sprintf(
+				"Unexpected tx response from proxy during recheck\nExpected %X, got %X",
+				memTx.tx,
+				tx))
+		}
+		if res.Error == nil {
+			// Good, nothing to do.
+		} else {
+			// Tx became invalidated due to newly committed block.
+			mem.logger.Info("Tx is no longer valid", "tx", txID(tx), "res", res, "err", res.Error)
+			// NOTE: we remove tx from the cache because it might be good later
+			mem.idxMtx.Lock()
+			mem.removeTx(tx, elem, true)
+			mem.idxMtx.Unlock()
+		}
+		mem.recheckCursor++
+		if mem.recheckCursor == len(mem.recheckTxs) {
+			// Done!
+			mem.recheckTxs = nil
+			mem.recheckCursor = 0
+			atomic.StoreInt32(&mem.rechecking, 0)
+			mem.logger.Info("Done rechecking txs")
+
+			// incase the recheck removed all txs
+			if mem.Size() > 0 {
+				mem.notifyTxsAvailable()
+			}
+		}
+	default:
+		// ignore other messages
+	}
+}
+
+func (mem *PriorityMempool) ReapMaxBytesMaxGas(maxDataBytes, maxGas int64) types.Txs {
+	mem.mtx.Lock()
+	defer mem.mtx.Unlock()
+
+	if maxDataBytes == 0 {
+		panic("ReapMaxBytesMaxGas requires maxDataBytes > 0")
+	}
+
+	mem.waitRecheck()
+
+	var totalBytes int64
+	var totalGas int64
+	elems := mem.orderedTxs()
+	txs := make([]types.Tx, 0, len(elems))
+	for _, e := range elems {
+		memTx := e.Value.(*mempoolTx)
+		// Check total size requirement
+		if maxDataBytes > -1 && totalBytes+int64(len(memTx.tx)) > maxDataBytes {
+			return txs
+		}
+		totalBytes += int64(len(memTx.tx))
+		// Check total gas requirement.
+		// If maxGas is negative, skip this check.
+		newTotalGas := totalGas + memTx.gasWanted
+		if maxGas > -1 && newTotalGas > maxGas {
+			return txs
+		}
+		totalGas = newTotalGas
+		txs = append(txs, memTx.tx)
+	}
+	return txs
+}
+
+func (mem *PriorityMempool) ReapMaxTxs(maxVal int) types.Txs {
+	mem.mtx.Lock()
+	defer mem.mtx.Unlock()
+
+	mem.waitRecheck()
+
+	elems := mem.orderedTxs()
+	if maxVal < 0 || maxVal > len(elems) {
+		maxVal = len(elems)
+	}
+	txs := make([]types.Tx, 0, maxVal)
+	for _, e := range elems[:maxVal] {
+		txs = append(txs, e.Value.(*mempoolTx).tx)
+	}
+	return txs
+}
+
+// orderedTxs returns the txs of the mempool by decreasing priority, keeping
+// the txs of the same sender ordered by sequence: the next tx is the one with
+// the highest priority among the first pending txs of each sender.
+func (mem *PriorityMempool) orderedTxs() []*clist.CElement {
+	mem.idxMtx.RLock()
+	defer mem.idxMtx.RUnlock()
+
+	return mem.orderedTxsLocked()
+}
+
+// NOTE: idxMtx must be held.
+func (mem *PriorityMempool) orderedTxsLocked() []*clist.CElement {
+	queues := make(txQueues, 0, len(mem.senders))
+	for _, senderTxs := range mem.senders {
+		queues = append(queues, senderTxs)
+	}
+	for e := mem.txs.Front(); e != nil; e = e.Next() {
+		if e.Value.(*mempoolTx).sender == "" {
+			queues = append(queues, []*clist.CElement{e})
+		}
+	}
+	heap.Init(&queues)
+
+	elems := make([]*clist.CElement, 0, mem.txs.Len())
+	for len(queues) > 0 {
+		elems = append(elems, queues[0][0])
+		if queues[0] = queues[0][1:]; len(queues[0]) == 0 {
+			heap.Pop(&queues)
+		} else {
+			heap.Fix(&queues, 0)
+		}
+	}
+	return elems
+}
+
+func (mem *PriorityMempool) Update(
+	height int64,
+	txs types.Txs,
+	deliverTxResponses []abci.ResponseDeliverTx,
+	preCheck PreCheckFunc,
+	maxTxBytes int64,
+) error {
+	mem.idxMtx.Lock()
+	mem.update(height, txs, deliverTxResponses, preCheck, maxTxBytes, func(tx types.Tx, e *clist.CElement) {
+		mem.removeTx(tx, e, false)
+	})
+	mem.idxMtx.Unlock()
+
+	// The CheckTx state was reset on Commit.
+	atomic.StoreInt32(&mem.staleCheckState, 0)
+
+	// Either recheck non-committed txs to see if they became invalid
+	// or just notify there're some txs left.
+	if mem.Size() > 0 {
+		if mem.config.Recheck {
+			mem.logger.Info("Recheck txs", "numtxs", mem.Size(), "height", height)
+			mem.recheckAllTxs(false)
+			// At this point, mem.txs are being rechecked.
+			// Before mem.Reap(), we should wait for mem.recheckTxs to be nil.
+		} else {
+			mem.notifyTxsAvailable()
+		}
+	}
+
+	return nil
+}
+
+// resetCheckState resets the CheckTx state of the app to the last committed
+// state, and rechecks the txs of the mempool, so that the state only has the
+// changes of these txs.
+// NOTE: lock must be held.
+func (mem *PriorityMempool) resetCheckState() {
+	mem.recheckAllTxs(true)
+}
+
+// recheckAllTxs rechecks the txs in the order they are reaped, so that the txs
+// of the same sender are rechecked by sequence. If reset is true, the CheckTx
+// state is reset before, even if there are no txs to recheck.
+func (mem *PriorityMempool) recheckAllTxs(reset bool) {
+	recheckTxs := make([]*clist.CElement, 0, mem.Size())
+	mem.idxMtx.Lock()
+	for _, e := range mem.orderedTxsLocked() {
+		memTx := e.Value.(*mempoolTx)
+		if !mem.checkRecheckTx(memTx) {
+			mem.removeTx(memTx.tx, e, false)
+			continue
+		}
+		recheckTxs = append(recheckTxs, e)
+	}
+	mem.idxMtx.Unlock()
+	if len(recheckTxs) == 0 {
+		if reset {
+			mem.proxyAppConn.CheckTxAsync(abci.RequestCheckTx{Type: abci.CheckTxTypeReset})
+		}
+		return
+	}
+
+	atomic.StoreInt32(&mem.rechecking, 1)
+	mem.recheckTxs = recheckTxs
+	mem.recheckCursor = 0
+
+	// Push txs to proxyAppConn
+	// NOTE: globalCb may be called concurrently.
+	for i, e := range recheckTxs {
+		var typ abci.CheckTxType = abci.CheckTxTypeRecheck
+		if reset && i == 0 {
+			typ = abci.CheckTxTypeReset
+		}
+		mem.proxyAppConn.CheckTxAsync(abci.RequestCheckTx{
+			Tx:   e.Value.(*mempoolTx).tx,
+			Type: typ,
+		})
+	}
+
+	mem.proxyAppConn.FlushAsync()
+}
+
+// --------------------------------------------------------------------------------
+
+// txQueues is a heap of the pending txs of each sender, ordered by sequence,
+// by decreasing priority of their first tx.
+type txQueues [][]*clist.CElement
+
+var _ heap.Interface = (*txQueues)(nil)
+
+func (q txQueues) Len() int { return len(q) }
+
+func (q txQueues) Less(i, j int) bool {
+	txi, txj := q[i][0].Value.(*mempoolTx), q[j][0].Value.(*mempoolTx)
+	if txi.priority != txj.priority {
+		return txi.priority > txj.priority
+	}
+	return txi.id < txj.id
+}
+
+func (q txQueues) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+
+func (q *txQueues) Push(x any) { *q = append(*q, x.([]*clist.CElement)) }
+
+func (q *txQueues) Pop() any {
+	old := *q
+	n := len(old)
+	x := old[n-1]
+	*q = old[:n-1]
+	return x
+}
+
+// evictableTxs is a heap of the txs which can be evicted from the mempool, by
+// increasing priority, and by arrival for the txs of equal priority. The txs
+// keep their index in the heap.
+type evictableTxs []*clist.CElement
+
+var _ heap.Interface = (*evictableTxs)(nil)
+
+func (q evictableTxs) Len() int { return len(q) }
+
+func (q evictableTxs) Less(i, j int) bool {
+	txi, txj := q[i].Value.(*mempoolTx), q[j].Value.(*mempoolTx)
+	if txi.priority != txj.priority {
+		return txi.priority < txj.priority
+	}
+	return txi.id < txj.id
+}
+
+func (q evictableTxs) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+	q[i].Value.(*mempoolTx).evictIdx = i
+	q[j].Value.(*mempoolTx).evictIdx = j
+}
+
+func (q *evictableTxs) Push(x any) {
+	e := x.(*clist.CElement)
+	e.Value.(*mempoolTx).evictIdx = len(*q)
+	*q = append(*q, e)
+}
+
+func (q *evictableTxs) Pop() any {
+	old := *q
+	n := len(old)
+	x := old[n-1]
+	x.Value.(*mempoolTx).evictIdx = -1
+	*q = old[:n-1]
+	return x
+}
+
+// remove removes e from the heap, if it is in it.
+func (q *evictableTxs) remove(e *clist.CElement) {
+	if idx := e.Value.(*mempoolTx).evictIdx; idx >= 0 {
+		heap.Remove(q, idx)
+	}
+}
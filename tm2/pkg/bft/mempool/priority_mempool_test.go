@@ -0,0 +1,359 @@
+package mempool
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	cfg "github.com/gnolang/gno/tm2/pkg/bft/mempool/config"
+	"github.com/gnolang/gno/tm2/pkg/bft/proxy"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/log"
+)
+
+// priorityApp is an application checking txs of the form
+// "sender/sequence/priority[/data]", which are invalid once a tx of the same
+// sender and sequence has been committed. On CheckTxTypeReplace, a tx with the
+// sender and sequence of a checked tx replaces it if its priority is higher.
+type priorityApp struct {
+	abci.BaseApplication
+
+	committed map[string]uint64 // sender -> next sequence
+	checked   []string          // txs accepted since the last reset
+	resets    int               // number of CheckTxTypeReset requests
+	rechecks  int               // number of CheckTxTypeRecheck requests
+}
+
+func newPriorityApp() *priorityApp {
+	return &priorityApp{committed: make(map[string]uint64)}
+}
+
+// parsePriorityTx returns the sender, sequence and priority of tx.
+func parsePriorityTx(tx string) (string, uint64, int64, error) {
+	parts := strings.Split(tx, "/")
+	if len(parts) < 3 {
+		return "", 0, 0, errors.New("invalid tx")
+	}
+	sequence, err := strconv.ParseUint(parts[1], 10, 64)
+	if err != nil {
+		return "", 0, 0, err
+	}
+	priority, err := strconv.ParseInt(parts[2], 10, 64)
+	if err != nil {
+		return "", 0, 0, err
+	}
+	return parts[0], sequence, priority, nil
+}
+
+func (app *priorityApp) CheckTx(req abci.RequestCheckTx) abci.ResponseCheckTx {
+	switch req.Type {
+	case abci.CheckTxTypeReset:
+		app.resets++
+		app.checked = nil
+		if len(req.Tx) == 0 {
+			return abci.ResponseCheckTx{}
+		}
+	case abci.CheckTxTypeRecheck:
+		app.rechecks++
+	}
+	sender, sequence, priority, err := parsePriorityTx(string(req.Tx))
+	if err != nil {
+		return abci.ResponseCheckTx{ResponseBase: abci.ResponseBase{Error: abci.StringError(err.Error())}}
+	}
+	if sequence < app.committed[sender] {
+		return abci.ResponseCheckTx{ResponseBase: abci.ResponseBase{Error: abci.StringError("invalid sequence")}}
+	}
+	res := abci.ResponseCheckTx{
+		GasWanted: 1,
+		Priority:  priority,
+		Sender:    sender,
+		Sequence:  sequence,
+	}
+	if req.Type == abci.CheckTxTypeReplace {
+		for i, checked := range app.checked {
+			if s, seq, p, _ := parsePriorityTx(checked); s == sender && seq == sequence {
+				if priority <= p {
+					return abci.ResponseCheckTx{ResponseBase: abci.ResponseBase{
+						Error: abci.StringError(ErrTxUnderpriced.Error()),
+						Log:   ErrTxUnderpriced.Error(),
+					}}
+				}
+				app.checked[i] = string(req.Tx)
+				return res
+			}
+		}
+	}
+	app.checked = append(app.checked, string(req.Tx))
+	return res
+}
+
+func newPriorityMempoolWithAppAndConfig(cc proxy.ClientCreator, config *cfg.MempoolConfig) (*PriorityMempool, cleanupFunc) {
+	appConnMem, _ := cc.NewABCIClient()
+	appConnMem.SetLogger(log.NewNoopLogger().With("module", "abci-client", "connection", "mempool"))
+	err := appConnMem.Start()
+	if err != nil {
+		panic(err)
+	}
+	mempool := NewPriorityMempool(config, appConnMem, 0, testMaxTxBytes)
+	mempool.SetLogger(log.NewNoopLogger())
+	return mempool, func() {
+		if config.RootDir != "" {
+			os.RemoveAll(config.RootDir)
+		}
+	}
+}
+
+// checkPriorityTx checks tx, and returns the error of CheckTx or the error
+// returned to the callback.
+func checkPriorityTx(t *testing.T, mempool *PriorityMempool, tx string) error {
+	t.Helper()
+
+	var resErr error
+	err := mempool.CheckTx(types.Tx(tx), func(res abci.Response) {
+		if res.(abci.ResponseCheckTx).Error != nil {
+			resErr = fmt.Errorf("%s", res.(abci.ResponseCheckTx).Log)
+		}
+	})
+	if err != nil {
+		return err
+	}
+	return resErr
+}
+
+func reapedTxs(mempool *PriorityMempool) []string {
+	var txs []string
+	for _, tx := range mempool.ReapMaxTxs(-1) {
+		txs = append(txs, string(tx))
+	}
+	return txs
+}
+
+func TestPriorityMempoolReapOrder(t *testing.T) {
+	t.Parallel()
+
+	cc := proxy.NewLocalClientCreator(newPriorityApp())
+	mempool, cleanup := newPriorityMempoolWithAppAndConfig(cc, cfg.TestMempoolConfig())
+	defer cleanup()
+
+	for _, tx := range []string{
+		"alice/0/10",
+		"alice/1/50",
+		"bob/0/20",
+		"carol/0/20",
+		"bob/1/5",
+		"alice/2/1",
+	} {
+		require.NoError(t, checkPriorityTx(t, mempool, tx), tx)
+	}
+
+	// the txs of each sender are ordered by sequence; equal priorities are
+	// ordered by arrival.
+	assert.Equal(t, []string{
+		"bob/0/20",
+		"carol/0/20",
+		"alice/0/10",
+		"alice/1/50",
+		"bob/1/5",
+		"alice/2/1",
+	}, reapedTxs(mempool))
+
+	txs := mempool.ReapMaxBytesMaxGas(-1, 2)
+	assert.Equal(t, types.Txs{types.Tx("bob/0/20"), types.Tx("carol/0/20")}, txs)
+}
+
+func TestPriorityMempoolReplaceByFee(t *testing.T) {
+	t.Parallel()
+
+	cc := proxy.NewLocalClientCreator(newPriorityApp())
+	mempool, cleanup := newPriorityMempoolWithAppAndConfig(cc, cfg.TestMempoolConfig())
+	defer cleanup()
+
+	require.NoError(t, checkPriorityTx(t, mempool, "alice/0/10"))
+	require.NoError(t, checkPriorityTx(t, mempool, "alice/1/10"))
+
+	// same priority
+	err := checkPriorityTx(t, mempool, "alice/1/10/replace")
+	assert.ErrorContains(t, err, ErrTxUnderpriced.Error())
+
+	// higher priority
+	require.NoError(t, checkPriorityTx(t, mempool, "alice/1/11/replace"))
+	assert.Equal(t, []string{"alice/0/10", "alice/1/11/replace"}, reapedTxs(mempool))
+	assert.Equal(t, int64(len("alice/0/10")+len("alice/1/11/replace")), mempool.TxsBytes())
+
+	// the replaced tx stays in the cache.
+	assert.ErrorIs(t, checkPriorityTx(t, mempool, "alice/1/10"), ErrTxInCache)
+}
+
+func TestPriorityMempoolUnderpricedReplacements(t *testing.T) {
+	t.Parallel()
+
+	app := newPriorityApp()
+	cc := proxy.NewLocalClientCreator(app)
+	mempool, cleanup := newPriorityMempoolWithAppAndConfig(cc, cfg.TestMempoolConfig())
+	defer cleanup()
+
+	require.NoError(t, checkPriorityTx(t, mempool, "alice/0/10"))
+	require.NoError(t, checkPriorityTx(t, mempool, "alice/1/10"))
+
+	// the underpriced replacements are rejected by the app, without changing
+	// its state, nor resetting it.
+	for i := 0; i < 10; i++ {
+		err := checkPriorityTx(t, mempool, fmt.Sprintf("alice/1/10/%d", i))
+		assert.ErrorContains(t, err, ErrTxUnderpriced.Error())
+	}
+	// neither do the replacements.
+	require.NoError(t, checkPriorityTx(t, mempool, "alice/1/11/replace"))
+	require.NoError(t, checkPriorityTx(t, mempool, "bob/0/10"))
+	assert.Equal(t, 0, app.resets)
+	assert.Equal(t, 0, app.rechecks)
+	assert.Equal(t, []string{"alice/0/10", "alice/1/11/replace", "bob/0/10"}, app.checked)
+	assert.Equal(t, []string{"alice/0/10", "alice/1/11/replace", "bob/0/10"}, reapedTxs(mempool))
+}
+
+func TestPriorityMempoolSequenceGap(t *testing.T) {
+	t.Parallel()
+
+	cc := proxy.NewLocalClientCreator(newPriorityApp())
+	mempool, cleanup := newPriorityMempoolWithAppAndConfig(cc, cfg.TestMempoolConfig())
+	defer cleanup()
+
+	require.NoError(t, checkPriorityTx(t, mempool, "alice/3/10"))
+	err := checkPriorityTx(t, mempool, "alice/5/10")
+	assert.ErrorContains(t, err, ErrTxSequenceGap.Error())
+	err = checkPriorityTx(t, mempool, "alice/2/10")
+	assert.ErrorContains(t, err, ErrTxSequenceGap.Error())
+	require.NoError(t, checkPriorityTx(t, mempool, "alice/4/10"))
+
+	// the rejected txs stay in the cache.
+	assert.ErrorIs(t, checkPriorityTx(t, mempool, "alice/5/10"), ErrTxInCache)
+	require.NoError(t, checkPriorityTx(t, mempool, "alice/5/10/retry"))
+	assert.Equal(t, 3, mempool.Size())
+}
+
+func TestPriorityMempoolEviction(t *testing.T) {
+	t.Parallel()
+
+	config := cfg.TestMempoolConfig()
+	config.Size = 3
+	cc := proxy.NewLocalClientCreator(newPriorityApp())
+	mempool, cleanup := newPriorityMempoolWithAppAndConfig(cc, config)
+	defer cleanup()
+
+	require.NoError(t, checkPriorityTx(t, mempool, "alice/0/1"))
+	require.NoError(t, checkPriorityTx(t, mempool, "alice/1/20"))
+	require.NoError(t, checkPriorityTx(t, mempool, "bob/0/5"))
+
+	// not higher than the lowest evictable tx (bob/0/5): alice/0/1 is not
+	// evictable, as alice/1/20 depends on it.
+	err := checkPriorityTx(t, mempool, "carol/0/5")
+	assert.ErrorContains(t, err, "mempool is full")
+
+	require.NoError(t, checkPriorityTx(t, mempool, "carol/0/6"))
+	assert.Equal(t, []string{"carol/0/6", "alice/0/1", "alice/1/20"}, reapedTxs(mempool))
+
+	// the evicted tx can be checked again.
+	err = checkPriorityTx(t, mempool, "bob/0/5")
+	assert.ErrorContains(t, err, "mempool is full")
+
+	require.NoError(t, checkPriorityTx(t, mempool, "alice/2/100"))
+	assert.Equal(t, []string{"alice/0/1", "alice/1/20", "alice/2/100"}, reapedTxs(mempool))
+
+	// the txs of the same sender are never evicted for a new tx.
+	err = checkPriorityTx(t, mempool, "alice/3/200")
+	assert.ErrorContains(t, err, "mempool is full")
+}
+
+func TestPriorityMempoolEvictionBySize(t *testing.T) {
+	t.Parallel()
+
+	config := cfg.TestMempoolConfig()
+	config.MaxPendingTxsBytes = 40
+	cc := proxy.NewLocalClientCreator(newPriorityApp())
+	mempool, cleanup := newPriorityMempoolWithAppAndConfig(cc, config)
+	defer cleanup()
+
+	for _, tx := range []string{"alice/0/1", "alice/1/2", "bob/0/50"} {
+		require.NoError(t, checkPriorityTx(t, mempool, tx), tx)
+	}
+	before := reapedTxs(mempool)
+
+	// evicting the txs of alice isn't enough, the mempool is left unchanged.
+	err := checkPriorityTx(t, mempool, "carol/0/10/"+strings.Repeat("x", 28))
+	assert.ErrorContains(t, err, "mempool is full")
+	assert.Equal(t, before, reapedTxs(mempool))
+
+	// once alice/1 is evicted, alice/0 is evicted too.
+	carol := "carol/0/10/" + strings.Repeat("x", 15)
+	require.NoError(t, checkPriorityTx(t, mempool, carol))
+	assert.Equal(t, []string{"bob/0/50", carol}, reapedTxs(mempool))
+
+	// the lowest priority tx is then carol/0.
+	require.NoError(t, checkPriorityTx(t, mempool, "dave/0/60"))
+	assert.Equal(t, []string{"dave/0/60", "bob/0/50"}, reapedTxs(mempool))
+}
+
+func TestPriorityMempoolUpdate(t *testing.T) {
+	t.Parallel()
+
+	app := newPriorityApp()
+	cc := proxy.NewLocalClientCreator(app)
+	mempool, cleanup := newPriorityMempoolWithAppAndConfig(cc, cfg.TestMempoolConfig())
+	defer cleanup()
+
+	for _, tx := range []string{"alice/0/10", "alice/1/10", "bob/0/10", "bob/1/10"} {
+		require.NoError(t, checkPriorityTx(t, mempool, tx), tx)
+	}
+
+	// alice/0 is committed, and bob/0 and bob/1 are invalidated by another tx
+	// of bob.
+	app.committed["alice"] = 1
+	app.committed["bob"] = 2
+	committed := types.Txs{types.Tx("alice/0/10")}
+	require.NoError(t, mempool.Update(1, committed, abciResponses(len(committed), nil), nil, 0))
+	assert.Equal(t, []string{"alice/1/10"}, reapedTxs(mempool))
+
+	// the next txs of the senders can be added.
+	require.NoError(t, checkPriorityTx(t, mempool, "alice/2/10"))
+	require.NoError(t, checkPriorityTx(t, mempool, "bob/2/10"))
+	assert.Equal(t, 3, mempool.Size())
+}
+
+func TestPriorityMempoolResetCheckState(t *testing.T) {
+	t.Parallel()
+
+	config := cfg.TestMempoolConfig()
+	config.Size = 2
+	app := newPriorityApp()
+	cc := proxy.NewLocalClientCreator(app)
+	mempool, cleanup := newPriorityMempoolWithAppAndConfig(cc, config)
+	defer cleanup()
+
+	require.NoError(t, checkPriorityTx(t, mempool, "alice/0/10"))
+	require.NoError(t, checkPriorityTx(t, mempool, "bob/0/10"))
+
+	// the rejected tx was accepted by the app.
+	err := checkPriorityTx(t, mempool, "carol/0/1")
+	assert.ErrorContains(t, err, "mempool is full")
+	assert.Equal(t, []string{"alice/0/10", "bob/0/10", "carol/0/1"}, app.checked)
+	// the state is reset, and the pending txs rechecked, before the next tx.
+	require.NoError(t, checkPriorityTx(t, mempool, "alice/0/20/replace"))
+	assert.Equal(t, []string{"alice/0/20/replace", "bob/0/10"}, app.checked)
+	assert.Equal(t, 1, app.resets)
+
+	// the app replaced the txs in its state, which is not reset.
+	require.NoError(t, checkPriorityTx(t, mempool, "bob/0/20/replace"))
+	assert.Equal(t, []string{"alice/0/20/replace", "bob/0/20/replace"}, app.checked)
+	assert.Equal(t, 1, app.resets)
+
+	// without txs, the state is only reset.
+	mempool.Flush()
+	assert.Error(t, checkPriorityTx(t, mempool, "invalid"))
+	assert.Empty(t, app.checked)
+}
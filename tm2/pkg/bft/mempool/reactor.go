@@ -33,10 +33,27 @@ const (
 type Reactor struct {
 	p2p.BaseReactor
 	config  *cfg.MempoolConfig
-	mempool *CListMempool
+	mempool GossipMempool
 	ids     *mempoolIDs
 }
 
+// GossipMempool is a Mempool whose txs can be gossiped by the Reactor,
+// like the CListMempool and the PriorityMempool.
+type GossipMempool interface {
+	Mempool
+
+	// SetLogger sets the Logger.
+	SetLogger(*slog.Logger)
+
+	// TxsFront returns the first tx of the list of txs, whose elements are
+	// *mempoolTx, for peer goroutines to call .NextWait() on.
+	TxsFront() *clist.CElement
+
+	// TxsWaitChan returns a channel to wait on txs. It will be closed once the
+	// mempool is not empty.
+	TxsWaitChan() <-chan struct{}
+}
+
 type mempoolIDs struct {
 	mtx       sync.RWMutex
 	peerMap   map[p2p.ID]uint16
@@ -101,7 +118,7 @@ func newMempoolIDs() *mempoolIDs {
 }
 
 // NewReactor returns a new Reactor with the given config and mempool.
-func NewReactor(config *cfg.MempoolConfig, mempool *CListMempool) *Reactor {
+func NewReactor(config *cfg.MempoolConfig, mempool GossipMempool) *Reactor {
 	memR := &Reactor{
 		config:  config,
 		mempool: mempool,
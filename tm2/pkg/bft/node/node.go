@@ -4,6 +4,7 @@ package node
 // is enabled by the user by setting a profiling address
 
 import (
+	"context"
 	"fmt"
 	"log/slog"
 	"net"
@@ -14,6 +15,7 @@ import (
 
 	"github.com/gnolang/gno/tm2/pkg/bft/appconn"
 	"github.com/gnolang/gno/tm2/pkg/bft/state/eventstore/file"
+	"github.com/gnolang/gno/tm2/pkg/bft/state/eventstore/kv"
 	"github.com/rs/cors"
 
 	"github.com/gnolang/gno/tm2/pkg/amino"
@@ -22,6 +24,7 @@ import (
 	cfg "github.com/gnolang/gno/tm2/pkg/bft/config"
 	cs "github.com/gnolang/gno/tm2/pkg/bft/consensus"
 	mempl "github.com/gnolang/gno/tm2/pkg/bft/mempool"
+	memcfg "github.com/gnolang/gno/tm2/pkg/bft/mempool/config"
 	"github.com/gnolang/gno/tm2/pkg/bft/privval"
 	"github.com/gnolang/gno/tm2/pkg/bft/proxy"
 	rpccore "github.com/gnolang/gno/tm2/pkg/bft/rpc/core"
@@ -30,6 +33,7 @@ import (
 	sm "github.com/gnolang/gno/tm2/pkg/bft/state"
 	"github.com/gnolang/gno/tm2/pkg/bft/state/eventstore"
 	"github.com/gnolang/gno/tm2/pkg/bft/state/eventstore/null"
+	"github.com/gnolang/gno/tm2/pkg/bft/statesync"
 	"github.com/gnolang/gno/tm2/pkg/bft/store"
 	"github.com/gnolang/gno/tm2/pkg/bft/types"
 	tmtime "github.com/gnolang/gno/tm2/pkg/bft/types/time"
@@ -39,6 +43,7 @@ import (
 	"github.com/gnolang/gno/tm2/pkg/errors"
 	"github.com/gnolang/gno/tm2/pkg/events"
 	"github.com/gnolang/gno/tm2/pkg/p2p"
+	"github.com/gnolang/gno/tm2/pkg/p2p/pex"
 	"github.com/gnolang/gno/tm2/pkg/service"
 	verset "github.com/gnolang/gno/tm2/pkg/versionset"
 )
@@ -127,6 +132,7 @@ type Option func(*Node)
 //   - MEMPOOL
 //   - BLOCKCHAIN
 //   - CONSENSUS
+//   - STATESYNC
 //   - EVIDENCE
 //   - PEX
 func CustomReactors(reactors map[string]p2p.Reactor) Option {
@@ -170,6 +176,9 @@ type Node struct {
 	mempool           mempl.Mempool
 	consensusState    *cs.ConsensusState   // latest consensus state
 	consensusReactor  *cs.ConsensusReactor // for participating in the consensus
+	stateSyncReactor  *statesync.Reactor   // for bootstrapping from a snapshot
+	stateSync         bool                 // whether the node should state sync on startup
+	stateSyncGenesis  sm.State             // the genesis state, for state sync verification
 	proxyApp          appconn.AppConns     // connection to the application
 	rpcListeners      []net.Listener       // rpc servers
 	txEventStore      eventstore.TxEventStore
@@ -204,6 +213,7 @@ func createAndStartProxyAppConns(clientCreator proxy.ClientCreator, logger *slog
 
 func createAndStartEventStoreService(
 	cfg *cfg.Config,
+	dbProvider DBProvider,
 	evsw events.EventSwitch,
 	logger *slog.Logger,
 ) (*eventstore.Service, eventstore.TxEventStore, error) {
@@ -220,6 +230,17 @@ func createAndStartEventStoreService(
 		if err != nil {
 			return nil, nil, fmt.Errorf("unable to create file tx event store, %w", err)
 		}
+	case kv.EventStoreType:
+		// Transaction and block events should be indexed
+		txIndexDB, err := dbProvider(&DBContext{"tx_index", cfg})
+		if err != nil {
+			return nil, nil, fmt.Errorf("unable to open tx index database, %w", err)
+		}
+
+		txEventStore, err = kv.NewTxEventStore(cfg.TxEventStore, txIndexDB)
+		if err != nil {
+			return nil, nil, fmt.Errorf("unable to create kv tx event store, %w", err)
+		}
 	default:
 		// Transaction event storing should be omitted
 		txEventStore = null.NewNullEventStore()
@@ -271,14 +292,26 @@ func onlyValidatorIsUs(state sm.State, privVal types.PrivValidator) bool {
 
 func createMempoolAndMempoolReactor(config *cfg.Config, proxyApp appconn.AppConns,
 	state sm.State, logger *slog.Logger,
-) (*mempl.Reactor, *mempl.CListMempool) {
-	mempool := mempl.NewCListMempool(
-		config.Mempool,
-		proxyApp.Mempool(),
-		state.LastBlockHeight,
-		state.ConsensusParams.Block.MaxTxBytes,
-		mempl.WithPreCheck(sm.TxPreCheck(state)),
-	)
+) (*mempl.Reactor, mempl.GossipMempool) {
+	var mempool mempl.GossipMempool
+	switch config.Mempool.Type {
+	case memcfg.PriorityMempoolType:
+		mempool = mempl.NewPriorityMempool(
+			config.Mempool,
+			proxyApp.Mempool(),
+			state.LastBlockHeight,
+			state.ConsensusParams.Block.MaxTxBytes,
+			mempl.WithPriorityPreCheck(sm.TxPreCheck(state)),
+		)
+	default:
+		mempool = mempl.NewCListMempool(
+			config.Mempool,
+			proxyApp.Mempool(),
+			state.LastBlockHeight,
+			state.ConsensusParams.Block.MaxTxBytes,
+			mempl.WithPreCheck(sm.TxPreCheck(state)),
+		)
+	}
 	mempoolLogger := logger.With("module", "mempool")
 	mempoolReactor := mempl.NewReactor(config.Mempool, mempool)
 	mempoolReactor.SetLogger(mempoolLogger)
@@ -306,7 +339,7 @@ func createConsensusReactor(config *cfg.Config,
 	state sm.State,
 	blockExec *sm.BlockExecutor,
 	blockStore sm.BlockStore,
-	mempool *mempl.CListMempool,
+	mempool mempl.Mempool,
 	privValidator types.PrivValidator,
 	fastSync bool,
 	evsw events.EventSwitch,
@@ -393,6 +426,7 @@ func createSwitch(config *cfg.Config,
 	mempoolReactor *mempl.Reactor,
 	bcReactor p2p.Reactor,
 	consensusReactor *cs.ConsensusReactor,
+	stateSyncReactor *statesync.Reactor,
 	nodeInfo p2p.NodeInfo,
 	nodeKey *p2p.NodeKey,
 	p2pLogger *slog.Logger,
@@ -406,6 +440,7 @@ func createSwitch(config *cfg.Config,
 	sw.AddReactor("MEMPOOL", mempoolReactor)
 	sw.AddReactor("BLOCKCHAIN", bcReactor)
 	sw.AddReactor("CONSENSUS", consensusReactor)
+	sw.AddReactor("STATESYNC", stateSyncReactor)
 
 	sw.SetNodeInfo(nodeInfo)
 	sw.SetNodeKey(nodeKey)
@@ -414,6 +449,44 @@ func createSwitch(config *cfg.Config,
 	return sw
 }
 
+func createAddrBookAndSetOnSwitch(config *cfg.Config, sw *p2p.Switch,
+	p2pLogger *slog.Logger, nodeKey *p2p.NodeKey,
+) (*pex.AddrBook, error) {
+	addrBook := pex.NewAddrBook(config.P2P.AddrBookFile(), config.P2P.AddrBookStrict)
+	addrBook.SetLogger(p2pLogger.With("book", config.P2P.AddrBookFile()))
+
+	// Add ourselves to addrbook to prevent dialing ourselves
+	for _, laddr := range []string{config.P2P.ExternalAddress, config.P2P.ListenAddress} {
+		if laddr == "" {
+			continue
+		}
+
+		addr, err := p2p.NewNetAddressFromString(p2p.NetAddressString(nodeKey.ID(), laddr))
+		if err != nil {
+			return nil, errors.Wrap(err, "p2p.laddr is incorrect")
+		}
+		addrBook.AddOurAddress(addr)
+	}
+
+	sw.SetAddrBook(addrBook)
+
+	return addrBook, nil
+}
+
+func createPEXReactorAndAddToSwitch(addrBook *pex.AddrBook, config *cfg.Config,
+	sw *p2p.Switch, logger *slog.Logger,
+) (*pex.Reactor, error) {
+	pexReactor, err := pex.NewReactor(config.P2P, addrBook)
+	if err != nil {
+		return nil, err
+	}
+
+	pexReactor.SetLogger(logger.With("module", "pex"))
+	sw.AddReactor("PEX", pexReactor)
+
+	return pexReactor, nil
+}
+
 // NewNode returns a new, ready to go, Tendermint Node.
 func NewNode(config *cfg.Config,
 	privValidator types.PrivValidator,
@@ -456,16 +529,26 @@ func NewNode(config *cfg.Config,
 	})
 
 	// Transaction event storing
-	eventStoreService, txEventStore, err := createAndStartEventStoreService(config, evsw, logger)
+	eventStoreService, txEventStore, err := createAndStartEventStoreService(config, dbProvider, evsw, logger)
 	if err != nil {
 		return nil, err
 	}
 
+	// State sync is only used to bootstrap a node with no state.
+	// The app is restored from a snapshot once the node is started,
+	// so the handshake is skipped
+	stateSync := config.StateSync.Enable && state.LastBlockHeight == 0
+	if stateSync {
+		logger.Info("Found no state, state sync enabled")
+	}
+
 	// Create the handshaker, which calls RequestInfo, sets the AppVersion on the state,
 	// and replays any blocks as necessary to sync tendermint with the app.
 	consensusLogger := logger.With("module", "consensus")
-	if err := doHandshake(stateDB, state, blockStore, genDoc, evsw, proxyApp, consensusLogger); err != nil {
-		return nil, err
+	if !stateSync {
+		if err := doHandshake(stateDB, state, blockStore, genDoc, evsw, proxyApp, consensusLogger); err != nil {
+			return nil, err
+		}
 	}
 
 	// Reload the state. It will have the Version.Consensus.App set by the
@@ -506,18 +589,24 @@ func NewNode(config *cfg.Config,
 		mempool,
 	)
 
-	// Make BlockchainReactor
-	bcReactor, err := createBlockchainReactor(config, state, blockExec, blockStore, fastSync, logger)
+	// Make BlockchainReactor. Don't fast sync while state syncing,
+	// the blockchain reactor is switched to fast sync once the state is restored
+	bcReactor, err := createBlockchainReactor(config, state, blockExec, blockStore, fastSync && !stateSync, logger)
 	if err != nil {
 		return nil, errors.Wrap(err, "could not create blockchain reactor")
 	}
 
-	// Make ConsensusReactor
+	// Make ConsensusReactor. It waits for the state and fast sync
+	// to complete, before switching to consensus
 	consensusReactor, consensusState := createConsensusReactor(
 		config, state, blockExec, blockStore, mempool,
-		privValidator, fastSync, evsw, consensusLogger,
+		privValidator, fastSync || stateSync, evsw, consensusLogger,
 	)
 
+	// Make StateSyncReactor. It always serves the app snapshots to peers
+	stateSyncReactor := statesync.NewReactor(config.StateSync, proxyApp.Snapshot(), proxyApp.Query())
+	stateSyncReactor.SetLogger(logger.With("module", "statesync"))
+
 	nodeInfo, err := makeNodeInfo(config, nodeKey, txEventStore, genDoc, state)
 	if err != nil {
 		return nil, errors.Wrap(err, "error making NodeInfo")
@@ -530,7 +619,7 @@ func NewNode(config *cfg.Config,
 	p2pLogger := logger.With("module", "p2p")
 	sw := createSwitch(
 		config, transport, peerFilters, mempoolReactor, bcReactor,
-		consensusReactor, nodeInfo, nodeKey, p2pLogger,
+		consensusReactor, stateSyncReactor, nodeInfo, nodeKey, p2pLogger,
 	)
 
 	err = sw.AddPersistentPeers(splitAndTrimEmpty(config.P2P.PersistentPeers, ",", " "))
@@ -538,6 +627,19 @@ func NewNode(config *cfg.Config,
 		return nil, errors.Wrap(err, "could not add peers from persistent_peers field")
 	}
 
+	// Optionally, start the pex reactor, to discover peers
+	// from the seeds, and exchange addresses with them
+	if config.P2P.PexReactor {
+		addrBook, err := createAddrBookAndSetOnSwitch(config, sw, p2pLogger, nodeKey)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not create addrbook")
+		}
+
+		if _, err := createPEXReactorAndAddToSwitch(addrBook, config, sw, logger); err != nil {
+			return nil, errors.Wrap(err, "could not create pex reactor")
+		}
+	}
+
 	if config.ProfListenAddress != "" {
 		server := &http.Server{
 			Addr:              config.ProfListenAddress,
@@ -567,6 +669,9 @@ func NewNode(config *cfg.Config,
 		mempool:           mempool,
 		consensusState:    consensusState,
 		consensusReactor:  consensusReactor,
+		stateSyncReactor:  stateSyncReactor,
+		stateSync:         stateSync,
+		stateSyncGenesis:  state,
 		proxyApp:          proxyApp,
 		txEventStore:      txEventStore,
 		eventStoreService: eventStoreService,
@@ -644,9 +749,84 @@ func (n *Node) OnStart() error {
 		return errors.Wrap(err, "could not dial peers from persistent_peers field")
 	}
 
+	// Run state sync, if enabled
+	if n.stateSync {
+		go n.startStateSync()
+	}
+
 	return nil
 }
 
+// startStateSync restores the app from a snapshot served by peers, and
+// switches to fast sync or consensus from the restored height. If the
+// restoration fails, the node falls back to syncing from genesis
+func (n *Node) startStateSync() {
+	logger := n.Logger.With("module", "statesync")
+
+	// Skip the WAL catchup, there is nothing written for the restored state
+	blocksSynced := 1
+
+	state, err := n.restoreSnapshot()
+	if err != nil {
+		logger.Error("State sync failed, syncing from genesis", "err", err)
+
+		// The handshake initializes the app at genesis. It fails
+		// if the app was left with a partially restored state
+		if err := doHandshake(
+			n.stateDB,
+			n.stateSyncGenesis,
+			n.blockStore,
+			n.genesisDoc,
+			n.evsw,
+			n.proxyApp,
+			n.Logger.With("module", "consensus"),
+		); err != nil {
+			logger.Error("Unable to sync from genesis, stopping the node", "err", err)
+
+			if err := n.Stop(); err != nil {
+				logger.Error("Unable to stop the node", "err", err)
+			}
+
+			return
+		}
+
+		state = sm.LoadState(n.stateDB)
+		blocksSynced = 0
+	}
+
+	if bcR, ok := n.bcReactor.(*bc.BlockchainReactor); ok && n.config.FastSyncMode {
+		if err := bcR.SwitchToFastSync(state); err != nil {
+			logger.Error("Unable to switch to fast sync", "err", err)
+		}
+
+		return
+	}
+
+	n.consensusReactor.SwitchToConsensus(state, blocksSynced)
+}
+
+// restoreSnapshot restores the app from a snapshot served by peers, and
+// bootstraps the node state and block store at the snapshot height
+func (n *Node) restoreSnapshot() (sm.State, error) {
+	stateProvider, err := statesync.NewLightStateProvider(n.config.StateSync, n.stateSyncGenesis)
+	if err != nil {
+		return sm.State{}, fmt.Errorf("unable to set up the state provider, %w", err)
+	}
+
+	state, commit, err := n.stateSyncReactor.Sync(context.Background(), stateProvider, n.config.StateSync.DiscoveryTime)
+	if err != nil {
+		return sm.State{}, err
+	}
+
+	if err := sm.BootstrapState(n.stateDB, state); err != nil {
+		return sm.State{}, fmt.Errorf("unable to bootstrap the node state, %w", err)
+	}
+
+	n.blockStore.Bootstrap(state.LastBlockHeight, commit)
+
+	return state, nil
+}
+
 // OnStop stops the Node. It implements service.Service.
 func (n *Node) OnStop() {
 	n.BaseService.OnStop()
@@ -701,6 +881,7 @@ func (n *Node) configureRPC() {
 	rpccore.SetGetFastSync(n.consensusReactor.FastSync)
 	rpccore.SetLogger(n.Logger.With("module", "rpc"))
 	rpccore.SetEventSwitch(n.evsw)
+	rpccore.SetTxEventStore(n.txEventStore)
 	rpccore.SetConfig(*n.config.RPC)
 }
 
@@ -890,6 +1071,7 @@ func makeNodeInfo(
 			bcChannel,
 			cs.StateChannel, cs.DataChannel, cs.VoteChannel, cs.VoteSetBitsChannel,
 			mempl.MempoolChannel,
+			statesync.SnapshotChannel, statesync.ChunkChannel,
 		},
 		Moniker: config.Moniker,
 		Other: p2p.NodeInfoOther{
@@ -898,6 +1080,10 @@ func makeNodeInfo(
 		},
 	}
 
+	if config.P2P.PexReactor {
+		nodeInfo.Channels = append(nodeInfo.Channels, pex.PexChannel)
+	}
+
 	lAddr := config.P2P.ExternalAddress
 	if lAddr == "" {
 		lAddr = config.P2P.ListenAddress
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"net"
 	"os"
+	"strings"
 	"syscall"
 	"testing"
 	"time"
@@ -73,6 +74,33 @@ func TestNodeStartStop(t *testing.T) {
 	}
 }
 
+func TestNodeStateSyncFallback(t *testing.T) {
+	config, genesisFile := cfg.ResetTestRoot("node_state_sync_fallback_test")
+	defer os.RemoveAll(config.RootDir)
+
+	// The state provider can't reach the rpc servers, so state sync fails
+	config.StateSync.Enable = true
+	config.StateSync.RPCServers = "http://127.0.0.1:1,http://127.0.0.1:2"
+	config.StateSync.TrustHeight = 1
+	config.StateSync.TrustHash = strings.Repeat("00", 32)
+
+	n, err := DefaultNewNode(config, genesisFile, events.NewEventSwitch(), log.NewNoopLogger())
+	require.NoError(t, err)
+
+	blocksSub := events.SubscribeToEvent(n.EventSwitch(), "node_test", types.EventNewBlock{})
+
+	require.NoError(t, n.Start())
+	defer n.Stop()
+
+	// The node syncs from genesis instead
+	select {
+	case _, ok := <-blocksSub:
+		require.True(t, ok, "blocksSub was cancelled")
+	case <-time.After(10 * time.Second):
+		t.Fatal("timed out waiting for the node to produce a block")
+	}
+}
+
 func TestSplitAndTrimEmpty(t *testing.T) {
 	testCases := []struct {
 		s        string
@@ -3,6 +3,7 @@ package client
 import (
 	"context"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/gnolang/gno/tm2/pkg/amino"
@@ -38,7 +39,12 @@ const (
 	blockResultsMethod       = "block_results"
 	commitMethod             = "commit"
 	txMethod                 = "tx"
+	txSearchMethod           = "tx_search"
+	blockSearchMethod        = "block_search"
 	validatorsMethod         = "validators"
+	subscribeMethod          = "subscribe"
+	unsubscribeMethod        = "unsubscribe"
+	unsubscribeAllMethod     = "unsubscribe_all"
 )
 
 // RPCClient encompasses common RPC client methods
@@ -46,6 +52,9 @@ type RPCClient struct {
 	requestTimeout time.Duration
 
 	caller rpcclient.Client
+
+	subscriptions    map[string]rpctypes.JSONRPCID // query -> subscribe request ID
+	subscriptionsMux sync.Mutex
 }
 
 // NewRPCClient creates a new RPC client instance with the given caller
@@ -53,6 +62,7 @@ func NewRPCClient(caller rpcclient.Client, opts ...Option) *RPCClient {
 	c := &RPCClient{
 		requestTimeout: defaultTimeout,
 		caller:         caller,
+		subscriptions:  make(map[string]rpctypes.JSONRPCID),
 	}
 
 	for _, opt := range opts {
@@ -311,6 +321,32 @@ func (c *RPCClient) Tx(hash []byte) (*ctypes.ResultTx, error) {
 	)
 }
 
+func (c *RPCClient) TxSearch(query string, page, perPage int) (*ctypes.ResultTxSearch, error) {
+	return sendRequestCommon[ctypes.ResultTxSearch](
+		c.caller,
+		c.requestTimeout,
+		txSearchMethod,
+		map[string]any{
+			"query":    query,
+			"page":     page,
+			"per_page": perPage,
+		},
+	)
+}
+
+func (c *RPCClient) BlockSearch(query string, page, perPage int) (*ctypes.ResultBlockSearch, error) {
+	return sendRequestCommon[ctypes.ResultBlockSearch](
+		c.caller,
+		c.requestTimeout,
+		blockSearchMethod,
+		map[string]any{
+			"query":    query,
+			"page":     page,
+			"per_page": perPage,
+		},
+	)
+}
+
 func (c *RPCClient) Validators(height *int64) (*ctypes.ResultValidators, error) {
 	params := map[string]any{}
 	if height != nil {
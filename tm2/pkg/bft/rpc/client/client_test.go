@@ -761,6 +761,97 @@ func TestRPCClient_Tx(t *testing.T) {
 	assert.Equal(t, expectedResult, result)
 }
 
+func TestRPCClient_TxSearch(t *testing.T) {
+	t.Parallel()
+
+	var (
+		query   = "tx.height >= 10"
+		page    = 2
+		perPage = 5
+
+		expectedResult = &ctypes.ResultTxSearch{
+			Txs: []*ctypes.ResultTx{
+				{
+					Hash:   []byte("tx hash"),
+					Height: 10,
+				},
+			},
+			TotalCount: 6,
+		}
+
+		verifyFn = func(t *testing.T, params map[string]any) {
+			t.Helper()
+
+			assert.Equal(t, query, params["query"])
+			assert.Equal(t, fmt.Sprintf("%d", page), params["page"])
+			assert.Equal(t, fmt.Sprintf("%d", perPage), params["per_page"])
+		}
+
+		mockClient = generateMockRequestClient(
+			t,
+			txSearchMethod,
+			verifyFn,
+			expectedResult,
+		)
+	)
+
+	// Create the client
+	c := NewRPCClient(mockClient)
+
+	// Get the result
+	result, err := c.TxSearch(query, page, perPage)
+	require.NoError(t, err)
+
+	assert.Equal(t, expectedResult, result)
+}
+
+func TestRPCClient_BlockSearch(t *testing.T) {
+	t.Parallel()
+
+	var (
+		query   = "block.height = 10"
+		page    = 1
+		perPage = 30
+
+		expectedResult = &ctypes.ResultBlockSearch{
+			Blocks: []*ctypes.ResultBlock{
+				{
+					BlockMeta: &bfttypes.BlockMeta{
+						Header: bfttypes.Header{
+							Height: 10,
+						},
+					},
+				},
+			},
+			TotalCount: 1,
+		}
+
+		verifyFn = func(t *testing.T, params map[string]any) {
+			t.Helper()
+
+			assert.Equal(t, query, params["query"])
+			assert.Equal(t, fmt.Sprintf("%d", page), params["page"])
+			assert.Equal(t, fmt.Sprintf("%d", perPage), params["per_page"])
+		}
+
+		mockClient = generateMockRequestClient(
+			t,
+			blockSearchMethod,
+			verifyFn,
+			expectedResult,
+		)
+	)
+
+	// Create the client
+	c := NewRPCClient(mockClient)
+
+	// Get the result
+	result, err := c.BlockSearch(query, page, perPage)
+	require.NoError(t, err)
+
+	assert.Equal(t, expectedResult, result)
+}
+
 func TestRPCClient_Validators(t *testing.T) {
 	t.Parallel()
 
@@ -869,3 +960,94 @@ func TestRPCClient_Batch(t *testing.T) {
 		assert.Equal(t, expectedStatuses[index], castResult)
 	}
 }
+
+func TestRPCClient_Subscribe(t *testing.T) {
+	t.Parallel()
+
+	t.Run("subscriptions not supported", func(t *testing.T) {
+		t.Parallel()
+
+		c := NewRPCClient(&mockClient{})
+
+		ch, err := c.Subscribe("tm.event = 'Tx'")
+		assert.Nil(t, ch)
+		assert.ErrorIs(t, err, ErrSubscriptionsNotSupported)
+
+		assert.ErrorIs(t, c.Unsubscribe("tm.event = 'Tx'"), ErrSubscriptionsNotSupported)
+		assert.ErrorIs(t, c.UnsubscribeAll(), ErrSubscriptionsNotSupported)
+	})
+
+	t.Run("valid subscription", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			query = "tm.event = 'Tx'"
+
+			expectedEvent = ctypes.ResultEvent{
+				Query: query,
+				Event: bfttypes.EventTx{
+					Result: bfttypes.TxResult{
+						Height: 10,
+						Tx:     []byte("tx"),
+					},
+				},
+			}
+
+			notifications = make(chan types.RPCResponse, 1)
+
+			mockClient = &mockSubscriber{
+				mockClient: *generateMockRequestClient(
+					t,
+					unsubscribeMethod,
+					func(t *testing.T, params map[string]any) {
+						t.Helper()
+
+						assert.Equal(t, query, params["query"])
+					},
+					&ctypes.ResultUnsubscribe{},
+				),
+				subscribeFn: func(_ context.Context, request types.RPCRequest) (<-chan types.RPCResponse, error) {
+					require.Equal(t, subscribeMethod, request.Method)
+
+					result, err := amino.MarshalJSON(expectedEvent)
+					require.NoError(t, err)
+
+					notifications <- types.RPCResponse{
+						JSONRPC: "2.0",
+						ID:      types.JSONRPCStringID(fmt.Sprintf("%v#event", request.ID)),
+						Result:  result,
+					}
+
+					return notifications, nil
+				},
+				unsubscribeFn: func(_ types.JSONRPCID) {
+					close(notifications)
+				},
+			}
+		)
+
+		c := NewRPCClient(mockClient)
+
+		events, err := c.Subscribe(query)
+		require.NoError(t, err)
+
+		// Duplicate subscriptions are rejected
+		_, err = c.Subscribe(query)
+		assert.ErrorIs(t, err, ErrAlreadySubscribed)
+
+		select {
+		case event := <-events:
+			assert.Equal(t, expectedEvent, event)
+		case <-time.After(5 * time.Second):
+			t.Fatal("event not received")
+		}
+
+		// Unsubscribe, and make sure the channel is closed
+		require.NoError(t, c.Unsubscribe(query))
+
+		_, more := <-events
+		assert.False(t, more)
+
+		assert.ErrorIs(t, c.Unsubscribe(query), ErrNotSubscribed)
+	})
+}
@@ -0,0 +1,160 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	ctypes "github.com/gnolang/gno/tm2/pkg/bft/rpc/core/types"
+	rpcclient "github.com/gnolang/gno/tm2/pkg/bft/rpc/lib/client"
+	rpctypes "github.com/gnolang/gno/tm2/pkg/bft/rpc/lib/types"
+)
+
+var _ EventsClient = (*RPCClient)(nil)
+
+var (
+	ErrSubscriptionsNotSupported = errors.New("event subscriptions are only supported over websocket")
+	ErrAlreadySubscribed         = errors.New("already subscribed to query")
+	ErrNotSubscribed             = errors.New("not subscribed to query")
+)
+
+// Subscribe subscribes to the node events that match the given query
+// (see the subscribe RPC endpoint for the query format).
+// Matching events are received on the returned channel, which is closed
+// when the subscription is canceled, dropped by the node, or the client is closed.
+// Subscriptions are only supported by websocket clients
+func (c *RPCClient) Subscribe(query string) (<-chan ctypes.ResultEvent, error) {
+	subscriber, ok := c.caller.(rpcclient.Subscriber)
+	if !ok {
+		return nil, ErrSubscriptionsNotSupported
+	}
+
+	request, err := newRequest(subscribeMethod, map[string]any{
+		"query": query,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Reserve the query, so concurrent subscriptions
+	// to the same query are not overwritten
+	c.subscriptionsMux.Lock()
+	if _, exists := c.subscriptions[query]; exists {
+		c.subscriptionsMux.Unlock()
+
+		return nil, ErrAlreadySubscribed
+	}
+
+	c.subscriptions[query] = request.ID
+	c.subscriptionsMux.Unlock()
+
+	ctx, cancelFn := context.WithTimeout(context.Background(), c.requestTimeout)
+	defer cancelFn()
+
+	notifications, err := subscriber.Subscribe(ctx, request)
+	if err != nil {
+		c.removeSubscription(query, request.ID)
+
+		return nil, fmt.Errorf("unable to call RPC method %s, %w", subscribeMethod, err)
+	}
+
+	events := make(chan ctypes.ResultEvent, cap(notifications))
+
+	go func() {
+		defer func() {
+			c.removeSubscription(query, request.ID)
+			close(events)
+		}()
+
+		for notification := range notifications {
+			// The node dropped the subscription
+			if notification.Error != nil {
+				subscriber.Unsubscribe(request.ID)
+
+				return
+			}
+
+			event, err := unmarshalResponseBytes[ctypes.ResultEvent](notification.Result)
+			if err != nil {
+				continue
+			}
+
+			events <- *event
+		}
+	}()
+
+	return events, nil
+}
+
+// Unsubscribe cancels the subscription to the given query
+func (c *RPCClient) Unsubscribe(query string) error {
+	subscriber, ok := c.caller.(rpcclient.Subscriber)
+	if !ok {
+		return ErrSubscriptionsNotSupported
+	}
+
+	c.subscriptionsMux.Lock()
+	id, exists := c.subscriptions[query]
+	c.subscriptionsMux.Unlock()
+
+	if !exists {
+		return ErrNotSubscribed
+	}
+
+	if _, err := sendRequestCommon[ctypes.ResultUnsubscribe](
+		c.caller,
+		c.requestTimeout,
+		unsubscribeMethod,
+		map[string]any{
+			"query": query,
+		},
+	); err != nil {
+		return err
+	}
+
+	subscriber.Unsubscribe(id)
+
+	return nil
+}
+
+// UnsubscribeAll cancels all active subscriptions
+func (c *RPCClient) UnsubscribeAll() error {
+	subscriber, ok := c.caller.(rpcclient.Subscriber)
+	if !ok {
+		return ErrSubscriptionsNotSupported
+	}
+
+	if _, err := sendRequestCommon[ctypes.ResultUnsubscribe](
+		c.caller,
+		c.requestTimeout,
+		unsubscribeAllMethod,
+		map[string]any{},
+	); err != nil {
+		return err
+	}
+
+	c.subscriptionsMux.Lock()
+	ids := make([]rpctypes.JSONRPCID, 0, len(c.subscriptions))
+
+	for _, id := range c.subscriptions {
+		ids = append(ids, id)
+	}
+	c.subscriptionsMux.Unlock()
+
+	for _, id := range ids {
+		subscriber.Unsubscribe(id)
+	}
+
+	return nil
+}
+
+// removeSubscription removes the query subscription,
+// if it still belongs to the given request ID
+func (c *RPCClient) removeSubscription(query string, id rpctypes.JSONRPCID) {
+	c.subscriptionsMux.Lock()
+	defer c.subscriptionsMux.Unlock()
+
+	if c.subscriptions[query] == id {
+		delete(c.subscriptions, query)
+	}
+}
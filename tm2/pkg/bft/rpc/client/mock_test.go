@@ -41,3 +41,29 @@ func (m *mockClient) Close() error {
 
 	return nil
 }
+
+type (
+	subscribeDelegate   func(context.Context, types.RPCRequest) (<-chan types.RPCResponse, error)
+	unsubscribeDelegate func(types.JSONRPCID)
+)
+
+type mockSubscriber struct {
+	mockClient
+
+	subscribeFn   subscribeDelegate
+	unsubscribeFn unsubscribeDelegate
+}
+
+func (m *mockSubscriber) Subscribe(ctx context.Context, request types.RPCRequest) (<-chan types.RPCResponse, error) {
+	if m.subscribeFn != nil {
+		return m.subscribeFn(ctx, request)
+	}
+
+	return nil, nil
+}
+
+func (m *mockSubscriber) Unsubscribe(id types.JSONRPCID) {
+	if m.unsubscribeFn != nil {
+		m.unsubscribeFn(id)
+	}
+}
@@ -17,10 +17,7 @@ var DefaultABCIQueryOptions = ABCIQueryOptions{Height: 0, Prove: false}
 
 // Client wraps most important rpc calls a client would make.
 //
-// NOTE: Events cannot be subscribed to from the RPC APIs. For events
-// subscriptions and filters and queries, an external API must be used that
-// first synchronously consumes the events from the node's synchronous event
-// switch, or reads logged events from the filesystem.
+// NOTE: Events can only be subscribed to over websocket, see EventsClient.
 type Client interface {
 	ABCIClient
 	HistoryClient
@@ -56,6 +53,7 @@ type SignClient interface {
 	BlockResults(height *int64) (*ctypes.ResultBlockResults, error)
 	Commit(height *int64) (*ctypes.ResultCommit, error)
 	Validators(height *int64) (*ctypes.ResultValidators, error)
+	BlockSearch(query string, page, perPage int) (*ctypes.ResultBlockSearch, error)
 }
 
 // HistoryClient provides access to data from genesis to now in large chunks.
@@ -87,4 +85,13 @@ type MempoolClient interface {
 
 type TxClient interface {
 	Tx(hash []byte) (*ctypes.ResultTx, error)
+	TxSearch(query string, page, perPage int) (*ctypes.ResultTxSearch, error)
+}
+
+// EventsClient is used for subscribing to node events,
+// and is only supported over websocket
+type EventsClient interface {
+	Subscribe(query string) (<-chan ctypes.ResultEvent, error)
+	Unsubscribe(query string) error
+	UnsubscribeAll() error
 }
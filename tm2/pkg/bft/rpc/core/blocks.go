@@ -6,6 +6,7 @@ import (
 	ctypes "github.com/gnolang/gno/tm2/pkg/bft/rpc/core/types"
 	rpctypes "github.com/gnolang/gno/tm2/pkg/bft/rpc/lib/types"
 	sm "github.com/gnolang/gno/tm2/pkg/bft/state"
+	eventquery "github.com/gnolang/gno/tm2/pkg/bft/state/eventstore/query"
 	"github.com/gnolang/gno/tm2/pkg/bft/types"
 )
 
@@ -417,6 +418,75 @@ func BlockResults(ctx *rpctypes.Context, heightPtr *int64) (*ctypes.ResultBlockR
 	return res, nil
 }
 
+// BlockSearch searches for blocks using the indexed block events
+// (begin and end block events). Results are ordered by height.
+//
+// ```shell
+// curl "localhost:26657/block_search?query=\"block.height>=10\"&page=1&per_page=30"
+// ```
+//
+// ### Query Parameters
+//
+// | Parameter | Type   | Default | Required | Description                           |
+// |-----------+--------+---------+----------+---------------------------------------|
+// | query     | string | ""      | true     | Query                                 |
+// | page      | int    | 1       | false    | Page number (1-based)                 |
+// | per_page  | int    | 30      | false    | Number of entries per page (max: 100) |
+//
+// The query supports the `block.height`, `event.type`, `event.pkg_path`,
+// `event.func` and `<EventType>.<AttributeKey>` keys.
+//
+// The total count is capped at 10000 blocks: to go over more blocks,
+// narrow down the query with a `block.height` range.
+func BlockSearch(_ *rpctypes.Context, query string, page, perPage int) (*ctypes.ResultBlockSearch, error) {
+	searcher, err := getSearcher()
+	if err != nil {
+		return nil, err
+	}
+
+	q, err := eventquery.Parse(query)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse query, %w", err)
+	}
+
+	heights, err := searcher.SearchBlocks(q, maxSearchResults)
+	if err != nil {
+		return nil, fmt.Errorf("unable to search blocks, %w", err)
+	}
+
+	// Paginate the results
+	totalCount := len(heights)
+	perPage = validatePerPage(perPage)
+
+	page, err = validatePage(page, perPage, totalCount)
+	if err != nil {
+		return nil, err
+	}
+
+	skipCount := (page - 1) * perPage
+	pageSize := min(perPage, totalCount-skipCount)
+
+	blocks := make([]*ctypes.ResultBlock, 0, pageSize)
+	for _, height := range heights[skipCount : skipCount+pageSize] {
+		block := blockStore.LoadBlock(height)
+		if block == nil {
+			// The block store can be behind the event store
+			// when the node is restarted mid-block
+			continue
+		}
+
+		blocks = append(blocks, &ctypes.ResultBlock{
+			BlockMeta: blockStore.LoadBlockMeta(height),
+			Block:     block,
+		})
+	}
+
+	return &ctypes.ResultBlockSearch{
+		Blocks:     blocks,
+		TotalCount: totalCount,
+	}, nil
+}
+
 func getHeight(currentHeight int64, heightPtr *int64) (int64, error) {
 	return getHeightWithMin(currentHeight, heightPtr, 1)
 }
@@ -0,0 +1,343 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	ctypes "github.com/gnolang/gno/tm2/pkg/bft/rpc/core/types"
+	rpctypes "github.com/gnolang/gno/tm2/pkg/bft/rpc/lib/types"
+	eventquery "github.com/gnolang/gno/tm2/pkg/bft/state/eventstore/query"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/events"
+	"github.com/gnolang/gno/tm2/pkg/random"
+)
+
+const (
+	// maxSubscriptionsPerClient is the maximum number of
+	// active subscriptions a single websocket client can have
+	maxSubscriptionsPerClient = 10
+
+	// subscriptionBufferSize is the number of events buffered
+	// per subscription. Subscriptions of clients that are unable to keep up
+	// with the event rate are dropped once the buffer is full
+	subscriptionBufferSize = 100
+)
+
+var (
+	errSubscribeNotWS       = errors.New("subscriptions are only available over websocket")
+	errTooManySubscriptions = fmt.Errorf("maximum number of subscriptions per client (%d) reached", maxSubscriptionsPerClient)
+	errAlreadySubscribed    = errors.New("already subscribed to query")
+	errSubscriptionNotFound = errors.New("subscription not found")
+	errSubscriptionDropped  = errors.New("subscription dropped, client is too slow to keep up with events")
+)
+
+// subscriptions are the active websocket event subscriptions
+var subscriptions = newSubscriptionRegistry()
+
+// Subscribe subscribes the websocket client to events matching the given query.
+// The subscription kind is selected with the tm.event key, which can be one of
+// NewBlock, Tx (the entire transaction result) and TxEvent (every individual
+// event emitted by a transaction). If it's not specified, both new blocks and
+// transactions are matched:
+//
+//	tm.event = 'TxEvent' AND event.pkg_path = 'gno.land/r/demo/foo'
+//
+// Matching events are pushed to the client as ResultEvent responses,
+// whose ID is the subscribe request ID, suffixed by "#event".
+// The subscription lasts until it's canceled, or the client disconnects
+func Subscribe(ctx *rpctypes.Context, query string) (*ctypes.ResultSubscribe, error) {
+	if ctx.WSConn == nil {
+		return nil, errSubscribeNotWS
+	}
+
+	q, err := eventquery.Parse(query)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse query, %w", err)
+	}
+
+	var (
+		sw         = evsw
+		remoteAddr = ctx.RemoteAddr()
+		listenerID = fmt.Sprintf("rpc-subscription#%s#%s", remoteAddr, random.RandStr(6))
+	)
+
+	subCtx, cancelFn := context.WithCancel(ctx.Context())
+
+	sub := &subscription{
+		query:  q,
+		kinds:  subscriptionKinds(q),
+		cancel: cancelFn,
+	}
+
+	if err := subscriptions.add(remoteAddr, q.String(), sub); err != nil {
+		cancelFn()
+
+		return nil, err
+	}
+
+	ch := events.SubscribeFilteredOn(
+		sw,
+		listenerID,
+		sub.filter,
+		make(chan events.Event, subscriptionBufferSize),
+	)
+
+	var eventID rpctypes.JSONRPCID = rpctypes.JSONRPCStringID("#event")
+	if ctx.JSONReq != nil {
+		eventID = rpctypes.JSONRPCStringID(fmt.Sprintf("%v#event", ctx.JSONReq.ID))
+	}
+
+	go func() {
+		defer func() {
+			sw.RemoveListener(listenerID)
+			subscriptions.remove(remoteAddr, q.String(), sub)
+		}()
+
+		for {
+			select {
+			case <-subCtx.Done():
+				return
+			case event, ok := <-ch:
+				if !ok {
+					// The event switch closes the channel
+					// once the subscription buffer is full
+					ctx.WSConn.TryWriteRPCResponses(rpctypes.RPCResponses{
+						rpctypes.RPCInternalError(eventID, errSubscriptionDropped),
+					})
+
+					return
+				}
+
+				// Skip events racing with the cancellation
+				if subCtx.Err() != nil {
+					return
+				}
+
+				for _, matched := range sub.match(event) {
+					resp := rpctypes.NewRPCSuccessResponse(eventID, ctypes.ResultEvent{
+						Query: q.String(),
+						Event: matched,
+					})
+
+					ctx.WSConn.WriteRPCResponses(rpctypes.RPCResponses{resp})
+				}
+			}
+		}
+	}()
+
+	return &ctypes.ResultSubscribe{}, nil
+}
+
+// Unsubscribe cancels the websocket client subscription for the given query
+func Unsubscribe(ctx *rpctypes.Context, query string) (*ctypes.ResultUnsubscribe, error) {
+	if ctx.WSConn == nil {
+		return nil, errSubscribeNotWS
+	}
+
+	q, err := eventquery.Parse(query)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse query, %w", err)
+	}
+
+	if !subscriptions.cancel(ctx.RemoteAddr(), q.String()) {
+		return nil, errSubscriptionNotFound
+	}
+
+	return &ctypes.ResultUnsubscribe{}, nil
+}
+
+// UnsubscribeAll cancels all websocket client subscriptions
+func UnsubscribeAll(ctx *rpctypes.Context) (*ctypes.ResultUnsubscribe, error) {
+	if ctx.WSConn == nil {
+		return nil, errSubscribeNotWS
+	}
+
+	if subscriptions.cancelAll(ctx.RemoteAddr()) == 0 {
+		return nil, errSubscriptionNotFound
+	}
+
+	return &ctypes.ResultUnsubscribe{}, nil
+}
+
+// subscription is a single websocket event subscription
+type subscription struct {
+	query  *eventquery.Query
+	kinds  map[string]bool // requested event kinds
+	cancel context.CancelFunc
+}
+
+// subscriptionKinds returns the event kinds requested by the query.
+// If the query has no tm.event condition, blocks and transactions are matched
+func subscriptionKinds(q *eventquery.Query) map[string]bool {
+	kinds := make(map[string]bool)
+
+	for _, c := range q.Conditions {
+		if c.Key == eventquery.EventKindKey && c.Op == eventquery.OpEqual {
+			kinds[c.Value] = true
+		}
+	}
+
+	if len(kinds) == 0 {
+		kinds[eventquery.EventKindNewBlock] = true
+		kinds[eventquery.EventKindTx] = true
+	}
+
+	return kinds
+}
+
+// filter filters out the event switch events
+// that can't produce any of the requested kinds
+func (s *subscription) filter(event events.Event) bool {
+	switch event.(type) {
+	case types.EventNewBlock:
+		return s.kinds[eventquery.EventKindNewBlock]
+	case types.EventTx:
+		return s.kinds[eventquery.EventKindTx] || s.kinds[eventquery.EventKindTxEvent]
+	default:
+		return false
+	}
+}
+
+// match returns the events that match the subscription query
+// out of the given event switch event
+func (s *subscription) match(event events.Event) []types.TMEvent {
+	matched := make([]types.TMEvent, 0, 1)
+
+	switch ev := event.(type) {
+	case types.EventNewBlock:
+		if ev.Block == nil || !s.kinds[eventquery.EventKindNewBlock] {
+			break
+		}
+
+		attrs := eventquery.BlockAttributes(ev.Block.Header, ev.ResultBeginBlock, ev.ResultEndBlock)
+		if s.query.Matches(attrs) {
+			matched = append(matched, ev)
+		}
+	case types.EventTx:
+		if s.kinds[eventquery.EventKindTx] && s.query.Matches(eventquery.TxAttributes(ev.Result)) {
+			matched = append(matched, ev)
+		}
+
+		if !s.kinds[eventquery.EventKindTxEvent] {
+			break
+		}
+
+		for _, appEvent := range ev.Result.Response.Events {
+			if !s.query.Matches(eventquery.EventAttributes(ev.Result, appEvent)) {
+				continue
+			}
+
+			matched = append(matched, types.EventTxEvent{
+				Height: ev.Result.Height,
+				Index:  ev.Result.Index,
+				TxHash: ev.Result.Tx.Hash(),
+				Event:  appEvent,
+			})
+		}
+	}
+
+	return matched
+}
+
+// subscriptionRegistry keeps track of the active
+// subscriptions, by client remote address and query
+type subscriptionRegistry struct {
+	mux  sync.Mutex
+	subs map[string]map[string]*subscription
+}
+
+func newSubscriptionRegistry() *subscriptionRegistry {
+	return &subscriptionRegistry{
+		subs: make(map[string]map[string]*subscription),
+	}
+}
+
+// add registers a new client subscription
+func (r *subscriptionRegistry) add(client, query string, sub *subscription) error {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	clientSubs, ok := r.subs[client]
+	if !ok {
+		clientSubs = make(map[string]*subscription)
+		r.subs[client] = clientSubs
+	}
+
+	if _, exists := clientSubs[query]; exists {
+		return errAlreadySubscribed
+	}
+
+	if len(clientSubs) >= maxSubscriptionsPerClient {
+		return errTooManySubscriptions
+	}
+
+	clientSubs[query] = sub
+
+	return nil
+}
+
+// remove removes the given client subscription, if it's still registered
+func (r *subscriptionRegistry) remove(client, query string, sub *subscription) {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	clientSubs := r.subs[client]
+	if clientSubs[query] != sub {
+		return
+	}
+
+	delete(clientSubs, query)
+
+	if len(clientSubs) == 0 {
+		delete(r.subs, client)
+	}
+}
+
+// cancel cancels and removes the client subscription for the given query.
+// Returns a flag indicating if the subscription was found
+func (r *subscriptionRegistry) cancel(client, query string) bool {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	clientSubs := r.subs[client]
+
+	sub, ok := clientSubs[query]
+	if !ok {
+		return false
+	}
+
+	sub.cancel()
+	delete(clientSubs, query)
+
+	if len(clientSubs) == 0 {
+		delete(r.subs, client)
+	}
+
+	return true
+}
+
+// cancelAll cancels and removes all client subscriptions.
+// Returns the number of canceled subscriptions
+func (r *subscriptionRegistry) cancelAll(client string) int {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	clientSubs := r.subs[client]
+	for _, sub := range clientSubs {
+		sub.cancel()
+	}
+
+	delete(r.subs, client)
+
+	return len(clientSubs)
+}
+
+// count returns the number of active client subscriptions
+func (r *subscriptionRegistry) count(client string) int {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	return len(r.subs[client])
+}
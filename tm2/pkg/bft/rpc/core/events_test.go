@@ -0,0 +1,264 @@
+package core
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	ctypes "github.com/gnolang/gno/tm2/pkg/bft/rpc/core/types"
+	rpctypes "github.com/gnolang/gno/tm2/pkg/bft/rpc/lib/types"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newSubscriptionContext creates a websocket RPC context for the given
+// subscription request ID, whose responses are piped to the returned channel
+func newSubscriptionContext(t *testing.T, id string) (*rpctypes.Context, <-chan rpctypes.RPCResponse) {
+	t.Helper()
+
+	var (
+		responses = make(chan rpctypes.RPCResponse, 10)
+
+		conn = &mockWSConn{
+			getRemoteAddrFn: func() string {
+				return "127.0.0.1:" + t.Name()
+			},
+			writeRPCResponsesFn: func(resp rpctypes.RPCResponses) {
+				for _, r := range resp {
+					responses <- r
+				}
+			},
+		}
+	)
+
+	return &rpctypes.Context{
+		JSONReq: &rpctypes.RPCRequest{
+			ID: rpctypes.JSONRPCStringID(id),
+		},
+		WSConn: conn,
+	}, responses
+}
+
+// waitEvent waits for the next subscription event
+func waitEvent(t *testing.T, responses <-chan rpctypes.RPCResponse) (rpctypes.RPCResponse, ctypes.ResultEvent) {
+	t.Helper()
+
+	select {
+	case resp := <-responses:
+		require.Nil(t, resp.Error)
+
+		var result ctypes.ResultEvent
+		require.NoError(t, amino.UnmarshalJSON(resp.Result, &result))
+
+		return resp, result
+	case <-time.After(5 * time.Second):
+		t.Fatal("subscription event not received")
+	}
+
+	return rpctypes.RPCResponse{}, ctypes.ResultEvent{}
+}
+
+func TestSubscribe(t *testing.T) {
+	t.Run("not websocket", func(t *testing.T) {
+		res, err := Subscribe(&rpctypes.Context{}, "tm.event = 'Tx'")
+
+		assert.Nil(t, res)
+		assert.ErrorIs(t, err, errSubscribeNotWS)
+	})
+
+	t.Run("invalid query", func(t *testing.T) {
+		ctx, _ := newSubscriptionContext(t, "id")
+
+		res, err := Subscribe(ctx, "tm.event =")
+
+		assert.Nil(t, res)
+		assert.Error(t, err)
+	})
+
+	t.Run("already subscribed", func(t *testing.T) {
+		// Set the GLOBALLY referenced event switch
+		SetEventSwitch(events.NewEventSwitch())
+
+		ctx, _ := newSubscriptionContext(t, "id")
+		defer UnsubscribeAll(ctx)
+
+		_, err := Subscribe(ctx, "tm.event = 'Tx'")
+		require.NoError(t, err)
+
+		_, err = Subscribe(ctx, "tm.event='Tx'")
+		assert.ErrorIs(t, err, errAlreadySubscribed)
+	})
+
+	t.Run("too many subscriptions", func(t *testing.T) {
+		// Set the GLOBALLY referenced event switch
+		SetEventSwitch(events.NewEventSwitch())
+
+		ctx, _ := newSubscriptionContext(t, "id")
+		defer UnsubscribeAll(ctx)
+
+		for i := 0; i < maxSubscriptionsPerClient; i++ {
+			_, err := Subscribe(ctx, fmt.Sprintf("tx.height = %d", i))
+			require.NoError(t, err)
+		}
+
+		_, err := Subscribe(ctx, "tm.event = 'NewBlock'")
+		assert.ErrorIs(t, err, errTooManySubscriptions)
+	})
+
+	t.Run("transactions", func(t *testing.T) {
+		evsw := events.NewEventSwitch()
+
+		// Set the GLOBALLY referenced event switch
+		SetEventSwitch(evsw)
+
+		ctx, responses := newSubscriptionContext(t, "tx")
+		defer UnsubscribeAll(ctx)
+
+		_, err := Subscribe(ctx, "tm.event = 'Tx' AND tx.height = 2")
+		require.NoError(t, err)
+
+		for height := int64(1); height <= 2; height++ {
+			evsw.FireEvent(types.EventTx{
+				Result: types.TxResult{
+					Height: height,
+					Tx:     []byte("tx"),
+				},
+			})
+		}
+
+		resp, result := waitEvent(t, responses)
+
+		assert.Equal(t, rpctypes.JSONRPCStringID("tx#event"), resp.ID)
+		assert.Equal(t, "tm.event = 'Tx' AND tx.height = 2", result.Query)
+
+		require.IsType(t, types.EventTx{}, result.Event)
+		assert.Equal(t, int64(2), result.Event.(types.EventTx).Result.Height)
+	})
+
+	t.Run("individual tx events", func(t *testing.T) {
+		evsw := events.NewEventSwitch()
+
+		// Set the GLOBALLY referenced event switch
+		SetEventSwitch(evsw)
+
+		ctx, responses := newSubscriptionContext(t, "events")
+		defer UnsubscribeAll(ctx)
+
+		_, err := Subscribe(ctx, "tm.event = 'TxEvent' AND event.type = 'Mint'")
+		require.NoError(t, err)
+
+		tx := types.Tx("tx")
+
+		evsw.FireEvent(types.EventTx{
+			Result: types.TxResult{
+				Height: 10,
+				Index:  1,
+				Tx:     tx,
+				Response: abci.ResponseDeliverTx{
+					ResponseBase: abci.ResponseBase{
+						Events: []abci.Event{
+							abci.EventString("Burn"),
+							abci.EventString("Mint"),
+						},
+					},
+				},
+			},
+		})
+
+		_, result := waitEvent(t, responses)
+
+		assert.Equal(t, types.EventTxEvent{
+			Height: 10,
+			Index:  1,
+			TxHash: tx.Hash(),
+			Event:  abci.EventString("Mint"),
+		}, result.Event)
+	})
+
+	t.Run("new blocks", func(t *testing.T) {
+		evsw := events.NewEventSwitch()
+
+		// Set the GLOBALLY referenced event switch
+		SetEventSwitch(evsw)
+
+		ctx, responses := newSubscriptionContext(t, "blocks")
+		defer UnsubscribeAll(ctx)
+
+		_, err := Subscribe(ctx, "block.height >= 5")
+		require.NoError(t, err)
+
+		for height := int64(4); height <= 5; height++ {
+			evsw.FireEvent(types.EventNewBlock{
+				Block: &types.Block{
+					Header: types.Header{
+						Height: height,
+					},
+				},
+			})
+		}
+
+		_, result := waitEvent(t, responses)
+
+		require.IsType(t, types.EventNewBlock{}, result.Event)
+		assert.Equal(t, int64(5), result.Event.(types.EventNewBlock).Block.Height)
+	})
+}
+
+func TestUnsubscribe(t *testing.T) {
+	t.Run("not websocket", func(t *testing.T) {
+		res, err := Unsubscribe(&rpctypes.Context{}, "tm.event = 'Tx'")
+
+		assert.Nil(t, res)
+		assert.ErrorIs(t, err, errSubscribeNotWS)
+	})
+
+	t.Run("subscription not found", func(t *testing.T) {
+		ctx, _ := newSubscriptionContext(t, "id")
+
+		_, err := Unsubscribe(ctx, "tm.event = 'Tx'")
+		assert.ErrorIs(t, err, errSubscriptionNotFound)
+
+		_, err = UnsubscribeAll(ctx)
+		assert.ErrorIs(t, err, errSubscriptionNotFound)
+	})
+
+	t.Run("valid unsubscribe", func(t *testing.T) {
+		evsw := events.NewEventSwitch()
+
+		// Set the GLOBALLY referenced event switch
+		SetEventSwitch(evsw)
+
+		ctx, responses := newSubscriptionContext(t, "id")
+
+		_, err := Subscribe(ctx, "tm.event = 'Tx'")
+		require.NoError(t, err)
+
+		_, err = Subscribe(ctx, "tm.event = 'NewBlock'")
+		require.NoError(t, err)
+
+		_, err = Unsubscribe(ctx, "tm.event='Tx'")
+		require.NoError(t, err)
+
+		assert.Equal(t, 1, subscriptions.count(ctx.RemoteAddr()))
+
+		// Make sure the canceled subscription is no longer notified
+		evsw.FireEvent(types.EventTx{
+			Result: types.TxResult{Tx: []byte("tx")},
+		})
+
+		select {
+		case <-responses:
+			t.Fatal("unexpected subscription event")
+		case <-time.After(100 * time.Millisecond):
+		}
+
+		_, err = UnsubscribeAll(ctx)
+		require.NoError(t, err)
+
+		assert.Equal(t, 0, subscriptions.count(ctx.RemoteAddr()))
+	})
+}
@@ -1,6 +1,11 @@
 package core
 
-import "github.com/gnolang/gno/tm2/pkg/bft/types"
+import (
+	"context"
+
+	rpctypes "github.com/gnolang/gno/tm2/pkg/bft/rpc/lib/types"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+)
 
 type (
 	heightDelegate          func() int64
@@ -76,3 +81,47 @@ func (m *mockBlockStore) SaveBlock(block *types.Block, blockParts *types.PartSet
 		m.saveBlockFn(block, blockParts, seenCommit)
 	}
 }
+
+type (
+	getRemoteAddrDelegate        func() string
+	writeRPCResponsesDelegate    func(rpctypes.RPCResponses)
+	tryWriteRPCResponsesDelegate func(rpctypes.RPCResponses) bool
+	contextDelegate              func() context.Context
+)
+
+type mockWSConn struct {
+	getRemoteAddrFn        getRemoteAddrDelegate
+	writeRPCResponsesFn    writeRPCResponsesDelegate
+	tryWriteRPCResponsesFn tryWriteRPCResponsesDelegate
+	contextFn              contextDelegate
+}
+
+func (m *mockWSConn) GetRemoteAddr() string {
+	if m.getRemoteAddrFn != nil {
+		return m.getRemoteAddrFn()
+	}
+
+	return ""
+}
+
+func (m *mockWSConn) WriteRPCResponses(resp rpctypes.RPCResponses) {
+	if m.writeRPCResponsesFn != nil {
+		m.writeRPCResponsesFn(resp)
+	}
+}
+
+func (m *mockWSConn) TryWriteRPCResponses(resp rpctypes.RPCResponses) bool {
+	if m.tryWriteRPCResponsesFn != nil {
+		return m.tryWriteRPCResponsesFn(resp)
+	}
+
+	return false
+}
+
+func (m *mockWSConn) Context() context.Context {
+	if m.contextFn != nil {
+		return m.contextFn()
+	}
+
+	return context.Background()
+}
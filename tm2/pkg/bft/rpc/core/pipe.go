@@ -10,6 +10,7 @@ import (
 	mempl "github.com/gnolang/gno/tm2/pkg/bft/mempool"
 	cfg "github.com/gnolang/gno/tm2/pkg/bft/rpc/config"
 	sm "github.com/gnolang/gno/tm2/pkg/bft/state"
+	"github.com/gnolang/gno/tm2/pkg/bft/state/eventstore"
 	"github.com/gnolang/gno/tm2/pkg/bft/types"
 	"github.com/gnolang/gno/tm2/pkg/crypto"
 	dbm "github.com/gnolang/gno/tm2/pkg/db"
@@ -21,6 +22,10 @@ const (
 	// see README
 	defaultPerPage = 30
 	maxPerPage     = 100
+
+	// maxSearchResults caps the total count of tx_search and block_search,
+	// so a search stops once it finds as many results
+	maxSearchResults = 10_000
 )
 
 // ----------------------------------------------
@@ -69,6 +74,7 @@ var (
 	evsw          events.EventSwitch
 	gTxDispatcher *txDispatcher
 	mempool       mempl.Mempool
+	txEventStore  eventstore.TxEventStore
 	getFastSync   func() bool // avoids dependency on consensus pkg
 
 	logger *slog.Logger
@@ -125,6 +131,10 @@ func SetEventSwitch(sw events.EventSwitch) {
 	gTxDispatcher = newTxDispatcher(evsw)
 }
 
+func SetTxEventStore(store eventstore.TxEventStore) {
+	txEventStore = store
+}
+
 func Start() {
 	gTxDispatcher.Start()
 }
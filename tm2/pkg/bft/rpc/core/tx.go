@@ -1,13 +1,18 @@
 package core
 
 import (
+	"errors"
 	"fmt"
 
 	ctypes "github.com/gnolang/gno/tm2/pkg/bft/rpc/core/types"
 	rpctypes "github.com/gnolang/gno/tm2/pkg/bft/rpc/lib/types"
 	sm "github.com/gnolang/gno/tm2/pkg/bft/state"
+	"github.com/gnolang/gno/tm2/pkg/bft/state/eventstore"
+	eventquery "github.com/gnolang/gno/tm2/pkg/bft/state/eventstore/query"
 )
 
+var errTxIndexingDisabled = errors.New("transaction indexing is disabled, or the event store is not searchable")
+
 // Tx allows you to query the transaction results. `nil` could mean the
 // transaction is in the mempool, invalidated, or was not sent in the first
 // place
@@ -64,3 +69,84 @@ func Tx(_ *rpctypes.Context, hash []byte) (*ctypes.ResultTx, error) {
 		Tx:       rawTx,
 	}, nil
 }
+
+// TxSearch allows you to query for multiple transactions results,
+// using the indexed transaction events.
+// Results are ordered by block height and transaction index.
+//
+// ```shell
+// curl "localhost:26657/tx_search?query=\"event.pkg_path='gno.land/r/demo/foo'\"&page=1&per_page=30"
+// ```
+//
+// ### Query Parameters
+//
+// | Parameter | Type   | Default | Required | Description                                 |
+// |-----------+--------+---------+----------+---------------------------------------------|
+// | query     | string | ""      | true     | Query                                       |
+// | page      | int    | 1       | false    | Page number (1-based)                       |
+// | per_page  | int    | 30      | false    | Number of entries per page (max: 100)       |
+//
+// The query supports the `tx.hash`, `tx.height`, `tx.signer`, `event.type`,
+// `event.pkg_path`, `event.func` and `<EventType>.<AttributeKey>` keys.
+//
+// The total count is capped at 10000 transactions: to go over more
+// transactions, narrow down the query with a `tx.height` range.
+func TxSearch(_ *rpctypes.Context, query string, page, perPage int) (*ctypes.ResultTxSearch, error) {
+	searcher, err := getSearcher()
+	if err != nil {
+		return nil, err
+	}
+
+	q, err := eventquery.Parse(query)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse query, %w", err)
+	}
+
+	hashes, err := searcher.SearchTxs(q, maxSearchResults)
+	if err != nil {
+		return nil, fmt.Errorf("unable to search transactions, %w", err)
+	}
+
+	// Paginate the results
+	totalCount := len(hashes)
+	perPage = validatePerPage(perPage)
+
+	page, err = validatePage(page, perPage, totalCount)
+	if err != nil {
+		return nil, err
+	}
+
+	skipCount := (page - 1) * perPage
+	pageSize := min(perPage, totalCount-skipCount)
+
+	txs := make([]*ctypes.ResultTx, 0, pageSize)
+	for _, hash := range hashes[skipCount : skipCount+pageSize] {
+		result, err := searcher.GetTx(hash)
+		if err != nil {
+			return nil, fmt.Errorf("unable to load transaction %X, %w", hash, err)
+		}
+
+		txs = append(txs, &ctypes.ResultTx{
+			Hash:     hash,
+			Height:   result.Height,
+			Index:    result.Index,
+			TxResult: result.Response,
+			Tx:       result.Tx,
+		})
+	}
+
+	return &ctypes.ResultTxSearch{
+		Txs:        txs,
+		TotalCount: totalCount,
+	}, nil
+}
+
+// getSearcher returns the searchable transaction event store, if any
+func getSearcher() (eventstore.Searcher, error) {
+	searcher, ok := txEventStore.(eventstore.Searcher)
+	if !ok {
+		return nil, errTxIndexingDisabled
+	}
+
+	return searcher, nil
+}
@@ -1,11 +1,15 @@
 package core
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/gnolang/gno/tm2/pkg/amino"
 	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
 	"github.com/gnolang/gno/tm2/pkg/bft/state"
+	"github.com/gnolang/gno/tm2/pkg/bft/state/eventstore/kv"
+	"github.com/gnolang/gno/tm2/pkg/bft/state/eventstore/null"
+	storetypes "github.com/gnolang/gno/tm2/pkg/bft/state/eventstore/types"
 	"github.com/gnolang/gno/tm2/pkg/bft/types"
 	"github.com/gnolang/gno/tm2/pkg/db/memdb"
 	"github.com/gnolang/gno/tm2/pkg/std"
@@ -220,3 +224,81 @@ func TestTxHandler(t *testing.T) {
 		assert.ErrorContains(t, err, "unable to load block results")
 	})
 }
+
+func TestTxSearchHandler(t *testing.T) {
+	// Tests are not run in parallel because the JSON-RPC
+	// handlers utilize global package-level variables
+	t.Run("event store not searchable", func(t *testing.T) {
+		SetTxEventStore(null.NewNullEventStore())
+
+		result, err := TxSearch(nil, "tx.height = 1", 1, 10)
+		require.Nil(t, result)
+
+		assert.ErrorIs(t, err, errTxIndexingDisabled)
+	})
+
+	t.Run("invalid query", func(t *testing.T) {
+		store, err := kv.NewTxEventStore(
+			&storetypes.Config{EventStoreType: kv.EventStoreType},
+			memdb.NewMemDB(),
+		)
+		require.NoError(t, err)
+
+		SetTxEventStore(store)
+
+		result, err := TxSearch(nil, "tx.height >=", 1, 10)
+		require.Nil(t, result)
+
+		assert.ErrorContains(t, err, "unable to parse query")
+	})
+
+	t.Run("paginated results", func(t *testing.T) {
+		store, err := kv.NewTxEventStore(
+			&storetypes.Config{EventStoreType: kv.EventStoreType},
+			memdb.NewMemDB(),
+		)
+		require.NoError(t, err)
+
+		SetTxEventStore(store)
+
+		// Save the transactions
+		numTxs := 5
+		txs := make([]types.TxResult, 0, numTxs)
+
+		for i := 0; i < numTxs; i++ {
+			txResult := types.TxResult{
+				Height: int64(i + 1),
+				Tx:     types.Tx(fmt.Sprintf("tx %d", i)),
+				Response: abci.ResponseDeliverTx{
+					GasUsed: int64(i),
+				},
+			}
+
+			require.NoError(t, store.Append(txResult))
+
+			txs = append(txs, txResult)
+		}
+
+		// Fetch the second page
+		result, err := TxSearch(nil, "tx.height > 1", 2, 2)
+		require.NoError(t, err)
+
+		assert.Equal(t, numTxs-1, result.TotalCount)
+		require.Len(t, result.Txs, 2)
+
+		for index, tx := range result.Txs {
+			expected := txs[index+3]
+
+			assert.Equal(t, expected.Tx.Hash(), tx.Hash)
+			assert.Equal(t, expected.Height, tx.Height)
+			assert.Equal(t, expected.Response, tx.TxResult)
+			assert.Equal(t, expected.Tx, tx.Tx)
+		}
+
+		// Fetch an out-of-range page
+		result, err = TxSearch(nil, "tx.height > 1", 5, 2)
+		require.Nil(t, result)
+
+		assert.Error(t, err)
+	})
+}
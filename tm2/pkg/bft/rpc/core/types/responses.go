@@ -176,6 +176,12 @@ type ResultTxSearch struct {
 	TotalCount int         `json:"total_count"`
 }
 
+// Result of searching for blocks
+type ResultBlockSearch struct {
+	Blocks     []*ResultBlock `json:"blocks"`
+	TotalCount int            `json:"total_count"`
+}
+
 // List of mempool txs
 type ResultUnconfirmedTxs struct {
 	Count      int        `json:"n_txs"`
@@ -199,9 +205,12 @@ type (
 	ResultUnsafeFlushMempool struct{}
 	ResultUnsafeProfile      struct{}
 	ResultHealth             struct{}
+	ResultSubscribe          struct{}
+	ResultUnsubscribe        struct{}
 )
 
 // Event data from a subscription
 type ResultEvent struct {
+	Query string        `json:"query"`
 	Event types.TMEvent `json:"event"`
 }
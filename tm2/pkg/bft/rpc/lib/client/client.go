@@ -18,6 +18,20 @@ type Client interface {
 	Close() error
 }
 
+// Subscriber is the JSON-RPC client abstraction
+// for transports that support server-pushed notifications
+type Subscriber interface {
+	Client
+
+	// Subscribe sends the subscription request to the JSON-RPC layer,
+	// and returns the channel on which the subscription notifications are received.
+	// The channel is closed when the subscription is canceled, or the client is closed
+	Subscribe(context.Context, types.RPCRequest) (<-chan types.RPCResponse, error)
+
+	// Unsubscribe stops receiving notifications for the given subscription request ID
+	Unsubscribe(types.JSONRPCID)
+}
+
 // Batch is the JSON-RPC batch abstraction
 type Batch interface {
 	// AddRequest adds a single request to the RPC batch
@@ -18,8 +18,13 @@ var (
 	ErrTimedOut                  = errors.New("context timed out")
 	ErrRequestResponseIDMismatch = errors.New("ws request / response ID mismatch")
 	ErrInvalidBatchResponse      = errors.New("invalid ws batch response size")
+	ErrSubscriptionExists        = errors.New("ws subscription with the same request ID already exists")
 )
 
+// subscriptionBufferSize is the number of subscription
+// notifications buffered before new ones are dropped
+const subscriptionBufferSize = 100
+
 type responseCh chan<- types.RPCResponses
 
 // Client is a WebSocket client implementation
@@ -34,6 +39,9 @@ type Client struct {
 
 	requestMap    map[string]responseCh
 	requestMapMux sync.Mutex
+
+	subscriptions    map[string]chan types.RPCResponse // notification ID -> notification channel
+	subscriptionsMux sync.Mutex
 }
 
 // NewClient initializes and creates a new WS RPC client
@@ -45,10 +53,11 @@ func NewClient(rpcURL string, opts ...Option) (*Client, error) {
 	}
 
 	c := &Client{
-		conn:       conn,
-		requestMap: make(map[string]responseCh),
-		backlog:    make(chan any, 1),
-		logger:     log.NewNoopLogger(),
+		conn:          conn,
+		requestMap:    make(map[string]responseCh),
+		subscriptions: make(map[string]chan types.RPCResponse),
+		backlog:       make(chan any, 1),
+		logger:        log.NewNoopLogger(),
 	}
 
 	ctx, cancelFn := context.WithCancelCause(context.Background())
@@ -153,6 +162,86 @@ func (c *Client) SendBatch(ctx context.Context, requests types.RPCRequests) (typ
 	}
 }
 
+// Subscribe sends the subscription request to the server, and returns the channel on which
+// the subscription notifications are received. Notifications are the server responses
+// whose ID is the subscription request ID, suffixed by "#event"
+func (c *Client) Subscribe(ctx context.Context, request types.RPCRequest) (<-chan types.RPCResponse, error) {
+	var (
+		notificationID = subscriptionNotificationID(request.ID)
+		ch             = make(chan types.RPCResponse, subscriptionBufferSize)
+	)
+
+	// Register the notification channel before sending the request,
+	// so no notification is missed
+	c.subscriptionsMux.Lock()
+	if _, exists := c.subscriptions[notificationID]; exists {
+		c.subscriptionsMux.Unlock()
+
+		return nil, ErrSubscriptionExists
+	}
+
+	c.subscriptions[notificationID] = ch
+	c.subscriptionsMux.Unlock()
+
+	response, err := c.SendRequest(ctx, request)
+	if err != nil {
+		c.Unsubscribe(request.ID)
+
+		return nil, err
+	}
+
+	if response.Error != nil {
+		c.Unsubscribe(request.ID)
+
+		return nil, response.Error
+	}
+
+	return ch, nil
+}
+
+// Unsubscribe closes the notification channel of the
+// subscription with the given request ID, if any
+func (c *Client) Unsubscribe(id types.JSONRPCID) {
+	notificationID := subscriptionNotificationID(id)
+
+	c.subscriptionsMux.Lock()
+	defer c.subscriptionsMux.Unlock()
+
+	ch, ok := c.subscriptions[notificationID]
+	if !ok {
+		return
+	}
+
+	delete(c.subscriptions, notificationID)
+	close(ch)
+}
+
+// subscriptionNotificationID returns the ID of the
+// notifications for the given subscription request ID
+func subscriptionNotificationID(id types.JSONRPCID) string {
+	return fmt.Sprintf("%v#event", id)
+}
+
+// notifySubscriber delivers the given response to its subscription,
+// if any. Returns a flag indicating if the response is a subscription notification
+func (c *Client) notifySubscriber(response types.RPCResponse) bool {
+	c.subscriptionsMux.Lock()
+	defer c.subscriptionsMux.Unlock()
+
+	ch, ok := c.subscriptions[response.ID.String()]
+	if !ok {
+		return false
+	}
+
+	select {
+	case ch <- response:
+	default:
+		c.logger.Warn("subscription notification dropped", "id", response.ID)
+	}
+
+	return true
+}
+
 // generateIDHash generates a unique hash from the given IDs
 func generateIDHash(ids ...string) string {
 	hash := fnv.New128()
@@ -231,6 +320,11 @@ func (c *Client) runReadRoutine(ctx context.Context) {
 				continue
 			}
 
+			// Subscription notifications are not tied to a pending request
+			if response.ID != nil && c.notifySubscriber(response) {
+				continue
+			}
+
 			// This is a single response, generate the unique ID
 			responseHash = generateIDHash(response.ID.String())
 			responses = types.RPCResponses{response}
@@ -281,5 +375,13 @@ func (c *Client) Close() error {
 func (c *Client) closeWithCause(err error) error {
 	c.cancelCauseFn(err)
 
+	// Close any active subscription
+	c.subscriptionsMux.Lock()
+	for id, ch := range c.subscriptions {
+		delete(c.subscriptions, id)
+		close(ch)
+	}
+	c.subscriptionsMux.Unlock()
+
 	return c.conn.Close()
 }
@@ -300,3 +300,152 @@ func TestClient_SendBatch(t *testing.T) {
 		assert.Equal(t, response.Error, resp[0].Error)
 	})
 }
+
+func TestClient_Subscribe(t *testing.T) {
+	t.Parallel()
+
+	t.Run("subscription rejected", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			upgrader = websocket.Upgrader{}
+
+			request = types.RPCRequest{
+				JSONRPC: "2.0",
+				ID:      types.JSONRPCStringID("id"),
+			}
+		)
+
+		// Create the server
+		handler := func(w http.ResponseWriter, r *http.Request) {
+			c, err := upgrader.Upgrade(w, r, nil)
+			require.NoError(t, err)
+
+			defer c.Close()
+
+			for {
+				mt, message, err := c.ReadMessage()
+				if websocket.IsUnexpectedCloseError(err) {
+					return
+				}
+
+				require.NoError(t, err)
+
+				var req types.RPCRequest
+				require.NoError(t, json.Unmarshal(message, &req))
+
+				marshalledResponse, err := json.Marshal(
+					types.NewRPCErrorResponse(req.ID, -32603, "Internal error", "invalid query"),
+				)
+				require.NoError(t, err)
+
+				require.NoError(t, c.WriteMessage(mt, marshalledResponse))
+			}
+		}
+
+		s := createTestServer(t, http.HandlerFunc(handler))
+		url := "ws" + strings.TrimPrefix(s.URL, "http")
+
+		// Create the client
+		c, err := NewClient(url)
+		require.NoError(t, err)
+
+		defer func() {
+			assert.NoError(t, c.Close())
+		}()
+
+		ctx, cancelFn := context.WithTimeout(context.Background(), time.Second*5)
+		defer cancelFn()
+
+		ch, err := c.Subscribe(ctx, request)
+		require.Nil(t, ch)
+		assert.Error(t, err)
+
+		// Make sure the subscription was cleared
+		c.subscriptionsMux.Lock()
+		assert.Len(t, c.subscriptions, 0)
+		c.subscriptionsMux.Unlock()
+	})
+
+	t.Run("notifications received", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			upgrader = websocket.Upgrader{}
+
+			request = types.RPCRequest{
+				JSONRPC: "2.0",
+				ID:      types.JSONRPCStringID("id"),
+			}
+
+			notification = types.RPCResponse{
+				JSONRPC: "2.0",
+				ID:      types.JSONRPCStringID("id#event"),
+				Result:  []byte(`{"query":"tm.event = 'Tx'"}`),
+			}
+		)
+
+		// Create the server
+		handler := func(w http.ResponseWriter, r *http.Request) {
+			c, err := upgrader.Upgrade(w, r, nil)
+			require.NoError(t, err)
+
+			defer c.Close()
+
+			for {
+				mt, message, err := c.ReadMessage()
+				if websocket.IsUnexpectedCloseError(err) {
+					return
+				}
+
+				require.NoError(t, err)
+
+				var req types.RPCRequest
+				require.NoError(t, json.Unmarshal(message, &req))
+				require.Equal(t, request.ID.String(), req.ID.String())
+
+				// Respond to the subscription, and push a notification
+				for _, resp := range []types.RPCResponse{
+					{JSONRPC: "2.0", ID: req.ID, Result: []byte("{}")},
+					notification,
+				} {
+					marshalledResponse, err := json.Marshal(resp)
+					require.NoError(t, err)
+
+					require.NoError(t, c.WriteMessage(mt, marshalledResponse))
+				}
+			}
+		}
+
+		s := createTestServer(t, http.HandlerFunc(handler))
+		url := "ws" + strings.TrimPrefix(s.URL, "http")
+
+		// Create the client
+		c, err := NewClient(url)
+		require.NoError(t, err)
+
+		defer func() {
+			assert.NoError(t, c.Close())
+		}()
+
+		ctx, cancelFn := context.WithTimeout(context.Background(), time.Second*5)
+		defer cancelFn()
+
+		ch, err := c.Subscribe(ctx, request)
+		require.NoError(t, err)
+
+		select {
+		case resp := <-ch:
+			assert.Equal(t, notification.ID, resp.ID)
+			assert.JSONEq(t, string(notification.Result), string(resp.Result))
+		case <-ctx.Done():
+			t.Fatal("notification not received")
+		}
+
+		// Make sure the channel is closed on unsubscribe
+		c.Unsubscribe(request.ID)
+
+		_, more := <-ch
+		assert.False(t, more)
+	})
+}
@@ -0,0 +1,548 @@
+package kv
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	"github.com/gnolang/gno/tm2/pkg/bft/state/eventstore"
+	"github.com/gnolang/gno/tm2/pkg/bft/state/eventstore/query"
+	storetypes "github.com/gnolang/gno/tm2/pkg/bft/state/eventstore/types"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	dbm "github.com/gnolang/gno/tm2/pkg/db"
+)
+
+var (
+	_ eventstore.TxEventStore    = (*TxEventStore)(nil)
+	_ eventstore.BlockEventStore = (*TxEventStore)(nil)
+	_ eventstore.Searcher        = (*TxEventStore)(nil)
+)
+
+const (
+	EventStoreType = "kv"
+)
+
+var (
+	errInvalidType   = errors.New("invalid config for kv event store specified")
+	errMissingDB     = errors.New("missing event store database")
+	errTxNotFound    = errors.New("transaction not found")
+	errInvalidHeight = errors.New("invalid height value")
+	errInvalidHash   = errors.New("invalid hash value")
+	errInvalidOp     = errors.New("unsupported operator for key")
+
+	errTooManyMatches = errors.New("too many matches, narrow down the height range of the condition")
+)
+
+// Key layout:
+//
+//	tx/<hash>                               -> amino(TxResult)
+//	txh/<height><index>                     -> hash
+//	txe/<key>\x00<value>\x00<height><index> -> hash
+//	blk/<height>                            -> nil
+//	blke/<key>\x00<value>\x00<height>       -> nil
+//
+// Heights are 8-byte and transaction indexes 4-byte big endian
+// integers, so iteration follows the chain order
+var (
+	txResultPrefix   = []byte("tx/")
+	txHeightPrefix   = []byte("txh/")
+	txEventPrefix    = []byte("txe/")
+	blockPrefix      = []byte("blk/")
+	blockEventPrefix = []byte("blke/")
+)
+
+const (
+	separator = byte(0)
+
+	// maxRangeMatches is the maximum number of matches
+	// of a range condition on an attribute, as they are
+	// loaded before the search
+	maxRangeMatches = 100_000
+
+	heightLen = 8
+	txPosLen  = heightLen + 4
+)
+
+// TxEventStore is the implementation of a transaction event store
+// that indexes transaction and block events in a key-value database,
+// so they can later be searched
+type TxEventStore struct {
+	db dbm.DB
+}
+
+// NewTxEventStore creates a new kv-based tx event store,
+// that uses the given database
+func NewTxEventStore(cfg *storetypes.Config, db dbm.DB) (*TxEventStore, error) {
+	if EventStoreType != cfg.EventStoreType {
+		return nil, errInvalidType
+	}
+
+	if db == nil {
+		return nil, errMissingDB
+	}
+
+	return &TxEventStore{
+		db: db,
+	}, nil
+}
+
+// Start starts the kv transaction event store
+func (t *TxEventStore) Start() error {
+	return nil
+}
+
+// Stop stops the kv transaction event store, by closing the database
+func (t *TxEventStore) Stop() error {
+	t.db.Close()
+
+	return nil
+}
+
+// GetType returns the kv transaction event store type
+func (t *TxEventStore) GetType() string {
+	return EventStoreType
+}
+
+// Append stores the transaction result, and indexes its attributes
+func (t *TxEventStore) Append(result types.TxResult) error {
+	var (
+		hash  = result.Tx.Hash()
+		pos   = txPosition(result.Height, result.Index)
+		batch = t.db.NewBatch()
+	)
+	defer batch.Close()
+
+	raw, err := amino.Marshal(result)
+	if err != nil {
+		return fmt.Errorf("unable to marshal transaction, %w", err)
+	}
+
+	batch.Set(prefixed(txResultPrefix, hash), raw)
+	batch.Set(prefixed(txHeightPrefix, pos), hash)
+
+	for key, values := range query.TxAttributes(result) {
+		// The hash and height have dedicated indexes,
+		// and the kind is the same for every transaction
+		if key == query.TxHashKey || key == query.TxHeightKey || key == query.EventKindKey {
+			continue
+		}
+
+		for _, value := range values {
+			batch.Set(attributeKey(txEventPrefix, key, value, pos), hash)
+		}
+	}
+
+	batch.WriteSync()
+
+	return nil
+}
+
+// AppendBlock indexes the attributes of the given block
+func (t *TxEventStore) AppendBlock(block types.EventNewBlockHeader) error {
+	var (
+		height = heightBytes(block.Header.Height)
+		batch  = t.db.NewBatch()
+	)
+	defer batch.Close()
+
+	batch.Set(prefixed(blockPrefix, height), []byte{})
+
+	attrs := query.BlockAttributes(block.Header, block.ResultBeginBlock, block.ResultEndBlock)
+
+	for key, values := range attrs {
+		if key == query.BlockHeightKey || key == query.EventKindKey {
+			continue
+		}
+
+		for _, value := range values {
+			batch.Set(attributeKey(blockEventPrefix, key, value, height), []byte{})
+		}
+	}
+
+	batch.WriteSync()
+
+	return nil
+}
+
+// GetTx returns the stored transaction result for the given hash
+func (t *TxEventStore) GetTx(hash []byte) (*types.TxResult, error) {
+	raw := t.db.Get(prefixed(txResultPrefix, hash))
+	if raw == nil {
+		return nil, errTxNotFound
+	}
+
+	var result types.TxResult
+	if err := amino.Unmarshal(raw, &result); err != nil {
+		return nil, fmt.Errorf("unable to unmarshal transaction, %w", err)
+	}
+
+	return &result, nil
+}
+
+// SearchTxs returns the hashes of the first transactions matching
+// the query, in chain order, at most limit of them
+func (t *TxEventStore) SearchTxs(q *query.Query, limit int) ([][]byte, error) {
+	var (
+		hashes = [][]byte{}
+		conds  = make([]query.Condition, 0, len(q.Conditions))
+
+		start, end = positionRange(txPosLen)
+	)
+
+	for _, cond := range q.Conditions {
+		if cond.Key != query.TxHashKey {
+			conds = append(conds, cond)
+
+			continue
+		}
+
+		// The hash narrows down the search to a single position
+		if cond.Op != query.OpEqual {
+			return nil, fmt.Errorf("%w %s, %s", errInvalidOp, cond.Key, cond.Op)
+		}
+
+		hash, err := hex.DecodeString(cond.Value)
+		if err != nil {
+			return nil, fmt.Errorf("%w, %w", errInvalidHash, err)
+		}
+
+		result, err := t.GetTx(hash)
+		if err != nil {
+			// No matching transaction
+			return hashes, nil //nolint:nilerr
+		}
+
+		pos := txPosition(result.Height, result.Index)
+		start, end = maxBytes(start, pos), minBytes(end, append(pos, 0x00))
+	}
+
+	err := t.search(txIndex, conds, start, end, limit, func(_, hash []byte) {
+		hashes = append(hashes, slices.Clone(hash))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return hashes, nil
+}
+
+// SearchBlocks returns the heights of the first blocks matching
+// the query, in ascending order, at most limit of them
+func (t *TxEventStore) SearchBlocks(q *query.Query, limit int) ([]int64, error) {
+	var (
+		heights    = []int64{}
+		start, end = positionRange(heightLen)
+	)
+
+	err := t.search(blockIndex, q.Conditions, start, end, limit, func(pos, _ []byte) {
+		heights = append(heights, int64(binary.BigEndian.Uint64(pos)))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return heights, nil
+}
+
+// index is the key layout of the transactions, or of the blocks
+type index struct {
+	posPrefix  []byte // <posPrefix><pos> -> value
+	attrPrefix []byte // <attrPrefix><key>\x00<value>\x00<pos> -> value
+	posLen     int
+	heightKey  string
+	kind       string
+}
+
+var (
+	txIndex    = index{txHeightPrefix, txEventPrefix, txPosLen, query.TxHeightKey, query.EventKindTx}
+	blockIndex = index{blockPrefix, blockEventPrefix, heightLen, query.BlockHeightKey, query.EventKindNewBlock}
+)
+
+// search calls fn with the position and value of the first entries of
+// the index that match all the conditions, in chain order, at most limit
+// of them. Only the positions within [start, end) are searched.
+//
+// The entries are iterated in chain order, and the search stops once
+// limit entries match, so only the matches of the attribute range
+// conditions are loaded beforehand, up to maxRangeMatches per condition
+func (t *TxEventStore) search(
+	idx index,
+	conds []query.Condition,
+	start, end []byte,
+	limit int,
+	fn func(pos, value []byte),
+) error {
+	var (
+		none        bool
+		equalConds  []query.Condition
+		rangeConds  []query.Condition
+		rangeFounds []map[string][]byte
+	)
+
+	for _, cond := range conds {
+		switch {
+		case cond.Key == idx.heightKey:
+			first, next, err := heightRange(cond, idx.posLen)
+			if err != nil {
+				return err
+			}
+
+			start, end = maxBytes(start, first), minBytes(end, next)
+		case cond.Key == query.EventKindKey:
+			// The kind is the same for every entry, so it is not
+			// indexed: either all entries match, or none do
+			none = none || !cond.Matches(idx.kind)
+		case cond.Op == query.OpEqual:
+			equalConds = append(equalConds, cond)
+		default:
+			rangeConds = append(rangeConds, cond)
+		}
+	}
+
+	if none || limit <= 0 || bytes.Compare(start, end) >= 0 {
+		return nil
+	}
+
+	for _, cond := range rangeConds {
+		found, err := t.searchAttribute(idx, cond, start, end)
+		if err != nil {
+			return err
+		}
+
+		if len(found) == 0 {
+			return nil
+		}
+
+		rangeFounds = append(rangeFounds, found)
+	}
+
+	// visit calls fn if the entry matches the conditions,
+	// and returns whether the search goes on
+	count := 0
+	visit := func(pos, value []byte) bool {
+		for _, cond := range equalConds {
+			if !t.db.Has(attributeKey(idx.attrPrefix, cond.Key, cond.Value, pos)) {
+				return true
+			}
+		}
+
+		for _, found := range rangeFounds {
+			if _, ok := found[string(pos)]; !ok {
+				return true
+			}
+		}
+
+		fn(pos, value)
+		count++
+
+		return count < limit
+	}
+
+	switch {
+	case len(rangeFounds) > 0:
+		// Go over the fewest matches of a range condition
+		found := slices.MinFunc(rangeFounds, func(a, b map[string][]byte) int {
+			return len(a) - len(b)
+		})
+
+		for _, pos := range sortedKeys(found) {
+			if !visit([]byte(pos), found[pos]) {
+				break
+			}
+		}
+	case len(equalConds) > 0:
+		// Go over the entries with the attribute value
+		keyPrefix := attributeKey(idx.attrPrefix, equalConds[0].Key, equalConds[0].Value, nil)
+
+		it := t.db.Iterator(prefixed(keyPrefix, start), prefixed(keyPrefix, end))
+		defer it.Close()
+
+		for ; it.Valid(); it.Next() {
+			pos := it.Key()[len(keyPrefix):]
+			if len(pos) != idx.posLen {
+				continue
+			}
+
+			if !visit(pos, it.Value()) {
+				break
+			}
+		}
+	default:
+		it := t.db.Iterator(prefixed(idx.posPrefix, start), prefixed(idx.posPrefix, end))
+		defer it.Close()
+
+		for ; it.Valid(); it.Next() {
+			if !visit(it.Key()[len(idx.posPrefix):], it.Value()) {
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
+// searchAttribute looks up the attribute index for the entries
+// within [start, end) matching the range condition, and returns
+// their positions mapped to their value
+func (t *TxEventStore) searchAttribute(
+	idx index,
+	cond query.Condition,
+	start, end []byte,
+) (map[string][]byte, error) {
+	// Ranges need to go over all values of the key
+	keyPrefix := append(prefixed(idx.attrPrefix, []byte(cond.Key)), separator)
+	found := make(map[string][]byte)
+
+	it := dbm.IteratePrefix(t.db, keyPrefix)
+	defer it.Close()
+
+	for ; it.Valid(); it.Next() {
+		key := it.Key()
+
+		// Extract the value, which is everything between
+		// the key and the fixed-length position
+		valueEnd := len(key) - idx.posLen - 1
+		if valueEnd < len(keyPrefix) {
+			continue
+		}
+
+		pos := key[len(key)-idx.posLen:]
+		if bytes.Compare(pos, start) < 0 || bytes.Compare(pos, end) >= 0 {
+			continue
+		}
+
+		if !cond.Matches(string(key[len(keyPrefix):valueEnd])) {
+			continue
+		}
+
+		if len(found) == maxRangeMatches {
+			return nil, fmt.Errorf("%w %s", errTooManyMatches, cond)
+		}
+
+		found[string(pos)] = slices.Clone(it.Value())
+	}
+
+	return found, nil
+}
+
+// heightRange returns the [start, end) position range of the
+// given height condition, for positions of length posLen
+func heightRange(cond query.Condition, posLen int) ([]byte, []byte, error) {
+	height, err := strconv.ParseInt(cond.Value, 10, 64)
+	if err != nil || height < 0 {
+		return nil, nil, fmt.Errorf("%w %q", errInvalidHeight, cond.Value)
+	}
+
+	var (
+		lowest, highest = positionRange(posLen)
+
+		first = padded(heightBytes(height), posLen, 0x00)
+		next  = padded(heightBytes(height+1), posLen, 0x00)
+	)
+
+	switch cond.Op {
+	case query.OpEqual:
+		return first, next, nil
+	case query.OpLess:
+		return lowest, first, nil
+	case query.OpLessEqual:
+		return lowest, next, nil
+	case query.OpGreater:
+		return next, highest, nil
+	case query.OpGreaterEqual:
+		return first, highest, nil
+	default:
+		return nil, nil, fmt.Errorf("%w %s, %s", errInvalidOp, cond.Key, cond.Op)
+	}
+}
+
+// positionRange returns the [start, end) range
+// of all the positions of length posLen
+func positionRange(posLen int) ([]byte, []byte) {
+	// Heights are positive, so no position
+	// can be greater or equal to the upper bound
+	return padded(heightBytes(0), posLen, 0x00), padded(heightBytes(-1), posLen, 0xFF)
+}
+
+// maxBytes returns the greatest of the two byte slices
+func maxBytes(a, b []byte) []byte {
+	if bytes.Compare(a, b) >= 0 {
+		return a
+	}
+
+	return b
+}
+
+// minBytes returns the smallest of the two byte slices
+func minBytes(a, b []byte) []byte {
+	if bytes.Compare(a, b) <= 0 {
+		return a
+	}
+
+	return b
+}
+
+// sortedKeys returns the sorted keys of the map
+func sortedKeys(m map[string][]byte) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+
+	slices.Sort(keys)
+
+	return keys
+}
+
+// attributeKey constructs the index key for the given attribute
+func attributeKey(prefix []byte, key, value string, pos []byte) []byte {
+	var buf bytes.Buffer
+
+	buf.Grow(len(prefix) + len(key) + len(value) + len(pos) + 2)
+
+	buf.Write(prefix)
+	buf.WriteString(key)
+	buf.WriteByte(separator)
+	buf.WriteString(value)
+	buf.WriteByte(separator)
+	buf.Write(pos)
+
+	return buf.Bytes()
+}
+
+// txPosition returns the chain position of the transaction
+func txPosition(height int64, index uint32) []byte {
+	pos := make([]byte, txPosLen)
+
+	binary.BigEndian.PutUint64(pos, uint64(height))
+	binary.BigEndian.PutUint32(pos[heightLen:], index)
+
+	return pos
+}
+
+// heightBytes returns the big endian representation of the height
+func heightBytes(height int64) []byte {
+	bz := make([]byte, heightLen)
+	binary.BigEndian.PutUint64(bz, uint64(height))
+
+	return bz
+}
+
+// padded pads the byte slice to the given length using the filler byte
+func padded(bz []byte, length int, filler byte) []byte {
+	for len(bz) < length {
+		bz = append(bz, filler)
+	}
+
+	return bz
+}
+
+// prefixed returns the key with the given prefix prepended
+func prefixed(prefix, key []byte) []byte {
+	return append(slices.Clone(prefix), key...)
+}
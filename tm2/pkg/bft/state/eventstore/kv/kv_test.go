@@ -0,0 +1,316 @@
+package kv
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	"github.com/gnolang/gno/tm2/pkg/bft/state/eventstore/query"
+	storetypes "github.com/gnolang/gno/tm2/pkg/bft/state/eventstore/types"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/db/memdb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testEventAttribute struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// testEvent mimics the layout of application (Gno) events
+type testEvent struct {
+	Type       string               `json:"type"`
+	Attributes []testEventAttribute `json:"attrs"`
+	PkgPath    string               `json:"pkg_path"`
+	Func       string               `json:"func"`
+}
+
+func (testEvent) AssertABCIEvent() {}
+
+var _ = amino.RegisterPackage(amino.NewPackage(
+	"github.com/gnolang/gno/tm2/pkg/bft/state/eventstore/kv",
+	"kv",
+	amino.GetCallersDirname(),
+).
+	WithDependencies(
+		abci.Package,
+	).
+	WithTypes(
+		testEventAttribute{},
+		testEvent{},
+	))
+
+// newTestStore creates a new in-memory kv event store
+func newTestStore(t *testing.T) *TxEventStore {
+	t.Helper()
+
+	store, err := NewTxEventStore(
+		&storetypes.Config{EventStoreType: EventStoreType},
+		memdb.NewMemDB(),
+	)
+	require.NoError(t, err)
+
+	return store
+}
+
+// newTxResult generates a transaction result with the given events
+func newTxResult(height int64, index uint32, events ...abci.Event) types.TxResult {
+	return types.TxResult{
+		Height: height,
+		Index:  index,
+		Tx:     []byte{byte(height), byte(index)},
+		Response: abci.ResponseDeliverTx{
+			ResponseBase: abci.ResponseBase{
+				Events: events,
+			},
+		},
+	}
+}
+
+func TestTxEventStore_New(t *testing.T) {
+	t.Parallel()
+
+	t.Run("invalid type", func(t *testing.T) {
+		t.Parallel()
+
+		s, err := NewTxEventStore(&storetypes.Config{EventStoreType: "invalid"}, memdb.NewMemDB())
+
+		assert.Nil(t, s)
+		assert.ErrorIs(t, err, errInvalidType)
+	})
+
+	t.Run("missing db", func(t *testing.T) {
+		t.Parallel()
+
+		s, err := NewTxEventStore(&storetypes.Config{EventStoreType: EventStoreType}, nil)
+
+		assert.Nil(t, s)
+		assert.ErrorIs(t, err, errMissingDB)
+	})
+}
+
+func TestTxEventStore_SearchTxs(t *testing.T) {
+	t.Parallel()
+
+	var (
+		store = newTestStore(t)
+
+		transfer = func(to string) abci.Event {
+			return testEvent{
+				Type:    "Transfer",
+				PkgPath: "gno.land/r/demo/foo",
+				Func:    "Send",
+				Attributes: []testEventAttribute{
+					{Key: "to", Value: to},
+				},
+			}
+		}
+
+		results = []types.TxResult{
+			newTxResult(1, 0, transfer("g1alice")),
+			newTxResult(1, 1, abci.EventString("Mint")),
+			newTxResult(2, 0, transfer("g1bob")),
+			newTxResult(3, 0, transfer("g1alice"), abci.EventString("Mint")),
+			newTxResult(256, 0),
+		}
+	)
+
+	for _, result := range results {
+		require.NoError(t, store.Append(result))
+	}
+
+	testTable := []struct {
+		name     string
+		query    string
+		expected []types.TxResult
+	}{
+		{
+			"by height",
+			"tx.height = 1",
+			results[0:2],
+		},
+		{
+			"by height range",
+			"tx.height > 1 AND tx.height <= 256",
+			results[2:5],
+		},
+		{
+			"by event type",
+			"event.type = 'Mint'",
+			[]types.TxResult{results[1], results[3]},
+		},
+		{
+			"by pkg path and attribute",
+			"event.pkg_path = 'gno.land/r/demo/foo' AND Transfer.to = 'g1alice'",
+			[]types.TxResult{results[0], results[3]},
+		},
+		{
+			"by attribute range",
+			"Transfer.to > 'g1alice'",
+			[]types.TxResult{results[2]},
+		},
+		{
+			"by hash",
+			fmt.Sprintf("tx.hash = '%X'", results[2].Tx.Hash()),
+			[]types.TxResult{results[2]},
+		},
+		{
+			"by hash and height",
+			fmt.Sprintf("tx.hash = '%X' AND tx.height > 2", results[2].Tx.Hash()),
+			[]types.TxResult{},
+		},
+		{
+			"by attribute range and event type",
+			"Transfer.to >= 'g1alice' AND event.type = 'Mint'",
+			[]types.TxResult{results[3]},
+		},
+		{
+			"by attribute range and height",
+			"Transfer.to < 'g1bob' AND tx.height >= 2",
+			[]types.TxResult{results[3]},
+		},
+		{
+			"no matches",
+			"event.type = 'Mint' AND tx.height < 1",
+			[]types.TxResult{},
+		},
+		{
+			"by tx kind",
+			"tm.event = 'Tx'",
+			results,
+		},
+		{
+			"by tx kind and event type",
+			"tm.event = 'Tx' AND event.type = 'Mint'",
+			[]types.TxResult{results[1], results[3]},
+		},
+		{
+			"by block kind",
+			"tm.event = 'NewBlock'",
+			[]types.TxResult{},
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			hashes, err := store.SearchTxs(query.MustParse(testCase.query), 100)
+			require.NoError(t, err)
+			require.Len(t, hashes, len(testCase.expected))
+
+			for index, hash := range hashes {
+				assert.Equal(t, testCase.expected[index].Tx.Hash(), hash)
+
+				result, err := store.GetTx(hash)
+				require.NoError(t, err)
+
+				assert.Equal(t, testCase.expected[index].Height, result.Height)
+				assert.Equal(t, testCase.expected[index].Index, result.Index)
+			}
+		})
+	}
+}
+
+func TestTxEventStore_SearchTxs_Limit(t *testing.T) {
+	t.Parallel()
+
+	store := newTestStore(t)
+
+	results := make([]types.TxResult, 0, 9)
+	for height := int64(1); height <= 9; height++ {
+		result := newTxResult(height, 0, abci.EventString("Mint"))
+		require.NoError(t, store.Append(result))
+
+		results = append(results, result)
+	}
+
+	// The search stops at the first matches, in chain order
+	for _, q := range []string{"tm.event = 'Tx'", "event.type = 'Mint'", "event.type >= 'Mint'"} {
+		hashes, err := store.SearchTxs(query.MustParse(q), 3)
+		require.NoError(t, err)
+		require.Len(t, hashes, 3)
+
+		for index, hash := range hashes {
+			assert.Equal(t, results[index].Tx.Hash(), hash)
+		}
+	}
+
+	hashes, err := store.SearchTxs(query.MustParse("tx.height > 7"), 3)
+	require.NoError(t, err)
+	assert.Equal(t, [][]byte{results[7].Tx.Hash(), results[8].Tx.Hash()}, hashes)
+
+	hashes, err = store.SearchTxs(query.MustParse("tm.event = 'Tx'"), 0)
+	require.NoError(t, err)
+	assert.Empty(t, hashes)
+}
+
+func TestTxEventStore_SearchTxs_Invalid(t *testing.T) {
+	t.Parallel()
+
+	store := newTestStore(t)
+
+	_, err := store.SearchTxs(query.MustParse("tx.height = 'abc'"), 100)
+	assert.ErrorIs(t, err, errInvalidHeight)
+
+	_, err = store.SearchTxs(query.MustParse("tx.hash > 'AB'"), 100)
+	assert.ErrorIs(t, err, errInvalidOp)
+
+	_, err = store.SearchTxs(query.MustParse("tx.hash = 'XYZ'"), 100)
+	assert.ErrorIs(t, err, errInvalidHash)
+}
+
+func TestTxEventStore_SearchBlocks(t *testing.T) {
+	t.Parallel()
+
+	store := newTestStore(t)
+
+	for height := int64(1); height <= 5; height++ {
+		block := types.EventNewBlockHeader{
+			Header: types.Header{
+				Height: height,
+			},
+		}
+
+		// Emit an end block event on even heights
+		if height%2 == 0 {
+			block.ResultEndBlock.Events = []abci.Event{
+				abci.EventString("ValidatorsChanged"),
+			}
+		}
+
+		require.NoError(t, store.AppendBlock(block))
+	}
+
+	testTable := []struct {
+		name     string
+		query    string
+		expected []int64
+	}{
+		{"by height", "block.height = 3", []int64{3}},
+		{"by height range", "block.height >= 2 AND block.height < 5", []int64{2, 3, 4}},
+		{"by event", "event.type = 'ValidatorsChanged'", []int64{2, 4}},
+		{"by event and height", "event.type = 'ValidatorsChanged' AND block.height > 2", []int64{4}},
+		{"no matches", "block.height > 5", []int64{}},
+		{"by block kind", "tm.event = 'NewBlock'", []int64{1, 2, 3, 4, 5}},
+		{"by block kind and event", "tm.event = 'NewBlock' AND event.type = 'ValidatorsChanged'", []int64{2, 4}},
+		{"by tx kind", "tm.event = 'Tx'", []int64{}},
+	}
+
+	heights, err := store.SearchBlocks(query.MustParse("block.height > 1"), 2)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{2, 3}, heights)
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			heights, err := store.SearchBlocks(query.MustParse(testCase.query), 100)
+			require.NoError(t, err)
+
+			assert.Equal(t, testCase.expected, heights)
+		})
+	}
+}
@@ -54,6 +54,24 @@ func (m mockEventStore) Append(result types.TxResult) error {
 	return nil
 }
 
+// BlockEventStore //
+
+type appendBlockDelegate func(types.EventNewBlockHeader) error
+
+type mockBlockEventStore struct {
+	mockEventStore
+
+	appendBlockFn appendBlockDelegate
+}
+
+func (m mockBlockEventStore) AppendBlock(block types.EventNewBlockHeader) error {
+	if m.appendBlockFn != nil {
+		return m.appendBlockFn(block)
+	}
+
+	return nil
+}
+
 // EventSwitch //
 
 type (
@@ -0,0 +1,143 @@
+package query
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/std"
+)
+
+// Attributes are the queryable key -> values pairs
+// of a transaction or a block
+type Attributes map[string][]string
+
+// add appends the value under the given key
+func (a Attributes) add(key, value string) {
+	a[key] = append(a[key], value)
+}
+
+// TxAttributes returns the queryable attributes of the given transaction result
+func TxAttributes(result types.TxResult) Attributes {
+	attrs := txAttributes(result)
+	attrs.add(EventKindKey, EventKindTx)
+
+	addEvents(attrs, result.Response.Events)
+
+	return attrs
+}
+
+// EventAttributes returns the queryable attributes of a single event
+// emitted by the given transaction, along with the transaction attributes
+func EventAttributes(result types.TxResult, ev abci.Event) Attributes {
+	attrs := txAttributes(result)
+	attrs.add(EventKindKey, EventKindTxEvent)
+
+	addEvents(attrs, []abci.Event{ev})
+
+	return attrs
+}
+
+// BlockAttributes returns the queryable attributes of the given block
+func BlockAttributes(
+	header types.Header,
+	beginBlock abci.ResponseBeginBlock,
+	endBlock abci.ResponseEndBlock,
+) Attributes {
+	attrs := Attributes{}
+
+	attrs.add(EventKindKey, EventKindNewBlock)
+	attrs.add(BlockHeightKey, strconv.FormatInt(header.Height, 10))
+
+	addEvents(attrs, beginBlock.Events)
+	addEvents(attrs, endBlock.Events)
+
+	return attrs
+}
+
+// txAttributes returns the event-independent attributes of the given transaction
+func txAttributes(result types.TxResult) Attributes {
+	attrs := Attributes{}
+
+	attrs.add(TxHashKey, fmt.Sprintf("%X", result.Tx.Hash()))
+	attrs.add(TxHeightKey, strconv.FormatInt(result.Height, 10))
+
+	// Signers are only extracted from well-formed std transactions,
+	// whose messages are registered with amino
+	var tx std.Tx
+	if err := amino.Unmarshal(result.Tx, &tx); err == nil {
+		for _, signer := range tx.GetSigners() {
+			attrs.add(TxSignerKey, signer.String())
+		}
+	}
+
+	return attrs
+}
+
+// eventFields are the indexed fields of an ABCI event,
+// matching the JSON layout of Gno events. Events that are not
+// in this shape are indexed only by their Go type name
+type eventFields struct {
+	Type       string `json:"type"`
+	PkgPath    string `json:"pkg_path"`
+	Func       string `json:"func"`
+	Attributes []struct {
+		Key   string `json:"key"`
+		Value string `json:"value"`
+	} `json:"attrs"`
+}
+
+// addEvents appends the attributes of the given ABCI events
+func addEvents(attrs Attributes, events []abci.Event) {
+	for _, ev := range events {
+		fields := parseEvent(ev)
+		if fields.Type == "" {
+			continue
+		}
+
+		attrs.add(EventTypeKey, fields.Type)
+
+		if fields.PkgPath != "" {
+			attrs.add(EventPkgPathKey, fields.PkgPath)
+		}
+
+		if fields.Func != "" {
+			attrs.add(EventFuncKey, fields.Func)
+		}
+
+		for _, attr := range fields.Attributes {
+			attrs.add(fields.Type+"."+attr.Key, attr.Value)
+		}
+	}
+}
+
+// parseEvent extracts the indexed fields out of an ABCI event.
+// The application-specific event types are not known to tm2,
+// so their JSON form is used instead
+func parseEvent(ev abci.Event) eventFields {
+	if ev == nil {
+		return eventFields{}
+	}
+
+	if str, ok := ev.(abci.EventString); ok {
+		return eventFields{Type: string(str)}
+	}
+
+	var fields eventFields
+
+	raw, err := json.Marshal(ev)
+	if err == nil {
+		_ = json.Unmarshal(raw, &fields)
+	}
+
+	if fields.Type == "" {
+		// Fallback to the Go type name
+		fields.Type = reflect.Indirect(reflect.ValueOf(ev)).Type().Name()
+	}
+
+	return fields
+}
@@ -0,0 +1,328 @@
+// Package query implements the minimal query language used to search
+// and filter transaction and block events.
+//
+// A query is a list of conditions joined by AND, where each condition
+// compares an attribute key with a value:
+//
+//	tx.height >= 10 AND event.type = 'Transfer' AND Transfer.to = 'g1jg8mtutu9khhfwc4nxmuhcpftf0pajdhfvsqf5'
+//
+// String values are single-quoted, numeric values can be written as-is.
+// Range operators (<, <=, >, >=) compare values numerically when both
+// sides are integers, and lexicographically otherwise.
+package query
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Reserved attribute keys. Any other key is interpreted
+// as an event attribute, in the form <EventType>.<AttributeKey>
+const (
+	TxHashKey      = "tx.hash"
+	TxHeightKey    = "tx.height"
+	TxSignerKey    = "tx.signer"
+	BlockHeightKey = "block.height"
+
+	EventTypeKey    = "event.type"
+	EventPkgPathKey = "event.pkg_path"
+	EventFuncKey    = "event.func"
+
+	// EventKindKey is the kind of the queried item,
+	// used to filter event subscriptions
+	EventKindKey = "tm.event"
+)
+
+// Event kinds, values of EventKindKey
+const (
+	EventKindNewBlock = "NewBlock"
+	EventKindTx       = "Tx"
+	EventKindTxEvent  = "TxEvent"
+)
+
+var (
+	ErrEmptyQuery      = errors.New("empty query")
+	errInvalidOperator = errors.New("invalid operator")
+	errMissingValue    = errors.New("missing value")
+	errMissingAnd      = errors.New("conditions should be joined by AND")
+	errUnterminatedStr = errors.New("unterminated string")
+)
+
+// Operator is a condition comparison operator
+type Operator int
+
+const (
+	OpEqual Operator = iota
+	OpLess
+	OpLessEqual
+	OpGreater
+	OpGreaterEqual
+)
+
+// String returns the textual representation of the operator
+func (o Operator) String() string {
+	switch o {
+	case OpEqual:
+		return "="
+	case OpLess:
+		return "<"
+	case OpLessEqual:
+		return "<="
+	case OpGreater:
+		return ">"
+	case OpGreaterEqual:
+		return ">="
+	default:
+		return "?"
+	}
+}
+
+// Condition is a single <key> <op> <value> comparison
+type Condition struct {
+	Key   string
+	Op    Operator
+	Value string
+}
+
+// String returns the textual representation of the condition
+func (c Condition) String() string {
+	if _, err := strconv.ParseInt(c.Value, 10, 64); err == nil {
+		return fmt.Sprintf("%s %s %s", c.Key, c.Op, c.Value)
+	}
+
+	return fmt.Sprintf("%s %s '%s'", c.Key, c.Op, c.Value)
+}
+
+// Matches returns a flag indicating if the given
+// value satisfies the condition
+func (c Condition) Matches(value string) bool {
+	cmp := compare(value, c.Value)
+
+	switch c.Op {
+	case OpEqual:
+		return cmp == 0
+	case OpLess:
+		return cmp < 0
+	case OpLessEqual:
+		return cmp <= 0
+	case OpGreater:
+		return cmp > 0
+	case OpGreaterEqual:
+		return cmp >= 0
+	default:
+		return false
+	}
+}
+
+// compare compares the two values, numerically if they
+// are both integers, and lexicographically otherwise
+func compare(a, b string) int {
+	ai, errA := strconv.ParseInt(a, 10, 64)
+	bi, errB := strconv.ParseInt(b, 10, 64)
+
+	if errA != nil || errB != nil {
+		return strings.Compare(a, b)
+	}
+
+	switch {
+	case ai < bi:
+		return -1
+	case ai > bi:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// Query is a parsed list of conditions, that all need to match
+type Query struct {
+	Conditions []Condition
+}
+
+// Parse parses the given query string
+func Parse(s string) (*Query, error) {
+	tokens, err := tokenize(s)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(tokens) == 0 {
+		return nil, ErrEmptyQuery
+	}
+
+	q := &Query{}
+
+	for i := 0; i < len(tokens); {
+		// Every condition after the first one is preceded by AND
+		if i > 0 {
+			if !strings.EqualFold(tokens[i].text, "AND") || tokens[i].quoted {
+				return nil, fmt.Errorf("%w, got %q", errMissingAnd, tokens[i].text)
+			}
+
+			i++
+		}
+
+		if i+2 >= len(tokens) {
+			return nil, fmt.Errorf("%w for condition at %q", errMissingValue, tokens[min(i, len(tokens)-1)].text)
+		}
+
+		key, op, value := tokens[i], tokens[i+1], tokens[i+2]
+		if key.quoted || key.isOperator() {
+			return nil, fmt.Errorf("invalid key %q", key.text)
+		}
+
+		operator, err := parseOperator(op)
+		if err != nil {
+			return nil, err
+		}
+
+		if value.isOperator() {
+			return nil, fmt.Errorf("%w for key %q", errMissingValue, key.text)
+		}
+
+		cond := Condition{
+			Key:   key.text,
+			Op:    operator,
+			Value: value.text,
+		}
+
+		// Hashes are matched in their uppercase hex form
+		if cond.Key == TxHashKey {
+			cond.Value = strings.ToUpper(cond.Value)
+		}
+
+		q.Conditions = append(q.Conditions, cond)
+
+		i += 3
+	}
+
+	return q, nil
+}
+
+// MustParse parses the given query string, and panics on error
+func MustParse(s string) *Query {
+	q, err := Parse(s)
+	if err != nil {
+		panic(fmt.Sprintf("unable to parse query %q, %v", s, err))
+	}
+
+	return q
+}
+
+// String returns the textual representation of the query
+func (q *Query) String() string {
+	conds := make([]string, 0, len(q.Conditions))
+	for _, c := range q.Conditions {
+		conds = append(conds, c.String())
+	}
+
+	return strings.Join(conds, " AND ")
+}
+
+// Matches returns a flag indicating if the given attributes
+// satisfy every condition of the query. A condition is satisfied
+// if at least one of the values stored under its key matches
+func (q *Query) Matches(attrs Attributes) bool {
+	for _, c := range q.Conditions {
+		matched := false
+
+		for _, value := range attrs[c.Key] {
+			if c.Matches(value) {
+				matched = true
+
+				break
+			}
+		}
+
+		if !matched {
+			return false
+		}
+	}
+
+	return true
+}
+
+type token struct {
+	text   string
+	quoted bool
+}
+
+func (t token) isOperator() bool {
+	if t.quoted {
+		return false
+	}
+
+	_, err := parseOperator(t)
+
+	return err == nil
+}
+
+func parseOperator(t token) (Operator, error) {
+	if !t.quoted {
+		switch t.text {
+		case "=":
+			return OpEqual, nil
+		case "<":
+			return OpLess, nil
+		case "<=":
+			return OpLessEqual, nil
+		case ">":
+			return OpGreater, nil
+		case ">=":
+			return OpGreaterEqual, nil
+		}
+	}
+
+	return 0, fmt.Errorf("%w %q", errInvalidOperator, t.text)
+}
+
+// tokenize splits the query string into keys, operators,
+// values and AND keywords
+func tokenize(s string) ([]token, error) {
+	var (
+		tokens []token
+		runes  = []rune(s)
+	)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '\'':
+			end := i + 1
+			for end < len(runes) && runes[end] != '\'' {
+				end++
+			}
+
+			if end == len(runes) {
+				return nil, errUnterminatedStr
+			}
+
+			tokens = append(tokens, token{text: string(runes[i+1 : end]), quoted: true})
+			i = end + 1
+		case r == '=' || r == '<' || r == '>':
+			end := i + 1
+			if r != '=' && end < len(runes) && runes[end] == '=' {
+				end++
+			}
+
+			tokens = append(tokens, token{text: string(runes[i:end])})
+			i = end
+		default:
+			end := i
+			for end < len(runes) && !unicode.IsSpace(runes[end]) && !strings.ContainsRune("='<>", runes[end]) {
+				end++
+			}
+
+			tokens = append(tokens, token{text: string(runes[i:end])})
+			i = end
+		}
+	}
+
+	return tokens, nil
+}
@@ -0,0 +1,136 @@
+package query
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuery_Parse(t *testing.T) {
+	t.Parallel()
+
+	testTable := []struct {
+		name     string
+		query    string
+		expected []Condition
+	}{
+		{
+			"single equality",
+			"event.type = 'Transfer'",
+			[]Condition{
+				{Key: EventTypeKey, Op: OpEqual, Value: "Transfer"},
+			},
+		},
+		{
+			"numeric range without spaces",
+			"tx.height>=10",
+			[]Condition{
+				{Key: TxHeightKey, Op: OpGreaterEqual, Value: "10"},
+			},
+		},
+		{
+			"multiple conditions",
+			"tx.height > 5 AND tx.height <= 10 and event.pkg_path = 'gno.land/r/demo/foo'",
+			[]Condition{
+				{Key: TxHeightKey, Op: OpGreater, Value: "5"},
+				{Key: TxHeightKey, Op: OpLessEqual, Value: "10"},
+				{Key: EventPkgPathKey, Op: OpEqual, Value: "gno.land/r/demo/foo"},
+			},
+		},
+		{
+			"quoted value with spaces",
+			"Transfer.memo = 'hello AND world'",
+			[]Condition{
+				{Key: "Transfer.memo", Op: OpEqual, Value: "hello AND world"},
+			},
+		},
+		{
+			"uppercased hash",
+			"tx.hash = 'abcdef'",
+			[]Condition{
+				{Key: TxHashKey, Op: OpEqual, Value: "ABCDEF"},
+			},
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			q, err := Parse(testCase.query)
+			require.NoError(t, err)
+
+			assert.Equal(t, testCase.expected, q.Conditions)
+		})
+	}
+}
+
+func TestQuery_Parse_Invalid(t *testing.T) {
+	t.Parallel()
+
+	testTable := []struct {
+		name  string
+		query string
+	}{
+		{"empty query", "   "},
+		{"missing value", "tx.height >="},
+		{"missing operator", "tx.height 10"},
+		{"missing AND", "tx.height = 10 tx.height = 11"},
+		{"dangling AND", "tx.height = 10 AND"},
+		{"unterminated string", "event.type = 'Transfer"},
+		{"quoted key", "'tx.height' = 10"},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			q, err := Parse(testCase.query)
+
+			assert.Nil(t, q)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestQuery_String(t *testing.T) {
+	t.Parallel()
+
+	q := MustParse("tx.height>=10 and event.type='Transfer'")
+
+	assert.Equal(t, "tx.height >= 10 AND event.type = 'Transfer'", q.String())
+}
+
+func TestQuery_Matches(t *testing.T) {
+	t.Parallel()
+
+	attrs := Attributes{
+		TxHeightKey:      {"10"},
+		EventTypeKey:     {"Transfer", "Mint"},
+		"Transfer.from":  {"g1alice"},
+		"Transfer.value": {"9"},
+	}
+
+	testTable := []struct {
+		name    string
+		query   string
+		matches bool
+	}{
+		{"equal height", "tx.height = 10", true},
+		{"numeric range", "tx.height > 9 AND tx.height < 11", true},
+		{"numeric not lexical", "Transfer.value < 10", true},
+		{"any event type", "event.type = 'Mint'", true},
+		{"all conditions", "event.type = 'Mint' AND Transfer.from = 'g1alice'", true},
+		{"one failing condition", "event.type = 'Mint' AND Transfer.from = 'g1bob'", false},
+		{"missing key", "Burn.from = 'g1alice'", false},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, testCase.matches, MustParse(testCase.query).Matches(attrs))
+		})
+	}
+}
@@ -1,6 +1,9 @@
 package eventstore
 
-import "github.com/gnolang/gno/tm2/pkg/bft/types"
+import (
+	"github.com/gnolang/gno/tm2/pkg/bft/state/eventstore/query"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+)
 
 const (
 	StatusOn  = "on"
@@ -22,3 +25,27 @@ type TxEventStore interface {
 	// to the event store
 	Append(result types.TxResult) error
 }
+
+// BlockEventStore is implemented by event stores
+// that also store block events
+type BlockEventStore interface {
+	// AppendBlock analyzes and appends a single block
+	// to the event store
+	AppendBlock(block types.EventNewBlockHeader) error
+}
+
+// Searcher is implemented by event stores that index
+// the transactions and blocks they store
+type Searcher interface {
+	// SearchTxs returns the hashes of the first transactions matching
+	// the query, ordered by block height and transaction index,
+	// at most limit of them
+	SearchTxs(q *query.Query, limit int) ([][]byte, error)
+
+	// GetTx returns the stored transaction result for the given hash
+	GetTx(hash []byte) (*types.TxResult, error)
+
+	// SearchBlocks returns the heights of the first blocks
+	// matching the query, in ascending order, at most limit of them
+	SearchBlocks(q *query.Query, limit int) ([]int64, error)
+}
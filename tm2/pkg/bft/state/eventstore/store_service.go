@@ -57,27 +57,41 @@ func (is *Service) OnStop() {
 }
 
 // monitorTxEvents acts as an intermediary feed service for the supplied
-// event store. It relays transaction events that come from the event stream
+// event store. It relays transaction events that come from the event stream,
+// as well as block events if the event store supports them
 func (is *Service) monitorTxEvents(ctx context.Context) {
-	// Create a subscription for transaction events
-	subCh := events.SubscribeToEvent(is.evsw, "tx-event-store", types.EventTx{})
+	blockStore, storesBlocks := is.txEventStore.(BlockEventStore)
+
+	// Create a subscription for transaction (and block) events
+	subCh := events.SubscribeFiltered(is.evsw, "tx-event-store", func(ev events.Event) bool {
+		switch ev.(type) {
+		case types.EventTx:
+			return true
+		case types.EventNewBlockHeader:
+			return storesBlocks
+		default:
+			return false
+		}
+	})
 
 	for {
 		select {
 		case <-ctx.Done():
 			return
 		case evRaw := <-subCh:
-			// Cast the event
-			ev, ok := evRaw.(types.EventTx)
-			if !ok {
-				is.Logger.Error("invalid transaction result type cast")
-
-				continue
-			}
-
-			// Alert the actual tx event store
-			if err := is.txEventStore.Append(ev.Result); err != nil {
-				is.Logger.Error("unable to store transaction", "err", err)
+			switch ev := evRaw.(type) {
+			case types.EventTx:
+				// Alert the actual tx event store
+				if err := is.txEventStore.Append(ev.Result); err != nil {
+					is.Logger.Error("unable to store transaction", "err", err)
+				}
+			case types.EventNewBlockHeader:
+				// Alert the block event store
+				if err := blockStore.AppendBlock(ev); err != nil {
+					is.Logger.Error("unable to store block", "err", err)
+				}
+			default:
+				is.Logger.Error("invalid event type cast")
 			}
 		}
 	}
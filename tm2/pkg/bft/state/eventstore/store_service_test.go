@@ -157,3 +157,71 @@ func TestEventStoreService_Monitor(t *testing.T) {
 		assert.Equal(t, event.Result, receivedResults[index])
 	}
 }
+
+func TestEventStoreService_MonitorBlocks(t *testing.T) {
+	t.Parallel()
+
+	var (
+		cbCh        = make(chan events.EventCallback, 1)
+		txCh        = make(chan types.TxResult, 1)
+		blockCh     = make(chan types.EventNewBlockHeader, 1)
+		blockHeader = types.EventNewBlockHeader{
+			Header: types.Header{
+				Height: 10,
+			},
+		}
+
+		mockEventStore = &mockBlockEventStore{
+			mockEventStore: mockEventStore{
+				appendFn: func(result types.TxResult) error {
+					txCh <- result
+
+					return nil
+				},
+			},
+			appendBlockFn: func(block types.EventNewBlockHeader) error {
+				blockCh <- block
+
+				return nil
+			},
+		}
+		mockEventSwitch = &mockEventSwitch{
+			addListenerFn: func(_ string, callback events.EventCallback) {
+				cbCh <- callback
+			},
+		}
+	)
+
+	// Create a new event store instance
+	i := NewEventStoreService(mockEventStore, mockEventSwitch)
+	if i == nil {
+		t.Fatal("unable to create event store service")
+	}
+
+	// Start the event store
+	if err := i.OnStart(); err != nil {
+		t.Fatalf("unable to start event store, %v", err)
+	}
+
+	t.Cleanup(i.OnStop)
+
+	cb := <-cbCh
+
+	// Fire off a block and a transaction event
+	cb(blockHeader)
+	cb(types.EventTx{Result: types.TxResult{Height: 10}})
+
+	select {
+	case block := <-blockCh:
+		assert.Equal(t, blockHeader, block)
+	case <-time.After(5 * time.Second):
+		t.Fatal("block event not received")
+	}
+
+	select {
+	case result := <-txCh:
+		assert.Equal(t, int64(10), result.Height)
+	case <-time.After(5 * time.Second):
+		t.Fatal("tx event not received")
+	}
+}
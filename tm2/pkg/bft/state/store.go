@@ -102,6 +102,39 @@ func SaveState(db dbm.DB, state State) {
 	saveState(db, state, stateKey)
 }
 
+// BootstrapState persists a State restored by state sync, which has no
+// previous history in the database. Along with the State, it persists the
+// ValidatorsInfo and ConsensusParamsInfo needed to verify the next blocks.
+// This flushes the writes (e.g. calls SetSync).
+func BootstrapState(db dbm.DB, state State) error {
+	height := state.LastBlockHeight
+	if height <= 0 {
+		return fmt.Errorf("invalid bootstrap state height %d", height)
+	}
+
+	// Only the genesis state can be replaced
+	if loaded := LoadState(db); loaded.LastBlockHeight != 0 {
+		return fmt.Errorf("state is already initialized at height %d", loaded.LastBlockHeight)
+	}
+
+	if state.LastValidators.IsNilOrEmpty() ||
+		state.Validators.IsNilOrEmpty() ||
+		state.NextValidators.IsNilOrEmpty() {
+		return errors.New("bootstrap state is missing validator sets")
+	}
+
+	// Persist the validator sets of the last, the current and the next block,
+	// as there is no history to load them from
+	saveValidatorsInfo(db, height, height, state.LastValidators)
+	saveValidatorsInfo(db, height+1, height+1, state.Validators)
+	saveValidatorsInfo(db, height+2, height+2, state.NextValidators)
+	saveConsensusParamsInfo(db, height+1, height+1, state.ConsensusParams)
+
+	db.SetSync(stateKey, state.Bytes())
+
+	return nil
+}
+
 func saveState(db dbm.DB, state State, key []byte) {
 	nextHeight := state.LastBlockHeight + 1
 	// If first block, save validators for block 1.
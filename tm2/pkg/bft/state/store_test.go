@@ -57,6 +57,55 @@ func TestStoreLoadValidators(t *testing.T) {
 	assert.NotZero(t, loadedVals.Size())
 }
 
+func TestBootstrapState(t *testing.T) {
+	t.Parallel()
+
+	var (
+		stateDB = memdb.NewMemDB()
+		height  = int64(100)
+	)
+
+	newValSet := func() *types.ValidatorSet {
+		val, _ := types.RandValidator(true, 10)
+
+		return types.NewValidatorSet([]*types.Validator{val})
+	}
+
+	state := sm.State{
+		ChainID:                          "test-chain",
+		LastBlockHeight:                  height,
+		LastValidators:                   newValSet(),
+		Validators:                       newValSet(),
+		NextValidators:                   newValSet(),
+		LastHeightValidatorsChanged:      height + 2,
+		ConsensusParams:                  types.DefaultConsensusParams(),
+		LastHeightConsensusParamsChanged: height + 1,
+	}
+
+	// The validator sets are required
+	invalid := state.Copy()
+	invalid.NextValidators = nil
+	require.Error(t, sm.BootstrapState(stateDB, invalid))
+
+	require.NoError(t, sm.BootstrapState(stateDB, state))
+
+	loaded := sm.LoadState(stateDB)
+	assert.Equal(t, height, loaded.LastBlockHeight)
+
+	for offset, expected := range []*types.ValidatorSet{state.LastValidators, state.Validators, state.NextValidators} {
+		vals, err := sm.LoadValidators(stateDB, height+int64(offset))
+		require.NoError(t, err)
+		assert.Equal(t, expected.Hash(), vals.Hash())
+	}
+
+	params, err := sm.LoadConsensusParams(stateDB, height+1)
+	require.NoError(t, err)
+	assert.Equal(t, state.ConsensusParams.Hash(), params.Hash())
+
+	// An initialized state can't be bootstrapped again
+	require.Error(t, sm.BootstrapState(stateDB, state))
+}
+
 func BenchmarkLoadValidators(b *testing.B) {
 	const valSetSize = 100
 
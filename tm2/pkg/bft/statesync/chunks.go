@@ -0,0 +1,283 @@
+package statesync
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gnolang/gno/tm2/pkg/p2p"
+)
+
+// maxChunksAhead is the maximum number of chunks fetched ahead of the next
+// chunk to apply, bounding the memory used by the fetched chunks
+const maxChunksAhead = 32
+
+var (
+	errDone            = errors.New("chunk queue has completed")
+	errNoChunkToFetch  = errors.New("no chunk to fetch")
+	errChunkNotArrived = errors.New("chunk did not arrive in time")
+)
+
+// chunk is a snapshot chunk, received from a peer
+type chunk struct {
+	Height int64
+	Format uint32
+	Index  uint32
+	Chunk  []byte
+	Sender p2p.ID
+}
+
+// chunkQueue keeps track of the chunks of the snapshot being restored:
+// which chunks are being fetched, which ones arrived, and which ones
+// were handed over to the app
+type chunkQueue struct {
+	mtx sync.Mutex
+
+	snapshot  *snapshot
+	chunks    map[uint32]*chunk   // arrived chunks
+	allocated map[uint32]struct{} // chunks being fetched
+	returned  map[uint32]struct{} // chunks handed over to the app
+	waiters   map[uint32][]chan struct{}
+	closed    bool
+	err       error // returned by Next once the queue is closed, if set
+}
+
+// newChunkQueue creates a new chunk queue for the given snapshot
+func newChunkQueue(s *snapshot) *chunkQueue {
+	return &chunkQueue{
+		snapshot:  s,
+		chunks:    make(map[uint32]*chunk, s.Chunks),
+		allocated: make(map[uint32]struct{}, s.Chunks),
+		returned:  make(map[uint32]struct{}, s.Chunks),
+		waiters:   make(map[uint32][]chan struct{}),
+	}
+}
+
+// Add adds a chunk to the queue. Returns a flag indicating
+// if the chunk was added, or ignored as it's unexpected or already present
+func (q *chunkQueue) Add(c *chunk) (bool, error) {
+	if c == nil || c.Chunk == nil {
+		return false, errors.New("cannot add nil chunk")
+	}
+
+	q.mtx.Lock()
+	defer q.mtx.Unlock()
+
+	if q.closed {
+		return false, nil
+	}
+
+	if c.Height != q.snapshot.Height || c.Format != q.snapshot.Format {
+		return false, nil
+	}
+
+	if c.Index >= q.snapshot.Chunks {
+		return false, fmt.Errorf("received unexpected chunk %d", c.Index)
+	}
+
+	if _, exists := q.chunks[c.Index]; exists {
+		return false, nil
+	}
+
+	q.chunks[c.Index] = c
+
+	// Notify the waiters
+	for _, waiter := range q.waiters[c.Index] {
+		close(waiter)
+	}
+
+	delete(q.waiters, c.Index)
+
+	return true, nil
+}
+
+// Allocate allocates the next chunk to fetch. Returns errNoChunkToFetch
+// if all the chunks are allocated, or too many of them are fetched ahead,
+// and errDone once the queue is closed
+func (q *chunkQueue) Allocate() (uint32, error) {
+	q.mtx.Lock()
+	defer q.mtx.Unlock()
+
+	if q.closed {
+		return 0, errDone
+	}
+
+	limit := min(q.nextIndex()+maxChunksAhead, q.snapshot.Chunks)
+
+	for index := uint32(0); index < limit; index++ {
+		if _, allocated := q.allocated[index]; !allocated {
+			q.allocated[index] = struct{}{}
+
+			return index, nil
+		}
+	}
+
+	return 0, errNoChunkToFetch
+}
+
+// Discard discards the given chunk, so it's fetched again
+func (q *chunkQueue) Discard(index uint32) {
+	q.mtx.Lock()
+	defer q.mtx.Unlock()
+
+	q.discard(index)
+}
+
+// DiscardSender discards all the chunks received from the given peer,
+// that were not handed over to the app yet
+func (q *chunkQueue) DiscardSender(peerID p2p.ID) {
+	q.mtx.Lock()
+	defer q.mtx.Unlock()
+
+	for index, c := range q.chunks {
+		if _, returned := q.returned[index]; returned {
+			continue
+		}
+
+		if c.Sender == peerID {
+			q.discard(index)
+		}
+	}
+}
+
+// discard discards the given chunk.
+// CONTRACT: the caller holds the lock
+func (q *chunkQueue) discard(index uint32) {
+	delete(q.chunks, index)
+	delete(q.allocated, index)
+	delete(q.returned, index)
+}
+
+// Retry schedules the given chunk to be handed over to the app again
+func (q *chunkQueue) Retry(index uint32) {
+	q.mtx.Lock()
+	defer q.mtx.Unlock()
+
+	delete(q.returned, index)
+}
+
+// RetryAll schedules all the chunks to be handed over to the app again
+func (q *chunkQueue) RetryAll() {
+	q.mtx.Lock()
+	defer q.mtx.Unlock()
+
+	q.returned = make(map[uint32]struct{}, q.snapshot.Chunks)
+}
+
+// WaitFor returns a channel that's closed once the given chunk arrives
+func (q *chunkQueue) WaitFor(index uint32) <-chan struct{} {
+	q.mtx.Lock()
+	defer q.mtx.Unlock()
+
+	ch := make(chan struct{})
+
+	if _, exists := q.chunks[index]; exists || q.closed {
+		close(ch)
+
+		return ch
+	}
+
+	q.waiters[index] = append(q.waiters[index], ch)
+
+	return ch
+}
+
+// Next returns the next chunk to hand over to the app, waiting up to the
+// given timeout for it to arrive. Returns errDone once all the chunks were
+// handed over, or the queue is closed, unless it failed
+func (q *chunkQueue) Next(timeout time.Duration) (*chunk, error) {
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+
+	for {
+		q.mtx.Lock()
+
+		if q.closed {
+			err := q.err
+			q.mtx.Unlock()
+
+			if err != nil {
+				return nil, err
+			}
+
+			return nil, errDone
+		}
+
+		index := q.nextIndex()
+		if index >= q.snapshot.Chunks {
+			q.mtx.Unlock()
+
+			return nil, errDone
+		}
+
+		if c, exists := q.chunks[index]; exists {
+			q.returned[index] = struct{}{}
+			q.mtx.Unlock()
+
+			return c, nil
+		}
+
+		q.mtx.Unlock()
+
+		// Wait for the chunk to arrive. It may be discarded in the meantime,
+		// so check again once it does
+		select {
+		case <-q.WaitFor(index):
+		case <-deadline.C:
+			return nil, fmt.Errorf("%w, chunk %d", errChunkNotArrived, index)
+		}
+	}
+}
+
+// Close closes the queue, releasing any waiters
+func (q *chunkQueue) Close() {
+	q.mtx.Lock()
+	defer q.mtx.Unlock()
+
+	q.close()
+}
+
+// Fail closes the queue, releasing any waiters,
+// and making Next return the given error
+func (q *chunkQueue) Fail(err error) {
+	q.mtx.Lock()
+	defer q.mtx.Unlock()
+
+	if !q.closed {
+		q.err = err
+	}
+
+	q.close()
+}
+
+// close closes the queue, releasing any waiters.
+// CONTRACT: the caller holds the lock
+func (q *chunkQueue) close() {
+	if q.closed {
+		return
+	}
+
+	q.closed = true
+
+	for _, waiters := range q.waiters {
+		for _, waiter := range waiters {
+			close(waiter)
+		}
+	}
+
+	q.waiters = nil
+	q.chunks = nil
+}
+
+// nextIndex returns the index of the next chunk to hand over to the app.
+// CONTRACT: the caller holds the lock
+func (q *chunkQueue) nextIndex() uint32 {
+	for index := uint32(0); index < q.snapshot.Chunks; index++ {
+		if _, returned := q.returned[index]; !returned {
+			return index
+		}
+	}
+
+	return q.snapshot.Chunks
+}
@@ -0,0 +1,158 @@
+package statesync
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChunkQueue_AllocateNext(t *testing.T) {
+	t.Parallel()
+
+	q := newChunkQueue(&snapshot{Height: 3, Format: 1, Chunks: 3})
+
+	// Allocate all the chunks
+	for i := uint32(0); i < 3; i++ {
+		index, err := q.Allocate()
+		require.NoError(t, err)
+		assert.Equal(t, i, index)
+	}
+
+	_, err := q.Allocate()
+	assert.ErrorIs(t, err, errNoChunkToFetch)
+
+	// Chunks for other snapshots are ignored
+	added, err := q.Add(&chunk{Height: 2, Format: 1, Index: 0, Chunk: []byte{0}})
+	require.NoError(t, err)
+	assert.False(t, added)
+
+	// Out of range chunks are invalid
+	_, err = q.Add(&chunk{Height: 3, Format: 1, Index: 3, Chunk: []byte{3}})
+	assert.Error(t, err)
+
+	// The chunks are handed over in order
+	added, err = q.Add(&chunk{Height: 3, Format: 1, Index: 1, Chunk: []byte{1}})
+	require.NoError(t, err)
+	assert.True(t, added)
+
+	_, err = q.Next(10 * time.Millisecond)
+	assert.ErrorIs(t, err, errChunkNotArrived)
+
+	go func() {
+		_, _ = q.Add(&chunk{Height: 3, Format: 1, Index: 0, Chunk: []byte{0}})
+	}()
+
+	c, err := q.Next(time.Second)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(0), c.Index)
+
+	c, err = q.Next(time.Second)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(1), c.Index)
+
+	// Retried chunks are handed over again
+	q.Retry(1)
+
+	c, err = q.Next(time.Second)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(1), c.Index)
+
+	_, err = q.Add(&chunk{Height: 3, Format: 1, Index: 2, Chunk: []byte{2}})
+	require.NoError(t, err)
+
+	c, err = q.Next(time.Second)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(2), c.Index)
+
+	_, err = q.Next(time.Second)
+	assert.ErrorIs(t, err, errDone)
+}
+
+func TestChunkQueue_Discard(t *testing.T) {
+	t.Parallel()
+
+	q := newChunkQueue(&snapshot{Height: 3, Format: 1, Chunks: 3})
+
+	for i := uint32(0); i < 3; i++ {
+		_, err := q.Allocate()
+		require.NoError(t, err)
+
+		_, err = q.Add(&chunk{Height: 3, Format: 1, Index: i, Chunk: []byte{byte(i)}, Sender: "a"})
+		require.NoError(t, err)
+	}
+
+	c, err := q.Next(time.Second)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(0), c.Index)
+
+	// Only the chunks not handed over are discarded from the sender
+	q.DiscardSender("a")
+
+	index, err := q.Allocate()
+	require.NoError(t, err)
+	assert.Equal(t, uint32(1), index)
+
+	index, err = q.Allocate()
+	require.NoError(t, err)
+	assert.Equal(t, uint32(2), index)
+
+	// A discarded chunk that was handed over is fetched and handed over again
+	q.Discard(0)
+
+	index, err = q.Allocate()
+	require.NoError(t, err)
+	assert.Equal(t, uint32(0), index)
+
+	_, err = q.Next(10 * time.Millisecond)
+	assert.ErrorIs(t, err, errChunkNotArrived)
+}
+
+func TestChunkQueue_Close(t *testing.T) {
+	t.Parallel()
+
+	q := newChunkQueue(&snapshot{Height: 3, Format: 1, Chunks: 3})
+
+	waiter := q.WaitFor(0)
+
+	q.Close()
+
+	select {
+	case <-waiter:
+	case <-time.After(time.Second):
+		t.Fatal("waiter was not released")
+	}
+
+	_, err := q.Allocate()
+	assert.ErrorIs(t, err, errDone)
+
+	_, err = q.Next(time.Second)
+	assert.ErrorIs(t, err, errDone)
+
+	added, err := q.Add(&chunk{Height: 3, Format: 1, Index: 0, Chunk: []byte{0}})
+	require.NoError(t, err)
+	assert.False(t, added)
+}
+
+func TestChunkQueue_Fail(t *testing.T) {
+	t.Parallel()
+
+	q := newChunkQueue(&snapshot{Height: 3, Format: 1, Chunks: 3})
+
+	waiter := q.WaitFor(0)
+
+	q.Fail(errRejectSnapshot)
+
+	select {
+	case <-waiter:
+	case <-time.After(time.Second):
+		t.Fatal("waiter was not released")
+	}
+
+	_, err := q.Allocate()
+	assert.ErrorIs(t, err, errDone)
+
+	_, err = q.Next(time.Second)
+	assert.ErrorIs(t, err, errRejectSnapshot)
+}
@@ -0,0 +1,104 @@
+package config
+
+import (
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+)
+
+// -----------------------------------------------------------------------------
+// StateSyncConfig
+
+// StateSyncConfig defines the configuration for state sync, which bootstraps
+// a new node from an application snapshot served by its peers, instead of
+// replaying the whole chain
+type StateSyncConfig struct {
+	Enable        bool          `json:"enable" toml:"enable" comment:"Bootstrap the node from a recent application snapshot, fetched from peers,\n instead of replaying all the blocks. Only used when the node has no state"`
+	RPCServers    string        `json:"rpc_servers" toml:"rpc_servers" comment:"Comma separated list of RPC servers used to light-verify the snapshot height.\n At least two are required, and every server is cross-checked"`
+	TrustHeight   int64         `json:"trust_height" toml:"trust_height" comment:"Trusted height and block hash (hex), obtained from a trusted source.\n The snapshot validator set must be mostly unchanged since the trusted height"`
+	TrustHash     string        `json:"trust_hash" toml:"trust_hash"`
+	TrustPeriod   time.Duration `json:"trust_period" toml:"trust_period" comment:"Period during which the trusted header can be used to verify newer headers.\n It should be shorter than the unbonding period of the validators"`
+	DiscoveryTime time.Duration `json:"discovery_time" toml:"discovery_time" comment:"Time spent discovering snapshots from peers, before picking one"`
+
+	ChunkFetchers       int32         `json:"chunk_fetchers" toml:"chunk_fetchers" comment:"Number of concurrent snapshot chunk fetchers, and the chunk request timeout"`
+	ChunkRequestTimeout time.Duration `json:"chunk_request_timeout" toml:"chunk_request_timeout"`
+
+	SnapshotInterval   uint64 `json:"snapshot_interval" toml:"snapshot_interval" comment:"Block interval at which the application creates snapshots to serve peers (0 to disable),\n and the number of recent snapshots to keep"`
+	SnapshotKeepRecent uint32 `json:"snapshot_keep_recent" toml:"snapshot_keep_recent"`
+}
+
+// DefaultStateSyncConfig returns a default configuration for state sync
+func DefaultStateSyncConfig() *StateSyncConfig {
+	return &StateSyncConfig{
+		Enable:              false,
+		RPCServers:          "",
+		TrustHeight:         0,
+		TrustHash:           "",
+		TrustPeriod:         168 * time.Hour,
+		DiscoveryTime:       15 * time.Second,
+		ChunkFetchers:       4,
+		ChunkRequestTimeout: 10 * time.Second,
+		SnapshotInterval:    0,
+		SnapshotKeepRecent:  2,
+	}
+}
+
+// TestStateSyncConfig returns a configuration for testing state sync
+func TestStateSyncConfig() *StateSyncConfig {
+	cfg := DefaultStateSyncConfig()
+	cfg.DiscoveryTime = 1 * time.Second
+	cfg.ChunkRequestTimeout = 1 * time.Second
+	return cfg
+}
+
+// RPCServerList returns the configured RPC servers
+func (cfg *StateSyncConfig) RPCServerList() []string {
+	servers := make([]string, 0)
+
+	for _, server := range strings.Split(cfg.RPCServers, ",") {
+		if server = strings.TrimSpace(server); server != "" {
+			servers = append(servers, server)
+		}
+	}
+
+	return servers
+}
+
+// ValidateBasic performs basic validation (checking param bounds, etc.) and
+// returns an error if any check fails.
+func (cfg *StateSyncConfig) ValidateBasic() error {
+	if cfg.DiscoveryTime < 0 {
+		return errors.New("discovery_time can't be negative")
+	}
+	if cfg.ChunkFetchers < 0 {
+		return errors.New("chunk_fetchers can't be negative")
+	}
+	if cfg.ChunkRequestTimeout < 0 {
+		return errors.New("chunk_request_timeout can't be negative")
+	}
+
+	if !cfg.Enable {
+		return nil
+	}
+
+	if len(cfg.RPCServerList()) < 2 {
+		return errors.New("at least two rpc_servers are required")
+	}
+	if cfg.TrustHeight <= 0 {
+		return errors.New("trust_height is required")
+	}
+	if hash, err := hex.DecodeString(cfg.TrustHash); err != nil || len(hash) == 0 {
+		return errors.New("trust_hash is required, as a hex string")
+	}
+	if cfg.TrustPeriod <= 0 {
+		return errors.New("trust_period is required")
+	}
+	if cfg.ChunkFetchers == 0 {
+		return errors.New("chunk_fetchers is required")
+	}
+	if cfg.ChunkRequestTimeout == 0 {
+		return errors.New("chunk_request_timeout is required")
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package statesync
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gnolang/gno/tm2/pkg/amino"
+)
+
+const (
+	// snapshotMsgSize is the maximum size of a snapshot message
+	snapshotMsgSize = 4 << 20 // 4MB
+
+	// chunkMsgSize is the maximum size of a chunk message
+	chunkMsgSize = 16 << 20 // 16MB
+)
+
+// StateSyncMessage is a generic message for this reactor.
+type StateSyncMessage interface {
+	ValidateBasic() error
+}
+
+func decodeMsg(bz []byte, maxSize int) (msg StateSyncMessage, err error) {
+	if len(bz) > maxSize {
+		return msg, fmt.Errorf("msg exceeds max size (%d > %d)", len(bz), maxSize)
+	}
+	err = amino.Unmarshal(bz, &msg)
+	return
+}
+
+// -------------------------------------
+
+// snapshotsRequestMessage requests the recent snapshots of a peer
+type snapshotsRequestMessage struct{}
+
+// ValidateBasic performs basic validation.
+func (m *snapshotsRequestMessage) ValidateBasic() error {
+	return nil
+}
+
+func (m *snapshotsRequestMessage) String() string {
+	return "[snapshotsRequestMessage]"
+}
+
+// -------------------------------------
+
+// snapshotsResponseMessage advertises a single snapshot of a peer
+type snapshotsResponseMessage struct {
+	Height   int64
+	Format   uint32
+	Chunks   uint32
+	Hash     []byte
+	Metadata []byte
+}
+
+// ValidateBasic performs basic validation.
+func (m *snapshotsResponseMessage) ValidateBasic() error {
+	if m.Height <= 0 {
+		return errors.New("invalid height")
+	}
+	if m.Chunks == 0 {
+		return errors.New("no chunks")
+	}
+	if len(m.Hash) == 0 {
+		return errors.New("no snapshot hash")
+	}
+	return nil
+}
+
+func (m *snapshotsResponseMessage) String() string {
+	return fmt.Sprintf("[snapshotsResponseMessage %v/%v %v chunks]", m.Height, m.Format, m.Chunks)
+}
+
+// -------------------------------------
+
+// chunkRequestMessage requests a snapshot chunk from a peer
+type chunkRequestMessage struct {
+	Height int64
+	Format uint32
+	Index  uint32
+}
+
+// ValidateBasic performs basic validation.
+func (m *chunkRequestMessage) ValidateBasic() error {
+	if m.Height <= 0 {
+		return errors.New("invalid height")
+	}
+	return nil
+}
+
+func (m *chunkRequestMessage) String() string {
+	return fmt.Sprintf("[chunkRequestMessage %v/%v #%v]", m.Height, m.Format, m.Index)
+}
+
+// -------------------------------------
+
+// chunkResponseMessage contains a snapshot chunk, or indicates it's missing
+type chunkResponseMessage struct {
+	Height  int64
+	Format  uint32
+	Index   uint32
+	Chunk   []byte
+	Missing bool
+}
+
+// ValidateBasic performs basic validation.
+func (m *chunkResponseMessage) ValidateBasic() error {
+	if m.Height <= 0 {
+		return errors.New("invalid height")
+	}
+	if m.Missing && len(m.Chunk) > 0 {
+		return errors.New("missing chunk cannot have contents")
+	}
+	return nil
+}
+
+func (m *chunkResponseMessage) String() string {
+	return fmt.Sprintf("[chunkResponseMessage %v/%v #%v missing=%v]", m.Height, m.Format, m.Index, m.Missing)
+}
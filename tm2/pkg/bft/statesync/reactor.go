@@ -0,0 +1,319 @@
+package statesync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	"github.com/gnolang/gno/tm2/pkg/bft/appconn"
+	sm "github.com/gnolang/gno/tm2/pkg/bft/state"
+	"github.com/gnolang/gno/tm2/pkg/bft/statesync/config"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/p2p"
+)
+
+const (
+	// SnapshotChannel exchanges snapshot metadata
+	SnapshotChannel = byte(0x60)
+	// ChunkChannel exchanges snapshot chunks
+	ChunkChannel = byte(0x61)
+
+	// recentSnapshots is the number of recent snapshots advertised to peers
+	recentSnapshots = 10
+)
+
+// Reactor handles state sync, both restoring the local app from snapshots
+// advertised by peers, and serving the local app snapshots to peers
+type Reactor struct {
+	p2p.BaseReactor
+
+	cfg       *config.StateSyncConfig
+	conn      appconn.Snapshot
+	connQuery appconn.Query
+
+	// the syncer is only set while syncing
+	mtx    sync.RWMutex
+	syncer *syncer
+}
+
+// NewReactor creates a new state sync reactor
+func NewReactor(cfg *config.StateSyncConfig, conn appconn.Snapshot, connQuery appconn.Query) *Reactor {
+	r := &Reactor{
+		cfg:       cfg,
+		conn:      conn,
+		connQuery: connQuery,
+	}
+	r.BaseReactor = *p2p.NewBaseReactor("StateSyncReactor", r)
+
+	return r
+}
+
+// GetChannels implements Reactor
+func (r *Reactor) GetChannels() []*p2p.ChannelDescriptor {
+	return []*p2p.ChannelDescriptor{
+		{
+			ID:                  SnapshotChannel,
+			Priority:            5,
+			SendQueueCapacity:   10,
+			RecvMessageCapacity: snapshotMsgSize,
+		},
+		{
+			ID:                  ChunkChannel,
+			Priority:            3,
+			SendQueueCapacity:   10,
+			RecvMessageCapacity: chunkMsgSize,
+		},
+	}
+}
+
+// AddPeer implements Reactor by requesting the peer snapshots, if syncing
+func (r *Reactor) AddPeer(peer p2p.Peer) {
+	r.mtx.RLock()
+	defer r.mtx.RUnlock()
+
+	if r.syncer != nil {
+		r.syncer.AddPeer(peer)
+	}
+}
+
+// RemovePeer implements Reactor by removing the peer snapshots, if syncing
+func (r *Reactor) RemovePeer(peer p2p.Peer, _ interface{}) {
+	r.mtx.RLock()
+	defer r.mtx.RUnlock()
+
+	if r.syncer != nil {
+		r.syncer.RemovePeer(peer)
+	}
+}
+
+// Receive implements Reactor
+func (r *Reactor) Receive(chID byte, src p2p.Peer, msgBytes []byte) {
+	if !r.IsRunning() {
+		return
+	}
+
+	maxSize := snapshotMsgSize
+	if chID == ChunkChannel {
+		maxSize = chunkMsgSize
+	}
+
+	msg, err := decodeMsg(msgBytes, maxSize)
+	if err != nil {
+		r.Logger.Error("Error decoding message", "src", src, "chId", chID, "msg", msg, "err", err, "bytes", msgBytes)
+		r.Switch.StopPeerForError(src, err)
+
+		return
+	}
+
+	if err = msg.ValidateBasic(); err != nil {
+		r.Logger.Error("Invalid message", "peer", src, "msg", msg, "err", err)
+		r.Switch.StopPeerForError(src, err)
+
+		return
+	}
+
+	switch chID {
+	case SnapshotChannel:
+		r.receiveSnapshotMsg(src, msg)
+	case ChunkChannel:
+		r.receiveChunkMsg(src, msg)
+	default:
+		r.Logger.Error(fmt.Sprintf("Unknown chId %X", chID))
+	}
+}
+
+// receiveSnapshotMsg handles a message received on the snapshot channel
+func (r *Reactor) receiveSnapshotMsg(src p2p.Peer, msg StateSyncMessage) {
+	switch msg := msg.(type) {
+	case *snapshotsRequestMessage:
+		snapshots, err := r.recentSnapshots(recentSnapshots)
+		if err != nil {
+			r.Logger.Error("Failed to fetch snapshots", "err", err)
+
+			return
+		}
+
+		for _, s := range snapshots {
+			r.Logger.Debug(
+				"Advertising snapshot",
+				"height", s.Height,
+				"format", s.Format,
+				"peer", src.ID(),
+			)
+
+			src.Send(SnapshotChannel, amino.MustMarshalAny(&snapshotsResponseMessage{
+				Height:   s.Height,
+				Format:   s.Format,
+				Chunks:   s.Chunks,
+				Hash:     s.Hash,
+				Metadata: s.Metadata,
+			}))
+		}
+	case *snapshotsResponseMessage:
+		r.mtx.RLock()
+		defer r.mtx.RUnlock()
+
+		if r.syncer == nil {
+			r.Logger.Debug("Received unexpected snapshot, no state sync in progress")
+
+			return
+		}
+
+		r.Logger.Debug("Received snapshot", "height", msg.Height, "format", msg.Format, "peer", src.ID())
+
+		r.syncer.AddSnapshot(src, &snapshot{
+			Height:   msg.Height,
+			Format:   msg.Format,
+			Chunks:   msg.Chunks,
+			Hash:     msg.Hash,
+			Metadata: msg.Metadata,
+		})
+	default:
+		r.Logger.Error(fmt.Sprintf("Unknown message type %T on the snapshot channel", msg))
+	}
+}
+
+// receiveChunkMsg handles a message received on the chunk channel
+func (r *Reactor) receiveChunkMsg(src p2p.Peer, msg StateSyncMessage) {
+	switch msg := msg.(type) {
+	case *chunkRequestMessage:
+		r.Logger.Debug(
+			"Received chunk request",
+			"height", msg.Height,
+			"format", msg.Format,
+			"chunk", msg.Index,
+			"peer", src.ID(),
+		)
+
+		res, err := r.conn.LoadSnapshotChunkSync(abci.RequestLoadSnapshotChunk{
+			Height: msg.Height,
+			Format: msg.Format,
+			Chunk:  msg.Index,
+		})
+		if err != nil {
+			r.Logger.Error(
+				"Failed to load chunk",
+				"height", msg.Height,
+				"format", msg.Format,
+				"chunk", msg.Index,
+				"err", err,
+			)
+
+			return
+		}
+
+		src.Send(ChunkChannel, amino.MustMarshalAny(&chunkResponseMessage{
+			Height:  msg.Height,
+			Format:  msg.Format,
+			Index:   msg.Index,
+			Chunk:   res.Chunk,
+			Missing: len(res.Chunk) == 0,
+		}))
+	case *chunkResponseMessage:
+		r.mtx.RLock()
+		defer r.mtx.RUnlock()
+
+		if r.syncer == nil {
+			r.Logger.Debug("Received unexpected chunk, no state sync in progress", "peer", src.ID())
+
+			return
+		}
+
+		r.Logger.Debug(
+			"Received chunk",
+			"height", msg.Height,
+			"format", msg.Format,
+			"chunk", msg.Index,
+			"peer", src.ID(),
+		)
+
+		c := &chunk{
+			Height: msg.Height,
+			Format: msg.Format,
+			Index:  msg.Index,
+			Chunk:  msg.Chunk,
+			Sender: src.ID(),
+		}
+
+		if msg.Missing {
+			// The peer doesn't have the chunk, the fetcher
+			// will request it again from another peer
+			r.syncer.MissingChunk(c)
+
+			return
+		}
+
+		if _, err := r.syncer.AddChunk(c); err != nil {
+			r.Logger.Error("Failed to add chunk", "chunk", msg.Index, "err", err)
+		}
+	default:
+		r.Logger.Error(fmt.Sprintf("Unknown message type %T on the chunk channel", msg))
+	}
+}
+
+// recentSnapshots fetches the n most recent snapshots from the app
+func (r *Reactor) recentSnapshots(n int) ([]*abci.Snapshot, error) {
+	res, err := r.conn.ListSnapshotsSync(abci.RequestListSnapshots{})
+	if err != nil {
+		return nil, err
+	}
+
+	snapshots := res.Snapshots
+
+	sort.SliceStable(snapshots, func(i, j int) bool {
+		a, b := snapshots[i], snapshots[j]
+
+		switch {
+		case a.Height != b.Height:
+			return a.Height > b.Height
+		default:
+			return a.Format > b.Format
+		}
+	})
+
+	if len(snapshots) > n {
+		snapshots = snapshots[:n]
+	}
+
+	return snapshots, nil
+}
+
+// Sync restores the app state from a snapshot advertised by peers,
+// light-verified by the given state provider.
+// Returns the state and commit at the snapshot height, to bootstrap
+// the node stores
+func (r *Reactor) Sync(
+	ctx context.Context,
+	stateProvider StateProvider,
+	discoveryTime time.Duration,
+) (sm.State, *types.Commit, error) {
+	r.mtx.Lock()
+	if r.syncer != nil {
+		r.mtx.Unlock()
+
+		return sm.State{}, nil, errors.New("a state sync is already running")
+	}
+
+	r.syncer = newSyncer(r.cfg, r.Logger, r.conn, r.connQuery, stateProvider)
+	r.mtx.Unlock()
+
+	defer func() {
+		r.mtx.Lock()
+		r.syncer = nil
+		r.mtx.Unlock()
+	}()
+
+	requestSnapshots := func() {
+		r.Switch.Broadcast(SnapshotChannel, amino.MustMarshalAny(&snapshotsRequestMessage{}))
+	}
+
+	// Request snapshots from all the connected peers
+	requestSnapshots()
+
+	return r.syncer.SyncAny(ctx, discoveryTime, requestSnapshots)
+}
@@ -0,0 +1,274 @@
+package statesync
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"math/rand"
+	"sort"
+	"sync"
+
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/p2p"
+)
+
+// snapshotKey uniquely identifies a snapshot
+type snapshotKey [sha256.Size]byte
+
+// snapshot is a snapshot advertised by peers
+type snapshot struct {
+	Height   int64
+	Format   uint32
+	Chunks   uint32
+	Hash     []byte
+	Metadata []byte
+
+	// populated by the syncer, from the verified state and header
+	trustedAppHash []byte
+	trustedHeader  *types.Header
+}
+
+// Key returns the key of the snapshot, covering all of its fields
+func (s *snapshot) Key() snapshotKey {
+	h := sha256.New()
+
+	var buf [16]byte
+
+	binary.BigEndian.PutUint64(buf[:8], uint64(s.Height))
+	binary.BigEndian.PutUint32(buf[8:12], s.Format)
+	binary.BigEndian.PutUint32(buf[12:], s.Chunks)
+
+	h.Write(buf[:])
+	h.Write(s.Hash)
+	h.Write(s.Metadata)
+
+	var key snapshotKey
+	copy(key[:], h.Sum(nil))
+
+	return key
+}
+
+// snapshotPool discovers the snapshots advertised by peers,
+// and keeps track of the rejected snapshots, formats and peers
+type snapshotPool struct {
+	mtx sync.Mutex
+
+	snapshots     map[snapshotKey]*snapshot
+	snapshotPeers map[snapshotKey]map[p2p.ID]p2p.Peer
+	peerIndex     map[p2p.ID]map[snapshotKey]struct{}
+
+	// blacklists for rejected items
+	formatBlacklist   map[uint32]struct{}
+	peerBlacklist     map[p2p.ID]struct{}
+	snapshotBlacklist map[snapshotKey]struct{}
+}
+
+// newSnapshotPool creates a new, empty, snapshot pool
+func newSnapshotPool() *snapshotPool {
+	return &snapshotPool{
+		snapshots:         make(map[snapshotKey]*snapshot),
+		snapshotPeers:     make(map[snapshotKey]map[p2p.ID]p2p.Peer),
+		peerIndex:         make(map[p2p.ID]map[snapshotKey]struct{}),
+		formatBlacklist:   make(map[uint32]struct{}),
+		peerBlacklist:     make(map[p2p.ID]struct{}),
+		snapshotBlacklist: make(map[snapshotKey]struct{}),
+	}
+}
+
+// Add adds a snapshot advertised by the given peer. Returns a flag indicating
+// if the snapshot is new, and wasn't rejected before
+func (p *snapshotPool) Add(peer p2p.Peer, s *snapshot) bool {
+	key := s.Key()
+
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	if _, rejected := p.formatBlacklist[s.Format]; rejected {
+		return false
+	}
+
+	if _, rejected := p.peerBlacklist[peer.ID()]; rejected {
+		return false
+	}
+
+	if _, rejected := p.snapshotBlacklist[key]; rejected {
+		return false
+	}
+
+	if p.snapshotPeers[key] == nil {
+		p.snapshotPeers[key] = make(map[p2p.ID]p2p.Peer)
+	}
+
+	p.snapshotPeers[key][peer.ID()] = peer
+
+	if p.peerIndex[peer.ID()] == nil {
+		p.peerIndex[peer.ID()] = make(map[snapshotKey]struct{})
+	}
+
+	p.peerIndex[peer.ID()][key] = struct{}{}
+
+	if _, exists := p.snapshots[key]; exists {
+		return false
+	}
+
+	p.snapshots[key] = s
+
+	return true
+}
+
+// Best returns the best snapshot: the most recent one, using the highest
+// format, and advertised by the most peers. Returns nil if there are none
+func (p *snapshotPool) Best() *snapshot {
+	ranked := p.Ranked()
+	if len(ranked) == 0 {
+		return nil
+	}
+
+	return ranked[0]
+}
+
+// Ranked returns the snapshots, the best ones first
+func (p *snapshotPool) Ranked() []*snapshot {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	candidates := make([]*snapshot, 0, len(p.snapshots))
+	for _, s := range p.snapshots {
+		candidates = append(candidates, s)
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+
+		switch {
+		case a.Height != b.Height:
+			return a.Height > b.Height
+		case a.Format != b.Format:
+			return a.Format > b.Format
+		default:
+			return len(p.snapshotPeers[a.Key()]) > len(p.snapshotPeers[b.Key()])
+		}
+	})
+
+	return candidates
+}
+
+// GetPeer returns a random peer advertising the snapshot, or nil if there
+// are none
+func (p *snapshotPool) GetPeer(s *snapshot) p2p.Peer {
+	peers := p.GetPeers(s)
+	if len(peers) == 0 {
+		return nil
+	}
+
+	return peers[rand.Intn(len(peers))] //nolint:gosec
+}
+
+// GetPeers returns the peers advertising the snapshot
+func (p *snapshotPool) GetPeers(s *snapshot) []p2p.Peer {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	peers := make([]p2p.Peer, 0, len(p.snapshotPeers[s.Key()]))
+	for _, peer := range p.snapshotPeers[s.Key()] {
+		peers = append(peers, peer)
+	}
+
+	// Sort the peers, for a deterministic order
+	sort.Slice(peers, func(i, j int) bool {
+		return peers[i].ID() < peers[j].ID()
+	})
+
+	return peers
+}
+
+// Reject rejects the snapshot, so it's not used anymore
+func (p *snapshotPool) Reject(s *snapshot) {
+	key := s.Key()
+
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	p.snapshotBlacklist[key] = struct{}{}
+	p.removeSnapshot(key)
+}
+
+// RejectFormat rejects all the snapshots using the given format
+func (p *snapshotPool) RejectFormat(format uint32) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	p.formatBlacklist[format] = struct{}{}
+
+	for key, s := range p.snapshots {
+		if s.Format == format {
+			p.removeSnapshot(key)
+		}
+	}
+}
+
+// RejectPeer rejects the given peer, and all the snapshots it advertised
+// that no other peer advertises
+func (p *snapshotPool) RejectPeer(peerID p2p.ID) {
+	if peerID == "" {
+		return
+	}
+
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	p.peerBlacklist[peerID] = struct{}{}
+	p.removePeer(peerID)
+}
+
+// RemovePeer removes the given peer, and all the snapshots it advertised
+// that no other peer advertises
+func (p *snapshotPool) RemovePeer(peerID p2p.ID) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	p.removePeer(peerID)
+}
+
+// RemoveSnapshotPeer removes the given peer from the peers advertising the
+// snapshot, as it's unable to serve it. Returns the number of remaining peers
+func (p *snapshotPool) RemoveSnapshotPeer(s *snapshot, peerID p2p.ID) int {
+	key := s.Key()
+
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	delete(p.snapshotPeers[key], peerID)
+	delete(p.peerIndex[peerID], key)
+
+	return len(p.snapshotPeers[key])
+}
+
+// removePeer removes the given peer.
+// CONTRACT: the caller holds the lock
+func (p *snapshotPool) removePeer(peerID p2p.ID) {
+	for key := range p.peerIndex[peerID] {
+		delete(p.snapshotPeers[key], peerID)
+
+		if len(p.snapshotPeers[key]) == 0 {
+			p.removeSnapshot(key)
+		}
+	}
+
+	delete(p.peerIndex, peerID)
+}
+
+// removeSnapshot removes the given snapshot.
+// CONTRACT: the caller holds the lock
+func (p *snapshotPool) removeSnapshot(key snapshotKey) {
+	if _, exists := p.snapshots[key]; !exists {
+		return
+	}
+
+	delete(p.snapshots, key)
+
+	for peerID := range p.snapshotPeers[key] {
+		delete(p.peerIndex[peerID], key)
+	}
+
+	delete(p.snapshotPeers, key)
+}
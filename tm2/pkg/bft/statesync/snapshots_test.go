@@ -0,0 +1,82 @@
+package statesync
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/gno/tm2/pkg/p2p/mock"
+)
+
+func TestSnapshotPool_Ranked(t *testing.T) {
+	t.Parallel()
+
+	var (
+		p = newSnapshotPool()
+
+		peerA = mock.NewPeer(nil)
+		peerB = mock.NewPeer(nil)
+
+		low     = &snapshot{Height: 1, Format: 1, Chunks: 1, Hash: []byte{1}}
+		high    = &snapshot{Height: 2, Format: 1, Chunks: 1, Hash: []byte{2}}
+		popular = &snapshot{Height: 2, Format: 1, Chunks: 1, Hash: []byte{3}}
+		format  = &snapshot{Height: 2, Format: 2, Chunks: 1, Hash: []byte{4}}
+	)
+
+	assert.Nil(t, p.Best())
+
+	assert.True(t, p.Add(peerA, low))
+	assert.True(t, p.Add(peerA, high))
+	assert.True(t, p.Add(peerA, popular))
+	assert.False(t, p.Add(peerB, popular)) // already known
+	assert.True(t, p.Add(peerB, format))
+
+	assert.Equal(t, []*snapshot{format, popular, high, low}, p.Ranked())
+	assert.Len(t, p.GetPeers(popular), 2)
+
+	// Rejected formats are removed, and not added anymore
+	p.RejectFormat(2)
+	assert.False(t, p.Add(peerA, format))
+	assert.Equal(t, popular, p.Best())
+
+	// Rejected snapshots are removed, and not added anymore
+	p.Reject(popular)
+	assert.False(t, p.Add(peerA, popular))
+	assert.Equal(t, high, p.Best())
+}
+
+func TestSnapshotPool_Peers(t *testing.T) {
+	t.Parallel()
+
+	var (
+		p = newSnapshotPool()
+
+		peerA = mock.NewPeer(nil)
+		peerB = mock.NewPeer(nil)
+
+		shared = &snapshot{Height: 1, Format: 1, Chunks: 1, Hash: []byte{1}}
+		single = &snapshot{Height: 2, Format: 1, Chunks: 1, Hash: []byte{2}}
+	)
+
+	p.Add(peerA, shared)
+	p.Add(peerB, shared)
+	p.Add(peerA, single)
+
+	// Snapshots only advertised by the removed peer are removed
+	p.RemovePeer(peerA.ID())
+
+	assert.Equal(t, []*snapshot{shared}, p.Ranked())
+	require.Len(t, p.GetPeers(shared), 1)
+	assert.Equal(t, peerB.ID(), p.GetPeer(shared).ID())
+
+	// Rejected peers can't advertise snapshots anymore
+	p.RejectPeer(peerB.ID())
+
+	assert.Empty(t, p.Ranked())
+	assert.False(t, p.Add(peerB, shared))
+	assert.Nil(t, p.GetPeer(shared))
+
+	// Removed peers can advertise snapshots again
+	assert.True(t, p.Add(peerA, single))
+}
@@ -0,0 +1,221 @@
+package statesync
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gnolang/gno/tm2/pkg/bft/light"
+	"github.com/gnolang/gno/tm2/pkg/bft/rpc/client"
+	ctypes "github.com/gnolang/gno/tm2/pkg/bft/rpc/core/types"
+	sm "github.com/gnolang/gno/tm2/pkg/bft/state"
+	"github.com/gnolang/gno/tm2/pkg/bft/statesync/config"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/db/memdb"
+)
+
+var (
+	errValidatorsMatch = errors.New("validator set does not match the header")
+	errParamsMatch     = errors.New("consensus params do not match the header")
+)
+
+// StateProvider provides the light-verified state and signed header
+// at a snapshot height
+type StateProvider interface {
+	// State returns the state after the block at the given height was committed
+	State(height int64) (sm.State, error)
+
+	// SignedHeader returns the header and commit of the block at the given height
+	SignedHeader(height int64) (*types.SignedHeader, error)
+}
+
+// rpcClient is the subset of the RPC client used to fetch the next
+// validators and the consensus params, which are verified
+// against the light-verified headers
+type rpcClient interface {
+	Validators(height *int64) (*ctypes.ResultValidators, error)
+	ConsensusParams(height *int64) (*ctypes.ResultConsensusParams, error)
+}
+
+// lightStateProvider is a StateProvider that fetches the state from RPC
+// servers, and verifies it with a light client anchored at the trusted
+// header. The light blocks are fetched from the primary server, and
+// cross-checked against the witness servers
+type lightStateProvider struct {
+	lc      *light.Client
+	primary rpcClient
+
+	initialState sm.State // provides the chain ID and the versions
+	now          func() time.Time
+}
+
+// NewLightStateProvider creates a new state provider, that light-verifies the
+// state fetched from the configured RPC servers. The initial state is the
+// genesis state of the chain
+func NewLightStateProvider(cfg *config.StateSyncConfig, initialState sm.State) (StateProvider, error) {
+	servers := cfg.RPCServerList()
+	if len(servers) < 2 {
+		return nil, errors.New("at least two rpc servers are required")
+	}
+
+	clients := make([]*client.RPCClient, 0, len(servers))
+
+	for _, server := range servers {
+		c, err := client.NewHTTPClient(server)
+		if err != nil {
+			return nil, fmt.Errorf("unable to create rpc client for %q, %w", server, err)
+		}
+
+		clients = append(clients, c)
+	}
+
+	trustHash, err := hex.DecodeString(cfg.TrustHash)
+	if err != nil {
+		return nil, fmt.Errorf("invalid trust hash, %w", err)
+	}
+
+	witnesses := make([]light.Provider, 0, len(clients)-1)
+	for _, c := range clients[1:] {
+		witnesses = append(witnesses, light.NewRPCProvider(c))
+	}
+
+	trustOptions := light.TrustOptions{
+		Period: cfg.TrustPeriod,
+		Height: cfg.TrustHeight,
+		Hash:   trustHash,
+	}
+
+	return newLightStateProvider(
+		initialState,
+		trustOptions,
+		light.NewRPCProvider(clients[0]),
+		witnesses,
+		clients[0],
+	)
+}
+
+// newLightStateProvider creates a new light state provider
+// using the given light block providers
+func newLightStateProvider(
+	initialState sm.State,
+	trustOptions light.TrustOptions,
+	primary light.Provider,
+	witnesses []light.Provider,
+	rpc rpcClient,
+) (*lightStateProvider, error) {
+	lc, err := light.NewClient(
+		initialState.ChainID,
+		trustOptions,
+		primary,
+		witnesses,
+		light.NewDBStore(memdb.NewMemDB()),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create light client, %w", err)
+	}
+
+	return &lightStateProvider{
+		lc:           lc,
+		primary:      rpc,
+		initialState: initialState,
+		now:          time.Now,
+	}, nil
+}
+
+// State implements StateProvider
+func (p *lightStateProvider) State(height int64) (sm.State, error) {
+	// The block at height+1 carries the results of executing the block
+	// at height: the app hash, the results hash and the next validators
+	current, err := p.lightBlock(height)
+	if err != nil {
+		return sm.State{}, err
+	}
+
+	next, err := p.lightBlock(height + 1)
+	if err != nil {
+		return sm.State{}, err
+	}
+
+	nextVals, err := p.fetchValidators(height+2, next.NextValidatorsHash)
+	if err != nil {
+		return sm.State{}, err
+	}
+
+	paramsHeight := height + 1
+
+	res, err := p.primary.ConsensusParams(&paramsHeight)
+	if err != nil {
+		return sm.State{}, fmt.Errorf("unable to fetch consensus params at height %d, %w", paramsHeight, err)
+	}
+
+	if !bytes.Equal(res.ConsensusParams.Hash(), next.ConsensusHash) {
+		return sm.State{}, fmt.Errorf("%w, height %d", errParamsMatch, paramsHeight)
+	}
+
+	return sm.State{
+		SoftwareVersion: p.initialState.SoftwareVersion,
+		BlockVersion:    p.initialState.BlockVersion,
+		AppVersion:      p.initialState.AppVersion,
+		ChainID:         p.initialState.ChainID,
+
+		LastBlockHeight:  height,
+		LastBlockTotalTx: current.TotalTxs,
+		LastBlockID:      current.Commit.BlockID,
+		LastBlockTime:    current.Time,
+
+		LastValidators:              current.ValidatorSet,
+		Validators:                  next.ValidatorSet,
+		NextValidators:              nextVals,
+		LastHeightValidatorsChanged: height + 2,
+
+		ConsensusParams:                  res.ConsensusParams,
+		LastHeightConsensusParamsChanged: paramsHeight,
+
+		LastResultsHash: next.LastResultsHash,
+		AppHash:         next.AppHash,
+	}, nil
+}
+
+// SignedHeader implements StateProvider
+func (p *lightStateProvider) SignedHeader(height int64) (*types.SignedHeader, error) {
+	lb, err := p.lightBlock(height)
+	if err != nil {
+		return nil, err
+	}
+
+	return lb.SignedHeader, nil
+}
+
+// lightBlock returns the light-verified light block at the given height
+func (p *lightStateProvider) lightBlock(height int64) (*light.LightBlock, error) {
+	lb, err := p.lc.VerifyLightBlockAtHeight(height, p.now())
+	if err != nil {
+		return nil, fmt.Errorf("unable to verify light block at height %d, %w", height, err)
+	}
+
+	return lb, nil
+}
+
+// fetchValidators fetches the validator set at the given height from
+// the primary server, and verifies it matches the given verified hash
+func (p *lightStateProvider) fetchValidators(height int64, hash []byte) (*types.ValidatorSet, error) {
+	res, err := p.primary.Validators(&height)
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch validators at height %d, %w", height, err)
+	}
+
+	if len(res.Validators) == 0 {
+		return nil, fmt.Errorf("empty validator set at height %d", height)
+	}
+
+	// The validators are used as is, to preserve their proposer priorities
+	vals := &types.ValidatorSet{Validators: res.Validators}
+
+	if !bytes.Equal(vals.Hash(), hash) {
+		return nil, fmt.Errorf("%w, height %d", errValidatorsMatch, height)
+	}
+
+	return vals, nil
+}
@@ -0,0 +1,223 @@
+package statesync
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	"github.com/gnolang/gno/tm2/pkg/bft/light"
+	ctypes "github.com/gnolang/gno/tm2/pkg/bft/rpc/core/types"
+	sm "github.com/gnolang/gno/tm2/pkg/bft/state"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+)
+
+const testChainID = "statesync-test"
+
+// mockChain is a mock chain, served by a mock rpc client and light block provider
+type mockChain struct {
+	headers    map[int64]*types.SignedHeader
+	validators *types.ValidatorSet
+	params     abci.ConsensusParams
+}
+
+// newMockChain creates a new mock chain with the given number of blocks,
+// all signed by the same validator set
+func newMockChain(t *testing.T, height int64) *mockChain {
+	t.Helper()
+
+	var (
+		vals, privVals = types.RandValidatorSet(4, 10)
+		params         = types.DefaultConsensusParams()
+
+		c = &mockChain{
+			headers:    make(map[int64]*types.SignedHeader),
+			validators: vals,
+			params:     params,
+		}
+	)
+
+	// The headers are recent, for the light client to trust them
+	var (
+		genesisTime = time.Now().Add(-time.Hour)
+		lastBlockID types.BlockID
+	)
+
+	for h := int64(1); h <= height; h++ {
+		header := &types.Header{
+			ChainID:            testChainID,
+			Height:             h,
+			Time:               genesisTime.Add(time.Duration(h) * time.Second),
+			LastBlockID:        lastBlockID,
+			TotalTxs:           h,
+			ValidatorsHash:     vals.Hash(),
+			NextValidatorsHash: vals.Hash(),
+			ConsensusHash:      params.Hash(),
+			AppHash:            []byte(fmt.Sprintf("app-hash-%d", h)),
+		}
+
+		blockID := types.BlockID{
+			Hash: header.Hash(),
+			PartsHeader: types.PartSetHeader{
+				Total: 1,
+				Hash:  []byte("parts"),
+			},
+		}
+
+		voteSet := types.NewVoteSet(testChainID, h, 0, types.PrecommitType, vals)
+
+		commit, err := types.MakeCommit(blockID, h, 0, voteSet, privVals)
+		require.NoError(t, err)
+
+		c.headers[h] = &types.SignedHeader{Header: header, Commit: commit}
+		lastBlockID = blockID
+	}
+
+	return c
+}
+
+func (c *mockChain) Commit(height *int64) (*ctypes.ResultCommit, error) {
+	header, ok := c.headers[*height]
+	if !ok {
+		return nil, fmt.Errorf("no header at height %d", *height)
+	}
+
+	return &ctypes.ResultCommit{SignedHeader: *header}, nil
+}
+
+func (c *mockChain) Validators(height *int64) (*ctypes.ResultValidators, error) {
+	return &ctypes.ResultValidators{
+		BlockHeight: *height,
+		Validators:  c.validators.Copy().Validators,
+	}, nil
+}
+
+func (c *mockChain) LightBlock(height int64) (*light.LightBlock, error) {
+	header, ok := c.headers[height]
+	if !ok {
+		return nil, fmt.Errorf("no header at height %d", height)
+	}
+
+	return &light.LightBlock{
+		SignedHeader: header,
+		ValidatorSet: c.validators.Copy(),
+	}, nil
+}
+
+func (c *mockChain) ConsensusParams(height *int64) (*ctypes.ResultConsensusParams, error) {
+	return &ctypes.ResultConsensusParams{
+		BlockHeight:     *height,
+		ConsensusParams: c.params,
+	}, nil
+}
+
+// trustOptions returns the light client trust options,
+// trusting the given header of the chain
+func trustOptions(c *mockChain, height int64) light.TrustOptions {
+	return light.TrustOptions{
+		Period: time.Hour * 24,
+		Height: height,
+		Hash:   c.headers[height].Hash(),
+	}
+}
+
+func TestLightStateProvider(t *testing.T) {
+	t.Parallel()
+
+	var (
+		chain   = newMockChain(t, 6)
+		initial = sm.State{ChainID: testChainID, SoftwareVersion: "v1"}
+	)
+
+	t.Run("valid state", func(t *testing.T) {
+		t.Parallel()
+
+		p, err := newLightStateProvider(initial, trustOptions(chain, 2), chain, []light.Provider{chain}, chain)
+		require.NoError(t, err)
+
+		state, err := p.State(4)
+		require.NoError(t, err)
+
+		assert.Equal(t, testChainID, state.ChainID)
+		assert.Equal(t, "v1", state.SoftwareVersion)
+		assert.Equal(t, int64(4), state.LastBlockHeight)
+		assert.Equal(t, int64(4), state.LastBlockTotalTx)
+		assert.Equal(t, chain.headers[4].Commit.BlockID, state.LastBlockID)
+		assert.Equal(t, chain.headers[5].AppHash, state.AppHash)
+		assert.Equal(t, chain.validators.Hash(), state.Validators.Hash())
+		assert.Equal(t, chain.validators.Hash(), state.NextValidators.Hash())
+		assert.Equal(t, chain.validators.Hash(), state.LastValidators.Hash())
+		assert.Equal(t, chain.params, state.ConsensusParams)
+
+		sh, err := p.SignedHeader(4)
+		require.NoError(t, err)
+		assert.Equal(t, chain.headers[4].Hash(), sh.Hash())
+		assert.Equal(t, chain.headers[4].Commit.Hash(), sh.Commit.Hash())
+	})
+
+	t.Run("below the trusted height", func(t *testing.T) {
+		t.Parallel()
+
+		p, err := newLightStateProvider(initial, trustOptions(chain, 4), chain, []light.Provider{chain}, chain)
+		require.NoError(t, err)
+
+		// The headers below the trusted header are verified backwards
+		state, err := p.State(2)
+		require.NoError(t, err)
+
+		assert.Equal(t, chain.headers[3].AppHash, state.AppHash)
+	})
+
+	t.Run("trust hash mismatch", func(t *testing.T) {
+		t.Parallel()
+
+		opts := trustOptions(chain, 2)
+		opts.Hash = chain.headers[3].Hash()
+
+		_, err := newLightStateProvider(initial, opts, chain, []light.Provider{chain}, chain)
+		assert.Error(t, err)
+	})
+
+	t.Run("witness conflict", func(t *testing.T) {
+		t.Parallel()
+
+		fork := newMockChain(t, 6)
+
+		// The fork agrees on the trusted header only
+		fork.headers[2] = chain.headers[2]
+
+		p, err := newLightStateProvider(initial, trustOptions(chain, 2), chain, []light.Provider{chain, fork}, chain)
+		require.NoError(t, err)
+
+		_, err = p.State(4)
+		assert.ErrorContains(t, err, "conflicting header")
+	})
+
+	t.Run("untrusted validators", func(t *testing.T) {
+		t.Parallel()
+
+		fork := newMockChain(t, 6)
+
+		// The trusted header is not signed by the fork validators
+		fork.headers[2] = chain.headers[2]
+
+		_, err := newLightStateProvider(initial, trustOptions(chain, 2), fork, []light.Provider{fork}, fork)
+		assert.Error(t, err)
+	})
+
+	t.Run("next validators mismatch", func(t *testing.T) {
+		t.Parallel()
+
+		other := newMockChain(t, 6)
+
+		// The validators at height+2 are not the verified next validators
+		p, err := newLightStateProvider(initial, trustOptions(chain, 2), chain, []light.Provider{chain}, other)
+		require.NoError(t, err)
+
+		_, err = p.State(4)
+		assert.ErrorIs(t, err, errValidatorsMatch)
+	})
+}
@@ -0,0 +1,585 @@
+package statesync
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	"github.com/gnolang/gno/tm2/pkg/bft/appconn"
+	sm "github.com/gnolang/gno/tm2/pkg/bft/state"
+	"github.com/gnolang/gno/tm2/pkg/bft/statesync/config"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/p2p"
+)
+
+// chunkStallFactor is the number of chunk request timeouts after which
+// a snapshot restoration with no chunk progress is considered stalled
+const chunkStallFactor = 10
+
+// chunkAllocateInterval is the interval at which idle chunk fetchers check
+// for chunks to fetch, as applied chunks can be discarded and refetched
+const chunkAllocateInterval = 500 * time.Millisecond
+
+var (
+	// errAbort is returned when the app aborted state sync
+	errAbort = errors.New("state sync aborted")
+	// errRetrySnapshot is returned when the snapshot restoration should be retried
+	errRetrySnapshot = errors.New("retry snapshot")
+	// errRejectSnapshot is returned when the snapshot was rejected
+	errRejectSnapshot = errors.New("snapshot was rejected")
+	// errRejectFormat is returned when the snapshot format was rejected
+	errRejectFormat = errors.New("snapshot format was rejected")
+	// errRejectSender is returned when the snapshot peers were rejected
+	errRejectSender = errors.New("snapshot sender was rejected")
+	// errVerifyFailed is returned when the restored app doesn't match the verified state
+	errVerifyFailed = errors.New("verification of the restored app failed")
+	// errNoSnapshots is returned when no snapshot is available
+	errNoSnapshots = errors.New("no suitable snapshots found")
+)
+
+// syncer restores the app state from a snapshot advertised by peers.
+// It offers the snapshots to the app, best first, fetches their chunks
+// from peers, and hands them over to the app until the restoration
+// succeeds, at which point the restored app is verified against
+// the light-verified state
+type syncer struct {
+	logger        *slog.Logger
+	cfg           *config.StateSyncConfig
+	stateProvider StateProvider
+	conn          appconn.Snapshot
+	connQuery     appconn.Query
+	snapshots     *snapshotPool
+
+	mtx    sync.RWMutex
+	chunks *chunkQueue // queue of the snapshot being restored, if any
+}
+
+// newSyncer creates a new state syncer
+func newSyncer(
+	cfg *config.StateSyncConfig,
+	logger *slog.Logger,
+	conn appconn.Snapshot,
+	connQuery appconn.Query,
+	stateProvider StateProvider,
+) *syncer {
+	return &syncer{
+		logger:        logger,
+		cfg:           cfg,
+		stateProvider: stateProvider,
+		conn:          conn,
+		connQuery:     connQuery,
+		snapshots:     newSnapshotPool(),
+	}
+}
+
+// AddChunk adds a chunk to the queue of the snapshot being restored,
+// if any. Returns a flag indicating if the chunk was added
+func (s *syncer) AddChunk(c *chunk) (bool, error) {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+
+	if s.chunks == nil {
+		return false, errors.New("no state sync in progress")
+	}
+
+	added, err := s.chunks.Add(c)
+	if err != nil {
+		return false, err
+	}
+
+	if added {
+		s.logger.Debug(
+			"Added chunk to queue",
+			"height", c.Height,
+			"format", c.Format,
+			"chunk", c.Index,
+		)
+	}
+
+	return added, nil
+}
+
+// MissingChunk handles a chunk the given peer is missing, usually as it
+// pruned the snapshot being restored. The peer isn't asked for the snapshot
+// chunks anymore, and the snapshot is rejected once no peer can serve it
+func (s *syncer) MissingChunk(c *chunk) {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+
+	if s.chunks == nil {
+		return
+	}
+
+	snap := s.chunks.snapshot
+	if c.Height != snap.Height || c.Format != snap.Format {
+		return
+	}
+
+	s.logger.Info(
+		"Peer is missing snapshot chunk",
+		"height", c.Height,
+		"format", c.Format,
+		"chunk", c.Index,
+		"peer", c.Sender,
+	)
+
+	if s.snapshots.RemoveSnapshotPeer(snap, c.Sender) == 0 {
+		s.chunks.Fail(fmt.Errorf("%w, no peer can serve the snapshot chunks", errRejectSnapshot))
+	}
+}
+
+// AddSnapshot adds a snapshot advertised by the given peer.
+// Returns a flag indicating if the snapshot is new
+func (s *syncer) AddSnapshot(peer p2p.Peer, snap *snapshot) bool {
+	added := s.snapshots.Add(peer, snap)
+	if added {
+		s.logger.Info(
+			"Discovered new snapshot",
+			"height", snap.Height,
+			"format", snap.Format,
+			"hash", fmt.Sprintf("%X", snap.Hash),
+		)
+	}
+
+	return added
+}
+
+// AddPeer requests the recent snapshots of the given peer
+func (s *syncer) AddPeer(peer p2p.Peer) {
+	s.logger.Debug("Requesting snapshots from peer", "peer", peer.ID())
+
+	peer.Send(SnapshotChannel, amino.MustMarshalAny(&snapshotsRequestMessage{}))
+}
+
+// RemovePeer removes the given peer, and the snapshots only it advertised
+func (s *syncer) RemovePeer(peer p2p.Peer) {
+	s.logger.Debug("Removing peer from sync", "peer", peer.ID())
+
+	s.snapshots.RemovePeer(peer.ID())
+}
+
+// SyncAny tries to restore any of the discovered snapshots, best first,
+// waiting for discoveryTime before the first attempt.
+// The requestSnapshots callback is invoked when no snapshots are available,
+// to discover new ones. Returns the verified state and commit
+// at the restored snapshot height
+func (s *syncer) SyncAny(
+	ctx context.Context,
+	discoveryTime time.Duration,
+	requestSnapshots func(),
+) (sm.State, *types.Commit, error) {
+	if discoveryTime > 0 {
+		s.logger.Info("Discovering snapshots", "discovery_time", discoveryTime)
+
+		select {
+		case <-ctx.Done():
+			return sm.State{}, nil, ctx.Err()
+		case <-time.After(discoveryTime):
+		}
+	}
+
+	var (
+		snap   *snapshot
+		chunks *chunkQueue
+	)
+
+	for {
+		// Pick the best snapshot, unless a snapshot restoration is retried
+		if snap == nil {
+			snap = s.snapshots.Best()
+			chunks = nil
+		}
+
+		if snap == nil {
+			if discoveryTime == 0 {
+				return sm.State{}, nil, errNoSnapshots
+			}
+
+			requestSnapshots()
+
+			s.logger.Info("No snapshots available, discovering more", "discovery_time", discoveryTime)
+
+			select {
+			case <-ctx.Done():
+				return sm.State{}, nil, ctx.Err()
+			case <-time.After(discoveryTime):
+			}
+
+			continue
+		}
+
+		if chunks == nil {
+			chunks = newChunkQueue(snap)
+		}
+
+		state, commit, err := s.Sync(ctx, snap, chunks)
+
+		switch {
+		case err == nil:
+			chunks.Close()
+
+			return state, commit, nil
+		case errors.Is(err, errAbort):
+			chunks.Close()
+
+			return sm.State{}, nil, err
+		case errors.Is(err, errRetrySnapshot):
+			chunks.RetryAll()
+
+			s.logger.Info(
+				"Retrying snapshot",
+				"height", snap.Height,
+				"format", snap.Format,
+				"hash", fmt.Sprintf("%X", snap.Hash),
+			)
+
+			continue
+		case errors.Is(err, errChunkNotArrived):
+			s.logger.Info(
+				"Timed out waiting for snapshot chunks, rejecting snapshot",
+				"height", snap.Height,
+				"format", snap.Format,
+				"hash", fmt.Sprintf("%X", snap.Hash),
+			)
+
+			s.snapshots.Reject(snap)
+		case errors.Is(err, errRejectSnapshot):
+			s.logger.Info(
+				"Snapshot rejected",
+				"height", snap.Height,
+				"format", snap.Format,
+				"hash", fmt.Sprintf("%X", snap.Hash),
+			)
+
+			s.snapshots.Reject(snap)
+		case errors.Is(err, errRejectFormat):
+			s.logger.Info("Snapshot format rejected", "format", snap.Format)
+
+			s.snapshots.RejectFormat(snap.Format)
+		case errors.Is(err, errRejectSender):
+			s.logger.Info(
+				"Snapshot senders rejected",
+				"height", snap.Height,
+				"format", snap.Format,
+				"hash", fmt.Sprintf("%X", snap.Hash),
+			)
+
+			for _, peer := range s.snapshots.GetPeers(snap) {
+				s.snapshots.RejectPeer(peer.ID())
+
+				s.logger.Info("Snapshot sender rejected", "peer", peer.ID())
+			}
+		default:
+			chunks.Close()
+
+			return sm.State{}, nil, fmt.Errorf("unable to restore snapshot, %w", err)
+		}
+
+		// Discard the snapshot chunks, and pick a new snapshot
+		chunks.Close()
+
+		snap = nil
+	}
+}
+
+// Sync restores the given snapshot, using the chunks in the given queue.
+// Returns the verified state and commit at the snapshot height
+func (s *syncer) Sync(ctx context.Context, snap *snapshot, chunks *chunkQueue) (sm.State, *types.Commit, error) {
+	s.mtx.Lock()
+	if s.chunks != nil {
+		s.mtx.Unlock()
+
+		return sm.State{}, nil, errors.New("a state sync is already in progress")
+	}
+
+	s.chunks = chunks
+	s.mtx.Unlock()
+
+	defer func() {
+		s.mtx.Lock()
+		s.chunks = nil
+		s.mtx.Unlock()
+	}()
+
+	// Fetch and verify the state and commit at the snapshot height,
+	// before offering the snapshot, so the app can verify the restoration
+	// against the trusted app hash
+	state, err := s.stateProvider.State(snap.Height)
+	if err != nil {
+		s.logger.Info("Failed to verify the snapshot height", "height", snap.Height, "err", err)
+
+		return sm.State{}, nil, fmt.Errorf("%w, unable to verify state, %w", errRejectSnapshot, err)
+	}
+
+	sh, err := s.stateProvider.SignedHeader(snap.Height)
+	if err != nil {
+		s.logger.Info("Failed to verify the snapshot commit", "height", snap.Height, "err", err)
+
+		return sm.State{}, nil, fmt.Errorf("%w, unable to verify commit, %w", errRejectSnapshot, err)
+	}
+
+	snap.trustedAppHash = state.AppHash
+	snap.trustedHeader = sh.Header
+
+	// Offer the snapshot to the app
+	if err := s.offerSnapshot(snap); err != nil {
+		return sm.State{}, nil, err
+	}
+
+	// Spawn the chunk fetchers. They stop once the restoration is over
+	fetchCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	for i := int32(0); i < s.cfg.ChunkFetchers; i++ {
+		go s.fetchChunks(fetchCtx, snap, chunks)
+	}
+
+	// Hand over the chunks to the app
+	if err := s.applyChunks(ctx, chunks); err != nil {
+		return sm.State{}, nil, err
+	}
+
+	// Verify the restored app against the light-verified state
+	appVersion, err := s.verifyApp(snap)
+	if err != nil {
+		return sm.State{}, nil, err
+	}
+
+	state.AppVersion = appVersion
+
+	s.logger.Info(
+		"Snapshot restored",
+		"height", snap.Height,
+		"format", snap.Format,
+		"hash", fmt.Sprintf("%X", snap.Hash),
+	)
+
+	return state, sh.Commit, nil
+}
+
+// offerSnapshot offers the snapshot to the app
+func (s *syncer) offerSnapshot(snap *snapshot) error {
+	s.logger.Info(
+		"Offering snapshot to the app",
+		"height", snap.Height,
+		"format", snap.Format,
+		"hash", fmt.Sprintf("%X", snap.Hash),
+	)
+
+	res, err := s.conn.OfferSnapshotSync(abci.RequestOfferSnapshot{
+		Snapshot: &abci.Snapshot{
+			Height:   snap.Height,
+			Format:   snap.Format,
+			Chunks:   snap.Chunks,
+			Hash:     snap.Hash,
+			Metadata: snap.Metadata,
+		},
+		AppHash: snap.trustedAppHash,
+		Header:  snap.trustedHeader,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to offer snapshot, %w", err)
+	}
+
+	switch res.Result {
+	case abci.OfferSnapshotAccept:
+		s.logger.Info("Snapshot accepted, restoring", "height", snap.Height, "format", snap.Format)
+
+		return nil
+	case abci.OfferSnapshotAbort:
+		return errAbort
+	case abci.OfferSnapshotReject:
+		return errRejectSnapshot
+	case abci.OfferSnapshotRejectFormat:
+		return errRejectFormat
+	case abci.OfferSnapshotRejectSender:
+		return errRejectSender
+	default:
+		return fmt.Errorf("unknown offer snapshot result %v", res.Result)
+	}
+}
+
+// applyChunks hands over the snapshot chunks to the app, in order,
+// until all of them are applied
+func (s *syncer) applyChunks(ctx context.Context, chunks *chunkQueue) error {
+	for {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		c, err := chunks.Next(chunkStallFactor * s.cfg.ChunkRequestTimeout)
+		if errors.Is(err, errDone) {
+			return nil
+		}
+
+		if err != nil {
+			return fmt.Errorf("failed to fetch chunk, %w", err)
+		}
+
+		res, err := s.conn.ApplySnapshotChunkSync(abci.RequestApplySnapshotChunk{
+			Index:  c.Index,
+			Chunk:  c.Chunk,
+			Sender: string(c.Sender),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to apply chunk %d, %w", c.Index, err)
+		}
+
+		s.logger.Info(
+			"Applied snapshot chunk",
+			"height", c.Height,
+			"format", c.Format,
+			"chunk", c.Index,
+			"total", chunks.snapshot.Chunks,
+		)
+
+		// Discard and refetch any chunks requested by the app
+		for _, index := range res.RefetchChunks {
+			chunks.Discard(index)
+		}
+
+		// Reject any senders requested by the app
+		for _, sender := range res.RejectSenders {
+			peerID := p2p.ID(sender)
+
+			s.snapshots.RejectPeer(peerID)
+			chunks.DiscardSender(peerID)
+		}
+
+		switch res.Result {
+		case abci.ApplySnapshotChunkAccept:
+		case abci.ApplySnapshotChunkAbort:
+			return errAbort
+		case abci.ApplySnapshotChunkRetry:
+			chunks.Retry(c.Index)
+		case abci.ApplySnapshotChunkRetrySnapshot:
+			return errRetrySnapshot
+		case abci.ApplySnapshotChunkRejectSnapshot:
+			return errRejectSnapshot
+		default:
+			return fmt.Errorf("unknown apply snapshot chunk result %v", res.Result)
+		}
+	}
+}
+
+// fetchChunks requests the allocated snapshot chunks from peers,
+// until the restoration is over
+func (s *syncer) fetchChunks(ctx context.Context, snap *snapshot, chunks *chunkQueue) {
+	var (
+		next  = true
+		index uint32
+		err   error
+	)
+
+	for {
+		if next {
+			index, err = chunks.Allocate()
+
+			switch {
+			case errors.Is(err, errDone):
+				return
+			case errors.Is(err, errNoChunkToFetch):
+				// All the chunks are allocated, but some may be discarded later
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(chunkAllocateInterval):
+				}
+
+				continue
+			case err != nil:
+				s.logger.Error("Failed to allocate chunk", "err", err)
+
+				return
+			}
+		}
+
+		s.logger.Info("Fetching snapshot chunk", "height", snap.Height, "format", snap.Format, "chunk", index)
+
+		ticker := time.NewTicker(s.cfg.ChunkRequestTimeout)
+
+		s.requestChunk(snap, index)
+
+		select {
+		case <-chunks.WaitFor(index):
+			next = true
+		case <-ticker.C:
+			// Request the chunk again, possibly from another peer
+			next = false
+		case <-ctx.Done():
+			ticker.Stop()
+
+			return
+		}
+
+		ticker.Stop()
+	}
+}
+
+// requestChunk requests the given chunk from a random peer advertising the snapshot
+func (s *syncer) requestChunk(snap *snapshot, index uint32) {
+	peer := s.snapshots.GetPeer(snap)
+	if peer == nil {
+		s.logger.Error(
+			"No valid peers found for snapshot",
+			"height", snap.Height,
+			"format", snap.Format,
+			"hash", fmt.Sprintf("%X", snap.Hash),
+		)
+
+		return
+	}
+
+	s.logger.Debug(
+		"Requesting snapshot chunk",
+		"height", snap.Height,
+		"format", snap.Format,
+		"chunk", index,
+		"peer", peer.ID(),
+	)
+
+	peer.Send(ChunkChannel, amino.MustMarshalAny(&chunkRequestMessage{
+		Height: snap.Height,
+		Format: snap.Format,
+		Index:  index,
+	}))
+}
+
+// verifyApp verifies the restored app matches the snapshot height and
+// trusted app hash. Returns the app version
+func (s *syncer) verifyApp(snap *snapshot) (string, error) {
+	res, err := s.connQuery.InfoSync(abci.RequestInfo{})
+	if err != nil {
+		return "", fmt.Errorf("failed to query app info, %w", err)
+	}
+
+	if !bytes.Equal(snap.trustedAppHash, res.LastBlockAppHash) {
+		s.logger.Error(
+			"App hash verification failed",
+			"expected", fmt.Sprintf("%X", snap.trustedAppHash),
+			"actual", fmt.Sprintf("%X", res.LastBlockAppHash),
+		)
+
+		return "", errVerifyFailed
+	}
+
+	if res.LastBlockHeight != snap.Height {
+		s.logger.Error(
+			"App height verification failed",
+			"expected", snap.Height,
+			"actual", res.LastBlockHeight,
+		)
+
+		return "", errVerifyFailed
+	}
+
+	s.logger.Info("Verified app", "height", snap.Height, "app_hash", fmt.Sprintf("%X", snap.trustedAppHash))
+
+	return res.AppVersion, nil
+}
@@ -0,0 +1,375 @@
+package statesync
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	sm "github.com/gnolang/gno/tm2/pkg/bft/state"
+	"github.com/gnolang/gno/tm2/pkg/bft/statesync/config"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/log"
+	"github.com/gnolang/gno/tm2/pkg/p2p/mock"
+)
+
+// mockApp is a mock app restoring snapshots
+type mockApp struct {
+	mtx sync.Mutex
+
+	offered []*abci.Snapshot
+	applied []uint32
+
+	offerResult func(*abci.Snapshot) abci.OfferSnapshotResult
+	applyResult func(abci.RequestApplySnapshotChunk) abci.ResponseApplySnapshotChunk
+
+	height  int64
+	appHash []byte
+	header  abci.Header
+}
+
+func (a *mockApp) Error() error {
+	return nil
+}
+
+func (a *mockApp) ListSnapshotsSync(abci.RequestListSnapshots) (abci.ResponseListSnapshots, error) {
+	return abci.ResponseListSnapshots{}, nil
+}
+
+func (a *mockApp) OfferSnapshotSync(req abci.RequestOfferSnapshot) (abci.ResponseOfferSnapshot, error) {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	a.offered = append(a.offered, req.Snapshot)
+	a.applied = nil
+
+	result := abci.OfferSnapshotAccept
+	if a.offerResult != nil {
+		result = a.offerResult(req.Snapshot)
+	}
+
+	if result == abci.OfferSnapshotAccept {
+		a.height = req.Snapshot.Height
+		a.appHash = req.AppHash
+		a.header = req.Header
+	}
+
+	return abci.ResponseOfferSnapshot{Result: result}, nil
+}
+
+func (a *mockApp) LoadSnapshotChunkSync(abci.RequestLoadSnapshotChunk) (abci.ResponseLoadSnapshotChunk, error) {
+	return abci.ResponseLoadSnapshotChunk{}, nil
+}
+
+func (a *mockApp) ApplySnapshotChunkSync(req abci.RequestApplySnapshotChunk) (abci.ResponseApplySnapshotChunk, error) {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	res := abci.ResponseApplySnapshotChunk{Result: abci.ApplySnapshotChunkAccept}
+	if a.applyResult != nil {
+		res = a.applyResult(req)
+	}
+
+	if res.Result == abci.ApplySnapshotChunkAccept {
+		a.applied = append(a.applied, req.Index)
+	}
+
+	return res, nil
+}
+
+func (a *mockApp) EchoSync(msg string) (abci.ResponseEcho, error) {
+	return abci.ResponseEcho{Message: msg}, nil
+}
+
+func (a *mockApp) InfoSync(abci.RequestInfo) (abci.ResponseInfo, error) {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	return abci.ResponseInfo{
+		AppVersion:       "v1",
+		LastBlockHeight:  a.height,
+		LastBlockAppHash: a.appHash,
+	}, nil
+}
+
+func (a *mockApp) QuerySync(abci.RequestQuery) (abci.ResponseQuery, error) {
+	return abci.ResponseQuery{}, nil
+}
+
+// mockStateProvider provides states with an app hash derived from the height
+type mockStateProvider struct{}
+
+func (mockStateProvider) State(height int64) (sm.State, error) {
+	return sm.State{LastBlockHeight: height, AppHash: []byte{byte(height)}}, nil
+}
+
+func (mockStateProvider) SignedHeader(height int64) (*types.SignedHeader, error) {
+	return &types.SignedHeader{
+		Header: &types.Header{Height: height},
+		Commit: &types.Commit{},
+	}, nil
+}
+
+// chunkPeer is a mock peer that serves the requested chunks to the syncer
+type chunkPeer struct {
+	*mock.Peer
+
+	syncer *syncer
+	pruned int64 // height of a snapshot whose chunks are missing
+}
+
+func newChunkPeer(s *syncer) *chunkPeer {
+	return &chunkPeer{
+		Peer:   mock.NewPeer(nil),
+		syncer: s,
+	}
+}
+
+func (p *chunkPeer) Send(chID byte, msgBytes []byte) bool {
+	if chID != ChunkChannel {
+		return true
+	}
+
+	msg, err := decodeMsg(msgBytes, chunkMsgSize)
+	if err != nil {
+		panic(err)
+	}
+
+	req := msg.(*chunkRequestMessage)
+
+	go func() {
+		if req.Height == p.pruned {
+			p.syncer.MissingChunk(&chunk{
+				Height: req.Height,
+				Format: req.Format,
+				Index:  req.Index,
+				Sender: p.ID(),
+			})
+
+			return
+		}
+
+		_, _ = p.syncer.AddChunk(&chunk{
+			Height: req.Height,
+			Format: req.Format,
+			Index:  req.Index,
+			Chunk:  []byte{byte(req.Index)},
+			Sender: p.ID(),
+		})
+	}()
+
+	return true
+}
+
+func (p *chunkPeer) TrySend(chID byte, msgBytes []byte) bool {
+	return p.Send(chID, msgBytes)
+}
+
+// newTestSyncer creates a new syncer using the given mock app
+func newTestSyncer(app *mockApp) *syncer {
+	return newSyncer(config.TestStateSyncConfig(), log.NewNoopLogger(), app, app, mockStateProvider{})
+}
+
+func TestSyncer_SyncAny(t *testing.T) {
+	t.Parallel()
+
+	app := &mockApp{}
+	s := newTestSyncer(app)
+
+	peer := newChunkPeer(s)
+
+	old := &snapshot{Height: 2, Format: 1, Chunks: 2, Hash: []byte{2}}
+	best := &snapshot{Height: 3, Format: 1, Chunks: 3, Hash: []byte{3}}
+
+	require.True(t, s.AddSnapshot(peer, old))
+	require.True(t, s.AddSnapshot(peer, best))
+
+	state, commit, err := s.SyncAny(context.Background(), 0, func() {})
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(3), state.LastBlockHeight)
+	assert.Equal(t, "v1", state.AppVersion)
+	assert.NotNil(t, commit)
+
+	require.Len(t, app.offered, 1)
+	assert.Equal(t, best.Height, app.offered[0].Height)
+	assert.Equal(t, best.Height, app.header.GetHeight())
+	assert.Equal(t, []uint32{0, 1, 2}, app.applied)
+}
+
+func TestSyncer_SyncAny_Reject(t *testing.T) {
+	t.Parallel()
+
+	app := &mockApp{
+		offerResult: func(s *abci.Snapshot) abci.OfferSnapshotResult {
+			if s.Format == 2 {
+				return abci.OfferSnapshotRejectFormat
+			}
+
+			if s.Height == 3 {
+				return abci.OfferSnapshotReject
+			}
+
+			return abci.OfferSnapshotAccept
+		},
+	}
+	s := newTestSyncer(app)
+
+	peer := newChunkPeer(s)
+
+	require.True(t, s.AddSnapshot(peer, &snapshot{Height: 4, Format: 2, Chunks: 1, Hash: []byte{4}}))
+	require.True(t, s.AddSnapshot(peer, &snapshot{Height: 3, Format: 1, Chunks: 1, Hash: []byte{3}}))
+	require.True(t, s.AddSnapshot(peer, &snapshot{Height: 2, Format: 1, Chunks: 1, Hash: []byte{2}}))
+
+	state, _, err := s.SyncAny(context.Background(), 0, func() {})
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(2), state.LastBlockHeight)
+	assert.Len(t, app.offered, 3)
+
+	// Rejected snapshots and formats are not added again
+	assert.False(t, s.AddSnapshot(peer, &snapshot{Height: 5, Format: 2, Chunks: 1, Hash: []byte{5}}))
+	assert.False(t, s.AddSnapshot(peer, &snapshot{Height: 3, Format: 1, Chunks: 1, Hash: []byte{3}}))
+}
+
+func TestSyncer_SyncAny_RetryChunk(t *testing.T) {
+	t.Parallel()
+
+	retried := false
+
+	app := &mockApp{
+		applyResult: func(req abci.RequestApplySnapshotChunk) abci.ResponseApplySnapshotChunk {
+			if req.Index == 1 && !retried {
+				retried = true
+
+				return abci.ResponseApplySnapshotChunk{
+					Result:        abci.ApplySnapshotChunkRetry,
+					RefetchChunks: []uint32{1},
+				}
+			}
+
+			return abci.ResponseApplySnapshotChunk{Result: abci.ApplySnapshotChunkAccept}
+		},
+	}
+	s := newTestSyncer(app)
+
+	peer := newChunkPeer(s)
+
+	require.True(t, s.AddSnapshot(peer, &snapshot{Height: 3, Format: 1, Chunks: 3, Hash: []byte{3}}))
+
+	_, _, err := s.SyncAny(context.Background(), 0, func() {})
+	require.NoError(t, err)
+
+	assert.True(t, retried)
+	assert.Equal(t, []uint32{0, 1, 2}, app.applied)
+}
+
+func TestSyncer_SyncAny_MissingChunk(t *testing.T) {
+	t.Parallel()
+
+	app := &mockApp{}
+	s := newTestSyncer(app)
+
+	peer := newChunkPeer(s)
+	peer.pruned = 3
+
+	require.True(t, s.AddSnapshot(peer, &snapshot{Height: 3, Format: 1, Chunks: 3, Hash: []byte{3}}))
+	require.True(t, s.AddSnapshot(peer, &snapshot{Height: 2, Format: 1, Chunks: 2, Hash: []byte{2}}))
+
+	// The snapshot is rejected once no peer can serve its chunks,
+	// without waiting for them to stall
+	start := time.Now()
+
+	state, _, err := s.SyncAny(context.Background(), 0, func() {})
+	require.NoError(t, err)
+
+	assert.Less(t, time.Since(start), chunkStallFactor*s.cfg.ChunkRequestTimeout)
+	assert.Equal(t, int64(2), state.LastBlockHeight)
+	assert.Len(t, app.offered, 2)
+	assert.Equal(t, []uint32{0, 1}, app.applied)
+}
+
+func TestSyncer_SyncAny_Abort(t *testing.T) {
+	t.Parallel()
+
+	app := &mockApp{
+		applyResult: func(abci.RequestApplySnapshotChunk) abci.ResponseApplySnapshotChunk {
+			return abci.ResponseApplySnapshotChunk{Result: abci.ApplySnapshotChunkAbort}
+		},
+	}
+	s := newTestSyncer(app)
+
+	peer := newChunkPeer(s)
+
+	require.True(t, s.AddSnapshot(peer, &snapshot{Height: 3, Format: 1, Chunks: 3, Hash: []byte{3}}))
+
+	_, _, err := s.SyncAny(context.Background(), 0, func() {})
+	assert.ErrorIs(t, err, errAbort)
+}
+
+func TestSyncer_SyncAny_NoSnapshots(t *testing.T) {
+	t.Parallel()
+
+	s := newTestSyncer(&mockApp{})
+
+	_, _, err := s.SyncAny(context.Background(), 0, func() {})
+	assert.ErrorIs(t, err, errNoSnapshots)
+
+	// Discovery continues until the context is canceled
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	requested := make(chan struct{}, 1)
+
+	_, _, err = s.SyncAny(ctx, 10*time.Millisecond, func() {
+		select {
+		case requested <- struct{}{}:
+		default:
+		}
+	})
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	assert.Len(t, requested, 1)
+}
+
+func TestSyncer_VerifyApp(t *testing.T) {
+	t.Parallel()
+
+	app := &mockApp{height: 3, appHash: []byte{4}}
+	s := newTestSyncer(app)
+
+	_, err := s.verifyApp(&snapshot{Height: 3, trustedAppHash: []byte{3}})
+	assert.ErrorIs(t, err, errVerifyFailed)
+
+	_, err = s.verifyApp(&snapshot{Height: 2, trustedAppHash: []byte{4}})
+	assert.ErrorIs(t, err, errVerifyFailed)
+
+	version, err := s.verifyApp(&snapshot{Height: 3, trustedAppHash: []byte{4}})
+	require.NoError(t, err)
+	assert.Equal(t, "v1", version)
+}
+
+func TestMessages_Roundtrip(t *testing.T) {
+	t.Parallel()
+
+	msgs := []StateSyncMessage{
+		&snapshotsRequestMessage{},
+		&snapshotsResponseMessage{Height: 1, Format: 1, Chunks: 2, Hash: []byte{1}, Metadata: []byte{2}},
+		&chunkRequestMessage{Height: 1, Format: 1, Index: 1},
+		&chunkResponseMessage{Height: 1, Format: 1, Index: 1, Chunk: []byte{1}},
+	}
+
+	for _, msg := range msgs {
+		decoded, err := decodeMsg(amino.MustMarshalAny(msg), chunkMsgSize)
+		require.NoError(t, err)
+		assert.Equal(t, msg, decoded)
+	}
+
+	_, err := decodeMsg(make([]byte, snapshotMsgSize+1), snapshotMsgSize)
+	assert.Error(t, err)
+}
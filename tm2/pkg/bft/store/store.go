@@ -187,6 +187,34 @@ func (bs *BlockStore) SaveBlock(block *types.Block, blockParts *types.PartSet, s
 	bs.db.SetSync(nil, nil)
 }
 
+// Bootstrap initializes an empty BlockStore at the given height, which was
+// restored by state sync, by persisting the seen commit for that height.
+// The blocks up to the given height are not available, and the next saved
+// block must be at height+1.
+func (bs *BlockStore) Bootstrap(height int64, seenCommit *types.Commit) {
+	if bs.Height() != 0 {
+		panic(fmt.Sprintf("BlockStore can only be bootstrapped when empty, height is %v", bs.Height()))
+	}
+	if seenCommit == nil || seenCommit.Height() != height {
+		panic("BlockStore can only be bootstrapped with the seen commit for the height")
+	}
+
+	// Save seen commit (seen +2/3 precommits for block)
+	seenCommitBytes := amino.MustMarshal(seenCommit)
+	bs.db.Set(calcSeenCommitKey(height), seenCommitBytes)
+
+	// Save new BlockStoreStateJSON descriptor
+	BlockStoreStateJSON{Height: height}.Save(bs.db)
+
+	// Done!
+	bs.mtx.Lock()
+	bs.height = height
+	bs.mtx.Unlock()
+
+	// Flush
+	bs.db.SetSync(nil, nil)
+}
+
 func (bs *BlockStore) saveBlockPart(height int64, index int, part *types.Part) {
 	if height != bs.Height()+1 {
 		panic(fmt.Sprintf("BlockStore can only save contiguous blocks. Wanted %v, got %v", bs.Height()+1, height))
@@ -449,3 +449,31 @@ func newBlock(hdr types.Header, lastCommit *types.Commit) *types.Block {
 		LastCommit: lastCommit,
 	}
 }
+
+func TestBlockStoreBootstrap(t *testing.T) {
+	t.Parallel()
+
+	_, bs, cleanup := makeStateAndBlockStore(log.NewNoopLogger())
+	defer cleanup()
+
+	const height = int64(10)
+
+	// Only the seen commit for the height is accepted
+	require.Panics(t, func() { bs.Bootstrap(height, makeTestCommit(height-1, tmtime.Now())) })
+
+	seenCommit := makeTestCommit(height, tmtime.Now())
+	bs.Bootstrap(height, seenCommit)
+
+	assert.Equal(t, height, bs.Height())
+	assert.Equal(t, height, LoadBlockStoreStateJSON(bs.db).Height)
+	assert.Equal(t, seenCommit.Hash(), bs.LoadSeenCommit(height).Hash())
+	assert.Nil(t, bs.LoadBlock(height))
+
+	// A non-empty store can't be bootstrapped
+	require.Panics(t, func() { bs.Bootstrap(height, seenCommit) })
+
+	// The following blocks can be saved
+	block := newBlock(types.Header{Height: height + 1}, seenCommit)
+	bs.SaveBlock(block, block.MakePartSet(2), makeTestCommit(height+1, tmtime.Now()))
+	assert.Equal(t, height+1, bs.Height())
+}
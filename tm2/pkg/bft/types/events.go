@@ -13,6 +13,7 @@ type TMEvent interface {
 func (EventNewBlock) AssertEvent()            {}
 func (EventNewBlockHeader) AssertEvent()      {}
 func (EventTx) AssertEvent()                  {}
+func (EventTxEvent) AssertEvent()             {}
 func (EventVote) AssertEvent()                {}
 func (EventString) AssertEvent()              {}
 func (EventValidatorSetUpdates) AssertEvent() {}
@@ -40,6 +41,16 @@ type EventTx struct {
 	Result TxResult `json:"result"`
 }
 
+// A single application event emitted by a tx.
+// Delivered to RPC subscribers that filter on individual events,
+// instead of the entire EventTx
+type EventTxEvent struct {
+	Height int64      `json:"height"`
+	Index  uint32     `json:"index"`
+	TxHash []byte     `json:"tx_hash"`
+	Event  abci.Event `json:"event"`
+}
+
 type EventVote struct {
 	Vote *Vote `json:"vote"`
 }
@@ -45,6 +45,7 @@ var Package = amino.RegisterPackage(amino.NewPackage(
 		EventNewBlock{},
 		EventNewBlockHeader{},
 		EventTx{},
+		EventTxEvent{},
 		EventVote{},
 		EventString(""),
 		EventValidatorSetUpdates{},
@@ -0,0 +1,234 @@
+package client
+
+import (
+	"context"
+	"flag"
+
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	"github.com/gnolang/gno/tm2/pkg/commands"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/gnolang/gno/tm2/pkg/crypto/keys"
+	"github.com/gnolang/gno/tm2/pkg/errors"
+	"github.com/gnolang/gno/tm2/pkg/sdk/auth"
+	"github.com/gnolang/gno/tm2/pkg/std"
+)
+
+type MakeGrantCfg struct {
+	RootCfg *MakeTxCfg
+
+	Grantee          string
+	SpendLimit       string
+	ExpirationHeight int64
+	AllowedMsgs      commands.StringArr
+	AllowedRealms    commands.StringArr
+}
+
+func NewMakeGrantCmd(rootCfg *MakeTxCfg, io commands.IO) *commands.Command {
+	cfg := &MakeGrantCfg{
+		RootCfg: rootCfg,
+	}
+
+	return commands.NewCommand(
+		commands.Metadata{
+			Name:       "grant",
+			ShortUsage: "grant [flags] <key-name or address>",
+			ShortHelp:  "grants a fee allowance to another account",
+			LongHelp: `Grants a fee allowance to the grantee, which can then set the key as the fee
+granter of its transactions (see -fee-granter), to have their gas fees paid by
+the key. The allowance replaces the one previously granted to the grantee.`,
+		},
+		cfg,
+		func(_ context.Context, args []string) error {
+			return execMakeGrant(cfg, args, io)
+		},
+	)
+}
+
+func (c *MakeGrantCfg) RegisterFlags(fs *flag.FlagSet) {
+	fs.StringVar(
+		&c.Grantee,
+		"grantee",
+		"",
+		"address of the account whose fees are paid",
+	)
+
+	fs.StringVar(
+		&c.SpendLimit,
+		"spend-limit",
+		"",
+		"maximum amount of fees paid (default: no limit)",
+	)
+
+	fs.Int64Var(
+		&c.ExpirationHeight,
+		"expiration-height",
+		0,
+		"last block height at which the allowance can be used (default: no expiration)",
+	)
+
+	fs.Var(
+		&c.AllowedMsgs,
+		"allowed-msg",
+		"type of message allowed, like bank/send or vm/exec; can be specified multiple times (default: all)",
+	)
+
+	fs.Var(
+		&c.AllowedRealms,
+		"allowed-realm",
+		"path of a realm which can be called; can be specified multiple times (default: all)",
+	)
+}
+
+func execMakeGrant(cfg *MakeGrantCfg, args []string, io commands.IO) error {
+	if len(args) != 1 {
+		return flag.ErrHelp
+	}
+
+	if cfg.RootCfg.GasWanted == 0 {
+		return errors.New("gas-wanted not specified")
+	}
+	if cfg.RootCfg.GasFee == "" {
+		return errors.New("gas-fee not specified")
+	}
+	if cfg.Grantee == "" {
+		return errors.New("grantee must be specified")
+	}
+
+	granter, err := getAddress(cfg.RootCfg, args[0])
+	if err != nil {
+		return err
+	}
+
+	// Parse grantee address.
+	grantee, err := crypto.AddressFromBech32(cfg.Grantee)
+	if err != nil {
+		return err
+	}
+
+	// Parse spend limit.
+	var spendLimit std.Coins
+	if cfg.SpendLimit != "" {
+		spendLimit, err = std.ParseCoins(cfg.SpendLimit)
+		if err != nil {
+			return errors.Wrap(err, "parsing spend limit coins")
+		}
+	}
+
+	// parse gas wanted & fee.
+	fee, err := cfg.RootCfg.ParseFee()
+	if err != nil {
+		return err
+	}
+
+	// construct msg & tx and marshal.
+	msg := auth.NewMsgGrantFeeAllowance(granter, grantee, auth.FeeAllowance{
+		SpendLimit:       spendLimit,
+		ExpirationHeight: cfg.ExpirationHeight,
+		AllowedMsgs:      cfg.AllowedMsgs,
+		AllowedRealms:    cfg.AllowedRealms,
+	})
+	if err := msg.ValidateBasic(); err != nil {
+		return err
+	}
+
+	return makeTx(cfg.RootCfg, args, msg, fee, io)
+}
+
+type MakeRevokeCfg struct {
+	RootCfg *MakeTxCfg
+
+	Grantee string
+}
+
+func NewMakeRevokeCmd(rootCfg *MakeTxCfg, io commands.IO) *commands.Command {
+	cfg := &MakeRevokeCfg{
+		RootCfg: rootCfg,
+	}
+
+	return commands.NewCommand(
+		commands.Metadata{
+			Name:       "revoke",
+			ShortUsage: "revoke [flags] <key-name or address>",
+			ShortHelp:  "revokes the fee allowance granted to another account",
+		},
+		cfg,
+		func(_ context.Context, args []string) error {
+			return execMakeRevoke(cfg, args, io)
+		},
+	)
+}
+
+func (c *MakeRevokeCfg) RegisterFlags(fs *flag.FlagSet) {
+	fs.StringVar(
+		&c.Grantee,
+		"grantee",
+		"",
+		"address of the account whose fees are paid",
+	)
+}
+
+func execMakeRevoke(cfg *MakeRevokeCfg, args []string, io commands.IO) error {
+	if len(args) != 1 {
+		return flag.ErrHelp
+	}
+
+	if cfg.RootCfg.GasWanted == 0 {
+		return errors.New("gas-wanted not specified")
+	}
+	if cfg.RootCfg.GasFee == "" {
+		return errors.New("gas-fee not specified")
+	}
+	if cfg.Grantee == "" {
+		return errors.New("grantee must be specified")
+	}
+
+	granter, err := getAddress(cfg.RootCfg, args[0])
+	if err != nil {
+		return err
+	}
+
+	// Parse grantee address.
+	grantee, err := crypto.AddressFromBech32(cfg.Grantee)
+	if err != nil {
+		return err
+	}
+
+	// parse gas wanted & fee.
+	fee, err := cfg.RootCfg.ParseFee()
+	if err != nil {
+		return err
+	}
+
+	msg := auth.NewMsgRevokeFeeAllowance(granter, grantee)
+	return makeTx(cfg.RootCfg, args, msg, fee, io)
+}
+
+// getAddress returns the address of the account of the key nameOrBech32.
+func getAddress(cfg *MakeTxCfg, nameOrBech32 string) (crypto.Address, error) {
+	kb, err := keys.NewKeyBaseFromDir(cfg.RootCfg.Home)
+	if err != nil {
+		return crypto.Address{}, err
+	}
+	info, err := kb.GetByNameOrAddress(nameOrBech32)
+	if err != nil {
+		return crypto.Address{}, err
+	}
+	return cfg.AccountAddress(info)
+}
+
+// makeTx prints the tx with msg, or signs and broadcasts it.
+func makeTx(cfg *MakeTxCfg, args []string, msg std.Msg, fee std.Fee, io commands.IO) error {
+	tx := std.Tx{
+		Msgs:          []std.Msg{msg},
+		Fee:           fee,
+		Signatures:    nil,
+		Memo:          cfg.Memo,
+		TimeoutHeight: cfg.TimeoutHeight,
+	}
+
+	if cfg.Broadcast {
+		return ExecSignAndBroadcast(cfg, args, tx, io)
+	}
+	io.Println(string(amino.MustMarshalJSON(tx)))
+	return nil
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/gnolang/gno/tm2/pkg/amino"
 	types "github.com/gnolang/gno/tm2/pkg/bft/rpc/core/types"
 	"github.com/gnolang/gno/tm2/pkg/commands"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
 	"github.com/gnolang/gno/tm2/pkg/crypto/keys"
 	"github.com/gnolang/gno/tm2/pkg/errors"
 	"github.com/gnolang/gno/tm2/pkg/std"
@@ -16,9 +17,12 @@ import (
 type MakeTxCfg struct {
 	RootCfg *BaseCfg
 
-	GasWanted int64
-	GasFee    string
-	Memo      string
+	GasWanted     int64
+	GasFee        string
+	FeeGranter    string
+	Account       string
+	Memo          string
+	TimeoutHeight int64
 
 	Broadcast bool
 	// Valid options are SimulateTest, SimulateSkip or SimulateOnly.
@@ -59,6 +63,9 @@ func NewMakeTxCmd(rootCfg *BaseCfg, io commands.IO) *commands.Command {
 
 	cmd.AddSubCommands(
 		NewMakeSendCmd(cfg, io),
+		NewMakeGrantCmd(cfg, io),
+		NewMakeRevokeCmd(cfg, io),
+		NewMakeRotateKeyCmd(cfg, io),
 	)
 
 	return cmd
@@ -79,6 +86,20 @@ func (c *MakeTxCfg) RegisterFlags(fs *flag.FlagSet) {
 		"gas payment fee",
 	)
 
+	fs.StringVar(
+		&c.FeeGranter,
+		"fee-granter",
+		"",
+		"address of the account paying the gas fee, within the fee allowance it granted",
+	)
+
+	fs.StringVar(
+		&c.Account,
+		"account",
+		"",
+		"address of the account of the tx, if not the address of the key, like after a key rotation",
+	)
+
 	fs.StringVar(
 		&c.Memo,
 		"memo",
@@ -86,6 +107,13 @@ func (c *MakeTxCfg) RegisterFlags(fs *flag.FlagSet) {
 		"any descriptive text",
 	)
 
+	fs.Int64Var(
+		&c.TimeoutHeight,
+		"timeout-height",
+		0,
+		"last block height at which the tx can be included (default: no timeout)",
+	)
+
 	fs.BoolVar(
 		&c.Broadcast,
 		"broadcast",
@@ -111,6 +139,37 @@ func (c *MakeTxCfg) RegisterFlags(fs *flag.FlagSet) {
 	)
 }
 
+// AccountAddress returns the address of the account of the tx signed with the
+// key info: the account flag if set, or the address of the key.
+func (c *MakeTxCfg) AccountAddress(info keys.Info) (crypto.Address, error) {
+	if c.Account == "" {
+		return info.GetAddress(), nil
+	}
+	addr, err := crypto.AddressFromBech32(c.Account)
+	if err != nil {
+		return crypto.Address{}, errors.Wrap(err, "parsing account address")
+	}
+	return addr, nil
+}
+
+// ParseFee parses the gas fee, and the fee granter if any.
+func (c *MakeTxCfg) ParseFee() (std.Fee, error) {
+	gasfee, err := std.ParseCoin(c.GasFee)
+	if err != nil {
+		return std.Fee{}, errors.Wrap(err, "parsing gas fee coin")
+	}
+	fee := std.NewFee(c.GasWanted, gasfee)
+
+	if c.FeeGranter != "" {
+		granter, err := crypto.AddressFromBech32(c.FeeGranter)
+		if err != nil {
+			return std.Fee{}, errors.Wrap(err, "parsing fee granter address")
+		}
+		fee = fee.WithGranter(granter)
+	}
+	return fee, nil
+}
+
 func SignAndBroadcastHandler(
 	cfg *MakeTxCfg,
 	nameOrBech32 string,
@@ -129,25 +188,31 @@ func SignAndBroadcastHandler(
 	if err != nil {
 		return nil, err
 	}
-	accountAddr := info.GetAddress()
+	accountAddr, err := cfg.AccountAddress(info)
+	if err != nil {
+		return nil, err
+	}
 
 	qopts := &QueryCfg{
 		RootCfg: baseopts,
-		Path:    fmt.Sprintf("auth/accounts/%s", accountAddr),
+		Path:    fmt.Sprintf("auth/typedaccounts/%s", accountAddr),
 	}
 	qres, err := QueryHandler(qopts)
 	if err != nil {
 		return nil, errors.Wrap(err, "query account")
 	}
-	var qret struct{ BaseAccount std.BaseAccount }
-	err = amino.UnmarshalJSON(qres.Response.Data, &qret)
+	var acc std.Account
+	err = amino.UnmarshalJSON(qres.Response.Data, &acc)
 	if err != nil {
 		return nil, err
 	}
 
 	// sign tx
-	accountNumber := qret.BaseAccount.AccountNumber
-	sequence := qret.BaseAccount.Sequence
+	var accountNumber, sequence uint64
+	if acc != nil {
+		accountNumber = acc.GetAccountNumber()
+		sequence = acc.GetSequence()
+	}
 
 	sOpts := signOpts{
 		chainID:         txopts.ChainID,
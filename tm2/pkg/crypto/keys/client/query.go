@@ -3,11 +3,15 @@ package client
 import (
 	"context"
 	"flag"
+	"strings"
 
+	"github.com/gnolang/gno/tm2/pkg/amino"
 	"github.com/gnolang/gno/tm2/pkg/bft/rpc/client"
 	ctypes "github.com/gnolang/gno/tm2/pkg/bft/rpc/core/types"
 	"github.com/gnolang/gno/tm2/pkg/commands"
 	"github.com/gnolang/gno/tm2/pkg/errors"
+	"github.com/gnolang/gno/tm2/pkg/sdk/bank"
+	"github.com/gnolang/gno/tm2/pkg/std"
 )
 
 type QueryCfg struct {
@@ -69,9 +73,39 @@ func execQuery(cfg *QueryCfg, args []string, io commands.IO) error {
 	io.Printf("height: %d\ndata: %s\n",
 		height,
 		string(resdata))
+
+	if strings.HasPrefix(cfg.Path, "bank/balances/") {
+		if display := displayBalance(cfg, resdata); display != "" {
+			io.Printf("display: %s\n", display)
+		}
+	}
 	return nil
 }
 
+// displayBalance returns the balance in resdata formatted in display units,
+// using the denom metadata of the bank module. It returns an empty string
+// if the balance is empty or if no denom metadata is available.
+func displayBalance(cfg *QueryCfg, resdata []byte) string {
+	var coins std.Coins
+	if err := amino.UnmarshalJSON(resdata, &coins); err != nil || coins.IsZero() {
+		return ""
+	}
+
+	qres, err := QueryHandler(&QueryCfg{
+		RootCfg: cfg.RootCfg,
+		Path:    "bank/denoms",
+	})
+	if err != nil || qres.Response.Error != nil {
+		return ""
+	}
+	var metadata []bank.DenomMetadata
+	if err := amino.UnmarshalJSON(qres.Response.Data, &metadata); err != nil || len(metadata) == 0 {
+		return ""
+	}
+
+	return bank.FormatCoins(coins, metadata)
+}
+
 func QueryHandler(cfg *QueryCfg) (*ctypes.ResultABCIQuery, error) {
 	remote := cfg.RootCfg.Remote
 	if remote == "" {
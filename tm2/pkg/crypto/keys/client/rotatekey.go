@@ -0,0 +1,116 @@
+package client
+
+import (
+	"context"
+	"flag"
+
+	"github.com/gnolang/gno/tm2/pkg/commands"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/gnolang/gno/tm2/pkg/crypto/keys"
+	"github.com/gnolang/gno/tm2/pkg/errors"
+	"github.com/gnolang/gno/tm2/pkg/sdk/auth"
+)
+
+type MakeRotateKeyCfg struct {
+	RootCfg *MakeTxCfg
+
+	NewKey string
+}
+
+func NewMakeRotateKeyCmd(rootCfg *MakeTxCfg, io commands.IO) *commands.Command {
+	cfg := &MakeRotateKeyCfg{
+		RootCfg: rootCfg,
+	}
+
+	return commands.NewCommand(
+		commands.Metadata{
+			Name:       "rotatekey",
+			ShortUsage: "rotatekey [flags] <key-name or address>",
+			ShortHelp:  "replaces the public key of an account on-chain",
+			LongHelp: `Replaces the public key of the account of the key with the new key, which
+signs the rotation, so it must be a key of the keybase able to sign. The account
+keeps its address, its coins and the realms it owns, but its transactions must
+then be signed with the new key only.`,
+		},
+		cfg,
+		func(_ context.Context, args []string) error {
+			return execMakeRotateKey(cfg, args, io)
+		},
+	)
+}
+
+func (c *MakeRotateKeyCfg) RegisterFlags(fs *flag.FlagSet) {
+	fs.StringVar(
+		&c.NewKey,
+		"new-key",
+		"",
+		"name or address of the new key in the keybase",
+	)
+}
+
+func execMakeRotateKey(cfg *MakeRotateKeyCfg, args []string, io commands.IO) error {
+	if len(args) != 1 {
+		return flag.ErrHelp
+	}
+
+	if cfg.RootCfg.GasWanted == 0 {
+		return errors.New("gas-wanted not specified")
+	}
+	if cfg.RootCfg.GasFee == "" {
+		return errors.New("gas-fee not specified")
+	}
+	if cfg.NewKey == "" {
+		return errors.New("new-key must be specified")
+	}
+
+	addr, err := getAddress(cfg.RootCfg, args[0])
+	if err != nil {
+		return err
+	}
+
+	// parse gas wanted & fee.
+	fee, err := cfg.RootCfg.ParseFee()
+	if err != nil {
+		return err
+	}
+
+	msg, err := signRotateKey(cfg.RootCfg, addr, cfg.NewKey, io)
+	if err != nil {
+		return err
+	}
+	if err := msg.ValidateBasic(); err != nil {
+		return err
+	}
+
+	return makeTx(cfg.RootCfg, args, msg, fee, io)
+}
+
+// signRotateKey returns the msg rotating the key of the account at addr to
+// the key newKey of the keybase, signed by the new key.
+func signRotateKey(cfg *MakeTxCfg, addr crypto.Address, newKey string, io commands.IO) (auth.MsgRotateKey, error) {
+	kb, err := keys.NewKeyBaseFromDir(cfg.RootCfg.Home)
+	if err != nil {
+		return auth.MsgRotateKey{}, err
+	}
+	info, err := kb.GetByNameOrAddress(newKey)
+	if err != nil {
+		return auth.MsgRotateKey{}, errors.Wrap(err, "new key not found in the keybase")
+	}
+	msg := auth.NewMsgRotateKey(addr, info.GetPubKey())
+
+	var pass string
+	if cfg.RootCfg.Quiet {
+		pass, err = io.GetPassword("", cfg.RootCfg.InsecurePasswordStdin)
+	} else {
+		pass, err = io.GetPassword("Enter password of the new key.", cfg.RootCfg.InsecurePasswordStdin)
+	}
+	if err != nil {
+		return auth.MsgRotateKey{}, err
+	}
+
+	msg.Signature, _, err = kb.Sign(newKey, pass, msg.RotationSignBytes())
+	if err != nil {
+		return auth.MsgRotateKey{}, errors.Wrap(err, "unable to sign the rotation with the new key")
+	}
+	return msg, nil
+}
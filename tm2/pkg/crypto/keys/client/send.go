@@ -82,7 +82,10 @@ func execMakeSend(cfg *MakeSendCfg, args []string, io commands.IO) error {
 	if err != nil {
 		return err
 	}
-	fromAddr := info.GetAddress()
+	fromAddr, err := cfg.RootCfg.AccountAddress(info)
+	if err != nil {
+		return err
+	}
 	// info.GetPubKey()
 
 	// Parse to address.
@@ -98,10 +101,9 @@ func execMakeSend(cfg *MakeSendCfg, args []string, io commands.IO) error {
 	}
 
 	// parse gas wanted & fee.
-	gaswanted := cfg.RootCfg.GasWanted
-	gasfee, err := std.ParseCoin(cfg.RootCfg.GasFee)
+	fee, err := cfg.RootCfg.ParseFee()
 	if err != nil {
-		return errors.Wrap(err, "parsing gas fee coin")
+		return err
 	}
 
 	// construct msg & tx and marshal.
@@ -111,10 +113,11 @@ func execMakeSend(cfg *MakeSendCfg, args []string, io commands.IO) error {
 		Amount:      send,
 	}
 	tx := std.Tx{
-		Msgs:       []std.Msg{msg},
-		Fee:        std.NewFee(gaswanted, gasfee),
-		Signatures: nil,
-		Memo:       cfg.RootCfg.Memo,
+		Msgs:          []std.Msg{msg},
+		Fee:           fee,
+		Signatures:    nil,
+		Memo:          cfg.RootCfg.Memo,
+		TimeoutHeight: cfg.RootCfg.TimeoutHeight,
 	}
 
 	if cfg.RootCfg.Broadcast {
@@ -0,0 +1,175 @@
+package iavl
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrNotEmpty is returned when importing into a tree that already has versions.
+	ErrNotEmpty = errors.New("tree must be empty")
+
+	errInvalidExportNode = errors.New("invalid export node")
+)
+
+// importBatchSize is the number of imported nodes after which the
+// pending node writes are flushed to the database.
+const importBatchSize = 10000
+
+// ExportNode is a single node of an exported tree.
+//
+// Nodes are exported in post-order (both children before their parent),
+// which allows the importer to rebuild the exact same tree structure, and
+// thus the same node hashes. Inner nodes have an empty Value.
+type ExportNode struct {
+	Key     []byte
+	Value   []byte
+	Version int64
+	Height  int8
+}
+
+// Export traverses the tree in post-order, calling fn for every node.
+// The traversal stops at the first error returned by fn.
+func (t *ImmutableTree) Export(fn func(*ExportNode) error) error {
+	if t.root == nil {
+		return nil
+	}
+
+	return t.exportNode(t.root, fn)
+}
+
+func (t *ImmutableTree) exportNode(node *Node, fn func(*ExportNode) error) error {
+	if !node.isLeaf() {
+		if err := t.exportNode(node.getLeftNode(t), fn); err != nil {
+			return err
+		}
+
+		if err := t.exportNode(node.getRightNode(t), fn); err != nil {
+			return err
+		}
+	}
+
+	return fn(&ExportNode{
+		Key:     node.key,
+		Value:   node.value,
+		Version: node.version,
+		Height:  node.height,
+	})
+}
+
+// Importer rebuilds a tree version out of the nodes produced by Export.
+// Nodes must be added in the exported order, and the import is finalized
+// with Commit.
+type Importer struct {
+	tree    *MutableTree
+	version int64
+
+	// Nodes whose parent was not added yet
+	stack   []*Node
+	pending int
+}
+
+// Import returns an importer for the given tree version.
+// The tree must be empty.
+func (tree *MutableTree) Import(version int64) (*Importer, error) {
+	if version <= 0 {
+		return nil, fmt.Errorf("invalid import version %d", version)
+	}
+
+	if tree.ndb.getLatestVersion() > 0 {
+		return nil, ErrNotEmpty
+	}
+
+	return &Importer{
+		tree:    tree,
+		version: version,
+	}, nil
+}
+
+// Add adds the next exported node to the tree
+func (i *Importer) Add(exported *ExportNode) error {
+	if exported == nil {
+		return fmt.Errorf("%w: nil node", errInvalidExportNode)
+	}
+
+	if exported.Version > i.version {
+		return fmt.Errorf(
+			"%w: node version %d is greater than the import version %d",
+			errInvalidExportNode, exported.Version, i.version,
+		)
+	}
+
+	node := &Node{
+		key:     exported.Key,
+		value:   exported.Value,
+		version: exported.Version,
+		height:  exported.Height,
+		size:    1,
+	}
+
+	switch {
+	case exported.Height < 0:
+		return fmt.Errorf("%w: negative height", errInvalidExportNode)
+	case exported.Height == 0:
+		// Leaf node
+	default:
+		if len(exported.Value) != 0 {
+			return fmt.Errorf("%w: inner node with value", errInvalidExportNode)
+		}
+
+		if len(i.stack) < 2 {
+			return fmt.Errorf("%w: inner node without children", errInvalidExportNode)
+		}
+
+		left, right := i.stack[len(i.stack)-2], i.stack[len(i.stack)-1]
+		if left.height >= node.height || right.height >= node.height {
+			return fmt.Errorf("%w: inner node height %d is not above its children", errInvalidExportNode, node.height)
+		}
+
+		i.stack = i.stack[:len(i.stack)-2]
+
+		node.leftHash = left.hash
+		node.rightHash = right.hash
+		node.size = left.size + right.size
+	}
+
+	node._hash()
+	i.tree.ndb.SaveNode(node)
+
+	i.stack = append(i.stack, node)
+
+	i.pending++
+	if i.pending >= importBatchSize {
+		i.tree.ndb.Commit()
+		i.pending = 0
+	}
+
+	return nil
+}
+
+// Commit saves the imported tree version, and loads it
+func (i *Importer) Commit() error {
+	ndb := i.tree.ndb
+
+	// The imported version doesn't follow any existing one
+	ndb.resetLatestVersion(i.version - 1)
+
+	switch len(i.stack) {
+	case 0:
+		if err := ndb.SaveEmptyRoot(i.version); err != nil {
+			return err
+		}
+	case 1:
+		if err := ndb.SaveRoot(i.stack[0], i.version); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("%w: %d nodes without a parent", errInvalidExportNode, len(i.stack))
+	}
+
+	ndb.Commit()
+
+	_, err := i.tree.LoadVersion(i.version)
+
+	return err
+}
@@ -0,0 +1,147 @@
+package iavl
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/gno/tm2/pkg/db/memdb"
+)
+
+// exportTree exports the given tree version
+func exportTree(t *testing.T, tree *MutableTree, version int64) []*ExportNode {
+	t.Helper()
+
+	itree, err := tree.GetImmutable(version)
+	require.NoError(t, err)
+
+	var nodes []*ExportNode
+
+	require.NoError(t, itree.Export(func(node *ExportNode) error {
+		nodes = append(nodes, node)
+
+		return nil
+	}))
+
+	return nodes
+}
+
+func TestExportImport(t *testing.T) {
+	t.Parallel()
+
+	tree := NewMutableTree(memdb.NewMemDB(), 0)
+
+	// Build a few versions, with updates and removals,
+	// so the nodes are spread across versions
+	for version := 0; version < 5; version++ {
+		for i := 0; i < 50; i++ {
+			tree.Set([]byte(fmt.Sprintf("key-%03d", i*(version+1))), []byte(fmt.Sprintf("value-%d", version)))
+		}
+
+		tree.Remove([]byte(fmt.Sprintf("key-%03d", version)))
+
+		_, _, err := tree.SaveVersion()
+		require.NoError(t, err)
+	}
+
+	const version = int64(4)
+
+	nodes := exportTree(t, tree, version)
+	require.NotEmpty(t, nodes)
+
+	imported := NewMutableTree(memdb.NewMemDB(), 0)
+
+	importer, err := imported.Import(version)
+	require.NoError(t, err)
+
+	for _, node := range nodes {
+		require.NoError(t, importer.Add(node))
+	}
+
+	require.NoError(t, importer.Commit())
+
+	original, err := tree.GetImmutable(version)
+	require.NoError(t, err)
+
+	assert.Equal(t, version, imported.Version())
+	assert.Equal(t, original.Hash(), imported.Hash())
+	assert.Equal(t, original.Size(), imported.Size())
+
+	original.Iterate(func(key, value []byte) bool {
+		_, importedValue := imported.Get(key)
+		assert.Equal(t, value, importedValue)
+
+		return false
+	})
+
+	// The imported tree can be further updated
+	imported.Set([]byte("new"), []byte("value"))
+
+	_, newVersion, err := imported.SaveVersion()
+	require.NoError(t, err)
+	assert.Equal(t, version+1, newVersion)
+}
+
+func TestImport_Empty(t *testing.T) {
+	t.Parallel()
+
+	tree := NewMutableTree(memdb.NewMemDB(), 0)
+
+	importer, err := tree.Import(3)
+	require.NoError(t, err)
+	require.NoError(t, importer.Commit())
+
+	assert.Equal(t, int64(3), tree.Version())
+	assert.True(t, tree.IsEmpty())
+}
+
+func TestImport_Invalid(t *testing.T) {
+	t.Parallel()
+
+	t.Run("non-empty tree", func(t *testing.T) {
+		t.Parallel()
+
+		tree := NewMutableTree(memdb.NewMemDB(), 0)
+		tree.Set([]byte("key"), []byte("value"))
+
+		_, _, err := tree.SaveVersion()
+		require.NoError(t, err)
+
+		_, err = tree.Import(2)
+		assert.ErrorIs(t, err, ErrNotEmpty)
+	})
+
+	t.Run("inner node without children", func(t *testing.T) {
+		t.Parallel()
+
+		importer, err := NewMutableTree(memdb.NewMemDB(), 0).Import(1)
+		require.NoError(t, err)
+
+		err = importer.Add(&ExportNode{Key: []byte("key"), Version: 1, Height: 1})
+		assert.ErrorIs(t, err, errInvalidExportNode)
+	})
+
+	t.Run("node version above import version", func(t *testing.T) {
+		t.Parallel()
+
+		importer, err := NewMutableTree(memdb.NewMemDB(), 0).Import(1)
+		require.NoError(t, err)
+
+		err = importer.Add(&ExportNode{Key: []byte("key"), Value: []byte("value"), Version: 2})
+		assert.ErrorIs(t, err, errInvalidExportNode)
+	})
+
+	t.Run("dangling nodes", func(t *testing.T) {
+		t.Parallel()
+
+		importer, err := NewMutableTree(memdb.NewMemDB(), 0).Import(1)
+		require.NoError(t, err)
+
+		require.NoError(t, importer.Add(&ExportNode{Key: []byte("a"), Value: []byte("value"), Version: 1}))
+		require.NoError(t, importer.Add(&ExportNode{Key: []byte("b"), Value: []byte("value"), Version: 1}))
+
+		assert.ErrorIs(t, importer.Commit(), errInvalidExportNode)
+	})
+}
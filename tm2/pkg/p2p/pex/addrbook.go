@@ -0,0 +1,523 @@
+package pex
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	osm "github.com/gnolang/gno/tm2/pkg/os"
+	"github.com/gnolang/gno/tm2/pkg/p2p"
+	"github.com/gnolang/gno/tm2/pkg/random"
+	"github.com/gnolang/gno/tm2/pkg/service"
+)
+
+const (
+	// maxAddresses is the maximum number of addresses kept in the book
+	maxAddresses = 2000
+
+	// needAddressThreshold is the number of addresses under which
+	// the book needs more addresses from peers
+	needAddressThreshold = 100
+
+	// maxNewAttempts is the number of failed dial attempts after which
+	// an address that was never dialed successfully is considered bad
+	maxNewAttempts = 3
+
+	// maxOldAttempts is the number of consecutive failed dial attempts after which
+	// an address that was dialed successfully before is considered bad
+	maxOldAttempts = 10
+
+	// baseDialBackoff is the minimum time between two dial attempts,
+	// doubled with each consecutive failed attempt, up to maxDialBackoff
+	baseDialBackoff = 5 * time.Second
+	maxDialBackoff  = 30 * time.Minute
+
+	// minGetSelection, maxGetSelection and getSelectionPercent bound
+	// the number of addresses shared with peers
+	minGetSelection     = 32
+	maxGetSelection     = 250
+	getSelectionPercent = 23
+
+	// saveInterval is the interval at which the book is persisted
+	saveInterval = 2 * time.Minute
+)
+
+var (
+	errNonRoutable    = errors.New("address is not routable")
+	errOwnAddress     = errors.New("address is our own")
+	errPrivateAddress = errors.New("address is private")
+	errBannedAddress  = errors.New("address is banned")
+	errBookFull       = errors.New("address book is full")
+)
+
+// knownAddress is an address tracked by the address book
+type knownAddress struct {
+	Addr        *p2p.NetAddress `json:"addr"`
+	Src         *p2p.NetAddress `json:"src"`
+	Attempts    int32           `json:"attempts"`
+	LastAttempt time.Time       `json:"last_attempt"`
+	LastSuccess time.Time       `json:"last_success"`
+	BannedUntil time.Time       `json:"banned_until"`
+}
+
+// isOld returns true if the address was dialed successfully before
+func (ka *knownAddress) isOld() bool {
+	return !ka.LastSuccess.IsZero()
+}
+
+// isBanned returns true if the address is banned at the given time
+func (ka *knownAddress) isBanned(now time.Time) bool {
+	return now.Before(ka.BannedUntil)
+}
+
+// isBad returns true if the address failed too many dial attempts
+func (ka *knownAddress) isBad() bool {
+	if ka.isOld() {
+		return ka.Attempts >= maxOldAttempts
+	}
+
+	return ka.Attempts >= maxNewAttempts
+}
+
+// isDialable returns true if the address is not banned,
+// and the dial backoff since the last attempt elapsed
+func (ka *knownAddress) isDialable(now time.Time) bool {
+	if ka.isBanned(now) {
+		return false
+	}
+
+	return ka.Attempts == 0 || now.Sub(ka.LastAttempt) >= ka.backoff()
+}
+
+// backoff returns the minimum time to wait after the last dial attempt
+func (ka *knownAddress) backoff() time.Duration {
+	if ka.Attempts == 0 {
+		return 0
+	}
+
+	return min(baseDialBackoff<<min(ka.Attempts-1, 16), maxDialBackoff)
+}
+
+// addrBookJSON is the persisted form of the address book
+type addrBookJSON struct {
+	Addrs []*knownAddress `json:"addrs"`
+}
+
+// AddrBook is a persistent book of the known peer addresses.
+// It keeps track of the dial attempts and bans of each address,
+// and picks the addresses to dial, favoring the ones that were
+// dialed successfully before
+type AddrBook struct {
+	service.BaseService
+
+	filePath string
+	strict   bool // only routable addresses are added
+
+	mtx        sync.Mutex
+	rng        *random.Rand
+	addrs      map[p2p.ID]*knownAddress
+	ourAddrs   map[string]struct{}
+	privateIDs map[p2p.ID]struct{}
+}
+
+// NewAddrBook creates a new address book, persisted at the given path.
+// If the path is empty, the book is not persisted.
+// In strict mode, only routable addresses are added to the book
+func NewAddrBook(filePath string, strict bool) *AddrBook {
+	b := &AddrBook{
+		filePath:   filePath,
+		strict:     strict,
+		rng:        random.NewRand(),
+		addrs:      make(map[p2p.ID]*knownAddress),
+		ourAddrs:   make(map[string]struct{}),
+		privateIDs: make(map[p2p.ID]struct{}),
+	}
+	b.BaseService = *service.NewBaseService(nil, "AddrBook", b)
+
+	return b
+}
+
+// OnStart implements Service by loading the persisted
+// book, and starting the periodic save routine
+func (b *AddrBook) OnStart() error {
+	if err := b.load(); err != nil {
+		return fmt.Errorf("unable to load address book, %w", err)
+	}
+
+	go b.saveRoutine()
+
+	return nil
+}
+
+// OnStop implements Service by persisting the book
+func (b *AddrBook) OnStop() {
+	if err := b.Save(); err != nil {
+		b.Logger.Error("Unable to save address book", "err", err)
+	}
+}
+
+// saveRoutine periodically persists the book, until it's stopped
+func (b *AddrBook) saveRoutine() {
+	ticker := time.NewTicker(saveInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := b.Save(); err != nil {
+				b.Logger.Error("Unable to save address book", "err", err)
+			}
+		case <-b.Quit():
+			return
+		}
+	}
+}
+
+// AddOurAddress adds one of our own addresses,
+// which is never added to the book, nor dialed
+func (b *AddrBook) AddOurAddress(addr *p2p.NetAddress) {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	b.ourAddrs[addr.String()] = struct{}{}
+}
+
+// OurAddress returns true if the address is one of our own addresses
+func (b *AddrBook) OurAddress(addr *p2p.NetAddress) bool {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	_, ok := b.ourAddrs[addr.String()]
+
+	return ok
+}
+
+// AddPrivateIDs adds the IDs of the private peers,
+// whose addresses are never added to the book, nor shared with peers
+func (b *AddrBook) AddPrivateIDs(ids []string) {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	for _, id := range ids {
+		b.privateIDs[p2p.ID(id)] = struct{}{}
+	}
+}
+
+// AddAddress adds the address learned from the given source to the book.
+// Known addresses are left untouched. If the book is full, the worst
+// address that was never dialed successfully is evicted
+func (b *AddrBook) AddAddress(addr, src *p2p.NetAddress) error {
+	if addr == nil {
+		return errNilAddress
+	}
+
+	if err := addr.Validate(); err != nil {
+		return fmt.Errorf("invalid address %s, %w", addr, err)
+	}
+
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	if b.strict && !addr.Routable() {
+		return fmt.Errorf("%w, %s", errNonRoutable, addr)
+	}
+
+	if _, ok := b.ourAddrs[addr.String()]; ok {
+		return fmt.Errorf("%w, %s", errOwnAddress, addr)
+	}
+
+	if _, ok := b.privateIDs[addr.ID]; ok {
+		return fmt.Errorf("%w, %s", errPrivateAddress, addr)
+	}
+
+	if ka, ok := b.addrs[addr.ID]; ok {
+		if ka.isBanned(time.Now()) {
+			return fmt.Errorf("%w, %s", errBannedAddress, addr)
+		}
+
+		return nil
+	}
+
+	if len(b.addrs) >= maxAddresses && !b.evict() {
+		return errBookFull
+	}
+
+	b.addrs[addr.ID] = &knownAddress{
+		Addr: addr,
+		Src:  src,
+	}
+
+	return nil
+}
+
+// evict removes the worst address that was never dialed successfully,
+// and returns true if an address was removed.
+// CONTRACT: the caller holds the lock
+func (b *AddrBook) evict() bool {
+	var (
+		now   = time.Now()
+		worst *knownAddress
+	)
+
+	for _, ka := range b.addrs {
+		if ka.isOld() || ka.isBanned(now) {
+			continue
+		}
+
+		if worst == nil ||
+			ka.Attempts > worst.Attempts ||
+			(ka.Attempts == worst.Attempts && ka.LastAttempt.Before(worst.LastAttempt)) {
+			worst = ka
+		}
+	}
+
+	if worst == nil {
+		return false
+	}
+
+	delete(b.addrs, worst.Addr.ID)
+
+	return true
+}
+
+// RemoveAddress removes the address from the book
+func (b *AddrBook) RemoveAddress(addr *p2p.NetAddress) {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	delete(b.addrs, addr.ID)
+}
+
+// HasAddress returns true if the address is in the book
+func (b *AddrBook) HasAddress(addr *p2p.NetAddress) bool {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	ka, ok := b.addrs[addr.ID]
+
+	return ok && ka.Addr.Equals(addr)
+}
+
+// MarkAttempt marks a dial attempt of the address.
+// Addresses that failed too many attempts are removed
+func (b *AddrBook) MarkAttempt(addr *p2p.NetAddress) {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	ka, ok := b.addrs[addr.ID]
+	if !ok {
+		return
+	}
+
+	now := time.Now()
+
+	ka.Attempts++
+	ka.LastAttempt = now
+
+	if ka.isBad() && !ka.isBanned(now) {
+		delete(b.addrs, addr.ID)
+	}
+}
+
+// MarkGood marks a successful connection with the peer
+func (b *AddrBook) MarkGood(id p2p.ID) {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	ka, ok := b.addrs[id]
+	if !ok {
+		return
+	}
+
+	ka.Attempts = 0
+	ka.LastSuccess = time.Now()
+}
+
+// MarkBad bans the address for the given duration.
+// The address is added to the book if it's unknown, to remember the ban
+func (b *AddrBook) MarkBad(addr *p2p.NetAddress, banTime time.Duration) {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	ka, ok := b.addrs[addr.ID]
+	if !ok {
+		ka = &knownAddress{Addr: addr}
+		b.addrs[addr.ID] = ka
+	}
+
+	ka.BannedUntil = time.Now().Add(banTime)
+}
+
+// IsBanned returns true if the peer is currently banned
+func (b *AddrBook) IsBanned(id p2p.ID) bool {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	ka, ok := b.addrs[id]
+
+	return ok && ka.isBanned(time.Now())
+}
+
+// PickAddress picks a random address to dial, among the addresses
+// that are not banned, and whose dial backoff elapsed.
+// The bias (between 0 and 100) is the preference for addresses
+// that were never dialed successfully, over the ones that were.
+// Returns nil if there is no address to dial
+func (b *AddrBook) PickAddress(bias int) *p2p.NetAddress {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	bias = max(0, min(bias, 100))
+
+	var (
+		now = time.Now()
+
+		fresh []*knownAddress
+		old   []*knownAddress
+	)
+
+	for _, ka := range b.addrs {
+		if !ka.isDialable(now) {
+			continue
+		}
+
+		if ka.isOld() {
+			old = append(old, ka)
+		} else {
+			fresh = append(fresh, ka)
+		}
+	}
+
+	var candidates []*knownAddress
+
+	switch {
+	case len(fresh) == 0:
+		candidates = old
+	case len(old) == 0:
+		candidates = fresh
+	default:
+		// Weigh each group by its size, and by the bias
+		oldWeight := math.Sqrt(float64(len(old))) * float64(100-bias)
+		newWeight := math.Sqrt(float64(len(fresh))) * float64(bias)
+
+		candidates = old
+		if b.rng.Float64()*(oldWeight+newWeight) < newWeight {
+			candidates = fresh
+		}
+	}
+
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	return candidates[b.rng.Intn(len(candidates))].Addr
+}
+
+// GetSelection returns a random selection of the addresses
+// that are not banned, to be shared with peers
+func (b *AddrBook) GetSelection() []*p2p.NetAddress {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	now := time.Now()
+
+	addrs := make([]*p2p.NetAddress, 0, len(b.addrs))
+	for _, ka := range b.addrs {
+		if ka.isBanned(now) {
+			continue
+		}
+
+		addrs = append(addrs, ka.Addr)
+	}
+
+	size := min(
+		max(len(addrs)*getSelectionPercent/100, minGetSelection),
+		maxGetSelection,
+		len(addrs),
+	)
+
+	selection := make([]*p2p.NetAddress, 0, size)
+	for _, i := range b.rng.Perm(len(addrs))[:size] {
+		selection = append(selection, addrs[i])
+	}
+
+	return selection
+}
+
+// NeedMoreAddrs returns true if the book needs more addresses
+func (b *AddrBook) NeedMoreAddrs() bool {
+	return b.Size() < needAddressThreshold
+}
+
+// Size returns the number of addresses in the book
+func (b *AddrBook) Size() int {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	return len(b.addrs)
+}
+
+// Empty returns true if the book has no address
+func (b *AddrBook) Empty() bool {
+	return b.Size() == 0
+}
+
+// Save persists the book to its file
+func (b *AddrBook) Save() error {
+	if b.filePath == "" {
+		return nil
+	}
+
+	b.mtx.Lock()
+
+	aJSON := addrBookJSON{
+		Addrs: make([]*knownAddress, 0, len(b.addrs)),
+	}
+
+	for _, ka := range b.addrs {
+		kaCopy := *ka
+		aJSON.Addrs = append(aJSON.Addrs, &kaCopy)
+	}
+
+	b.mtx.Unlock()
+
+	jsonBytes, err := amino.MarshalJSONIndent(aJSON, "", "  ")
+	if err != nil {
+		return fmt.Errorf("unable to marshal address book, %w", err)
+	}
+
+	return osm.WriteFileAtomic(b.filePath, jsonBytes, 0o644)
+}
+
+// load loads the persisted book, if any
+func (b *AddrBook) load() error {
+	if b.filePath == "" || !osm.FileExists(b.filePath) {
+		return nil
+	}
+
+	jsonBytes, err := osm.ReadFile(b.filePath)
+	if err != nil {
+		return err
+	}
+
+	var aJSON addrBookJSON
+	if err := amino.UnmarshalJSON(jsonBytes, &aJSON); err != nil {
+		return fmt.Errorf("unable to unmarshal address book, %w", err)
+	}
+
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	for _, ka := range aJSON.Addrs {
+		if ka.Addr == nil {
+			continue
+		}
+
+		b.addrs[ka.Addr.ID] = ka
+	}
+
+	return nil
+}
@@ -0,0 +1,336 @@
+package pex
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/gno/tm2/pkg/crypto/ed25519"
+	"github.com/gnolang/gno/tm2/pkg/p2p"
+)
+
+// newTestAddr creates a new random routable address
+func newTestAddr(t *testing.T) *p2p.NetAddress {
+	t.Helper()
+
+	_, addr := p2p.CreateRoutableAddr()
+
+	return addr
+}
+
+// newLocalAddr creates a new random non-routable address
+func newLocalAddr(t *testing.T) *p2p.NetAddress {
+	t.Helper()
+
+	id := ed25519.GenPrivKey().PubKey().Address().ID()
+
+	return p2p.NewNetAddressFromIPPort(id, net.ParseIP("127.0.0.1"), 26656)
+}
+
+func TestAddrBook_AddAddress(t *testing.T) {
+	t.Parallel()
+
+	t.Run("valid address", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			b    = NewAddrBook("", true)
+			addr = newTestAddr(t)
+			src  = newTestAddr(t)
+		)
+
+		require.NoError(t, b.AddAddress(addr, src))
+		assert.True(t, b.HasAddress(addr))
+		assert.Equal(t, 1, b.Size())
+
+		// Known addresses are left untouched
+		require.NoError(t, b.AddAddress(addr, newTestAddr(t)))
+		assert.Equal(t, 1, b.Size())
+	})
+
+	t.Run("non routable address", func(t *testing.T) {
+		t.Parallel()
+
+		addr := newLocalAddr(t)
+
+		assert.ErrorIs(t, NewAddrBook("", true).AddAddress(addr, addr), errNonRoutable)
+		assert.NoError(t, NewAddrBook("", false).AddAddress(addr, addr))
+	})
+
+	t.Run("own address", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			b    = NewAddrBook("", true)
+			addr = newTestAddr(t)
+		)
+
+		b.AddOurAddress(addr)
+
+		assert.True(t, b.OurAddress(addr))
+		assert.ErrorIs(t, b.AddAddress(addr, addr), errOwnAddress)
+	})
+
+	t.Run("private address", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			b    = NewAddrBook("", true)
+			addr = newTestAddr(t)
+		)
+
+		b.AddPrivateIDs([]string{addr.ID.String()})
+
+		assert.ErrorIs(t, b.AddAddress(addr, addr), errPrivateAddress)
+	})
+
+	t.Run("banned address", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			b    = NewAddrBook("", true)
+			addr = newTestAddr(t)
+		)
+
+		b.MarkBad(addr, time.Hour)
+
+		assert.True(t, b.IsBanned(addr.ID))
+		assert.ErrorIs(t, b.AddAddress(addr, addr), errBannedAddress)
+	})
+
+	t.Run("invalid address", func(t *testing.T) {
+		t.Parallel()
+
+		b := NewAddrBook("", false)
+
+		assert.ErrorIs(t, b.AddAddress(nil, nil), errNilAddress)
+		assert.Error(t, b.AddAddress(&p2p.NetAddress{}, nil))
+	})
+}
+
+func TestAddrBook_Full(t *testing.T) {
+	t.Parallel()
+
+	b := NewAddrBook("", true)
+
+	addrs := make([]*p2p.NetAddress, 0, maxAddresses)
+	for i := 0; i < maxAddresses; i++ {
+		addr := newTestAddr(t)
+		require.NoError(t, b.AddAddress(addr, addr))
+
+		addrs = append(addrs, addr)
+	}
+
+	// The address with the most failed attempts is evicted
+	b.MarkAttempt(addrs[0])
+
+	extra := newTestAddr(t)
+	require.NoError(t, b.AddAddress(extra, extra))
+
+	assert.Equal(t, maxAddresses, b.Size())
+	assert.False(t, b.HasAddress(addrs[0]))
+	assert.True(t, b.HasAddress(extra))
+
+	// Addresses that were dialed successfully are never evicted
+	for _, addr := range append(addrs[1:], extra) {
+		b.MarkGood(addr.ID)
+	}
+
+	assert.ErrorIs(t, b.AddAddress(newTestAddr(t), nil), errBookFull)
+}
+
+func TestAddrBook_MarkAttempt(t *testing.T) {
+	t.Parallel()
+
+	var (
+		b     = NewAddrBook("", true)
+		fresh = newTestAddr(t)
+		old   = newTestAddr(t)
+	)
+
+	require.NoError(t, b.AddAddress(fresh, nil))
+	require.NoError(t, b.AddAddress(old, nil))
+
+	b.MarkGood(old.ID)
+
+	// The dial backoff applies after a failed attempt
+	b.MarkAttempt(fresh)
+	b.MarkAttempt(old)
+
+	assert.Nil(t, b.PickAddress(50))
+
+	// Addresses never dialed successfully are removed sooner
+	for i := 1; i < maxNewAttempts; i++ {
+		b.MarkAttempt(fresh)
+		b.MarkAttempt(old)
+	}
+
+	assert.False(t, b.HasAddress(fresh))
+	assert.True(t, b.HasAddress(old))
+
+	// A successful connection resets the attempts
+	b.MarkGood(old.ID)
+
+	assert.Equal(t, old, b.PickAddress(50))
+
+	for i := 0; i < maxOldAttempts; i++ {
+		b.MarkAttempt(old)
+	}
+
+	assert.True(t, b.Empty())
+}
+
+func TestAddrBook_Backoff(t *testing.T) {
+	t.Parallel()
+
+	ka := &knownAddress{}
+	assert.Equal(t, time.Duration(0), ka.backoff())
+
+	ka.Attempts = 1
+	assert.Equal(t, baseDialBackoff, ka.backoff())
+
+	ka.Attempts = 3
+	assert.Equal(t, 4*baseDialBackoff, ka.backoff())
+
+	ka.Attempts = 100
+	assert.Equal(t, maxDialBackoff, ka.backoff())
+
+	now := time.Now()
+
+	ka.Attempts = 2
+	ka.LastAttempt = now
+
+	assert.False(t, ka.isDialable(now))
+	assert.True(t, ka.isDialable(now.Add(2*baseDialBackoff)))
+}
+
+func TestAddrBook_MarkBad(t *testing.T) {
+	t.Parallel()
+
+	var (
+		b      = NewAddrBook("", true)
+		banned = newTestAddr(t)
+		good   = newTestAddr(t)
+	)
+
+	require.NoError(t, b.AddAddress(banned, nil))
+	require.NoError(t, b.AddAddress(good, nil))
+
+	b.MarkBad(banned, 100*time.Millisecond)
+
+	assert.True(t, b.IsBanned(banned.ID))
+	assert.False(t, b.IsBanned(good.ID))
+
+	// Banned addresses are neither dialed, nor shared
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, good, b.PickAddress(50))
+	}
+
+	assert.Equal(t, []*p2p.NetAddress{good}, b.GetSelection())
+
+	// The ban expires
+	assert.Eventually(t, func() bool {
+		return !b.IsBanned(banned.ID)
+	}, time.Second, 10*time.Millisecond)
+
+	assert.Len(t, b.GetSelection(), 2)
+}
+
+func TestAddrBook_PickAddress(t *testing.T) {
+	t.Parallel()
+
+	var (
+		b     = NewAddrBook("", true)
+		fresh = newTestAddr(t)
+		old   = newTestAddr(t)
+	)
+
+	assert.Nil(t, b.PickAddress(50))
+
+	require.NoError(t, b.AddAddress(fresh, nil))
+	require.NoError(t, b.AddAddress(old, nil))
+
+	b.MarkGood(old.ID)
+
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, fresh, b.PickAddress(100))
+		assert.Equal(t, old, b.PickAddress(0))
+	}
+}
+
+func TestAddrBook_GetSelection(t *testing.T) {
+	t.Parallel()
+
+	b := NewAddrBook("", true)
+
+	assert.Empty(t, b.GetSelection())
+
+	for i := 0; i < minGetSelection/2; i++ {
+		require.NoError(t, b.AddAddress(newTestAddr(t), nil))
+	}
+
+	assert.Len(t, b.GetSelection(), minGetSelection/2)
+
+	for i := 0; i < maxAddresses; i++ {
+		require.NoError(t, b.AddAddress(newTestAddr(t), nil))
+	}
+
+	selection := b.GetSelection()
+	require.Len(t, selection, maxGetSelection)
+
+	seen := make(map[p2p.ID]struct{}, len(selection))
+	for _, addr := range selection {
+		seen[addr.ID] = struct{}{}
+	}
+
+	assert.Len(t, seen, len(selection))
+}
+
+func TestAddrBook_SaveLoad(t *testing.T) {
+	t.Parallel()
+
+	var (
+		filePath = filepath.Join(t.TempDir(), "addrbook.json")
+
+		good   = newTestAddr(t)
+		fresh  = newTestAddr(t)
+		banned = newTestAddr(t)
+	)
+
+	b := NewAddrBook(filePath, true)
+	require.NoError(t, b.Start())
+
+	require.NoError(t, b.AddAddress(good, fresh))
+	require.NoError(t, b.AddAddress(fresh, good))
+
+	b.MarkGood(good.ID)
+	b.MarkBad(banned, time.Hour)
+
+	require.NoError(t, b.Stop())
+
+	loaded := NewAddrBook(filePath, true)
+	require.NoError(t, loaded.Start())
+	defer loaded.Stop()
+
+	assert.Equal(t, 3, loaded.Size())
+	assert.True(t, loaded.HasAddress(good))
+	assert.True(t, loaded.HasAddress(fresh))
+	assert.True(t, loaded.IsBanned(banned.ID))
+
+	assert.Equal(t, good, loaded.PickAddress(0))
+	assert.Equal(t, fresh.String(), loaded.addrs[good.ID].Src.String())
+}
+
+func TestAddrBook_LoadInvalid(t *testing.T) {
+	t.Parallel()
+
+	filePath := filepath.Join(t.TempDir(), "addrbook.json")
+	require.NoError(t, os.WriteFile(filePath, []byte("{invalid"), 0o644))
+
+	assert.Error(t, NewAddrBook(filePath, true).Start())
+}
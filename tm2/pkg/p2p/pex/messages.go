@@ -0,0 +1,77 @@
+package pex
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	"github.com/gnolang/gno/tm2/pkg/p2p"
+)
+
+const (
+	// maxAddressSize is the maximum size of an encoded address
+	maxAddressSize = 256
+
+	// maxMsgSize is the maximum size of a pex message
+	maxMsgSize = maxAddressSize * maxGetSelection
+)
+
+var errNilAddress = errors.New("nil address")
+
+// PexMessage is a generic message for this reactor.
+type PexMessage interface {
+	ValidateBasic() error
+}
+
+func decodeMsg(bz []byte) (msg PexMessage, err error) {
+	if len(bz) > maxMsgSize {
+		return msg, fmt.Errorf("msg exceeds max size (%d > %d)", len(bz), maxMsgSize)
+	}
+	err = amino.Unmarshal(bz, &msg)
+	return
+}
+
+// -------------------------------------
+
+// pexRequestMessage requests the known addresses of a peer
+type pexRequestMessage struct{}
+
+// ValidateBasic performs basic validation.
+func (m *pexRequestMessage) ValidateBasic() error {
+	return nil
+}
+
+func (m *pexRequestMessage) String() string {
+	return "[pexRequestMessage]"
+}
+
+// -------------------------------------
+
+// pexAddrsMessage is the response to a pexRequestMessage,
+// and contains a selection of the known addresses of a peer
+type pexAddrsMessage struct {
+	Addrs []*p2p.NetAddress
+}
+
+// ValidateBasic performs basic validation.
+func (m *pexAddrsMessage) ValidateBasic() error {
+	if len(m.Addrs) > maxGetSelection {
+		return fmt.Errorf("too many addresses (%d > %d)", len(m.Addrs), maxGetSelection)
+	}
+
+	for i, addr := range m.Addrs {
+		if addr == nil {
+			return fmt.Errorf("%w at index %d", errNilAddress, i)
+		}
+
+		if err := addr.Validate(); err != nil {
+			return fmt.Errorf("invalid address at index %d, %w", i, err)
+		}
+	}
+
+	return nil
+}
+
+func (m *pexAddrsMessage) String() string {
+	return fmt.Sprintf("[pexAddrsMessage %v]", m.Addrs)
+}
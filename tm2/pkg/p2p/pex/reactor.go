@@ -0,0 +1,455 @@
+package pex
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	"github.com/gnolang/gno/tm2/pkg/p2p"
+	"github.com/gnolang/gno/tm2/pkg/p2p/config"
+	"github.com/gnolang/gno/tm2/pkg/random"
+)
+
+const (
+	// PexChannel exchanges peer addresses
+	PexChannel = byte(0x00)
+
+	// ensurePeersPeriod is the interval at which the reactor
+	// dials new peers, if there are not enough outbound peers
+	ensurePeersPeriod = 30 * time.Second
+
+	// minReceiveRequestInterval is the minimum interval between two
+	// address requests of a peer. Faster peers are banned
+	minReceiveRequestInterval = ensurePeersPeriod / 3
+
+	// minSendRequestInterval is the minimum interval between two
+	// address requests sent to a peer, with a margin over the receive limit
+	minSendRequestInterval = 2 * minReceiveRequestInterval
+
+	// banTime is the duration of the ban of misbehaving peers
+	banTime = 24 * time.Hour
+
+	// seedDisconnectWait is the time a seed waits before disconnecting a peer
+	// it sent its addresses to, so the addresses can be flushed
+	seedDisconnectWait = 3 * time.Second
+
+	// biasToSelectNewPeers is the preference for addresses that were
+	// never dialed successfully, when picking addresses to dial
+	biasToSelectNewPeers = 30
+)
+
+var (
+	errUnsolicitedAddrs = errors.New("received unsolicited addresses")
+	errTooManyRequests  = errors.New("received too many address requests")
+)
+
+// Reactor exchanges peer addresses with peers, and keeps
+// the node connected to enough outbound peers by dialing
+// the addresses of the book, or the seeds if the book is empty.
+//
+// In seed mode, the reactor shares its addresses with the
+// peers asking for them, and then disconnects from them
+type Reactor struct {
+	p2p.BaseReactor
+
+	book     *AddrBook
+	seeds    []*p2p.NetAddress
+	seedMode bool
+
+	ensurePeersPeriod time.Duration
+	ensurePeersCh     chan struct{}
+
+	rng *random.Rand
+
+	mtx              sync.Mutex
+	requestsSent     map[p2p.ID]struct{}  // peers awaiting an addresses response
+	lastRequestsSent map[p2p.ID]time.Time // last address request sent to each peer
+	lastRequestsRecv map[p2p.ID]time.Time // last address request of each peer
+}
+
+// NewReactor creates a new peer exchange reactor, using the given address book
+func NewReactor(cfg *config.P2PConfig, book *AddrBook) (*Reactor, error) {
+	seeds, errs := p2p.NewNetAddressFromStrings(splitAndTrimEmpty(cfg.Seeds))
+	for _, err := range errs {
+		// Seeds that can't be resolved now may be resolvable later
+		if _, ok := err.(p2p.NetAddressLookupError); ok {
+			continue
+		}
+
+		return nil, fmt.Errorf("invalid seed address, %w", err)
+	}
+
+	book.AddPrivateIDs(splitAndTrimEmpty(cfg.PrivatePeerIDs))
+
+	r := &Reactor{
+		book:              book,
+		seeds:             seeds,
+		seedMode:          cfg.SeedMode,
+		ensurePeersPeriod: ensurePeersPeriod,
+		ensurePeersCh:     make(chan struct{}, 1),
+		rng:               random.NewRand(),
+		requestsSent:      make(map[p2p.ID]struct{}),
+		lastRequestsSent:  make(map[p2p.ID]time.Time),
+		lastRequestsRecv:  make(map[p2p.ID]time.Time),
+	}
+	r.BaseReactor = *p2p.NewBaseReactor("PexReactor", r)
+
+	return r, nil
+}
+
+// OnStart implements Service by starting the
+// address book, and the peer dialing routine
+func (r *Reactor) OnStart() error {
+	if err := r.book.Start(); err != nil {
+		return err
+	}
+
+	go r.ensurePeersRoutine()
+
+	return nil
+}
+
+// OnStop implements Service by stopping the address book
+func (r *Reactor) OnStop() {
+	r.book.Stop()
+}
+
+// GetChannels implements Reactor
+func (r *Reactor) GetChannels() []*p2p.ChannelDescriptor {
+	return []*p2p.ChannelDescriptor{
+		{
+			ID:                  PexChannel,
+			Priority:            1,
+			SendQueueCapacity:   10,
+			RecvMessageCapacity: maxMsgSize,
+		},
+	}
+}
+
+// AddPeer implements Reactor. Outbound peers are marked good,
+// and asked for their addresses if the book needs more.
+// The self-reported addresses of inbound peers are added to the book
+func (r *Reactor) AddPeer(peer p2p.Peer) {
+	if peer.IsOutbound() {
+		r.book.MarkGood(peer.ID())
+
+		if r.book.NeedMoreAddrs() {
+			r.requestAddrs(peer)
+		}
+
+		return
+	}
+
+	addr := peer.NodeInfo().NetAddress
+	if err := r.book.AddAddress(addr, addr); err != nil {
+		r.Logger.Debug("Unable to add inbound peer address", "addr", addr, "err", err)
+	}
+}
+
+// RemovePeer implements Reactor
+func (r *Reactor) RemovePeer(peer p2p.Peer, _ interface{}) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	delete(r.requestsSent, peer.ID())
+	delete(r.lastRequestsSent, peer.ID())
+	delete(r.lastRequestsRecv, peer.ID())
+}
+
+// Receive implements Reactor
+func (r *Reactor) Receive(chID byte, src p2p.Peer, msgBytes []byte) {
+	if !r.IsRunning() {
+		return
+	}
+
+	msg, err := decodeMsg(msgBytes)
+	if err != nil {
+		r.Logger.Error("Error decoding message", "src", src, "chId", chID, "msg", msg, "err", err, "bytes", msgBytes)
+		r.Switch.StopPeerForError(src, err)
+
+		return
+	}
+
+	if err = msg.ValidateBasic(); err != nil {
+		r.Logger.Error("Invalid message", "peer", src, "msg", msg, "err", err)
+		r.Switch.StopPeerForError(src, err)
+
+		return
+	}
+
+	switch msg := msg.(type) {
+	case *pexRequestMessage:
+		if err := r.receiveRequest(src); err != nil {
+			r.banPeer(src, err)
+
+			return
+		}
+
+		src.Send(PexChannel, amino.MustMarshalAny(&pexAddrsMessage{
+			Addrs: r.book.GetSelection(),
+		}))
+
+		// Seeds only share their addresses with inbound peers
+		if r.seedMode && !src.IsOutbound() {
+			go r.disconnectAfter(src, seedDisconnectWait)
+		}
+	case *pexAddrsMessage:
+		if err := r.receiveAddrs(src, msg.Addrs); err != nil {
+			r.banPeer(src, err)
+
+			return
+		}
+
+		// Seeds only crawl their outbound peers for addresses
+		if r.seedMode && src.IsOutbound() {
+			r.Switch.StopPeerGracefully(src)
+		}
+	default:
+		r.Logger.Error(fmt.Sprintf("Unknown message type %T", msg))
+	}
+}
+
+// receiveRequest checks the peer is not requesting addresses too often
+func (r *Reactor) receiveRequest(src p2p.Peer) error {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	now := time.Now()
+
+	last, ok := r.lastRequestsRecv[src.ID()]
+	if ok && now.Sub(last) < minReceiveRequestInterval {
+		return fmt.Errorf(
+			"%w, last request %s ago, minimum interval is %s",
+			errTooManyRequests,
+			now.Sub(last),
+			minReceiveRequestInterval,
+		)
+	}
+
+	r.lastRequestsRecv[src.ID()] = now
+
+	return nil
+}
+
+// receiveAddrs adds the addresses sent by the peer to the book,
+// if they were requested
+func (r *Reactor) receiveAddrs(src p2p.Peer, addrs []*p2p.NetAddress) error {
+	r.mtx.Lock()
+	_, requested := r.requestsSent[src.ID()]
+	delete(r.requestsSent, src.ID())
+	r.mtx.Unlock()
+
+	if !requested {
+		return errUnsolicitedAddrs
+	}
+
+	srcAddr := peerAddress(src)
+
+	for _, addr := range addrs {
+		if err := r.book.AddAddress(addr, srcAddr); err != nil {
+			r.Logger.Debug("Unable to add address", "addr", addr, "src", srcAddr, "err", err)
+		}
+	}
+
+	// Dial the new addresses right away
+	select {
+	case r.ensurePeersCh <- struct{}{}:
+	default:
+	}
+
+	return nil
+}
+
+// requestAddrs asks the peer for its addresses, if there is
+// no pending request for the peer, and it wasn't asked recently
+func (r *Reactor) requestAddrs(peer p2p.Peer) {
+	r.mtx.Lock()
+
+	now := time.Now()
+
+	_, pending := r.requestsSent[peer.ID()]
+	last, ok := r.lastRequestsSent[peer.ID()]
+
+	if pending || ok && now.Sub(last) < minSendRequestInterval {
+		r.mtx.Unlock()
+
+		return
+	}
+
+	r.requestsSent[peer.ID()] = struct{}{}
+	r.lastRequestsSent[peer.ID()] = now
+	r.mtx.Unlock()
+
+	peer.Send(PexChannel, amino.MustMarshalAny(&pexRequestMessage{}))
+}
+
+// banPeer bans the misbehaving peer, and disconnects from it.
+// Persistent peers are only disconnected
+func (r *Reactor) banPeer(peer p2p.Peer, reason error) {
+	if !peer.IsPersistent() {
+		r.book.MarkBad(peerAddress(peer), banTime)
+	}
+
+	r.Switch.StopPeerForError(peer, reason)
+}
+
+// disconnectAfter gracefully disconnects from the peer after the given wait
+func (r *Reactor) disconnectAfter(peer p2p.Peer, wait time.Duration) {
+	select {
+	case <-time.After(wait):
+		r.Switch.StopPeerGracefully(peer)
+	case <-r.Quit():
+	}
+}
+
+// ensurePeersRoutine periodically makes sure there are
+// enough outbound peers, until the reactor is stopped
+func (r *Reactor) ensurePeersRoutine() {
+	r.ensurePeers()
+
+	ticker := time.NewTicker(r.ensurePeersPeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-r.ensurePeersCh:
+		case <-r.Quit():
+			return
+		}
+
+		r.ensurePeers()
+	}
+}
+
+// ensurePeers dials addresses from the book, up to the maximum number
+// of outbound peers. If the book needs more addresses, a random peer is
+// asked for its addresses, and the seeds are dialed when no peer is known
+func (r *Reactor) ensurePeers() {
+	out, in, dialing := r.Switch.NumPeers()
+
+	numToDial := r.Switch.MaxNumOutboundPeers() - (out + dialing)
+
+	r.Logger.Debug(
+		"Ensure peers",
+		"numOutPeers", out,
+		"numInPeers", in,
+		"numDialing", dialing,
+		"numToDial", numToDial,
+	)
+
+	numDialed := 0
+
+	if numToDial > 0 {
+		toDial := make(map[p2p.ID]*p2p.NetAddress, numToDial)
+
+		// The same address can be picked more than once,
+		// so try a few more times than needed
+		for i := 0; i < numToDial*3 && len(toDial) < numToDial; i++ {
+			addr := r.book.PickAddress(biasToSelectNewPeers)
+			if addr == nil {
+				break
+			}
+
+			if _, ok := toDial[addr.ID]; ok || r.Switch.IsDialingOrExistingAddress(addr) {
+				continue
+			}
+
+			toDial[addr.ID] = addr
+		}
+
+		for _, addr := range toDial {
+			go r.dialPeer(addr)
+		}
+
+		numDialed = len(toDial)
+	}
+
+	if !r.book.NeedMoreAddrs() {
+		return
+	}
+
+	if peers := r.Switch.Peers().List(); len(peers) > 0 {
+		r.requestAddrs(peers[r.rng.Intn(len(peers))])
+
+		return
+	}
+
+	// Fall back to the seeds, if there is neither
+	// a peer to ask for addresses, nor an address to dial
+	if numDialed == 0 && dialing == 0 {
+		r.dialSeeds()
+	}
+}
+
+// dialPeer dials the address, and records the attempt in the book
+func (r *Reactor) dialPeer(addr *p2p.NetAddress) {
+	r.book.MarkAttempt(addr)
+
+	err := r.Switch.DialPeerWithAddress(addr)
+	if err == nil {
+		return
+	}
+
+	switch err.(type) {
+	case p2p.CurrentlyDialingOrExistingAddressError:
+		r.Logger.Debug("Already dialing or connected to peer", "addr", addr)
+	default:
+		r.Logger.Debug("Unable to dial peer", "addr", addr, "err", err)
+	}
+}
+
+// dialSeeds dials the seeds in a random order,
+// until one of them is connected
+func (r *Reactor) dialSeeds() {
+	for _, i := range r.rng.Perm(len(r.seeds)) {
+		seed := r.seeds[i]
+
+		if r.book.OurAddress(seed) {
+			continue
+		}
+
+		err := r.Switch.DialPeerWithAddress(seed)
+		if err == nil {
+			return
+		}
+
+		if _, ok := err.(p2p.CurrentlyDialingOrExistingAddressError); ok {
+			return
+		}
+
+		r.Logger.Error("Unable to dial seed", "seed", seed, "err", err)
+	}
+
+	if len(r.seeds) > 0 {
+		r.Logger.Error("Unable to connect to any seed")
+	}
+}
+
+// peerAddress returns the dialable address of the peer,
+// which is the socket address for outbound peers,
+// and the self-reported address for inbound peers
+func peerAddress(peer p2p.Peer) *p2p.NetAddress {
+	if peer.IsOutbound() {
+		return peer.SocketAddr()
+	}
+
+	return peer.NodeInfo().NetAddress
+}
+
+// splitAndTrimEmpty splits the comma separated list,
+// removing spaces and empty elements
+func splitAndTrimEmpty(s string) []string {
+	var out []string
+
+	for _, part := range strings.Split(s, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+
+	return out
+}
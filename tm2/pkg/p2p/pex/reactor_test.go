@@ -0,0 +1,263 @@
+package pex
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	"github.com/gnolang/gno/tm2/pkg/log"
+	"github.com/gnolang/gno/tm2/pkg/p2p"
+	"github.com/gnolang/gno/tm2/pkg/p2p/config"
+	"github.com/gnolang/gno/tm2/pkg/p2p/mock"
+)
+
+// recordingPeer is a mock peer that records the pex messages sent to it
+type recordingPeer struct {
+	*mock.Peer
+
+	mtx  sync.Mutex
+	msgs []PexMessage
+}
+
+func newRecordingPeer(outbound bool) *recordingPeer {
+	p := &recordingPeer{
+		Peer: mock.NewPeer(nil),
+	}
+	p.Outbound = outbound
+
+	return p
+}
+
+func (p *recordingPeer) Send(chID byte, msgBytes []byte) bool {
+	if chID != PexChannel {
+		return true
+	}
+
+	msg, err := decodeMsg(msgBytes)
+	if err != nil {
+		panic(err)
+	}
+
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	p.msgs = append(p.msgs, msg)
+
+	return true
+}
+
+func (p *recordingPeer) TrySend(chID byte, msgBytes []byte) bool {
+	return p.Send(chID, msgBytes)
+}
+
+func (p *recordingPeer) messages() []PexMessage {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	return append([]PexMessage(nil), p.msgs...)
+}
+
+// newTestReactor creates a new pex reactor with an in-memory
+// address book, attached to a switch that isn't started
+func newTestReactor(t *testing.T, cfg *config.P2PConfig) *Reactor {
+	t.Helper()
+
+	r, err := NewReactor(cfg, NewAddrBook("", false))
+	require.NoError(t, err)
+
+	r.SetLogger(log.NewNoopLogger())
+
+	p2p.MakeSwitch(cfg, 0, "127.0.0.1", "123.123.123", func(_ int, sw *p2p.Switch) *p2p.Switch {
+		sw.AddReactor("PEX", r)
+
+		return sw
+	})
+
+	require.NoError(t, r.Start())
+	t.Cleanup(func() {
+		r.Stop()
+	})
+
+	return r
+}
+
+func TestReactor_RequestAddrs(t *testing.T) {
+	t.Parallel()
+
+	var (
+		r    = newTestReactor(t, config.TestP2PConfig())
+		peer = newRecordingPeer(true)
+		addr = newTestAddr(t)
+	)
+
+	// Outbound peers are asked for their addresses
+	r.AddPeer(peer)
+
+	require.Len(t, peer.messages(), 1)
+	assert.IsType(t, &pexRequestMessage{}, peer.messages()[0])
+
+	// Requested addresses are added to the book
+	r.Receive(PexChannel, peer, amino.MustMarshalAny(&pexAddrsMessage{
+		Addrs: []*p2p.NetAddress{addr},
+	}))
+
+	assert.True(t, r.book.HasAddress(addr))
+	assert.False(t, r.book.IsBanned(peer.ID()))
+
+	// Peers are not asked again right away
+	r.requestAddrs(peer)
+	assert.Len(t, peer.messages(), 1)
+}
+
+func TestReactor_UnsolicitedAddrs(t *testing.T) {
+	t.Parallel()
+
+	var (
+		r          = newTestReactor(t, config.TestP2PConfig())
+		peer       = newRecordingPeer(false)
+		persistent = newRecordingPeer(false)
+		addr       = newTestAddr(t)
+	)
+
+	persistent.Persistent = true
+
+	msg := amino.MustMarshalAny(&pexAddrsMessage{
+		Addrs: []*p2p.NetAddress{addr},
+	})
+
+	r.Receive(PexChannel, peer, msg)
+	r.Receive(PexChannel, persistent, msg)
+
+	assert.False(t, r.book.HasAddress(addr))
+
+	// Persistent peers are never banned
+	assert.True(t, r.book.IsBanned(peer.ID()))
+	assert.False(t, r.book.IsBanned(persistent.ID()))
+}
+
+func TestReactor_ReceiveRequest(t *testing.T) {
+	t.Parallel()
+
+	var (
+		r    = newTestReactor(t, config.TestP2PConfig())
+		peer = newRecordingPeer(false)
+		addr = newTestAddr(t)
+	)
+
+	require.NoError(t, r.book.AddAddress(addr, addr))
+
+	// Inbound peers' addresses are added to the book
+	r.AddPeer(peer)
+	assert.True(t, r.book.HasAddress(peer.NodeInfo().NetAddress))
+
+	r.Receive(PexChannel, peer, amino.MustMarshalAny(&pexRequestMessage{}))
+
+	msgs := peer.messages()
+	require.Len(t, msgs, 1)
+	require.IsType(t, &pexAddrsMessage{}, msgs[0])
+	assert.Len(t, msgs[0].(*pexAddrsMessage).Addrs, 2)
+
+	// Requesting addresses too often gets the peer banned
+	r.Receive(PexChannel, peer, amino.MustMarshalAny(&pexRequestMessage{}))
+
+	assert.Len(t, peer.messages(), 1)
+	assert.True(t, r.book.IsBanned(peer.ID()))
+}
+
+func TestReactor_SeedMode(t *testing.T) {
+	t.Parallel()
+
+	cfg := config.TestP2PConfig()
+	cfg.SeedMode = true
+
+	var (
+		r    = newTestReactor(t, cfg)
+		peer = newRecordingPeer(true)
+	)
+
+	// Seeds disconnect from their outbound peers,
+	// once they received their addresses
+	r.AddPeer(peer)
+	require.True(t, peer.IsRunning())
+
+	r.Receive(PexChannel, peer, amino.MustMarshalAny(&pexAddrsMessage{}))
+
+	assert.False(t, peer.IsRunning())
+	assert.False(t, r.book.IsBanned(peer.ID()))
+}
+
+func TestReactor_EnsurePeers(t *testing.T) {
+	t.Parallel()
+
+	cfg := config.TestP2PConfig()
+
+	newSwitch := func(i int) (*p2p.Switch, *Reactor) {
+		r, err := NewReactor(cfg, NewAddrBook("", false))
+		require.NoError(t, err)
+
+		r.SetLogger(log.NewNoopLogger())
+
+		sw := p2p.MakeSwitch(cfg, i, "127.0.0.1", "123.123.123", func(_ int, sw *p2p.Switch) *p2p.Switch {
+			sw.AddReactor("PEX", r)
+			sw.SetAddrBook(r.book)
+
+			return sw
+		})
+
+		return sw, r
+	}
+
+	var (
+		swA, _  = newSwitch(0)
+		swB, rB = newSwitch(1)
+	)
+
+	// B only knows the address of A
+	require.NoError(t, rB.book.AddAddress(swA.NetAddress(), nil))
+
+	require.NoError(t, p2p.StartSwitches([]*p2p.Switch{swA, swB}))
+	defer swA.Stop()
+	defer swB.Stop()
+
+	assert.Eventually(t, func() bool {
+		out, _, _ := swB.NumPeers()
+		_, in, _ := swA.NumPeers()
+
+		return out == 1 && in == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// A successful connection is recorded
+	assert.Eventually(t, func() bool {
+		rB.book.mtx.Lock()
+		defer rB.book.mtx.Unlock()
+
+		ka, ok := rB.book.addrs[swA.NetAddress().ID]
+
+		return ok && ka.isOld()
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestMessages_Roundtrip(t *testing.T) {
+	t.Parallel()
+
+	msgs := []PexMessage{
+		&pexRequestMessage{},
+		&pexAddrsMessage{Addrs: []*p2p.NetAddress{newTestAddr(t), newTestAddr(t)}},
+	}
+
+	for _, msg := range msgs {
+		decoded, err := decodeMsg(amino.MustMarshalAny(msg))
+		require.NoError(t, err)
+		assert.Equal(t, amino.MustMarshalAny(msg), amino.MustMarshalAny(decoded))
+	}
+
+	_, err := decodeMsg(make([]byte, maxMsgSize+1))
+	assert.Error(t, err)
+
+	assert.ErrorIs(t, (&pexAddrsMessage{Addrs: []*p2p.NetAddress{nil}}).ValidateBasic(), errNilAddress)
+	assert.Error(t, (&pexAddrsMessage{Addrs: make([]*p2p.NetAddress, maxGetSelection+1)}).ValidateBasic())
+}
@@ -44,6 +44,15 @@ func MConnConfig(cfg *config.P2PConfig) conn.MConnConfig {
 	return mConfig
 }
 
+// AddrBook is the address book consulted by the switch,
+// to avoid dialing our own addresses and to reject banned peers.
+type AddrBook interface {
+	AddOurAddress(*NetAddress)
+	OurAddress(*NetAddress) bool
+	RemoveAddress(*NetAddress)
+	IsBanned(ID) bool
+}
+
 // PeerFilterFunc to be implemented by filter hooks after a new Peer has been
 // fully setup.
 type PeerFilterFunc func(IPeerSet, Peer) error
@@ -70,6 +79,7 @@ type Switch struct {
 	persistentPeersAddrs []*NetAddress
 
 	transport Transport
+	addrBook  AddrBook
 
 	filterTimeout time.Duration
 	peerFilters   []PeerFilterFunc
@@ -194,6 +204,12 @@ func (sw *Switch) SetNodeKey(nodeKey *NodeKey) {
 	sw.nodeKey = nodeKey
 }
 
+// SetAddrBook sets the switch's address book.
+// NOTE: Not goroutine safe.
+func (sw *Switch) SetAddrBook(addrBook AddrBook) {
+	sw.addrBook = addrBook
+}
+
 // ---------------------------------------------------------------------
 // Service start/stop
 
@@ -436,6 +452,11 @@ func (sw *Switch) dialPeersAsync(netAddrs []*NetAddress) {
 				return
 			}
 
+			if sw.addrBook != nil && sw.addrBook.OurAddress(addr) {
+				sw.Logger.Debug("Ignore attempt to connect to our own address", "addr", addr)
+				return
+			}
+
 			sw.randomSleep(0)
 
 			err := sw.DialPeerWithAddress(addr)
@@ -618,7 +639,12 @@ func (sw *Switch) addOutboundPeerWithConfig(
 	if err != nil {
 		if e, ok := err.(RejectedError); ok {
 			if e.IsSelf() {
-				// TODO: warn?
+				// Remember the address as our own, so it is not dialed again
+				if sw.addrBook != nil {
+					sw.addrBook.RemoveAddress(addr)
+					sw.addrBook.AddOurAddress(addr)
+				}
+
 				return err
 			}
 		}
@@ -649,6 +675,11 @@ func (sw *Switch) filterPeer(p Peer) error {
 		return RejectedError{id: p.ID(), isDuplicate: true}
 	}
 
+	// Avoid banned peers
+	if sw.addrBook != nil && sw.addrBook.IsBanned(p.ID()) {
+		return RejectedError{id: p.ID(), isBanned: true}
+	}
+
 	errc := make(chan error, len(sw.peerFilters))
 
 	for _, f := range sw.peerFilters {
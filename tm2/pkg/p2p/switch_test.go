@@ -303,6 +303,47 @@ func TestSwitchPeerFilterDuplicate(t *testing.T) {
 	}
 }
 
+// bannedAddrBook is an address book banning all the peers
+type bannedAddrBook struct{}
+
+func (bannedAddrBook) AddOurAddress(*NetAddress)   {}
+func (bannedAddrBook) OurAddress(*NetAddress) bool { return false }
+func (bannedAddrBook) RemoveAddress(*NetAddress)   {}
+func (bannedAddrBook) IsBanned(ID) bool            { return true }
+
+func TestSwitchPeerFilterBanned(t *testing.T) {
+	t.Parallel()
+
+	sw := MakeSwitch(cfg, 1, "testing", "123.123.123", initSwitchFunc)
+	sw.SetAddrBook(bannedAddrBook{})
+	sw.Start()
+	defer sw.Stop()
+
+	// simulate remote peer
+	rp := &remotePeer{PrivKey: ed25519.GenPrivKey(), Config: cfg}
+	rp.Start()
+	defer rp.Stop()
+
+	p, err := sw.transport.Dial(*rp.Addr(), peerConfig{
+		chDescs:      sw.chDescs,
+		onPeerError:  sw.StopPeerForError,
+		isPersistent: sw.isPeerPersistentFn(),
+		reactorsByCh: sw.reactorsByCh,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = sw.addPeer(p)
+	if errRej, ok := err.(RejectedError); ok {
+		if !errRej.IsBanned() {
+			t.Errorf("expected peer to be banned. got %v", errRej)
+		}
+	} else {
+		t.Errorf("expected RejectedError, got %v", err)
+	}
+}
+
 func assertNoPeersAfterTimeout(t *testing.T, sw *Switch, timeout time.Duration) {
 	t.Helper()
 
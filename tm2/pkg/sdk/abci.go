@@ -1,6 +1,9 @@
 package sdk
 
-import abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+import (
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	"github.com/gnolang/gno/tm2/pkg/store"
+)
 
 // InitChainer initializes application state at genesis
 type InitChainer func(ctx Context, req abci.RequestInitChain) abci.ResponseInitChain
@@ -25,3 +28,9 @@ type BeginTxHook func(ctx Context) Context
 // EndTxHook is a BaseApp-specific hook, called after all the messages in a
 // transaction have terminated.
 type EndTxHook func(ctx Context, result Result)
+
+// RestoreHook is a BaseApp-specific hook, called after the app state was
+// restored from a state sync snapshot, to reload any application-specific
+// state that's usually loaded on startup. The hook is given a cache of the
+// restored state, which is never written back.
+type RestoreHook func(ms store.MultiStore)
@@ -3,9 +3,11 @@ package auth
 import (
 	"encoding/hex"
 	"fmt"
+	"math"
 	"math/big"
 
 	"github.com/gnolang/gno/tm2/pkg/amino"
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
 	"github.com/gnolang/gno/tm2/pkg/crypto"
 	"github.com/gnolang/gno/tm2/pkg/crypto/ed25519"
 	"github.com/gnolang/gno/tm2/pkg/crypto/multisig"
@@ -33,11 +35,20 @@ type AnteOptions struct {
 	// This is useful for development, and maybe production chains.
 	// Always check your settings and inspect genesis transactions.
 	VerifyGenesisSignatures bool
+
+	// If ReplaceByFee is true, a tx signed for the sequence of a tx pending in
+	// the mempool is accepted on a CheckTxTypeReplace request, if its priority
+	// is higher, so that it replaces the pending tx: the fee of the replaced
+	// tx is refunded in the CheckTx state. The pending txs are recorded in
+	// the CheckTx state; only the txs with a single signer and without fee
+	// granter can be replaced. It must only be set with the priority mempool.
+	ReplaceByFee bool
 }
 
-// NewAnteHandler returns an AnteHandler that checks and increments sequence
-// numbers, checks signatures & account numbers, and deducts fees from the first
-// signer.
+// NewAnteHandler returns an AnteHandler that checks the timeout height,
+// checks and increments sequence numbers, checks signatures & account numbers,
+// and deducts fees from the first signer, or from the fee granter within its
+// fee allowance.
 func NewAnteHandler(ak AccountKeeper, bank BankKeeperI, sigGasConsumer SignatureVerificationGasConsumer, opts AnteOptions) sdk.AnteHandler {
 	return func(
 		ctx sdk.Context, tx std.Tx, simulate bool,
@@ -57,6 +68,24 @@ func NewAnteHandler(ak AccountKeeper, bank BankKeeperI, sigGasConsumer Signature
 			return ctx, res, true
 		}
 
+		// Ensure that the tx has not expired. Outside of DeliverTx, the context
+		// is that of the last committed block, so the tx can be included in the
+		// next block at the earliest. This also evicts the expired txs from the
+		// mempool, when they are rechecked after each block.
+		height := ctx.BlockHeight()
+		if ctx.Mode() != sdk.RunTxModeDeliver {
+			height++
+		}
+		if tx.IsExpired(height) {
+			res = abciResult(std.ErrTxExpired(
+				fmt.Sprintf(
+					"tx expired at height %d; current height: %d",
+					tx.TimeoutHeight, height,
+				),
+			))
+			return ctx, res, true
+		}
+
 		// Ensure that the provided fees meet a minimum threshold for the validator,
 		// if this is a CheckTx. This is only for local mempool purposes, and thus
 		// is only run upon checktx.
@@ -114,27 +143,61 @@ func NewAnteHandler(ak AccountKeeper, bank BankKeeperI, sigGasConsumer Signature
 		signerAccs := make([]std.Account, len(signerAddrs))
 		isGenesis := ctx.BlockHeight() == 0
 
-		// fetch first signer, who's going to pay the fees
+		// fetch first signer, who's going to pay the fees, unless there
+		// is a fee granter
 		signerAccs[0], res = GetSignerAcc(newCtx, ak, signerAddrs[0])
 		if !res.IsOK() {
 			return newCtx, res, true
 		}
 
-		// deduct the fees
-		if !tx.Fee.GasFee.IsZero() {
-			res = DeductFees(bank, newCtx, signerAccs[0], std.Coins{tx.Fee.GasFee})
+		// deduct the fees, from the fee granter if any.
+		feePayer := signerAccs[0]
+		if granter := tx.Fee.Granter; !granter.IsZero() {
+			err := ak.UseFeeAllowance(newCtx, granter, signerAddrs[0], std.Coins{tx.Fee.GasFee}, tx.GetMsgs())
+			if err != nil {
+				return newCtx, abciResult(err), true
+			}
+			feePayer, res = GetSignerAcc(newCtx, ak, granter)
 			if !res.IsOK() {
 				return newCtx, res, true
 			}
+		}
 
-			// reload the account as fees have been deducted
-			signerAccs[0] = ak.GetAccount(newCtx, signerAccs[0].GetAddress())
+		// whether the tx may replace a tx pending in the mempool, in which
+		// case committedCtx reads the state committed in the last block, as
+		// the changes of the pending txs are still in the CheckTx state.
+		replace := newCtx.IsCheckTx() && opts.ReplaceByFee && !simulate &&
+			newCtx.CheckTxType() == abci.CheckTxTypeReplace && newCtx.CommittedMultiStore() != nil
+		var committedCtx sdk.Context
+		if replace {
+			// the changes to the committed state are discarded.
+			committedCtx = newCtx.WithMultiStore(newCtx.CommittedMultiStore().MultiCacheWrap())
+		}
+
+		// whether the fee can only be paid once the fee of the replaced tx is
+		// refunded, the tx must then be a replacing tx.
+		var feeRes sdk.Result
+		mustReplace := false
+		if !tx.Fee.GasFee.IsZero() {
+			res = DeductFees(bank, newCtx, feePayer, std.Coins{tx.Fee.GasFee})
+			if !res.IsOK() && replace {
+				feeRes = res
+				mustReplace = true
+			} else if !res.IsOK() {
+				return newCtx, res, true
+			} else {
+				// reload the account as fees have been deducted
+				signerAccs[0] = ak.GetAccount(newCtx, signerAccs[0].GetAddress())
+			}
 		}
 
 		// stdSigs contains the sequence number, account number, and signatures.
 		// When simulating, this would just be a 0-length slice.
 		stdSigs := tx.GetSignatures()
 
+		// the sequence of the tx, for the mempool ordering.
+		sequence := signerAccs[0].GetSequence()
+
 		for i := 0; i < len(stdSigs); i++ {
 			// skip the fee payer, account is cached and fees were deducted already
 			if i != 0 {
@@ -155,6 +218,16 @@ func NewAnteHandler(ak AccountKeeper, bank BankKeeperI, sigGasConsumer Signature
 					return newCtx, res, true
 				}
 				signerAccs[i], res = processSig(newCtx, sacc, stdSigs[i], signBytes, simulate, params, sigGasConsumer)
+				if res.IsOK() && i == 0 && mustReplace {
+					// not a replacing tx.
+					return newCtx, feeRes, true
+				} else if !res.IsOK() && replace && i == 0 {
+					// the tx may be signed for the sequence of a pending tx.
+					signerAccs[i], sequence, res = processReplacingSig(newCtx, committedCtx, ak, tx, sacc, stdSigs[i], params, sigGasConsumer)
+					if res.IsOK() {
+						signerAccs[i], res = replacePendingTx(newCtx, ak, bank, tx, signerAccs[i], sequence, mustReplace)
+					}
+				}
 				if !res.IsOK() {
 					return newCtx, res, true
 				}
@@ -162,8 +235,23 @@ func NewAnteHandler(ak AccountKeeper, bank BankKeeperI, sigGasConsumer Signature
 			ak.SetAccount(newCtx, signerAccs[i])
 		}
 
+		// record the pending tx, for a tx to replace it.
+		priority := TxPriority(tx.Fee, priorityDenom(newCtx))
+		if newCtx.IsCheckTx() && opts.ReplaceByFee && !simulate &&
+			len(stdSigs) == 1 && tx.Fee.Granter.IsZero() {
+			ak.setPendingTx(newCtx, signerAddrs[0], sequence, pendingTx{
+				Priority: priority,
+				Fee:      std.Coins{tx.Fee.GasFee},
+			})
+		}
+
 		// TODO: tx tags (?)
-		return newCtx, sdk.Result{GasWanted: tx.Fee.GasWanted}, false // continue...
+		return newCtx, sdk.Result{
+			GasWanted: tx.Fee.GasWanted,
+			Priority:  priority,
+			Sender:    signerAddrs[0].String(),
+			Sequence:  sequence,
+		}, false // continue...
 	}
 }
 
@@ -240,6 +328,83 @@ func processSig(
 	return acc, res
 }
 
+// MaxReplacedSequences is the number of sequences preceding the current
+// sequence of an account, for which a tx pending in the mempool can be
+// replaced.
+const MaxReplacedSequences = 16
+
+// processReplacingSig verifies that the signature is valid for the sequence of
+// one of the txs of the account pending in the mempool, between its sequence in
+// committedCtx and its sequence acc in the CheckTx state, and returns this
+// sequence. The verifications consume the gas of the tx. The sequence of the
+// account is left unchanged.
+func processReplacingSig(
+	ctx, committedCtx sdk.Context, ak AccountKeeper, tx std.Tx, acc std.Account, sig std.Signature, params Params,
+	sigGasConsumer SignatureVerificationGasConsumer,
+) (updatedAcc std.Account, seq uint64, res sdk.Result) {
+	pubKey, res := ProcessPubKey(acc, sig, false)
+	if !res.IsOK() {
+		return nil, 0, res
+	}
+
+	seq = acc.GetSequence()
+	first := seq
+	if committed := ak.GetAccount(committedCtx, acc.GetAddress()); committed != nil {
+		first = committed.GetSequence()
+	}
+	for i := 0; i < MaxReplacedSequences && seq > first; i++ {
+		seq--
+		if res := sigGasConsumer(ctx.GasMeter(), sig.Signature, pubKey, params); !res.IsOK() {
+			return nil, 0, res
+		}
+		signBytes, err := tx.GetSignBytes(ctx.ChainID(), acc.GetAccountNumber(), seq)
+		if err != nil {
+			return nil, 0, abciResult(std.ErrInternal(err.Error()))
+		}
+		if pubKey.VerifyBytes(signBytes, sig.Signature) {
+			return acc, seq, sdk.Result{}
+		}
+	}
+	return nil, 0, abciResult(std.ErrUnauthorized("signature verification failed; verify correct account, sequence, and chain-id"))
+}
+
+// replacePendingTx checks that tx can replace the pending tx of acc with the
+// given sequence, which must have a lower priority, and refunds the fee of the
+// replaced tx. If deductFee is true, the fee of tx is then deducted, as it
+// could not be paid before. It returns the updated account.
+func replacePendingTx(
+	ctx sdk.Context, ak AccountKeeper, bank BankKeeperI, tx std.Tx, acc std.Account, sequence uint64, deductFee bool,
+) (std.Account, sdk.Result) {
+	addr := acc.GetAddress()
+	if len(tx.GetSignatures()) != 1 || !tx.Fee.Granter.IsZero() {
+		return nil, abciResult(std.ErrUnauthorized(
+			"only a tx with a single signer and without fee granter can replace a pending tx"))
+	}
+	ptx := ak.getPendingTx(ctx, addr, sequence)
+	if ptx == nil {
+		return nil, abciResult(std.ErrUnauthorized(
+			fmt.Sprintf("the pending tx of %s with sequence %d cannot be replaced", addr, sequence)))
+	}
+	if priority := TxPriority(tx.Fee, priorityDenom(ctx)); priority <= ptx.Priority {
+		return nil, abciResult(std.ErrInsufficientFee(
+			fmt.Sprintf("tx priority %d must be higher than the priority %d of the replaced tx", priority, ptx.Priority)))
+	}
+
+	if !ptx.Fee.IsZero() {
+		if err := bank.SendCoins(ctx, FeeCollectorAddress(), addr, ptx.Fee); err != nil {
+			return nil, abciResult(err)
+		}
+	}
+	acc = ak.GetAccount(ctx, addr)
+	if deductFee {
+		if res := DeductFees(bank, ctx, acc, std.Coins{tx.Fee.GasFee}); !res.IsOK() {
+			return nil, res
+		}
+		acc = ak.GetAccount(ctx, addr)
+	}
+	return acc, sdk.Result{}
+}
+
 // ProcessPubKey verifies that the given account address matches that of the
 // std.Signature. In addition, it will set the public key of the account if it
 // has not been set.
@@ -322,6 +487,16 @@ func DeductFees(bank BankKeeperI, ctx sdk.Context, acc std.Account, fees std.Coi
 		))
 	}
 
+	// verify the fees are not paid with locked coins of a vesting account
+	if _, ok := acc.(VestingAccount); ok {
+		spendable := SpendableCoins(acc, ctx.BlockTime())
+		if !spendable.IsAllGTE(fees) {
+			return abciResult(std.ErrInsufficientFunds(
+				fmt.Sprintf("insufficient spendable funds to pay for fees; %s < %s", spendable, fees),
+			))
+		}
+	}
+
 	err := bank.SendCoins(ctx, acc.GetAddress(), FeeCollectorAddress(), fees)
 	if err != nil {
 		return abciResult(err)
@@ -415,6 +590,38 @@ func SetGasMeter(simulate bool, ctx sdk.Context, gasLimit int64) sdk.Context {
 	return ctx.WithGasMeter(store.NewGasMeter(gasLimit))
 }
 
+// TxPriority returns the priority of a tx with the given fee in the mempool,
+// which is its gas price, as the amount of the gas fee paid per million of gas.
+// The gas prices of other denoms than denom can't be compared, so the fees
+// paid in them have no priority; an empty denom compares any.
+func TxPriority(fee std.Fee, denom string) int64 {
+	if fee.GasWanted <= 0 {
+		return 0
+	}
+	if denom != "" && fee.GasFee.Denom != denom {
+		return 0
+	}
+	price := new(big.Int).Mul(big.NewInt(fee.GasFee.Amount), big.NewInt(1_000_000))
+	price.Quo(price, big.NewInt(fee.GasWanted))
+	if !price.IsInt64() {
+		return math.MaxInt64
+	}
+	return price.Int64()
+}
+
+// priorityDenom returns the denom of the gas prices compared by the priority
+// of the txs: the one of the block gas price, or else the one of the minimum
+// gas prices of the node.
+func priorityDenom(ctx sdk.Context) string {
+	if gp, ok := ctx.Value(GasPriceContextKey{}).(std.GasPrice); ok && gp.Price.Denom != "" {
+		return gp.Price.Denom
+	}
+	if mgp := ctx.MinGasPrices(); len(mgp) > 0 {
+		return mgp[0].Price.Denom
+	}
+	return ""
+}
+
 // GetSignBytes returns a slice of bytes to sign over for a given transaction
 // and an account.
 func GetSignBytes(chainID string, tx std.Tx, acc std.Account, genesis bool) ([]byte, error) {
@@ -431,6 +638,7 @@ func GetSignBytes(chainID string, tx std.Tx, acc std.Account, genesis bool) ([]b
 			Fee:           tx.Fee,
 			Msgs:          tx.Msgs,
 			Memo:          tx.Memo,
+			TimeoutHeight: tx.TimeoutHeight,
 		},
 	)
 }
@@ -2,6 +2,7 @@ package auth
 
 import (
 	"fmt"
+	"math"
 	"math/rand"
 	"reflect"
 	"strings"
@@ -291,7 +292,7 @@ func TestAnteHandlerSequences(t *testing.T) {
 	// tx from just second signer with incorrect sequence fails
 	msg = tu.NewTestMsg(addr2)
 	msgs = []std.Msg{msg}
-	privs, accnums, seqs = []crypto.PrivKey{priv2}, []uint64{1}, []uint64{0}
+	privs, accnums, seqs = []crypto.PrivKey{priv2}, []uint64{acc2.GetAccountNumber()}, []uint64{0}
 	tx = tu.NewTestTx(t, ctx.ChainID(), msgs, privs, accnums, seqs, fee)
 	checkInvalidTx(t, anteHandler, ctx, tx, false, std.UnauthorizedError{})
 
@@ -350,6 +351,278 @@ func TestAnteHandlerFees(t *testing.T) {
 	require.Equal(t, env.acck.GetAccount(ctx, addr1).GetCoins().AmountOf("atom"), int64(0))
 }
 
+// Test logic around fees paid by a fee granter.
+func TestAnteHandlerFeeGranter(t *testing.T) {
+	t.Parallel()
+
+	// setup
+	env := setupTestEnv()
+	ctx := env.ctx
+	anteHandler := NewAnteHandler(env.acck, env.bank, DefaultSigVerificationGasConsumer, defaultAnteOptions())
+
+	// keys and addresses
+	priv1, _, addr1 := tu.KeyTestPubAddr()
+	_, _, addr2 := tu.KeyTestPubAddr()
+
+	// set the accounts; the grantee has no funds.
+	acc1 := env.acck.NewAccountWithAddress(ctx, addr1)
+	env.acck.SetAccount(ctx, acc1)
+	acc2 := env.acck.NewAccountWithAddress(ctx, addr2)
+	acc2.SetCoins(std.NewCoins(std.NewCoin("atom", 300)))
+	env.acck.SetAccount(ctx, acc2)
+
+	// msg and signatures
+	var tx std.Tx
+	msg := tu.NewTestMsg(addr1)
+	privs, accnums, seqs := []crypto.PrivKey{priv1}, []uint64{0}, []uint64{0}
+	fee := tu.NewTestFee().WithGranter(addr2)
+	msgs := []std.Msg{msg}
+
+	// no fee allowance
+	tx = tu.NewTestTx(t, ctx.ChainID(), msgs, privs, accnums, seqs, fee)
+	checkInvalidTx(t, anteHandler, ctx, tx, false, std.UnauthorizedError{})
+
+	// message type not allowed
+	env.acck.SetFeeAllowance(ctx, addr2, addr1, FeeAllowance{
+		AllowedMsgs: []string{"bank/send"},
+	})
+	checkInvalidTx(t, anteHandler, ctx, tx, false, std.UnauthorizedError{})
+
+	// the message does not target a realm
+	env.acck.SetFeeAllowance(ctx, addr2, addr1, FeeAllowance{
+		AllowedRealms: []string{"gno.land/r/demo/foo"},
+	})
+	checkInvalidTx(t, anteHandler, ctx, tx, false, std.UnauthorizedError{})
+
+	// expired allowance
+	env.acck.SetFeeAllowance(ctx, addr2, addr1, FeeAllowance{
+		ExpirationHeight: 1,
+	})
+	checkInvalidTx(t, anteHandler, ctx.WithBlockHeader(&bft.Header{Height: 2, ChainID: ctx.ChainID()}), tx, false, std.UnauthorizedError{})
+
+	// the granter pays the fees
+	env.acck.SetFeeAllowance(ctx, addr2, addr1, FeeAllowance{
+		SpendLimit:       std.NewCoins(std.NewCoin("atom", 200)),
+		ExpirationHeight: 1,
+		AllowedMsgs:      []string{"TestMsg/Test message"},
+	})
+	checkValidTx(t, anteHandler, ctx, tx, false)
+
+	require.Equal(t, int64(150), env.acck.GetAccount(ctx, FeeCollectorAddress()).GetCoins().AmountOf("atom"))
+	require.Equal(t, int64(150), env.acck.GetAccount(ctx, addr2).GetCoins().AmountOf("atom"))
+	require.True(t, env.acck.GetAccount(ctx, addr1).GetCoins().IsZero())
+	fa := env.acck.GetFeeAllowance(ctx, addr2, addr1)
+	require.NotNil(t, fa)
+	require.Equal(t, int64(50), fa.SpendLimit.AmountOf("atom"))
+
+	// the spend limit is exceeded
+	seqs = []uint64{1}
+	tx = tu.NewTestTx(t, ctx.ChainID(), msgs, privs, accnums, seqs, fee)
+	checkInvalidTx(t, anteHandler, ctx, tx, false, std.InsufficientFundsError{})
+
+	// the granter must be signed over
+	env.acck.SetFeeAllowance(ctx, addr2, addr1, FeeAllowance{})
+	tx = tu.NewTestTx(t, ctx.ChainID(), msgs, privs, accnums, seqs, tu.NewTestFee())
+	tx.Fee.Granter = addr2
+	checkInvalidTx(t, anteHandler, ctx, tx, false, std.UnauthorizedError{})
+}
+
+// Test logic around the timeout height.
+func TestAnteHandlerTimeoutHeight(t *testing.T) {
+	t.Parallel()
+
+	// setup
+	env := setupTestEnv()
+	ctx := env.ctx
+	anteHandler := NewAnteHandler(env.acck, env.bank, DefaultSigVerificationGasConsumer, defaultAnteOptions())
+
+	// keys and addresses
+	priv1, _, addr1 := tu.KeyTestPubAddr()
+
+	// set the accounts
+	acc1 := env.acck.NewAccountWithAddress(ctx, addr1)
+	acc1.SetCoins(tu.NewTestCoins())
+	env.acck.SetAccount(ctx, acc1)
+
+	// msg and signatures
+	var tx std.Tx
+	msg := tu.NewTestMsg(addr1)
+	privs, accnums, seqs := []crypto.PrivKey{priv1}, []uint64{0}, []uint64{0}
+	fee := tu.NewTestFee()
+	msgs := []std.Msg{msg}
+
+	// the timeout height is signed over
+	tx = tu.NewTestTx(t, ctx.ChainID(), msgs, privs, accnums, seqs, fee)
+	tx.TimeoutHeight = 1
+	checkInvalidTx(t, anteHandler, ctx, tx, false, std.UnauthorizedError{})
+
+	signBytes, err := tx.GetSignBytes(ctx.ChainID(), accnums[0], seqs[0])
+	require.NoError(t, err)
+	tx = tu.NewTestTxWithSignBytes(msgs, privs, fee, signBytes, "")
+	tx.TimeoutHeight = 1
+
+	// expired in the next block
+	checkInvalidTx(t, anteHandler, ctx.WithMode(sdk.RunTxModeCheck), tx, false, std.TxExpiredError{})
+	checkInvalidTx(t, anteHandler, ctx.WithMode(sdk.RunTxModeSimulate), tx, true, std.TxExpiredError{})
+	checkInvalidTx(t, anteHandler, ctx.WithBlockHeader(&bft.Header{Height: 2, ChainID: ctx.ChainID()}), tx, false, std.TxExpiredError{})
+
+	// included at the timeout height
+	checkValidTx(t, anteHandler, ctx, tx, false)
+
+	// invalid timeout height
+	seqs = []uint64{1}
+	tx = tu.NewTestTx(t, ctx.ChainID(), msgs, privs, accnums, seqs, fee)
+	tx.TimeoutHeight = -1
+	checkInvalidTx(t, anteHandler, ctx, tx, false, std.TxExpiredError{})
+}
+
+// Test logic around the replacement of txs pending in the mempool.
+func TestAnteHandlerReplaceTx(t *testing.T) {
+	t.Parallel()
+
+	// setup; the changes of the pending txs are in the CheckTx state only.
+	env := setupTestEnv()
+	committedCtx := env.ctx.WithValue(GasPriceContextKey{}, std.GasPrice{})
+	ctx := committedCtx.WithMode(sdk.RunTxModeCheck).WithMultiStore(committedCtx.MultiStore().MultiCacheWrap())
+	opts := defaultAnteOptions()
+	opts.ReplaceByFee = true
+	anteHandler := NewAnteHandler(env.acck, env.bank, DefaultSigVerificationGasConsumer, opts)
+
+	// keys and addresses
+	priv1, _, addr1 := tu.KeyTestPubAddr()
+
+	// set the accounts
+	acc1 := env.acck.NewAccountWithAddress(committedCtx, addr1)
+	acc1.SetCoins(tu.NewTestCoins())
+	env.acck.SetAccount(committedCtx, acc1)
+
+	// msg and signatures
+	var tx std.Tx
+	msg := tu.NewTestMsg(addr1)
+	privs, accnums := []crypto.PrivKey{priv1}, []uint64{0}
+	msgs := []std.Msg{msg}
+
+	// the priority is the gas price, and the sequence the one signed over.
+	for seq := uint64(0); seq < 2; seq++ {
+		tx = tu.NewTestTx(t, ctx.ChainID(), msgs, privs, accnums, []uint64{seq}, tu.NewTestFee())
+		_, res, abort := anteHandler(ctx, tx, false)
+		require.False(t, abort, res.Log)
+		assert.Equal(t, int64(150*1_000_000/50000), res.Priority)
+		assert.Equal(t, addr1.String(), res.Sender)
+		assert.Equal(t, seq, res.Sequence)
+	}
+
+	// a tx signed for the sequence of a pending tx.
+	fee := std.NewFee(50000, std.NewCoin("atom", 300))
+	tx = tu.NewTestTx(t, ctx.ChainID(), msgs, privs, accnums, []uint64{0}, fee)
+	checkInvalidTx(t, anteHandler, ctx, tx, false, std.UnauthorizedError{})
+	checkInvalidTx(t, anteHandler, ctx.WithCheckTxType(abci.CheckTxTypeReplace), tx, false, std.UnauthorizedError{})
+
+	replaceCtx := ctx.WithCheckTxType(abci.CheckTxTypeReplace).WithCommittedMultiStore(committedCtx.MultiStore().MultiCacheWrap())
+	checkInvalidTx(t, anteHandler, replaceCtx.WithMode(sdk.RunTxModeDeliver), tx, false, std.UnauthorizedError{})
+	newCtx, res, abort := anteHandler(replaceCtx, tx, false)
+	require.False(t, abort, res.Log)
+	assert.Equal(t, int64(300*1_000_000/50000), res.Priority)
+	assert.Equal(t, uint64(0), res.Sequence)
+	assert.Equal(t, uint64(2), env.acck.GetAccount(ctx, addr1).GetSequence())
+	// the signature is verified for the sequences 2, 1 and 0.
+	assert.GreaterOrEqual(t, newCtx.GasMeter().GasConsumed(), 3*DefaultSigVerifyCostSecp256k1)
+
+	// not a pending sequence.
+	tx = tu.NewTestTx(t, ctx.ChainID(), msgs, privs, accnums, []uint64{3}, fee)
+	checkInvalidTx(t, anteHandler, replaceCtx, tx, false, std.UnauthorizedError{})
+
+	// the next sequence is still accepted.
+	tx = tu.NewTestTx(t, ctx.ChainID(), msgs, privs, accnums, []uint64{2}, fee)
+	_, res, abort = anteHandler(replaceCtx, tx, false)
+	require.False(t, abort, res.Log)
+	assert.Equal(t, uint64(2), res.Sequence)
+
+	// the committed sequences cannot be replaced.
+	acc1 = env.acck.GetAccount(committedCtx, addr1)
+	require.NoError(t, acc1.SetSequence(1))
+	env.acck.SetAccount(committedCtx, acc1)
+	replaceCtx = replaceCtx.WithCommittedMultiStore(committedCtx.MultiStore().MultiCacheWrap())
+	tx = tu.NewTestTx(t, ctx.ChainID(), msgs, privs, accnums, []uint64{0}, fee)
+	checkInvalidTx(t, anteHandler, replaceCtx, tx, false, std.UnauthorizedError{})
+	tx = tu.NewTestTx(t, ctx.ChainID(), msgs, privs, accnums, []uint64{1}, fee)
+	checkValidTx(t, anteHandler, replaceCtx, tx, false)
+
+	// the txs are not replaced without the option, like with the fifo
+	// mempool.
+	anteHandler = NewAnteHandler(env.acck, env.bank, DefaultSigVerificationGasConsumer, defaultAnteOptions())
+	checkInvalidTx(t, anteHandler, replaceCtx, tx, false, std.UnauthorizedError{})
+}
+
+// Test that a tx replacing a pending tx is not charged for the fee of the
+// replaced tx.
+func TestAnteHandlerReplaceTxFee(t *testing.T) {
+	t.Parallel()
+
+	// setup; the changes of the pending txs are in the CheckTx state only.
+	env := setupTestEnv()
+	committedCtx := env.ctx.WithValue(GasPriceContextKey{}, std.GasPrice{})
+	ctx := committedCtx.WithMode(sdk.RunTxModeCheck).WithMultiStore(committedCtx.MultiStore().MultiCacheWrap())
+	opts := defaultAnteOptions()
+	opts.ReplaceByFee = true
+	anteHandler := NewAnteHandler(env.acck, env.bank, DefaultSigVerificationGasConsumer, opts)
+
+	// keys and addresses
+	priv1, _, addr1 := tu.KeyTestPubAddr()
+
+	// set the accounts; the balance covers only one fee.
+	acc1 := env.acck.NewAccountWithAddress(committedCtx, addr1)
+	acc1.SetCoins(std.NewCoins(std.NewCoin("atom", 300)))
+	env.acck.SetAccount(committedCtx, acc1)
+
+	// msg and signatures
+	var tx std.Tx
+	privs, accnums := []crypto.PrivKey{priv1}, []uint64{0}
+	msgs := []std.Msg{tu.NewTestMsg(addr1)}
+
+	// the pending tx.
+	tx = tu.NewTestTx(t, ctx.ChainID(), msgs, privs, accnums, []uint64{0}, tu.NewTestFee())
+	checkValidTx(t, anteHandler, ctx, tx, false)
+	assert.Equal(t, int64(150), env.acck.GetAccount(ctx, addr1).GetCoins().AmountOf("atom"))
+
+	// a tx with a higher fee cannot be paid on top of the pending tx.
+	replaceCtx := ctx.WithCheckTxType(abci.CheckTxTypeReplace).WithCommittedMultiStore(committedCtx.MultiStore().MultiCacheWrap())
+	fee := std.NewFee(50000, std.NewCoin("atom", 300))
+	tx = tu.NewTestTx(t, ctx.ChainID(), msgs, privs, accnums, []uint64{1}, fee)
+	checkInvalidTx(t, anteHandler, replaceCtx, tx, false, std.InsufficientFundsError{})
+
+	// a tx without a higher priority cannot replace the pending tx.
+	underpriced := tu.NewTestTxWithMemo(t, ctx.ChainID(), msgs, privs, accnums, []uint64{0}, tu.NewTestFee(), "underpriced")
+	checkInvalidTx(t, anteHandler, replaceCtx.WithMultiStore(ctx.MultiStore().MultiCacheWrap()), underpriced, false, std.InsufficientFeeError{})
+
+	// but it can replace the pending tx, whose fee is refunded in the
+	// CheckTx state.
+	tx = tu.NewTestTx(t, ctx.ChainID(), msgs, privs, accnums, []uint64{0}, fee)
+	_, res, abort := anteHandler(replaceCtx, tx, false)
+	require.False(t, abort, res.Log)
+	assert.Equal(t, uint64(0), res.Sequence)
+	assert.Equal(t, uint64(1), env.acck.GetAccount(ctx, addr1).GetSequence())
+	assert.Equal(t, int64(0), env.acck.GetAccount(ctx, addr1).GetCoins().AmountOf("atom"))
+	assert.Equal(t, int64(300), env.acck.GetAccount(committedCtx, addr1).GetCoins().AmountOf("atom"))
+
+	// the replacing tx is then the pending tx.
+	underpriced = tu.NewTestTxWithMemo(t, ctx.ChainID(), msgs, privs, accnums, []uint64{0}, fee, "underpriced")
+	checkInvalidTx(t, anteHandler, replaceCtx.WithMultiStore(ctx.MultiStore().MultiCacheWrap()), underpriced, false, std.InsufficientFeeError{})
+}
+
+func TestTxPriority(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, int64(0), TxPriority(std.NewFee(0, std.NewCoin("atom", 10)), "atom"))
+	assert.Equal(t, int64(3), TxPriority(std.NewFee(1_000_000, std.NewCoin("atom", 3)), "atom"))
+	assert.Equal(t, int64(1_000_000), TxPriority(std.NewFee(10, std.NewCoin("atom", 10)), "atom"))
+	assert.Equal(t, int64(math.MaxInt64), TxPriority(std.NewFee(1, std.NewCoin("atom", math.MaxInt64)), "atom"))
+
+	// the fees of other denoms have no priority.
+	assert.Equal(t, int64(0), TxPriority(std.NewFee(10, std.NewCoin("worthless", 1e9)), "atom"))
+	assert.Equal(t, int64(1e14), TxPriority(std.NewFee(10, std.NewCoin("worthless", 1e9)), ""))
+}
+
 // Test logic around memo gas consumption.
 func TestAnteHandlerMemoGas(t *testing.T) {
 	t.Parallel()
@@ -445,6 +718,61 @@ func TestAnteHandlerMultiSigner(t *testing.T) {
 	checkValidTx(t, anteHandler, ctx, tx, false)
 }
 
+func TestAnteHandlerRotatedKey(t *testing.T) {
+	t.Parallel()
+
+	// setup
+	env := setupTestEnv()
+	anteHandler := NewAnteHandler(env.acck, env.bank, DefaultSigVerificationGasConsumer, defaultAnteOptions())
+	ctx := env.ctx
+
+	// the account of priv1 is rotated to priv2, then to a 2 of 3 multisig.
+	priv1, _, addr := tu.KeyTestPubAddr()
+	priv2, pub2, _ := tu.KeyTestPubAddr()
+	acc := env.acck.NewAccountWithAddress(ctx, addr)
+	acc.SetCoins(tu.NewTestCoins())
+	require.NoError(t, acc.SetAccountNumber(0))
+	env.acck.SetAccount(ctx, acc)
+
+	msg := tu.NewTestMsg(addr)
+	fee := tu.NewTestFee()
+	tx := tu.NewTestTx(t, ctx.ChainID(), []std.Msg{msg}, []crypto.PrivKey{priv1}, []uint64{0}, []uint64{0}, fee)
+	checkValidTx(t, anteHandler, ctx, tx, false)
+
+	acc = env.acck.GetAccount(ctx, addr)
+	require.NoError(t, acc.SetPubKey(pub2))
+	env.acck.SetAccount(ctx, acc)
+
+	tx = tu.NewTestTx(t, ctx.ChainID(), []std.Msg{msg}, []crypto.PrivKey{priv1}, []uint64{0}, []uint64{1}, fee)
+	checkInvalidTx(t, anteHandler, ctx, tx, false, std.UnauthorizedError{})
+	tx = tu.NewTestTx(t, ctx.ChainID(), []std.Msg{msg}, []crypto.PrivKey{priv2}, []uint64{0}, []uint64{1}, fee)
+	checkValidTx(t, anteHandler, ctx, tx, false)
+
+	privs := []crypto.PrivKey{secp256k1.GenPrivKey(), secp256k1.GenPrivKey(), secp256k1.GenPrivKey()}
+	pubs := []crypto.PubKey{privs[0].PubKey(), privs[1].PubKey(), privs[2].PubKey()}
+	multisigKey := multisig.NewPubKeyMultisigThreshold(2, pubs)
+	acc = env.acck.GetAccount(ctx, addr)
+	require.NoError(t, acc.SetPubKey(multisigKey))
+	env.acck.SetAccount(ctx, acc)
+
+	signBytes, err := std.GetSignaturePayload(std.SignDoc{
+		ChainID:       ctx.ChainID(),
+		AccountNumber: 0,
+		Sequence:      2,
+		Fee:           fee,
+		Msgs:          []std.Msg{msg},
+	})
+	require.NoError(t, err)
+	multisignature := multisig.NewMultisig(len(pubs))
+	for _, priv := range privs[1:] {
+		sig, err := priv.Sign(signBytes)
+		require.NoError(t, err)
+		require.NoError(t, multisignature.AddSignatureFromPubKey(sig, priv.PubKey(), pubs))
+	}
+	tx = std.NewTx([]std.Msg{msg}, fee, []std.Signature{{PubKey: multisigKey, Signature: multisignature.Marshal()}}, "")
+	checkValidTx(t, anteHandler, ctx, tx, false)
+}
+
 func TestAnteHandlerBadSignBytes(t *testing.T) {
 	t.Parallel()
 
@@ -864,7 +1192,7 @@ func TestCustomSignatureVerificationGasConsumer(t *testing.T) {
 	require.NoError(t, acc2.SetAccountNumber(1))
 	env.acck.SetAccount(ctx, acc2)
 	msg = tu.NewTestMsg(addr2)
-	privs, accnums, seqs = []crypto.PrivKey{priv2}, []uint64{1}, []uint64{0}
+	privs, accnums, seqs = []crypto.PrivKey{priv2}, []uint64{acc2.GetAccountNumber()}, []uint64{0}
 	fee = tu.NewTestFee()
 	msgs = []std.Msg{msg}
 	tx = tu.NewTestTx(t, ctx.ChainID(), msgs, privs, accnums, seqs, fee)
@@ -1,6 +1,8 @@
 package auth
 
 import (
+	"encoding/binary"
+
 	"github.com/gnolang/gno/tm2/pkg/crypto"
 )
 
@@ -19,6 +21,11 @@ const (
 
 	// AddressStoreKeyPrefix prefix for account-by-address store
 	AddressStoreKeyPrefix = "/a/"
+	// prefix for fee-allowance-by-granter-and-grantee store
+	FeeAllowanceStoreKeyPrefix = "/g/"
+	// prefix for pending-tx-by-signer-and-sequence store, in the CheckTx
+	// state only
+	PendingTxStoreKeyPrefix = "/p/"
 	// key for gas price
 	GasPriceKey = "gasPrice"
 	// param key for global account number
@@ -30,6 +37,20 @@ func AddressStoreKey(addr crypto.Address) []byte {
 	return append([]byte(AddressStoreKeyPrefix), addr.Bytes()...)
 }
 
+// FeeAllowanceStoreKey returns the key of the fee allowance granted by granter
+// to grantee.
+func FeeAllowanceStoreKey(granter, grantee crypto.Address) []byte {
+	key := append([]byte(FeeAllowanceStoreKeyPrefix), granter.Bytes()...)
+	return append(key, grantee.Bytes()...)
+}
+
+// PendingTxStoreKey returns the key of the tx of signer with the given
+// sequence, pending in the mempool.
+func PendingTxStoreKey(signer crypto.Address, sequence uint64) []byte {
+	key := append([]byte(PendingTxStoreKeyPrefix), signer.Bytes()...)
+	return binary.BigEndian.AppendUint64(key, sequence)
+}
+
 // NOTE: do not modify.
 // XXX: consider parameterization at the keeper level.
 var feeCollector crypto.Address
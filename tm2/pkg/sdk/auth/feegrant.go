@@ -0,0 +1,162 @@
+package auth
+
+import (
+	"fmt"
+
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/gnolang/gno/tm2/pkg/sdk"
+	"github.com/gnolang/gno/tm2/pkg/std"
+)
+
+// FeeAllowance is granted by a granter to a grantee, to pay the fees of the
+// transactions of the grantee, which set the granter as the fee granter.
+type FeeAllowance struct {
+	// SpendLimit is the remaining amount of fees the granter pays.
+	// If empty, there is no limit.
+	SpendLimit std.Coins `json:"spend_limit,omitempty" yaml:"spend_limit,omitempty"`
+	// ExpirationHeight is the last block height at which the allowance can
+	// be used. If zero, the allowance does not expire.
+	ExpirationHeight int64 `json:"expiration_height,omitempty" yaml:"expiration_height,omitempty"`
+	// AllowedMsgs restricts the allowance to the messages of the given types,
+	// in the form <route>/<type>, like "bank/send" or "vm/exec".
+	// If empty, all the messages are allowed.
+	AllowedMsgs []string `json:"allowed_msgs,omitempty" yaml:"allowed_msgs,omitempty"`
+	// AllowedRealms restricts the allowance to the messages targeting the
+	// given package paths; see [PkgPathMsg].
+	// If empty, all the messages are allowed.
+	AllowedRealms []string `json:"allowed_realms,omitempty" yaml:"allowed_realms,omitempty"`
+}
+
+// PkgPathMsg is implemented by the messages targeting a package, like the
+// messages of the VM. Fee allowances restricted to some realms only cover
+// these messages.
+type PkgPathMsg interface {
+	std.Msg
+
+	// GetPkgPath returns the path of the package targeted by the message.
+	GetPkgPath() string
+}
+
+// ValidateBasic does a simple validation check of the allowance.
+func (fa FeeAllowance) ValidateBasic() error {
+	if len(fa.SpendLimit) > 0 {
+		if !fa.SpendLimit.IsValid() {
+			return std.ErrInvalidCoins(fa.SpendLimit.String())
+		}
+		if !fa.SpendLimit.IsAllPositive() {
+			return std.ErrInvalidCoins("spend limit must be positive")
+		}
+	}
+	if fa.ExpirationHeight < 0 {
+		return std.ErrUnknownRequest("expiration height must not be negative")
+	}
+	for _, typ := range fa.AllowedMsgs {
+		if typ == "" {
+			return std.ErrUnknownRequest("allowed message type must not be empty")
+		}
+	}
+	for _, path := range fa.AllowedRealms {
+		if path == "" {
+			return std.ErrUnknownRequest("allowed realm must not be empty")
+		}
+	}
+	return nil
+}
+
+// IsExpired returns whether the allowance can no longer be used at the given
+// block height.
+func (fa FeeAllowance) IsExpired(height int64) bool {
+	return fa.ExpirationHeight != 0 && height > fa.ExpirationHeight
+}
+
+// Allows returns an error if one of msgs is not allowed by fa.
+func (fa FeeAllowance) Allows(msgs []std.Msg) error {
+	for _, msg := range msgs {
+		if len(fa.AllowedMsgs) > 0 {
+			typ := msg.Route() + "/" + msg.Type()
+			if !contains(fa.AllowedMsgs, typ) {
+				return std.ErrUnauthorized(fmt.Sprintf("message %s not allowed by fee allowance", typ))
+			}
+		}
+		if len(fa.AllowedRealms) > 0 {
+			pm, ok := msg.(PkgPathMsg)
+			if !ok {
+				return std.ErrUnauthorized(fmt.Sprintf(
+					"message %s/%s not allowed by fee allowance restricted to realms", msg.Route(), msg.Type()))
+			}
+			if path := pm.GetPkgPath(); !contains(fa.AllowedRealms, path) {
+				return std.ErrUnauthorized(fmt.Sprintf("realm %s not allowed by fee allowance", path))
+			}
+		}
+	}
+	return nil
+}
+
+func contains(list []string, s string) bool {
+	for _, x := range list {
+		if x == s {
+			return true
+		}
+	}
+	return false
+}
+
+// GetFeeAllowance returns the fee allowance granted by granter to grantee, or
+// nil if there is none.
+func (ak AccountKeeper) GetFeeAllowance(ctx sdk.Context, granter, grantee crypto.Address) *FeeAllowance {
+	stor := ctx.GasStore(ak.key)
+	bz := stor.Get(FeeAllowanceStoreKey(granter, grantee))
+	if bz == nil {
+		return nil
+	}
+	fa := new(FeeAllowance)
+	amino.MustUnmarshalSized(bz, fa)
+	return fa
+}
+
+// SetFeeAllowance sets the fee allowance granted by granter to grantee,
+// replacing the previous one.
+func (ak AccountKeeper) SetFeeAllowance(ctx sdk.Context, granter, grantee crypto.Address, fa FeeAllowance) {
+	stor := ctx.GasStore(ak.key)
+	// NOTE: length-prefixed, as an unrestricted allowance is encoded as nil.
+	stor.Set(FeeAllowanceStoreKey(granter, grantee), amino.MustMarshalSized(fa))
+}
+
+// RemoveFeeAllowance removes the fee allowance granted by granter to grantee.
+func (ak AccountKeeper) RemoveFeeAllowance(ctx sdk.Context, granter, grantee crypto.Address) {
+	stor := ctx.GasStore(ak.key)
+	stor.Delete(FeeAllowanceStoreKey(granter, grantee))
+}
+
+// UseFeeAllowance checks that the fee allowance granted by granter to grantee
+// covers the fees of a transaction with msgs, and deducts fees from its spend
+// limit. The allowance is removed once it is spent.
+func (ak AccountKeeper) UseFeeAllowance(ctx sdk.Context, granter, grantee crypto.Address, fees std.Coins, msgs []std.Msg) error {
+	fa := ak.GetFeeAllowance(ctx, granter, grantee)
+	if fa == nil {
+		return std.ErrUnauthorized(fmt.Sprintf("no fee allowance granted by %s to %s", granter, grantee))
+	}
+	if fa.IsExpired(ctx.BlockHeight()) {
+		return std.ErrUnauthorized(fmt.Sprintf(
+			"fee allowance granted by %s to %s expired at height %d", granter, grantee, fa.ExpirationHeight))
+	}
+	if err := fa.Allows(msgs); err != nil {
+		return err
+	}
+	if len(fa.SpendLimit) == 0 || fees.IsZero() {
+		return nil
+	}
+
+	if !fa.SpendLimit.IsAllGTE(fees) {
+		return std.ErrInsufficientFunds(fmt.Sprintf(
+			"fee allowance exceeded; %s < %s", fa.SpendLimit, fees))
+	}
+	fa.SpendLimit = fa.SpendLimit.Sub(fees)
+	if fa.SpendLimit.IsZero() {
+		ak.RemoveFeeAllowance(ctx, granter, grantee)
+		return nil
+	}
+	ak.SetFeeAllowance(ctx, granter, grantee, *fa)
+	return nil
+}
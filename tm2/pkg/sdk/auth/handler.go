@@ -12,32 +12,116 @@ import (
 )
 
 type authHandler struct {
-	acck AccountKeeper
+	acck           AccountKeeper
+	sigGasConsumer SignatureVerificationGasConsumer
 }
 
-// NewHandler returns a handler for "auth" type messages.
-func NewHandler(acck AccountKeeper) authHandler {
+// NewHandler returns a handler for "auth" type messages. The signatures of
+// the new keys of MsgRotateKey consume gas with sigGasConsumer, which should
+// be the one of the ante handler.
+func NewHandler(acck AccountKeeper, sigGasConsumer SignatureVerificationGasConsumer) authHandler {
 	return authHandler{
-		acck: acck,
+		acck:           acck,
+		sigGasConsumer: sigGasConsumer,
 	}
 }
 
 func (ah authHandler) Process(ctx sdk.Context, msg std.Msg) sdk.Result {
-	// no messages supported yet.
-	errMsg := fmt.Sprintf("unrecognized auth message type: %T", msg)
-	return abciResult(std.ErrUnknownRequest(errMsg))
+	switch msg := msg.(type) {
+	case MsgGrantFeeAllowance:
+		return ah.handleMsgGrantFeeAllowance(ctx, msg)
+
+	case MsgRevokeFeeAllowance:
+		return ah.handleMsgRevokeFeeAllowance(ctx, msg)
+
+	case MsgRotateKey:
+		return ah.handleMsgRotateKey(ctx, msg)
+
+	default:
+		errMsg := fmt.Sprintf("unrecognized auth message type: %T", msg)
+		return abciResult(std.ErrUnknownRequest(errMsg))
+	}
+}
+
+// Handle MsgGrantFeeAllowance.
+func (ah authHandler) handleMsgGrantFeeAllowance(ctx sdk.Context, msg MsgGrantFeeAllowance) sdk.Result {
+	if msg.Allowance.IsExpired(ctx.BlockHeight()) {
+		return abciResult(std.ErrUnknownRequest(fmt.Sprintf(
+			"fee allowance already expired at height %d", msg.Allowance.ExpirationHeight)))
+	}
+
+	// Create the account of the grantee if it does not exist, so that it can
+	// sign its first transactions without being funded first.
+	if ah.acck.GetAccount(ctx, msg.Grantee) == nil {
+		acc := ah.acck.NewAccountWithAddress(ctx, msg.Grantee)
+		ah.acck.SetAccount(ctx, acc)
+	}
+
+	ah.acck.SetFeeAllowance(ctx, msg.Granter, msg.Grantee, msg.Allowance)
+	return sdk.Result{}
+}
+
+// Handle MsgRevokeFeeAllowance.
+func (ah authHandler) handleMsgRevokeFeeAllowance(ctx sdk.Context, msg MsgRevokeFeeAllowance) sdk.Result {
+	if ah.acck.GetFeeAllowance(ctx, msg.Granter, msg.Grantee) == nil {
+		return abciResult(std.ErrUnknownRequest(fmt.Sprintf(
+			"no fee allowance granted by %s to %s", msg.Granter, msg.Grantee)))
+	}
+
+	ah.acck.RemoveFeeAllowance(ctx, msg.Granter, msg.Grantee)
+	return sdk.Result{}
+}
+
+// Handle MsgRotateKey.
+func (ah authHandler) handleMsgRotateKey(ctx sdk.Context, msg MsgRotateKey) sdk.Result {
+	acc := ah.acck.GetAccount(ctx, msg.Address)
+	if acc == nil {
+		return abciResult(std.ErrUnknownAddress(fmt.Sprintf("account %s does not exist", msg.Address)))
+	}
+
+	// The account could no longer sign transactions with more keys.
+	params := ah.acck.GetParams(ctx)
+	if n := std.CountSubKeys(msg.PubKey); int64(n) > params.TxSigLimit {
+		return abciResult(std.ErrTooManySignatures(
+			fmt.Sprintf("public key has %d keys, limit: %d", n, params.TxSigLimit)))
+	}
+
+	// The new key must have signed the rotation.
+	if len(msg.Signature) == 0 {
+		return abciResult(std.ErrUnauthorized("missing signature of the new key"))
+	}
+	if res := ah.sigGasConsumer(ctx.GasMeter(), msg.Signature, msg.PubKey, params); !res.IsOK() {
+		return res
+	}
+	if err := msg.verifySignature(); err != nil {
+		return abciResult(err)
+	}
+
+	if err := acc.SetPubKey(msg.PubKey); err != nil {
+		return abciResult(std.ErrInternal(err.Error()))
+	}
+	ah.acck.SetAccount(ctx, acc)
+	return sdk.Result{}
 }
 
 //----------------------------------------
 // Query
 
-// query account path
-const QueryAccount = "accounts"
+// query paths
+const (
+	QueryAccount      = "accounts"
+	QueryTypedAccount = "typedaccounts"
+	QueryFeeAllowance = "feeallowances"
+)
 
 func (ah authHandler) Query(ctx sdk.Context, req abci.RequestQuery) (res abci.ResponseQuery) {
 	switch secondPart(req.Path) {
 	case QueryAccount:
-		return ah.queryAccount(ctx, req)
+		return ah.queryAccount(ctx, req, false)
+	case QueryTypedAccount:
+		return ah.queryAccount(ctx, req, true)
+	case QueryFeeAllowance:
+		return ah.queryFeeAllowance(ctx, req)
 	default:
 		res = sdk.ABCIResponseQueryFromError(
 			std.ErrUnknownRequest("unknown auth query endpoint"))
@@ -47,7 +131,9 @@ func (ah authHandler) Query(ctx sdk.Context, req abci.RequestQuery) (res abci.Re
 
 // queryAccount fetch an account for the supplied height.
 // Account address are passed as path component.
-func (ah authHandler) queryAccount(ctx sdk.Context, req abci.RequestQuery) (res abci.ResponseQuery) {
+// If typed, the account is encoded with its @type, so that any account type
+// can be decoded into a std.Account.
+func (ah authHandler) queryAccount(ctx sdk.Context, req abci.RequestQuery, typed bool) (res abci.ResponseQuery) {
 	// parse addr from path.
 	b32addr := thirdPart(req.Path)
 	addr, err := crypto.AddressFromBech32(b32addr)
@@ -59,8 +145,48 @@ func (ah authHandler) queryAccount(ctx sdk.Context, req abci.RequestQuery) (res
 	}
 
 	// get account from addr.
+	var bz []byte
+	if acc := ah.acck.GetAccount(ctx, addr); typed {
+		bz, err = amino.MarshalJSONIndent(&acc, "", "  ")
+	} else {
+		bz, err = amino.MarshalJSONIndent(acc, "", "  ")
+	}
+	if err != nil {
+		res = sdk.ABCIResponseQueryFromError(
+			std.ErrInternal(fmt.Sprintf("could not marshal result to JSON: %s", err.Error())))
+		return
+	}
+
+	res.Data = bz
+	return
+}
+
+// queryFeeAllowance fetches the fee allowance granted by an account to
+// another, for the supplied height.
+// Granter and grantee addresses are passed as path components.
+func (ah authHandler) queryFeeAllowance(ctx sdk.Context, req abci.RequestQuery) (res abci.ResponseQuery) {
+	// parse addrs from path.
+	parts := strings.Split(req.Path, "/")
+	if len(parts) != 4 {
+		res = sdk.ABCIResponseQueryFromError(
+			std.ErrUnknownRequest("expected path auth/feeallowances/<granter>/<grantee>"))
+		return
+	}
+	var addrs [2]crypto.Address
+	for i, b32addr := range parts[2:] {
+		addr, err := crypto.AddressFromBech32(b32addr)
+		if err != nil {
+			res = sdk.ABCIResponseQueryFromError(
+				std.ErrInvalidAddress(
+					"invalid query address " + b32addr))
+			return
+		}
+		addrs[i] = addr
+	}
+
+	// get allowance from addrs.
 	bz, err := amino.MarshalJSONIndent(
-		ah.acck.GetAccount(ctx, addr),
+		ah.acck.GetFeeAllowance(ctx, addrs[0], addrs[1]),
 		"", "  ")
 	if err != nil {
 		res = sdk.ABCIResponseQueryFromError(
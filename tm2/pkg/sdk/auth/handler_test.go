@@ -0,0 +1,252 @@
+package auth
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	bft "github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/gnolang/gno/tm2/pkg/crypto/ed25519"
+	"github.com/gnolang/gno/tm2/pkg/crypto/multisig"
+	"github.com/gnolang/gno/tm2/pkg/crypto/secp256k1"
+	"github.com/gnolang/gno/tm2/pkg/sdk"
+	tu "github.com/gnolang/gno/tm2/pkg/sdk/testutils"
+	"github.com/gnolang/gno/tm2/pkg/std"
+	"github.com/gnolang/gno/tm2/pkg/store"
+)
+
+func TestQueryAccount(t *testing.T) {
+	t.Parallel()
+
+	env := setupTestEnv()
+	h := NewHandler(env.acck, DefaultSigVerificationGasConsumer)
+	_, _, addr := tu.KeyTestPubAddr()
+
+	acc := NewContinuousVestingAccount(env.acck.NewAccountWithAddress(env.ctx, addr), tu.NewTestCoins(), 1, 2)
+	acc.SetCoins(tu.NewTestCoins())
+	env.acck.SetAccount(env.ctx, acc)
+
+	// the account is encoded without its type.
+	qres := h.Query(env.ctx, abci.RequestQuery{Path: fmt.Sprintf("auth/%s/%s", QueryAccount, addr)})
+	require.Nil(t, qres.Error)
+	assert.NotContains(t, string(qres.Data), "@type")
+	var cva ContinuousVestingAccount
+	require.NoError(t, amino.UnmarshalJSON(qres.Data, &cva))
+	assert.Equal(t, acc, &cva)
+
+	// the typed account can be decoded into a std.Account.
+	qres = h.Query(env.ctx, abci.RequestQuery{Path: fmt.Sprintf("auth/%s/%s", QueryTypedAccount, addr)})
+	require.Nil(t, qres.Error)
+	var typed std.Account
+	require.NoError(t, amino.UnmarshalJSON(qres.Data, &typed))
+	assert.Equal(t, acc, typed)
+}
+
+func TestFeeAllowanceMsgs(t *testing.T) {
+	t.Parallel()
+
+	env := setupTestEnv()
+	h := NewHandler(env.acck, DefaultSigVerificationGasConsumer)
+	_, _, granter := tu.KeyTestPubAddr()
+	_, _, grantee := tu.KeyTestPubAddr()
+
+	allowance := FeeAllowance{
+		SpendLimit:    std.NewCoins(std.NewCoin("atom", 1000)),
+		AllowedRealms: []string{"gno.land/r/demo/foo"},
+	}
+	msg := NewMsgGrantFeeAllowance(granter, grantee, allowance)
+	require.NoError(t, msg.ValidateBasic())
+
+	// the account of the grantee is created.
+	require.Nil(t, env.acck.GetAccount(env.ctx, grantee))
+	res := h.Process(env.ctx, msg)
+	require.True(t, res.IsOK(), res.Log)
+	require.NotNil(t, env.acck.GetAccount(env.ctx, grantee))
+
+	req := abci.RequestQuery{
+		Path: fmt.Sprintf("auth/%s/%s/%s", QueryFeeAllowance, granter, grantee),
+	}
+	qres := h.Query(env.ctx, req)
+	require.Nil(t, qres.Error)
+	var fa *FeeAllowance
+	require.NoError(t, amino.UnmarshalJSON(qres.Data, &fa))
+	assert.Equal(t, &allowance, fa)
+
+	res = h.Process(env.ctx, NewMsgRevokeFeeAllowance(granter, grantee))
+	require.True(t, res.IsOK(), res.Log)
+	assert.Nil(t, env.acck.GetFeeAllowance(env.ctx, granter, grantee))
+
+	// nothing left to revoke.
+	res = h.Process(env.ctx, NewMsgRevokeFeeAllowance(granter, grantee))
+	require.False(t, res.IsOK())
+
+	// expired allowance.
+	msg.Allowance.ExpirationHeight = env.ctx.BlockHeight()
+	res = h.Process(env.ctx.WithBlockHeader(&bft.Header{Height: 2}), msg)
+	require.False(t, res.IsOK())
+}
+
+func TestMsgGrantFeeAllowanceValidateBasic(t *testing.T) {
+	t.Parallel()
+
+	_, _, addr1 := tu.KeyTestPubAddr()
+	_, _, addr2 := tu.KeyTestPubAddr()
+
+	tests := []struct {
+		name  string
+		msg   MsgGrantFeeAllowance
+		valid bool
+	}{
+		{"unrestricted", NewMsgGrantFeeAllowance(addr1, addr2, FeeAllowance{}), true},
+		{"missing granter", MsgGrantFeeAllowance{Grantee: addr2}, false},
+		{"same grantee", NewMsgGrantFeeAllowance(addr1, addr1, FeeAllowance{}), false},
+		{"negative spend limit", NewMsgGrantFeeAllowance(addr1, addr2, FeeAllowance{
+			SpendLimit: std.Coins{{Denom: "atom", Amount: -1}},
+		}), false},
+		{"negative expiration", NewMsgGrantFeeAllowance(addr1, addr2, FeeAllowance{
+			ExpirationHeight: -1,
+		}), false},
+		{"empty msg type", NewMsgGrantFeeAllowance(addr1, addr2, FeeAllowance{
+			AllowedMsgs: []string{""},
+		}), false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tc.msg.ValidateBasic()
+			if tc.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+// signRotateKey returns the msg rotating the key of addr to the key of priv,
+// signed by priv.
+func signRotateKey(t *testing.T, addr crypto.Address, priv crypto.PrivKey) MsgRotateKey {
+	t.Helper()
+
+	msg := NewMsgRotateKey(addr, priv.PubKey())
+	sig, err := priv.Sign(msg.RotationSignBytes())
+	require.NoError(t, err)
+	msg.Signature = sig
+	return msg
+}
+
+func TestRotateKeyMsg(t *testing.T) {
+	t.Parallel()
+
+	env := setupTestEnv()
+	require.NoError(t, env.acck.SetParams(env.ctx, DefaultParams()))
+	// the signature of the new key consumes gas with the given consumer.
+	var verified int
+	sigGasConsumer := func(meter store.GasMeter, sig []byte, pubkey crypto.PubKey, params Params) sdk.Result {
+		verified++
+		return DefaultSigVerificationGasConsumer(meter, sig, pubkey, params)
+	}
+	h := NewHandler(env.acck, sigGasConsumer)
+	_, pub, addr := tu.KeyTestPubAddr()
+
+	newPriv := secp256k1.GenPrivKey()
+	newPub := newPriv.PubKey()
+	res := h.Process(env.ctx, signRotateKey(t, addr, newPriv))
+	require.False(t, res.IsOK())
+
+	acc := env.acck.NewAccountWithAddress(env.ctx, addr)
+	require.NoError(t, acc.SetPubKey(pub))
+	acc.SetCoins(tu.NewTestCoins())
+	env.acck.SetAccount(env.ctx, acc)
+
+	// the new key must sign the rotation.
+	res = h.Process(env.ctx, NewMsgRotateKey(addr, newPub))
+	require.False(t, res.IsOK())
+	msg := signRotateKey(t, addr, secp256k1.GenPrivKey())
+	msg.PubKey = newPub
+	res = h.Process(env.ctx, msg)
+	require.False(t, res.IsOK())
+	assert.Equal(t, pub, env.acck.GetAccount(env.ctx, addr).GetPubKey())
+
+	verified = 0
+	res = h.Process(env.ctx, signRotateKey(t, addr, newPriv))
+	require.True(t, res.IsOK(), res.Log)
+	assert.Equal(t, 1, verified)
+	acc = env.acck.GetAccount(env.ctx, addr)
+	assert.Equal(t, addr, acc.GetAddress())
+	assert.Equal(t, newPub, acc.GetPubKey())
+	assert.Equal(t, tu.NewTestCoins(), acc.GetCoins())
+
+	// too many keys to sign transactions.
+	var pubs []crypto.PubKey
+	for i := int64(0); i <= DefaultTxSigLimit; i++ {
+		pubs = append(pubs, secp256k1.GenPrivKey().PubKey())
+	}
+	// the limit is checked before the signature is verified.
+	verified = 0
+	msg = NewMsgRotateKey(addr, multisig.NewPubKeyMultisigThreshold(2, pubs))
+	msg.Signature = []byte("signature")
+	res = h.Process(env.ctx, msg)
+	require.False(t, res.IsOK())
+	assert.Equal(t, 0, verified)
+	assert.Equal(t, newPub, env.acck.GetAccount(env.ctx, addr).GetPubKey())
+}
+
+func TestMsgRotateKeyValidateBasic(t *testing.T) {
+	t.Parallel()
+
+	_, _, addr := tu.KeyTestPubAddr()
+	priv1, priv2 := secp256k1.GenPrivKey(), secp256k1.GenPrivKey()
+	pub1, pub2 := priv1.PubKey(), priv2.PubKey()
+
+	// a multisig key signs with a threshold of its keys.
+	multisigKeys := []crypto.PubKey{pub1, pub2}
+	multisigPub := multisig.NewPubKeyMultisigThreshold(2, multisigKeys)
+	multisigMsg := NewMsgRotateKey(addr, multisigPub)
+	mSig := multisig.NewMultisig(2)
+	for _, priv := range []crypto.PrivKey{priv1, priv2} {
+		sig, err := priv.Sign(multisigMsg.RotationSignBytes())
+		require.NoError(t, err)
+		require.NoError(t, mSig.AddSignatureFromPubKey(sig, priv.PubKey(), multisigKeys))
+	}
+	multisigMsg.Signature = mSig.Marshal()
+
+	unsigned := NewMsgRotateKey(addr, pub1)
+	wrongSigner := signRotateKey(t, addr, priv2)
+	wrongSigner.PubKey = pub1
+	edPriv := ed25519.GenPrivKey()
+
+	tests := []struct {
+		name  string
+		msg   MsgRotateKey
+		valid bool
+	}{
+		{"secp256k1", signRotateKey(t, addr, priv1), true},
+		{"multisig", multisigMsg, true},
+		{"missing address", MsgRotateKey{PubKey: pub1}, false},
+		{"missing key", MsgRotateKey{Address: addr}, false},
+		{"missing signature", unsigned, false},
+		{"wrong signer, verified by the handler", wrongSigner, true},
+		{"ed25519", signRotateKey(t, addr, edPriv), false},
+		{"invalid threshold", NewMsgRotateKey(addr, multisig.PubKeyMultisigThreshold{K: 3, PubKeys: []crypto.PubKey{pub1, pub2}}), false},
+		{"ed25519 multisig", NewMsgRotateKey(addr, multisig.NewPubKeyMultisigThreshold(1, []crypto.PubKey{pub1, edPriv.PubKey()})), false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tc.msg.ValidateBasic()
+			if tc.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
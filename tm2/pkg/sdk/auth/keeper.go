@@ -98,6 +98,33 @@ func (ak AccountKeeper) RemoveAccount(ctx sdk.Context, acc std.Account) {
 	stor.Delete(AddressStoreKey(addr))
 }
 
+// pendingTx is the record of a tx pending in the mempool, for a tx with the
+// same signer and sequence to replace it.
+type pendingTx struct {
+	Priority int64
+	Fee      std.Coins
+}
+
+// getPendingTx returns the record of the tx of signer with the given sequence,
+// or nil if there is none. The records are only kept in the CheckTx state,
+// without consuming gas.
+func (ak AccountKeeper) getPendingTx(ctx sdk.Context, signer crypto.Address, sequence uint64) *pendingTx {
+	stor := ctx.Store(ak.key)
+	bz := stor.Get(PendingTxStoreKey(signer, sequence))
+	if bz == nil {
+		return nil
+	}
+	ptx := new(pendingTx)
+	amino.MustUnmarshalSized(bz, ptx)
+	return ptx
+}
+
+// setPendingTx sets the record of the tx of signer with the given sequence.
+func (ak AccountKeeper) setPendingTx(ctx sdk.Context, signer crypto.Address, sequence uint64, ptx pendingTx) {
+	stor := ctx.Store(ak.key)
+	stor.Set(PendingTxStoreKey(signer, sequence), amino.MustMarshalSized(ptx))
+}
+
 // IterateAccounts implements AccountKeeper.
 func (ak AccountKeeper) IterateAccounts(ctx sdk.Context, process func(std.Account) (stop bool)) {
 	stor := ctx.GasStore(ak.key)
@@ -0,0 +1,193 @@
+package auth
+
+import (
+	"fmt"
+
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/gnolang/gno/tm2/pkg/crypto/multisig"
+	"github.com/gnolang/gno/tm2/pkg/crypto/secp256k1"
+	"github.com/gnolang/gno/tm2/pkg/std"
+)
+
+// RouterKey is the name of the auth module
+const RouterKey = ModuleName
+
+// MsgGrantFeeAllowance - grants a fee allowance to the grantee, replacing
+// the one previously granted by the granter, if any.
+type MsgGrantFeeAllowance struct {
+	Granter   crypto.Address `json:"granter" yaml:"granter"`
+	Grantee   crypto.Address `json:"grantee" yaml:"grantee"`
+	Allowance FeeAllowance   `json:"allowance" yaml:"allowance"`
+}
+
+var _ std.Msg = MsgGrantFeeAllowance{}
+
+// NewMsgGrantFeeAllowance - construct a msg granting a fee allowance.
+func NewMsgGrantFeeAllowance(granter, grantee crypto.Address, allowance FeeAllowance) MsgGrantFeeAllowance {
+	return MsgGrantFeeAllowance{Granter: granter, Grantee: grantee, Allowance: allowance}
+}
+
+// Route Implements Msg.
+func (msg MsgGrantFeeAllowance) Route() string { return RouterKey }
+
+// Type Implements Msg.
+func (msg MsgGrantFeeAllowance) Type() string { return "grant_fee_allowance" }
+
+// ValidateBasic Implements Msg.
+func (msg MsgGrantFeeAllowance) ValidateBasic() error {
+	if msg.Granter.IsZero() {
+		return std.ErrInvalidAddress("missing granter address")
+	}
+	if msg.Grantee.IsZero() {
+		return std.ErrInvalidAddress("missing grantee address")
+	}
+	if msg.Granter == msg.Grantee {
+		return std.ErrInvalidAddress("granter and grantee must differ")
+	}
+	return msg.Allowance.ValidateBasic()
+}
+
+// GetSignBytes Implements Msg.
+func (msg MsgGrantFeeAllowance) GetSignBytes() []byte {
+	return std.MustSortJSON(amino.MustMarshalJSON(msg))
+}
+
+// GetSigners Implements Msg.
+func (msg MsgGrantFeeAllowance) GetSigners() []crypto.Address {
+	return []crypto.Address{msg.Granter}
+}
+
+// MsgRevokeFeeAllowance - revokes the fee allowance granted to the grantee.
+type MsgRevokeFeeAllowance struct {
+	Granter crypto.Address `json:"granter" yaml:"granter"`
+	Grantee crypto.Address `json:"grantee" yaml:"grantee"`
+}
+
+var _ std.Msg = MsgRevokeFeeAllowance{}
+
+// NewMsgRevokeFeeAllowance - construct a msg revoking a fee allowance.
+func NewMsgRevokeFeeAllowance(granter, grantee crypto.Address) MsgRevokeFeeAllowance {
+	return MsgRevokeFeeAllowance{Granter: granter, Grantee: grantee}
+}
+
+// Route Implements Msg.
+func (msg MsgRevokeFeeAllowance) Route() string { return RouterKey }
+
+// Type Implements Msg.
+func (msg MsgRevokeFeeAllowance) Type() string { return "revoke_fee_allowance" }
+
+// ValidateBasic Implements Msg.
+func (msg MsgRevokeFeeAllowance) ValidateBasic() error {
+	if msg.Granter.IsZero() {
+		return std.ErrInvalidAddress("missing granter address")
+	}
+	if msg.Grantee.IsZero() {
+		return std.ErrInvalidAddress("missing grantee address")
+	}
+	return nil
+}
+
+// GetSignBytes Implements Msg.
+func (msg MsgRevokeFeeAllowance) GetSignBytes() []byte {
+	return std.MustSortJSON(amino.MustMarshalJSON(msg))
+}
+
+// GetSigners Implements Msg.
+func (msg MsgRevokeFeeAllowance) GetSigners() []crypto.Address {
+	return []crypto.Address{msg.Granter}
+}
+
+// MsgRotateKey - replaces the public key of an account, which keeps its
+// address, coins and sequence. The new key can be a threshold multisig key.
+// The rotation is signed by the new key, see RotationSignBytes, so that an
+// account can only be given a key controlled by its owner.
+type MsgRotateKey struct {
+	Address   crypto.Address `json:"address" yaml:"address"`
+	PubKey    crypto.PubKey  `json:"pub_key" yaml:"pub_key"`
+	Signature []byte         `json:"signature" yaml:"signature"`
+}
+
+var _ std.Msg = MsgRotateKey{}
+
+// NewMsgRotateKey - construct a msg rotating the key of an account, without
+// the signature of the new key.
+func NewMsgRotateKey(addr crypto.Address, pubKey crypto.PubKey) MsgRotateKey {
+	return MsgRotateKey{Address: addr, PubKey: pubKey}
+}
+
+// Route Implements Msg.
+func (msg MsgRotateKey) Route() string { return RouterKey }
+
+// Type Implements Msg.
+func (msg MsgRotateKey) Type() string { return "rotate_key" }
+
+// ValidateBasic Implements Msg.
+func (msg MsgRotateKey) ValidateBasic() error {
+	if msg.Address.IsZero() {
+		return std.ErrInvalidAddress("missing account address")
+	}
+	if msg.PubKey == nil {
+		return std.ErrInvalidPubKey("missing public key")
+	}
+	if err := validateAccountPubKey(msg.PubKey); err != nil {
+		return err
+	}
+	// the signature is verified by the handler, which charges gas for it.
+	if len(msg.Signature) == 0 {
+		return std.ErrUnauthorized("missing signature of the new key")
+	}
+	return nil
+}
+
+// RotationSignBytes returns the bytes signed by the new key, which are the
+// sign bytes of the msg without its signature.
+func (msg MsgRotateKey) RotationSignBytes() []byte {
+	msg.Signature = nil
+	return msg.GetSignBytes()
+}
+
+// verifySignature returns an error if the msg is not signed by the new key.
+func (msg MsgRotateKey) verifySignature() error {
+	if !msg.PubKey.VerifyBytes(msg.RotationSignBytes(), msg.Signature) {
+		return std.ErrUnauthorized("invalid signature of the new key")
+	}
+	return nil
+}
+
+// GetSignBytes Implements Msg.
+func (msg MsgRotateKey) GetSignBytes() []byte {
+	return std.MustSortJSON(amino.MustMarshalJSON(msg))
+}
+
+// GetSigners Implements Msg.
+func (msg MsgRotateKey) GetSigners() []crypto.Address {
+	return []crypto.Address{msg.Address}
+}
+
+// validateAccountPubKey returns an error if pubKey cannot sign transactions,
+// so that an account is not locked by rotating its key.
+func validateAccountPubKey(pubKey crypto.PubKey) error {
+	switch pubKey := pubKey.(type) {
+	case secp256k1.PubKeySecp256k1:
+		return nil
+
+	case multisig.PubKeyMultisigThreshold:
+		if pubKey.K == 0 || int(pubKey.K) > len(pubKey.PubKeys) {
+			return std.ErrInvalidPubKey(fmt.Sprintf(
+				"invalid multisig threshold %d of %d keys", pubKey.K, len(pubKey.PubKeys)))
+		}
+		for _, subKey := range pubKey.PubKeys {
+			if subKey == nil {
+				return std.ErrInvalidPubKey("missing multisig public key")
+			}
+			if err := validateAccountPubKey(subKey); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	default:
+		return std.ErrInvalidPubKey(fmt.Sprintf("unsupported public key type %T", pubKey))
+	}
+}
@@ -0,0 +1,23 @@
+package auth
+
+import (
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	"github.com/gnolang/gno/tm2/pkg/std"
+)
+
+var Package = amino.RegisterPackage(amino.NewPackage(
+	"github.com/gnolang/gno/tm2/pkg/sdk/auth",
+	"auth",
+	amino.GetCallersDirname(),
+).WithDependencies(
+	std.Package,
+).WithTypes(
+	FeeAllowance{}, "FeeAllowance",
+	MsgGrantFeeAllowance{}, "MsgGrantFeeAllowance",
+	MsgRevokeFeeAllowance{}, "MsgRevokeFeeAllowance",
+	MsgRotateKey{}, "MsgRotateKey",
+	BaseVestingAccount{}, "BaseVestingAccount",
+	&ContinuousVestingAccount{}, "ContinuousVestingAccount",
+	&PeriodicVestingAccount{}, "PeriodicVestingAccount",
+	VestingPeriod{}, "VestingPeriod",
+))
@@ -0,0 +1,269 @@
+package auth
+
+import (
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/gnolang/gno/tm2/pkg/std"
+)
+
+// VestingAccount is an account holding coins which are locked until they
+// vest, following a schedule set when the account is created. Locked coins
+// count in the balance of the account, but they cannot be spent.
+type VestingAccount interface {
+	std.Account
+
+	// GetOriginalVesting returns the coins subject to the vesting schedule.
+	GetOriginalVesting() std.Coins
+	// GetStartTime returns the unix time at which vesting starts.
+	GetStartTime() int64
+	// GetEndTime returns the unix time at which all the coins are vested.
+	GetEndTime() int64
+
+	// LockedCoins returns the coins which are not vested at blockTime.
+	LockedCoins(blockTime time.Time) std.Coins
+	// Validate checks the vesting schedule, and that the balance of the
+	// account covers the original vesting amount.
+	Validate() error
+}
+
+var (
+	_ VestingAccount = &ContinuousVestingAccount{}
+	_ VestingAccount = &PeriodicVestingAccount{}
+)
+
+// SpendableCoins returns the coins of acc which can be spent at blockTime.
+// For accounts which are not vesting accounts, it is the balance of acc.
+func SpendableCoins(acc std.Account, blockTime time.Time) std.Coins {
+	coins := acc.GetCoins()
+	vacc, ok := acc.(VestingAccount)
+	if !ok {
+		return coins
+	}
+	locked := vacc.LockedCoins(blockTime)
+	if locked.IsZero() {
+		return coins
+	}
+
+	// The locked coins can exceed the balance, if coins were added to the
+	// account after the vesting started; so clamp each denom at zero.
+	spendable := std.Coins{}
+	for _, coin := range coins {
+		amount := coin.Amount - locked.AmountOf(coin.Denom)
+		if amount > 0 {
+			spendable = append(spendable, std.NewCoin(coin.Denom, amount))
+		}
+	}
+	return spendable
+}
+
+//----------------------------------------
+// BaseVestingAccount
+
+// BaseVestingAccount implements the parts shared by the vesting accounts.
+type BaseVestingAccount struct {
+	std.BaseAccount
+
+	OriginalVesting std.Coins `json:"original_vesting" yaml:"original_vesting"`
+	StartTime       int64     `json:"start_time" yaml:"start_time"`
+	EndTime         int64     `json:"end_time" yaml:"end_time"`
+}
+
+func newBaseVestingAccount(acc std.Account, originalVesting std.Coins, startTime, endTime int64) BaseVestingAccount {
+	return BaseVestingAccount{
+		BaseAccount: *std.NewBaseAccount(
+			acc.GetAddress(), acc.GetCoins(), acc.GetPubKey(),
+			acc.GetAccountNumber(), acc.GetSequence(),
+		),
+		OriginalVesting: originalVesting,
+		StartTime:       startTime,
+		EndTime:         endTime,
+	}
+}
+
+// GetOriginalVesting - Implements VestingAccount.
+func (bva BaseVestingAccount) GetOriginalVesting() std.Coins {
+	return bva.OriginalVesting
+}
+
+// GetStartTime - Implements VestingAccount.
+func (bva BaseVestingAccount) GetStartTime() int64 {
+	return bva.StartTime
+}
+
+// GetEndTime - Implements VestingAccount.
+func (bva BaseVestingAccount) GetEndTime() int64 {
+	return bva.EndTime
+}
+
+// Validate checks that the vesting schedule is well-formed, and that the
+// balance of the account covers the original vesting amount.
+func (bva BaseVestingAccount) Validate() error {
+	if !bva.OriginalVesting.IsValid() || !bva.OriginalVesting.IsAllPositive() {
+		return std.ErrInvalidCoins(fmt.Sprintf("invalid original vesting: %s", bva.OriginalVesting))
+	}
+	if bva.StartTime < 0 {
+		return std.ErrUnknownRequest("vesting start time must not be negative")
+	}
+	if bva.EndTime < bva.StartTime {
+		return std.ErrUnknownRequest("vesting end time must not be before start time")
+	}
+	if !bva.Coins.IsAllGTE(bva.OriginalVesting) {
+		return std.ErrInsufficientCoins(
+			fmt.Sprintf("account coins do not cover original vesting; %s < %s", bva.Coins, bva.OriginalVesting),
+		)
+	}
+	return nil
+}
+
+func (bva BaseVestingAccount) String() string {
+	return fmt.Sprintf(`%s
+  OriginalVesting: %s
+  StartTime:       %d
+  EndTime:         %d`,
+		bva.BaseAccount.String(), bva.OriginalVesting, bva.StartTime, bva.EndTime,
+	)
+}
+
+//----------------------------------------
+// ContinuousVestingAccount
+
+// ContinuousVestingAccount is a vesting account whose coins vest linearly
+// between StartTime and EndTime.
+type ContinuousVestingAccount struct {
+	BaseVestingAccount
+}
+
+// NewContinuousVestingAccount returns a vesting account with the fields of
+// acc, which vests originalVesting linearly from startTime to endTime.
+func NewContinuousVestingAccount(acc std.Account, originalVesting std.Coins, startTime, endTime int64) *ContinuousVestingAccount {
+	return &ContinuousVestingAccount{
+		BaseVestingAccount: newBaseVestingAccount(acc, originalVesting, startTime, endTime),
+	}
+}
+
+// LockedCoins - Implements VestingAccount.
+func (cva ContinuousVestingAccount) LockedCoins(blockTime time.Time) std.Coins {
+	now := blockTime.Unix()
+	switch {
+	case now >= cva.EndTime:
+		return std.Coins{}
+	case now <= cva.StartTime:
+		return cva.OriginalVesting
+	}
+
+	// locked = original * (end - now) / (end - start), rounded up so that
+	// coins never vest earlier than scheduled.
+	var (
+		remaining = big.NewInt(cva.EndTime - now)
+		duration  = big.NewInt(cva.EndTime - cva.StartTime)
+		locked    = std.Coins{}
+	)
+	for _, coin := range cva.OriginalVesting {
+		amount := new(big.Int).Mul(big.NewInt(coin.Amount), remaining)
+		amount.Add(amount, duration).Sub(amount, big.NewInt(1))
+		amount.Quo(amount, duration)
+		if amount.Sign() > 0 {
+			locked = append(locked, std.NewCoin(coin.Denom, amount.Int64()))
+		}
+	}
+	return locked
+}
+
+//----------------------------------------
+// PeriodicVestingAccount
+
+// VestingPeriod is a step of the schedule of a PeriodicVestingAccount:
+// Amount vests Length seconds after the end of the previous period.
+type VestingPeriod struct {
+	Length int64     `json:"length" yaml:"length"`
+	Amount std.Coins `json:"amount" yaml:"amount"`
+}
+
+// PeriodicVestingAccount is a vesting account whose coins vest in steps, at
+// the end of each of its periods.
+type PeriodicVestingAccount struct {
+	BaseVestingAccount
+
+	Periods []VestingPeriod `json:"periods" yaml:"periods"`
+}
+
+// NewPeriodicVestingAccount returns a vesting account with the fields of
+// acc, which vests the amounts of periods one after the other, starting at
+// startTime. The original vesting is the sum of the amounts of periods.
+func NewPeriodicVestingAccount(acc std.Account, startTime int64, periods []VestingPeriod) *PeriodicVestingAccount {
+	var (
+		originalVesting = std.Coins{}
+		endTime         = startTime
+	)
+	for _, period := range periods {
+		originalVesting = originalVesting.AddUnsafe(period.Amount)
+		endTime += period.Length
+	}
+	return &PeriodicVestingAccount{
+		BaseVestingAccount: newBaseVestingAccount(acc, originalVesting, startTime, endTime),
+		Periods:            periods,
+	}
+}
+
+// Validate checks that the periods match the vesting schedule, in addition
+// to the checks of BaseVestingAccount.
+func (pva PeriodicVestingAccount) Validate() error {
+	if len(pva.Periods) == 0 {
+		return std.ErrUnknownRequest("periodic vesting account must have at least one period")
+	}
+	var (
+		total   = std.Coins{}
+		endTime = pva.StartTime
+	)
+	for _, period := range pva.Periods {
+		if period.Length <= 0 {
+			return std.ErrUnknownRequest("vesting period length must be positive")
+		}
+		if !period.Amount.IsValid() || !period.Amount.IsAllPositive() {
+			return std.ErrInvalidCoins(fmt.Sprintf("invalid vesting period amount: %s", period.Amount))
+		}
+		total = total.Add(period.Amount)
+		endTime += period.Length
+	}
+	if !total.IsAllGTE(pva.OriginalVesting) || !pva.OriginalVesting.IsAllGTE(total) {
+		return std.ErrInvalidCoins(
+			fmt.Sprintf("vesting periods do not sum up to original vesting; %s != %s", total, pva.OriginalVesting),
+		)
+	}
+	if endTime != pva.EndTime {
+		return std.ErrUnknownRequest("vesting periods do not match vesting end time")
+	}
+	return pva.BaseVestingAccount.Validate()
+}
+
+// LockedCoins - Implements VestingAccount.
+func (pva PeriodicVestingAccount) LockedCoins(blockTime time.Time) std.Coins {
+	now := blockTime.Unix()
+	if now <= pva.StartTime {
+		return pva.OriginalVesting
+	}
+
+	var (
+		vested  = std.Coins{}
+		endTime = pva.StartTime
+	)
+	for _, period := range pva.Periods {
+		endTime += period.Length
+		if now < endTime {
+			break
+		}
+		vested = vested.Add(period.Amount)
+	}
+	return pva.OriginalVesting.Sub(vested)
+}
+
+// String implements fmt.Stringer
+func (pva PeriodicVestingAccount) String() string {
+	s := pva.BaseVestingAccount.String() + "\n  Periods:"
+	for _, period := range pva.Periods {
+		s += fmt.Sprintf("\n    %ds: %s", period.Length, period.Amount)
+	}
+	return s
+}
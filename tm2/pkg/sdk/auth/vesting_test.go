@@ -0,0 +1,172 @@
+package auth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	bft "github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/gnolang/gno/tm2/pkg/std"
+)
+
+func newTestAccount(coins std.Coins) std.Account {
+	addr := crypto.AddressFromPreimage([]byte("vesting"))
+	return std.NewBaseAccount(addr, coins, nil, 1, 0)
+}
+
+func TestContinuousVestingAccount(t *testing.T) {
+	t.Parallel()
+
+	coins := std.NewCoins(std.NewCoin("atom", 1000), std.NewCoin("ugnot", 100))
+	acc := NewContinuousVestingAccount(newTestAccount(coins), coins, 1000, 2000)
+	require.NoError(t, acc.Validate())
+
+	tests := []struct {
+		name   string
+		time   int64
+		locked std.Coins
+	}{
+		{"before start", 500, coins},
+		{"at start", 1000, coins},
+		{"a quarter", 1250, std.NewCoins(std.NewCoin("atom", 750), std.NewCoin("ugnot", 75))},
+		{"rounded up", 1999, std.NewCoins(std.NewCoin("atom", 1), std.NewCoin("ugnot", 1))},
+		{"at end", 2000, std.Coins{}},
+		{"after end", 3000, std.Coins{}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			blockTime := time.Unix(tc.time, 0)
+			locked := acc.LockedCoins(blockTime)
+			assert.True(t, tc.locked.IsEqual(locked), "want %s, got %s", tc.locked, locked)
+			assert.True(t, coins.Sub(tc.locked).IsEqual(SpendableCoins(acc, blockTime)))
+		})
+	}
+}
+
+func TestPeriodicVestingAccount(t *testing.T) {
+	t.Parallel()
+
+	coins := std.NewCoins(std.NewCoin("ugnot", 300))
+	periods := []VestingPeriod{
+		{Length: 100, Amount: std.NewCoins(std.NewCoin("ugnot", 100))},
+		{Length: 50, Amount: std.NewCoins(std.NewCoin("ugnot", 200))},
+	}
+	acc := NewPeriodicVestingAccount(newTestAccount(coins), 1000, periods)
+	require.NoError(t, acc.Validate())
+	assert.Equal(t, int64(1150), acc.GetEndTime())
+	assert.True(t, coins.IsEqual(acc.GetOriginalVesting()))
+
+	tests := []struct {
+		name   string
+		time   int64
+		locked std.Coins
+	}{
+		{"before start", 500, coins},
+		{"first period", 1099, coins},
+		{"first period vested", 1100, std.NewCoins(std.NewCoin("ugnot", 200))},
+		{"second period", 1149, std.NewCoins(std.NewCoin("ugnot", 200))},
+		{"all vested", 1150, std.Coins{}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			locked := acc.LockedCoins(time.Unix(tc.time, 0))
+			assert.True(t, tc.locked.IsEqual(locked), "want %s, got %s", tc.locked, locked)
+		})
+	}
+}
+
+func TestVestingAccountValidate(t *testing.T) {
+	t.Parallel()
+
+	coins := std.NewCoins(std.NewCoin("ugnot", 100))
+	period := VestingPeriod{Length: 10, Amount: coins}
+
+	tests := []struct {
+		name string
+		acc  interface{ Validate() error }
+	}{
+		{"no original vesting", NewContinuousVestingAccount(newTestAccount(coins), std.Coins{}, 0, 10)},
+		{"negative start time", NewContinuousVestingAccount(newTestAccount(coins), coins, -1, 10)},
+		{"end before start", NewContinuousVestingAccount(newTestAccount(coins), coins, 10, 0)},
+		{"coins do not cover vesting", NewContinuousVestingAccount(newTestAccount(coins), coins.Add(coins), 0, 10)},
+		{"no periods", NewPeriodicVestingAccount(newTestAccount(coins), 0, nil)},
+		{"empty period", NewPeriodicVestingAccount(newTestAccount(coins), 0, []VestingPeriod{period, {Length: 10}})},
+		{"zero length period", NewPeriodicVestingAccount(newTestAccount(coins), 0, []VestingPeriod{{Amount: coins}})},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Error(t, tc.acc.Validate())
+		})
+	}
+
+	// periods not matching the original vesting
+	acc := NewPeriodicVestingAccount(newTestAccount(coins), 0, []VestingPeriod{period})
+	acc.OriginalVesting = std.NewCoins(std.NewCoin("atom", 100))
+	assert.Error(t, acc.Validate())
+}
+
+func TestSpendableCoins(t *testing.T) {
+	t.Parallel()
+
+	vesting := std.NewCoins(std.NewCoin("ugnot", 100))
+	acc := NewContinuousVestingAccount(newTestAccount(vesting), vesting, 1000, 2000)
+
+	// coins received by the account, in addition to the original vesting,
+	// are spendable.
+	acc.Coins = std.NewCoins(std.NewCoin("atom", 10), std.NewCoin("ugnot", 120))
+	spendable := SpendableCoins(acc, time.Unix(1500, 0))
+	assert.Equal(t, "10atom,70ugnot", spendable.String())
+
+	// locked coins can exceed the balance, after spending the vested coins.
+	acc.Coins = std.NewCoins(std.NewCoin("ugnot", 40))
+	spendable = SpendableCoins(acc, time.Unix(1500, 0))
+	assert.True(t, spendable.IsZero())
+
+	// non-vesting accounts can spend their whole balance.
+	base := newTestAccount(vesting)
+	assert.True(t, vesting.IsEqual(SpendableCoins(base, time.Unix(0, 0))))
+}
+
+func TestVestingAccountKeeper(t *testing.T) {
+	t.Parallel()
+
+	env := setupTestEnv()
+	coins := std.NewCoins(std.NewCoin("ugnot", 100))
+	acc := NewPeriodicVestingAccount(newTestAccount(coins), 1000, []VestingPeriod{
+		{Length: 100, Amount: coins},
+	})
+	env.acck.SetAccount(env.ctx, acc)
+
+	got := env.acck.GetAccount(env.ctx, acc.GetAddress())
+	require.IsType(t, &PeriodicVestingAccount{}, got)
+	assert.Equal(t, acc, got)
+}
+
+func TestDeductFeesVestingAccount(t *testing.T) {
+	t.Parallel()
+
+	env := setupTestEnv()
+	ctx := env.ctx.WithBlockHeader(&bft.Header{Height: 1, ChainID: "test-chain-id", Time: time.Unix(1500, 0)})
+
+	coins := std.NewCoins(std.NewCoin("ugnot", 100))
+	acc := NewContinuousVestingAccount(newTestAccount(coins), coins, 1000, 2000)
+	env.acck.SetAccount(ctx, acc)
+
+	// only half of the coins are vested
+	res := DeductFees(env.bank, ctx, acc, std.NewCoins(std.NewCoin("ugnot", 51)))
+	require.False(t, res.IsOK())
+	assert.IsType(t, std.InsufficientFundsError{}, res.Error)
+
+	res = DeductFees(env.bank, ctx, acc, std.NewCoins(std.NewCoin("ugnot", 50)))
+	require.True(t, res.IsOK(), res.Log)
+	assert.Equal(t, int64(50), env.acck.GetAccount(ctx, acc.GetAddress()).GetCoins().AmountOf("ugnot"))
+}
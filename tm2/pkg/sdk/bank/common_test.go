@@ -17,6 +17,7 @@ import (
 
 type testEnv struct {
 	ctx  sdk.Context
+	key  store.StoreKey
 	bank BankKeeper
 	acck auth.AccountKeeper
 }
@@ -35,7 +36,7 @@ func setupTestEnv() testEnv {
 		authCapKey, paramk, std.ProtoBaseAccount,
 	)
 
-	bank := NewBankKeeper(acck)
+	bank := NewBankKeeper(authCapKey, acck)
 
-	return testEnv{ctx: ctx, bank: bank, acck: acck}
+	return testEnv{ctx: ctx, key: authCapKey, bank: bank, acck: acck}
 }
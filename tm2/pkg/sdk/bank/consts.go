@@ -2,4 +2,12 @@ package bank
 
 const (
 	ModuleName = "bank"
+
+	// DenomMetadataStoreKeyPrefix is the prefix for the denom-metadata-by-denom store
+	DenomMetadataStoreKeyPrefix = "/dm/"
 )
+
+// DenomMetadataStoreKey returns the key of the metadata of denom.
+func DenomMetadataStoreKey(denom string) []byte {
+	return append([]byte(DenomMetadataStoreKeyPrefix), []byte(denom)...)
+}
@@ -0,0 +1,92 @@
+package bank
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gnolang/gno/tm2/pkg/std"
+)
+
+// MaxDenomExponent is the maximum exponent of a display unit; int64 amounts
+// have at most 19 digits.
+const MaxDenomExponent = 18
+
+// DenomMetadata describes a coin denomination, so that clients can render
+// amounts in a human readable way.
+type DenomMetadata struct {
+	// Base is the denom of the coins, in their smallest unit, like "ugnot".
+	Base string `json:"base" yaml:"base"`
+	// Display is the unit in which amounts are displayed, like "gnot".
+	Display string `json:"display" yaml:"display"`
+	// Exponent is the number of decimals of the display unit:
+	// 1 Display = 10^Exponent Base.
+	Exponent uint32 `json:"exponent" yaml:"exponent"`
+	// Description is a short description of the coin.
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+	// Issuer is the package path of the realm issuing the coins.
+	// It is empty for the native coins.
+	Issuer string `json:"issuer,omitempty" yaml:"issuer,omitempty"`
+}
+
+// ValidateBasic does a simple validation check of the metadata.
+func (md DenomMetadata) ValidateBasic() error {
+	if !(std.Coin{Denom: md.Base, Amount: 1}).IsValid() {
+		return std.ErrInvalidCoins(fmt.Sprintf("invalid base denom: %q", md.Base))
+	}
+	if md.Display == "" || strings.TrimSpace(md.Display) != md.Display {
+		return std.ErrUnknownRequest(fmt.Sprintf("invalid display unit: %q", md.Display))
+	}
+	if md.Exponent > MaxDenomExponent {
+		return std.ErrUnknownRequest(
+			fmt.Sprintf("exponent must not be greater than %d", MaxDenomExponent),
+		)
+	}
+	return nil
+}
+
+// FormatAmount returns amount, in base units, as a decimal number of display
+// units followed by the display unit, like "1.5gnot" for 1500000ugnot.
+func (md DenomMetadata) FormatAmount(amount int64) string {
+	digits := strconv.FormatUint(absUint64(amount), 10)
+	exp := int(md.Exponent)
+	if len(digits) <= exp {
+		digits = strings.Repeat("0", exp-len(digits)+1) + digits
+	}
+
+	res := digits[:len(digits)-exp]
+	if frac := strings.TrimRight(digits[len(digits)-exp:], "0"); frac != "" {
+		res += "." + frac
+	}
+	if amount < 0 {
+		res = "-" + res
+	}
+	return res + md.Display
+}
+
+func absUint64(x int64) uint64 {
+	if x < 0 {
+		return uint64(-(x + 1)) + 1
+	}
+	return uint64(x)
+}
+
+// FormatCoins returns coins as a comma-separated list of amounts in display
+// units, using the metadata of their denoms. The coins without metadata are
+// formatted like [std.Coin.String].
+func FormatCoins(coins std.Coins, metadata []DenomMetadata) string {
+	byBase := make(map[string]DenomMetadata, len(metadata))
+	for _, md := range metadata {
+		byBase[md.Base] = md
+	}
+
+	res := make([]string, len(coins))
+	for i, coin := range coins {
+		if md, ok := byBase[coin.Denom]; ok {
+			res[i] = md.FormatAmount(coin.Amount)
+		} else {
+			res[i] = coin.String()
+		}
+	}
+	return strings.Join(res, ",")
+}
@@ -0,0 +1,71 @@
+package bank
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/gnolang/gno/tm2/pkg/std"
+)
+
+func TestDenomMetadataValidateBasic(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		md        DenomMetadata
+		expectErr bool
+	}{
+		{"valid", DenomMetadata{Base: "ugnot", Display: "gnot", Exponent: 6}, false},
+		{"valid realm denom", DenomMetadata{Base: "/gno.land/r/demo/foo:foo", Display: "FOO", Exponent: 4, Issuer: "gno.land/r/demo/foo"}, false},
+		{"invalid base", DenomMetadata{Base: "1gnot", Display: "gnot"}, true},
+		{"empty display", DenomMetadata{Base: "ugnot"}, true},
+		{"display with spaces", DenomMetadata{Base: "ugnot", Display: " gnot"}, true},
+		{"exponent too large", DenomMetadata{Base: "ugnot", Display: "gnot", Exponent: MaxDenomExponent + 1}, true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tc.md.ValidateBasic()
+			if tc.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestDenomMetadataFormatAmount(t *testing.T) {
+	t.Parallel()
+
+	gnot := DenomMetadata{Base: "ugnot", Display: "gnot", Exponent: 6}
+	tests := []struct {
+		md     DenomMetadata
+		amount int64
+		want   string
+	}{
+		{gnot, 0, "0gnot"},
+		{gnot, 1, "0.000001gnot"},
+		{gnot, 1500000, "1.5gnot"},
+		{gnot, 10000000, "10gnot"},
+		{gnot, -2500000, "-2.5gnot"},
+		{DenomMetadata{Base: "foo", Display: "foo"}, 42, "42foo"},
+		{DenomMetadata{Base: "foo", Display: "kfoo", Exponent: 18}, math.MinInt64, "-9.223372036854775808kfoo"},
+	}
+	for _, tc := range tests {
+		assert.Equal(t, tc.want, tc.md.FormatAmount(tc.amount))
+	}
+}
+
+func TestFormatCoins(t *testing.T) {
+	t.Parallel()
+
+	metadata := []DenomMetadata{{Base: "ugnot", Display: "gnot", Exponent: 6}}
+	coins := std.NewCoins(std.NewCoin("atom", 10), std.NewCoin("ugnot", 1234567))
+	assert.Equal(t, "10atom,1.234567gnot", FormatCoins(coins, metadata))
+	assert.Equal(t, "10atom,1234567ugnot", FormatCoins(coins, nil))
+	assert.Equal(t, "", FormatCoins(nil, metadata))
+}
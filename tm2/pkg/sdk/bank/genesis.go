@@ -0,0 +1,49 @@
+package bank
+
+import (
+	"github.com/gnolang/gno/tm2/pkg/sdk"
+)
+
+// GenesisState - all bank state that must be provided at genesis
+type GenesisState struct {
+	DenomMetadata []DenomMetadata `json:"denom_metadata" yaml:"denom_metadata"`
+}
+
+// NewGenesisState - Create a new genesis state
+func NewGenesisState(denomMetadata []DenomMetadata) GenesisState {
+	return GenesisState{denomMetadata}
+}
+
+// DefaultGenesisState - Return a default genesis state
+func DefaultGenesisState() GenesisState {
+	return NewGenesisState(nil)
+}
+
+// ValidateGenesis performs basic validation of bank genesis data returning an
+// error for any failed validation criteria.
+func ValidateGenesis(data GenesisState) error {
+	for _, md := range data.DenomMetadata {
+		if err := md.ValidateBasic(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// InitGenesis - Init store state from genesis data
+func (bank BankKeeper) InitGenesis(ctx sdk.Context, data GenesisState) {
+	if err := ValidateGenesis(data); err != nil {
+		panic(err)
+	}
+
+	for _, md := range data.DenomMetadata {
+		if err := bank.SetDenomMetadata(ctx, md); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// ExportGenesis returns a GenesisState for a given context and keeper
+func (bank BankKeeper) ExportGenesis(ctx sdk.Context) GenesisState {
+	return NewGenesisState(bank.GetAllDenomMetadata(ctx))
+}
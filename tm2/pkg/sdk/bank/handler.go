@@ -98,13 +98,18 @@ func (bh bankHandler) handleMsgMultiSend(ctx sdk.Context, msg MsgMultiSend) sdk.
 //----------------------------------------
 // Query
 
-// query balance path
-const QueryBalance = "balances"
+// query paths
+const (
+	QueryBalance = "balances"
+	QueryDenoms  = "denoms"
+)
 
 func (bh bankHandler) Query(ctx sdk.Context, req abci.RequestQuery) (res abci.ResponseQuery) {
 	switch secondPart(req.Path) {
 	case QueryBalance:
 		return bh.queryBalance(ctx, req)
+	case QueryDenoms:
+		return bh.queryDenoms(ctx, req)
 	default:
 		res = sdk.ABCIResponseQueryFromError(
 			std.ErrUnknownRequest("unknown bank query endpoint"))
@@ -135,6 +140,34 @@ func (bh bankHandler) queryBalance(ctx sdk.Context, req abci.RequestQuery) (res
 	return
 }
 
+// queryDenoms fetches the metadata of all the denoms, or of the denom passed
+// as path component, like "bank/denoms/ugnot". The denom may contain slashes.
+func (bh bankHandler) queryDenoms(ctx sdk.Context, req abci.RequestQuery) (res abci.ResponseQuery) {
+	var result any
+
+	if denom := thirdPartWithSlashes(req.Path); denom == "" {
+		result = bh.bank.GetAllDenomMetadata(ctx)
+	} else {
+		md, ok := bh.bank.GetDenomMetadata(ctx, denom)
+		if !ok {
+			res = sdk.ABCIResponseQueryFromError(
+				std.ErrUnknownRequest(fmt.Sprintf("no metadata for denom %q", denom)))
+			return
+		}
+		result = md
+	}
+
+	bz, err := amino.MarshalJSONIndent(result, "", "  ")
+	if err != nil {
+		res = sdk.ABCIResponseQueryFromError(
+			std.ErrInternal(fmt.Sprintf("could not marshal result to JSON: %s", err.Error())))
+		return
+	}
+
+	res.Data = bz
+	return
+}
+
 //----------------------------------------
 // misc
 
@@ -161,3 +194,12 @@ func thirdPart(path string) string {
 		return parts[2]
 	}
 }
+
+// returns the third component of a path, including all the following ones.
+func thirdPartWithSlashes(path string) string {
+	parts := strings.SplitN(path, "/", 3)
+	if len(parts) < 3 {
+		return ""
+	}
+	return parts[2]
+}
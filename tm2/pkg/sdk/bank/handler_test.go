@@ -54,6 +54,30 @@ func TestBalances(t *testing.T) {
 	require.True(t, coins.AmountOf("foo") == 10)
 }
 
+func TestQueryDenoms(t *testing.T) {
+	t.Parallel()
+
+	env := setupTestEnv()
+	h := NewHandler(env.bank)
+	foo := DenomMetadata{Base: "/gno.land/r/demo/foo:foo", Display: "FOO", Exponent: 2, Issuer: "gno.land/r/demo/foo"}
+	require.NoError(t, env.bank.SetDenomMetadata(env.ctx, foo))
+
+	res := h.Query(env.ctx, abci.RequestQuery{Path: "bank/" + QueryDenoms})
+	require.Nil(t, res.Error)
+	var all []DenomMetadata
+	require.NoError(t, amino.UnmarshalJSON(res.Data, &all))
+	require.Equal(t, []DenomMetadata{foo}, all)
+
+	res = h.Query(env.ctx, abci.RequestQuery{Path: "bank/" + QueryDenoms + "/" + foo.Base})
+	require.Nil(t, res.Error)
+	var md DenomMetadata
+	require.NoError(t, amino.UnmarshalJSON(res.Data, &md))
+	require.Equal(t, foo, md)
+
+	res = h.Query(env.ctx, abci.RequestQuery{Path: "bank/" + QueryDenoms + "/ugnot"})
+	require.Error(t, res.Error)
+}
+
 func TestQuerierRouteNotFound(t *testing.T) {
 	t.Parallel()
 
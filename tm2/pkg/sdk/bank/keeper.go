@@ -4,10 +4,12 @@ import (
 	"fmt"
 	"log/slog"
 
+	"github.com/gnolang/gno/tm2/pkg/amino"
 	"github.com/gnolang/gno/tm2/pkg/crypto"
 	"github.com/gnolang/gno/tm2/pkg/sdk"
 	"github.com/gnolang/gno/tm2/pkg/sdk/auth"
 	"github.com/gnolang/gno/tm2/pkg/std"
+	"github.com/gnolang/gno/tm2/pkg/store"
 )
 
 // bank.Keeper defines a module interface that facilitates the transfer of
@@ -21,6 +23,9 @@ type BankKeeperI interface {
 	SubtractCoins(ctx sdk.Context, addr crypto.Address, amt std.Coins) (std.Coins, error)
 	AddCoins(ctx sdk.Context, addr crypto.Address, amt std.Coins) (std.Coins, error)
 	SetCoins(ctx sdk.Context, addr crypto.Address, amt std.Coins) error
+
+	SetDenomMetadata(ctx sdk.Context, md DenomMetadata) error
+	InitGenesis(ctx sdk.Context, data GenesisState)
 }
 
 var _ BankKeeperI = BankKeeper{}
@@ -33,10 +38,11 @@ type BankKeeper struct {
 	acck auth.AccountKeeper
 }
 
-// NewBankKeeper returns a new BankKeeper.
-func NewBankKeeper(acck auth.AccountKeeper) BankKeeper {
+// NewBankKeeper returns a new BankKeeper, which stores the denom metadata
+// under the given key.
+func NewBankKeeper(key store.StoreKey, acck auth.AccountKeeper) BankKeeper {
 	return BankKeeper{
-		ViewKeeper: NewViewKeeper(acck),
+		ViewKeeper: NewViewKeeper(key, acck),
 		acck:       acck,
 	}
 }
@@ -115,7 +121,8 @@ func (bank BankKeeper) SendCoins(ctx sdk.Context, fromAddr crypto.Address, toAdd
 
 // SubtractCoins subtracts amt from the coins at the addr.
 //
-// CONTRACT: If the account is a vesting account, the amount has to be spendable.
+// If the account is a vesting account, the amount has to be spendable at the
+// time of the current block: locked coins cannot be subtracted.
 func (bank BankKeeper) SubtractCoins(ctx sdk.Context, addr crypto.Address, amt std.Coins) (std.Coins, error) {
 	if !amt.IsValid() {
 		return nil, std.ErrInvalidCoins(amt.String())
@@ -134,6 +141,16 @@ func (bank BankKeeper) SubtractCoins(ctx sdk.Context, addr crypto.Address, amt s
 		)
 		return nil, err
 	}
+
+	if _, ok := acc.(auth.VestingAccount); ok {
+		spendable := auth.SpendableCoins(acc, ctx.BlockTime())
+		if !spendable.IsAllGTE(amt) {
+			err := std.ErrInsufficientCoins(
+				fmt.Sprintf("insufficient spendable account funds; %s < %s", spendable, amt),
+			)
+			return nil, err
+		}
+	}
 	err := bank.SetCoins(ctx, addr, newCoins)
 
 	return newCoins, err
@@ -178,6 +195,17 @@ func (bank BankKeeper) SetCoins(ctx sdk.Context, addr crypto.Address, amt std.Co
 	return nil
 }
 
+// SetDenomMetadata sets the metadata of the denom md.Base.
+func (bank BankKeeper) SetDenomMetadata(ctx sdk.Context, md DenomMetadata) error {
+	if err := md.ValidateBasic(); err != nil {
+		return err
+	}
+
+	stor := ctx.GasStore(bank.key)
+	stor.Set(DenomMetadataStoreKey(md.Base), amino.MustMarshal(md))
+	return nil
+}
+
 // ----------------------------------------
 // ViewKeeper
 
@@ -186,18 +214,23 @@ func (bank BankKeeper) SetCoins(ctx sdk.Context, addr crypto.Address, amt std.Co
 type ViewKeeperI interface {
 	GetCoins(ctx sdk.Context, addr crypto.Address) std.Coins
 	HasCoins(ctx sdk.Context, addr crypto.Address, amt std.Coins) bool
+
+	GetDenomMetadata(ctx sdk.Context, denom string) (DenomMetadata, bool)
+	GetAllDenomMetadata(ctx sdk.Context) []DenomMetadata
 }
 
 var _ ViewKeeperI = ViewKeeper{}
 
 // ViewKeeper implements a read only keeper implementation of ViewKeeperI.
 type ViewKeeper struct {
+	// The (unexposed) key used to access the denom metadata store.
+	key  store.StoreKey
 	acck auth.AccountKeeper
 }
 
 // NewViewKeeper returns a new ViewKeeper.
-func NewViewKeeper(acck auth.AccountKeeper) ViewKeeper {
-	return ViewKeeper{acck: acck}
+func NewViewKeeper(key store.StoreKey, acck auth.AccountKeeper) ViewKeeper {
+	return ViewKeeper{key: key, acck: acck}
 }
 
 // Logger returns a module-specific logger.
@@ -218,3 +251,32 @@ func (view ViewKeeper) GetCoins(ctx sdk.Context, addr crypto.Address) std.Coins
 func (view ViewKeeper) HasCoins(ctx sdk.Context, addr crypto.Address, amt std.Coins) bool {
 	return view.GetCoins(ctx, addr).IsAllGTE(amt)
 }
+
+// GetDenomMetadata returns the metadata of denom, if any.
+func (view ViewKeeper) GetDenomMetadata(ctx sdk.Context, denom string) (DenomMetadata, bool) {
+	stor := ctx.GasStore(view.key)
+	bz := stor.Get(DenomMetadataStoreKey(denom))
+	if bz == nil {
+		return DenomMetadata{}, false
+	}
+
+	var md DenomMetadata
+	amino.MustUnmarshal(bz, &md)
+	return md, true
+}
+
+// GetAllDenomMetadata returns the metadata of all the denoms, sorted by
+// denom.
+func (view ViewKeeper) GetAllDenomMetadata(ctx sdk.Context) []DenomMetadata {
+	stor := ctx.GasStore(view.key)
+	iter := store.PrefixIterator(stor, []byte(DenomMetadataStoreKeyPrefix))
+	defer iter.Close()
+
+	mds := []DenomMetadata{}
+	for ; iter.Valid(); iter.Next() {
+		var md DenomMetadata
+		amino.MustUnmarshal(iter.Value(), &md)
+		mds = append(mds, md)
+	}
+	return mds
+}
@@ -2,11 +2,14 @@ package bank
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
+	bft "github.com/gnolang/gno/tm2/pkg/bft/types"
 	"github.com/gnolang/gno/tm2/pkg/crypto"
 	"github.com/gnolang/gno/tm2/pkg/sdk"
+	"github.com/gnolang/gno/tm2/pkg/sdk/auth"
 	"github.com/gnolang/gno/tm2/pkg/std"
 )
 
@@ -95,7 +98,7 @@ func TestBankKeeper(t *testing.T) {
 	env := setupTestEnv()
 	ctx := env.ctx
 
-	bank := NewBankKeeper(env.acck)
+	bank := NewBankKeeper(env.key, env.acck)
 
 	addr := crypto.AddressFromPreimage([]byte("addr1"))
 	addr2 := crypto.AddressFromPreimage([]byte("addr2"))
@@ -137,12 +140,51 @@ func TestBankKeeper(t *testing.T) {
 	require.Error(t, err)
 }
 
+func TestBankKeeperVestingAccount(t *testing.T) {
+	t.Parallel()
+
+	env := setupTestEnv()
+	ctx := env.ctx.WithBlockHeader(&bft.Header{ChainID: "test-chain-id", Time: time.Unix(1500, 0)})
+
+	addr := crypto.AddressFromPreimage([]byte("addr1"))
+	addr2 := crypto.AddressFromPreimage([]byte("addr2"))
+	coins := std.NewCoins(std.NewCoin("foocoin", 100))
+	acc := auth.NewContinuousVestingAccount(env.acck.NewAccountWithAddress(ctx, addr), coins, 1000, 2000)
+	acc.SetCoins(coins)
+	env.acck.SetAccount(ctx, acc)
+
+	// Half of the coins are vested
+	err := env.bank.SendCoins(ctx, addr, addr2, std.NewCoins(std.NewCoin("foocoin", 51)))
+	require.Error(t, err)
+	require.True(t, env.bank.GetCoins(ctx, addr).IsEqual(coins))
+
+	err = env.bank.SendCoins(ctx, addr, addr2, std.NewCoins(std.NewCoin("foocoin", 50)))
+	require.NoError(t, err)
+	require.True(t, env.bank.GetCoins(ctx, addr).IsEqual(std.NewCoins(std.NewCoin("foocoin", 50))))
+
+	// Received coins are spendable
+	env.bank.AddCoins(ctx, addr, std.NewCoins(std.NewCoin("barcoin", 30)))
+	inputs := []Input{NewInput(addr, std.NewCoins(std.NewCoin("barcoin", 30), std.NewCoin("foocoin", 1)))}
+	outputs := []Output{NewOutput(addr2, std.NewCoins(std.NewCoin("barcoin", 30), std.NewCoin("foocoin", 1)))}
+	require.Error(t, env.bank.InputOutputCoins(ctx, inputs, outputs))
+
+	inputs = []Input{NewInput(addr, std.NewCoins(std.NewCoin("barcoin", 30)))}
+	outputs = []Output{NewOutput(addr2, std.NewCoins(std.NewCoin("barcoin", 30)))}
+	require.NoError(t, env.bank.InputOutputCoins(ctx, inputs, outputs))
+
+	// All the coins are vested at the end time
+	ctx = ctx.WithBlockHeader(&bft.Header{ChainID: "test-chain-id", Time: time.Unix(2000, 0)})
+	err = env.bank.SendCoins(ctx, addr, addr2, std.NewCoins(std.NewCoin("foocoin", 50)))
+	require.NoError(t, err)
+	require.True(t, env.bank.GetCoins(ctx, addr).IsZero())
+}
+
 func TestViewKeeper(t *testing.T) {
 	t.Parallel()
 
 	env := setupTestEnv()
 	ctx := env.ctx
-	view := NewViewKeeper(env.acck)
+	view := NewViewKeeper(env.key, env.acck)
 
 	addr := crypto.AddressFromPreimage([]byte("addr1"))
 	acc := env.acck.NewAccountWithAddress(ctx, addr)
@@ -160,3 +202,39 @@ func TestViewKeeper(t *testing.T) {
 	require.False(t, view.HasCoins(ctx, addr, std.NewCoins(std.NewCoin("foocoin", 15))))
 	require.False(t, view.HasCoins(ctx, addr, std.NewCoins(std.NewCoin("barcoin", 5))))
 }
+
+func TestDenomMetadata(t *testing.T) {
+	t.Parallel()
+
+	env := setupTestEnv()
+	ctx := env.ctx
+
+	_, ok := env.bank.GetDenomMetadata(ctx, "ugnot")
+	require.False(t, ok)
+	require.Empty(t, env.bank.GetAllDenomMetadata(ctx))
+
+	gnot := DenomMetadata{Base: "ugnot", Display: "gnot", Exponent: 6}
+	foo := DenomMetadata{Base: "/gno.land/r/demo/foo:foo", Display: "FOO", Exponent: 2, Issuer: "gno.land/r/demo/foo"}
+	require.NoError(t, env.bank.SetDenomMetadata(ctx, gnot))
+	require.NoError(t, env.bank.SetDenomMetadata(ctx, foo))
+	require.Error(t, env.bank.SetDenomMetadata(ctx, DenomMetadata{Base: "ugnot"}))
+
+	md, ok := env.bank.GetDenomMetadata(ctx, "ugnot")
+	require.True(t, ok)
+	require.Equal(t, gnot, md)
+	require.Equal(t, []DenomMetadata{foo, gnot}, env.bank.GetAllDenomMetadata(ctx))
+
+	// the metadata can be updated
+	gnot.Description = "native coin"
+	require.NoError(t, env.bank.SetDenomMetadata(ctx, gnot))
+	md, _ = env.bank.GetDenomMetadata(ctx, "ugnot")
+	require.Equal(t, "native coin", md.Description)
+
+	// genesis round trip
+	env2 := setupTestEnv()
+	env2.bank.InitGenesis(env2.ctx, env.bank.ExportGenesis(ctx))
+	require.Equal(t, env.bank.GetAllDenomMetadata(ctx), env2.bank.GetAllDenomMetadata(env2.ctx))
+	require.Panics(t, func() {
+		env2.bank.InitGenesis(env2.ctx, NewGenesisState([]DenomMetadata{{Base: "ugnot", Display: "gnot", Exponent: 19}}))
+	})
+}
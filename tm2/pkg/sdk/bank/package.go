@@ -13,4 +13,6 @@ var Package = amino.RegisterPackage(amino.NewPackage(
 	NoOutputsError{}, "NoOutputsError",
 	InputOutputMismatchError{}, "InputOutputMismatchError",
 	MsgSend{}, "MsgSend",
+	DenomMetadata{}, "DenomMetadata",
+	GenesisState{}, "GenesisState",
 ))
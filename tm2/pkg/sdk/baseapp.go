@@ -1,19 +1,23 @@
 package sdk
 
 import (
+	goerrors "errors"
 	"fmt"
 	"log/slog"
 	"os"
 	"runtime/debug"
 	"sort"
 	"strings"
+	"sync/atomic"
 	"syscall"
+	"time"
 
 	"github.com/gnolang/gno/tm2/pkg/amino"
 	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
 	bft "github.com/gnolang/gno/tm2/pkg/bft/types"
 	dbm "github.com/gnolang/gno/tm2/pkg/db"
 	"github.com/gnolang/gno/tm2/pkg/errors"
+	"github.com/gnolang/gno/tm2/pkg/sdk/snapshots"
 	"github.com/gnolang/gno/tm2/pkg/std"
 	"github.com/gnolang/gno/tm2/pkg/store"
 )
@@ -44,6 +48,14 @@ type BaseApp struct {
 
 	beginTxHook BeginTxHook // BaseApp-specific hook run before running transaction messages.
 	endTxHook   EndTxHook   // BaseApp-specific hook run after running transaction messages.
+	restoreHook RestoreHook // BaseApp-specific hook run after restoring a state sync snapshot.
+
+	// state sync snapshots
+	snapshotManager    *snapshots.Manager // nil if snapshots are disabled
+	snapshotInterval   uint64             // block interval between snapshots, 0 to disable creation
+	snapshotKeepRecent uint32             // number of recent snapshots to keep, 0 to keep all
+	restoreHeader      abci.Header        // header of the snapshot being restored
+	snapshotting       atomic.Bool        // flag indicating if a snapshot is being created
 
 	// --------------------
 	// Volatile state
@@ -572,18 +584,34 @@ func (app *BaseApp) BeginBlock(req abci.RequestBeginBlock) (res abci.ResponseBeg
 //
 // NOTE:CheckTx does not run the actual Msg handler function(s).
 func (app *BaseApp) CheckTx(req abci.RequestCheckTx) (res abci.ResponseCheckTx) {
+	if req.Type == abci.CheckTxTypeReset {
+		// Reset the Check state to the latest committed.
+		app.setCheckState(app.checkState.ctx.BlockHeader())
+		if len(req.Tx) == 0 {
+			return
+		}
+	}
+
 	var tx Tx
 	err := amino.Unmarshal(req.Tx, &tx)
 	if err != nil {
 		res.Error = ABCIError(std.ErrTxDecode(err.Error()))
 		return
 	} else {
-		ctx := app.getContextForTx(RunTxModeCheck, req.Tx)
+		ctx := app.getContextForTx(RunTxModeCheck, req.Tx).WithCheckTxType(req.Type)
+		if req.Type == abci.CheckTxTypeReplace {
+			// the tx may replace a pending tx, whose changes are still in
+			// the Check state.
+			ctx = ctx.WithCommittedMultiStore(app.cms.MultiCacheWrap())
+		}
 
 		result := app.runTx(ctx, tx)
 		res.ResponseBase = result.ResponseBase
 		res.GasWanted = result.GasWanted
 		res.GasUsed = result.GasUsed
+		res.Priority = result.Priority
+		res.Sender = result.Sender
+		res.Sequence = result.Sequence
 		return
 	}
 }
@@ -732,6 +760,8 @@ func (app *BaseApp) runTx(ctx Context, tx Tx) (result Result) {
 		// determined by the GasMeter. We need access to the context to get the gas
 		// meter so we initialize upfront.
 		gasWanted int64
+		// The mempool ordering, returned by the AnteHandler.
+		anteResult Result
 
 		ms   = ctx.MultiStore()
 		mode = ctx.Mode()
@@ -846,6 +876,7 @@ func (app *BaseApp) runTx(ctx Context, tx Tx) (result Result) {
 			ctx = newCtx.WithMultiStore(ms)
 			msCache.MultiWrite()
 			gasWanted = result.GasWanted
+			anteResult = result
 		}
 	}
 
@@ -859,6 +890,9 @@ func (app *BaseApp) runTx(ctx Context, tx Tx) (result Result) {
 
 	result = app.runMsgs(runMsgCtx, msgs, mode)
 	result.GasWanted = gasWanted
+	result.Priority = anteResult.Priority
+	result.Sender = anteResult.Sender
+	result.Sequence = anteResult.Sequence
 
 	// Safety check: don't write the cache state unless we're in DeliverTx.
 	if mode != RunTxModeDeliver {
@@ -942,11 +976,229 @@ func (app *BaseApp) Commit() (res abci.ResponseCommit) {
 	// empty/reset the deliver state
 	app.deliverState = nil
 
+	// Create a state sync snapshot in the background, if due.
+	app.createSnapshot(commitID.Version)
+
 	// return.
 	res.Data = commitID.Hash
 	return
 }
 
+// createSnapshot creates a state sync snapshot at the given height in the
+// background, if it's a multiple of the snapshot interval, and prunes old
+// snapshots. The height is retained from pruning until it's exported, while
+// the next blocks are committed. Only one snapshot is created at a time.
+// Failures are logged, as snapshots are not part of the consensus.
+func (app *BaseApp) createSnapshot(height int64) {
+	if app.snapshotManager == nil || app.snapshotInterval == 0 || uint64(height)%app.snapshotInterval != 0 {
+		return
+	}
+
+	if !app.snapshotting.CompareAndSwap(false, true) {
+		app.logger.Info("skipping state sync snapshot, another one is being created", "height", height)
+
+		return
+	}
+
+	release, err := app.snapshotManager.Retain(height)
+	if err != nil {
+		app.logger.Error("failed to create state sync snapshot", "height", height, "err", err)
+		app.snapshotting.Store(false)
+
+		return
+	}
+
+	go func() {
+		defer app.snapshotting.Store(false)
+		defer release()
+
+		start := time.Now()
+
+		snapshot, err := app.snapshotManager.Create(height)
+		if err != nil {
+			app.logger.Error("failed to create state sync snapshot", "height", height, "err", err)
+
+			return
+		}
+
+		app.logger.Info(
+			"created state sync snapshot",
+			"height", height,
+			"chunks", snapshot.Chunks,
+			"elapsed", time.Since(start),
+		)
+
+		if app.snapshotKeepRecent == 0 {
+			return
+		}
+
+		if err := app.snapshotManager.Prune(app.snapshotKeepRecent); err != nil {
+			app.logger.Error("failed to prune state sync snapshots", "err", err)
+		}
+	}()
+}
+
+// ListSnapshots implements the ABCI interface. It returns the state sync
+// snapshots available locally.
+func (app *BaseApp) ListSnapshots(req abci.RequestListSnapshots) (res abci.ResponseListSnapshots) {
+	if app.snapshotManager == nil {
+		return
+	}
+
+	list, err := app.snapshotManager.List()
+	if err != nil {
+		res.Error = ABCIError(std.ErrInternal(fmt.Sprintf("unable to list snapshots: %s", err)))
+		return
+	}
+
+	res.Snapshots = list
+	return
+}
+
+// LoadSnapshotChunk implements the ABCI interface. It returns the requested
+// chunk of a local snapshot, or an empty chunk if it's not available.
+func (app *BaseApp) LoadSnapshotChunk(req abci.RequestLoadSnapshotChunk) (res abci.ResponseLoadSnapshotChunk) {
+	if app.snapshotManager == nil {
+		return
+	}
+
+	chunk, err := app.snapshotManager.LoadChunk(req.Height, req.Format, req.Chunk)
+	if err != nil {
+		app.logger.Debug(
+			"unable to load snapshot chunk",
+			"height", req.Height,
+			"format", req.Format,
+			"chunk", req.Chunk,
+			"err", err,
+		)
+
+		return
+	}
+
+	res.Chunk = chunk
+	return
+}
+
+// OfferSnapshot implements the ABCI interface. It starts the restoration of
+// the offered snapshot, which is verified against the trusted app hash.
+// Snapshots can only be restored by an app that has no state yet.
+func (app *BaseApp) OfferSnapshot(req abci.RequestOfferSnapshot) (res abci.ResponseOfferSnapshot) {
+	switch {
+	case app.snapshotManager == nil:
+		app.logger.Error("snapshot offered, but snapshots are disabled")
+		res.Result = abci.OfferSnapshotAbort
+
+		return
+	case app.LastBlockHeight() != 0:
+		app.logger.Error("snapshot offered, but the app already has state", "height", app.LastBlockHeight())
+		res.Result = abci.OfferSnapshotAbort
+
+		return
+	case req.Snapshot == nil:
+		res.Result = abci.OfferSnapshotReject
+
+		return
+	case req.Header == nil || req.Header.GetHeight() != req.Snapshot.Height:
+		// The header replaces the last header of the base store,
+		// which isn't part of the snapshot
+		app.logger.Info("rejecting snapshot without a matching header", "height", req.Snapshot.Height)
+		res.Result = abci.OfferSnapshotReject
+
+		return
+	}
+
+	err := app.snapshotManager.Offer(req.Snapshot, req.AppHash)
+
+	switch {
+	case err == nil:
+		app.restoreHeader = req.Header
+		res.Result = abci.OfferSnapshotAccept
+	case goerrors.Is(err, snapshots.ErrUnknownFormat):
+		res.Result = abci.OfferSnapshotRejectFormat
+	case goerrors.Is(err, snapshots.ErrInvalidMetadata):
+		app.logger.Info("rejecting invalid snapshot", "height", req.Snapshot.Height, "err", err)
+		res.Result = abci.OfferSnapshotReject
+	default:
+		app.logger.Error("unable to restore snapshot", "height", req.Snapshot.Height, "err", err)
+		res.Result = abci.OfferSnapshotAbort
+	}
+
+	return
+}
+
+// ApplySnapshotChunk implements the ABCI interface. It applies the next chunk
+// of the offered snapshot, and loads the restored state once all the chunks
+// were applied.
+func (app *BaseApp) ApplySnapshotChunk(req abci.RequestApplySnapshotChunk) (res abci.ResponseApplySnapshotChunk) {
+	if app.snapshotManager == nil {
+		res.Result = abci.ApplySnapshotChunkAbort
+		return
+	}
+
+	done, err := app.snapshotManager.ApplyChunk(req.Index, req.Chunk)
+
+	switch {
+	case goerrors.Is(err, snapshots.ErrChunkHashMismatch):
+		// Fetch the chunk again, from a different peer
+		app.logger.Info("rejecting invalid snapshot chunk", "chunk", req.Index, "sender", req.Sender, "err", err)
+
+		res.Result = abci.ApplySnapshotChunkRetry
+		res.RefetchChunks = []uint32{req.Index}
+
+		if req.Sender != "" {
+			res.RejectSenders = []string{req.Sender}
+		}
+
+		return
+	case err != nil:
+		app.logger.Error("unable to apply snapshot chunk", "chunk", req.Index, "err", err)
+		res.Result = abci.ApplySnapshotChunkAbort
+
+		return
+	}
+
+	if done {
+		if err := app.loadRestoredState(); err != nil {
+			app.logger.Error("unable to load restored state", "err", err)
+			res.Result = abci.ApplySnapshotChunkAbort
+
+			return
+		}
+
+		app.logger.Info("restored state sync snapshot", "height", app.LastBlockHeight())
+	}
+
+	res.Result = abci.ApplySnapshotChunkAccept
+	return
+}
+
+// loadRestoredState loads the app state after restoring a snapshot,
+// the same way it's loaded on startup.
+func (app *BaseApp) loadRestoredState() error {
+	// The base store isn't part of the snapshot,
+	// save the header it's expected to hold
+	baseStore := app.cms.GetStore(app.baseKey)
+	if baseStore == nil {
+		return errors.New("baseapp expects MultiStore with 'base' Store")
+	}
+	baseStore.Set(mainLastHeaderKey, amino.MustMarshal(app.restoreHeader))
+
+	// The check state is reset as it's loaded from the restored header
+	app.checkState = nil
+
+	if err := app.initFromMainStore(); err != nil {
+		return err
+	}
+
+	if app.restoreHook != nil {
+		// The restored state must match the snapshot,
+		// the writes of the hook are discarded.
+		app.restoreHook(app.cms.MultiCacheWrap())
+	}
+
+	return nil
+}
+
 // halt attempts to gracefully shutdown the node via SIGINT and SIGTERM falling
 // back on os.Exit if both fail.
 func (app *BaseApp) halt() {
@@ -8,6 +8,7 @@ import (
 	"os"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -582,6 +583,19 @@ func TestCheckTx(t *testing.T) {
 	// Ensure AnteHandler ran
 	require.Equal(t, nTxs, storedCounter)
 
+	// A reset discards the CheckTx state, before checking the tx.
+	txBytes, err := amino.Marshal(newTxCounter(0, 0))
+	require.NoError(t, err)
+	r := app.CheckTx(abci.RequestCheckTx{Tx: txBytes, Type: abci.CheckTxTypeReset})
+	require.True(t, r.IsOK(), fmt.Sprintf("%v", r))
+	checkStateStore = app.checkState.ctx.Store(mainKey)
+	require.Equal(t, int64(1), getIntFromStore(checkStateStore, counterKey))
+
+	r = app.CheckTx(abci.RequestCheckTx{Type: abci.CheckTxTypeReset})
+	require.True(t, r.IsOK(), fmt.Sprintf("%v", r))
+	checkStateStore = app.checkState.ctx.Store(mainKey)
+	require.Nil(t, checkStateStore.Get(counterKey))
+
 	// If a block is committed, CheckTx state should be reset.
 	header := &bft.Header{ChainID: "test-chain", Height: 1}
 	app.BeginBlock(abci.RequestBeginBlock{Header: header})
@@ -593,6 +607,39 @@ func TestCheckTx(t *testing.T) {
 	require.Nil(t, storedBytes)
 }
 
+// Test that the state committed in the last block is only given to the ante
+// handler on the CheckTxTypeReplace requests.
+func TestCheckTxReplace(t *testing.T) {
+	t.Parallel()
+
+	counterKey := []byte("counter-key")
+	var committed store.MultiStore
+	anteOpt := func(bapp *BaseApp) {
+		bapp.SetAnteHandler(func(ctx Context, tx Tx, simulate bool) (newCtx Context, res Result, abort bool) {
+			committed = ctx.CommittedMultiStore()
+			setIntOnStore(ctx.Store(mainKey), counterKey, 1)
+			return ctx, Result{}, false
+		})
+	}
+	routerOpt := func(bapp *BaseApp) {
+		bapp.Router().AddRoute(routeMsgCounter, newTestHandler(func(ctx Context, msg Msg) Result { return Result{} }))
+	}
+
+	app := setupBaseApp(t, anteOpt, routerOpt)
+	app.InitChain(abci.RequestInitChain{ChainID: "test-chain"})
+
+	txBytes, err := amino.Marshal(newTxCounter(0, 0))
+	require.NoError(t, err)
+	r := app.CheckTx(abci.RequestCheckTx{Tx: txBytes})
+	require.True(t, r.IsOK(), fmt.Sprintf("%v", r))
+	require.Nil(t, committed)
+
+	r = app.CheckTx(abci.RequestCheckTx{Tx: txBytes, Type: abci.CheckTxTypeReplace})
+	require.True(t, r.IsOK(), fmt.Sprintf("%v", r))
+	require.NotNil(t, committed)
+	require.Nil(t, committed.GetStore(mainKey).Get(counterKey))
+}
+
 // Test that successive DeliverTx can see each others' effects
 // on the store, both within and across blocks.
 func TestDeliverTx(t *testing.T) {
@@ -1247,3 +1294,108 @@ func TestGetMaximumBlockGas(t *testing.T) {
 	app.setConsensusParams(&abci.ConsensusParams{Block: &abci.BlockParams{MaxGas: -5000000}})
 	require.Panics(t, func() { app.getMaximumBlockGas() })
 }
+
+func TestSnapshots(t *testing.T) {
+	t.Parallel()
+
+	key, value := []byte("hello"), []byte("goodbye")
+
+	// Create a source app, taking a snapshot every 2 blocks
+	app := setupBaseApp(t, SetSnapshotOptions(memdb.NewMemDB(), 2, 1))
+	app.InitChain(abci.RequestInitChain{ChainID: "test-chain"})
+
+	for height := int64(1); height <= 4; height++ {
+		header := &bft.Header{ChainID: "test-chain", Height: height}
+		app.BeginBlock(abci.RequestBeginBlock{Header: header})
+		app.deliverState.ctx.Store(mainKey).Set(key, value)
+		app.Commit()
+
+		// Snapshots are created in the background
+		require.Eventually(t, func() bool {
+			return !app.snapshotting.Load()
+		}, 5*time.Second, 10*time.Millisecond)
+	}
+
+	// Only the most recent snapshot is kept
+	listRes := app.ListSnapshots(abci.RequestListSnapshots{})
+	require.Nil(t, listRes.Error)
+	require.Len(t, listRes.Snapshots, 1)
+
+	snapshot := listRes.Snapshots[0]
+	require.Equal(t, int64(4), snapshot.Height)
+
+	// Restore the snapshot into a new app
+	var restored bool
+
+	target := newBaseApp(t.Name(), memdb.NewMemDB(), SetSnapshotOptions(memdb.NewMemDB(), 0, 0))
+	target.SetRestoreHook(func(store.MultiStore) { restored = true })
+	require.NoError(t, target.LoadLatestVersion())
+
+	// The base store isn't part of the snapshot, so the header is required
+	offerRes := target.OfferSnapshot(abci.RequestOfferSnapshot{
+		Snapshot: snapshot,
+		AppHash:  app.LastCommitID().Hash,
+	})
+	require.Equal(t, abci.OfferSnapshotReject, offerRes.Result)
+
+	offerRes = target.OfferSnapshot(abci.RequestOfferSnapshot{
+		Snapshot: snapshot,
+		AppHash:  app.LastCommitID().Hash,
+		Header:   &bft.Header{ChainID: "test-chain", Height: 3},
+	})
+	require.Equal(t, abci.OfferSnapshotReject, offerRes.Result)
+
+	offerRes = target.OfferSnapshot(abci.RequestOfferSnapshot{
+		Snapshot: snapshot,
+		AppHash:  app.LastCommitID().Hash,
+		Header:   &bft.Header{ChainID: "test-chain", Height: 4},
+	})
+	require.Equal(t, abci.OfferSnapshotAccept, offerRes.Result)
+
+	for index := uint32(0); index < snapshot.Chunks; index++ {
+		chunkRes := app.LoadSnapshotChunk(abci.RequestLoadSnapshotChunk{
+			Height: snapshot.Height,
+			Format: snapshot.Format,
+			Chunk:  index,
+		})
+		require.NotEmpty(t, chunkRes.Chunk)
+
+		// A tampered chunk is refetched from another sender
+		applyRes := target.ApplySnapshotChunk(abci.RequestApplySnapshotChunk{
+			Index:  index,
+			Chunk:  append([]byte{0x1}, chunkRes.Chunk...),
+			Sender: "bad-peer",
+		})
+		require.Equal(t, abci.ApplySnapshotChunkRetry, applyRes.Result)
+		require.Equal(t, []uint32{index}, applyRes.RefetchChunks)
+		require.Equal(t, []string{"bad-peer"}, applyRes.RejectSenders)
+
+		applyRes = target.ApplySnapshotChunk(abci.RequestApplySnapshotChunk{
+			Index: index,
+			Chunk: chunkRes.Chunk,
+		})
+		require.Equal(t, abci.ApplySnapshotChunkAccept, applyRes.Result)
+	}
+
+	require.True(t, restored)
+	require.Equal(t, app.LastCommitID(), target.LastCommitID())
+	require.Equal(t, "test-chain", target.checkState.ctx.ChainID())
+	require.Equal(t, int64(4), target.checkState.ctx.BlockHeight())
+
+	res := target.Query(abci.RequestQuery{Path: ".store/main/key", Data: key})
+	require.Equal(t, value, res.Value)
+
+	// The restored app can't accept further snapshots
+	offerRes = target.OfferSnapshot(abci.RequestOfferSnapshot{
+		Snapshot: snapshot,
+		AppHash:  app.LastCommitID().Hash,
+		Header:   &bft.Header{ChainID: "test-chain", Height: 4},
+	})
+	require.Equal(t, abci.OfferSnapshotAbort, offerRes.Result)
+
+	// The restored app can commit new blocks
+	header := &bft.Header{ChainID: "test-chain", Height: 5}
+	target.BeginBlock(abci.RequestBeginBlock{Header: header})
+	target.Commit()
+	require.Equal(t, int64(5), target.LastBlockHeight())
+}
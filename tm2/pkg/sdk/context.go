@@ -33,6 +33,8 @@ type Context struct {
 	minGasPrices  []GasPrice
 	consParams    *abci.ConsensusParams
 	eventLogger   *EventLogger
+	checkTxType   abci.CheckTxType
+	committedMS   store.MultiStore
 }
 
 // Proposed rename, not done to avoid API breakage
@@ -53,6 +55,11 @@ func (c Context) BlockGasMeter() store.GasMeter { return c.blockGasMeter }
 func (c Context) IsCheckTx() bool               { return c.mode == RunTxModeCheck }
 func (c Context) MinGasPrices() []GasPrice      { return c.minGasPrices }
 func (c Context) EventLogger() *EventLogger     { return c.eventLogger }
+func (c Context) CheckTxType() abci.CheckTxType { return c.checkTxType }
+
+// CommittedMultiStore returns a cache of the state committed in the last
+// block, which is only set on the CheckTxTypeReplace requests.
+func (c Context) CommittedMultiStore() store.MultiStore { return c.committedMS }
 
 // clone the header before returning
 func (c Context) BlockHeader() abci.Header {
@@ -92,6 +99,19 @@ func (c Context) WithMode(mode RunTxMode) Context {
 	return c
 }
 
+// WithCheckTxType sets the type of the CheckTx request being run.
+func (c Context) WithCheckTxType(typ abci.CheckTxType) Context {
+	c.checkTxType = typ
+	return c
+}
+
+// WithCommittedMultiStore sets the cache of the state committed in the last
+// block.
+func (c Context) WithCommittedMultiStore(ms store.MultiStore) Context {
+	c.committedMS = ms
+	return c
+}
+
 func (c Context) WithMultiStore(ms store.MultiStore) Context {
 	c.ms = ms
 	return c
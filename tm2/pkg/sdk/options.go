@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	dbm "github.com/gnolang/gno/tm2/pkg/db"
+	"github.com/gnolang/gno/tm2/pkg/sdk/snapshots"
 	"github.com/gnolang/gno/tm2/pkg/store"
 )
 
@@ -29,6 +30,27 @@ func SetMinGasPrices(gasPricesStr string) func(*BaseApp) {
 	return func(bap *BaseApp) { bap.setMinGasPrices(gasPrices) }
 }
 
+// SetSnapshotOptions returns an option that enables state sync snapshots.
+// Snapshots are saved to the given db every interval blocks (0 disables
+// their creation), keeping only the keepRecent most recent ones (0 keeps all).
+//
+// The snapshots only hold the merkleized stores, verified against the app
+// hash once restored. The base store isn't part of them: its last header is
+// set to the verified header offered along with the snapshot. Apps keeping
+// any other state in the base store can't use snapshots.
+func SetSnapshotOptions(db dbm.DB, interval uint64, keepRecent uint32) func(*BaseApp) {
+	return func(bap *BaseApp) {
+		ms, ok := bap.cms.(store.SnapshotMultiStore)
+		if !ok {
+			panic("state sync snapshots are not supported by the multistore")
+		}
+
+		bap.snapshotManager = snapshots.NewManager(snapshots.NewStore(db), ms, bap.baseKey)
+		bap.snapshotInterval = interval
+		bap.snapshotKeepRecent = keepRecent
+	}
+}
+
 func (app *BaseApp) SetName(name string) {
 	if app.sealed {
 		panic("SetName() on sealed BaseApp")
@@ -99,3 +121,10 @@ func (app *BaseApp) SetEndTxHook(endTx EndTxHook) {
 	}
 	app.endTxHook = endTx
 }
+
+func (app *BaseApp) SetRestoreHook(restore RestoreHook) {
+	if app.sealed {
+		panic("SetRestoreHook() on sealed BaseApp")
+	}
+	app.restoreHook = restore
+}
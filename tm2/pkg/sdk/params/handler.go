@@ -1,14 +1,36 @@
 package params
 
 import (
+	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
 
+	"github.com/gnolang/gno/tm2/pkg/amino"
 	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
 	"github.com/gnolang/gno/tm2/pkg/sdk"
 	"github.com/gnolang/gno/tm2/pkg/std"
+	"github.com/gnolang/gno/tm2/pkg/store"
 )
 
+const (
+	// QueryKeys lists the keys of the params starting with a prefix, like
+	// "params/keys/gno.land/r/sys/params" or "params/keys/auth".
+	QueryKeys = "keys"
+	// QueryList lists the params starting with a prefix, with their values
+	// decoded by type, like "params/list/gno.land/r/sys/params".
+	QueryList = "list"
+)
+
+// Param is a param returned by the list query. The params of the modules
+// are keyed by module and JSON field name, like "auth.max_memo_bytes", and
+// have no type.
+type Param struct {
+	Key   string          `json:"key"`
+	Type  string          `json:"type,omitempty"`
+	Value json.RawMessage `json:"value"`
+}
+
 type paramsHandler struct {
 	params ParamsKeeper
 }
@@ -31,6 +53,10 @@ func (bh paramsHandler) Query(ctx sdk.Context, req abci.RequestQuery) (res abci.
 	switch secondPart(req.Path) {
 	case bh.params.prefix:
 		return bh.queryParam(ctx, req)
+	case QueryKeys:
+		return bh.queryKeys(ctx, req)
+	case QueryList:
+		return bh.queryList(ctx, req)
 	default:
 		res = sdk.ABCIResponseQueryFromError(
 			std.ErrUnknownRequest("unknown params query endpoint"))
@@ -45,6 +71,7 @@ func (bh paramsHandler) queryParam(ctx sdk.Context, req abci.RequestQuery) (res
 	if key == "" {
 		res = sdk.ABCIResponseQueryFromError(
 			std.ErrUnknownRequest("param key is empty"))
+		return
 	}
 
 	// XXX: validate?
@@ -55,6 +82,110 @@ func (bh paramsHandler) queryParam(ctx sdk.Context, req abci.RequestQuery) (res
 	return
 }
 
+// queryKeys returns the keys of the params starting with a prefix, as a JSON
+// array.
+func (bh paramsHandler) queryKeys(ctx sdk.Context, req abci.RequestQuery) (res abci.ResponseQuery) {
+	prms, err := bh.listParams(ctx, thirdPartWithSlashes(req.Path))
+	if err != nil {
+		res = sdk.ABCIResponseQueryFromError(err)
+		return
+	}
+
+	keys := make([]string, len(prms))
+	for i, prm := range prms {
+		keys[i] = prm.Key
+	}
+	bz, err := json.Marshal(keys)
+	if err != nil {
+		res = sdk.ABCIResponseQueryFromError(std.ErrInternal(err.Error()))
+		return
+	}
+
+	res.Data = bz
+	return
+}
+
+// queryList returns the params starting with a prefix, as a JSON array of
+// Param.
+func (bh paramsHandler) queryList(ctx sdk.Context, req abci.RequestQuery) (res abci.ResponseQuery) {
+	prms, err := bh.listParams(ctx, thirdPartWithSlashes(req.Path))
+	if err != nil {
+		res = sdk.ABCIResponseQueryFromError(err)
+		return
+	}
+
+	if prms == nil {
+		prms = []Param{}
+	}
+	bz, err := json.Marshal(prms)
+	if err != nil {
+		res = sdk.ABCIResponseQueryFromError(std.ErrInternal(err.Error()))
+		return
+	}
+
+	res.Data = bz
+	return
+}
+
+// listParams returns the typed params and the fields of the params of the
+// modules whose key starts with prefix, sorted by key.
+func (bh paramsHandler) listParams(ctx sdk.Context, prefix string) ([]Param, error) {
+	var prms []Param
+	for _, key := range bh.params.ListKeys(ctx, prefix) {
+		val, err := decodeParam(ParamType(key), bh.params.GetRaw(ctx, key))
+		if err != nil {
+			return nil, std.ErrInternal(fmt.Sprintf("invalid param %s: %v", key, err))
+		}
+		prms = append(prms, Param{Key: key, Type: ParamType(key), Value: val})
+	}
+
+	// modules params.
+	stor := ctx.Store(bh.params.key)
+	iter := store.PrefixIterator(stor, []byte(ValueStoreKeyPrefix))
+	defer iter.Close()
+	for ; iter.Valid(); iter.Next() {
+		module := strings.TrimPrefix(string(iter.Key()), ValueStoreKeyPrefix)
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(iter.Value(), &fields); err != nil {
+			return nil, std.ErrInternal(fmt.Sprintf("invalid %s params: %v", module, err))
+		}
+		for field, val := range fields {
+			if key := module + "." + field; strings.HasPrefix(key, prefix) {
+				prms = append(prms, Param{Key: key, Value: val})
+			}
+		}
+	}
+
+	sort.Slice(prms, func(i, j int) bool {
+		return prms[i].Key < prms[j].Key
+	})
+	return prms, nil
+}
+
+// decodeParam decodes the amino JSON value bz of a param of type typ, and
+// encodes it back to plain JSON, with integers as numbers.
+func decodeParam(typ string, bz []byte) (json.RawMessage, error) {
+	var ptr any
+	switch typ {
+	case "string":
+		ptr = new(string)
+	case "bool":
+		ptr = new(bool)
+	case "int64":
+		ptr = new(int64)
+	case "uint64":
+		ptr = new(uint64)
+	case "bytes":
+		ptr = new([]byte)
+	default:
+		return nil, fmt.Errorf("unknown param type %q", typ)
+	}
+	if err := amino.UnmarshalJSON(bz, ptr); err != nil {
+		return nil, err
+	}
+	return json.Marshal(ptr)
+}
+
 //----------------------------------------
 // misc
 
@@ -75,5 +206,8 @@ func secondPart(path string) string {
 // returns the third component of a path, including other slashes.
 func thirdPartWithSlashes(path string) string {
 	split := strings.SplitN(path, "/", 3)
+	if len(split) < 3 {
+		return ""
+	}
 	return split[2]
 }
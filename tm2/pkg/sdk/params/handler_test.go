@@ -77,3 +77,66 @@ func TestQuerierRouteNotFound(t *testing.T) {
 	res := h.Query(env.ctx, req)
 	require.Error(t, res.Error)
 }
+
+func TestQueryKeysAndList(t *testing.T) {
+	t.Parallel()
+
+	env := setupTestEnv()
+	h := NewHandler(env.keeper)
+
+	env.keeper.SetString(env.ctx, "gno.land/r/foo.bar.string", "baz")
+	env.keeper.SetInt64(env.ctx, "gno.land/r/foo.bar.int64", -12345)
+	env.keeper.SetUint64(env.ctx, "gno.land/r/foo.bar.uint64", 4242)
+	env.keeper.SetBool(env.ctx, "gno.land/r/foo.bar.bool", true)
+	env.keeper.SetBytes(env.ctx, "gno.land/r/foo.bar.bytes", []byte("baz"))
+	env.keeper.SetString(env.ctx, "gno.land/r/foobar.baz.string", "qux")
+	env.store.Set([]byte("gno.land/r/foo.untyped"), []byte("ignored"))
+	require.NoError(t, env.keeper.SetParams(env.ctx, "mod", struct {
+		Max  int64  `json:"max"`
+		Name string `json:"name"`
+	}{Max: 42, Name: "mod"}))
+
+	tcs := []struct {
+		path     string
+		expected string
+	}{
+		{
+			path:     "params/keys/gno.land/r/foo.",
+			expected: `["gno.land/r/foo.bar.bool","gno.land/r/foo.bar.bytes","gno.land/r/foo.bar.int64","gno.land/r/foo.bar.string","gno.land/r/foo.bar.uint64"]`,
+		},
+		{
+			path:     "params/keys/gno.land/r/foobar",
+			expected: `["gno.land/r/foobar.baz.string"]`,
+		},
+		{
+			path:     "params/keys/mod",
+			expected: `["mod.max","mod.name"]`,
+		},
+		{
+			path:     "params/keys/gno.land/r/none",
+			expected: `[]`,
+		},
+		{
+			path: "params/list/gno.land/r/foo.",
+			expected: `[{"key":"gno.land/r/foo.bar.bool","type":"bool","value":true},` +
+				`{"key":"gno.land/r/foo.bar.bytes","type":"bytes","value":"YmF6"},` +
+				`{"key":"gno.land/r/foo.bar.int64","type":"int64","value":-12345},` +
+				`{"key":"gno.land/r/foo.bar.string","type":"string","value":"baz"},` +
+				`{"key":"gno.land/r/foo.bar.uint64","type":"uint64","value":4242}]`,
+		},
+		{
+			path:     "params/list/mod.m",
+			expected: `[{"key":"mod.max","value":"42"}]`,
+		},
+		{
+			path:     "params/list/gno.land/r/none",
+			expected: `[]`,
+		},
+	}
+
+	for _, tc := range tcs {
+		res := h.Query(env.ctx, abci.RequestQuery{Path: tc.path})
+		require.Nil(t, res.Error, tc.path)
+		assert.Equal(t, tc.expected, string(res.Data), tc.path)
+	}
+}
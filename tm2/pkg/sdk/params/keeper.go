@@ -17,6 +17,9 @@ const (
 	ValueStoreKeyPrefix = "/pv/"
 )
 
+// paramTypes are the types of the params, used as key suffixes.
+var paramTypes = []string{"string", "bool", "int64", "uint64", "bytes"}
+
 func ValueStoreKey(key string) []byte {
 	return append([]byte(ValueStoreKeyPrefix), []byte(key)...)
 }
@@ -36,8 +39,7 @@ type ParamsKeeperI interface {
 
 	Has(ctx sdk.Context, key string) bool
 	GetRaw(ctx sdk.Context, key string) []byte
-
-	// XXX: ListKeys?
+	ListKeys(ctx sdk.Context, prefix string) []string
 }
 
 var _ ParamsKeeperI = ParamsKeeper{}
@@ -95,6 +97,35 @@ func (pk ParamsKeeper) GetRaw(ctx sdk.Context, key string) []byte {
 	return stor.Get([]byte(key))
 }
 
+// ListKeys returns the keys of the typed params starting with prefix, in
+// ascending order. The params of the modules are not included.
+func (pk ParamsKeeper) ListKeys(ctx sdk.Context, prefix string) []string {
+	stor := ctx.Store(pk.key)
+	iter := store.PrefixIterator(stor, []byte(prefix))
+	defer iter.Close()
+
+	var keys []string
+	for ; iter.Valid(); iter.Next() {
+		key := string(iter.Key())
+		if strings.HasPrefix(key, "/") || ParamType(key) == "" {
+			continue
+		}
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// ParamType returns the type of the param key, given by its suffix, like
+// "string" for "foo.string", or an empty string if the key is not typed.
+func ParamType(key string) string {
+	for _, typ := range paramTypes {
+		if suffix := "." + typ; strings.HasSuffix(key, suffix) && len(key) > len(suffix) {
+			return typ
+		}
+	}
+	return ""
+}
+
 func (pk ParamsKeeper) GetString(ctx sdk.Context, key string, ptr *string) {
 	checkSuffix(key, ".string")
 	pk.getIfExists(ctx, key, ptr)
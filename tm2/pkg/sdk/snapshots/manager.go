@@ -0,0 +1,406 @@
+// Package snapshots implements the creation and restoration of state sync
+// snapshots of the application multistore.
+//
+// A snapshot is the zlib-compressed stream of the exported store items,
+// split into fixed-size chunks. The snapshot metadata holds the hash of
+// every chunk, so chunks can be verified as soon as they are received. The
+// restored state is verified against the trusted app hash once the last
+// chunk is applied.
+//
+// NOTE: only the merkleized stores are covered by the app hash, so only them
+// are part of the snapshots. The other stores (e.g. dbadapter stores) need to
+// be excluded from the snapshots, and are left empty once restored: apps that
+// keep state in them can't be restored out of snapshots.
+package snapshots
+
+import (
+	"bufio"
+	"bytes"
+	"compress/zlib"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"sync"
+
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	"github.com/gnolang/gno/tm2/pkg/store/types"
+)
+
+const (
+	// SnapshotFormat is the current snapshot format
+	SnapshotFormat uint32 = 1
+
+	// ChunkSize is the maximum size of a snapshot chunk
+	ChunkSize = 4 << 20 // 4MB
+
+	// maxItemSize is the maximum size of a single encoded snapshot item
+	maxItemSize = 64 << 20 // 64MB
+)
+
+var (
+	ErrUnknownFormat      = errors.New("unknown snapshot format")
+	ErrInvalidMetadata    = errors.New("invalid snapshot metadata")
+	ErrInvalidChunkIndex  = errors.New("invalid chunk index")
+	ErrChunkHashMismatch  = errors.New("chunk hash mismatch")
+	ErrAppHashMismatch    = errors.New("restored app hash mismatch")
+	ErrNoRestoration      = errors.New("no snapshot restoration in progress")
+	ErrRestorationStarted = errors.New("snapshot restoration already started writing state")
+)
+
+// metadata is the snapshot metadata, as stored in abci.Snapshot.Metadata
+type metadata struct {
+	ChunkHashes [][]byte
+}
+
+// snapshotEntry is a single item of the snapshot stream
+type snapshotEntry struct {
+	Store string
+	Item  types.SnapshotItem
+}
+
+// Manager creates snapshots of the multistore, serves them,
+// and restores the multistore out of snapshots received from peers
+type Manager struct {
+	mux sync.Mutex
+
+	store    *Store
+	ms       types.SnapshotMultiStore
+	excluded []types.StoreKey // non-merkleized stores
+
+	restore *restoration // active restoration, if any
+	dirty   bool         // flag indicating if a failed restoration wrote state
+}
+
+// NewManager creates a new snapshot manager, for the multistore
+// without the given excluded stores
+func NewManager(store *Store, ms types.SnapshotMultiStore, excluded ...types.StoreKey) *Manager {
+	return &Manager{
+		store:    store,
+		ms:       ms,
+		excluded: excluded,
+	}
+}
+
+// Create creates a snapshot of the multistore at the given height. The
+// height needs to be retained if new versions are committed concurrently
+func (m *Manager) Create(height int64) (*abci.Snapshot, error) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	if _, err := m.store.Get(height, SnapshotFormat); err == nil {
+		return nil, fmt.Errorf("snapshot at height %d already exists", height)
+	}
+
+	// Remove any leftovers of an interrupted creation
+	m.store.Delete(height, SnapshotFormat)
+
+	var (
+		cw = newChunkWriter(func(index uint32, chunk []byte) {
+			m.store.saveChunk(height, SnapshotFormat, index, chunk)
+		})
+
+		zw = zlib.NewWriter(cw)
+		bw = bufio.NewWriter(zw)
+	)
+
+	err := m.ms.Export(height, m.excluded, func(store string, item types.SnapshotItem) error {
+		_, err := amino.MarshalSizedWriter(bw, snapshotEntry{
+			Store: store,
+			Item:  item,
+		})
+
+		return err
+	})
+	if err == nil {
+		err = bw.Flush()
+	}
+
+	if err == nil {
+		err = zw.Close()
+	}
+
+	if err != nil {
+		m.store.Delete(height, SnapshotFormat)
+
+		return nil, fmt.Errorf("unable to export snapshot, %w", err)
+	}
+
+	cw.flush()
+
+	raw, err := amino.Marshal(metadata{ChunkHashes: cw.chunkHashes})
+	if err != nil {
+		return nil, fmt.Errorf("unable to marshal snapshot metadata, %w", err)
+	}
+
+	snapshot := &abci.Snapshot{
+		Height:   height,
+		Format:   SnapshotFormat,
+		Chunks:   uint32(len(cw.chunkHashes)),
+		Hash:     cw.hash.Sum(nil),
+		Metadata: raw,
+	}
+
+	if err := m.store.save(snapshot); err != nil {
+		return nil, err
+	}
+
+	return snapshot, nil
+}
+
+// Retain prevents the multistore version at the given height from being
+// pruned, until the returned release function is called
+func (m *Manager) Retain(height int64) (func(), error) {
+	return m.ms.RetainVersion(height, m.excluded)
+}
+
+// List returns the available snapshots, the most recent first
+func (m *Manager) List() ([]*abci.Snapshot, error) {
+	return m.store.List()
+}
+
+// LoadChunk returns the given snapshot chunk
+func (m *Manager) LoadChunk(height int64, format, index uint32) ([]byte, error) {
+	return m.store.LoadChunk(height, format, index)
+}
+
+// Prune deletes all but the given number of most recent snapshots
+func (m *Manager) Prune(keepRecent uint32) error {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	return m.store.Prune(keepRecent)
+}
+
+// Offer starts the restoration of the given snapshot. The restored
+// multistore will be verified against the given app hash.
+// Offering a new snapshot abandons the previous restoration, unless
+// it already started writing the restored state.
+func (m *Manager) Offer(snapshot *abci.Snapshot, appHash []byte) error {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	if snapshot.Format != SnapshotFormat {
+		return fmt.Errorf("%w %d", ErrUnknownFormat, snapshot.Format)
+	}
+
+	var meta metadata
+	if err := amino.Unmarshal(snapshot.Metadata, &meta); err != nil {
+		return fmt.Errorf("%w, %w", ErrInvalidMetadata, err)
+	}
+
+	if snapshot.Height <= 0 || snapshot.Chunks == 0 || int(snapshot.Chunks) != len(meta.ChunkHashes) {
+		return fmt.Errorf("%w, chunk count mismatch", ErrInvalidMetadata)
+	}
+
+	if len(appHash) == 0 {
+		return fmt.Errorf("%w, missing app hash", ErrInvalidMetadata)
+	}
+
+	if m.dirty {
+		return ErrRestorationStarted
+	}
+
+	if m.restore != nil {
+		if m.restore.started {
+			return ErrRestorationStarted
+		}
+
+		m.restore.abort()
+	}
+
+	m.restore = newRestoration(m.ms, m.excluded, snapshot, meta, appHash)
+
+	return nil
+}
+
+// ApplyChunk applies the next chunk of the offered snapshot.
+// Returns a flag indicating if the restoration is complete
+func (m *Manager) ApplyChunk(index uint32, chunk []byte) (bool, error) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	if m.restore == nil {
+		return false, ErrNoRestoration
+	}
+
+	done, err := m.restore.apply(index, chunk)
+	if done || (err != nil && !errors.Is(err, ErrChunkHashMismatch) && !errors.Is(err, ErrInvalidChunkIndex)) {
+		// The restoration is over, successfully or not
+		m.dirty = err != nil && m.restore.started
+		m.restore = nil
+	}
+
+	return done, err
+}
+
+// restoration is a snapshot restoration in progress
+type restoration struct {
+	snapshot *abci.Snapshot
+	meta     metadata
+	appHash  []byte
+
+	ms       types.SnapshotMultiStore
+	excluded []types.StoreKey
+
+	next    uint32 // index of the next expected chunk
+	started bool   // flag indicating if chunks were applied
+
+	pw     *io.PipeWriter
+	doneCh chan error
+}
+
+func newRestoration(
+	ms types.SnapshotMultiStore,
+	excluded []types.StoreKey,
+	snapshot *abci.Snapshot,
+	meta metadata,
+	appHash []byte,
+) *restoration {
+	pr, pw := io.Pipe()
+
+	r := &restoration{
+		snapshot: snapshot,
+		meta:     meta,
+		appHash:  appHash,
+		ms:       ms,
+		excluded: excluded,
+		pw:       pw,
+		doneCh:   make(chan error, 1),
+	}
+
+	go func() {
+		err := r.run(pr)
+
+		// Unblock any pending chunk write
+		pr.CloseWithError(err)
+
+		r.doneCh <- err
+	}()
+
+	return r
+}
+
+// run restores the multistore out of the snapshot stream
+func (r *restoration) run(stream io.Reader) error {
+	zr, err := zlib.NewReader(stream)
+	if err != nil {
+		return fmt.Errorf("unable to read snapshot stream, %w", err)
+	}
+	defer zr.Close()
+
+	br := bufio.NewReader(zr)
+
+	return r.ms.Restore(r.snapshot.Height, r.excluded, func() (string, types.SnapshotItem, error) {
+		var entry snapshotEntry
+
+		if _, err := amino.UnmarshalSizedReader(br, &entry, maxItemSize); err != nil {
+			if errors.Is(err, io.EOF) {
+				return "", types.SnapshotItem{}, io.EOF
+			}
+
+			return "", types.SnapshotItem{}, fmt.Errorf("unable to read snapshot item, %w", err)
+		}
+
+		return entry.Store, entry.Item, nil
+	})
+}
+
+// apply applies the given chunk
+func (r *restoration) apply(index uint32, chunk []byte) (bool, error) {
+	if index != r.next {
+		return false, fmt.Errorf("%w %d, expected %d", ErrInvalidChunkIndex, index, r.next)
+	}
+
+	if sum := sha256.Sum256(chunk); !bytes.Equal(sum[:], r.meta.ChunkHashes[index]) {
+		return false, fmt.Errorf("%w for chunk %d", ErrChunkHashMismatch, index)
+	}
+
+	r.started = true
+
+	if _, err := r.pw.Write(chunk); err != nil {
+		return false, fmt.Errorf("unable to restore snapshot, %w", <-r.doneCh)
+	}
+
+	r.next++
+
+	if r.next < r.snapshot.Chunks {
+		return false, nil
+	}
+
+	// Last chunk, wait for the restoration to complete
+	r.pw.Close()
+
+	if err := <-r.doneCh; err != nil {
+		return false, fmt.Errorf("unable to restore snapshot, %w", err)
+	}
+
+	if hash := r.ms.LastCommitID().Hash; !bytes.Equal(hash, r.appHash) {
+		return false, fmt.Errorf("%w, expected %X, got %X", ErrAppHashMismatch, r.appHash, hash)
+	}
+
+	return true, nil
+}
+
+// abort abandons the restoration
+func (r *restoration) abort() {
+	r.pw.CloseWithError(errors.New("snapshot restoration aborted"))
+
+	<-r.doneCh
+}
+
+// chunkWriter splits the written snapshot stream into chunks
+type chunkWriter struct {
+	saveFn func(index uint32, chunk []byte)
+
+	buf         []byte
+	chunkHashes [][]byte
+	hash        hash.Hash // hash of the entire stream
+}
+
+func newChunkWriter(saveFn func(uint32, []byte)) *chunkWriter {
+	return &chunkWriter{
+		saveFn: saveFn,
+		buf:    make([]byte, 0, ChunkSize),
+		hash:   sha256.New(),
+	}
+}
+
+// Write implements io.Writer
+func (w *chunkWriter) Write(p []byte) (int, error) {
+	n := len(p)
+
+	w.hash.Write(p)
+
+	for len(p) > 0 {
+		free := ChunkSize - len(w.buf)
+		if free > len(p) {
+			free = len(p)
+		}
+
+		w.buf = append(w.buf, p[:free]...)
+		p = p[free:]
+
+		if len(w.buf) == ChunkSize {
+			w.flush()
+		}
+	}
+
+	return n, nil
+}
+
+// flush saves the buffered chunk, if any
+func (w *chunkWriter) flush() {
+	if len(w.buf) == 0 {
+		return
+	}
+
+	sum := sha256.Sum256(w.buf)
+
+	w.saveFn(uint32(len(w.chunkHashes)), w.buf)
+	w.chunkHashes = append(w.chunkHashes, sum[:])
+
+	w.buf = make([]byte, 0, ChunkSize)
+}
@@ -0,0 +1,237 @@
+package snapshots
+
+import (
+	"crypto/rand"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	"github.com/gnolang/gno/tm2/pkg/db/memdb"
+	"github.com/gnolang/gno/tm2/pkg/store/dbadapter"
+	"github.com/gnolang/gno/tm2/pkg/store/iavl"
+	"github.com/gnolang/gno/tm2/pkg/store/rootmulti"
+	"github.com/gnolang/gno/tm2/pkg/store/types"
+)
+
+var (
+	mainKey = types.NewStoreKey("main")
+	baseKey = types.NewStoreKey("base")
+)
+
+// newMultiStore creates a new multistore with an IAVL and a plain store
+func newMultiStore(t *testing.T) types.SnapshotMultiStore {
+	t.Helper()
+
+	db := memdb.NewMemDB()
+
+	ms := rootmulti.NewMultiStore(db)
+	ms.MountStoreWithDB(mainKey, iavl.StoreConstructor, db)
+	ms.MountStoreWithDB(baseKey, dbadapter.StoreConstructor, db)
+
+	require.NoError(t, ms.LoadLatestVersion())
+
+	return ms
+}
+
+// populate writes random values to the multistore, and commits them
+func populate(t *testing.T, ms types.SnapshotMultiStore, count int) types.CommitID {
+	t.Helper()
+
+	var (
+		main = ms.GetStore(mainKey)
+		base = ms.GetStore(baseKey)
+	)
+
+	for i := 0; i < count; i++ {
+		value := make([]byte, 1024)
+
+		_, err := rand.Read(value)
+		require.NoError(t, err)
+
+		main.Set([]byte(fmt.Sprintf("main-%d", i)), value)
+		base.Set([]byte(fmt.Sprintf("base-%d", i)), value)
+	}
+
+	return ms.Commit()
+}
+
+// restore restores the given snapshot using the source manager chunks
+func restore(t *testing.T, source, target *Manager, snapshot *abci.Snapshot, appHash []byte) {
+	t.Helper()
+
+	require.NoError(t, target.Offer(snapshot, appHash))
+
+	for index := uint32(0); index < snapshot.Chunks; index++ {
+		chunk, err := source.LoadChunk(snapshot.Height, snapshot.Format, index)
+		require.NoError(t, err)
+
+		done, err := target.ApplyChunk(index, chunk)
+		require.NoError(t, err)
+		assert.Equal(t, index == snapshot.Chunks-1, done)
+	}
+}
+
+func TestManager_CreateRestore(t *testing.T) {
+	t.Parallel()
+
+	ms := newMultiStore(t)
+
+	populate(t, ms, 100)
+	commitID := populate(t, ms, 5000) // enough random data for multiple chunks
+
+	manager := NewManager(NewStore(memdb.NewMemDB()), ms, baseKey)
+
+	snapshot, err := manager.Create(commitID.Version)
+	require.NoError(t, err)
+
+	assert.Equal(t, commitID.Version, snapshot.Height)
+	assert.Greater(t, snapshot.Chunks, uint32(1))
+
+	snapshots, err := manager.List()
+	require.NoError(t, err)
+	assert.Equal(t, []*abci.Snapshot{snapshot}, snapshots)
+
+	// Restore the snapshot into a new multistore
+	restored := newMultiStore(t)
+	target := NewManager(NewStore(memdb.NewMemDB()), restored, baseKey)
+
+	restore(t, manager, target, snapshot, commitID.Hash)
+
+	assert.Equal(t, commitID, restored.LastCommitID())
+	assert.Equal(t, ms.GetStore(mainKey).Get([]byte("main-10")), restored.GetStore(mainKey).Get([]byte("main-10")))
+
+	// The excluded base store isn't restored
+	assert.Nil(t, restored.GetStore(baseKey).Get([]byte("base-10")))
+}
+
+func TestManager_NonMerkleizedStore(t *testing.T) {
+	t.Parallel()
+
+	ms := newMultiStore(t)
+	commitID := populate(t, ms, 10)
+
+	// The base store isn't covered by the app hash, so it can't be exported
+	manager := NewManager(NewStore(memdb.NewMemDB()), ms)
+
+	_, err := manager.Create(commitID.Version)
+	assert.ErrorContains(t, err, "store base doesn't support snapshots")
+
+	snapshots, err := manager.List()
+	require.NoError(t, err)
+	assert.Empty(t, snapshots)
+}
+
+func TestManager_Prune(t *testing.T) {
+	t.Parallel()
+
+	ms := newMultiStore(t)
+	manager := NewManager(NewStore(memdb.NewMemDB()), ms, baseKey)
+
+	for i := 0; i < 4; i++ {
+		commitID := populate(t, ms, 10)
+
+		_, err := manager.Create(commitID.Version)
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, manager.Prune(2))
+
+	snapshots, err := manager.List()
+	require.NoError(t, err)
+	require.Len(t, snapshots, 2)
+
+	assert.Equal(t, int64(4), snapshots[0].Height)
+	assert.Equal(t, int64(3), snapshots[1].Height)
+
+	_, err = manager.LoadChunk(1, SnapshotFormat, 0)
+	assert.ErrorIs(t, err, errChunkNotFound)
+}
+
+func TestManager_Offer(t *testing.T) {
+	t.Parallel()
+
+	ms := newMultiStore(t)
+	commitID := populate(t, ms, 10)
+
+	manager := NewManager(NewStore(memdb.NewMemDB()), ms, baseKey)
+
+	snapshot, err := manager.Create(commitID.Version)
+	require.NoError(t, err)
+
+	t.Run("unknown format", func(t *testing.T) {
+		t.Parallel()
+
+		target := NewManager(NewStore(memdb.NewMemDB()), newMultiStore(t), baseKey)
+
+		invalid := *snapshot
+		invalid.Format = SnapshotFormat + 1
+
+		assert.ErrorIs(t, target.Offer(&invalid, commitID.Hash), ErrUnknownFormat)
+	})
+
+	t.Run("invalid metadata", func(t *testing.T) {
+		t.Parallel()
+
+		target := NewManager(NewStore(memdb.NewMemDB()), newMultiStore(t), baseKey)
+
+		invalid := *snapshot
+		invalid.Chunks++
+
+		assert.ErrorIs(t, target.Offer(&invalid, commitID.Hash), ErrInvalidMetadata)
+	})
+
+	t.Run("app hash mismatch", func(t *testing.T) {
+		t.Parallel()
+
+		target := NewManager(NewStore(memdb.NewMemDB()), newMultiStore(t), baseKey)
+		require.NoError(t, target.Offer(snapshot, []byte("invalid app hash")))
+
+		chunk, err := manager.LoadChunk(snapshot.Height, snapshot.Format, 0)
+		require.NoError(t, err)
+
+		_, err = target.ApplyChunk(0, chunk)
+		assert.ErrorIs(t, err, ErrAppHashMismatch)
+
+		// The failed restoration left state behind
+		assert.ErrorIs(t, target.Offer(snapshot, commitID.Hash), ErrRestorationStarted)
+	})
+}
+
+func TestManager_ApplyChunk_Invalid(t *testing.T) {
+	t.Parallel()
+
+	ms := newMultiStore(t)
+	commitID := populate(t, ms, 10)
+
+	manager := NewManager(NewStore(memdb.NewMemDB()), ms, baseKey)
+
+	snapshot, err := manager.Create(commitID.Version)
+	require.NoError(t, err)
+
+	chunk, err := manager.LoadChunk(snapshot.Height, snapshot.Format, 0)
+	require.NoError(t, err)
+
+	target := NewManager(NewStore(memdb.NewMemDB()), newMultiStore(t), baseKey)
+
+	// No snapshot was offered
+	_, err = target.ApplyChunk(0, chunk)
+	assert.ErrorIs(t, err, ErrNoRestoration)
+
+	require.NoError(t, target.Offer(snapshot, commitID.Hash))
+
+	// Unexpected chunk index
+	_, err = target.ApplyChunk(1, chunk)
+	assert.ErrorIs(t, err, ErrInvalidChunkIndex)
+
+	// Tampered chunk
+	_, err = target.ApplyChunk(0, append([]byte{0x1}, chunk...))
+	assert.ErrorIs(t, err, ErrChunkHashMismatch)
+
+	// The restoration can continue with the valid chunk
+	done, err := target.ApplyChunk(0, chunk)
+	require.NoError(t, err)
+	assert.True(t, done)
+}
@@ -0,0 +1,163 @@
+package snapshots
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	dbm "github.com/gnolang/gno/tm2/pkg/db"
+)
+
+var (
+	errSnapshotNotFound = errors.New("snapshot not found")
+	errChunkNotFound    = errors.New("snapshot chunk not found")
+)
+
+// Key layout:
+//
+//	s/<height><format>        -> amino(abci.Snapshot)
+//	c/<height><format><index> -> chunk
+//
+// Heights are 8-byte and formats and indexes 4-byte big endian integers
+var (
+	snapshotPrefix = []byte("s/")
+	chunkPrefix    = []byte("c/")
+)
+
+// Store persists the created snapshots, and their chunks
+type Store struct {
+	db dbm.DB
+}
+
+// NewStore creates a new snapshot store, backed by the given database
+func NewStore(db dbm.DB) *Store {
+	return &Store{
+		db: db,
+	}
+}
+
+// Get returns the snapshot with the given height and format
+func (s *Store) Get(height int64, format uint32) (*abci.Snapshot, error) {
+	raw := s.db.Get(snapshotKey(height, format))
+	if raw == nil {
+		return nil, errSnapshotNotFound
+	}
+
+	var snapshot abci.Snapshot
+	if err := amino.Unmarshal(raw, &snapshot); err != nil {
+		return nil, fmt.Errorf("unable to unmarshal snapshot, %w", err)
+	}
+
+	return &snapshot, nil
+}
+
+// List returns the stored snapshots, the most recent first
+func (s *Store) List() ([]*abci.Snapshot, error) {
+	it := dbm.IteratePrefix(s.db, snapshotPrefix)
+	defer it.Close()
+
+	snapshots := make([]*abci.Snapshot, 0)
+
+	for ; it.Valid(); it.Next() {
+		var snapshot abci.Snapshot
+		if err := amino.Unmarshal(it.Value(), &snapshot); err != nil {
+			return nil, fmt.Errorf("unable to unmarshal snapshot, %w", err)
+		}
+
+		snapshots = append(snapshots, &snapshot)
+	}
+
+	sort.SliceStable(snapshots, func(i, j int) bool {
+		return snapshots[i].Height > snapshots[j].Height
+	})
+
+	return snapshots, nil
+}
+
+// LoadChunk returns the chunk of the given snapshot
+func (s *Store) LoadChunk(height int64, format, index uint32) ([]byte, error) {
+	chunk := s.db.Get(chunkKey(height, format, index))
+	if chunk == nil {
+		return nil, errChunkNotFound
+	}
+
+	return chunk, nil
+}
+
+// saveChunk saves a single chunk of a snapshot that's being created
+func (s *Store) saveChunk(height int64, format, index uint32, chunk []byte) {
+	s.db.Set(chunkKey(height, format, index), chunk)
+}
+
+// save saves the snapshot, whose chunks were already saved
+func (s *Store) save(snapshot *abci.Snapshot) error {
+	raw, err := amino.Marshal(snapshot)
+	if err != nil {
+		return fmt.Errorf("unable to marshal snapshot, %w", err)
+	}
+
+	s.db.SetSync(snapshotKey(snapshot.Height, snapshot.Format), raw)
+
+	return nil
+}
+
+// Delete deletes the snapshot with the given height and format, with its chunks
+func (s *Store) Delete(height int64, format uint32) {
+	batch := s.db.NewBatch()
+	defer batch.Close()
+
+	batch.Delete(snapshotKey(height, format))
+
+	// Also delete chunks left behind by an interrupted creation
+	it := dbm.IteratePrefix(s.db, chunkKey(height, format, 0)[:len(chunkPrefix)+12])
+	for ; it.Valid(); it.Next() {
+		batch.Delete(it.Key())
+	}
+	it.Close()
+
+	batch.WriteSync()
+}
+
+// Prune deletes all but the given number of most recent snapshots
+func (s *Store) Prune(keepRecent uint32) error {
+	snapshots, err := s.List()
+	if err != nil {
+		return err
+	}
+
+	if len(snapshots) <= int(keepRecent) {
+		return nil
+	}
+
+	for _, snapshot := range snapshots[keepRecent:] {
+		s.Delete(snapshot.Height, snapshot.Format)
+	}
+
+	return nil
+}
+
+// snapshotKey returns the key of the given snapshot
+func snapshotKey(height int64, format uint32) []byte {
+	key := make([]byte, len(snapshotPrefix)+12)
+
+	copy(key, snapshotPrefix)
+	binary.BigEndian.PutUint64(key[len(snapshotPrefix):], uint64(height))
+	binary.BigEndian.PutUint32(key[len(snapshotPrefix)+8:], format)
+
+	return key
+}
+
+// chunkKey returns the key of the given snapshot chunk
+func chunkKey(height int64, format, index uint32) []byte {
+	key := make([]byte, len(chunkPrefix)+16)
+
+	copy(key, chunkPrefix)
+	binary.BigEndian.PutUint64(key[len(chunkPrefix):], uint64(height))
+	binary.BigEndian.PutUint32(key[len(chunkPrefix)+8:], format)
+	binary.BigEndian.PutUint32(key[len(chunkPrefix)+12:], index)
+
+	return key
+}
@@ -25,6 +25,12 @@ type Result struct {
 	abci.ResponseBase
 	GasWanted int64
 	GasUsed   int64
+
+	// Set by the AnteHandler, to order the txs in the mempool; see
+	// abci.ResponseCheckTx.
+	Priority int64
+	Sender   string
+	Sequence uint64
 }
 
 // AnteHandler authenticates transactions, before their internal messages are handled.
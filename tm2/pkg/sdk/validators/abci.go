@@ -0,0 +1,12 @@
+package validators
+
+import (
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	"github.com/gnolang/gno/tm2/pkg/sdk"
+)
+
+// EndBlocker is called in the EndBlock(), it returns the updates of the
+// validator set made in the block, to be applied by the consensus.
+func EndBlocker(ctx sdk.Context, vk ValidatorKeeperI) []abci.ValidatorUpdate {
+	return vk.PopValidatorUpdates(ctx)
+}
@@ -0,0 +1,34 @@
+package validators
+
+import (
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+)
+
+const (
+	// module name
+	ModuleName = "validators"
+
+	// StoreKey is string representation of the store key for validators
+	StoreKey = ModuleName
+
+	// QuerierRoute is the querier route for validators
+	QuerierRoute = ModuleName
+
+	// ValidatorStoreKeyPrefix prefix for validator-by-address store
+	ValidatorStoreKeyPrefix = "/v/"
+	// PendingUpdateStoreKeyPrefix prefix for the validators changed in the
+	// current block, cleared by the EndBlocker
+	PendingUpdateStoreKeyPrefix = "/u/"
+)
+
+// ValidatorStoreKey turns an address to the key used to get its validator
+// from the store.
+func ValidatorStoreKey(addr crypto.Address) []byte {
+	return append([]byte(ValidatorStoreKeyPrefix), addr.Bytes()...)
+}
+
+// PendingUpdateStoreKey turns an address to the key used to mark its
+// validator as changed in the current block.
+func PendingUpdateStoreKey(addr crypto.Address) []byte {
+	return append([]byte(PendingUpdateStoreKeyPrefix), addr.Bytes()...)
+}
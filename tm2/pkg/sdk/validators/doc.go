@@ -0,0 +1,14 @@
+// Package validators provides a staking-free proof-of-authority module to
+// manage the validator set of a chain.
+//
+// The validators are added, removed and given voting power with messages
+// signed by the admin set in the params, which can be a multisig account, and
+// the changes made in a block are returned by EndBlocker, to be applied by
+// the consensus. An app wires it like the other modules:
+//
+//	vk := validators.NewValidatorKeeper(mainKey, paramsKpr)
+//	baseApp.Router().AddRoute(validators.RouterKey, validators.NewHandler(vk))
+//
+// returning vk.InitGenesis from its InitChainer, with the validators of the
+// genesis of the consensus, and validators.EndBlocker from its EndBlocker.
+package validators
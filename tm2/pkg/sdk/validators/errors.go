@@ -0,0 +1,37 @@
+package validators
+
+import (
+	"fmt"
+
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/gnolang/gno/tm2/pkg/errors"
+)
+
+// for convenience:
+type abciError struct{}
+
+func (abciError) AssertABCIError() {}
+
+// declare all validators errors.
+// NOTE: these are meant to be used in conjunction with pkgs/errors.
+type (
+	ValidatorExistsError   struct{ abciError }
+	ValidatorNotFoundError struct{ abciError }
+	LastValidatorError     struct{ abciError }
+)
+
+func (e ValidatorExistsError) Error() string   { return "validator already exists" }
+func (e ValidatorNotFoundError) Error() string { return "validator not found" }
+func (e LastValidatorError) Error() string     { return "cannot remove the last validator" }
+
+func ErrValidatorExists(addr crypto.Address) error {
+	return errors.Wrap(ValidatorExistsError{}, fmt.Sprintf("validator %s already in the set", addr))
+}
+
+func ErrValidatorNotFound(addr crypto.Address) error {
+	return errors.Wrap(ValidatorNotFoundError{}, fmt.Sprintf("validator %s not in the set", addr))
+}
+
+func ErrLastValidator() error {
+	return errors.Wrap(LastValidatorError{}, "")
+}
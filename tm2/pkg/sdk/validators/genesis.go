@@ -0,0 +1,31 @@
+package validators
+
+import (
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	"github.com/gnolang/gno/tm2/pkg/sdk"
+)
+
+// InitGenesis - Init store state from genesis data, and return the initial
+// validator set of the consensus. If set, it must match the validators of
+// the genesis of the consensus.
+func (vk ValidatorKeeper) InitGenesis(ctx sdk.Context, data GenesisState) []abci.ValidatorUpdate {
+	if err := ValidateGenesis(data); err != nil {
+		panic(err)
+	}
+
+	if err := vk.SetParams(ctx, data.Params); err != nil {
+		panic(err)
+	}
+
+	updates := make([]abci.ValidatorUpdate, 0, len(data.Validators))
+	for _, val := range data.Validators {
+		vk.setValidator(ctx, val)
+		updates = append(updates, val.ABCIValidatorUpdate())
+	}
+	return updates
+}
+
+// ExportGenesis returns a GenesisState for a given context and keeper
+func (vk ValidatorKeeper) ExportGenesis(ctx sdk.Context) GenesisState {
+	return NewGenesisState(vk.GetParams(ctx), vk.GetValidators(ctx))
+}
@@ -0,0 +1,193 @@
+package validators
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/gnolang/gno/tm2/pkg/sdk"
+	"github.com/gnolang/gno/tm2/pkg/std"
+)
+
+type validatorsHandler struct {
+	vk ValidatorKeeper
+}
+
+// NewHandler returns a handler for "validators" type messages.
+func NewHandler(vk ValidatorKeeper) validatorsHandler {
+	return validatorsHandler{
+		vk: vk,
+	}
+}
+
+func (vh validatorsHandler) Process(ctx sdk.Context, msg std.Msg) sdk.Result {
+	// All the messages must be signed by the admin.
+	if admin := vh.vk.GetParams(ctx).Admin; admin.IsZero() || !hasSigner(msg, admin) {
+		return abciResult(std.ErrUnauthorized("validator set can only be changed by the admin"))
+	}
+
+	switch msg := msg.(type) {
+	case MsgAddValidator:
+		return vh.handleMsgAddValidator(ctx, msg)
+
+	case MsgRemoveValidator:
+		return vh.handleMsgRemoveValidator(ctx, msg)
+
+	case MsgSetValidatorPower:
+		return vh.handleMsgSetValidatorPower(ctx, msg)
+
+	case MsgSetAdmin:
+		return vh.handleMsgSetAdmin(ctx, msg)
+
+	default:
+		errMsg := fmt.Sprintf("unrecognized validators message type: %T", msg)
+		return abciResult(std.ErrUnknownRequest(errMsg))
+	}
+}
+
+// Handle MsgAddValidator.
+func (vh validatorsHandler) handleMsgAddValidator(ctx sdk.Context, msg MsgAddValidator) sdk.Result {
+	err := vh.vk.AddValidator(ctx, NewValidator(msg.PubKey, msg.Power))
+	if err != nil {
+		return abciResult(err)
+	}
+	return sdk.Result{}
+}
+
+// Handle MsgRemoveValidator.
+func (vh validatorsHandler) handleMsgRemoveValidator(ctx sdk.Context, msg MsgRemoveValidator) sdk.Result {
+	err := vh.vk.RemoveValidator(ctx, msg.Address)
+	if err != nil {
+		return abciResult(err)
+	}
+	return sdk.Result{}
+}
+
+// Handle MsgSetValidatorPower.
+func (vh validatorsHandler) handleMsgSetValidatorPower(ctx sdk.Context, msg MsgSetValidatorPower) sdk.Result {
+	err := vh.vk.SetValidatorPower(ctx, msg.Address, msg.Power)
+	if err != nil {
+		return abciResult(err)
+	}
+	return sdk.Result{}
+}
+
+// Handle MsgSetAdmin.
+func (vh validatorsHandler) handleMsgSetAdmin(ctx sdk.Context, msg MsgSetAdmin) sdk.Result {
+	params := vh.vk.GetParams(ctx)
+	params.Admin = msg.NewAdmin
+	if err := vh.vk.SetParams(ctx, params); err != nil {
+		return abciResult(std.ErrInternal(err.Error()))
+	}
+	return sdk.Result{}
+}
+
+func hasSigner(msg std.Msg, addr crypto.Address) bool {
+	for _, signer := range msg.GetSigners() {
+		if signer == addr {
+			return true
+		}
+	}
+	return false
+}
+
+//----------------------------------------
+// Query
+
+// query paths
+const (
+	QueryValidators = "validators"
+	QueryParams     = "params"
+)
+
+func (vh validatorsHandler) Query(ctx sdk.Context, req abci.RequestQuery) (res abci.ResponseQuery) {
+	switch secondPart(req.Path) {
+	case QueryValidators:
+		return vh.queryValidators(ctx, req)
+	case QueryParams:
+		return vh.queryParams(ctx, req)
+	default:
+		res = sdk.ABCIResponseQueryFromError(
+			std.ErrUnknownRequest("unknown validators query endpoint"))
+		return
+	}
+}
+
+// queryValidators fetches the validator set for the supplied height, or a
+// single validator if its address is passed as path component.
+func (vh validatorsHandler) queryValidators(ctx sdk.Context, req abci.RequestQuery) (res abci.ResponseQuery) {
+	var result any
+	if b32addr := thirdPart(req.Path); b32addr != "" {
+		addr, err := crypto.AddressFromBech32(b32addr)
+		if err != nil {
+			res = sdk.ABCIResponseQueryFromError(
+				std.ErrInvalidAddress(
+					"invalid query address " + b32addr))
+			return
+		}
+		val, ok := vh.vk.GetValidator(ctx, addr)
+		if !ok {
+			res = sdk.ABCIResponseQueryFromError(ErrValidatorNotFound(addr))
+			return
+		}
+		result = val
+	} else {
+		vals := vh.vk.GetValidators(ctx)
+		if vals == nil {
+			vals = []Validator{}
+		}
+		result = vals
+	}
+
+	bz, err := amino.MarshalJSONIndent(result, "", "  ")
+	if err != nil {
+		res = sdk.ABCIResponseQueryFromError(
+			std.ErrInternal(fmt.Sprintf("could not marshal result to JSON: %s", err.Error())))
+		return
+	}
+
+	res.Data = bz
+	return
+}
+
+// queryParams fetches the params of the module for the supplied height.
+func (vh validatorsHandler) queryParams(ctx sdk.Context, req abci.RequestQuery) (res abci.ResponseQuery) {
+	bz, err := amino.MarshalJSONIndent(vh.vk.GetParams(ctx), "", "  ")
+	if err != nil {
+		res = sdk.ABCIResponseQueryFromError(
+			std.ErrInternal(fmt.Sprintf("could not marshal result to JSON: %s", err.Error())))
+		return
+	}
+
+	res.Data = bz
+	return
+}
+
+//----------------------------------------
+// misc
+
+func abciResult(err error) sdk.Result {
+	return sdk.ABCIResultFromError(err)
+}
+
+// returns the second component of a path.
+func secondPart(path string) string {
+	parts := strings.Split(path, "/")
+	if len(parts) < 2 {
+		return ""
+	} else {
+		return parts[1]
+	}
+}
+
+// returns the third component of a path.
+func thirdPart(path string) string {
+	parts := strings.Split(path, "/")
+	if len(parts) < 3 {
+		return ""
+	} else {
+		return parts[2]
+	}
+}
@@ -0,0 +1,112 @@
+package validators
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	tu "github.com/gnolang/gno/tm2/pkg/sdk/testutils"
+)
+
+func TestInvalidMsg(t *testing.T) {
+	t.Parallel()
+
+	env := setupTestEnv()
+	h := NewHandler(env.vk)
+	res := h.Process(env.ctx, tu.NewTestMsg(env.admin))
+	require.False(t, res.IsOK())
+	require.True(t, strings.Contains(res.Log, "unrecognized validators message type"))
+}
+
+func TestHandlerValidatorMsgs(t *testing.T) {
+	t.Parallel()
+
+	env := setupTestEnv()
+	h := NewHandler(env.vk)
+	val1 := newTestValidator(10)
+	val2 := newTestValidator(20)
+	env.vk.InitGenesis(env.ctx, NewGenesisState(NewParams(env.admin), []Validator{val1}))
+
+	// not the admin.
+	other := crypto.AddressFromPreimage([]byte("other"))
+	res := h.Process(env.ctx, NewMsgAddValidator(other, val2.PubKey, val2.Power))
+	require.False(t, res.IsOK())
+	assert.Contains(t, res.Log, "can only be changed by the admin")
+
+	res = h.Process(env.ctx, NewMsgAddValidator(env.admin, val2.PubKey, val2.Power))
+	require.True(t, res.IsOK(), res.Log)
+	res = h.Process(env.ctx, NewMsgAddValidator(env.admin, val2.PubKey, val2.Power))
+	require.False(t, res.IsOK())
+
+	res = h.Process(env.ctx, NewMsgSetValidatorPower(env.admin, val2.Address, 25))
+	require.True(t, res.IsOK(), res.Log)
+	got, _ := env.vk.GetValidator(env.ctx, val2.Address)
+	assert.Equal(t, int64(25), got.Power)
+
+	res = h.Process(env.ctx, NewMsgRemoveValidator(env.admin, val1.Address))
+	require.True(t, res.IsOK(), res.Log)
+	res = h.Process(env.ctx, NewMsgRemoveValidator(env.admin, val2.Address))
+	require.False(t, res.IsOK())
+	assert.IsType(t, LastValidatorError{}, res.Error)
+
+	// hand over to a new admin.
+	res = h.Process(env.ctx, NewMsgSetAdmin(env.admin, other))
+	require.True(t, res.IsOK(), res.Log)
+	res = h.Process(env.ctx, NewMsgAddValidator(env.admin, val1.PubKey, val1.Power))
+	require.False(t, res.IsOK())
+	res = h.Process(env.ctx, NewMsgAddValidator(other, val1.PubKey, val1.Power))
+	require.True(t, res.IsOK(), res.Log)
+}
+
+func TestHandlerNoAdmin(t *testing.T) {
+	t.Parallel()
+
+	env := setupTestEnv()
+	h := NewHandler(env.vk)
+	val := newTestValidator(10)
+	env.vk.InitGenesis(env.ctx, DefaultGenesisState())
+
+	res := h.Process(env.ctx, NewMsgAddValidator(env.admin, val.PubKey, val.Power))
+	require.False(t, res.IsOK())
+	assert.Contains(t, res.Log, "can only be changed by the admin")
+}
+
+func TestQuery(t *testing.T) {
+	t.Parallel()
+
+	env := setupTestEnv()
+	h := NewHandler(env.vk)
+	val := newTestValidator(10)
+	env.vk.InitGenesis(env.ctx, NewGenesisState(NewParams(env.admin), []Validator{val}))
+
+	res := h.Query(env.ctx, abci.RequestQuery{Path: "validators/validators"})
+	require.Nil(t, res.Error)
+	var vals []Validator
+	require.NoError(t, amino.UnmarshalJSON(res.Data, &vals))
+	require.Len(t, vals, 1)
+	assert.Equal(t, val.Address, vals[0].Address)
+
+	res = h.Query(env.ctx, abci.RequestQuery{Path: fmt.Sprintf("validators/validators/%s", val.Address)})
+	require.Nil(t, res.Error)
+	var got Validator
+	require.NoError(t, amino.UnmarshalJSON(res.Data, &got))
+	assert.Equal(t, int64(10), got.Power)
+
+	res = h.Query(env.ctx, abci.RequestQuery{Path: fmt.Sprintf("validators/validators/%s", env.admin)})
+	require.Error(t, res.Error)
+
+	res = h.Query(env.ctx, abci.RequestQuery{Path: "validators/params"})
+	require.Nil(t, res.Error)
+	var params Params
+	require.NoError(t, amino.UnmarshalJSON(res.Data, &params))
+	assert.Equal(t, env.admin, params.Admin)
+
+	res = h.Query(env.ctx, abci.RequestQuery{Path: "validators/notfound"})
+	require.Error(t, res.Error)
+}
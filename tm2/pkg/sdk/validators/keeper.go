@@ -0,0 +1,191 @@
+package validators
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	bft "github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/gnolang/gno/tm2/pkg/sdk"
+	"github.com/gnolang/gno/tm2/pkg/sdk/params"
+	"github.com/gnolang/gno/tm2/pkg/std"
+	"github.com/gnolang/gno/tm2/pkg/store"
+)
+
+// ValidatorKeeper manages the validator set of a proof-of-authority chain:
+// the validators are added, removed and given voting power by the admin set
+// in the params, and the changes are applied to the consensus at the end of
+// each block.
+type ValidatorKeeper struct {
+	// The (unexposed) key used to access the store from the Context.
+	key store.StoreKey
+
+	// The keeper used to store the params.
+	paramk params.ParamsKeeper
+}
+
+// NewValidatorKeeper returns a new ValidatorKeeper.
+func NewValidatorKeeper(key store.StoreKey, pk params.ParamsKeeper) ValidatorKeeper {
+	return ValidatorKeeper{
+		key:    key,
+		paramk: pk,
+	}
+}
+
+// Logger returns a module-specific logger.
+func (vk ValidatorKeeper) Logger(ctx sdk.Context) *slog.Logger {
+	return ctx.Logger().With("module", ModuleName)
+}
+
+// GetValidator returns the validator with the given address, if it is in the
+// validator set.
+func (vk ValidatorKeeper) GetValidator(ctx sdk.Context, addr crypto.Address) (val Validator, ok bool) {
+	stor := ctx.Store(vk.key)
+	bz := stor.Get(ValidatorStoreKey(addr))
+	if bz == nil {
+		return val, false
+	}
+	amino.MustUnmarshal(bz, &val)
+	return val, true
+}
+
+// GetValidators returns the validator set, sorted by address.
+func (vk ValidatorKeeper) GetValidators(ctx sdk.Context) []Validator {
+	stor := ctx.Store(vk.key)
+	iter := store.PrefixIterator(stor, []byte(ValidatorStoreKeyPrefix))
+	defer iter.Close()
+
+	var vals []Validator
+	for ; iter.Valid(); iter.Next() {
+		var val Validator
+		amino.MustUnmarshal(iter.Value(), &val)
+		vals = append(vals, val)
+	}
+	return vals
+}
+
+// AddValidator adds val to the validator set.
+func (vk ValidatorKeeper) AddValidator(ctx sdk.Context, val Validator) error {
+	if err := val.ValidateBasic(); err != nil {
+		return err
+	}
+	if _, ok := vk.GetValidator(ctx, val.Address); ok {
+		return ErrValidatorExists(val.Address)
+	}
+	if err := vk.checkTotalPower(ctx, val.Power); err != nil {
+		return err
+	}
+
+	vk.markUpdated(ctx, val.Address, nil)
+	vk.setValidator(ctx, val)
+	return nil
+}
+
+// RemoveValidator removes the validator with the given address from the
+// validator set. The last validator cannot be removed.
+func (vk ValidatorKeeper) RemoveValidator(ctx sdk.Context, addr crypto.Address) error {
+	val, ok := vk.GetValidator(ctx, addr)
+	if !ok {
+		return ErrValidatorNotFound(addr)
+	}
+	if len(vk.GetValidators(ctx)) == 1 {
+		return ErrLastValidator()
+	}
+
+	vk.markUpdated(ctx, addr, &val)
+	stor := ctx.Store(vk.key)
+	stor.Delete(ValidatorStoreKey(addr))
+	return nil
+}
+
+// SetValidatorPower changes the voting power of the validator with the given
+// address.
+func (vk ValidatorKeeper) SetValidatorPower(ctx sdk.Context, addr crypto.Address, power int64) error {
+	if err := validatePower(power); err != nil {
+		return err
+	}
+	val, ok := vk.GetValidator(ctx, addr)
+	if !ok {
+		return ErrValidatorNotFound(addr)
+	}
+	if err := vk.checkTotalPower(ctx, power-val.Power); err != nil {
+		return err
+	}
+
+	vk.markUpdated(ctx, addr, &val)
+	val.Power = power
+	vk.setValidator(ctx, val)
+	return nil
+}
+
+// PopValidatorUpdates returns the updates of the validator set of the
+// consensus, for the validators changed since the last call, and clears them.
+// A removed validator has no voting power.
+func (vk ValidatorKeeper) PopValidatorUpdates(ctx sdk.Context) []abci.ValidatorUpdate {
+	stor := ctx.Store(vk.key)
+	iter := store.PrefixIterator(stor, []byte(PendingUpdateStoreKeyPrefix))
+
+	var (
+		keys    [][]byte
+		updates []abci.ValidatorUpdate
+	)
+	for ; iter.Valid(); iter.Next() {
+		keys = append(keys, iter.Key())
+		addr := crypto.AddressFromBytes(iter.Key()[len(PendingUpdateStoreKeyPrefix):])
+
+		if val, ok := vk.GetValidator(ctx, addr); ok {
+			updates = append(updates, val.ABCIValidatorUpdate())
+		} else if bz := iter.Value(); bz[0] == 1 {
+			var prev Validator
+			amino.MustUnmarshal(bz[1:], &prev)
+			prev.Power = 0
+			updates = append(updates, prev.ABCIValidatorUpdate())
+		}
+		// else, added then removed in the same block: unknown to the
+		// consensus.
+	}
+	iter.Close()
+
+	for _, key := range keys {
+		stor.Delete(key)
+	}
+	return updates
+}
+
+func (vk ValidatorKeeper) setValidator(ctx sdk.Context, val Validator) {
+	stor := ctx.Store(vk.key)
+	stor.Set(ValidatorStoreKey(val.Address), amino.MustMarshal(val))
+}
+
+// markUpdated records that the validator at addr changed in the current
+// block, with its value before its first change, prev, which is nil if it
+// was not in the validator set.
+func (vk ValidatorKeeper) markUpdated(ctx sdk.Context, addr crypto.Address, prev *Validator) {
+	stor := ctx.Store(vk.key)
+	key := PendingUpdateStoreKey(addr)
+	if stor.Has(key) {
+		return
+	}
+	// NOTE: prefixed with a byte, as empty values cannot be stored.
+	bz := []byte{0}
+	if prev != nil {
+		bz = append([]byte{1}, amino.MustMarshal(*prev)...)
+	}
+	stor.Set(key, bz)
+}
+
+// checkTotalPower returns an error if changing the total voting power of the
+// validator set by delta overflows the maximum allowed by the consensus.
+func (vk ValidatorKeeper) checkTotalPower(ctx sdk.Context, delta int64) error {
+	total := delta
+	for _, val := range vk.GetValidators(ctx) {
+		total += val.Power
+	}
+	if total > bft.MaxTotalVotingPower {
+		return std.ErrUnknownRequest(fmt.Sprintf(
+			"total voting power %d exceeds the maximum %d", total, bft.MaxTotalVotingPower))
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package validators
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	bft "github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/errors"
+)
+
+func TestValidatorKeeper(t *testing.T) {
+	t.Parallel()
+
+	env := setupTestEnv()
+	ctx, vk := env.ctx, env.vk
+
+	val1 := newTestValidator(10)
+	val2 := newTestValidator(20)
+
+	_, ok := vk.GetValidator(ctx, val1.Address)
+	assert.False(t, ok)
+	assert.Empty(t, vk.GetValidators(ctx))
+
+	require.NoError(t, vk.AddValidator(ctx, val1))
+	require.NoError(t, vk.AddValidator(ctx, val2))
+	err := vk.AddValidator(ctx, val1)
+	assert.IsType(t, ValidatorExistsError{}, errors.Cause(err))
+
+	got, ok := vk.GetValidator(ctx, val1.Address)
+	require.True(t, ok)
+	assert.Equal(t, val1.Address, got.Address)
+	assert.True(t, val1.PubKey.Equals(got.PubKey))
+	assert.Len(t, vk.GetValidators(ctx), 2)
+
+	require.NoError(t, vk.SetValidatorPower(ctx, val1.Address, 15))
+	got, _ = vk.GetValidator(ctx, val1.Address)
+	assert.Equal(t, int64(15), got.Power)
+	assert.Error(t, vk.SetValidatorPower(ctx, val1.Address, 0))
+	assert.Error(t, vk.SetValidatorPower(ctx, val1.Address, bft.MaxTotalVotingPower))
+
+	require.NoError(t, vk.RemoveValidator(ctx, val1.Address))
+	err = vk.RemoveValidator(ctx, val1.Address)
+	assert.IsType(t, ValidatorNotFoundError{}, errors.Cause(err))
+	err = vk.RemoveValidator(ctx, val2.Address)
+	assert.IsType(t, LastValidatorError{}, errors.Cause(err))
+	assert.Len(t, vk.GetValidators(ctx), 1)
+}
+
+func TestValidatorKeeperPopValidatorUpdates(t *testing.T) {
+	t.Parallel()
+
+	env := setupTestEnv()
+	ctx, vk := env.ctx, env.vk
+
+	val1 := newTestValidator(10)
+	val2 := newTestValidator(20)
+	val3 := newTestValidator(30)
+	vk.InitGenesis(ctx, NewGenesisState(NewParams(env.admin), []Validator{val1, val2}))
+	assert.Empty(t, EndBlocker(ctx, vk))
+
+	// changes made in a block.
+	require.NoError(t, vk.SetValidatorPower(ctx, val1.Address, 11))
+	require.NoError(t, vk.SetValidatorPower(ctx, val1.Address, 12))
+	require.NoError(t, vk.RemoveValidator(ctx, val2.Address))
+	// added then removed in the same block.
+	require.NoError(t, vk.AddValidator(ctx, val3))
+	require.NoError(t, vk.RemoveValidator(ctx, val3.Address))
+
+	updates := EndBlocker(ctx, vk)
+	expected := []abci.ValidatorUpdate{
+		{Address: val1.Address, PubKey: val1.PubKey, Power: 12},
+		{Address: val2.Address, PubKey: val2.PubKey, Power: 0},
+	}
+	assert.ElementsMatch(t, expected, updates)
+	assert.Empty(t, EndBlocker(ctx, vk))
+
+	// removed then added back in the same block.
+	require.NoError(t, vk.AddValidator(ctx, val2))
+	require.NoError(t, vk.RemoveValidator(ctx, val2.Address))
+	require.NoError(t, vk.AddValidator(ctx, val2))
+	assert.Equal(t, []abci.ValidatorUpdate{val2.ABCIValidatorUpdate()}, EndBlocker(ctx, vk))
+}
+
+func TestValidatorKeeperGenesis(t *testing.T) {
+	t.Parallel()
+
+	env := setupTestEnv()
+	ctx, vk := env.ctx, env.vk
+
+	val1 := newTestValidator(10)
+	val2 := newTestValidator(20)
+	genesis := NewGenesisState(NewParams(env.admin), []Validator{val1, val2})
+
+	updates := vk.InitGenesis(ctx, genesis)
+	assert.ElementsMatch(t, []abci.ValidatorUpdate{val1.ABCIValidatorUpdate(), val2.ABCIValidatorUpdate()}, updates)
+
+	exported := vk.ExportGenesis(ctx)
+	assert.True(t, genesis.Params.Equals(exported.Params))
+	assert.ElementsMatch(t, genesis.Validators, exported.Validators)
+
+	// invalid genesis.
+	assert.Panics(t, func() {
+		vk.InitGenesis(ctx, NewGenesisState(DefaultParams(), []Validator{val1, val1}))
+	})
+	assert.Panics(t, func() {
+		vk.InitGenesis(ctx, NewGenesisState(DefaultParams(), []Validator{{Address: val1.Address, PubKey: val2.PubKey, Power: 1}}))
+	})
+}
@@ -0,0 +1,173 @@
+package validators
+
+import (
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/gnolang/gno/tm2/pkg/std"
+)
+
+// RouterKey is the name of the validators module
+const RouterKey = ModuleName
+
+// MsgAddValidator - adds a validator to the validator set.
+type MsgAddValidator struct {
+	Admin  crypto.Address `json:"admin" yaml:"admin"`
+	PubKey crypto.PubKey  `json:"pub_key" yaml:"pub_key"`
+	Power  int64          `json:"power" yaml:"power"`
+}
+
+var _ std.Msg = MsgAddValidator{}
+
+// NewMsgAddValidator - construct a msg adding a validator.
+func NewMsgAddValidator(admin crypto.Address, pubKey crypto.PubKey, power int64) MsgAddValidator {
+	return MsgAddValidator{Admin: admin, PubKey: pubKey, Power: power}
+}
+
+// Route Implements Msg.
+func (msg MsgAddValidator) Route() string { return RouterKey }
+
+// Type Implements Msg.
+func (msg MsgAddValidator) Type() string { return "add_validator" }
+
+// ValidateBasic Implements Msg.
+func (msg MsgAddValidator) ValidateBasic() error {
+	if msg.Admin.IsZero() {
+		return std.ErrInvalidAddress("missing admin address")
+	}
+	if msg.PubKey == nil {
+		return std.ErrInvalidPubKey("missing validator public key")
+	}
+	return validatePower(msg.Power)
+}
+
+// GetSignBytes Implements Msg.
+func (msg MsgAddValidator) GetSignBytes() []byte {
+	return std.MustSortJSON(amino.MustMarshalJSON(msg))
+}
+
+// GetSigners Implements Msg.
+func (msg MsgAddValidator) GetSigners() []crypto.Address {
+	return []crypto.Address{msg.Admin}
+}
+
+// MsgRemoveValidator - removes a validator from the validator set.
+type MsgRemoveValidator struct {
+	Admin   crypto.Address `json:"admin" yaml:"admin"`
+	Address crypto.Address `json:"address" yaml:"address"`
+}
+
+var _ std.Msg = MsgRemoveValidator{}
+
+// NewMsgRemoveValidator - construct a msg removing a validator.
+func NewMsgRemoveValidator(admin, addr crypto.Address) MsgRemoveValidator {
+	return MsgRemoveValidator{Admin: admin, Address: addr}
+}
+
+// Route Implements Msg.
+func (msg MsgRemoveValidator) Route() string { return RouterKey }
+
+// Type Implements Msg.
+func (msg MsgRemoveValidator) Type() string { return "remove_validator" }
+
+// ValidateBasic Implements Msg.
+func (msg MsgRemoveValidator) ValidateBasic() error {
+	if msg.Admin.IsZero() {
+		return std.ErrInvalidAddress("missing admin address")
+	}
+	if msg.Address.IsZero() {
+		return std.ErrInvalidAddress("missing validator address")
+	}
+	return nil
+}
+
+// GetSignBytes Implements Msg.
+func (msg MsgRemoveValidator) GetSignBytes() []byte {
+	return std.MustSortJSON(amino.MustMarshalJSON(msg))
+}
+
+// GetSigners Implements Msg.
+func (msg MsgRemoveValidator) GetSigners() []crypto.Address {
+	return []crypto.Address{msg.Admin}
+}
+
+// MsgSetValidatorPower - changes the voting power of a validator.
+type MsgSetValidatorPower struct {
+	Admin   crypto.Address `json:"admin" yaml:"admin"`
+	Address crypto.Address `json:"address" yaml:"address"`
+	Power   int64          `json:"power" yaml:"power"`
+}
+
+var _ std.Msg = MsgSetValidatorPower{}
+
+// NewMsgSetValidatorPower - construct a msg changing the power of a
+// validator.
+func NewMsgSetValidatorPower(admin, addr crypto.Address, power int64) MsgSetValidatorPower {
+	return MsgSetValidatorPower{Admin: admin, Address: addr, Power: power}
+}
+
+// Route Implements Msg.
+func (msg MsgSetValidatorPower) Route() string { return RouterKey }
+
+// Type Implements Msg.
+func (msg MsgSetValidatorPower) Type() string { return "set_validator_power" }
+
+// ValidateBasic Implements Msg.
+func (msg MsgSetValidatorPower) ValidateBasic() error {
+	if msg.Admin.IsZero() {
+		return std.ErrInvalidAddress("missing admin address")
+	}
+	if msg.Address.IsZero() {
+		return std.ErrInvalidAddress("missing validator address")
+	}
+	return validatePower(msg.Power)
+}
+
+// GetSignBytes Implements Msg.
+func (msg MsgSetValidatorPower) GetSignBytes() []byte {
+	return std.MustSortJSON(amino.MustMarshalJSON(msg))
+}
+
+// GetSigners Implements Msg.
+func (msg MsgSetValidatorPower) GetSigners() []crypto.Address {
+	return []crypto.Address{msg.Admin}
+}
+
+// MsgSetAdmin - hands the management of the validator set to a new admin.
+type MsgSetAdmin struct {
+	Admin    crypto.Address `json:"admin" yaml:"admin"`
+	NewAdmin crypto.Address `json:"new_admin" yaml:"new_admin"`
+}
+
+var _ std.Msg = MsgSetAdmin{}
+
+// NewMsgSetAdmin - construct a msg changing the admin.
+func NewMsgSetAdmin(admin, newAdmin crypto.Address) MsgSetAdmin {
+	return MsgSetAdmin{Admin: admin, NewAdmin: newAdmin}
+}
+
+// Route Implements Msg.
+func (msg MsgSetAdmin) Route() string { return RouterKey }
+
+// Type Implements Msg.
+func (msg MsgSetAdmin) Type() string { return "set_admin" }
+
+// ValidateBasic Implements Msg.
+func (msg MsgSetAdmin) ValidateBasic() error {
+	if msg.Admin.IsZero() {
+		return std.ErrInvalidAddress("missing admin address")
+	}
+	if msg.NewAdmin.IsZero() {
+		return std.ErrInvalidAddress("missing new admin address")
+	}
+	return nil
+}
+
+// GetSignBytes Implements Msg.
+func (msg MsgSetAdmin) GetSignBytes() []byte {
+	return std.MustSortJSON(amino.MustMarshalJSON(msg))
+}
+
+// GetSigners Implements Msg.
+func (msg MsgSetAdmin) GetSigners() []crypto.Address {
+	return []crypto.Address{msg.Admin}
+}
@@ -0,0 +1,20 @@
+package validators
+
+import (
+	"github.com/gnolang/gno/tm2/pkg/amino"
+)
+
+var Package = amino.RegisterPackage(amino.NewPackage(
+	"github.com/gnolang/gno/tm2/pkg/sdk/validators",
+	"validators",
+	amino.GetCallersDirname(),
+).WithDependencies().WithTypes(
+	ValidatorExistsError{}, "ValidatorExistsError",
+	ValidatorNotFoundError{}, "ValidatorNotFoundError",
+	LastValidatorError{}, "LastValidatorError",
+	Validator{}, "Validator",
+	MsgAddValidator{}, "MsgAddValidator",
+	MsgRemoveValidator{}, "MsgRemoveValidator",
+	MsgSetValidatorPower{}, "MsgSetValidatorPower",
+	MsgSetAdmin{}, "MsgSetAdmin",
+))
@@ -0,0 +1,61 @@
+package validators
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/gnolang/gno/tm2/pkg/sdk"
+)
+
+// Params defines the parameters for the validators module.
+type Params struct {
+	// Admin is the only account allowed to change the validator set. It can
+	// be the address of a multisig account. If zero, the validator set can
+	// no longer be changed.
+	Admin crypto.Address `json:"admin" yaml:"admin"`
+}
+
+// NewParams creates a new Params object
+func NewParams(admin crypto.Address) Params {
+	return Params{
+		Admin: admin,
+	}
+}
+
+// Equals returns a boolean determining if two Params types are identical.
+func (p Params) Equals(p2 Params) bool {
+	return amino.DeepEqual(p, p2)
+}
+
+// DefaultParams returns a default set of parameters.
+func DefaultParams() Params {
+	return Params{}
+}
+
+// String implements the stringer interface.
+func (p Params) String() string {
+	var sb strings.Builder
+	sb.WriteString("Params: \n")
+	sb.WriteString(fmt.Sprintf("Admin: %s\n", p.Admin))
+	return sb.String()
+}
+
+func (vk ValidatorKeeper) SetParams(ctx sdk.Context, params Params) error {
+	return vk.paramk.SetParams(ctx, ModuleName, params)
+}
+
+func (vk ValidatorKeeper) GetParams(ctx sdk.Context) Params {
+	params := &Params{}
+
+	ok, err := vk.paramk.GetParams(ctx, ModuleName, params)
+
+	if !ok {
+		panic("params key " + ModuleName + " does not exist")
+	}
+	if err != nil {
+		panic(err.Error())
+	}
+	return *params
+}
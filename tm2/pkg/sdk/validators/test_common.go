@@ -0,0 +1,46 @@
+package validators
+
+import (
+	bft "github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/gnolang/gno/tm2/pkg/crypto/ed25519"
+	"github.com/gnolang/gno/tm2/pkg/db/memdb"
+	"github.com/gnolang/gno/tm2/pkg/log"
+	"github.com/gnolang/gno/tm2/pkg/sdk"
+	"github.com/gnolang/gno/tm2/pkg/sdk/params"
+	"github.com/gnolang/gno/tm2/pkg/store"
+	"github.com/gnolang/gno/tm2/pkg/store/iavl"
+)
+
+type testEnv struct {
+	ctx   sdk.Context
+	vk    ValidatorKeeper
+	admin crypto.Address
+}
+
+func setupTestEnv() testEnv {
+	db := memdb.NewMemDB()
+
+	valCapKey := store.NewStoreKey("valCapKey")
+
+	ms := store.NewCommitMultiStore(db)
+	ms.MountStoreWithDB(valCapKey, iavl.StoreConstructor, db)
+	ms.LoadLatestVersion()
+
+	paramk := params.NewParamsKeeper(valCapKey, "")
+	vk := NewValidatorKeeper(valCapKey, paramk)
+
+	ctx := sdk.NewContext(sdk.RunTxModeDeliver, ms, &bft.Header{Height: 1, ChainID: "test-chain-id"}, log.NewNoopLogger())
+
+	admin := crypto.AddressFromPreimage([]byte("admin"))
+	if err := vk.SetParams(ctx, NewParams(admin)); err != nil {
+		panic(err)
+	}
+
+	return testEnv{ctx: ctx, vk: vk, admin: admin}
+}
+
+// newTestValidator returns a validator with a new key and the given power.
+func newTestValidator(power int64) Validator {
+	return NewValidator(ed25519.GenPrivKey().PubKey(), power)
+}
@@ -0,0 +1,97 @@
+package validators
+
+import (
+	"fmt"
+
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/gnolang/gno/tm2/pkg/sdk"
+	"github.com/gnolang/gno/tm2/pkg/std"
+)
+
+// ValidatorKeeperI is the interface of the validators keeper.
+type ValidatorKeeperI interface {
+	GetParams(ctx sdk.Context) Params
+	SetParams(ctx sdk.Context, params Params) error
+
+	GetValidator(ctx sdk.Context, addr crypto.Address) (Validator, bool)
+	GetValidators(ctx sdk.Context) []Validator
+	AddValidator(ctx sdk.Context, val Validator) error
+	RemoveValidator(ctx sdk.Context, addr crypto.Address) error
+	SetValidatorPower(ctx sdk.Context, addr crypto.Address, power int64) error
+	PopValidatorUpdates(ctx sdk.Context) []abci.ValidatorUpdate
+
+	InitGenesis(ctx sdk.Context, data GenesisState) []abci.ValidatorUpdate
+	ExportGenesis(ctx sdk.Context) GenesisState
+}
+
+var _ ValidatorKeeperI = ValidatorKeeper{}
+
+// Validator is a validator of the chain, with its voting power.
+type Validator struct {
+	Address crypto.Address `json:"address" yaml:"address"`
+	PubKey  crypto.PubKey  `json:"pub_key" yaml:"pub_key"`
+	Power   int64          `json:"power" yaml:"power"`
+}
+
+// NewValidator returns a validator with the address of pubKey.
+func NewValidator(pubKey crypto.PubKey, power int64) Validator {
+	return Validator{Address: pubKey.Address(), PubKey: pubKey, Power: power}
+}
+
+// ValidateBasic does a simple validation check of the validator.
+func (v Validator) ValidateBasic() error {
+	if v.PubKey == nil {
+		return std.ErrInvalidPubKey("missing validator public key")
+	}
+	if v.Address != v.PubKey.Address() {
+		return std.ErrInvalidAddress(fmt.Sprintf(
+			"validator address %s does not match public key", v.Address))
+	}
+	return validatePower(v.Power)
+}
+
+// ABCIValidatorUpdate returns the update setting the validator in the
+// validator set of the consensus.
+func (v Validator) ABCIValidatorUpdate() abci.ValidatorUpdate {
+	return abci.ValidatorUpdate{Address: v.Address, PubKey: v.PubKey, Power: v.Power}
+}
+
+func validatePower(power int64) error {
+	if power <= 0 {
+		return std.ErrUnknownRequest("validator power must be positive")
+	}
+	return nil
+}
+
+// GenesisState - all validators state that must be provided at genesis
+type GenesisState struct {
+	Params     Params      `json:"params" yaml:"params"`
+	Validators []Validator `json:"validators" yaml:"validators"`
+}
+
+// NewGenesisState - Create a new genesis state
+func NewGenesisState(params Params, validators []Validator) GenesisState {
+	return GenesisState{Params: params, Validators: validators}
+}
+
+// DefaultGenesisState - Return a default genesis state
+func DefaultGenesisState() GenesisState {
+	return NewGenesisState(DefaultParams(), nil)
+}
+
+// ValidateGenesis performs basic validation of validators genesis data
+// returning an error for any failed validation criteria.
+func ValidateGenesis(data GenesisState) error {
+	seen := make(map[crypto.Address]struct{}, len(data.Validators))
+	for _, val := range data.Validators {
+		if err := val.ValidateBasic(); err != nil {
+			return err
+		}
+		if _, ok := seen[val.Address]; ok {
+			return fmt.Errorf("duplicate validator %s", val.Address)
+		}
+		seen[val.Address] = struct{}{}
+	}
+	return nil
+}
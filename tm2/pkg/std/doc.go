@@ -19,6 +19,7 @@ type SignDoc struct {
 	Fee           Fee    `json:"fee" yaml:"fee"`
 	Msgs          []Msg  `json:"msgs" yaml:"msgs"`
 	Memo          string `json:"memo" yaml:"memo"`
+	TimeoutHeight int64  `json:"timeout_height,omitempty" yaml:"timeout_height,omitempty"`
 }
 
 // GetSignaturePayload returns the sign payload for the SignDoc.
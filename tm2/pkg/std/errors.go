@@ -31,6 +31,7 @@ type (
 	TooManySignaturesError struct{ abciError }
 	NoSignaturesError      struct{ abciError }
 	GasOverflowError       struct{ abciError }
+	TxExpiredError         struct{ abciError }
 )
 
 func (e InternalError) Error() string          { return "internal error" }
@@ -51,6 +52,7 @@ func (e InsufficientFeeError) Error() string   { return "insufficient fee error"
 func (e TooManySignaturesError) Error() string { return "too many signatures error" }
 func (e NoSignaturesError) Error() string      { return "no signatures error" }
 func (e GasOverflowError) Error() string       { return "gas overflow error" }
+func (e TxExpiredError) Error() string         { return "tx expired error" }
 
 // NOTE also update pkg/std/package.go registrations.
 
@@ -125,3 +127,7 @@ func ErrNoSignatures(msg string) error {
 func ErrGasOverflow(msg string) error {
 	return errors.Wrap(GasOverflowError{}, msg)
 }
+
+func ErrTxExpired(msg string) error {
+	return errors.Wrap(TxExpiredError{}, msg)
+}
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/gnolang/gno/tm2/pkg/amino"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
 	"github.com/gnolang/gno/tm2/pkg/std"
 	"github.com/stretchr/testify/require"
 )
@@ -77,3 +78,28 @@ func TestAminoCoin(t *testing.T) {
 	err = amino.UnmarshalJSON(bz, &coin)
 	require.NoError(t, err)
 }
+
+func TestAminoFee(t *testing.T) {
+	fee := std.NewFee(100, std.Coin{Denom: "token", Amount: 10})
+
+	// A fee without granter is encoded without it.
+	bz, err := amino.Marshal(fee)
+	require.NoError(t, err)
+	var fee2 std.Fee
+	require.NoError(t, amino.Unmarshal(bz, &fee2))
+	require.Equal(t, fee, fee2)
+	bz, err = amino.MarshalJSON(fee)
+	require.NoError(t, err)
+	require.Equal(t, `{"gas_wanted":"100","gas_fee":"10token"}`, string(bz))
+
+	fee = fee.WithGranter(crypto.AddressFromPreimage([]byte("granter")))
+	bz2, err := amino.Marshal(fee)
+	require.NoError(t, err)
+	require.Greater(t, len(bz2), len(bz))
+	require.NoError(t, amino.Unmarshal(bz2, &fee2))
+	require.Equal(t, fee, fee2)
+	bz, err = amino.MarshalJSON(fee)
+	require.NoError(t, err)
+	require.NoError(t, amino.UnmarshalJSON(bz, &fee2))
+	require.Equal(t, fee, fee2)
+}
@@ -19,12 +19,17 @@ var (
 )
 
 // Tx is a standard way to wrap a Msg with Fee and Signatures.
-// NOTE: the first signature is the fee payer (Signatures must not be nil).
+// NOTE: the first signature is the fee payer, unless the fee has a granter
+// (Signatures must not be nil).
 type Tx struct {
 	Msgs       []Msg       `json:"msg" yaml:"msg"`
 	Fee        Fee         `json:"fee" yaml:"fee"`
 	Signatures []Signature `json:"signatures" yaml:"signatures"`
 	Memo       string      `json:"memo" yaml:"memo"`
+
+	// TimeoutHeight is the last block height at which the transaction can be
+	// included. If zero, the transaction does not expire.
+	TimeoutHeight int64 `json:"timeout_height,omitempty" yaml:"timeout_height,omitempty"`
 }
 
 func NewTx(msgs []Msg, fee Fee, sigs []Signature, memo string) Tx {
@@ -50,6 +55,9 @@ func (tx Tx) ValidateBasic() error {
 	if !tx.Fee.GasFee.IsValid() {
 		return ErrInsufficientFee(fmt.Sprintf("invalid fee %s amount provided", tx.Fee.GasFee))
 	}
+	if tx.TimeoutHeight < 0 {
+		return ErrTxExpired(fmt.Sprintf("invalid timeout height %d", tx.TimeoutHeight))
+	}
 	if len(stdSigs) == 0 {
 		return ErrNoSignatures("no signers")
 	}
@@ -115,17 +123,28 @@ func (tx Tx) GetSignBytes(chainID string, accountNumber uint64, sequence uint64)
 		Fee:           tx.Fee,
 		Msgs:          tx.Msgs,
 		Memo:          tx.Memo,
+		TimeoutHeight: tx.TimeoutHeight,
 	})
 }
 
+// IsExpired returns whether the transaction can no longer be included in a
+// block at the given height.
+func (tx Tx) IsExpired(height int64) bool {
+	return tx.TimeoutHeight != 0 && height > tx.TimeoutHeight
+}
+
 // __________________________________________________________
 
 // Fee includes the amount of coins paid in fees and the maximum
 // gas to be used by the transaction. The ratio yields an effective "gasprice",
 // which must be above some miminum to be accepted into the mempool.
+//
+// If Granter is set, the fees are paid by the granter instead of the first
+// signer, within the fee allowance granted by the granter to the first signer.
 type Fee struct {
-	GasWanted int64 `json:"gas_wanted" yaml:"gas_wanted"`
-	GasFee    Coin  `json:"gas_fee" yaml:"gas_fee"`
+	GasWanted int64          `json:"gas_wanted" yaml:"gas_wanted"`
+	GasFee    Coin           `json:"gas_fee" yaml:"gas_fee"`
+	Granter   crypto.Address `json:"granter,omitempty" yaml:"granter,omitempty"`
 }
 
 // NewFee returns a new instance of Fee
@@ -136,6 +155,35 @@ func NewFee(gasWanted int64, gasFee Coin) Fee {
 	}
 }
 
+// WithGranter returns a copy of the fee, paid by granter.
+func (fee Fee) WithGranter(granter crypto.Address) Fee {
+	fee.Granter = granter
+	return fee
+}
+
+// feeRepr is the amino representation of Fee, where the granter is empty if
+// zero, so that fees without granter are encoded without it.
+type feeRepr struct {
+	GasWanted int64  `json:"gas_wanted" yaml:"gas_wanted"`
+	GasFee    Coin   `json:"gas_fee" yaml:"gas_fee"`
+	Granter   string `json:"granter,omitempty" yaml:"granter,omitempty"`
+}
+
+func (fee Fee) MarshalAmino() (feeRepr, error) {
+	repr := feeRepr{GasWanted: fee.GasWanted, GasFee: fee.GasFee}
+	if !fee.Granter.IsZero() {
+		repr.Granter = fee.Granter.String()
+	}
+	return repr, nil
+}
+
+func (fee *Fee) UnmarshalAmino(repr feeRepr) error {
+	fee.GasWanted = repr.GasWanted
+	fee.GasFee = repr.GasFee
+	fee.Granter = crypto.Address{}
+	return fee.Granter.UnmarshalAmino(repr.Granter)
+}
+
 // Bytes for signing later
 func (fee Fee) Bytes() []byte {
 	bz, err := amino.MarshalJSON(fee) // TODO
@@ -24,6 +24,9 @@ type (
 	GasConfig              = types.GasConfig
 	OutOfGasError          = types.OutOfGasError
 	GasOverflowError       = types.GasOverflowError
+	SnapshotItem           = types.SnapshotItem
+	Snapshotter            = types.Snapshotter
+	SnapshotMultiStore     = types.SnapshotMultiStore
 )
 
 var (
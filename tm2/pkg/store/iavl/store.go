@@ -3,6 +3,7 @@ package iavl
 import (
 	goerrors "errors"
 	"fmt"
+	"io"
 	"sync"
 
 	"github.com/gnolang/gno/tm2/pkg/amino"
@@ -35,12 +36,17 @@ var (
 	_ types.Store       = (*Store)(nil)
 	_ types.CommitStore = (*Store)(nil)
 	_ types.Queryable   = (*Store)(nil)
+	_ types.Snapshotter = (*Store)(nil)
 )
 
 // Store Implements types.Store and CommitStore.
 type Store struct {
 	tree Tree
 	opts types.StoreOptions
+
+	retainMtx sync.Mutex     // guards the retained versions
+	retained  map[int64]int  // versions retained from pruning, by retain count
+	pruned    map[int64]bool // retained versions to delete once released
 }
 
 func UnsafeNewStore(tree *iavl.MutableTree, opts types.StoreOptions) *Store {
@@ -84,15 +90,23 @@ func (st *Store) Commit() types.CommitID {
 		panic(err)
 	}
 
+	st.retainMtx.Lock()
+	defer st.retainMtx.Unlock()
+
 	// Release an old version of history, if not a sync waypoint.
 	previous := version - 1
 	if st.opts.KeepRecent < previous {
 		toRelease := previous - st.opts.KeepRecent
 		if st.opts.KeepEvery == 0 || toRelease%st.opts.KeepEvery != 0 {
-			err := st.tree.DeleteVersion(toRelease)
-			if errCause := errors.Cause(err); errCause != nil && !goerrors.Is(errCause, iavl.ErrVersionDoesNotExist) {
-				panic(err)
-			}
+			st.pruneVersion(toRelease)
+		}
+	}
+
+	// Release the pruned versions that are no longer retained.
+	for toRelease := range st.pruned {
+		if st.retained[toRelease] == 0 {
+			delete(st.pruned, toRelease)
+			st.pruneVersion(toRelease)
 		}
 	}
 
@@ -102,6 +116,24 @@ func (st *Store) Commit() types.CommitID {
 	}
 }
 
+// pruneVersion deletes the given version, unless it's retained, in which
+// case it's deleted on the first commit after it's released.
+// CONTRACT: the caller holds retainMtx.
+func (st *Store) pruneVersion(version int64) {
+	if st.retained[version] > 0 {
+		if st.pruned == nil {
+			st.pruned = make(map[int64]bool)
+		}
+		st.pruned[version] = true
+		return
+	}
+
+	err := st.tree.DeleteVersion(version)
+	if errCause := errors.Cause(err); errCause != nil && !goerrors.Is(errCause, iavl.ErrVersionDoesNotExist) {
+		panic(err)
+	}
+}
+
 // Implements Committer.
 func (st *Store) LastCommitID() types.CommitID {
 	return types.CommitID{
@@ -212,6 +244,88 @@ func (st *Store) ReverseIterator(start, end []byte) types.Iterator {
 	return newIAVLIterator(iTree, start, end, false)
 }
 
+// Implements types.Snapshotter.
+func (st *Store) RetainVersion(version int64) (func(), error) {
+	st.retainMtx.Lock()
+	defer st.retainMtx.Unlock()
+
+	if !st.VersionExists(version) {
+		return nil, iavl.ErrVersionDoesNotExist
+	}
+
+	if st.retained == nil {
+		st.retained = make(map[int64]int)
+	}
+	st.retained[version]++
+
+	var once sync.Once
+
+	return func() {
+		once.Do(func() {
+			st.retainMtx.Lock()
+			defer st.retainMtx.Unlock()
+
+			st.retained[version]--
+			if st.retained[version] == 0 {
+				delete(st.retained, version)
+			}
+		})
+	}, nil
+}
+
+// Implements types.Snapshotter.
+func (st *Store) Export(version int64, fn func(types.SnapshotItem) error) error {
+	tree, err := st.tree.GetImmutable(version)
+	if err != nil {
+		return fmt.Errorf("unable to load version %d, %w", version, err)
+	}
+
+	return tree.Export(func(node *iavl.ExportNode) error {
+		return fn(types.SnapshotItem{
+			Key:     node.Key,
+			Value:   node.Value,
+			Version: node.Version,
+			Height:  node.Height,
+		})
+	})
+}
+
+// Implements types.Snapshotter.
+func (st *Store) Restore(version int64, next func() (types.SnapshotItem, error)) error {
+	tree, ok := st.tree.(*iavl.MutableTree)
+	if !ok {
+		return goerrors.New("unable to restore an immutable IAVL store")
+	}
+
+	importer, err := tree.Import(version)
+	if err != nil {
+		return err
+	}
+
+	for {
+		item, err := next()
+		if goerrors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return err
+		}
+
+		err = importer.Add(&iavl.ExportNode{
+			Key:     item.Key,
+			Value:   item.Value,
+			Version: item.Version,
+			Height:  item.Height,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return importer.Commit()
+}
+
 // Handle gatest the latest height, if height is 0
 func getHeight(tree Tree, req abci.RequestQuery) int64 {
 	height := req.Height
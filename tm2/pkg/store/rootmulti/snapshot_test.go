@@ -0,0 +1,192 @@
+package rootmulti
+
+import (
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	dbm "github.com/gnolang/gno/tm2/pkg/db"
+	"github.com/gnolang/gno/tm2/pkg/db/memdb"
+
+	"github.com/gnolang/gno/tm2/pkg/store/dbadapter"
+	"github.com/gnolang/gno/tm2/pkg/store/iavl"
+	"github.com/gnolang/gno/tm2/pkg/store/types"
+)
+
+type exportedItem struct {
+	store string
+	item  types.SnapshotItem
+}
+
+// baseKey is the key of the plain store, excluded from the snapshots
+var baseKey = types.NewStoreKey("base")
+
+// newSnapshotMultiStore creates a multistore with an IAVL and a
+// plain store, sharing the same database like in gno.land
+func newSnapshotMultiStore(t *testing.T, db dbm.DB) *multiStore {
+	t.Helper()
+
+	ms := NewMultiStore(db)
+	ms.MountStoreWithDB(types.NewStoreKey("main"), iavl.StoreConstructor, db)
+	ms.MountStoreWithDB(baseKey, dbadapter.StoreConstructor, db)
+
+	require.NoError(t, ms.LoadLatestVersion())
+
+	return ms
+}
+
+// exportItems exports the multistore at the given version
+func exportItems(t *testing.T, ms *multiStore, version int64) []exportedItem {
+	t.Helper()
+
+	var items []exportedItem
+
+	require.NoError(t, ms.Export(version, []types.StoreKey{baseKey}, func(store string, item types.SnapshotItem) error {
+		items = append(items, exportedItem{store: store, item: item})
+
+		return nil
+	}))
+
+	return items
+}
+
+// itemReader returns a restore reader over the given items
+func itemReader(items []exportedItem) func() (string, types.SnapshotItem, error) {
+	return func() (string, types.SnapshotItem, error) {
+		if len(items) == 0 {
+			return "", types.SnapshotItem{}, io.EOF
+		}
+
+		next := items[0]
+		items = items[1:]
+
+		return next.store, next.item, nil
+	}
+}
+
+func TestMultiStoreSnapshot(t *testing.T) {
+	t.Parallel()
+
+	ms := newSnapshotMultiStore(t, memdb.NewMemDB())
+
+	for version := 0; version < 3; version++ {
+		main := ms.getStoreByName("main")
+		base := ms.getStoreByName("base")
+
+		for i := 0; i < 20; i++ {
+			main.Set([]byte(fmt.Sprintf("main-%d", i)), []byte(fmt.Sprintf("value-%d", version)))
+			base.Set([]byte(fmt.Sprintf("base-%d", i)), []byte(fmt.Sprintf("value-%d", version)))
+		}
+
+		ms.Commit()
+	}
+
+	commitID := ms.LastCommitID()
+	items := exportItems(t, ms, commitID.Version)
+
+	// The excluded base store isn't exported
+	for _, item := range items {
+		assert.Equal(t, "main", item.store)
+	}
+
+	restored := newSnapshotMultiStore(t, memdb.NewMemDB())
+	require.NoError(t, restored.Restore(commitID.Version, []types.StoreKey{baseKey}, itemReader(items)))
+
+	assert.Equal(t, commitID, restored.LastCommitID())
+	assert.Equal(t, []byte("value-2"), restored.getStoreByName("main").Get([]byte("main-5")))
+	assert.Nil(t, restored.getStoreByName("base").Get([]byte("base-5")))
+
+	// The restored multistore can commit new versions
+	restored.getStoreByName("main").Set([]byte("new"), []byte("value"))
+	assert.Equal(t, commitID.Version+1, restored.Commit().Version)
+}
+
+func TestMultiStoreSnapshot_Retained(t *testing.T) {
+	t.Parallel()
+
+	ms := newSnapshotMultiStore(t, memdb.NewMemDB())
+	ms.SetStoreOptions(types.StoreOptions{PruningOptions: types.PruneEverything})
+
+	main := ms.getStoreByName("main")
+	main.Set([]byte("key"), []byte("value-1"))
+	commitID := ms.Commit()
+
+	release, err := ms.RetainVersion(commitID.Version, []types.StoreKey{baseKey})
+	require.NoError(t, err)
+
+	// The retained version isn't pruned by the next commits
+	for i := 2; i <= 3; i++ {
+		main.Set([]byte("key"), []byte(fmt.Sprintf("value-%d", i)))
+		ms.Commit()
+	}
+
+	items := exportItems(t, ms, commitID.Version)
+
+	restored := newSnapshotMultiStore(t, memdb.NewMemDB())
+	require.NoError(t, restored.Restore(commitID.Version, []types.StoreKey{baseKey}, itemReader(items)))
+
+	assert.Equal(t, commitID, restored.LastCommitID())
+	assert.Equal(t, []byte("value-1"), restored.getStoreByName("main").Get([]byte("key")))
+
+	// Once released, the version is pruned by the next commit
+	release()
+	ms.Commit()
+
+	_, err = ms.RetainVersion(commitID.Version, []types.StoreKey{baseKey})
+	assert.Error(t, err)
+}
+
+func TestMultiStoreSnapshot_Invalid(t *testing.T) {
+	t.Parallel()
+
+	t.Run("export of a missing version", func(t *testing.T) {
+		t.Parallel()
+
+		ms := newSnapshotMultiStore(t, memdb.NewMemDB())
+		ms.Commit()
+		ms.Commit()
+
+		err := ms.Export(3, []types.StoreKey{baseKey}, func(string, types.SnapshotItem) error { return nil })
+		assert.Error(t, err)
+
+		_, err = ms.RetainVersion(3, []types.StoreKey{baseKey})
+		assert.Error(t, err)
+	})
+
+	t.Run("export of a non-merkleized store", func(t *testing.T) {
+		t.Parallel()
+
+		ms := newSnapshotMultiStore(t, memdb.NewMemDB())
+		ms.Commit()
+
+		err := ms.Export(1, nil, func(string, types.SnapshotItem) error { return nil })
+		assert.ErrorContains(t, err, "store base doesn't support snapshots")
+
+		_, err = ms.RetainVersion(1, nil)
+		assert.ErrorContains(t, err, "store base doesn't support snapshots")
+	})
+
+	t.Run("restore into a non-empty multistore", func(t *testing.T) {
+		t.Parallel()
+
+		ms := newSnapshotMultiStore(t, memdb.NewMemDB())
+		ms.Commit()
+
+		assert.Error(t, ms.Restore(2, []types.StoreKey{baseKey}, itemReader(nil)))
+	})
+
+	t.Run("unknown store", func(t *testing.T) {
+		t.Parallel()
+
+		ms := newSnapshotMultiStore(t, memdb.NewMemDB())
+
+		items := []exportedItem{
+			{store: "unknown", item: types.SnapshotItem{Key: []byte("key"), Value: []byte("value")}},
+		}
+
+		assert.Error(t, ms.Restore(1, []types.StoreKey{baseKey}, itemReader(items)))
+	})
+}
@@ -1,7 +1,11 @@
 package rootmulti
 
 import (
+	goerrors "errors"
 	"fmt"
+	"io"
+	"slices"
+	"sort"
 	"strings"
 
 	"github.com/gnolang/gno/tm2/pkg/amino"
@@ -35,8 +39,9 @@ type multiStore struct {
 }
 
 var (
-	_ types.CommitMultiStore = (*multiStore)(nil)
-	_ types.Queryable        = (*multiStore)(nil)
+	_ types.CommitMultiStore   = (*multiStore)(nil)
+	_ types.SnapshotMultiStore = (*multiStore)(nil)
+	_ types.Queryable          = (*multiStore)(nil)
 )
 
 func NewMultiStore(db dbm.DB) *multiStore {
@@ -255,6 +260,181 @@ func (ms *multiStore) getStoreByName(name string) types.Store {
 	return ms.stores[key]
 }
 
+// ---------------------- Snapshots ------------------
+
+// Export implements SnapshotMultiStore.
+// Stores are exported in the order of their names. Any version that wasn't
+// pruned can be exported, and it needs to be retained if new versions are
+// committed concurrently.
+func (ms *multiStore) Export(version int64, excluded []types.StoreKey, fn func(string, types.SnapshotItem) error) error {
+	if version <= 0 {
+		return fmt.Errorf("invalid export version %d", version)
+	}
+
+	for _, key := range ms.snapshotStoreKeys(excluded) {
+		exporter, ok := ms.stores[key].(types.Snapshotter)
+		if !ok {
+			return fmt.Errorf("store %s doesn't support snapshots", key.Name())
+		}
+
+		err := exporter.Export(version, func(item types.SnapshotItem) error {
+			return fn(key.Name(), item)
+		})
+		if err != nil {
+			return fmt.Errorf("unable to export store %s, %w", key.Name(), err)
+		}
+	}
+
+	return nil
+}
+
+// RetainVersion implements SnapshotMultiStore.
+func (ms *multiStore) RetainVersion(version int64, excluded []types.StoreKey) (func(), error) {
+	var releases []func()
+
+	release := func() {
+		for _, release := range releases {
+			release()
+		}
+	}
+
+	for _, key := range ms.snapshotStoreKeys(excluded) {
+		retainer, ok := ms.stores[key].(types.Snapshotter)
+		if !ok {
+			release()
+
+			return nil, fmt.Errorf("store %s doesn't support snapshots", key.Name())
+		}
+
+		r, err := retainer.RetainVersion(version)
+		if err != nil {
+			release()
+
+			return nil, fmt.Errorf("unable to retain version %d of store %s, %w", version, key.Name(), err)
+		}
+
+		releases = append(releases, r)
+	}
+
+	return release, nil
+}
+
+// Restore implements SnapshotMultiStore.
+// The excluded stores are left empty.
+func (ms *multiStore) Restore(version int64, excluded []types.StoreKey, next func() (string, types.SnapshotItem, error)) error {
+	if version <= 0 {
+		return fmt.Errorf("invalid restore version %d", version)
+	}
+
+	if !ms.lastCommitID.IsZero() || getLatestVersion(ms.db) != 0 {
+		return goerrors.New("unable to restore a non-empty multistore")
+	}
+
+	reader := &snapshotReader{next: next}
+	reader.advance()
+
+	for _, key := range ms.snapshotStoreKeys(excluded) {
+		restorer, ok := ms.stores[key].(types.Snapshotter)
+		if !ok {
+			return fmt.Errorf("store %s doesn't support snapshots", key.Name())
+		}
+
+		err := restorer.Restore(version, func() (types.SnapshotItem, error) {
+			return reader.nextItem(key.Name())
+		})
+		if err != nil {
+			return fmt.Errorf("unable to restore store %s, %w", key.Name(), err)
+		}
+	}
+
+	switch {
+	case reader.err == nil:
+		return fmt.Errorf("unexpected snapshot item for store %q", reader.store)
+	case !goerrors.Is(reader.err, io.EOF):
+		return reader.err
+	}
+
+	// Save the commit info of the restored version, and load it
+	keys := ms.sortedStoreKeys()
+
+	cInfo := commitInfo{
+		Version:    version,
+		StoreInfos: make([]storeInfo, 0, len(keys)),
+	}
+
+	for _, key := range keys {
+		si := storeInfo{}
+		si.Name = key.Name()
+		si.Core.CommitID = ms.stores[key].LastCommitID()
+
+		cInfo.StoreInfos = append(cInfo.StoreInfos, si)
+	}
+
+	batch := ms.db.NewBatch()
+	defer batch.Close()
+	setCommitInfo(batch, version, cInfo)
+	setLatestVersion(batch, version)
+	batch.WriteSync()
+
+	return ms.LoadVersion(version)
+}
+
+// snapshotStoreKeys returns the keys of the mounted stores
+// that are part of the snapshots, sorted by name
+func (ms *multiStore) snapshotStoreKeys(excluded []types.StoreKey) []types.StoreKey {
+	keys := ms.sortedStoreKeys()
+
+	return slices.DeleteFunc(keys, func(key types.StoreKey) bool {
+		return slices.Contains(excluded, key)
+	})
+}
+
+// sortedStoreKeys returns the keys of the mounted stores, sorted by name
+func (ms *multiStore) sortedStoreKeys() []types.StoreKey {
+	keys := make([]types.StoreKey, 0, len(ms.storesParams))
+	for key := range ms.storesParams {
+		keys = append(keys, key)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].Name() < keys[j].Name()
+	})
+
+	return keys
+}
+
+// snapshotReader reads the restored snapshot items,
+// keeping the next item around until its store is restored
+type snapshotReader struct {
+	next func() (string, types.SnapshotItem, error)
+
+	store string
+	item  types.SnapshotItem
+	err   error
+}
+
+// advance reads the next item
+func (r *snapshotReader) advance() {
+	r.store, r.item, r.err = r.next()
+}
+
+// nextItem returns the next item of the given store,
+// or io.EOF once all its items are read
+func (r *snapshotReader) nextItem(store string) (types.SnapshotItem, error) {
+	if r.err != nil {
+		return types.SnapshotItem{}, r.err
+	}
+
+	if r.store != store {
+		return types.SnapshotItem{}, io.EOF
+	}
+
+	item := r.item
+	r.advance()
+
+	return item, nil
+}
+
 // ---------------------- Query ------------------
 
 // Query calls substore.Query with the same `req` where `req.Path` is
@@ -433,6 +613,28 @@ func (si storeInfo) Hash() []byte {
 // ----------------------------------------
 // Misc.
 
+// LatestStoreNames returns the names of the stores committed in the latest
+// version of the multistore saved in db, or nil if nothing was committed.
+func LatestStoreNames(db dbm.DB) ([]string, error) {
+	ver := getLatestVersion(db)
+	if ver == 0 {
+		return nil, nil
+	}
+
+	cInfo, err := getCommitInfo(db, ver)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(cInfo.StoreInfos))
+	for _, si := range cInfo.StoreInfos {
+		names = append(names, si.Name)
+	}
+	sort.Strings(names)
+
+	return names, nil
+}
+
 func getLatestVersion(db dbm.DB) int64 {
 	var latest int64
 	latestBytes := db.Get([]byte(latestVersionKey))
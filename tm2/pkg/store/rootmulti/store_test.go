@@ -168,6 +168,25 @@ func TestMultistoreCommitLoad(t *testing.T) {
 	checkStore(t, store, commitID, commitID)
 }
 
+func TestLatestStoreNames(t *testing.T) {
+	t.Parallel()
+
+	db := memdb.NewMemDB()
+
+	// Nothing committed yet.
+	names, err := LatestStoreNames(db)
+	require.NoError(t, err)
+	require.Nil(t, names)
+
+	store := newMultiStoreWithMounts(db)
+	require.NoError(t, store.LoadLatestVersion())
+	store.Commit()
+
+	names, err = LatestStoreNames(db)
+	require.NoError(t, err)
+	require.Equal(t, []string{"store1", "store2", "store3"}, names)
+}
+
 func TestParsePath(t *testing.T) {
 	t.Parallel()
 
@@ -11,6 +11,12 @@ func NewCommitMultiStore(db dbm.DB) types.CommitMultiStore {
 	return rootmulti.NewMultiStore(db)
 }
 
+// LatestStoreNames returns the names of the stores committed in the latest
+// version of the multistore saved in db.
+func LatestStoreNames(db dbm.DB) ([]string, error) {
+	return rootmulti.LatestStoreNames(db)
+}
+
 func NewPruningOptionsFromString(strategy string) (opt PruningOptions) {
 	switch strategy {
 	case "nothing":
@@ -0,0 +1,56 @@
+package types
+
+// SnapshotItem is a single entry of an exported store.
+//
+// The layout of the items depends on the store: IAVL stores export their
+// tree nodes (including their Version and Height), while plain stores only
+// export their raw key-value pairs.
+type SnapshotItem struct {
+	Key     []byte
+	Value   []byte
+	Version int64
+	Height  int8
+}
+
+// Snapshotter is implemented by the CommitStores that can be exported into
+// state sync snapshots, and restored from them.
+type Snapshotter interface {
+	// Export exports the store contents at the given version, calling fn
+	// for every item. The export stops at the first error returned by fn.
+	Export(version int64, fn func(SnapshotItem) error) error
+
+	// Restore restores the store at the given version out of the exported
+	// items. next returns io.EOF once there are no more items.
+	// The store must be empty.
+	Restore(version int64, next func() (SnapshotItem, error)) error
+
+	// RetainVersion prevents the given version from being pruned, until the
+	// returned release function is called. It allows exporting the version
+	// while new versions are committed.
+	RetainVersion(version int64) (release func(), err error)
+}
+
+// SnapshotMultiStore is a CommitMultiStore that can be exported into
+// state sync snapshots, and restored from them.
+//
+// Only the stores covered by the app hash can be verified once restored, so
+// every mounted store must be a Snapshotter, except the excluded stores,
+// which are neither exported nor restored.
+type SnapshotMultiStore interface {
+	CommitMultiStore
+
+	// Export exports the contents of every mounted store at the given
+	// version, calling fn for every item of the store with the given name.
+	Export(version int64, excluded []StoreKey, fn func(store string, item SnapshotItem) error) error
+
+	// RetainVersion prevents the given version of every mounted store from
+	// being pruned, until the returned release function is called. It allows
+	// exporting the version while new versions are committed.
+	RetainVersion(version int64, excluded []StoreKey) (release func(), err error)
+
+	// Restore restores every mounted store at the given version, out of the
+	// exported items, and loads the version. next returns the name of the
+	// store and the item, or io.EOF once there are no more items.
+	// The multistore must be empty.
+	Restore(version int64, excluded []StoreKey, next func() (string, SnapshotItem, error)) error
+}